@@ -2,6 +2,7 @@ package app
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -28,6 +29,28 @@ const (
 	MailLabelAlliance = 8
 )
 
+// MailLabelColors are the colors a mail label can have in EVE.
+var MailLabelColors = []string{
+	"#0000fe",
+	"#006634",
+	"#0099ff",
+	"#00ff33",
+	"#01ffff",
+	"#349800",
+	"#660066",
+	"#666666",
+	"#999999",
+	"#99ffff",
+	"#9a0000",
+	"#ccff9a",
+	"#e6e6e6",
+	"#fe0000",
+	"#ff6600",
+	"#ffff01",
+	"#ffffcd",
+	"#ffffff",
+}
+
 // A mail label for an Eve mail belonging to a character.
 type CharacterMailLabel struct {
 	ID          int64
@@ -104,3 +127,65 @@ func (cm CharacterMail) RecipientNames() []string {
 func (cm CharacterMail) BodyToMarkdown() string {
 	return evehtml.ToMarkdown(cm.Body)
 }
+
+// ReplySubject returns the subject for a reply to a mail.
+func (cm CharacterMail) ReplySubject() string {
+	return prefixMailSubject("Re", cm.Subject)
+}
+
+// ForwardSubject returns the subject for forwarding a mail.
+func (cm CharacterMail) ForwardSubject() string {
+	return prefixMailSubject("Fw", cm.Subject)
+}
+
+func prefixMailSubject(prefix, subject string) string {
+	if strings.HasPrefix(strings.ToLower(subject), strings.ToLower(prefix)+":") {
+		return subject
+	}
+	return fmt.Sprintf("%s: %s", prefix, subject)
+}
+
+// ReplyRecipients returns the recipients for a reply to a mail.
+// A reply goes to the sender and a reply to all also goes to all other recipients,
+// including mailing lists, corporations and alliances.
+// A reply to a sent mail goes to it's original recipients.
+// The character itself and entities which can not receive mails are excluded.
+func (cm CharacterMail) ReplyRecipients(all bool) []*EveEntity {
+	var candidates []*EveEntity
+	if cm.From != nil && cm.From.ID == cm.CharacterID {
+		candidates = cm.Recipients
+	} else if all {
+		candidates = slices.Concat([]*EveEntity{cm.From}, cm.Recipients)
+	} else {
+		candidates = []*EveEntity{cm.From}
+	}
+	seen := make(map[int32]bool)
+	var recipients []*EveEntity
+	for _, o := range candidates {
+		if o == nil || o.ID == cm.CharacterID || seen[o.ID] || !o.CanReceiveMail() {
+			continue
+		}
+		seen[o.ID] = true
+		recipients = append(recipients, o)
+	}
+	return recipients
+}
+
+// QuotedBody returns a mail as quote in Eve Online HTML.
+func (cm CharacterMail) QuotedBody() string {
+	var from string
+	if cm.From != nil {
+		from = cm.From.ShowInfoLink()
+	}
+	to := make([]string, len(cm.Recipients))
+	for i, r := range cm.Recipients {
+		to[i] = r.ShowInfoLink()
+	}
+	header := []string{
+		evehtml.FromPlain(cm.Subject),
+		"From: " + from,
+		"Sent: " + cm.Timestamp.Format(DateTimeFormat),
+		"To: " + strings.Join(to, ", "),
+	}
+	return evehtml.Quote(header, cm.Body)
+}
@@ -0,0 +1,79 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+func TestCharacterMailSubjects(t *testing.T) {
+	cases := []struct {
+		subject string
+		reply   string
+		forward string
+	}{
+		{"Hello", "Re: Hello", "Fw: Hello"},
+		{"Re: Hello", "Re: Hello", "Fw: Re: Hello"},
+		{"RE: Hello", "RE: Hello", "Fw: RE: Hello"},
+		{"Fw: Hello", "Re: Fw: Hello", "Fw: Hello"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.subject, func(t *testing.T) {
+			m := app.CharacterMail{Subject: tc.subject}
+			assert.Equal(t, tc.reply, m.ReplySubject())
+			assert.Equal(t, tc.forward, m.ForwardSubject())
+		})
+	}
+}
+
+func TestCharacterMailReplyRecipients(t *testing.T) {
+	me := &app.EveEntity{ID: 1, Name: "Me", Category: app.EveEntityCharacter}
+	sender := &app.EveEntity{ID: 2, Name: "Sender", Category: app.EveEntityCharacter}
+	other := &app.EveEntity{ID: 3, Name: "Other", Category: app.EveEntityCharacter}
+	corp := &app.EveEntity{ID: 4, Name: "Corp", Category: app.EveEntityCorporation}
+	alliance := &app.EveEntity{ID: 5, Name: "Alliance", Category: app.EveEntityAlliance}
+	list := &app.EveEntity{ID: 6, Name: "List", Category: app.EveEntityMailList}
+	unknown := &app.EveEntity{ID: 7, Name: "?", Category: app.EveEntityUnknown}
+	t.Run("reply goes to sender only", func(t *testing.T) {
+		m := app.CharacterMail{CharacterID: me.ID, From: sender, Recipients: []*app.EveEntity{me, other, list}}
+		assert.Equal(t, []*app.EveEntity{sender}, m.ReplyRecipients(false))
+	})
+	t.Run("reply all goes to sender and other recipients", func(t *testing.T) {
+		m := app.CharacterMail{
+			CharacterID: me.ID,
+			From:        sender,
+			Recipients:  []*app.EveEntity{me, other, corp, alliance, list, unknown, sender},
+		}
+		want := []*app.EveEntity{sender, other, corp, alliance, list}
+		assert.Equal(t, want, m.ReplyRecipients(true))
+	})
+	t.Run("reply to sent mail goes to original recipients", func(t *testing.T) {
+		m := app.CharacterMail{CharacterID: me.ID, From: me, Recipients: []*app.EveEntity{other, corp}}
+		assert.Equal(t, []*app.EveEntity{other, corp}, m.ReplyRecipients(false))
+		assert.Equal(t, []*app.EveEntity{other, corp}, m.ReplyRecipients(true))
+	})
+}
+
+func TestCharacterMailQuotedBody(t *testing.T) {
+	m := app.CharacterMail{
+		Body:    "Hi<br>there",
+		From:    &app.EveEntity{ID: 2, Name: "Sender", Category: app.EveEntityCharacter},
+		Subject: "Hello & welcome",
+		Recipients: []*app.EveEntity{
+			{ID: 4, Name: "Corp", Category: app.EveEntityCorporation},
+			{ID: 6, Name: "List", Category: app.EveEntityMailList},
+		},
+		Timestamp: time.Date(2025, 3, 4, 12, 30, 0, 0, time.UTC),
+	}
+	got := m.QuotedBody()
+	want := "<br><br>--------------------------------<br>" +
+		"Hello &amp; welcome<br>" +
+		`From: <a href="showinfo:1376//2">Sender</a><br>` +
+		"Sent: " + m.Timestamp.Format(app.DateTimeFormat) + "<br>" +
+		`To: <a href="showinfo:2//4">Corp</a>, List<br>` +
+		"<br>Hi<br>there"
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/evehtml"
+)
+
+type CharacterMailDraftStatus uint
+
+const (
+	MailDraftUndefined   CharacterMailDraftStatus = iota
+	MailDraftSaved                                // saved as draft and not yet queued for sending
+	MailDraftQueued                               // queued in the outbox and waiting to be sent
+	MailDraftFailed                               // sending failed permanently
+	MailDraftUnconfirmed                          // sending timed out and the mail might have been sent
+	MailDraftSending                              // mail is currently being sent
+)
+
+// ErrMailDraftUnconfirmed signals that a mail might have been sent,
+// but ESI did not confirm it. The user needs to check the sent mails before sending it again.
+var ErrMailDraftUnconfirmed = errors.New("mail might have been sent")
+
+// ErrMailDraftSending signals that a mail draft is already being sent.
+var ErrMailDraftSending = errors.New("mail is already being sent")
+
+func (s CharacterMailDraftStatus) String() string {
+	switch s {
+	case MailDraftSaved:
+		return "Draft"
+	case MailDraftQueued:
+		return "Queued"
+	case MailDraftFailed:
+		return "Failed"
+	case MailDraftUnconfirmed:
+		return "Unconfirmed"
+	case MailDraftSending:
+		return "Sending"
+	}
+	return "?"
+}
+
+// A CharacterMailDraft is an unsent Eve mail of a character.
+// It is either a draft or a mail queued in the outbox.
+type CharacterMailDraft struct {
+	ID            int64
+	Attempts      int    // number of failed attempts to send this mail
+	Body          string // body as written in the composer, i.e. plain text with optional Eve Online HTML tags
+	CharacterID   int32
+	LastError     string
+	NextAttemptAt time.Time
+	QuotedBody    string // quoted mail in Eve Online HTML for replies and forwards
+	Recipients    []*EveEntity
+	Status        CharacterMailDraftStatus
+	Subject       string
+	UpdatedAt     time.Time
+}
+
+// BodyHTML returns the body to be sent as Eve Online HTML.
+func (d CharacterMailDraft) BodyHTML() string {
+	return evehtml.FromRichText(d.Body) + d.QuotedBody
+}
+
+// RecipientIDs returns the IDs of the recipients.
+func (d CharacterMailDraft) RecipientIDs() []int32 {
+	ids := make([]int32, len(d.Recipients))
+	for i, r := range d.Recipients {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+// StatusDisplay returns the status of a draft for display.
+func (d CharacterMailDraft) StatusDisplay() string {
+	switch d.Status {
+	case MailDraftQueued:
+		if d.Attempts == 0 {
+			return "Waiting to be sent"
+		}
+		return "Will retry at " + d.NextAttemptAt.Local().Format(DateTimeFormat)
+	case MailDraftFailed:
+		return "Failed: " + d.LastError
+	case MailDraftUnconfirmed:
+		return "Might have been sent. Please check your sent mails: " + d.LastError
+	}
+	return d.Status.String()
+}
@@ -0,0 +1,31 @@
+package app
+
+import "time"
+
+// MailExportFormat is the file format for exporting mails.
+type MailExportFormat uint
+
+const (
+	MailExportMbox MailExportFormat = iota // all mails in one mbox file
+	MailExportEML                          // one EML file per mail
+)
+
+func (f MailExportFormat) String() string {
+	switch f {
+	case MailExportMbox:
+		return "mbox"
+	case MailExportEML:
+		return "EML"
+	}
+	return "?"
+}
+
+// MailExportParams selects the mails of a character for exporting.
+type MailExportParams struct {
+	CharacterID int32
+	LabelID     int32     // export mails with this label. 0 = all. Ignored when ListID is set.
+	ListID      int32     // export mails sent to this mailing list. 0 = any
+	Since       time.Time // exclude older mails when not zero
+	Until       time.Time // exclude newer mails when not zero
+	KeepHTML    bool      // keep bodies as HTML instead of converting them to plain text
+}
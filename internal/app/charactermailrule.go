@@ -0,0 +1,55 @@
+package app
+
+import (
+	"strings"
+)
+
+// A CharacterMailRule is applied to new mails of a character when they are received.
+// A rule matches when all of it's filters match. Empty filters match everything.
+// All matching rules are applied.
+type CharacterMailRule struct {
+	ID                   int64
+	CharacterID          int32
+	IsEnabled            bool
+	Keyword              string // matches subject or body of a mail, case insensitive
+	LabelID              int32  // label applied to matching mails. 0 = none
+	MailListID           int32  // matches mails sent to this mailing list. 0 = any
+	MarkAsRead           bool
+	Name                 string
+	Sender               string // matches the name of the sender, case insensitive
+	SuppressNotification bool
+}
+
+// HasActions reports whether a rule does anything with matching mails.
+func (r CharacterMailRule) HasActions() bool {
+	return r.LabelID != 0 || r.MarkAsRead || r.SuppressNotification
+}
+
+// Matches reports whether a rule matches a mail.
+func (r CharacterMailRule) Matches(m *CharacterMail) bool {
+	if !r.IsEnabled || m.CharacterID != r.CharacterID {
+		return false
+	}
+	if r.Sender != "" && (m.From == nil || !strings.EqualFold(m.From.Name, r.Sender)) {
+		return false
+	}
+	if r.MailListID != 0 {
+		var found bool
+		for _, o := range m.Recipients {
+			if o != nil && o.ID == r.MailListID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Keyword != "" {
+		k := strings.ToLower(r.Keyword)
+		if !strings.Contains(strings.ToLower(m.Subject), k) && !strings.Contains(strings.ToLower(m.BodyPlain()), k) {
+			return false
+		}
+	}
+	return true
+}
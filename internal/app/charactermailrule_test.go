@@ -0,0 +1,48 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+func TestCharacterMailRuleMatches(t *testing.T) {
+	m := &app.CharacterMail{
+		Body:        "Fleet forms up in <b>Amamake</b>.",
+		CharacterID: 1,
+		From:        &app.EveEntity{ID: 10, Name: "Bruce Wayne"},
+		Recipients:  []*app.EveEntity{{ID: 20, Name: "Alpha", Category: app.EveEntityMailList}},
+		Subject:     "Stratop tonight",
+	}
+	cases := []struct {
+		name string
+		rule app.CharacterMailRule
+		want bool
+	}{
+		{"should match all when no filters", app.CharacterMailRule{CharacterID: 1, IsEnabled: true}, true},
+		{"should not match when disabled", app.CharacterMailRule{CharacterID: 1}, false},
+		{"should not match other character", app.CharacterMailRule{CharacterID: 2, IsEnabled: true}, false},
+		{"should match sender", app.CharacterMailRule{CharacterID: 1, IsEnabled: true, Sender: "bruce wayne"}, true},
+		{"should not match other sender", app.CharacterMailRule{CharacterID: 1, IsEnabled: true, Sender: "Peter Parker"}, false},
+		{"should match mailing list", app.CharacterMailRule{CharacterID: 1, IsEnabled: true, MailListID: 20}, true},
+		{"should not match other mailing list", app.CharacterMailRule{CharacterID: 1, IsEnabled: true, MailListID: 21}, false},
+		{"should match keyword in subject", app.CharacterMailRule{CharacterID: 1, IsEnabled: true, Keyword: "STRATOP"}, true},
+		{"should match keyword in body", app.CharacterMailRule{CharacterID: 1, IsEnabled: true, Keyword: "amamake"}, true},
+		{"should not match other keyword", app.CharacterMailRule{CharacterID: 1, IsEnabled: true, Keyword: "Jita"}, false},
+		{"should match all filters", app.CharacterMailRule{CharacterID: 1, IsEnabled: true, Sender: "Bruce Wayne", MailListID: 20, Keyword: "fleet"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.rule.Matches(m))
+		})
+	}
+}
+
+func TestCharacterMailRuleHasActions(t *testing.T) {
+	assert.False(t, app.CharacterMailRule{}.HasActions())
+	assert.True(t, app.CharacterMailRule{LabelID: 3}.HasActions())
+	assert.True(t, app.CharacterMailRule{MarkAsRead: true}.HasActions())
+	assert.True(t, app.CharacterMailRule{SuppressNotification: true}.HasActions())
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+var mailSubjectPrefix = regexp.MustCompile(`(?i)^\s*(re|fw|fwd)\s*:\s*`)
+
+// NormalizeMailSubject returns a subject without reply and forward prefixes, e.g. "Re: Fw: Hi" becomes "Hi".
+func NormalizeMailSubject(s string) string {
+	for {
+		s2 := mailSubjectPrefix.ReplaceAllString(s, "")
+		if s2 == s {
+			break
+		}
+		s = s2
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// A CharacterMailThread is a conversation of mails with the same subject between the same participants.
+type CharacterMailThread struct {
+	Subject      string                 // normalized subject
+	Mails        []*CharacterMailHeader // in ascending order by timestamp
+	participants set.Set[int32]
+}
+
+// Latest returns the most recent mail of a thread.
+func (t CharacterMailThread) Latest() *CharacterMailHeader {
+	return t.Mails[len(t.Mails)-1]
+}
+
+// IsRead reports whether all mails of a thread have been read.
+func (t CharacterMailThread) IsRead() bool {
+	for _, m := range t.Mails {
+		if !m.IsRead {
+			return false
+		}
+	}
+	return true
+}
+
+// MailIDs returns the mail IDs of a thread.
+func (t CharacterMailThread) MailIDs() []int32 {
+	ids := make([]int32, len(t.Mails))
+	for i, m := range t.Mails {
+		ids[i] = m.MailID
+	}
+	return ids
+}
+
+// GroupMailThreads groups mails of a character into threads and returns them in descending order by their latest mail.
+//
+// Mails belong to the same thread when they have the same normalized subject and share at least one participant,
+// i.e. the sender or a recipient other than the character itself.
+// participants contains the IDs of the sender and recipients for each mail ID.
+func GroupMailThreads(characterID int32, headers []*CharacterMailHeader, participants map[int32]set.Set[int32]) []*CharacterMailThread {
+	headers = slices.Clone(headers)
+	slices.SortStableFunc(headers, func(a, b *CharacterMailHeader) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	bySubject := make(map[string][]*CharacterMailThread)
+	for _, h := range headers {
+		p := set.New[int32]()
+		if x, ok := participants[h.MailID]; ok {
+			p = x.Clone()
+		}
+		if h.From != nil {
+			p.Add(h.From.ID)
+		}
+		p.Remove(characterID)
+		subject := NormalizeMailSubject(h.Subject)
+		key := strings.ToLower(subject)
+		current := &CharacterMailThread{
+			Subject:      subject,
+			Mails:        []*CharacterMailHeader{h},
+			participants: p,
+		}
+		var others []*CharacterMailThread
+		for _, t := range bySubject[key] {
+			if t.participants.IsDisjoint(p) {
+				others = append(others, t)
+				continue
+			}
+			current.Mails = append(t.Mails, current.Mails...)
+			current.participants = t.participants.Union(current.participants)
+		}
+		bySubject[key] = append(others, current)
+	}
+	threads := make([]*CharacterMailThread, 0)
+	for _, tt := range bySubject {
+		for _, t := range tt {
+			slices.SortStableFunc(t.Mails, func(a, b *CharacterMailHeader) int {
+				return a.Timestamp.Compare(b.Timestamp)
+			})
+			threads = append(threads, t)
+		}
+	}
+	slices.SortFunc(threads, func(a, b *CharacterMailThread) int {
+		if c := b.Latest().Timestamp.Compare(a.Latest().Timestamp); c != 0 {
+			return c
+		}
+		return int(b.Latest().MailID) - int(a.Latest().MailID)
+	})
+	return threads
+}
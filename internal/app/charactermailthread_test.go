@@ -0,0 +1,98 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+func TestNormalizeMailSubject(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"Stratop", "Stratop"},
+		{"Re: Stratop", "Stratop"},
+		{"RE:Stratop", "Stratop"},
+		{"Fw: Re: Stratop", "Stratop"},
+		{"Fwd: re:  Stratop  tonight ", "Stratop tonight"},
+		{"Rental: Stratop", "Rental: Stratop"},
+		{"", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			assert.Equal(t, tc.want, app.NormalizeMailSubject(tc.in))
+		})
+	}
+}
+
+func TestGroupMailThreads(t *testing.T) {
+	const characterID = 1
+	me := &app.EveEntity{ID: characterID}
+	alice := &app.EveEntity{ID: 10}
+	bob := &app.EveEntity{ID: 11}
+	now := time.Now()
+	makeHeader := func(mailID int32, from *app.EveEntity, subject string, hours int, isRead bool) *app.CharacterMailHeader {
+		return &app.CharacterMailHeader{
+			CharacterID: characterID,
+			From:        from,
+			IsRead:      isRead,
+			MailID:      mailID,
+			Subject:     subject,
+			Timestamp:   now.Add(time.Duration(hours) * time.Hour),
+		}
+	}
+	t.Run("should group mails with same subject and participants", func(t *testing.T) {
+		// given
+		m1 := makeHeader(1, alice, "Peace talks", 0, true)
+		m2 := makeHeader(2, me, "Re: Peace talks", 1, true)
+		m3 := makeHeader(3, alice, "RE: Re: Peace talks", 2, false)
+		m4 := makeHeader(4, bob, "Peace talks", 3, true)
+		m5 := makeHeader(5, bob, "Stratop", 4, true)
+		participants := map[int32]set.Set[int32]{
+			1: set.New[int32](10, characterID),
+			2: set.New[int32](characterID, 10),
+			3: set.New[int32](10, characterID),
+			4: set.New[int32](11, characterID),
+			5: set.New[int32](11, characterID),
+		}
+		// when
+		got := app.GroupMailThreads(characterID, []*app.CharacterMailHeader{m5, m4, m3, m2, m1}, participants)
+		// then
+		if assert.Len(t, got, 3) {
+			assert.Equal(t, "Stratop", got[0].Subject)
+			assert.Equal(t, []int32{5}, got[0].MailIDs())
+			assert.Equal(t, []int32{4}, got[1].MailIDs())
+			assert.Equal(t, "Peace talks", got[2].Subject)
+			assert.Equal(t, []int32{1, 2, 3}, got[2].MailIDs())
+			assert.Equal(t, m3, got[2].Latest())
+			assert.False(t, got[2].IsRead())
+			assert.True(t, got[1].IsRead())
+		}
+	})
+	t.Run("should merge threads which are connected through a later mail", func(t *testing.T) {
+		// given
+		m1 := makeHeader(1, alice, "Peace talks", 0, true)
+		m2 := makeHeader(2, bob, "Peace talks", 1, true)
+		m3 := makeHeader(3, me, "Re: Peace talks", 2, true)
+		participants := map[int32]set.Set[int32]{
+			1: set.New[int32](10, characterID),
+			2: set.New[int32](11, characterID),
+			3: set.New[int32](characterID, 10, 11),
+		}
+		// when
+		got := app.GroupMailThreads(characterID, []*app.CharacterMailHeader{m3, m2, m1}, participants)
+		// then
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, []int32{1, 2, 3}, got[0].MailIDs())
+		}
+	})
+	t.Run("should return empty slice when there are no mails", func(t *testing.T) {
+		got := app.GroupMailThreads(characterID, []*app.CharacterMailHeader{}, nil)
+		assert.Len(t, got, 0)
+	})
+}
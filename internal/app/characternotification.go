@@ -3,6 +3,8 @@ package app
 import (
 	"fmt"
 	"log/slog"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 	"unicode"
@@ -10,11 +12,17 @@ import (
 	"bytes"
 
 	"github.com/yuin/goldmark"
+	"gopkg.in/yaml.v3"
 
 	"github.com/ErikKalkoken/evebuddy/internal/evehtml"
 	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
 )
 
+// NotificationDuplicateWindow is the maximum time between two notifications
+// received by different characters for them to be considered duplicates.
+const NotificationDuplicateWindow = 5 * time.Minute
+
 type CharacterNotification struct {
 	ID             int64
 	Body           optional.Optional[string] // generated body text in markdown
@@ -83,8 +91,8 @@ func (cn *CharacterNotification) String() string {
 	return s
 }
 
-// BodyPlain returns the body of a notification as plain text.
-func (cn *CharacterNotification) BodyPlain() (optional.Optional[string], error) {
+// BodyHTML returns the body of a notification as HTML.
+func (cn *CharacterNotification) BodyHTML() (optional.Optional[string], error) {
 	var b optional.Optional[string]
 	if cn.Body.IsEmpty() {
 		return b, nil
@@ -93,6 +101,116 @@ func (cn *CharacterNotification) BodyPlain() (optional.Optional[string], error)
 	if err := goldmark.Convert([]byte(cn.Body.ValueOrZero()), &buf); err != nil {
 		return b, fmt.Errorf("convert notification body: %w", err)
 	}
-	b.Set(evehtml.Strip(buf.String()))
+	b.Set(buf.String())
 	return b, nil
 }
+
+// BodyPlain returns the body of a notification as plain text.
+func (cn *CharacterNotification) BodyPlain() (optional.Optional[string], error) {
+	b, err := cn.BodyHTML()
+	if err != nil || b.IsEmpty() {
+		return b, err
+	}
+	return optional.New(evehtml.Strip(b.ValueOrZero())), nil
+}
+
+// IsDuplicate reports whether a notification received by another character is about the same event.
+// That is the case when both have the same type and payload and were sent at about the same time.
+func (cn *CharacterNotification) IsDuplicate(other *CharacterNotification) bool {
+	if cn.CharacterID == other.CharacterID || cn.Type != other.Type {
+		return false
+	}
+	d := cn.Timestamp.Sub(other.Timestamp)
+	if d < 0 {
+		d = -d
+	}
+	if d > NotificationDuplicateWindow {
+		return false
+	}
+	return isSameNotificationPayload(cn.Text, other.Text)
+}
+
+// isSameNotificationPayload reports whether two YAML payloads have the same content.
+// Payloads are compared after parsing, so differences in formatting are ignored.
+func isSameNotificationPayload(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var x, y any
+	if err := yaml.Unmarshal([]byte(a), &x); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(b), &y); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// CollapsedNotification is a notification which was received by one or more characters.
+type CollapsedNotification struct {
+	Notification *CharacterNotification   // the first received notification
+	CharacterIDs []int32                  // characters which received the notification in ascending order
+	Duplicates   []*CharacterNotification // the same notification received by other characters
+}
+
+// IDs returns the IDs of all collapsed notifications.
+func (cn *CollapsedNotification) IDs() set.Set[int64] {
+	ids := set.New(cn.Notification.ID)
+	for _, d := range cn.Duplicates {
+		ids.Add(d.ID)
+	}
+	return ids
+}
+
+// IsRead reports whether all collapsed notifications have been read.
+func (cn *CollapsedNotification) IsRead() bool {
+	if !cn.Notification.IsRead {
+		return false
+	}
+	for _, d := range cn.Duplicates {
+		if !d.IsRead {
+			return false
+		}
+	}
+	return true
+}
+
+// CollapseNotifications returns notifications with duplicates
+// received by several characters collapsed into one.
+// The order of notifications is preserved.
+func CollapseNotifications(nn []*CharacterNotification) []*CollapsedNotification {
+	cc := make([]*CollapsedNotification, 0)
+	type2cc := make(map[string][]*CollapsedNotification) // for faster lookup
+	for _, n := range nn {
+		c := func() *CollapsedNotification {
+			for _, c := range type2cc[n.Type] {
+				if slices.Contains(c.CharacterIDs, n.CharacterID) {
+					continue
+				}
+				if c.Notification.IsDuplicate(n) {
+					return c
+				}
+			}
+			return nil
+		}()
+		if c == nil {
+			c = &CollapsedNotification{
+				Notification: n,
+				CharacterIDs: []int32{n.CharacterID},
+				Duplicates:   make([]*CharacterNotification, 0),
+			}
+			cc = append(cc, c)
+			type2cc[n.Type] = append(type2cc[n.Type], c)
+			continue
+		}
+		c.CharacterIDs = append(c.CharacterIDs, n.CharacterID)
+		slices.Sort(c.CharacterIDs)
+		if n.Timestamp.Before(c.Notification.Timestamp) {
+			c.Duplicates = append(c.Duplicates, c.Notification)
+			c.Notification = n
+		} else {
+			c.Duplicates = append(c.Duplicates, n)
+		}
+	}
+	return cc
+}
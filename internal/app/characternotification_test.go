@@ -2,9 +2,11 @@ package app_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,6 +27,28 @@ func TestCharacterNotification(t *testing.T) {
 	})
 }
 
+func TestCharacterNotificationBodyHTML(t *testing.T) {
+	t.Run("can return body as HTML", func(t *testing.T) {
+		n := &app.CharacterNotification{
+			Type: "Alpha",
+			Body: optional.New("**alpha**"),
+		}
+		got, err := n.BodyHTML()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "<p><strong>alpha</strong></p>\n", got.MustValue())
+		}
+	})
+	t.Run("should return empty when body is empty", func(t *testing.T) {
+		n := &app.CharacterNotification{
+			Type: "Alpha",
+		}
+		got, err := n.BodyHTML()
+		if assert.NoError(t, err) {
+			assert.True(t, got.IsEmpty())
+		}
+	})
+}
+
 func TestCharacterNotificationBodyPlain(t *testing.T) {
 	t.Run("can return body as plain text", func(t *testing.T) {
 		n := &app.CharacterNotification{
@@ -46,3 +70,66 @@ func TestCharacterNotificationBodyPlain(t *testing.T) {
 		}
 	})
 }
+
+func TestCharacterNotificationIsDuplicate(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name  string
+		other *app.CharacterNotification
+		want  bool
+	}{
+		{
+			"same notification of other character",
+			&app.CharacterNotification{CharacterID: 2, Type: "Alpha", Timestamp: now, Text: "a: 1\nb: 2\n"},
+			true,
+		},
+		{
+			"same payload with different formatting",
+			&app.CharacterNotification{CharacterID: 2, Type: "Alpha", Timestamp: now.Add(time.Minute), Text: "b: 2\na:   1\n"},
+			true,
+		},
+		{
+			"same character",
+			&app.CharacterNotification{CharacterID: 1, Type: "Alpha", Timestamp: now, Text: "a: 1\nb: 2\n"},
+			false,
+		},
+		{
+			"other type",
+			&app.CharacterNotification{CharacterID: 2, Type: "Bravo", Timestamp: now, Text: "a: 1\nb: 2\n"},
+			false,
+		},
+		{
+			"other payload",
+			&app.CharacterNotification{CharacterID: 2, Type: "Alpha", Timestamp: now, Text: "a: 1\nb: 3\n"},
+			false,
+		},
+		{
+			"outside time window",
+			&app.CharacterNotification{CharacterID: 2, Type: "Alpha", Timestamp: now.Add(-time.Hour), Text: "a: 1\nb: 2\n"},
+			false,
+		},
+	}
+	n := &app.CharacterNotification{CharacterID: 1, Type: "Alpha", Timestamp: now, Text: "a: 1\nb: 2\n"}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, n.IsDuplicate(tc.other))
+		})
+	}
+}
+
+func TestCollapseNotifications(t *testing.T) {
+	now := time.Now()
+	n1 := &app.CharacterNotification{ID: 1, CharacterID: 2, Type: "Alpha", Timestamp: now, Text: "a: 1", IsRead: true}
+	n2 := &app.CharacterNotification{ID: 2, CharacterID: 1, Type: "Alpha", Timestamp: now.Add(-time.Second), Text: "a: 1"}
+	n3 := &app.CharacterNotification{ID: 3, CharacterID: 1, Type: "Bravo", Timestamp: now, Text: "a: 1", IsRead: true}
+	got := app.CollapseNotifications([]*app.CharacterNotification{n1, n2, n3})
+	want := []*app.CollapsedNotification{
+		{Notification: n2, CharacterIDs: []int32{1, 2}, Duplicates: []*app.CharacterNotification{n1}},
+		{Notification: n3, CharacterIDs: []int32{1}, Duplicates: []*app.CharacterNotification{}},
+	}
+	if assert.Equal(t, want, got) {
+		assert.Equal(t, set.New[int64](1, 2), got[0].IDs())
+		assert.False(t, got[0].IsRead())
+		assert.True(t, got[1].IsRead())
+	}
+}
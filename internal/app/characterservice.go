@@ -2,39 +2,70 @@ package app
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"fyne.io/fyne/v2/data/binding"
+	"github.com/ErikKalkoken/evebuddy/internal/eft"
 	"github.com/ErikKalkoken/evebuddy/internal/optional"
 	"github.com/ErikKalkoken/evebuddy/internal/set"
 )
 
 // CharacterService ...
 type CharacterService interface {
-	AddEveEntitiesFromSearchESI(ctx context.Context, characterID int32, search string) ([]int32, error)
+	AddEveEntitiesFromSearchESI(ctx context.Context, characterID int32, search string, categories ...EveEntityCategory) ([]int32, error)
+	AddMailsLabel(ctx context.Context, characterID int32, mailIDs []int32, labelID int32) error
 	AssetTotalValue(ctx context.Context, characterID int32) (optional.Optional[float64], error)
+	CalcSkillRequirementsSP(ctx context.Context, characterID int32, requirements []SkillRequirement) (SkillRequirementsSP, error)
+	CalcSkillqueueTrainingTime(ctx context.Context, characterID int32, attributes TrainingAttributes) (time.Duration, error)
+	CalcSkillTrainingTime(ctx context.Context, characterID, typeID int32, level int, attributes TrainingAttributes) (time.Duration, error)
+	CheckFitting(ctx context.Context, fitting *eft.Fitting) (*FittingCheck, error)
+	CompareSkills(ctx context.Context, characterIDs []int32) (*SkillComparison, error)
 	CountContractBids(ctx context.Context, contractID int64) (int, error)
 	CountNotifications(ctx context.Context, characterID int32) (map[NotificationGroup][]int, error)
+	CreateMailLabel(ctx context.Context, characterID int32, name, color string) (int32, error)
+	CreateMailRule(ctx context.Context, r *CharacterMailRule) (int64, error)
+	CreateNotificationRule(ctx context.Context, r *NotificationRule) (int64, error)
+	CreateStructureTimer(ctx context.Context, arg CreateStructureTimerParams) (int64, error)
+	CreateWebhook(ctx context.Context, w *Webhook) (int64, error)
 	DeleteCharacter(ctx context.Context, id int32) error
 	DeleteMail(ctx context.Context, characterID, mailID int32) error
+	DeleteMailDraft(ctx context.Context, characterID int32, draftID int64) error
+	DeleteMailLabel(ctx context.Context, characterID, labelID int32) error
+	DeleteMailRule(ctx context.Context, characterID int32, ruleID int64) error
+	DeleteMails(ctx context.Context, characterID int32, mailIDs []int32) error
+	DeleteNotificationRule(ctx context.Context, id int64) error
+	DeleteStructureTimer(ctx context.Context, id int64) error
+	DeleteWebhook(ctx context.Context, id int64) error
 	DisableAllTrainingWatchers(ctx context.Context) error
 	EnableAllTrainingWatchers(ctx context.Context) error
 	EnableTrainingWatcher(ctx context.Context, characterID int32) error
+	ExportMailsEML(ctx context.Context, arg MailExportParams, create func(name string) (io.WriteCloser, error)) (int, error)
+	ExportMailsMbox(ctx context.Context, arg MailExportParams, w io.Writer) (int, error)
+	ForwardAlert(ctx context.Context, title, content string) error
 	GetAllMailUnreadCount(ctx context.Context) (int, error)
 	GetAnyCharacter(ctx context.Context) (*Character, error)
 	GetAttributes(ctx context.Context, characterID int32) (*CharacterAttributes, error)
 	GetCharacter(ctx context.Context, id int32) (*Character, error)
+	GetContract(ctx context.Context, characterID, contractID int32) (*CharacterContract, error)
 	GetContractTopBid(ctx context.Context, contractID int64) (*CharacterContractBid, error)
 	GetJumpClone(ctx context.Context, characterID, cloneID int32) (*CharacterJumpClone, error)
 	GetMail(ctx context.Context, characterID int32, mailID int32) (*CharacterMail, error)
 	GetMailCounts(ctx context.Context, characterID int32) (int, int, error)
+	GetMailDraft(ctx context.Context, characterID int32, draftID int64) (*CharacterMailDraft, error)
 	GetMailLabelUnreadCounts(ctx context.Context, characterID int32) (map[int32]int, error)
 	GetMailListUnreadCounts(ctx context.Context, characterID int32) (map[int32]int, error)
+	GetNotification(ctx context.Context, characterID int32, notificationID int64) (*CharacterNotification, error)
+	GetShipMastery(ctx context.Context, characterID, shipTypeID int32) (*CharacterShipMastery, error)
 	GetSkill(ctx context.Context, characterID, typeID int32) (*CharacterSkill, error)
+	GetSkillPointHistory(ctx context.Context, characterID int32, since time.Time) (*CharacterSkillPointHistory, error)
 	GetTotalTrainingTime(ctx context.Context, characterID int32) (optional.Optional[time.Duration], error)
+	GetTrainableSkill(ctx context.Context, typeID int32) (*TrainableSkill, error)
+	GetTrainingAttributes(ctx context.Context, characterID int32) (*CharacterTrainingAttributes, error)
 	HasTokenWithScopes(ctx context.Context, characterID int32) (bool, error)
 	ListAllAssets(ctx context.Context) ([]*CharacterAsset, error)
 	ListAllJumpClones(ctx context.Context) ([]*CharacterJumpClone2, error)
+	ListAllNotifications(ctx context.Context) ([]*CharacterNotification, error)
 	ListAllPlanets(ctx context.Context) ([]*CharacterPlanet, error)
 	ListAssets(ctx context.Context, characterID int32) ([]*CharacterAsset, error)
 	ListAssetsInItemHangar(ctx context.Context, characterID int32, locationID int64) ([]*CharacterAsset, error)
@@ -46,31 +77,58 @@ type CharacterService interface {
 	ListContracts(ctx context.Context, characterID int32) ([]*CharacterContract, error)
 	ListImplants(ctx context.Context, characterID int32) ([]*CharacterImplant, error)
 	ListJumpClones(ctx context.Context, characterID int32) ([]*CharacterJumpClone, error)
+	ListMailDrafts(ctx context.Context, characterID int32) ([]*CharacterMailDraft, error)
 	ListMailHeadersForLabelOrdered(ctx context.Context, characterID int32, labelID int32) ([]*CharacterMailHeader, error)
 	ListMailHeadersForListOrdered(ctx context.Context, characterID int32, listID int32) ([]*CharacterMailHeader, error)
 	ListMailLabelsOrdered(ctx context.Context, characterID int32) ([]*CharacterMailLabel, error)
 	ListMailLists(ctx context.Context, characterID int32) ([]*EveEntity, error)
+	ListMailRules(ctx context.Context, characterID int32) ([]*CharacterMailRule, error)
+	ListMailThreadsForLabelOrdered(ctx context.Context, characterID int32, labelID int32) ([]*CharacterMailThread, error)
+	ListMailThreadsForListOrdered(ctx context.Context, characterID int32, listID int32) ([]*CharacterMailThread, error)
+	ListMoonExtractions(ctx context.Context) ([]*MoonExtraction, error)
+	ListNotificationRules(ctx context.Context) ([]*NotificationRule, error)
 	ListNotificationsAll(ctx context.Context, characterID int32) ([]*CharacterNotification, error)
 	ListNotificationsTypes(ctx context.Context, characterID int32, ng NotificationGroup) ([]*CharacterNotification, error)
 	ListNotificationsUnread(ctx context.Context, characterID int32) ([]*CharacterNotification, error)
 	ListPlanets(ctx context.Context, characterID int32) ([]*CharacterPlanet, error)
+	ListRequiredSkills(ctx context.Context, typeID int32) ([]SkillRequirement, error)
+	ListShipMasteryLevels(ctx context.Context, characterID int32) (map[int32]int, error)
 	ListShipsAbilities(ctx context.Context, characterID int32, search string) ([]*CharacterShipAbility, error)
 	ListSkillGroupsProgress(ctx context.Context, characterID int32) ([]ListCharacterSkillGroupProgress, error)
 	ListSkillProgress(ctx context.Context, characterID, eveGroupID int32) ([]ListSkillProgress, error)
 	ListSkillqueueItems(ctx context.Context, characterID int32) ([]*CharacterSkillqueueItem, error)
+	ListStructureTimers(ctx context.Context) ([]*StructureTimer, error)
+	ListTimerEvents(ctx context.Context) ([]*TimerEvent, error)
 	ListWalletJournalEntries(ctx context.Context, characterID int32) ([]*CharacterWalletJournalEntry, error)
 	ListWalletTransactions(ctx context.Context, characterID int32) ([]*CharacterWalletTransaction, error)
-	NotifyCommunications(ctx context.Context, characterID int32, earliest time.Time, typesEnabled set.Set[string], notify func(title, content string)) error
+	ListWebhooks(ctx context.Context) ([]*Webhook, error)
+	NotifyCommunications(ctx context.Context, characterID int32, earliest time.Time, typesEnabled set.Set[string], notify func(title, content string, channels set.Set[NotificationChannel])) error
 	NotifyExpiredExtractions(ctx context.Context, characterID int32, earliest time.Time, notify func(title, content string)) error
 	NotifyExpiredTraining(ctx context.Context, characterID int32, notify func(title, content string)) error
 	NotifyMails(ctx context.Context, characterID int32, earliest time.Time, notify func(title, content string)) error
+	NotifyMoonExtractions(ctx context.Context, earliest time.Time, notify func(title, content string)) error
+	NotifyStructureTimers(ctx context.Context, leadTime time.Duration, notify func(title, content string)) error
 	NotifyUpdatedContracts(ctx context.Context, characterID int32, earliest time.Time, notify func(title, content string)) error
+	QueueDueMails(ctx context.Context) error
+	RemoveMailsLabel(ctx context.Context, characterID int32, mailIDs []int32, labelID int32) error
+	RunMailOutbox(ctx context.Context, onChanged func(characterID int32))
+	SearchData(ctx context.Context, query string) ([]*DataSearchResult, error)
+	SaveMailDraft(ctx context.Context, d *CharacterMailDraft) (int64, error)
 	SearchESI(ctx context.Context, characterID int32, search string, categories []SearchCategory, strict bool) (map[SearchCategory][]*EveEntity, int, error)
 	SendMail(ctx context.Context, characterID int32, subject string, recipients []*EveEntity, body string) (int32, error)
+	SendMailDraft(ctx context.Context, d *CharacterMailDraft) (bool, error)
+	SendWebhookMessages(ctx context.Context) error
+	SortMissingSkills(ctx context.Context, missing []MissingSkill) ([]MissingSkill, error)
 	UpdateAssetTotalValue(ctx context.Context, characterID int32) (float64, error)
 	UpdateIsTrainingWatched(ctx context.Context, id int32, v bool) error
 	UpdateMailRead(ctx context.Context, characterID, mailID int32) error
+	UpdateMailRule(ctx context.Context, r *CharacterMailRule) error
+	UpdateMailsRead(ctx context.Context, characterID int32, mailIDs []int32, isRead bool) error
+	UpdateNotificationRule(ctx context.Context, r *NotificationRule) error
+	UpdateNotificationsSetProcessed(ctx context.Context, ids set.Set[int64]) error
 	UpdateOrCreateCharacterFromSSO(ctx context.Context, infoText binding.ExternalString) (int32, error)
 	UpdateSectionIfNeeded(ctx context.Context, arg CharacterUpdateSectionParams) (bool, error)
 	UpdateSkillqueueESI(ctx context.Context, arg CharacterUpdateSectionParams) (bool, error)
+	UpdateWebhook(ctx context.Context, w *Webhook) error
+	WriteTimersCalendar(ctx context.Context, w io.Writer) error
 }
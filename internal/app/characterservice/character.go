@@ -3,6 +3,7 @@ package characterservice
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"slices"
 
@@ -274,23 +275,30 @@ func (s *CharacterService) updateWalletBalanceESI(ctx context.Context, arg app.C
 }
 
 // AddEveEntitiesFromSearchESI runs a search on ESI and adds the results as new EveEntity objects to the database.
+// It searches for alliances, characters and corporations, unless other categories are specified.
 // This method performs a character specific search and needs a token.
-func (s *CharacterService) AddEveEntitiesFromSearchESI(ctx context.Context, characterID int32, search string) ([]int32, error) {
+func (s *CharacterService) AddEveEntitiesFromSearchESI(ctx context.Context, characterID int32, search string, categories ...app.EveEntityCategory) ([]int32, error) {
+	if len(categories) == 0 {
+		categories = []app.EveEntityCategory{app.EveEntityAlliance, app.EveEntityCharacter, app.EveEntityCorporation}
+	}
+	esiCategories := make([]string, 0)
+	for _, c := range categories {
+		x, ok := esiSearchCategories[c]
+		if !ok {
+			return nil, fmt.Errorf("search for category %s: %w", c, app.ErrInvalid)
+		}
+		esiCategories = append(esiCategories, x)
+	}
 	token, err := s.getValidCharacterToken(ctx, characterID)
 	if err != nil {
 		return nil, err
 	}
-	categories := []string{
-		"corporation",
-		"character",
-		"alliance",
-	}
 	ctx = contextWithESIToken(ctx, token.AccessToken)
-	r, _, err := s.esiClient.ESI.SearchApi.GetCharactersCharacterIdSearch(ctx, categories, characterID, search, nil)
+	r, _, err := s.esiClient.ESI.SearchApi.GetCharactersCharacterIdSearch(ctx, esiCategories, characterID, search, nil)
 	if err != nil {
 		return nil, err
 	}
-	ids := slices.Concat(r.Alliance, r.Character, r.Corporation)
+	ids := slices.Concat(r.Alliance, r.Character, r.Corporation, r.InventoryType, r.SolarSystem)
 	missingIDs, err := s.EveUniverseService.AddMissingEntities(ctx, ids)
 	if err != nil {
 		slog.Error("Failed to fetch missing IDs", "error", err)
@@ -298,3 +306,12 @@ func (s *CharacterService) AddEveEntitiesFromSearchESI(ctx context.Context, char
 	}
 	return missingIDs, nil
 }
+
+// esiSearchCategories maps entity categories to the categories of the ESI search.
+var esiSearchCategories = map[app.EveEntityCategory]string{
+	app.EveEntityAlliance:      "alliance",
+	app.EveEntityCharacter:     "character",
+	app.EveEntityCorporation:   "corporation",
+	app.EveEntityInventoryType: "inventory_type",
+	app.EveEntitySolarSystem:   "solar_system",
+}
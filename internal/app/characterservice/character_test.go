@@ -2,8 +2,12 @@ package characterservice_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/antihax/goesi"
+	"github.com/jarcoal/httpmock"
+
 	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/ErikKalkoken/evebuddy/internal/app/characterservice"
 	"github.com/ErikKalkoken/evebuddy/internal/app/eveuniverseservice"
@@ -143,10 +147,57 @@ func TestTrainingWatchers(t *testing.T) {
 
 func newCharacterService(st *storage.Storage) *characterservice.CharacterService {
 	sc := statuscacheservice.New(memcache.New())
-	eu := eveuniverseservice.New(st, nil)
+	eu := eveuniverseservice.New(st, goesi.NewAPIClient(nil, ""))
 	eu.StatusCacheService = sc
 	s := characterservice.New(st, nil, nil)
 	s.EveUniverseService = eu
 	s.StatusCacheService = sc
 	return s
 }
+
+func TestAddEveEntitiesFromSearchESI(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("can search for solar systems", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		httpmock.RegisterResponder(
+			"GET",
+			fmt.Sprintf(`=~^https://esi\.evetech\.net/v\d+/characters/%d/search/\?.*categories=solar_system`, c.ID),
+			httpmock.NewJsonResponderOrPanic(200, map[string]any{"solar_system": []int{30002537}}),
+		)
+		httpmock.RegisterResponder(
+			"POST",
+			"https://esi.evetech.net/v3/universe/names/",
+			httpmock.NewJsonResponderOrPanic(200, []map[string]any{
+				{"id": 30002537, "name": "Amamake", "category": "solar_system"},
+			}),
+		)
+		// when
+		ids, err := cs.AddEveEntitiesFromSearchESI(ctx, c.ID, "Amamake", app.EveEntitySolarSystem)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, []int32{30002537}, ids)
+			e, err := st.GetEveEntity(ctx, 30002537)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.EveEntitySolarSystem, e.Category)
+			}
+		}
+	})
+	t.Run("should return error when category is not supported", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		_, err := cs.AddEveEntitiesFromSearchESI(ctx, c.ID, "Jita", app.EveEntityStation)
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+}
@@ -3,6 +3,7 @@ package characterservice
 
 import (
 	"net/http"
+	"sync"
 
 	"golang.org/x/sync/singleflight"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
 	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
 	"github.com/ErikKalkoken/evebuddy/internal/sso"
+	"github.com/ErikKalkoken/evebuddy/internal/syncqueue"
 	"github.com/antihax/goesi"
 )
 
@@ -22,6 +24,8 @@ type CharacterService struct {
 
 	esiClient  *goesi.APIClient
 	httpClient *http.Client
+	mailOutbox *syncqueue.SyncQueue[mailOutboxItem] // queued mails waiting to be sent by the outbox worker
+	notifyMu   sync.Mutex                           // serializes sending notifications, so that nothing is notified twice
 	sfg        *singleflight.Group
 	st         *storage.Storage
 }
@@ -44,6 +48,7 @@ func New(
 		st:         st,
 		esiClient:  esiClient,
 		httpClient: httpClient,
+		mailOutbox: syncqueue.New[mailOutboxItem](),
 		sfg:        new(singleflight.Group),
 	}
 	return ct
@@ -87,6 +87,10 @@ func (cs *CharacterService) NotifyUpdatedContracts(ctx context.Context, characte
 	return nil
 }
 
+func (s *CharacterService) GetContract(ctx context.Context, characterID, contractID int32) (*app.CharacterContract, error) {
+	return s.st.GetCharacterContract(ctx, characterID, contractID)
+}
+
 func (s *CharacterService) ListContracts(ctx context.Context, characterID int32) ([]*app.CharacterContract, error) {
 	return s.st.ListCharacterContracts(ctx, characterID)
 }
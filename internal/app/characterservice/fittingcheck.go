@@ -0,0 +1,141 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/eft"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+// CheckFitting reports which characters have the skills to fly the ship of a fitting
+// and use all its modules, charges and drones.
+//
+// Items with quantities are only checked when they are drones or fighters,
+// since other items are assumed to be cargo.
+func (s *CharacterService) CheckFitting(ctx context.Context, fitting *eft.Fitting) (*app.FittingCheck, error) {
+	ids, err := s.EveUniverseService.ResolveTypeNamesESI(ctx, fitting.TypeNames())
+	if err != nil {
+		return nil, err
+	}
+	fc := &app.FittingCheck{
+		Characters:   make([]app.FittingCheckCharacter, 0),
+		FittingName:  fitting.Name,
+		ShipTypeName: fitting.ShipType,
+		UnknownTypes: make([]string, 0),
+	}
+	shipTypeID, ok := ids[fitting.ShipType]
+	if !ok {
+		return nil, fmt.Errorf("check fitting: unknown ship type %s: %w", fitting.ShipType, app.ErrNotFound)
+	}
+	fc.ShipTypeID = shipTypeID
+	typeIDs := []int32{shipTypeID}
+	for _, it := range fitting.Modules {
+		typeIDs = append(typeIDs, ids[it.Name])
+	}
+	for _, it := range fitting.Charges {
+		typeIDs = append(typeIDs, ids[it.Name])
+	}
+	for _, it := range fitting.Items {
+		id, ok := ids[it.Name]
+		if !ok {
+			typeIDs = append(typeIDs, 0)
+			continue
+		}
+		et, err := s.EveUniverseService.GetOrCreateTypeESI(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch et.Group.Category.ID {
+		case app.EveCategoryDrone, app.EveCategoryFighter:
+			typeIDs = append(typeIDs, id)
+		}
+	}
+	for _, name := range fitting.TypeNames() {
+		if _, ok := ids[name]; !ok {
+			fc.UnknownTypes = append(fc.UnknownTypes, name)
+		}
+	}
+	checked := make(map[int32]bool)
+	lists := make([][]app.SkillRequirement, 0)
+	for _, id := range typeIDs {
+		if id == 0 || checked[id] {
+			continue
+		}
+		checked[id] = true
+		requirements, err := s.ListRequiredSkills(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, requirements)
+	}
+	fc.Requirements = app.MergeSkillRequirements(lists...)
+	characters, err := s.st.ListCharactersShort(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range characters {
+		x, err := s.checkFittingForCharacter(ctx, c, fc.Requirements)
+		if err != nil {
+			return nil, err
+		}
+		fc.Characters = append(fc.Characters, x)
+	}
+	return fc, nil
+}
+
+func (s *CharacterService) checkFittingForCharacter(ctx context.Context, c *app.CharacterShort, requirements []app.SkillRequirement) (app.FittingCheckCharacter, error) {
+	r := app.FittingCheckCharacter{
+		CharacterID:   c.ID,
+		CharacterName: c.Name,
+	}
+	levels := make(map[int32]int)
+	for _, req := range requirements {
+		skill, err := s.st.GetCharacterSkill(ctx, c.ID, req.SkillID)
+		if errors.Is(err, app.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return r, err
+		}
+		levels[req.SkillID] = skill.TrainedSkillLevel
+	}
+	r.Missing = app.CheckSkillRequirements(levels, requirements)
+	d, err := s.calcMissingSkillsTrainingTime(ctx, c.ID, r.Missing)
+	if err != nil {
+		return r, err
+	}
+	r.TrainingTime = d
+	return r, nil
+}
+
+// calcMissingSkillsTrainingTime returns the time for a character to train all missing skills
+// with the current attributes. The time is empty when it can not be calculated.
+func (s *CharacterService) calcMissingSkillsTrainingTime(ctx context.Context, characterID int32, missing []app.MissingSkill) (optional.Optional[time.Duration], error) {
+	var z optional.Optional[time.Duration]
+	if len(missing) == 0 {
+		return optional.New(time.Duration(0)), nil
+	}
+	ta, err := s.GetTrainingAttributes(ctx, characterID)
+	if errors.Is(err, app.ErrNotFound) {
+		return z, nil
+	}
+	if err != nil {
+		return z, err
+	}
+	var total time.Duration
+	for _, m := range missing {
+		d, err := s.CalcSkillTrainingTime(ctx, characterID, m.SkillID, m.RequiredLevel, ta.Current)
+		if errors.Is(err, app.ErrNotFound) {
+			return z, nil
+		}
+		if err != nil {
+			return z, err
+		}
+		total += d
+	}
+	return optional.New(total), nil
+}
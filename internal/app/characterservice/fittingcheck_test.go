@@ -0,0 +1,119 @@
+package characterservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/eft"
+)
+
+func TestCheckFitting(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	for _, id := range []int32{
+		app.EveDogmaAttributePrimarySkillID,
+		app.EveDogmaAttributePrimarySkillLevel,
+		app.EveDogmaAttributePrimaryAttribute,
+		app.EveDogmaAttributeSecondaryAttribute,
+		app.EveDogmaAttributeTrainingTimeMultiplier,
+	} {
+		factory.CreateEveDogmaAttribute(storage.CreateEveDogmaAttributeParams{ID: id})
+	}
+	addAttributes := func(typeID int32, values map[int32]float32) {
+		for id, v := range values {
+			factory.CreateEveTypeDogmaAttribute(storage.CreateEveTypeDogmaAttributeParams{
+				EveTypeID:        typeID,
+				DogmaAttributeID: id,
+				Value:            v,
+			})
+		}
+	}
+	skillCategory := factory.CreateEveCategory(storage.CreateEveCategoryParams{ID: app.EveCategorySkill})
+	skill := factory.CreateEveType(storage.CreateEveTypeParams{
+		GroupID: factory.CreateEveGroup(storage.CreateEveGroupParams{CategoryID: skillCategory.ID}).ID,
+		Name:    "Spaceship Command",
+	})
+	addAttributes(skill.ID, map[int32]float32{
+		app.EveDogmaAttributePrimaryAttribute:       app.EveDogmaAttributeIntelligence,
+		app.EveDogmaAttributeSecondaryAttribute:     app.EveDogmaAttributeMemory,
+		app.EveDogmaAttributeTrainingTimeMultiplier: 1,
+	})
+	ship := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Tengu"})
+	addAttributes(ship.ID, map[int32]float32{
+		app.EveDogmaAttributePrimarySkillID:    float32(skill.ID),
+		app.EveDogmaAttributePrimarySkillLevel: 3,
+	})
+	droneCategory := factory.CreateEveCategory(storage.CreateEveCategoryParams{ID: app.EveCategoryDrone})
+	drone := factory.CreateEveType(storage.CreateEveTypeParams{
+		GroupID: factory.CreateEveGroup(storage.CreateEveGroupParams{CategoryID: droneCategory.ID}).ID,
+		Name:    "Hornet II",
+	})
+	addAttributes(drone.ID, map[int32]float32{
+		app.EveDogmaAttributePrimarySkillID:    float32(skill.ID),
+		app.EveDogmaAttributePrimarySkillLevel: 2,
+	})
+	cargo := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Spare Part"})
+	addAttributes(cargo.ID, map[int32]float32{
+		app.EveDogmaAttributePrimarySkillID:    float32(skill.ID),
+		app.EveDogmaAttributePrimarySkillLevel: 5,
+	})
+	c1 := factory.CreateCharacter()
+	factory.CreateCharacterSkill(storage.UpdateOrCreateCharacterSkillParams{
+		CharacterID:        c1.ID,
+		EveTypeID:          skill.ID,
+		ActiveSkillLevel:   3,
+		TrainedSkillLevel:  3,
+		SkillPointsInSkill: 8000,
+	})
+	c2 := factory.CreateCharacter()
+	factory.CreateCharacterAttributes(storage.UpdateOrCreateCharacterAttributesParams{
+		CharacterID:  c2.ID,
+		Intelligence: 27,
+		Memory:       21,
+	})
+	httpmock.RegisterResponder(
+		"POST",
+		"https://esi.evetech.net/v1/universe/ids/",
+		httpmock.NewJsonResponderOrPanic(200, map[string]any{
+			"inventory_types": []map[string]any{
+				{"id": ship.ID, "name": "Tengu"},
+				{"id": drone.ID, "name": "Hornet II"},
+				{"id": cargo.ID, "name": "Spare Part"},
+			},
+		}),
+	)
+	fitting := &eft.Fitting{
+		Name:     "Test",
+		ShipType: "Tengu",
+		Modules:  []eft.Item{{Name: "Unknown Module", Quantity: 1}},
+		Items:    []eft.Item{{Name: "Hornet II", Quantity: 5}, {Name: "Spare Part", Quantity: 1}},
+	}
+	// when
+	got, err := cs.CheckFitting(ctx, fitting)
+	// then
+	if assert.NoError(t, err) {
+		assert.Equal(t, ship.ID, got.ShipTypeID)
+		assert.Equal(t, []string{"Unknown Module"}, got.UnknownTypes)
+		assert.Equal(t, []app.SkillRequirement{{SkillID: skill.ID, SkillName: "Spaceship Command", Level: 3}}, got.Requirements)
+		m := make(map[int32]app.FittingCheckCharacter)
+		for _, c := range got.Characters {
+			m[c.CharacterID] = c
+		}
+		assert.True(t, m[c1.ID].CanUse())
+		assert.False(t, m[c2.ID].CanUse())
+		assert.Equal(t, []app.MissingSkill{{SkillID: skill.ID, SkillName: "Spaceship Command", CurrentLevel: 0, RequiredLevel: 3}}, m[c2.ID].Missing)
+		assert.Equal(t, 213*time.Minute+20*time.Second, m[c2.ID].TrainingTime.ValueOrZero())
+		assert.Len(t, got.CanUse(), 1)
+	}
+}
@@ -1,9 +1,14 @@
 package characterservice
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 	"slices"
 	"time"
 
@@ -11,8 +16,55 @@ import (
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
 )
 
+const esiBaseURL = "https://esi.evetech.net"
+
+// AddMailsLabel adds a label to mails both on ESI and in the database.
+func (s *CharacterService) AddMailsLabel(ctx context.Context, characterID int32, mailIDs []int32, labelID int32) error {
+	return s.updateMailContentsESI(ctx, characterID, mailIDs, func(isRead bool, labels set.Set[int32]) (bool, set.Set[int32]) {
+		labels.Add(labelID)
+		return isRead, labels
+	})
+}
+
+// CreateMailLabel creates a new mail label both on ESI and in the database
+// and returns the ID of the new label.
+func (s *CharacterService) CreateMailLabel(ctx context.Context, characterID int32, name, color string) (int32, error) {
+	if name == "" {
+		return 0, fmt.Errorf("create mail label: missing name: %w", app.ErrInvalid)
+	}
+	if !slices.Contains(app.MailLabelColors, color) {
+		return 0, fmt.Errorf("create mail label: invalid color %s: %w", color, app.ErrInvalid)
+	}
+	token, err := s.getValidCharacterToken(ctx, characterID)
+	if err != nil {
+		return 0, err
+	}
+	ctx = contextWithESIToken(ctx, token.AccessToken)
+	labelID, _, err := s.esiClient.ESI.MailApi.PostCharactersCharacterIdMailLabels(
+		ctx,
+		characterID,
+		esi.PostCharactersCharacterIdMailLabelsLabel{Color: color, Name: name},
+		nil,
+	)
+	if err != nil {
+		return 0, err
+	}
+	arg := storage.MailLabelParams{
+		CharacterID: characterID,
+		Color:       color,
+		LabelID:     labelID,
+		Name:        name,
+	}
+	if _, err := s.st.UpdateOrCreateCharacterMailLabel(ctx, arg); err != nil {
+		return 0, err
+	}
+	slog.Info("Mail label created", "characterID", characterID, "labelID", labelID)
+	return labelID, nil
+}
+
 // DeleteMail deletes a mail both on ESI and in the database.
 func (s *CharacterService) DeleteMail(ctx context.Context, characterID, mailID int32) error {
 	token, err := s.getValidCharacterToken(ctx, characterID)
@@ -32,6 +84,38 @@ func (s *CharacterService) DeleteMail(ctx context.Context, characterID, mailID i
 	return nil
 }
 
+// DeleteMailLabel deletes a custom mail label both on ESI and in the database.
+// The label is also removed from all mails.
+func (s *CharacterService) DeleteMailLabel(ctx context.Context, characterID, labelID int32) error {
+	if labelID <= app.MailLabelAlliance {
+		return fmt.Errorf("delete mail label %d: can not delete default labels: %w", labelID, app.ErrInvalid)
+	}
+	token, err := s.getValidCharacterToken(ctx, characterID)
+	if err != nil {
+		return err
+	}
+	ctx = contextWithESIToken(ctx, token.AccessToken)
+	_, err = s.esiClient.ESI.MailApi.DeleteCharactersCharacterIdMailLabelsLabelId(ctx, characterID, labelID, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.st.DeleteCharacterMailLabel(ctx, characterID, labelID); err != nil {
+		return err
+	}
+	slog.Info("Mail label deleted", "characterID", characterID, "labelID", labelID)
+	return nil
+}
+
+// DeleteMails deletes several mails both on ESI and in the database.
+func (s *CharacterService) DeleteMails(ctx context.Context, characterID int32, mailIDs []int32) error {
+	for _, id := range mailIDs {
+		if err := s.DeleteMail(ctx, characterID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *CharacterService) GetMail(ctx context.Context, characterID int32, mailID int32) (*app.CharacterMail, error) {
 	return s.st.GetCharacterMail(ctx, characterID, mailID)
 }
@@ -101,24 +185,74 @@ func (s *CharacterService) ListMailLabelsOrdered(ctx context.Context, characterI
 	return s.st.ListCharacterMailLabelsOrdered(ctx, characterID)
 }
 
+// ListMailThreadsForLabelOrdered returns the mails with a label grouped into conversations.
+// Threads are ordered by their latest mail in descending order.
+func (s *CharacterService) ListMailThreadsForLabelOrdered(ctx context.Context, characterID int32, labelID int32) ([]*app.CharacterMailThread, error) {
+	headers, err := s.st.ListCharacterMailHeadersForLabelOrdered(ctx, characterID, labelID)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupMailThreads(ctx, characterID, headers)
+}
+
+// ListMailThreadsForListOrdered returns the mails sent to a mailing list grouped into conversations.
+// Threads are ordered by their latest mail in descending order.
+func (s *CharacterService) ListMailThreadsForListOrdered(ctx context.Context, characterID int32, listID int32) ([]*app.CharacterMailThread, error) {
+	headers, err := s.st.ListCharacterMailHeadersForListOrdered(ctx, characterID, listID)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupMailThreads(ctx, characterID, headers)
+}
+
+func (s *CharacterService) groupMailThreads(ctx context.Context, characterID int32, headers []*app.CharacterMailHeader) ([]*app.CharacterMailThread, error) {
+	participants, err := s.st.ListCharacterMailParticipants(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	return app.GroupMailThreads(characterID, headers, participants), nil
+}
+
+// RemoveMailsLabel removes a label from mails both on ESI and in the database.
+// Returns [app.ErrInvalid] when a mail would be left without any label.
+func (s *CharacterService) RemoveMailsLabel(ctx context.Context, characterID int32, mailIDs []int32, labelID int32) error {
+	return s.updateMailContentsESI(ctx, characterID, mailIDs, func(isRead bool, labels set.Set[int32]) (bool, set.Set[int32]) {
+		labels.Remove(labelID)
+		return isRead, labels
+	})
+}
+
 // SendMail creates a new mail on ESI and stores it locally.
 func (s *CharacterService) SendMail(ctx context.Context, characterID int32, subject string, recipients []*app.EveEntity, body string) (int32, error) {
+	mailID, _, err := s.postMailESI(ctx, characterID, subject, recipients, body)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.storeSentMail(ctx, characterID, mailID, subject, recipients, body); err != nil {
+		return 0, err
+	}
+	return mailID, nil
+}
+
+// postMailESI creates a new mail on ESI and returns it's mail ID.
+// The response from ESI is returned too, if there was one.
+func (s *CharacterService) postMailESI(ctx context.Context, characterID int32, subject string, recipients []*app.EveEntity, body string) (int32, *http.Response, error) {
 	if subject == "" {
-		return 0, fmt.Errorf("missing subject")
+		return 0, nil, fmt.Errorf("missing subject: %w", app.ErrInvalid)
 	}
 	if body == "" {
-		return 0, fmt.Errorf("missing body")
+		return 0, nil, fmt.Errorf("missing body: %w", app.ErrInvalid)
 	}
 	if len(recipients) == 0 {
-		return 0, fmt.Errorf("missing recipients")
+		return 0, nil, fmt.Errorf("missing recipients: %w", app.ErrInvalid)
 	}
 	rr, err := eveEntitiesToESIMailRecipients(recipients)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	token, err := s.getValidCharacterToken(ctx, characterID)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	esiMail := esi.PostCharactersCharacterIdMailMail{
 		Body:       body,
@@ -126,18 +260,24 @@ func (s *CharacterService) SendMail(ctx context.Context, characterID int32, subj
 		Recipients: rr,
 	}
 	ctx = contextWithESIToken(ctx, token.AccessToken)
-	mailID, _, err := s.esiClient.ESI.MailApi.PostCharactersCharacterIdMail(ctx, characterID, esiMail, nil)
+	mailID, r, err := s.esiClient.ESI.MailApi.PostCharactersCharacterIdMail(ctx, characterID, esiMail, nil)
 	if err != nil {
-		return 0, err
+		return 0, r, err
 	}
-	recipientIDs := make([]int32, len(rr))
-	for i, r := range rr {
-		recipientIDs[i] = r.RecipientId
+	slog.Info("Mail sent", "characterID", characterID, "mailID", mailID)
+	return mailID, r, nil
+}
+
+// storeSentMail stores a mail which was sent by a character.
+func (s *CharacterService) storeSentMail(ctx context.Context, characterID, mailID int32, subject string, recipients []*app.EveEntity, body string) error {
+	recipientIDs := make([]int32, len(recipients))
+	for i, r := range recipients {
+		recipientIDs[i] = r.ID
 	}
 	ids := slices.Concat(recipientIDs, []int32{characterID})
-	_, err = s.EveUniverseService.AddMissingEntities(ctx, ids)
+	_, err := s.EveUniverseService.AddMissingEntities(ctx, ids)
 	if err != nil {
-		return 0, err
+		return err
 	}
 	arg1 := storage.MailLabelParams{
 		CharacterID: characterID,
@@ -146,7 +286,7 @@ func (s *CharacterService) SendMail(ctx context.Context, characterID int32, subj
 	}
 	_, err = s.st.GetOrCreateCharacterMailLabel(ctx, arg1) // make sure sent label exists
 	if err != nil {
-		return 0, err
+		return err
 	}
 	arg2 := storage.CreateCharacterMailParams{
 		Body:         body,
@@ -161,10 +301,89 @@ func (s *CharacterService) SendMail(ctx context.Context, characterID int32, subj
 	}
 	_, err = s.st.CreateCharacterMail(ctx, arg2)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	slog.Info("Mail sent", "characterID", characterID, "mailID", mailID)
-	return mailID, nil
+	return nil
+}
+
+// UpdateMailsRead updates the read state of mails both on ESI and in the database.
+func (s *CharacterService) UpdateMailsRead(ctx context.Context, characterID int32, mailIDs []int32, isRead bool) error {
+	return s.updateMailContentsESI(ctx, characterID, mailIDs, func(_ bool, labels set.Set[int32]) (bool, set.Set[int32]) {
+		return isRead, labels
+	})
+}
+
+// updateMailContentsESI applies a change of read state and labels to mails both on ESI and in the database.
+// Mails which are not changed are skipped.
+// Mails which could not be updated are reported in the returned error
+// and are not changed in the database, while the remaining mails are still updated.
+func (s *CharacterService) updateMailContentsESI(ctx context.Context, characterID int32, mailIDs []int32, change func(isRead bool, labels set.Set[int32]) (bool, set.Set[int32])) error {
+	token, err := s.getValidCharacterToken(ctx, characterID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, mailID := range mailIDs {
+		m, err := s.st.GetCharacterMail(ctx, characterID, mailID)
+		if err != nil {
+			return err
+		}
+		labels := set.New[int32]()
+		for _, l := range m.Labels {
+			labels.Add(l.LabelID)
+		}
+		isRead, labels2 := change(m.IsRead, labels.Clone())
+		if isRead == m.IsRead && labels2.Equal(labels) {
+			continue
+		}
+		if labels2.Size() == 0 {
+			errs = append(errs, fmt.Errorf("mail %q: a mail needs at least one label: %w", m.Subject, app.ErrInvalid))
+			continue
+		}
+		labelIDs := labels2.ToSlice()
+		slices.Sort(labelIDs)
+		if err := s.putMailContentsESI(ctx, token.AccessToken, characterID, mailID, isRead, labelIDs); err != nil {
+			errs = append(errs, fmt.Errorf("mail %q: %w", m.Subject, err))
+			continue
+		}
+		if err := s.st.UpdateCharacterMail(ctx, characterID, m.ID, isRead, labelIDs); err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("update mails: %d of %d mails failed: %w", len(errs), len(mailIDs), errors.Join(errs...))
+	}
+	return nil
+}
+
+// putMailContentsESI updates the read state and labels of a mail on ESI.
+// This is a raw request, because goesi omits the read flag when it is false,
+// which makes it impossible to mark a mail as unread.
+func (s *CharacterService) putMailContentsESI(ctx context.Context, accessToken string, characterID, mailID int32, isRead bool, labelIDs []int32) error {
+	data, err := json.Marshal(struct {
+		Labels []int32 `json:"labels"`
+		Read   bool    `json:"read"`
+	}{labelIDs, isRead})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/v1/characters/%d/mail/%d/", esiBaseURL, characterID, mailID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("update mail %d for character %d: %s: %s", mailID, characterID, resp.Status, body)
+	}
+	return nil
 }
 
 var eveEntityCategory2MailRecipientType = map[app.EveEntityCategory]string{
@@ -179,7 +398,7 @@ func eveEntitiesToESIMailRecipients(ee []*app.EveEntity) ([]esi.PostCharactersCh
 	for i, e := range ee {
 		c, ok := eveEntityCategory2MailRecipientType[e.Category]
 		if !ok {
-			return rr, fmt.Errorf("match EveEntity category to ESI mail recipient type: %v", e)
+			return rr, fmt.Errorf("match EveEntity category to ESI mail recipient type: %v: %w", e, app.ErrInvalid)
 		}
 		rr[i] = esi.PostCharactersCharacterIdMailRecipient{
 			RecipientId:   e.ID,
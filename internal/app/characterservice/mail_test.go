@@ -2,7 +2,10 @@ package characterservice_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"testing"
 	"time"
 
@@ -83,3 +86,295 @@ func TestSendMail(t *testing.T) {
 		}
 	})
 }
+
+func TestCreateMailLabel(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	s := newCharacterService(st)
+	t.Run("can create label", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v2/characters/%d/mail/labels/", c.ID),
+			httpmock.NewJsonResponderOrPanic(201, 42))
+		// when
+		labelID, err := s.CreateMailLabel(ctx, c.ID, "Doctrines", "#ff6600")
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, int32(42), labelID)
+			l, err := st.GetCharacterMailLabel(ctx, c.ID, 42)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "Doctrines", l.Name)
+				assert.Equal(t, "#ff6600", l.Color)
+			}
+		}
+	})
+	t.Run("should return error when color is invalid", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		_, err := s.CreateMailLabel(ctx, c.ID, "Doctrines", "#123456")
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+}
+
+func TestDeleteMailLabel(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	s := newCharacterService(st)
+	t.Run("can delete label", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		l := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		httpmock.RegisterResponder(
+			"DELETE",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/labels/%d/", c.ID, l.LabelID),
+			httpmock.NewStringResponder(204, ""))
+		// when
+		err := s.DeleteMailLabel(ctx, c.ID, l.LabelID)
+		// then
+		if assert.NoError(t, err) {
+			_, err := st.GetCharacterMailLabel(ctx, c.ID, l.LabelID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+		}
+	})
+	t.Run("should not delete default labels", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		err := s.DeleteMailLabel(ctx, c.ID, app.MailLabelInbox)
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+}
+
+func TestUpdateMails(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	s := newCharacterService(st)
+	type mailContents struct {
+		Labels []int32 `json:"labels"`
+		Read   bool    `json:"read"`
+	}
+	var body string // body of the last request
+	registerResponder := func(characterID, mailID int32, sent *mailContents) {
+		httpmock.RegisterResponder(
+			"PUT",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/%d/", characterID, mailID),
+			func(req *http.Request) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				body = string(b)
+				if err := json.Unmarshal(b, sent); err != nil {
+					return nil, err
+				}
+				return httpmock.NewStringResponse(204, ""), nil
+			})
+	}
+	t.Run("can mark mails as unread", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		l := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			IsRead:      true,
+			LabelIDs:    []int32{l.LabelID},
+		})
+		sent := &mailContents{Read: true}
+		registerResponder(c.ID, m.MailID, sent)
+		// when
+		err := s.UpdateMailsRead(ctx, c.ID, []int32{m.MailID}, false)
+		// then
+		if assert.NoError(t, err) {
+			assert.False(t, sent.Read)
+			assert.Contains(t, body, `"read":false`)
+			assert.Equal(t, []int32{l.LabelID}, sent.Labels)
+			m2, err := st.GetCharacterMail(ctx, c.ID, m.MailID)
+			if assert.NoError(t, err) {
+				assert.False(t, m2.IsRead)
+			}
+		}
+	})
+	t.Run("can add label to mails", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		l1 := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		l2 := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			IsRead:      true,
+			LabelIDs:    []int32{l1.LabelID},
+		})
+		sent := &mailContents{}
+		registerResponder(c.ID, m.MailID, sent)
+		// when
+		err := s.AddMailsLabel(ctx, c.ID, []int32{m.MailID}, l2.LabelID)
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, sent.Read)
+			assert.ElementsMatch(t, []int32{l1.LabelID, l2.LabelID}, sent.Labels)
+			m2, err := st.GetCharacterMail(ctx, c.ID, m.MailID)
+			if assert.NoError(t, err) {
+				assert.Len(t, m2.Labels, 2)
+			}
+		}
+	})
+	t.Run("can remove label from mails", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		l1 := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		l2 := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			LabelIDs:    []int32{l1.LabelID, l2.LabelID},
+		})
+		sent := &mailContents{}
+		registerResponder(c.ID, m.MailID, sent)
+		// when
+		err := s.RemoveMailsLabel(ctx, c.ID, []int32{m.MailID}, l2.LabelID)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, []int32{l1.LabelID}, sent.Labels)
+			m2, err := st.GetCharacterMail(ctx, c.ID, m.MailID)
+			if assert.NoError(t, err) {
+				if assert.Len(t, m2.Labels, 1) {
+					assert.Equal(t, l1.LabelID, m2.Labels[0].LabelID)
+				}
+			}
+		}
+	})
+	t.Run("should update remaining mails and report failed ones", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		l := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		m1 := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			LabelIDs:    []int32{l.LabelID},
+			Subject:     "Alpha",
+		})
+		m2 := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			LabelIDs:    []int32{l.LabelID},
+			Subject:     "Bravo",
+		})
+		registerResponder(c.ID, m1.MailID, &mailContents{})
+		httpmock.RegisterResponder(
+			"PUT",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/%d/", c.ID, m2.MailID),
+			httpmock.NewJsonResponderOrPanic(404, map[string]string{"error": "Mail not found"}),
+		)
+		// when
+		err := s.UpdateMailsRead(ctx, c.ID, []int32{m1.MailID, m2.MailID}, true)
+		// then
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "Bravo")
+			assert.NotContains(t, err.Error(), "Alpha")
+		}
+		x1, err := st.GetCharacterMail(ctx, c.ID, m1.MailID)
+		if assert.NoError(t, err) {
+			assert.True(t, x1.IsRead)
+		}
+		x2, err := st.GetCharacterMail(ctx, c.ID, m2.MailID)
+		if assert.NoError(t, err) {
+			assert.False(t, x2.IsRead)
+		}
+	})
+	t.Run("should not remove last label", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		l := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			LabelIDs:    []int32{l.LabelID},
+		})
+		// when
+		err := s.RemoveMailsLabel(ctx, c.ID, []int32{m.MailID}, l.LabelID)
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+}
+
+func TestListMailThreads(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	s := newCharacterService(st)
+	t.Run("can list threads for label", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		me := factory.CreateEveEntityCharacter(app.EveEntity{ID: c.ID})
+		other := factory.CreateEveEntityCharacter()
+		inbox := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID, LabelID: app.MailLabelInbox})
+		now := time.Now().UTC()
+		m1 := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID:  c.ID,
+			FromID:       other.ID,
+			LabelIDs:     []int32{inbox.LabelID},
+			RecipientIDs: []int32{me.ID},
+			Subject:      "Peace talks",
+			Timestamp:    now.Add(-2 * time.Hour),
+		})
+		m2 := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID:  c.ID,
+			FromID:       other.ID,
+			LabelIDs:     []int32{inbox.LabelID},
+			RecipientIDs: []int32{me.ID},
+			Subject:      "Re: Peace talks",
+			Timestamp:    now.Add(-1 * time.Hour),
+		})
+		m3 := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID:  c.ID,
+			LabelIDs:     []int32{inbox.LabelID},
+			RecipientIDs: []int32{me.ID},
+			Subject:      "Stratop",
+			Timestamp:    now,
+		})
+		// when
+		got, err := s.ListMailThreadsForLabelOrdered(ctx, c.ID, app.MailLabelInbox)
+		// then
+		if assert.NoError(t, err) {
+			if assert.Len(t, got, 2) {
+				assert.Equal(t, []int32{m3.MailID}, got[0].MailIDs())
+				assert.Equal(t, []int32{m1.MailID, m2.MailID}, got[1].MailIDs())
+			}
+		}
+	})
+}
@@ -0,0 +1,337 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/antihax/goesi/esi"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+)
+
+// Parameters for retrying to send queued mails.
+const (
+	mailOutboxRetryBackoff = 30 * time.Second
+	mailOutboxRetryMax     = time.Hour
+)
+
+// mailOutboxItem identifies a queued mail in the outbox.
+type mailOutboxItem struct {
+	characterID int32
+	draftID     int64
+}
+
+func (s *CharacterService) DeleteMailDraft(ctx context.Context, characterID int32, draftID int64) error {
+	return s.st.DeleteCharacterMailDraft(ctx, characterID, draftID)
+}
+
+func (s *CharacterService) GetMailDraft(ctx context.Context, characterID int32, draftID int64) (*app.CharacterMailDraft, error) {
+	return s.st.GetCharacterMailDraft(ctx, characterID, draftID)
+}
+
+// ListMailDrafts returns all drafts and queued mails of a character.
+func (s *CharacterService) ListMailDrafts(ctx context.Context, characterID int32) ([]*app.CharacterMailDraft, error) {
+	return s.st.ListCharacterMailDrafts(ctx, characterID)
+}
+
+// SaveMailDraft creates or updates a mail draft and returns it's ID.
+// Drafts with status queued are added to the outbox and will be sent by the outbox worker.
+// Drafts which are currently being sent can not be updated and return [app.ErrMailDraftSending].
+func (s *CharacterService) SaveMailDraft(ctx context.Context, d *app.CharacterMailDraft) (int64, error) {
+	if d.ID != 0 {
+		current, err := s.st.GetCharacterMailDraft(ctx, d.CharacterID, d.ID)
+		if err != nil {
+			return 0, err
+		}
+		if current.Status == app.MailDraftSending {
+			return 0, fmt.Errorf("mail draft %d: %w", d.ID, app.ErrMailDraftSending)
+		}
+	}
+	id, err := s.saveMailDraft(ctx, d)
+	if err != nil {
+		return 0, err
+	}
+	if d.Status == app.MailDraftQueued {
+		s.mailOutbox.Put(mailOutboxItem{characterID: d.CharacterID, draftID: id})
+	}
+	return id, nil
+}
+
+// saveMailDraft creates or updates a mail draft without adding it to the outbox and returns it's ID.
+func (s *CharacterService) saveMailDraft(ctx context.Context, d *app.CharacterMailDraft) (int64, error) {
+	arg := storage.UpdateOrCreateCharacterMailDraftParams{
+		Body:         d.Body,
+		CharacterID:  d.CharacterID,
+		QuotedBody:   d.QuotedBody,
+		RecipientIDs: d.RecipientIDs(),
+		Status:       d.Status,
+		Subject:      d.Subject,
+	}
+	if arg.Status == app.MailDraftUndefined {
+		arg.Status = app.MailDraftSaved
+	}
+	if d.ID == 0 {
+		return s.st.CreateCharacterMailDraft(ctx, arg)
+	}
+	if err := s.st.UpdateCharacterMailDraft(ctx, d.ID, arg); err != nil {
+		return 0, err
+	}
+	return d.ID, nil
+}
+
+// SendMailDraft tries to send a mail draft immediately and reports whether it was sent.
+// When ESI is not reachable the mail is queued in the outbox instead and will be sent later.
+// When it is unclear whether the mail was sent, it is kept as unconfirmed draft
+// and [app.ErrMailDraftUnconfirmed] is returned.
+// A sent mail is removed from the drafts.
+func (s *CharacterService) SendMailDraft(ctx context.Context, d *app.CharacterMailDraft) (bool, error) {
+	if d.ID != 0 {
+		// an existing draft might be sent by the outbox worker at the same time
+		previous, err := s.claimMailDraft(ctx, d.CharacterID, d.ID)
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			// return the draft if it was not processed, e.g. when the request was canceled
+			_, err := s.st.UpdateCharacterMailDraftStatusIf(context.WithoutCancel(ctx), d.ID, app.MailDraftSending, previous)
+			if err != nil {
+				slog.Error("Failed to release mail draft", "characterID", d.CharacterID, "draftID", d.ID, "error", err)
+			}
+		}()
+	}
+	body := d.BodyHTML()
+	mailID, r, err := s.postMailESI(ctx, d.CharacterID, d.Subject, d.Recipients, body)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false, err
+		}
+		arg := storage.UpdateCharacterMailDraftAttemptParams{
+			Attempts:  d.Attempts + 1,
+			LastError: err.Error(),
+		}
+		switch classifyMailSendError(r, err) {
+		case mailSendRetryable:
+			// the outbox worker will pick up the mail once it is due
+			retryAfter := mailOutboxRetryAfter(1)
+			arg.NextAttemptAt = time.Now().Add(retryAfter)
+			arg.Status = app.MailDraftQueued
+			slog.Info("Failed to send mail. Queuing it in outbox", "characterID", d.CharacterID, "retryAfter", retryAfter, "error", err)
+		case mailSendUnconfirmed:
+			arg.NextAttemptAt = time.Now()
+			arg.Status = app.MailDraftUnconfirmed
+			slog.Warn("Mail might have been sent. Keeping it as unconfirmed draft", "characterID", d.CharacterID, "error", err)
+		default:
+			return false, err
+		}
+		// the draft is saved first without being due and then updated with the attempt
+		d2 := *d
+		d2.Status = app.MailDraftSaved
+		id, err2 := s.saveMailDraft(ctx, &d2)
+		if err2 != nil {
+			return false, err2
+		}
+		arg.ID = id
+		if err2 := s.st.UpdateCharacterMailDraftAttempt(ctx, arg); err2 != nil {
+			return false, err2
+		}
+		if arg.Status == app.MailDraftUnconfirmed {
+			return false, fmt.Errorf("send mail: %w: %w", app.ErrMailDraftUnconfirmed, err)
+		}
+		return false, nil
+	}
+	if d.ID != 0 {
+		if err := s.st.DeleteCharacterMailDraft(ctx, d.CharacterID, d.ID); err != nil {
+			return true, err
+		}
+	}
+	if err := s.storeSentMail(ctx, d.CharacterID, mailID, d.Subject, d.Recipients, body); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// QueueDueMails adds all queued mails which are due to the outbox worker.
+// This should be called periodically while ESI is reachable.
+func (s *CharacterService) QueueDueMails(ctx context.Context) error {
+	drafts, err := s.st.ListCharacterMailDraftsDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, d := range drafts {
+		s.mailOutbox.Put(mailOutboxItem{characterID: d.CharacterID, draftID: d.ID})
+	}
+	return nil
+}
+
+// RunMailOutbox runs the worker which sends queued mails until the context is canceled.
+// Mails which failed with a temporary error, e.g. during an ESI outage, are retried later with an exponential backoff.
+// Mails which might have been sent, e.g. after a timeout, are not retried and need to be checked by the user.
+// onChanged is called for a character after one of it's queued mails was sent or has failed.
+func (s *CharacterService) RunMailOutbox(ctx context.Context, onChanged func(characterID int32)) {
+	// Mails which were being sent when the app was stopped might have been sent.
+	if err := s.st.UpdateCharacterMailDraftsStatus(ctx, app.MailDraftSending, app.MailDraftUnconfirmed); err != nil {
+		slog.Error("Mail outbox: failed to update interrupted mails", "error", err)
+	}
+	slog.Info("Mail outbox started")
+	for {
+		it, err := s.mailOutbox.Get(ctx)
+		if err != nil {
+			slog.Info("Mail outbox stopped")
+			return
+		}
+		hasChanged, err := s.sendQueuedMail(ctx, it.characterID, it.draftID)
+		if err != nil {
+			slog.Error("Mail outbox: failed to process mail", "characterID", it.characterID, "draftID", it.draftID, "error", err)
+		}
+		if hasChanged && onChanged != nil {
+			onChanged(it.characterID)
+		}
+	}
+}
+
+// sendQueuedMail tries to send a queued mail and reports whether it has changed.
+// The mail is claimed before sending, so that it can not be sent twice.
+func (s *CharacterService) sendQueuedMail(ctx context.Context, characterID int32, draftID int64) (bool, error) {
+	d, err := s.st.GetCharacterMailDraft(ctx, characterID, draftID)
+	if errors.Is(err, app.ErrNotFound) {
+		return false, nil // mail was already sent or deleted
+	} else if err != nil {
+		return false, err
+	}
+	if d.Status != app.MailDraftQueued || d.NextAttemptAt.After(time.Now()) {
+		return false, nil
+	}
+	ok, err := s.st.UpdateCharacterMailDraftStatusIf(ctx, d.ID, app.MailDraftQueued, app.MailDraftSending)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, nil // mail is being sent by someone else or was changed
+	}
+	defer func() {
+		// return the mail to the outbox if it was not processed, e.g. when the request was canceled
+		_, err := s.st.UpdateCharacterMailDraftStatusIf(context.WithoutCancel(ctx), d.ID, app.MailDraftSending, app.MailDraftQueued)
+		if err != nil {
+			slog.Error("Mail outbox: failed to release mail", "characterID", characterID, "draftID", d.ID, "error", err)
+		}
+	}()
+	body := d.BodyHTML()
+	mailID, r, err := s.postMailESI(ctx, characterID, d.Subject, d.Recipients, body)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false, err
+		}
+		attempts := d.Attempts + 1
+		arg := storage.UpdateCharacterMailDraftAttemptParams{
+			ID:        d.ID,
+			Attempts:  attempts,
+			LastError: err.Error(),
+		}
+		switch classifyMailSendError(r, err) {
+		case mailSendRetryable:
+			retryAfter := mailOutboxRetryAfter(attempts)
+			arg.NextAttemptAt = time.Now().Add(retryAfter)
+			arg.Status = app.MailDraftQueued
+			slog.Info("Failed to send queued mail. Will retry.", "characterID", characterID, "draftID", d.ID, "attempts", attempts, "retryAfter", retryAfter, "error", err)
+		case mailSendUnconfirmed:
+			arg.NextAttemptAt = time.Now()
+			arg.Status = app.MailDraftUnconfirmed
+			slog.Warn("Queued mail might have been sent. Not retrying", "characterID", characterID, "draftID", d.ID, "error", err)
+		default:
+			arg.NextAttemptAt = time.Now()
+			arg.Status = app.MailDraftFailed
+			slog.Warn("Failed to send queued mail", "characterID", characterID, "draftID", d.ID, "error", err)
+		}
+		if err := s.st.UpdateCharacterMailDraftAttempt(ctx, arg); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	// The mail has been sent at this point and must not be sent again.
+	if err := s.st.DeleteCharacterMailDraft(ctx, characterID, d.ID); err != nil {
+		return true, err
+	}
+	if err := s.storeSentMail(ctx, characterID, mailID, d.Subject, d.Recipients, body); err != nil {
+		return true, fmt.Errorf("store sent mail %d: %w", mailID, err)
+	}
+	return true, nil
+}
+
+// claimMailDraft marks an existing mail draft as being sent and returns it's previous status.
+// It returns [app.ErrMailDraftSending] when the draft is already being sent.
+func (s *CharacterService) claimMailDraft(ctx context.Context, characterID int32, draftID int64) (app.CharacterMailDraftStatus, error) {
+	d, err := s.st.GetCharacterMailDraft(ctx, characterID, draftID)
+	if errors.Is(err, app.ErrNotFound) {
+		return 0, fmt.Errorf("mail draft %d was already sent or deleted: %w", draftID, err)
+	} else if err != nil {
+		return 0, err
+	}
+	if d.Status == app.MailDraftSending {
+		return 0, fmt.Errorf("mail draft %d: %w", draftID, app.ErrMailDraftSending)
+	}
+	ok, err := s.st.UpdateCharacterMailDraftStatusIf(ctx, draftID, d.Status, app.MailDraftSending)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("mail draft %d: %w", draftID, app.ErrMailDraftSending)
+	}
+	return d.Status, nil
+}
+
+// mailOutboxRetryAfter returns how long to wait before the next attempt.
+func mailOutboxRetryAfter(attempts int) time.Duration {
+	d := mailOutboxRetryBackoff << (attempts - 1)
+	if d > mailOutboxRetryMax || d <= 0 {
+		d = mailOutboxRetryMax
+	}
+	return d
+}
+
+// mailSendFailure is the kind of failure when sending a mail.
+type mailSendFailure uint
+
+const (
+	mailSendFailed      mailSendFailure = iota // mail was not sent and retrying will not help
+	mailSendRetryable                          // mail was not sent and might be sent when retried later
+	mailSendUnconfirmed                        // mail might have been sent, so retrying could send it twice
+)
+
+// classifyMailSendError returns the kind of failure for an error from sending a mail to ESI.
+// r is the response from ESI, if there was one.
+func classifyMailSendError(r *http.Response, err error) mailSendFailure {
+	if errors.Is(err, app.ErrInvalid) {
+		return mailSendFailed
+	}
+	var swaggerErr esi.GenericSwaggerError
+	if errors.As(err, &swaggerErr) && r != nil {
+		switch c := r.StatusCode; {
+		case c == http.StatusGatewayTimeout:
+			return mailSendUnconfirmed // ESI might have processed the request
+		case c >= 500, c == 420, c == http.StatusTooManyRequests:
+			return mailSendRetryable
+		}
+		return mailSendFailed
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		var opErr *net.OpError
+		if errors.As(urlErr, &opErr) && opErr.Op == "dial" {
+			return mailSendRetryable // no connection to ESI
+		}
+		var dnsErr *net.DNSError
+		if errors.As(urlErr, &dnsErr) {
+			return mailSendRetryable
+		}
+		// The request might have reached ESI, e.g. when it timed out while waiting for a response.
+		return mailSendUnconfirmed
+	}
+	return mailSendFailed
+}
@@ -0,0 +1,488 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/antihax/goesi/esi"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestSaveMailDraft(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("should save new draft without queuing it", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		s := newCharacterService(st)
+		c := factory.CreateCharacter()
+		r := factory.CreateEveEntityCharacter()
+		d := &app.CharacterMailDraft{
+			Body:        "body",
+			CharacterID: c.ID,
+			Recipients:  []*app.EveEntity{r},
+			Subject:     "subject",
+		}
+		// when
+		id, err := s.SaveMailDraft(ctx, d)
+		// then
+		if assert.NoError(t, err) {
+			d2, err := s.GetMailDraft(ctx, c.ID, id)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MailDraftSaved, d2.Status)
+				assert.Equal(t, "body", d2.Body)
+			}
+			assert.True(t, s.mailOutbox.IsEmpty())
+		}
+	})
+	t.Run("should update existing draft and queue it", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		s := newCharacterService(st)
+		d := factory.CreateCharacterMailDraft()
+		d.Status = app.MailDraftQueued
+		d.Subject = "changed"
+		// when
+		id, err := s.SaveMailDraft(ctx, d)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, d.ID, id)
+			d2, err := s.GetMailDraft(ctx, d.CharacterID, id)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MailDraftQueued, d2.Status)
+				assert.Equal(t, "changed", d2.Subject)
+			}
+			it, err := s.mailOutbox.GetNoWait()
+			if assert.NoError(t, err) {
+				assert.Equal(t, mailOutboxItem{characterID: d.CharacterID, draftID: d.ID}, it)
+			}
+		}
+	})
+}
+
+func TestQueueDueMails(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	// given
+	s := newCharacterService(st)
+	d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{Status: app.MailDraftQueued})
+	factory.CreateCharacterMailDraft()
+	// when
+	err := s.QueueDueMails(ctx)
+	// then
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, s.mailOutbox.Size())
+		it, err := s.mailOutbox.GetNoWait()
+		if assert.NoError(t, err) {
+			assert.Equal(t, d.ID, it.draftID)
+		}
+	}
+}
+
+func TestSendQueuedMail(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	s := newCharacterService(st)
+	t.Run("should send mail and remove it from outbox", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		factory.CreateEveEntityCharacter(app.EveEntity{ID: c.ID})
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			Body:        "body",
+			CharacterID: c.ID,
+			Status:      app.MailDraftQueued,
+		})
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			httpmock.NewJsonResponderOrPanic(201, 123))
+		// when
+		hasChanged, err := s.sendQueuedMail(ctx, c.ID, d.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, hasChanged)
+			_, err := st.GetCharacterMailDraft(ctx, c.ID, d.ID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+			m, err := st.GetCharacterMail(ctx, c.ID, 123)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "body", m.Body)
+				assert.Equal(t, d.Subject, m.Subject)
+			}
+		}
+	})
+	t.Run("should keep mail queued and retry later when ESI is not available", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			CharacterID: c.ID,
+			Status:      app.MailDraftQueued,
+		})
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			httpmock.NewJsonResponderOrPanic(503, map[string]any{"error": "service unavailable"}))
+		// when
+		hasChanged, err := s.sendQueuedMail(ctx, c.ID, d.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, hasChanged)
+			d2, err := st.GetCharacterMailDraft(ctx, c.ID, d.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MailDraftQueued, d2.Status)
+				assert.Equal(t, 1, d2.Attempts)
+				assert.NotEmpty(t, d2.LastError)
+				assert.WithinDuration(t, time.Now().Add(mailOutboxRetryBackoff), d2.NextAttemptAt, 5*time.Second)
+			}
+		}
+	})
+	t.Run("should mark mail as failed when error is permanent", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			CharacterID: c.ID,
+			Status:      app.MailDraftQueued,
+		})
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			httpmock.NewJsonResponderOrPanic(400, map[string]any{"error": "bad request"}))
+		// when
+		hasChanged, err := s.sendQueuedMail(ctx, c.ID, d.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, hasChanged)
+			d2, err := st.GetCharacterMailDraft(ctx, c.ID, d.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MailDraftFailed, d2.Status)
+				assert.Equal(t, 1, d2.Attempts)
+			}
+		}
+	})
+	t.Run("should mark mail as unconfirmed when request timed out", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			CharacterID: c.ID,
+			Status:      app.MailDraftQueued,
+		})
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			httpmock.NewErrorResponder(os.ErrDeadlineExceeded))
+		// when
+		hasChanged, err := s.sendQueuedMail(ctx, c.ID, d.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, hasChanged)
+			d2, err := st.GetCharacterMailDraft(ctx, c.ID, d.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MailDraftUnconfirmed, d2.Status)
+				assert.Equal(t, 1, d2.Attempts)
+			}
+			dd, err := st.ListCharacterMailDraftsDue(ctx, time.Now().Add(24*time.Hour))
+			if assert.NoError(t, err) {
+				assert.Len(t, dd, 0)
+			}
+		}
+	})
+	t.Run("should ignore drafts which are not queued", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		d := factory.CreateCharacterMailDraft()
+		// when
+		hasChanged, err := s.sendQueuedMail(ctx, d.CharacterID, d.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.False(t, hasChanged)
+			assert.Equal(t, 0, httpmock.GetTotalCallCount())
+		}
+	})
+	t.Run("should ignore mails which are already being sent", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			Status: app.MailDraftQueued,
+		})
+		ok, err := st.UpdateCharacterMailDraftStatusIf(ctx, d.ID, app.MailDraftQueued, app.MailDraftSending)
+		if !assert.NoError(t, err) || !assert.True(t, ok) {
+			t.FailNow()
+		}
+		// when
+		hasChanged, err := s.sendQueuedMail(ctx, d.CharacterID, d.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.False(t, hasChanged)
+			assert.Equal(t, 0, httpmock.GetTotalCallCount())
+		}
+	})
+}
+
+func TestRunMailOutbox(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	t.Run("should mark mails which were interrupted while sending as unconfirmed", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		s := newCharacterService(st)
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			Status: app.MailDraftSending,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		// when
+		s.RunMailOutbox(ctx, nil)
+		// then
+		d2, err := st.GetCharacterMailDraft(context.Background(), d.CharacterID, d.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, app.MailDraftUnconfirmed, d2.Status)
+		}
+	})
+}
+
+func TestMailOutboxRetryAfter(t *testing.T) {
+	assert.Equal(t, mailOutboxRetryBackoff, mailOutboxRetryAfter(1))
+	assert.Equal(t, 2*mailOutboxRetryBackoff, mailOutboxRetryAfter(2))
+	assert.Equal(t, mailOutboxRetryMax, mailOutboxRetryAfter(100))
+}
+
+func TestClassifyMailSendError(t *testing.T) {
+	swaggerErr := esi.GenericSwaggerError{}
+	cases := []struct {
+		name       string
+		statusCode int
+		err        error
+		want       mailSendFailure
+	}{
+		{"invalid", 0, fmt.Errorf("missing body: %w", app.ErrInvalid), mailSendFailed},
+		{"bad request", http.StatusBadRequest, swaggerErr, mailSendFailed},
+		{"service unavailable", http.StatusServiceUnavailable, swaggerErr, mailSendRetryable},
+		{"error limited", 420, swaggerErr, mailSendRetryable},
+		{"rate limited", http.StatusTooManyRequests, swaggerErr, mailSendRetryable},
+		{"gateway timeout", http.StatusGatewayTimeout, swaggerErr, mailSendUnconfirmed},
+		{"connection refused", 0, &url.Error{Op: "Post", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, mailSendRetryable},
+		{"host not found", 0, &url.Error{Op: "Post", Err: &net.DNSError{Err: "no such host"}}, mailSendRetryable},
+		{"timeout", 0, &url.Error{Op: "Post", Err: os.ErrDeadlineExceeded}, mailSendUnconfirmed},
+		{"connection reset", 0, &url.Error{Op: "Post", Err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}}, mailSendUnconfirmed},
+		{"other", 0, errors.New("other"), mailSendFailed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var r *http.Response
+			if tc.statusCode != 0 {
+				r = &http.Response{StatusCode: tc.statusCode}
+			}
+			assert.Equal(t, tc.want, classifyMailSendError(r, tc.err))
+		})
+	}
+}
+
+func TestSendMailDraft(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	t.Run("should send mail and delete draft", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		s := newCharacterService(st)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		factory.CreateEveEntityCharacter(app.EveEntity{ID: c.ID})
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			Body:        "line 1\nline 2",
+			CharacterID: c.ID,
+			QuotedBody:  "<br>quote",
+		})
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			httpmock.NewJsonResponderOrPanic(201, 123))
+		// when
+		isSent, err := s.SendMailDraft(ctx, d)
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, isSent)
+			_, err := st.GetCharacterMailDraft(ctx, c.ID, d.ID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+			m, err := st.GetCharacterMail(ctx, c.ID, 123)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "line 1<br>line 2<br>quote", m.Body)
+			}
+		}
+	})
+	t.Run("should queue mail when ESI is not available", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		s := newCharacterService(st)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		r := factory.CreateEveEntityCharacter()
+		d := &app.CharacterMailDraft{
+			Body:        "body",
+			CharacterID: c.ID,
+			Recipients:  []*app.EveEntity{r},
+			Subject:     "subject",
+		}
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			httpmock.NewJsonResponderOrPanic(503, map[string]any{"error": "service unavailable"}))
+		// when
+		isSent, err := s.SendMailDraft(ctx, d)
+		// then
+		if assert.NoError(t, err) {
+			assert.False(t, isSent)
+			dd, err := st.ListCharacterMailDrafts(ctx, c.ID)
+			if assert.NoError(t, err) {
+				if assert.Len(t, dd, 1) {
+					assert.Equal(t, app.MailDraftQueued, dd[0].Status)
+					assert.Equal(t, 1, dd[0].Attempts)
+					assert.NotEmpty(t, dd[0].LastError)
+					assert.WithinDuration(t, time.Now().Add(mailOutboxRetryBackoff), dd[0].NextAttemptAt, 5*time.Second)
+				}
+			}
+			assert.Equal(t, 0, s.mailOutbox.Size())
+			due, err := st.ListCharacterMailDraftsDue(ctx, time.Now())
+			if assert.NoError(t, err) {
+				assert.Len(t, due, 0)
+			}
+		}
+	})
+	t.Run("should keep mail as unconfirmed draft when request timed out", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		s := newCharacterService(st)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		r := factory.CreateEveEntityCharacter()
+		d := &app.CharacterMailDraft{
+			Body:        "body",
+			CharacterID: c.ID,
+			Recipients:  []*app.EveEntity{r},
+			Subject:     "subject",
+		}
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			httpmock.NewErrorResponder(os.ErrDeadlineExceeded))
+		// when
+		isSent, err := s.SendMailDraft(ctx, d)
+		// then
+		assert.ErrorIs(t, err, app.ErrMailDraftUnconfirmed)
+		assert.False(t, isSent)
+		dd, err := st.ListCharacterMailDrafts(ctx, c.ID)
+		if assert.NoError(t, err) {
+			if assert.Len(t, dd, 1) {
+				assert.Equal(t, app.MailDraftUnconfirmed, dd[0].Status)
+				assert.NotEmpty(t, dd[0].LastError)
+			}
+		}
+		assert.Equal(t, 0, s.mailOutbox.Size())
+	})
+	t.Run("should return error when error is permanent", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		s := newCharacterService(st)
+		c := factory.CreateCharacter()
+		d := &app.CharacterMailDraft{
+			Body:        "body",
+			CharacterID: c.ID,
+			Subject:     "subject",
+		}
+		// when
+		_, err := s.SendMailDraft(ctx, d)
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("should not send draft which is already being sent", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		s := newCharacterService(st)
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			Status: app.MailDraftSending,
+		})
+		// when
+		_, err := s.SendMailDraft(ctx, d)
+		// then
+		assert.ErrorIs(t, err, app.ErrMailDraftSending)
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+	t.Run("should release draft when sending was canceled", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		s := newCharacterService(st)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			CharacterID: c.ID,
+			Status:      app.MailDraftFailed,
+		})
+		ctx, cancel := context.WithCancel(ctx)
+		httpmock.RegisterResponder(
+			"POST",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			func(r *http.Request) (*http.Response, error) {
+				cancel()
+				return nil, context.Canceled
+			})
+		// when
+		_, err := s.SendMailDraft(ctx, d)
+		// then
+		assert.ErrorIs(t, err, context.Canceled)
+		d2, err := st.GetCharacterMailDraft(context.Background(), c.ID, d.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, app.MailDraftFailed, d2.Status)
+		}
+	})
+	t.Run("should not update draft which is being sent", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		s := newCharacterService(st)
+		d := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			Status: app.MailDraftSending,
+		})
+		d.Status = app.MailDraftQueued
+		// when
+		_, err := s.SaveMailDraft(ctx, d)
+		// then
+		assert.ErrorIs(t, err, app.ErrMailDraftSending)
+	})
+}
@@ -0,0 +1,144 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/mailarchive"
+)
+
+const (
+	mailExportDomain     = "eveonline.invalid"
+	mailExportMaxSubject = 40 // max characters of the subject in file names
+)
+
+// ExportMailsMbox writes the selected mails of a character as mbox archive to w
+// and returns the number of exported mails.
+func (s *CharacterService) ExportMailsMbox(ctx context.Context, arg app.MailExportParams, w io.Writer) (int, error) {
+	mm, err := s.listMailsForExport(ctx, arg)
+	if err != nil {
+		return 0, err
+	}
+	messages := make([]mailarchive.Message, len(mm))
+	for i, m := range mm {
+		messages[i] = mailToArchiveMessage(m, arg.KeepHTML)
+	}
+	if err := mailarchive.WriteMbox(w, messages); err != nil {
+		return 0, err
+	}
+	return len(messages), nil
+}
+
+// ExportMailsEML writes the selected mails of a character as individual EML files
+// and returns the number of exported mails.
+// Files are created with create, which receives a unique file name for each mail.
+func (s *CharacterService) ExportMailsEML(ctx context.Context, arg app.MailExportParams, create func(name string) (io.WriteCloser, error)) (int, error) {
+	mm, err := s.listMailsForExport(ctx, arg)
+	if err != nil {
+		return 0, err
+	}
+	for i, m := range mm {
+		err := func() error {
+			w, err := create(mailExportFileName(m))
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+			return mailToArchiveMessage(m, arg.KeepHTML).WriteEML(w)
+		}()
+		if err != nil {
+			return i, fmt.Errorf("export mail %d: %w", m.MailID, err)
+		}
+	}
+	return len(mm), nil
+}
+
+// listMailsForExport returns the selected mails of a character in ascending order by timestamp.
+func (s *CharacterService) listMailsForExport(ctx context.Context, arg app.MailExportParams) ([]*app.CharacterMail, error) {
+	var headers []*app.CharacterMailHeader
+	var err error
+	if arg.ListID != 0 {
+		headers, err = s.st.ListCharacterMailHeadersForListOrdered(ctx, arg.CharacterID, arg.ListID)
+	} else {
+		labelID := arg.LabelID
+		if labelID == 0 {
+			labelID = app.MailLabelAll
+		}
+		headers, err = s.st.ListCharacterMailHeadersForLabelOrdered(ctx, arg.CharacterID, labelID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	mm := make([]*app.CharacterMail, 0)
+	for i := len(headers) - 1; i >= 0; i-- {
+		h := headers[i]
+		if !arg.Since.IsZero() && h.Timestamp.Before(arg.Since) {
+			continue
+		}
+		if !arg.Until.IsZero() && h.Timestamp.After(arg.Until) {
+			continue
+		}
+		m, err := s.st.GetCharacterMail(ctx, arg.CharacterID, h.MailID)
+		if err != nil {
+			return nil, err
+		}
+		mm = append(mm, m)
+	}
+	return mm, nil
+}
+
+func mailToArchiveMessage(m *app.CharacterMail, keepHTML bool) mailarchive.Message {
+	x := mailarchive.Message{
+		ID:      fmt.Sprintf("%d@mail.%s", m.MailID, mailExportDomain),
+		Date:    m.Timestamp,
+		Subject: m.Subject,
+		IsHTML:  keepHTML,
+	}
+	if keepHTML {
+		x.Body = m.Body
+	} else {
+		x.Body = m.BodyPlain()
+	}
+	if m.From != nil {
+		x.From = eveEntityMailAddress(m.From)
+	}
+	for _, r := range m.Recipients {
+		if r == nil {
+			continue
+		}
+		x.To = append(x.To, eveEntityMailAddress(r))
+	}
+	return x
+}
+
+// eveEntityMailAddress returns a pseudo mail address for an entity.
+// Eve entities do not have real mail addresses,
+// so the ID and category are used to make them unique and recognizable.
+func eveEntityMailAddress(e *app.EveEntity) mail.Address {
+	category := strings.ReplaceAll(e.Category.String(), " ", "-")
+	return mail.Address{
+		Name:    e.Name,
+		Address: fmt.Sprintf("%d@%s.%s", e.ID, category, mailExportDomain),
+	}
+}
+
+var mailExportInvalidChars = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// mailExportFileName returns a unique file name for exporting a mail,
+// e.g. "20250314-183000_123_Stratop_tonight.eml".
+func mailExportFileName(m *app.CharacterMail) string {
+	subject := []rune(strings.Trim(mailExportInvalidChars.ReplaceAllString(m.Subject, "_"), "_"))
+	if len(subject) > mailExportMaxSubject {
+		subject = subject[:mailExportMaxSubject]
+	}
+	name := fmt.Sprintf("%s_%d", m.Timestamp.UTC().Format("20060102-150405"), m.MailID)
+	if len(subject) > 0 {
+		name += "_" + string(subject)
+	}
+	return name + ".eml"
+}
@@ -0,0 +1,143 @@
+package characterservice
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestExportMails(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	s := newCharacterService(st)
+	now := time.Now().UTC().Truncate(time.Second)
+	c := factory.CreateCharacter()
+	list := factory.CreateCharacterMailList(c.ID)
+	from := factory.CreateEveEntityCharacter(app.EveEntity{Name: "Bruce Wayne"})
+	m1 := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+		Body:        "first <b>mail</b>",
+		CharacterID: c.ID,
+		FromID:      from.ID,
+		Subject:     "Alpha",
+		Timestamp:   now.Add(-3 * time.Hour),
+	})
+	m2 := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+		CharacterID:  c.ID,
+		RecipientIDs: []int32{list.ID},
+		Subject:      "Bravo",
+		Timestamp:    now.Add(-2 * time.Hour),
+	})
+	m3 := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+		CharacterID: c.ID,
+		Subject:     "Charlie",
+		Timestamp:   now.Add(-1 * time.Hour),
+	})
+	factory.CreateCharacterMail()
+	t.Run("can export all mails of a character as mbox", func(t *testing.T) {
+		// given
+		var b bytes.Buffer
+		// when
+		n, err := s.ExportMailsMbox(ctx, app.MailExportParams{CharacterID: c.ID}, &b)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3, n)
+			got := b.String()
+			assert.Equal(t, 3, strings.Count("\n"+got, "\nFrom "))
+			i1 := strings.Index(got, "Subject: Alpha")
+			i2 := strings.Index(got, "Subject: Bravo")
+			i3 := strings.Index(got, "Subject: Charlie")
+			assert.True(t, i1 < i2 && i2 < i3, "mails should be in ascending order")
+			assert.Contains(t, got, "From: \"Bruce Wayne\" <")
+			assert.Contains(t, got, "first mail")
+			assert.Contains(t, got, "Content-Type: text/plain")
+		}
+	})
+	t.Run("can keep bodies as HTML", func(t *testing.T) {
+		// given
+		var b bytes.Buffer
+		// when
+		_, err := s.ExportMailsMbox(ctx, app.MailExportParams{CharacterID: c.ID, KeepHTML: true}, &b)
+		// then
+		if assert.NoError(t, err) {
+			got := b.String()
+			assert.Contains(t, got, "first <b>mail</b>")
+			assert.Contains(t, got, "Content-Type: text/html")
+		}
+	})
+	t.Run("can filter by mailing list", func(t *testing.T) {
+		// given
+		var b bytes.Buffer
+		// when
+		n, err := s.ExportMailsMbox(ctx, app.MailExportParams{CharacterID: c.ID, ListID: list.ID}, &b)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			assert.Contains(t, b.String(), "Subject: Bravo")
+		}
+	})
+	t.Run("can filter by date range", func(t *testing.T) {
+		// given
+		var b bytes.Buffer
+		arg := app.MailExportParams{
+			CharacterID: c.ID,
+			Since:       m2.Timestamp,
+			Until:       m2.Timestamp.Add(time.Minute),
+		}
+		// when
+		n, err := s.ExportMailsMbox(ctx, arg, &b)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, n)
+			assert.Contains(t, b.String(), "Subject: Bravo")
+		}
+	})
+	t.Run("can export mails as EML files", func(t *testing.T) {
+		// given
+		files := make(map[string]*bytes.Buffer)
+		create := func(name string) (io.WriteCloser, error) {
+			b := new(bytes.Buffer)
+			files[name] = b
+			return nopWriteCloser{b}, nil
+		}
+		// when
+		n, err := s.ExportMailsEML(ctx, app.MailExportParams{CharacterID: c.ID}, create)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3, n)
+			assert.Len(t, files, 3)
+			for _, m := range []*app.CharacterMail{m1, m2, m3} {
+				b, found := files[mailExportFileName(m)]
+				if assert.True(t, found) {
+					assert.Contains(t, b.String(), "Subject: "+m.Subject)
+				}
+			}
+		}
+	})
+}
+
+func TestMailExportFileName(t *testing.T) {
+	m := &app.CharacterMail{
+		MailID:    123,
+		Subject:   "Re: Stratop / tonight?",
+		Timestamp: time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC),
+	}
+	assert.Equal(t, "20250314-183000_123_Re_Stratop_tonight.eml", mailExportFileName(m))
+	m.Subject = "???"
+	assert.Equal(t, "20250314-183000_123.eml", mailExportFileName(m))
+}
@@ -0,0 +1,84 @@
+package characterservice
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+// CreateMailRule creates a new mail rule and returns it's ID.
+func (s *CharacterService) CreateMailRule(ctx context.Context, r *app.CharacterMailRule) (int64, error) {
+	return s.st.CreateCharacterMailRule(ctx, mailRuleParams(r))
+}
+
+func (s *CharacterService) DeleteMailRule(ctx context.Context, characterID int32, ruleID int64) error {
+	return s.st.DeleteCharacterMailRule(ctx, characterID, ruleID)
+}
+
+func (s *CharacterService) ListMailRules(ctx context.Context, characterID int32) ([]*app.CharacterMailRule, error) {
+	return s.st.ListCharacterMailRules(ctx, characterID)
+}
+
+func (s *CharacterService) UpdateMailRule(ctx context.Context, r *app.CharacterMailRule) error {
+	return s.st.UpdateCharacterMailRule(ctx, r.ID, mailRuleParams(r))
+}
+
+func mailRuleParams(r *app.CharacterMailRule) storage.UpdateOrCreateCharacterMailRuleParams {
+	return storage.UpdateOrCreateCharacterMailRuleParams{
+		CharacterID:          r.CharacterID,
+		IsEnabled:            r.IsEnabled,
+		Keyword:              r.Keyword,
+		LabelID:              r.LabelID,
+		MailListID:           r.MailListID,
+		MarkAsRead:           r.MarkAsRead,
+		Name:                 r.Name,
+		Sender:               r.Sender,
+		SuppressNotification: r.SuppressNotification,
+	}
+}
+
+// applyMailRules applies all matching rules to a new mail.
+//
+// Suppressing the notification only changes the local mail,
+// while labels and the read state are also updated on ESI.
+// Failing to update ESI is logged and does not prevent the mail from being stored.
+func (s *CharacterService) applyMailRules(ctx context.Context, characterID, mailID int32, rules []*app.CharacterMailRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	m, err := s.st.GetCharacterMail(ctx, characterID, mailID)
+	if err != nil {
+		return err
+	}
+	var markAsRead, suppressNotification bool
+	labelIDs := set.New[int32]()
+	for _, r := range rules {
+		if !r.Matches(m) {
+			continue
+		}
+		slog.Debug("Applying mail rule", "characterID", characterID, "mailID", mailID, "rule", r.Name)
+		if r.LabelID != 0 {
+			labelIDs.Add(r.LabelID)
+		}
+		markAsRead = markAsRead || r.MarkAsRead
+		suppressNotification = suppressNotification || r.SuppressNotification
+	}
+	if suppressNotification && !m.IsProcessed {
+		if err := s.st.UpdateCharacterMailSetProcessed(ctx, m.ID); err != nil {
+			return err
+		}
+	}
+	if !markAsRead && labelIDs.Size() == 0 {
+		return nil
+	}
+	err = s.updateMailContentsESI(ctx, characterID, []int32{mailID}, func(isRead bool, labels set.Set[int32]) (bool, set.Set[int32]) {
+		return isRead || markAsRead, labels.Union(labelIDs)
+	})
+	if err != nil {
+		slog.Warn("Failed to apply mail rules on ESI", "characterID", characterID, "mailID", mailID, "error", err)
+	}
+	return nil
+}
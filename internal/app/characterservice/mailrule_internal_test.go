@@ -0,0 +1,133 @@
+package characterservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestApplyMailRules(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	s := newCharacterService(st)
+	type mailContents struct {
+		Labels []int32 `json:"labels"`
+		Read   bool    `json:"read"`
+	}
+	t.Run("should apply label, mark as read and suppress notification", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		l1 := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID, LabelID: app.MailLabelInbox})
+		l2 := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		from := factory.CreateEveEntityCharacter(app.EveEntity{Name: "Bruce Wayne"})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			FromID:      from.ID,
+			LabelIDs:    []int32{l1.LabelID},
+		})
+		r1 := factory.CreateCharacterMailRule(storage.UpdateOrCreateCharacterMailRuleParams{
+			CharacterID: c.ID,
+			IsEnabled:   true,
+			LabelID:     l2.LabelID,
+			Sender:      "bruce wayne",
+		})
+		r2 := factory.CreateCharacterMailRule(storage.UpdateOrCreateCharacterMailRuleParams{
+			CharacterID:          c.ID,
+			IsEnabled:            true,
+			MarkAsRead:           true,
+			SuppressNotification: true,
+		})
+		sent := &mailContents{}
+		httpmock.RegisterResponder(
+			"PUT",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/%d/", c.ID, m.MailID),
+			func(req *http.Request) (*http.Response, error) {
+				if err := json.NewDecoder(req.Body).Decode(sent); err != nil {
+					return nil, err
+				}
+				return httpmock.NewStringResponse(204, ""), nil
+			})
+		// when
+		err := s.applyMailRules(ctx, c.ID, m.MailID, []*app.CharacterMailRule{r1, r2})
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, sent.Read)
+			assert.ElementsMatch(t, []int32{l1.LabelID, l2.LabelID}, sent.Labels)
+			m2, err := st.GetCharacterMail(ctx, c.ID, m.MailID)
+			if assert.NoError(t, err) {
+				assert.True(t, m2.IsRead)
+				assert.True(t, m2.IsProcessed)
+				assert.Len(t, m2.Labels, 2)
+			}
+		}
+	})
+	t.Run("should do nothing when no rule matches", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{CharacterID: c.ID})
+		r := factory.CreateCharacterMailRule(storage.UpdateOrCreateCharacterMailRuleParams{
+			CharacterID:          c.ID,
+			IsEnabled:            true,
+			Keyword:              "does not exist",
+			MarkAsRead:           true,
+			SuppressNotification: true,
+		})
+		// when
+		err := s.applyMailRules(ctx, c.ID, m.MailID, []*app.CharacterMailRule{r})
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, httpmock.GetTotalCallCount())
+			m2, err := st.GetCharacterMail(ctx, c.ID, m.MailID)
+			if assert.NoError(t, err) {
+				assert.False(t, m2.IsRead)
+				assert.False(t, m2.IsProcessed)
+			}
+		}
+	})
+	t.Run("should suppress notification even when ESI is not available", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{CharacterID: c.ID})
+		r := factory.CreateCharacterMailRule(storage.UpdateOrCreateCharacterMailRuleParams{
+			CharacterID:          c.ID,
+			IsEnabled:            true,
+			MarkAsRead:           true,
+			SuppressNotification: true,
+		})
+		httpmock.RegisterResponder(
+			"PUT",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/%d/", c.ID, m.MailID),
+			httpmock.NewStringResponder(503, ""))
+		// when
+		err := s.applyMailRules(ctx, c.ID, m.MailID, []*app.CharacterMailRule{r})
+		// then
+		if assert.NoError(t, err) {
+			m2, err := st.GetCharacterMail(ctx, c.ID, m.MailID)
+			if assert.NoError(t, err) {
+				assert.False(t, m2.IsRead)
+				assert.True(t, m2.IsProcessed)
+			}
+		}
+	})
+}
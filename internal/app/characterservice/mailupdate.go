@@ -2,6 +2,7 @@ package characterservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -196,20 +197,54 @@ func (s *CharacterService) determineMailIDs(ctx context.Context, characterID int
 
 func (s *CharacterService) resolveMailEntities(ctx context.Context, mm []esi.GetCharactersCharacterIdMail200Ok) error {
 	entityIDs := set.New[int32]()
+	mailListIDs := set.New[int32]()
 	for _, m := range mm {
 		entityIDs.Add(m.From)
 		for _, r := range m.Recipients {
-			entityIDs.Add(r.RecipientId)
+			if r.RecipientType == "mailing_list" {
+				mailListIDs.Add(r.RecipientId)
+			} else {
+				entityIDs.Add(r.RecipientId)
+			}
 		}
 	}
+	entityIDs = entityIDs.Difference(mailListIDs)
 	_, err := s.EveUniverseService.AddMissingEntities(ctx, entityIDs.ToSlice())
 	if err != nil {
 		return err
 	}
+	// Mailing lists can not be resolved by ESI
+	// and are only known by name when the character is subscribed to them.
+	for id := range mailListIDs.Values() {
+		if err := s.ensureMailListEntity(ctx, id); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// ensureMailListEntity makes sure an EveEntity for a mailing list exists and has the correct category.
+func (s *CharacterService) ensureMailListEntity(ctx context.Context, id int32) error {
+	name := "?"
+	o, err := s.st.GetEveEntity(ctx, id)
+	switch {
+	case errors.Is(err, app.ErrNotFound):
+	case err != nil:
+		return err
+	case o.Category == app.EveEntityMailList:
+		return nil
+	default:
+		name = o.Name
+	}
+	_, err = s.st.UpdateOrCreateEveEntity(ctx, id, name, app.EveEntityMailList)
+	return err
+}
+
 func (s *CharacterService) addNewMailsESI(ctx context.Context, characterID int32, headers []esi.GetCharactersCharacterIdMail200Ok) error {
+	rules, err := s.st.ListCharacterMailRules(ctx, characterID)
+	if err != nil {
+		return err
+	}
 	count := 0
 	g := new(errgroup.Group)
 	g.SetLimit(20)
@@ -217,7 +252,7 @@ func (s *CharacterService) addNewMailsESI(ctx context.Context, characterID int32
 		count++
 		mailID := h.MailId
 		g.Go(func() error {
-			err := s.fetchAndStoreMail(ctx, characterID, mailID)
+			err := s.fetchAndStoreMail(ctx, characterID, mailID, rules)
 			if err != nil {
 				return fmt.Errorf("fetch mail %d: %w", mailID, err)
 			}
@@ -231,7 +266,7 @@ func (s *CharacterService) addNewMailsESI(ctx context.Context, characterID int32
 	return nil
 }
 
-func (s *CharacterService) fetchAndStoreMail(ctx context.Context, characterID, mailID int32) error {
+func (s *CharacterService) fetchAndStoreMail(ctx context.Context, characterID, mailID int32, rules []*app.CharacterMailRule) error {
 	m, _, err := s.esiClient.ESI.MailApi.GetCharactersCharacterIdMailMailId(ctx, characterID, mailID, nil)
 	if err != nil {
 		return err
@@ -255,6 +290,12 @@ func (s *CharacterService) fetchAndStoreMail(ctx context.Context, characterID, m
 	if err != nil {
 		return err
 	}
+	if err := s.applyMailRules(ctx, characterID, mailID, rules); err != nil {
+		return err
+	}
+	if err := s.forwardMail(ctx, characterID, mailID); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -281,28 +322,5 @@ func (s *CharacterService) updateExistingMail(ctx context.Context, characterID i
 
 // UpdateMailRead updates an existing mail as read
 func (s *CharacterService) UpdateMailRead(ctx context.Context, characterID, mailID int32) error {
-	token, err := s.getValidCharacterToken(ctx, characterID)
-	if err != nil {
-		return err
-	}
-	ctx = contextWithESIToken(ctx, token.AccessToken)
-	m, err := s.st.GetCharacterMail(ctx, characterID, mailID)
-	if err != nil {
-		return err
-	}
-	labelIDs := make([]int32, len(m.Labels))
-	for i, l := range m.Labels {
-		labelIDs[i] = l.LabelID
-	}
-	contents := esi.PutCharactersCharacterIdMailMailIdContents{Read: true, Labels: labelIDs}
-	_, err = s.esiClient.ESI.MailApi.PutCharactersCharacterIdMailMailId(ctx, m.CharacterID, contents, m.MailID, nil)
-	if err != nil {
-		return err
-	}
-	m.IsRead = true
-	if err := s.st.UpdateCharacterMail(ctx, characterID, m.ID, m.IsRead, labelIDs); err != nil {
-		return err
-	}
-	return nil
-
+	return s.UpdateMailsRead(ctx, characterID, []int32{mailID}, true)
 }
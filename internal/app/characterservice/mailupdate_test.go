@@ -264,4 +264,66 @@ func TestUpdateMail(t *testing.T) {
 			}
 		}
 	})
+	t.Run("should store unknown mailing lists with correct category", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		c := factory.CreateCharacter()
+		factory.CreateCharacterToken(app.CharacterToken{CharacterID: c.ID})
+		e1 := factory.CreateEveEntityCharacter()
+		factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID, LabelID: 1})
+		mailListID := int32(145000001)
+		mailID := 7
+		recipients := []map[string]any{
+			{
+				"recipient_id":   mailListID,
+				"recipient_type": "mailing_list",
+			},
+		}
+		dataHeader := []map[string]any{
+			{
+				"from":       e1.ID,
+				"is_read":    true,
+				"labels":     []int32{1},
+				"mail_id":    mailID,
+				"recipients": recipients,
+				"subject":    "test",
+				"timestamp":  "2015-09-30T16:07:00Z",
+			},
+		}
+		httpmock.RegisterResponder(
+			"GET",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/", c.ID),
+			httpmock.NewJsonResponderOrPanic(200, dataHeader),
+		)
+		dataMail := map[string]any{
+			"body":       "blah blah blah",
+			"from":       e1.ID,
+			"labels":     []int32{1},
+			"read":       true,
+			"recipients": recipients,
+			"subject":    "test",
+			"timestamp":  "2015-09-30T16:07:00Z",
+		}
+		httpmock.RegisterResponder(
+			"GET",
+			fmt.Sprintf("https://esi.evetech.net/v1/characters/%d/mail/%d/", c.ID, mailID),
+			httpmock.NewJsonResponderOrPanic(200, dataMail),
+		)
+		// when
+		_, err := s.UpdateSectionIfNeeded(ctx, app.CharacterUpdateSectionParams{
+			CharacterID: c.ID,
+			Section:     app.SectionMails,
+		})
+		// then
+		if assert.NoError(t, err) {
+			m, err := s.GetMail(ctx, c.ID, int32(mailID))
+			if assert.NoError(t, err) {
+				if assert.Len(t, m.Recipients, 1) {
+					assert.Equal(t, mailListID, m.Recipients[0].ID)
+					assert.Equal(t, app.EveEntityMailList, m.Recipients[0].Category)
+				}
+			}
+		}
+	})
 }
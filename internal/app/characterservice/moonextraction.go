@@ -0,0 +1,160 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/antihax/goesi/esi"
+	"github.com/dustin/go-humanize"
+)
+
+func (s *CharacterService) ListMoonExtractions(ctx context.Context) ([]*app.MoonExtraction, error) {
+	return s.st.ListMoonExtractions(ctx)
+}
+
+// NotifyMoonExtractions sends a notification for every extraction which chunk has arrived.
+// Extractions are shared between characters of the same corporation,
+// so this needs to be called only once for all characters.
+// Concurrent calls are serialized, so that each chunk is notified only once.
+func (s *CharacterService) NotifyMoonExtractions(ctx context.Context, earliest time.Time, notify func(title, content string)) error {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	extractions, err := s.st.ListMoonExtractions(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, x := range extractions {
+		if !x.IsReady(now) || x.ChunkArrivalAt.Before(earliest) {
+			continue
+		}
+		if x.LastNotified.ValueOrZero().Equal(x.ChunkArrivalAt) {
+			continue
+		}
+		title := fmt.Sprintf("%s: Moon chunk has arrived", x.Corporation.Name)
+		content := fmt.Sprintf(
+			"The chunk for %s at %s is ready to be fractured. It will fracture automatically at %s.",
+			x.StructureName,
+			x.Moon.Name,
+			x.AutoFractureAt.Format(app.DateTimeFormat),
+		)
+		if v := x.EstimatedValue(); !v.IsEmpty() {
+			content += fmt.Sprintf(" Estimated value: %s ISK", humanize.Comma(int64(v.ValueOrZero())))
+		}
+		notify(title, content)
+		if err := s.st.UpdateMoonExtractionLastNotified(ctx, x.ID, x.ChunkArrivalAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateMoonExtractions updates the moon extractions of a character's corporation from new notifications.
+func (s *CharacterService) updateMoonExtractions(ctx context.Context, characterID int32, notifications []esi.GetCharactersCharacterIdNotifications200Ok) error {
+	var nn []esi.GetCharactersCharacterIdNotifications200Ok
+	for _, n := range notifications {
+		if evenotification.Type2group[evenotification.Type(n.Type_)] == app.GroupMoonMining {
+			nn = append(nn, n)
+		}
+	}
+	if len(nn) == 0 {
+		return nil
+	}
+	character, err := s.GetCharacter(ctx, characterID)
+	if err != nil {
+		return err
+	}
+	if character.EveCharacter == nil || character.EveCharacter.Corporation == nil {
+		return nil
+	}
+	corporationID := character.EveCharacter.Corporation.ID
+	slices.SortFunc(nn, func(a, b esi.GetCharactersCharacterIdNotifications200Ok) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	for _, n := range nn {
+		if err := s.updateMoonExtraction(ctx, corporationID, n); err != nil {
+			slog.Error("Failed to update moon extraction", "characterID", characterID, "notificationID", n.NotificationId, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *CharacterService) updateMoonExtraction(ctx context.Context, corporationID int32, n esi.GetCharactersCharacterIdNotifications200Ok) error {
+	type_ := evenotification.Type(n.Type_)
+	data, err := evenotification.ParseMoonMining(type_, n.Text)
+	if err != nil {
+		return err
+	}
+	if _, err := s.EveUniverseService.GetOrCreateMoonESI(ctx, data.MoonID); err != nil {
+		return err
+	}
+	for typeID := range data.OreVolumeByType {
+		if _, err := s.EveUniverseService.GetOrCreateTypeESI(ctx, typeID); err != nil {
+			return err
+		}
+	}
+	arg := storage.UpdateOrCreateMoonExtractionParams{
+		AutoFractureAt: data.AutoFractureAt,
+		ChunkArrivalAt: data.ChunkArrivalAt,
+		CorporationID:  corporationID,
+		MoonID:         data.MoonID,
+		Ores:           data.OreVolumeByType,
+		Status:         app.MoonExtractionStatusStarted,
+		StructureID:    data.StructureID,
+		StructureName:  data.StructureName,
+	}
+	switch type_ {
+	case evenotification.MoonminingExtractionStarted:
+		arg.StartedAt = optional.New(n.Timestamp)
+		_, err := s.st.UpdateOrCreateMoonExtraction(ctx, arg)
+		return err
+	case evenotification.MoonminingExtractionFinished:
+		// only needed when the start of an extraction is unknown
+		_, err := s.st.GetMoonExtraction(ctx, data.StructureID, data.AutoFractureAt)
+		if errors.Is(err, app.ErrNotFound) {
+			arg.ChunkArrivalAt = n.Timestamp
+			_, err = s.st.UpdateOrCreateMoonExtraction(ctx, arg)
+		}
+		return err
+	case evenotification.MoonminingAutomaticFracture, evenotification.MoonminingLaserFired:
+		x, err := s.latestMoonExtraction(ctx, data.StructureID, func(x *app.MoonExtraction) bool {
+			return !x.ChunkArrivalAt.After(n.Timestamp)
+		})
+		if err != nil || x == nil {
+			return err
+		}
+		return s.st.UpdateMoonExtractionStatus(ctx, x.ID, app.MoonExtractionStatusFractured)
+	case evenotification.MoonminingExtractionCancelled:
+		x, err := s.latestMoonExtraction(ctx, data.StructureID, func(x *app.MoonExtraction) bool {
+			return x.Status == app.MoonExtractionStatusStarted && !x.StartedAt.ValueOrZero().After(n.Timestamp)
+		})
+		if err != nil || x == nil {
+			return err
+		}
+		return s.st.UpdateMoonExtractionStatus(ctx, x.ID, app.MoonExtractionStatusCancelled)
+	}
+	return nil
+}
+
+// latestMoonExtraction returns the latest extraction of a structure matching a condition
+// or nil if none matches.
+func (s *CharacterService) latestMoonExtraction(ctx context.Context, structureID int64, match func(x *app.MoonExtraction) bool) (*app.MoonExtraction, error) {
+	oo, err := s.st.ListMoonExtractionsForStructure(ctx, structureID)
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range oo {
+		if match(x) {
+			return x, nil
+		}
+	}
+	return nil, nil
+}
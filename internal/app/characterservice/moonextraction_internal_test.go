@@ -0,0 +1,183 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/antihax/goesi/esi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestUpdateMoonExtractions(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	s := newCharacterService(st)
+	ctx := context.Background()
+	const structureID = 1_000_000_000_001
+	now := time.Now().UTC().Truncate(time.Second)
+	chunkArrivalAt := now.Add(6 * 24 * time.Hour)
+	autoFractureAt := chunkArrivalAt.Add(3 * time.Hour)
+	makeText := func(moonID, oreTypeID int32, extra string) string {
+		return fmt.Sprintf(
+			"moonID: %d\noreVolumeByType:\n  %d: 1000\nsolarSystemID: 30002537\nstructureID: %d\nstructureName: Dummy\nstructureTypeID: 35835\n%s",
+			moonID,
+			oreTypeID,
+			structureID,
+			extra,
+		)
+	}
+	makeStartedText := func(moonID, oreTypeID int32) string {
+		return makeText(moonID, oreTypeID, fmt.Sprintf(
+			"autoTime: %d\nreadyTime: %d\n", toLDAPTime(autoFractureAt), toLDAPTime(chunkArrivalAt),
+		))
+	}
+	t.Run("should create extraction when started", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		moon := factory.CreateEveMoon()
+		oreType := factory.CreateEveType()
+		nn := []esi.GetCharactersCharacterIdNotifications200Ok{{
+			NotificationId: 1,
+			Text:           makeStartedText(moon.ID, oreType.ID),
+			Timestamp:      now,
+			Type_:          string(evenotification.MoonminingExtractionStarted),
+		}}
+		// when
+		err := s.updateMoonExtractions(ctx, c.ID, nn)
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.GetMoonExtraction(ctx, structureID, autoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, chunkArrivalAt, x.ChunkArrivalAt)
+				assert.Equal(t, c.EveCharacter.Corporation.ID, x.Corporation.ID)
+				assert.Equal(t, moon.ID, x.Moon.ID)
+				assert.Equal(t, app.MoonExtractionStatusStarted, x.Status)
+				assert.Equal(t, now, x.StartedAt.ValueOrZero())
+				assert.Len(t, x.Ores, 1)
+			}
+		}
+	})
+	t.Run("should mark extraction as cancelled", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		moon := factory.CreateEveMoon()
+		oreType := factory.CreateEveType()
+		nn := []esi.GetCharactersCharacterIdNotifications200Ok{
+			{
+				NotificationId: 2,
+				Text:           makeText(moon.ID, oreType.ID, "cancelledBy: 1001\n"),
+				Timestamp:      now.Add(time.Hour),
+				Type_:          string(evenotification.MoonminingExtractionCancelled),
+			},
+			{
+				NotificationId: 1,
+				Text:           makeStartedText(moon.ID, oreType.ID),
+				Timestamp:      now,
+				Type_:          string(evenotification.MoonminingExtractionStarted),
+			},
+		}
+		// when
+		err := s.updateMoonExtractions(ctx, c.ID, nn)
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.GetMoonExtraction(ctx, structureID, autoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MoonExtractionStatusCancelled, x.Status)
+			}
+		}
+	})
+	t.Run("should mark extraction as fractured", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		x1 := factory.CreateMoonExtraction(storage.UpdateOrCreateMoonExtractionParams{
+			StructureID:    structureID,
+			ChunkArrivalAt: now.Add(-time.Hour),
+		})
+		oreType := factory.CreateEveType()
+		nn := []esi.GetCharactersCharacterIdNotifications200Ok{{
+			NotificationId: 3,
+			Text:           makeText(x1.Moon.ID, oreType.ID, "firedBy: 1001\n"),
+			Timestamp:      now,
+			Type_:          string(evenotification.MoonminingLaserFired),
+		}}
+		// when
+		err := s.updateMoonExtractions(ctx, c.ID, nn)
+		// then
+		if assert.NoError(t, err) {
+			x2, err := st.GetMoonExtraction(ctx, structureID, x1.AutoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MoonExtractionStatusFractured, x2.Status)
+			}
+		}
+	})
+	t.Run("should not reset fractured extraction when started is received again", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		moon := factory.CreateEveMoon()
+		oreType := factory.CreateEveType()
+		started := esi.GetCharactersCharacterIdNotifications200Ok{
+			NotificationId: 5,
+			Text:           makeStartedText(moon.ID, oreType.ID),
+			Timestamp:      now,
+			Type_:          string(evenotification.MoonminingExtractionStarted),
+		}
+		fractured := esi.GetCharactersCharacterIdNotifications200Ok{
+			NotificationId: 6,
+			Text:           makeText(moon.ID, oreType.ID, "firedBy: 1001\n"),
+			Timestamp:      chunkArrivalAt.Add(time.Hour),
+			Type_:          string(evenotification.MoonminingLaserFired),
+		}
+		for _, n := range []esi.GetCharactersCharacterIdNotifications200Ok{started, fractured} {
+			if err := s.updateMoonExtractions(ctx, c.ID, []esi.GetCharactersCharacterIdNotifications200Ok{n}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// when
+		err := s.updateMoonExtractions(ctx, c.ID, []esi.GetCharactersCharacterIdNotifications200Ok{started})
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.GetMoonExtraction(ctx, structureID, autoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MoonExtractionStatusFractured, x.Status)
+			}
+		}
+	})
+	t.Run("should create extraction from finished when start is unknown", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		moon := factory.CreateEveMoon()
+		oreType := factory.CreateEveType()
+		nn := []esi.GetCharactersCharacterIdNotifications200Ok{{
+			NotificationId: 4,
+			Text:           makeText(moon.ID, oreType.ID, fmt.Sprintf("autoTime: %d\n", toLDAPTime(autoFractureAt))),
+			Timestamp:      chunkArrivalAt,
+			Type_:          string(evenotification.MoonminingExtractionFinished),
+		}}
+		// when
+		err := s.updateMoonExtractions(ctx, c.ID, nn)
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.GetMoonExtraction(ctx, structureID, autoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, chunkArrivalAt, x.ChunkArrivalAt)
+				assert.True(t, x.StartedAt.IsEmpty())
+			}
+		}
+	})
+}
+
+func toLDAPTime(t time.Time) int64 {
+	return (t.Unix() + 11644473600) * 10000000
+}
@@ -0,0 +1,99 @@
+package characterservice_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyMoonExtractions(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	earliest := now.Add(-12 * time.Hour)
+	cases := []struct {
+		name           string
+		status         app.MoonExtractionStatus
+		chunkArrivalAt time.Time
+		lastNotified   bool
+		shouldNotify   bool
+	}{
+		{"notify arrived chunk", app.MoonExtractionStatusStarted, now.Add(-time.Hour), false, true},
+		{"don't notify chunk not yet arrived", app.MoonExtractionStatusStarted, now.Add(time.Hour), false, false},
+		{"don't notify old chunk", app.MoonExtractionStatusStarted, now.Add(-16 * time.Hour), false, false},
+		{"don't notify cancelled extraction", app.MoonExtractionStatusCancelled, now.Add(-time.Hour), false, false},
+		{"don't notify fractured extraction", app.MoonExtractionStatusFractured, now.Add(-time.Hour), false, false},
+		{"don't notify again", app.MoonExtractionStatusStarted, now.Add(-time.Hour), true, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			testutil.TruncateTables(db)
+			x := factory.CreateMoonExtraction(storage.UpdateOrCreateMoonExtractionParams{
+				ChunkArrivalAt: tc.chunkArrivalAt,
+				Status:         tc.status,
+			})
+			if tc.lastNotified {
+				err := st.UpdateMoonExtractionLastNotified(ctx, x.ID, x.ChunkArrivalAt)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			var sendCount int
+			// when
+			err := cs.NotifyMoonExtractions(ctx, earliest, func(title string, content string) {
+				sendCount++
+			})
+			// then
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.shouldNotify, sendCount == 1)
+			}
+			if tc.shouldNotify {
+				x2, err := st.GetMoonExtraction(ctx, x.StructureID, x.AutoFractureAt)
+				if assert.NoError(t, err) {
+					assert.Equal(t, optional.New(x.ChunkArrivalAt), x2.LastNotified)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyMoonExtractionsConcurrently(t *testing.T) {
+	// given
+	db, st, factory := testutil.New()
+	defer db.Close()
+	db.SetMaxOpenConns(1) // each connection would get its own in-memory DB
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	factory.CreateMoonExtraction(storage.UpdateOrCreateMoonExtractionParams{
+		ChunkArrivalAt: now.Add(-time.Hour),
+		Status:         app.MoonExtractionStatusStarted,
+	})
+	var sendCount atomic.Int32
+	var wg sync.WaitGroup
+	// when
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := cs.NotifyMoonExtractions(ctx, now.Add(-12*time.Hour), func(title string, content string) {
+				sendCount.Add(1)
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	// then
+	assert.Equal(t, int32(1), sendCount.Load())
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
@@ -30,9 +32,12 @@ func (s *CharacterService) CountNotifications(ctx context.Context, characterID i
 	return values, nil
 }
 
-// TODO: Add tests for NotifyCommunications
-
-func (cs *CharacterService) NotifyCommunications(ctx context.Context, characterID int32, earliest time.Time, typesEnabled set.Set[string], notify func(title, content string)) error {
+// NotifyCommunications sends notifications for new communications of a character.
+// How a communication is delivered is decided by the notification rules.
+// Communications received by several characters are only notified once.
+func (cs *CharacterService) NotifyCommunications(ctx context.Context, characterID int32, earliest time.Time, typesEnabled set.Set[string], notify func(title, content string, channels set.Set[app.NotificationChannel])) error {
+	cs.notifyMu.Lock()
+	defer cs.notifyMu.Unlock()
 	nn, err := cs.st.ListCharacterNotificationsUnprocessed(ctx, characterID, earliest)
 	if err != nil {
 		return err
@@ -40,17 +45,43 @@ func (cs *CharacterService) NotifyCommunications(ctx context.Context, characterI
 	if len(nn) == 0 {
 		return nil
 	}
-	characterName, err := cs.getCharacterName(ctx, characterID)
+	rules, err := cs.st.ListNotificationRules(ctx)
 	if err != nil {
 		return err
 	}
+	now := time.Now()
 	for _, n := range nn {
-		if !typesEnabled.Contains(n.Type) {
+		channels, deliver, handled := notificationDelivery(rules, n, typesEnabled, now)
+		if !handled {
 			continue
 		}
-		title := fmt.Sprintf("%s: New Communication from %s", characterName, n.Sender.Name)
-		content := n.Title.ValueOrZero()
-		notify(title, content)
+		if deliver {
+			duplicates, err := cs.listNotificationDuplicates(ctx, n)
+			if err != nil {
+				return err
+			}
+			characterIDs := set.New(n.CharacterID)
+			var isNotified bool
+			for _, d := range duplicates {
+				characterIDs.Add(d.CharacterID)
+				if !d.IsProcessed {
+					continue
+				}
+				// A duplicate was notified already when the rules delivered it.
+				if _, ok, _ := notificationDelivery(rules, d, typesEnabled, now); ok {
+					isNotified = true
+				}
+			}
+			if !isNotified {
+				names, err := cs.getCharacterNames(ctx, characterIDs)
+				if err != nil {
+					return err
+				}
+				title := fmt.Sprintf("%s: New Communication from %s", strings.Join(names, ", "), n.Sender.Name)
+				content := n.Title.ValueOrZero()
+				notify(title, content, channels)
+			}
+		}
 		if err := cs.st.UpdateCharacterNotificationSetProcessed(ctx, n.ID); err != nil {
 			return err
 		}
@@ -58,6 +89,46 @@ func (cs *CharacterService) NotifyCommunications(ctx context.Context, characterI
 	return nil
 }
 
+// listNotificationDuplicates returns the duplicates of a notification received by other characters.
+func (cs *CharacterService) listNotificationDuplicates(ctx context.Context, n *app.CharacterNotification) ([]*app.CharacterNotification, error) {
+	start := n.Timestamp.Add(-app.NotificationDuplicateWindow)
+	end := n.Timestamp.Add(app.NotificationDuplicateWindow)
+	nn, err := cs.st.ListCharacterNotificationsForTypeAndTimespan(ctx, n.Type, start, end)
+	if err != nil {
+		return nil, err
+	}
+	duplicates := make([]*app.CharacterNotification, 0)
+	for _, x := range nn {
+		if n.IsDuplicate(x) {
+			duplicates = append(duplicates, x)
+		}
+	}
+	return duplicates, nil
+}
+
+// getCharacterNames returns the names of characters in alphabetical order.
+func (cs *CharacterService) getCharacterNames(ctx context.Context, characterIDs set.Set[int32]) ([]string, error) {
+	names := make([]string, 0, characterIDs.Size())
+	for id := range characterIDs.Values() {
+		n, err := cs.getCharacterName(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func (s *CharacterService) GetNotification(ctx context.Context, characterID int32, notificationID int64) (*app.CharacterNotification, error) {
+	return s.st.GetCharacterNotification(ctx, characterID, notificationID)
+}
+
+// ListAllNotifications returns the notifications of all characters.
+func (s *CharacterService) ListAllNotifications(ctx context.Context) ([]*app.CharacterNotification, error) {
+	return s.st.ListAllCharacterNotifications(ctx)
+}
+
 func (s *CharacterService) ListNotificationsTypes(ctx context.Context, characterID int32, ng app.NotificationGroup) ([]*app.CharacterNotification, error) {
 	types := evenotification.GroupTypes[ng]
 	t2 := make([]string, len(types))
@@ -75,6 +146,12 @@ func (s *CharacterService) ListNotificationsUnread(ctx context.Context, characte
 	return s.st.ListCharacterNotificationsUnread(ctx, characterID)
 }
 
+// UpdateNotificationsSetProcessed marks notifications as processed,
+// so that no more desktop notifications are sent for them.
+func (s *CharacterService) UpdateNotificationsSetProcessed(ctx context.Context, ids set.Set[int64]) error {
+	return s.st.UpdateCharacterNotificationsSetProcessed(ctx, ids)
+}
+
 func (s *CharacterService) updateNotificationsESI(ctx context.Context, arg app.CharacterUpdateSectionParams) (bool, error) {
 	if arg.Section != app.SectionNotifications {
 		panic("called with wrong section")
@@ -151,6 +228,7 @@ func (s *CharacterService) updateNotificationsESI(ctx context.Context, arg app.C
 			if err != nil {
 				return err
 			}
+			newIDs := make([]int64, 0, len(newNotifs))
 			for _, n := range newNotifs {
 				title, body, err := s.EveNotificationService.RenderESI(ctx, n.Type_, n.Text, n.Timestamp)
 				if err != nil {
@@ -170,8 +248,25 @@ func (s *CharacterService) updateNotificationsESI(ctx context.Context, arg app.C
 				if err := s.st.CreateCharacterNotification(ctx, arg); err != nil {
 					return err
 				}
+				newIDs = append(newIDs, n.NotificationId)
 			}
 			slog.Info("Stored new notifications", "characterID", characterID, "entries", len(newNotifs))
+			s.processNewNotifications(ctx, characterID, newIDs, newNotifs)
 			return nil
 		})
 }
+
+// processNewNotifications runs all follow-up steps for newly stored notifications of a character.
+// The notifications are already stored and will not be new again on the next update,
+// so each step is run independently and failed steps are only logged.
+func (s *CharacterService) processNewNotifications(ctx context.Context, characterID int32, newIDs []int64, newNotifs []esi.GetCharactersCharacterIdNotifications200Ok) {
+	if err := s.forwardNotifications(ctx, characterID, newIDs); err != nil {
+		slog.Error("Failed to forward notifications", "characterID", characterID, "error", err)
+	}
+	if err := s.updateMoonExtractions(ctx, characterID, newNotifs); err != nil {
+		slog.Error("Failed to update moon extractions", "characterID", characterID, "error", err)
+	}
+	if err := s.updateStructureTimers(ctx, characterID, newNotifs); err != nil {
+		slog.Error("Failed to update structure timers", "characterID", characterID, "error", err)
+	}
+}
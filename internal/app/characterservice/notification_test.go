@@ -47,7 +47,7 @@ func TestNotifyCommunications(t *testing.T) {
 			})
 			var sendCount int
 			// when
-			err := cs.NotifyCommunications(ctx, n.CharacterID, earliest, typesEnabled, func(title string, content string) {
+			err := cs.NotifyCommunications(ctx, n.CharacterID, earliest, typesEnabled, func(title string, content string, channels set.Set[app.NotificationChannel]) {
 				sendCount++
 			})
 			// then
@@ -58,6 +58,192 @@ func TestNotifyCommunications(t *testing.T) {
 	}
 }
 
+func TestNotifyCommunicationsWithRules(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	earliest := now.Add(-12 * time.Hour)
+	typesEnabled := set.New(string(evenotification.StructureUnderAttack))
+	t.Run("should route to channels of matching rule", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		n := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Type:      string(evenotification.StructureUnderAttack),
+			Timestamp: now,
+			Title:     optional.New("title"),
+			Body:      optional.New("body"),
+		})
+		factory.CreateNotificationRule(storage.UpdateOrCreateNotificationRuleParams{
+			Action:       app.RuleActionRoute,
+			Channels:     set.New(app.ChannelNtfy),
+			CharacterIDs: set.New(n.CharacterID),
+			IsEnabled:    true,
+		})
+		var got set.Set[app.NotificationChannel]
+		var sendCount int
+		// when
+		err := cs.NotifyCommunications(ctx, n.CharacterID, earliest, typesEnabled, func(title, content string, channels set.Set[app.NotificationChannel]) {
+			sendCount++
+			got = channels
+		})
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, sendCount)
+			assert.Equal(t, set.New(app.ChannelNtfy), got)
+		}
+	})
+	t.Run("should suppress and mark as processed", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		n := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Type:      string(evenotification.StructureUnderAttack),
+			Timestamp: now,
+			Title:     optional.New("title"),
+			Body:      optional.New("body"),
+		})
+		factory.CreateNotificationRule(storage.UpdateOrCreateNotificationRuleParams{
+			Action:    app.RuleActionSuppress,
+			IsEnabled: true,
+		})
+		var sendCount int
+		// when
+		err := cs.NotifyCommunications(ctx, n.CharacterID, earliest, typesEnabled, func(title, content string, channels set.Set[app.NotificationChannel]) {
+			sendCount++
+		})
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, sendCount)
+			n2, err := st.GetCharacterNotification(ctx, n.CharacterID, n.NotificationID)
+			if assert.NoError(t, err) {
+				assert.True(t, n2.IsProcessed)
+			}
+		}
+	})
+	t.Run("should apply first matching rule only", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		n := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Type:      string(evenotification.SkyhookOnline),
+			Timestamp: now,
+			Title:     optional.New("title"),
+			Body:      optional.New("body"),
+		})
+		factory.CreateNotificationRule(storage.UpdateOrCreateNotificationRuleParams{
+			Action:    app.RuleActionSuppress,
+			IsEnabled: true,
+			Position:  2,
+		})
+		factory.CreateNotificationRule(storage.UpdateOrCreateNotificationRuleParams{
+			Action:    app.RuleActionEscalate,
+			IsEnabled: true,
+			Position:  1,
+		})
+		var got set.Set[app.NotificationChannel]
+		var sendCount int
+		// when
+		err := cs.NotifyCommunications(ctx, n.CharacterID, earliest, typesEnabled, func(title, content string, channels set.Set[app.NotificationChannel]) {
+			sendCount++
+			got = channels
+		})
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, sendCount)
+			assert.Equal(t, set.New(app.NotificationChannels()...), got)
+		}
+	})
+}
+
+func TestNotifyCommunicationsDuplicates(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	earliest := now.Add(-12 * time.Hour)
+	typesEnabled := set.New(string(evenotification.StructureUnderAttack))
+	createNotification := func(characterID int32, text string) *app.CharacterNotification {
+		return factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Body:        optional.New("body"),
+			CharacterID: characterID,
+			Text:        text,
+			Timestamp:   now,
+			Title:       optional.New("title"),
+			Type:        string(evenotification.StructureUnderAttack),
+		})
+	}
+	t.Run("should notify once for duplicates and list all characters", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c1 := factory.CreateCharacter()
+		c2 := factory.CreateCharacter()
+		createNotification(c1.ID, "structureID: 42\n")
+		n2 := createNotification(c2.ID, "structureID: 42\n")
+		var titles []string
+		notify := func(title, content string, channels set.Set[app.NotificationChannel]) {
+			titles = append(titles, title)
+		}
+		// when
+		err1 := cs.NotifyCommunications(ctx, c1.ID, earliest, typesEnabled, notify)
+		err2 := cs.NotifyCommunications(ctx, c2.ID, earliest, typesEnabled, notify)
+		// then
+		if assert.NoError(t, err1) && assert.NoError(t, err2) {
+			if assert.Len(t, titles, 1) {
+				assert.Contains(t, titles[0], c1.EveCharacter.Name)
+				assert.Contains(t, titles[0], c2.EveCharacter.Name)
+			}
+			x, err := st.GetCharacterNotification(ctx, c2.ID, n2.NotificationID)
+			if assert.NoError(t, err) {
+				assert.True(t, x.IsProcessed)
+			}
+		}
+	})
+	t.Run("should notify for each character when payload differs", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c1 := factory.CreateCharacter()
+		c2 := factory.CreateCharacter()
+		createNotification(c1.ID, "structureID: 42\n")
+		createNotification(c2.ID, "structureID: 7\n")
+		var sendCount int
+		notify := func(title, content string, channels set.Set[app.NotificationChannel]) {
+			sendCount++
+		}
+		// when
+		err1 := cs.NotifyCommunications(ctx, c1.ID, earliest, typesEnabled, notify)
+		err2 := cs.NotifyCommunications(ctx, c2.ID, earliest, typesEnabled, notify)
+		// then
+		if assert.NoError(t, err1) && assert.NoError(t, err2) {
+			assert.Equal(t, 2, sendCount)
+		}
+	})
+	t.Run("should notify when duplicate was suppressed by a rule", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c1 := factory.CreateCharacter()
+		c2 := factory.CreateCharacter()
+		createNotification(c1.ID, "structureID: 42\n")
+		createNotification(c2.ID, "structureID: 42\n")
+		factory.CreateNotificationRule(storage.UpdateOrCreateNotificationRuleParams{
+			Action:       app.RuleActionSuppress,
+			CharacterIDs: set.New(c1.ID),
+			IsEnabled:    true,
+		})
+		var sendCount int
+		notify := func(title, content string, channels set.Set[app.NotificationChannel]) {
+			sendCount++
+		}
+		// when
+		err1 := cs.NotifyCommunications(ctx, c1.ID, earliest, typesEnabled, notify)
+		err2 := cs.NotifyCommunications(ctx, c2.ID, earliest, typesEnabled, notify)
+		// then
+		if assert.NoError(t, err1) && assert.NoError(t, err2) {
+			assert.Equal(t, 1, sendCount)
+		}
+	})
+}
+
 func TestCountNotificatios(t *testing.T) {
 	db, st, factory := testutil.New()
 	defer db.Close()
@@ -0,0 +1,78 @@
+package characterservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+// CreateNotificationRule creates a new notification rule and returns it's ID.
+func (s *CharacterService) CreateNotificationRule(ctx context.Context, r *app.NotificationRule) (int64, error) {
+	return s.st.CreateNotificationRule(ctx, notificationRuleParams(r))
+}
+
+func (s *CharacterService) DeleteNotificationRule(ctx context.Context, id int64) error {
+	return s.st.DeleteNotificationRule(ctx, id)
+}
+
+func (s *CharacterService) ListNotificationRules(ctx context.Context) ([]*app.NotificationRule, error) {
+	return s.st.ListNotificationRules(ctx)
+}
+
+func (s *CharacterService) UpdateNotificationRule(ctx context.Context, r *app.NotificationRule) error {
+	return s.st.UpdateNotificationRule(ctx, r.ID, notificationRuleParams(r))
+}
+
+func notificationRuleParams(r *app.NotificationRule) storage.UpdateOrCreateNotificationRuleParams {
+	return storage.UpdateOrCreateNotificationRuleParams{
+		Action:            r.Action,
+		Channels:          r.Channels,
+		CharacterIDs:      r.CharacterIDs,
+		Groups:            r.Groups,
+		IsEnabled:         r.IsEnabled,
+		Keyword:           r.Keyword,
+		Name:              r.Name,
+		Position:          r.Position,
+		QuietHoursEnabled: r.QuietHoursEnabled,
+		QuietHoursEnd:     r.QuietHoursEnd,
+		QuietHoursStart:   r.QuietHoursStart,
+		Sender:            r.Sender,
+		Types:             r.Types,
+	}
+}
+
+// notificationDelivery decides how a communication is delivered.
+// It returns the channels for delivery and reports whether the communication was handled,
+// i.e. it was either delivered or suppressed by a rule.
+// An empty set of channels means the default channels.
+//
+// The first matching rule is applied. When no rule matches a communication is delivered
+// when it's type is enabled.
+// Critical types, e.g. StructureUnderAttack, are delivered during quiet hours.
+func notificationDelivery(rules []*app.NotificationRule, n *app.CharacterNotification, typesEnabled set.Set[string], now time.Time) (channels set.Set[app.NotificationChannel], deliver bool, handled bool) {
+	t := evenotification.Type(n.Type)
+	group := evenotification.Type2group[t]
+	for _, r := range rules {
+		if !r.Matches(n, group) {
+			continue
+		}
+		switch r.Action {
+		case app.RuleActionEscalate:
+			return set.New(app.NotificationChannels()...), true, true
+		case app.RuleActionRoute:
+			if r.IsQuiet(now) && !t.IsCritical() {
+				return channels, false, true
+			}
+			return r.Channels, true, true
+		}
+		return channels, false, true
+	}
+	if !typesEnabled.Contains(n.Type) {
+		return channels, false, false
+	}
+	return channels, true, true
+}
@@ -0,0 +1,47 @@
+package characterservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationDelivery(t *testing.T) {
+	night := time.Date(2025, 3, 14, 23, 0, 0, 0, time.Local)
+	day := time.Date(2025, 3, 14, 12, 0, 0, 0, time.Local)
+	typesEnabled := set.New(string(evenotification.StructureUnderAttack))
+	quietRule := &app.NotificationRule{
+		Action:            app.RuleActionRoute,
+		Channels:          set.New(app.ChannelDesktop),
+		IsEnabled:         true,
+		QuietHoursEnabled: true,
+		QuietHoursStart:   22 * 60,
+		QuietHoursEnd:     7 * 60,
+	}
+	cases := []struct {
+		name        string
+		rules       []*app.NotificationRule
+		typ         evenotification.Type
+		now         time.Time
+		wantDeliver bool
+		wantHandled bool
+	}{
+		{"no rules and type enabled", nil, evenotification.StructureUnderAttack, day, true, true},
+		{"no rules and type not enabled", nil, evenotification.SkyhookOnline, day, false, false},
+		{"route outside quiet hours", []*app.NotificationRule{quietRule}, evenotification.SkyhookOnline, day, true, true},
+		{"suppress during quiet hours", []*app.NotificationRule{quietRule}, evenotification.SkyhookOnline, night, false, true},
+		{"deliver critical during quiet hours", []*app.NotificationRule{quietRule}, evenotification.StructureUnderAttack, night, true, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &app.CharacterNotification{Type: string(tc.typ)}
+			_, deliver, handled := notificationDelivery(tc.rules, n, typesEnabled, tc.now)
+			assert.Equal(t, tc.wantDeliver, deliver)
+			assert.Equal(t, tc.wantHandled, handled)
+		})
+	}
+}
@@ -11,6 +11,11 @@ import (
 	esioptional "github.com/antihax/goesi/optional"
 )
 
+// SearchData returns mails, communications and contracts of all characters matching a query.
+func (s *CharacterService) SearchData(ctx context.Context, query string) ([]*app.DataSearchResult, error) {
+	return s.st.SearchData(ctx, query)
+}
+
 // SearchESI performs a name search for items on the ESI server
 // and returns the results by EveEntity category and sorted by name.
 // It also returns the total number of results.
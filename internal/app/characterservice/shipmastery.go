@@ -0,0 +1,70 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+// ListShipMasteryLevels returns the mastery levels reached by a character for each ship by type ID.
+// Ships without mastery definitions are not included.
+func (s *CharacterService) ListShipMasteryLevels(ctx context.Context, characterID int32) (map[int32]int, error) {
+	skills, err := s.st.ListEveShipMasterySkills(ctx)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.trainedSkillLevels(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int32]int)
+	for id, mastery := range app.NewEveShipMasteries(skills) {
+		m[id] = mastery.Level(levels)
+	}
+	return m, nil
+}
+
+// GetShipMastery returns the mastery level reached by a character for a ship
+// and the skills missing for the next level ordered by prerequisites.
+// Returns [app.ErrNotFound] when there are no mastery definitions for the ship.
+func (s *CharacterService) GetShipMastery(ctx context.Context, characterID, shipTypeID int32) (*app.CharacterShipMastery, error) {
+	skills, err := s.st.ListEveShipMasterySkillsForShip(ctx, shipTypeID)
+	if err != nil {
+		return nil, err
+	}
+	mastery, ok := app.NewEveShipMasteries(skills)[shipTypeID]
+	if !ok {
+		return nil, fmt.Errorf("get ship mastery for ship %d: %w", shipTypeID, app.ErrNotFound)
+	}
+	levels, err := s.trainedSkillLevels(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	m := &app.CharacterShipMastery{
+		CharacterID: characterID,
+		Level:       mastery.Level(levels),
+		Missing:     make([]app.MissingSkill, 0),
+		ShipTypeID:  shipTypeID,
+	}
+	if next := m.NextLevel(); next > 0 {
+		m.Missing, err = s.SortMissingSkills(ctx, app.CheckSkillRequirements(levels, mastery.RequiredSkills(next)))
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.TrainingTime, err = s.calcMissingSkillsTrainingTime(ctx, characterID, m.Missing)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// trainedSkillLevels returns the trained levels of all skills of a character by skill ID.
+func (s *CharacterService) trainedSkillLevels(ctx context.Context, characterID int32) (map[int32]int, error) {
+	c, err := s.CompareSkills(ctx, []int32{characterID})
+	if err != nil {
+		return nil, err
+	}
+	return c.Levels(characterID), nil
+}
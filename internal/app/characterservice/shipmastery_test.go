@@ -0,0 +1,74 @@
+package characterservice_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestShipMastery(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	category := factory.CreateEveCategory(storage.CreateEveCategoryParams{ID: app.EveCategorySkill, IsPublished: true})
+	group := factory.CreateEveGroup(storage.CreateEveGroupParams{CategoryID: category.ID, IsPublished: true})
+	skill1 := factory.CreateEveType(storage.CreateEveTypeParams{GroupID: group.ID, IsPublished: true, Name: "Alpha"})
+	skill2 := factory.CreateEveType(storage.CreateEveTypeParams{GroupID: group.ID, IsPublished: true, Name: "Bravo"})
+	factory.CreateEveDogmaAttribute(storage.CreateEveDogmaAttributeParams{ID: app.EveDogmaAttributePrimarySkillID})
+	factory.CreateEveTypeDogmaAttribute(storage.CreateEveTypeDogmaAttributeParams{
+		EveTypeID:        skill1.ID,
+		DogmaAttributeID: app.EveDogmaAttributePrimarySkillID,
+		Value:            float32(skill2.ID),
+	}) // Alpha requires Bravo
+	err := st.ReplaceEveShipMasteries(
+		ctx,
+		[]storage.CreateEveCertificateParams{{ID: 1, Name: "Navigation"}},
+		[]storage.CreateEveCertificateSkillParams{
+			{CertificateID: 1, Level: 1, SkillTypeID: skill1.ID, SkillLevel: 1},
+			{CertificateID: 1, Level: 2, SkillTypeID: skill1.ID, SkillLevel: 3},
+			{CertificateID: 1, Level: 2, SkillTypeID: skill2.ID, SkillLevel: 2},
+		},
+		[]storage.CreateEveShipMasteryParams{
+			{CertificateID: 1, Level: 1, ShipTypeID: 42},
+			{CertificateID: 1, Level: 2, ShipTypeID: 42},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := factory.CreateCharacter()
+	factory.CreateCharacterSkill(storage.UpdateOrCreateCharacterSkillParams{
+		CharacterID:       c.ID,
+		EveTypeID:         skill1.ID,
+		ActiveSkillLevel:  2,
+		TrainedSkillLevel: 2,
+	})
+	t.Run("can list mastery levels for ships", func(t *testing.T) {
+		got, err := cs.ListShipMasteryLevels(ctx, c.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[int32]int{42: 1}, got)
+		}
+	})
+	t.Run("can return mastery with missing skills for next level ordered by prerequisites", func(t *testing.T) {
+		got, err := cs.GetShipMastery(ctx, c.ID, 42)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, got.Level)
+			assert.Equal(t, 2, got.NextLevel())
+			want := []app.MissingSkill{
+				{SkillID: skill2.ID, SkillName: "Bravo", CurrentLevel: 0, RequiredLevel: 2},
+				{SkillID: skill1.ID, SkillName: "Alpha", CurrentLevel: 2, RequiredLevel: 3},
+			}
+			assert.Equal(t, want, got.Missing)
+		}
+	})
+	t.Run("should return not found when ship has no masteries", func(t *testing.T) {
+		_, err := cs.GetShipMastery(ctx, c.ID, 99)
+		assert.ErrorIs(t, err, app.ErrNotFound)
+	})
+}
@@ -2,7 +2,9 @@ package characterservice
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
@@ -11,6 +13,12 @@ import (
 	"github.com/antihax/goesi/esi"
 )
 
+// Parameters for recording skill point snapshots.
+const (
+	skillPointSnapshotInterval  = time.Hour
+	skillPointSnapshotRetention = 366 * 24 * time.Hour
+)
+
 func (s *CharacterService) GetSkill(ctx context.Context, characterID, typeID int32) (*app.CharacterSkill, error) {
 	return s.st.GetCharacterSkill(ctx, characterID, typeID)
 }
@@ -35,6 +43,9 @@ func (s *CharacterService) updateSkillsESI(ctx context.Context, arg app.Characte
 				return false, err
 			}
 			slog.Debug("Received character skills from ESI", "characterID", characterID, "items", len(skills.Skills))
+			if err := s.recordSkillPointSnapshot(ctx, characterID, int(skills.TotalSp), int(skills.UnallocatedSp)); err != nil {
+				return false, err
+			}
 			return skills, nil
 		},
 		func(ctx context.Context, characterID int32, data any) error {
@@ -77,3 +88,61 @@ func (s *CharacterService) updateSkillsESI(ctx context.Context, arg app.Characte
 			return nil
 		})
 }
+
+// recordSkillPointSnapshot records the current skill points of a character,
+// so that training progress and idle periods can be shown.
+// A new snapshot is skipped when nothing changed within the snapshot interval
+// and snapshots older than the retention period are removed.
+func (s *CharacterService) recordSkillPointSnapshot(ctx context.Context, characterID int32, totalSP, unallocatedSP int) error {
+	isTraining, err := s.isTraining(ctx, characterID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	latest, err := s.st.GetLatestCharacterSkillPointSnapshot(ctx, characterID)
+	if errors.Is(err, app.ErrNotFound) {
+		// no previous snapshot
+	} else if err != nil {
+		return err
+	} else if latest.TotalSP == totalSP &&
+		latest.UnallocatedSP == unallocatedSP &&
+		latest.IsTraining == isTraining &&
+		now.Sub(latest.RecordedAt) < skillPointSnapshotInterval {
+		return nil
+	}
+	arg := storage.CreateCharacterSkillPointSnapshotParams{
+		CharacterID:   characterID,
+		IsTraining:    isTraining,
+		RecordedAt:    now,
+		TotalSP:       totalSP,
+		UnallocatedSP: unallocatedSP,
+	}
+	if err := s.st.CreateCharacterSkillPointSnapshot(ctx, arg); err != nil {
+		return err
+	}
+	return s.st.DeleteCharacterSkillPointSnapshotsBefore(ctx, characterID, now.Add(-skillPointSnapshotRetention))
+}
+
+// isTraining reports whether the skill queue of a character is currently active
+// as calculated from the stored start and finish dates of the queue.
+// Returns an empty value when the skill queue has not been loaded yet.
+func (s *CharacterService) isTraining(ctx context.Context, characterID int32) (optional.Optional[bool], error) {
+	var z optional.Optional[bool]
+	_, err := s.st.GetCharacterSectionStatus(ctx, characterID, app.SectionSkillqueue)
+	if errors.Is(err, app.ErrNotFound) {
+		return z, nil
+	}
+	if err != nil {
+		return z, err
+	}
+	items, err := s.st.ListCharacterSkillqueueItems(ctx, characterID)
+	if err != nil {
+		return z, err
+	}
+	for _, item := range items {
+		if item.IsActive() {
+			return optional.New(true), nil
+		}
+	}
+	return optional.New(false), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
@@ -74,6 +75,12 @@ func TestUpdateCharacterSkillsESI(t *testing.T) {
 				assert.Equal(t, 20000, o2.SkillPointsInSkill)
 				assert.Equal(t, 2, o2.TrainedSkillLevel)
 			}
+			oo, err := st.ListCharacterSkillPointSnapshots(ctx, c.ID, time.Now().Add(-time.Hour))
+			if assert.NoError(t, err) {
+				if assert.Len(t, oo, 1) {
+					assert.Equal(t, 90000, oo[0].TotalSP)
+				}
+			}
 		}
 	})
 	t.Run("should delete skills not returned from ESI", func(t *testing.T) {
@@ -144,3 +151,171 @@ func TestUpdateCharacterSkillsESI(t *testing.T) {
 // 		}
 // 	})
 // }
+
+func TestIsTraining(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	s := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("should report unknown when skill queue was not loaded", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		got, err := s.isTraining(ctx, c.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, got.IsEmpty())
+		}
+	})
+	t.Run("should report training when skill queue is active", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterSectionStatus(testutil.CharacterSectionStatusParams{
+			CharacterID: c.ID,
+			Section:     app.SectionSkillqueue,
+		})
+		factory.CreateCharacterSkillqueueItem(storage.SkillqueueItemParams{
+			CharacterID: c.ID,
+			StartDate:   time.Now().Add(-time.Hour),
+			FinishDate:  time.Now().Add(48 * time.Hour),
+		})
+		// when
+		got, err := s.isTraining(ctx, c.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.True(t, got.ValueOrZero())
+		}
+	})
+	t.Run("should report not training when skill queue has finished", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterSectionStatus(testutil.CharacterSectionStatusParams{
+			CharacterID: c.ID,
+			Section:     app.SectionSkillqueue,
+		})
+		factory.CreateCharacterSkillqueueItem(storage.SkillqueueItemParams{
+			CharacterID: c.ID,
+			StartDate:   time.Now().Add(-48 * time.Hour),
+			FinishDate:  time.Now().Add(-time.Hour),
+		})
+		// when
+		got, err := s.isTraining(ctx, c.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, false, got.MustValue())
+		}
+	})
+}
+
+func TestRecordSkillPointSnapshot(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	s := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("should record snapshot when there is none", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		err := s.recordSkillPointSnapshot(ctx, c.ID, 1000, 10)
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListCharacterSkillPointSnapshots(ctx, c.ID, time.Now().Add(-time.Hour))
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 1)
+			}
+		}
+	})
+	t.Run("should skip snapshot when nothing changed recently", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		if err := st.CreateCharacterSkillPointSnapshot(ctx, storage.CreateCharacterSkillPointSnapshotParams{
+			CharacterID:   c.ID,
+			RecordedAt:    time.Now().Add(-10 * time.Minute),
+			TotalSP:       1000,
+			UnallocatedSP: 10,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := s.recordSkillPointSnapshot(ctx, c.ID, 1000, 10)
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListCharacterSkillPointSnapshots(ctx, c.ID, time.Now().Add(-time.Hour))
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 1)
+			}
+		}
+	})
+	t.Run("should record snapshot when skill points changed", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		if err := st.CreateCharacterSkillPointSnapshot(ctx, storage.CreateCharacterSkillPointSnapshotParams{
+			CharacterID:   c.ID,
+			RecordedAt:    time.Now().Add(-10 * time.Minute),
+			TotalSP:       1000,
+			UnallocatedSP: 10,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := s.recordSkillPointSnapshot(ctx, c.ID, 1100, 10)
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListCharacterSkillPointSnapshots(ctx, c.ID, time.Now().Add(-time.Hour))
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 2)
+			}
+		}
+	})
+	t.Run("should record snapshot when latest is older than interval", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		if err := st.CreateCharacterSkillPointSnapshot(ctx, storage.CreateCharacterSkillPointSnapshotParams{
+			CharacterID:   c.ID,
+			RecordedAt:    time.Now().Add(-2 * time.Hour),
+			TotalSP:       1000,
+			UnallocatedSP: 10,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := s.recordSkillPointSnapshot(ctx, c.ID, 1000, 10)
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListCharacterSkillPointSnapshots(ctx, c.ID, time.Now().Add(-3*time.Hour))
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 2)
+			}
+		}
+	})
+	t.Run("should remove snapshots older than retention period", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		if err := st.CreateCharacterSkillPointSnapshot(ctx, storage.CreateCharacterSkillPointSnapshotParams{
+			CharacterID: c.ID,
+			RecordedAt:  time.Now().Add(-skillPointSnapshotRetention - time.Hour),
+			TotalSP:     900,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := s.recordSkillPointSnapshot(ctx, c.ID, 1000, 10)
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListCharacterSkillPointSnapshots(ctx, c.ID, time.Now().Add(-2*skillPointSnapshotRetention))
+			if assert.NoError(t, err) {
+				if assert.Len(t, oo, 1) {
+					assert.Equal(t, 1000, oo[0].TotalSP)
+				}
+			}
+		}
+	})
+}
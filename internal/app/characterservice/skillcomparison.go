@@ -0,0 +1,165 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+// requiredSkillAttributes are the dogma attributes defining the required skills of a type
+// and their required levels.
+var requiredSkillAttributes = []struct {
+	skill int32
+	level int32
+}{
+	{app.EveDogmaAttributePrimarySkillID, app.EveDogmaAttributePrimarySkillLevel},
+	{app.EveDogmaAttributeSecondarySkillID, app.EveDogmaAttributeSecondarySkillLevel},
+	{app.EveDogmaAttributeTertiarySkillID, app.EveDogmaAttributeTertiarySkillLevel},
+	{app.EveDogmaAttributeQuaternarySkillID, app.EveDogmaAttributeQuaternarySkillLevel},
+	{app.EveDogmaAttributeQuinarySkillID, app.EveDogmaAttributeQuinarySkillLevel},
+	{app.EveDogmaAttributeSenarySkillID, app.EveDogmaAttributeSenarySkillLevel},
+}
+
+// CompareSkills returns a comparison of the trained skills of characters.
+func (s *CharacterService) CompareSkills(ctx context.Context, characterIDs []int32) (*app.SkillComparison, error) {
+	if len(characterIDs) == 0 {
+		return nil, fmt.Errorf("compare skills: no characters: %w", app.ErrInvalid)
+	}
+	groups, err := s.st.ListCharacterSkillGroupsProgress(ctx, characterIDs[0])
+	if err != nil {
+		return nil, err
+	}
+	c := &app.SkillComparison{
+		CharacterIDs: characterIDs,
+		Groups:       make([]app.SkillComparisonGroup, 0, len(groups)),
+	}
+	for _, g := range groups {
+		var skills []app.SkillComparisonSkill
+		for i, characterID := range characterIDs {
+			oo, err := s.st.ListCharacterSkillProgress(ctx, characterID, g.GroupID)
+			if err != nil {
+				return nil, err
+			}
+			if skills == nil {
+				skills = make([]app.SkillComparisonSkill, len(oo))
+				for j, o := range oo {
+					skills[j] = app.SkillComparisonSkill{
+						Levels:   make([]int, len(characterIDs)),
+						TypeID:   o.TypeID,
+						TypeName: o.TypeName,
+					}
+				}
+			}
+			for j, o := range oo {
+				skills[j].Levels[i] = o.TrainedSkillLevel
+			}
+		}
+		c.Groups = append(c.Groups, app.SkillComparisonGroup{
+			GroupID:   g.GroupID,
+			GroupName: g.GroupName,
+			Skills:    skills,
+		})
+	}
+	return c, nil
+}
+
+// ListRequiredSkills returns all skills required to use a type, including the prerequisites of those skills.
+// Prerequisites are listed before the skills which require them and each skill is listed only once
+// with the highest required level.
+// Types which are not yet known are fetched from ESI.
+func (s *CharacterService) ListRequiredSkills(ctx context.Context, typeID int32) ([]app.SkillRequirement, error) {
+	if _, err := s.EveUniverseService.GetOrCreateTypeESI(ctx, typeID); err != nil {
+		return nil, err
+	}
+	levels := make(map[int32]int)
+	names := make(map[int32]string)
+	order := make([]int32, 0)
+	var addRequirements func(typeID int32) error
+	addRequirements = func(typeID int32) error {
+		oo, err := s.st.ListEveTypeDogmaAttributesForType(ctx, typeID)
+		if err != nil {
+			return err
+		}
+		values := make(map[int32]float32)
+		for _, o := range oo {
+			values[o.DogmaAttribute.ID] = o.Value
+		}
+		for _, x := range requiredSkillAttributes {
+			v, ok := values[x.skill]
+			if !ok {
+				continue
+			}
+			level, ok := values[x.level]
+			if !ok {
+				continue
+			}
+			skillID := int32(v)
+			if _, ok := names[skillID]; !ok {
+				et, err := s.EveUniverseService.GetOrCreateTypeESI(ctx, skillID)
+				if err != nil {
+					return err
+				}
+				names[skillID] = et.Name
+				if err := addRequirements(skillID); err != nil {
+					return err
+				}
+				order = append(order, skillID)
+			}
+			levels[skillID] = max(levels[skillID], int(level))
+		}
+		return nil
+	}
+	if err := addRequirements(typeID); err != nil {
+		return nil, fmt.Errorf("list required skills for type %d: %w", typeID, err)
+	}
+	requirements := make([]app.SkillRequirement, len(order))
+	for i, id := range order {
+		requirements[i] = app.SkillRequirement{
+			Level:     levels[id],
+			SkillID:   id,
+			SkillName: names[id],
+		}
+	}
+	return requirements, nil
+}
+
+// SortMissingSkills returns the missing skills ordered so that each skill comes after the skills it requires,
+// e.g. for adding them to the skill queue.
+// The prerequisites are taken from the locally stored types.
+func (s *CharacterService) SortMissingSkills(ctx context.Context, missing []app.MissingSkill) ([]app.MissingSkill, error) {
+	prerequisites := make(map[int32][]int32)
+	var addPrerequisites func(skillID int32) error
+	addPrerequisites = func(skillID int32) error {
+		if _, ok := prerequisites[skillID]; ok {
+			return nil
+		}
+		oo, err := s.st.ListEveTypeDogmaAttributesForType(ctx, skillID)
+		if err != nil {
+			return err
+		}
+		values := make(map[int32]float32)
+		for _, o := range oo {
+			values[o.DogmaAttribute.ID] = o.Value
+		}
+		ids := make([]int32, 0)
+		for _, x := range requiredSkillAttributes {
+			if v, ok := values[x.skill]; ok {
+				ids = append(ids, int32(v))
+			}
+		}
+		prerequisites[skillID] = ids
+		for _, id := range ids {
+			if err := addPrerequisites(id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, m := range missing {
+		if err := addPrerequisites(m.SkillID); err != nil {
+			return nil, fmt.Errorf("sort missing skills: %w", err)
+		}
+	}
+	return app.SortMissingSkills(missing, prerequisites), nil
+}
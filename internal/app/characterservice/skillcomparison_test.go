@@ -0,0 +1,158 @@
+package characterservice_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestCompareSkills(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("can compare skills of characters", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		category := factory.CreateEveCategory(storage.CreateEveCategoryParams{ID: app.EveCategorySkill, IsPublished: true})
+		group := factory.CreateEveGroup(storage.CreateEveGroupParams{CategoryID: category.ID, IsPublished: true, Name: "Missiles"})
+		skill1 := factory.CreateEveType(storage.CreateEveTypeParams{GroupID: group.ID, IsPublished: true, Name: "Alpha"})
+		skill2 := factory.CreateEveType(storage.CreateEveTypeParams{GroupID: group.ID, IsPublished: true, Name: "Bravo"})
+		c1 := factory.CreateCharacter()
+		c2 := factory.CreateCharacter()
+		factory.CreateCharacterSkill(storage.UpdateOrCreateCharacterSkillParams{
+			CharacterID:       c1.ID,
+			EveTypeID:         skill1.ID,
+			ActiveSkillLevel:  3,
+			TrainedSkillLevel: 3,
+		})
+		factory.CreateCharacterSkill(storage.UpdateOrCreateCharacterSkillParams{
+			CharacterID:       c2.ID,
+			EveTypeID:         skill1.ID,
+			ActiveSkillLevel:  5,
+			TrainedSkillLevel: 5,
+		})
+		// when
+		got, err := cs.CompareSkills(ctx, []int32{c1.ID, c2.ID})
+		// then
+		if assert.NoError(t, err) {
+			want := []app.SkillComparisonGroup{{
+				GroupID:   group.ID,
+				GroupName: "Missiles",
+				Skills: []app.SkillComparisonSkill{
+					{TypeID: skill1.ID, TypeName: "Alpha", Levels: []int{3, 5}},
+					{TypeID: skill2.ID, TypeName: "Bravo", Levels: []int{0, 0}},
+				},
+			}}
+			assert.Equal(t, want, got.Groups)
+		}
+	})
+	t.Run("should return error when no characters given", func(t *testing.T) {
+		_, err := cs.CompareSkills(ctx, []int32{})
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+}
+
+func TestListRequiredSkills(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	for _, id := range []int32{
+		app.EveDogmaAttributePrimarySkillID,
+		app.EveDogmaAttributePrimarySkillLevel,
+		app.EveDogmaAttributeSecondarySkillID,
+		app.EveDogmaAttributeSecondarySkillLevel,
+	} {
+		factory.CreateEveDogmaAttribute(storage.CreateEveDogmaAttributeParams{ID: id})
+	}
+	addRequirement := func(typeID, skillID int32, level int, primary bool) {
+		var idAttribute, levelAttribute int32 = app.EveDogmaAttributePrimarySkillID, app.EveDogmaAttributePrimarySkillLevel
+		if !primary {
+			idAttribute, levelAttribute = app.EveDogmaAttributeSecondarySkillID, app.EveDogmaAttributeSecondarySkillLevel
+		}
+		factory.CreateEveTypeDogmaAttribute(storage.CreateEveTypeDogmaAttributeParams{
+			EveTypeID:        typeID,
+			DogmaAttributeID: idAttribute,
+			Value:            float32(skillID),
+		})
+		factory.CreateEveTypeDogmaAttribute(storage.CreateEveTypeDogmaAttributeParams{
+			EveTypeID:        typeID,
+			DogmaAttributeID: levelAttribute,
+			Value:            float32(level),
+		})
+	}
+	t.Run("can return required skills with prerequisites", func(t *testing.T) {
+		// given
+		launcher := factory.CreateEveType()
+		missiles := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Missiles"})
+		heavyMissiles := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Heavy Missiles"})
+		launcherOperation := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Launcher Operation"})
+		addRequirement(launcher.ID, heavyMissiles.ID, 5, true)
+		addRequirement(launcher.ID, missiles.ID, 4, false)
+		addRequirement(heavyMissiles.ID, missiles.ID, 3, true)
+		addRequirement(missiles.ID, launcherOperation.ID, 1, true)
+		// when
+		got, err := cs.ListRequiredSkills(ctx, launcher.ID)
+		// then
+		if assert.NoError(t, err) {
+			want := []app.SkillRequirement{
+				{SkillID: launcherOperation.ID, SkillName: "Launcher Operation", Level: 1},
+				{SkillID: missiles.ID, SkillName: "Missiles", Level: 4},
+				{SkillID: heavyMissiles.ID, SkillName: "Heavy Missiles", Level: 5},
+			}
+			assert.Equal(t, want, got)
+		}
+	})
+	t.Run("should return empty list when type has no requirements", func(t *testing.T) {
+		x := factory.CreateEveType()
+		got, err := cs.ListRequiredSkills(ctx, x.ID)
+		if assert.NoError(t, err) {
+			assert.Empty(t, got)
+		}
+	})
+}
+
+func TestSortMissingSkills(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("should order missing skills after their prerequisites", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		factory.CreateEveDogmaAttribute(storage.CreateEveDogmaAttributeParams{ID: app.EveDogmaAttributePrimarySkillID})
+		launcherOperation := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Launcher Operation"})
+		missiles := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Missiles"})
+		heavyMissiles := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Heavy Missiles"})
+		drones := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Drones"})
+		for _, x := range [][2]int32{{heavyMissiles.ID, missiles.ID}, {missiles.ID, launcherOperation.ID}} {
+			factory.CreateEveTypeDogmaAttribute(storage.CreateEveTypeDogmaAttributeParams{
+				EveTypeID:        x[0],
+				DogmaAttributeID: app.EveDogmaAttributePrimarySkillID,
+				Value:            float32(x[1]),
+			})
+		}
+		missing := []app.MissingSkill{
+			{SkillID: drones.ID, SkillName: "Drones", RequiredLevel: 1},
+			{SkillID: heavyMissiles.ID, SkillName: "Heavy Missiles", RequiredLevel: 5},
+			{SkillID: launcherOperation.ID, SkillName: "Launcher Operation", RequiredLevel: 1},
+		}
+		// when
+		got, err := cs.SortMissingSkills(ctx, missing)
+		// then
+		if assert.NoError(t, err) {
+			want := []app.MissingSkill{
+				{SkillID: drones.ID, SkillName: "Drones", RequiredLevel: 1},
+				{SkillID: launcherOperation.ID, SkillName: "Launcher Operation", RequiredLevel: 1},
+				{SkillID: heavyMissiles.ID, SkillName: "Heavy Missiles", RequiredLevel: 5},
+			}
+			assert.Equal(t, want, got)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+// CalcSkillRequirementsSP returns how many skill points a character has trained
+// and is still missing for a set of skill requirements.
+// Skill points trained above the required levels are not counted.
+func (s *CharacterService) CalcSkillRequirementsSP(ctx context.Context, characterID int32, requirements []app.SkillRequirement) (app.SkillRequirementsSP, error) {
+	var r app.SkillRequirementsSP
+	for _, req := range requirements {
+		ts, err := s.GetTrainableSkill(ctx, req.SkillID)
+		if err != nil {
+			return r, err
+		}
+		var sp int // remains 0 when the skill has not been injected yet
+		skill, err := s.st.GetCharacterSkill(ctx, characterID, req.SkillID)
+		if err != nil && !errors.Is(err, app.ErrNotFound) {
+			return r, err
+		}
+		if err == nil {
+			sp = skill.SkillPointsInSkill
+		}
+		required := ts.SkillPoints(req.Level)
+		r.Trained += min(sp, required)
+		r.Missing += max(0, required-sp)
+	}
+	return r, nil
+}
@@ -0,0 +1,49 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+// GetSkillPointHistory returns the skill point history of a character since a point in time.
+func (s *CharacterService) GetSkillPointHistory(ctx context.Context, characterID int32, since time.Time) (*app.CharacterSkillPointHistory, error) {
+	snapshots, err := s.st.ListCharacterSkillPointSnapshots(ctx, characterID, since)
+	if err != nil {
+		return nil, err
+	}
+	h := &app.CharacterSkillPointHistory{
+		ActualRate:  app.CalcSkillPointRate(snapshots),
+		CharacterID: characterID,
+		Days:        app.SummarizeSkillPointDays(snapshots),
+	}
+	ta, err := s.GetTrainingAttributes(ctx, characterID)
+	if errors.Is(err, app.ErrNotFound) {
+		return h, nil // attributes have not been loaded yet
+	}
+	if err != nil {
+		return nil, err
+	}
+	h.PotentialRate = optional.New(ta.Current.MaxSkillPointsPerMinute() * 60)
+	items, err := s.st.ListCharacterSkillqueueItems(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if !item.IsActive() {
+			continue
+		}
+		ts, err := s.GetTrainableSkill(ctx, item.SkillID)
+		if err != nil && !errors.Is(err, app.ErrNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			h.TheoreticalRate = optional.New(ts.SkillPointsPerMinute(ta.Current) * 60)
+		}
+		break
+	}
+	return h, nil
+}
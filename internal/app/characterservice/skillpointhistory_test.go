@@ -0,0 +1,67 @@
+package characterservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestGetSkillPointHistory(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("should return history with rates", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterAttributes(storage.UpdateOrCreateCharacterAttributesParams{
+			CharacterID:  c.ID,
+			Charisma:     20,
+			Intelligence: 20,
+			Memory:       20,
+			Perception:   20,
+			Willpower:    20,
+		})
+		now := time.Now()
+		for _, x := range []struct {
+			hours int
+			sp    int
+		}{{-72, 1000}, {-48, 1000}, {-2, 2000}, {0, 6000}} {
+			err := st.CreateCharacterSkillPointSnapshot(ctx, storage.CreateCharacterSkillPointSnapshotParams{
+				CharacterID: c.ID,
+				RecordedAt:  now.Add(time.Duration(x.hours) * time.Hour),
+				TotalSP:     x.sp,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		// when
+		got, err := cs.GetSkillPointHistory(ctx, c.ID, now.Add(-30*24*time.Hour))
+		// then
+		if assert.NoError(t, err) {
+			assert.NotEmpty(t, got.Days)
+			assert.InDelta(t, 5000.0/72, got.ActualRate.ValueOrZero(), 0.1)
+			assert.InDelta(t, 1800, got.PotentialRate.ValueOrZero(), 0.1)
+			assert.True(t, got.TheoreticalRate.IsEmpty())
+		}
+	})
+	t.Run("should return history when attributes are unknown", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		got, err := cs.GetSkillPointHistory(ctx, c.ID, time.Now().Add(-30*24*time.Hour))
+		// then
+		if assert.NoError(t, err) {
+			assert.Empty(t, got.Days)
+			assert.True(t, got.PotentialRate.IsEmpty())
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+// GetTrainingAttributes returns the training attributes of a character
+// together with the bonuses from its currently plugged in implants.
+func (s *CharacterService) GetTrainingAttributes(ctx context.Context, characterID int32) (*app.CharacterTrainingAttributes, error) {
+	a, err := s.st.GetCharacterAttributes(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	implants, err := s.st.ListCharacterImplants(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+	var bonuses app.TrainingAttributes
+	for _, i := range implants {
+		oo, err := s.st.ListEveTypeDogmaAttributesForType(ctx, i.EveType.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range oo {
+			v := int(o.Value)
+			switch o.DogmaAttribute.ID {
+			case app.EveDogmaAttributeCharismaModifier:
+				bonuses.Charisma += v
+			case app.EveDogmaAttributeIntelligenceModifier:
+				bonuses.Intelligence += v
+			case app.EveDogmaAttributeMemoryModifier:
+				bonuses.Memory += v
+			case app.EveDogmaAttributePerceptionModifier:
+				bonuses.Perception += v
+			case app.EveDogmaAttributeWillpowerModifier:
+				bonuses.Willpower += v
+			}
+		}
+	}
+	ta := &app.CharacterTrainingAttributes{
+		CharacterID: characterID,
+		Current:     a.TrainingAttributes(),
+		Implants:    bonuses,
+	}
+	return ta, nil
+}
+
+// GetTrainableSkill returns a skill type with the properties needed for calculating its training time.
+// Returns [app.ErrNotFound] when the type is not a known skill.
+func (s *CharacterService) GetTrainableSkill(ctx context.Context, typeID int32) (*app.TrainableSkill, error) {
+	oo, err := s.st.ListEveTypeDogmaAttributesForType(ctx, typeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(oo) == 0 || oo[0].EveType.Group.Category.ID != app.EveCategorySkill {
+		return nil, fmt.Errorf("trainable skill %d: %w", typeID, app.ErrNotFound)
+	}
+	ts := app.TrainableSkill{
+		TypeID:   oo[0].EveType.ID,
+		TypeName: oo[0].EveType.Name,
+	}
+	for _, o := range oo {
+		switch o.DogmaAttribute.ID {
+		case app.EveDogmaAttributePrimaryAttribute:
+			ts.PrimaryAttribute = int32(o.Value)
+		case app.EveDogmaAttributeSecondaryAttribute:
+			ts.SecondaryAttribute = int32(o.Value)
+		case app.EveDogmaAttributeTrainingTimeMultiplier:
+			ts.Rank = int(o.Value)
+		}
+	}
+	if ts.Rank == 0 || ts.PrimaryAttribute == 0 || ts.SecondaryAttribute == 0 {
+		return nil, fmt.Errorf("trainable skill %d: %w", typeID, app.ErrNotFound)
+	}
+	return &ts, nil
+}
+
+// CalcSkillTrainingTime returns how long it takes a character with the given attributes
+// to train a skill from its currently trained skill points up to a level.
+func (s *CharacterService) CalcSkillTrainingTime(ctx context.Context, characterID, typeID int32, level int, attributes app.TrainingAttributes) (time.Duration, error) {
+	if level < 1 || level > app.SkillLevelMax {
+		return 0, fmt.Errorf("calc skill training time for level %d: %w", level, app.ErrInvalid)
+	}
+	ts, err := s.GetTrainableSkill(ctx, typeID)
+	if err != nil {
+		return 0, err
+	}
+	var sp int // remains 0 when the skill has not been injected yet
+	skill, err := s.st.GetCharacterSkill(ctx, characterID, typeID)
+	if err != nil && !errors.Is(err, app.ErrNotFound) {
+		return 0, err
+	}
+	if err == nil {
+		sp = skill.SkillPointsInSkill
+	}
+	return ts.TrainingTime(attributes, sp, level), nil
+}
+
+// CalcSkillqueueTrainingTime returns how long it would take a character with the given attributes
+// to train the remaining skills in its skill queue.
+func (s *CharacterService) CalcSkillqueueTrainingTime(ctx context.Context, characterID int32, attributes app.TrainingAttributes) (time.Duration, error) {
+	items, err := s.st.ListCharacterSkillqueueItems(ctx, characterID)
+	if err != nil {
+		return 0, err
+	}
+	skills := make(map[int32]*app.TrainableSkill)
+	var total time.Duration
+	for _, item := range items {
+		if item.IsCompleted() {
+			continue
+		}
+		ts, ok := skills[item.SkillID]
+		if !ok {
+			ts, err = s.GetTrainableSkill(ctx, item.SkillID)
+			if err != nil {
+				return 0, err
+			}
+			skills[item.SkillID] = ts
+		}
+		sp := max(item.TrainingStartSP, item.LevelStartSP)
+		if item.IsActive() {
+			sp = item.LevelStartSP + int(float64(item.LevelEndSP-item.LevelStartSP)*item.CompletionP())
+		}
+		total += ts.TrainingTime(attributes, sp, item.FinishedLevel)
+	}
+	return total, nil
+}
@@ -0,0 +1,206 @@
+package characterservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestSkillTraining(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	createAttribute := func(id int32) {
+		if _, err := st.GetEveDogmaAttribute(ctx, id); err != nil {
+			factory.CreateEveDogmaAttribute(storage.CreateEveDogmaAttributeParams{ID: id})
+		}
+	}
+	createSkill := func(rank int, primary, secondary int32) *app.EveType {
+		category, err := st.GetEveCategory(ctx, app.EveCategorySkill)
+		if err != nil {
+			category = factory.CreateEveCategory(storage.CreateEveCategoryParams{ID: app.EveCategorySkill})
+		}
+		group := factory.CreateEveGroup(storage.CreateEveGroupParams{CategoryID: category.ID})
+		skill := factory.CreateEveType(storage.CreateEveTypeParams{GroupID: group.ID})
+		for id, v := range map[int32]float32{
+			app.EveDogmaAttributePrimaryAttribute:       float32(primary),
+			app.EveDogmaAttributeSecondaryAttribute:     float32(secondary),
+			app.EveDogmaAttributeTrainingTimeMultiplier: float32(rank),
+		} {
+			createAttribute(id)
+			factory.CreateEveTypeDogmaAttribute(storage.CreateEveTypeDogmaAttributeParams{
+				EveTypeID:        skill.ID,
+				DogmaAttributeID: id,
+				Value:            v,
+			})
+		}
+		return skill
+	}
+	t.Run("can return training attributes with implant bonuses", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterAttributes(storage.UpdateOrCreateCharacterAttributesParams{
+			CharacterID:  c.ID,
+			Charisma:     20,
+			Intelligence: 27,
+			Memory:       25,
+			Perception:   20,
+			Willpower:    20,
+		})
+		implant := factory.CreateEveType()
+		createAttribute(app.EveDogmaAttributeIntelligenceModifier)
+		createAttribute(app.EveDogmaAttributeMemoryModifier)
+		factory.CreateEveTypeDogmaAttribute(storage.CreateEveTypeDogmaAttributeParams{
+			EveTypeID:        implant.ID,
+			DogmaAttributeID: app.EveDogmaAttributeIntelligenceModifier,
+			Value:            4,
+		})
+		implant2 := factory.CreateEveType()
+		factory.CreateEveTypeDogmaAttribute(storage.CreateEveTypeDogmaAttributeParams{
+			EveTypeID:        implant2.ID,
+			DogmaAttributeID: app.EveDogmaAttributeMemoryModifier,
+			Value:            5,
+		})
+		factory.CreateCharacterImplant(storage.CreateCharacterImplantParams{CharacterID: c.ID, EveTypeID: implant.ID})
+		factory.CreateCharacterImplant(storage.CreateCharacterImplantParams{CharacterID: c.ID, EveTypeID: implant2.ID})
+		// when
+		got, err := cs.GetTrainingAttributes(ctx, c.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, app.TrainingAttributes{Intelligence: 4, Memory: 5}, got.Implants)
+			assert.Equal(t, app.TrainingAttributes{Charisma: 20, Intelligence: 23, Memory: 20, Perception: 20, Willpower: 20}, got.Base())
+		}
+	})
+	t.Run("can return trainable skill", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		skill := createSkill(3, app.EveDogmaAttributeIntelligence, app.EveDogmaAttributeMemory)
+		// when
+		got, err := cs.GetTrainableSkill(ctx, skill.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3, got.Rank)
+			assert.Equal(t, int32(app.EveDogmaAttributeIntelligence), got.PrimaryAttribute)
+			assert.Equal(t, int32(app.EveDogmaAttributeMemory), got.SecondaryAttribute)
+			assert.Equal(t, skill.Name, got.TypeName)
+		}
+	})
+	t.Run("should return not found when type is not a skill", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x := factory.CreateEveType()
+		// when
+		_, err := cs.GetTrainableSkill(ctx, x.ID)
+		// then
+		assert.ErrorIs(t, err, app.ErrNotFound)
+	})
+	t.Run("can calculate training time for partially trained skill", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		skill := createSkill(1, app.EveDogmaAttributeIntelligence, app.EveDogmaAttributeMemory)
+		factory.CreateCharacterSkill(storage.UpdateOrCreateCharacterSkillParams{
+			CharacterID:        c.ID,
+			EveTypeID:          skill.ID,
+			SkillPointsInSkill: 250,
+			TrainedSkillLevel:  1,
+			ActiveSkillLevel:   1,
+		})
+		a := app.TrainingAttributes{Intelligence: 27, Memory: 21}
+		// when
+		got, err := cs.CalcSkillTrainingTime(ctx, c.ID, skill.ID, 3, a)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 206*time.Minute+40*time.Second, got)
+		}
+	})
+	t.Run("can calculate training time for skill not yet injected", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		skill := createSkill(1, app.EveDogmaAttributeIntelligence, app.EveDogmaAttributeMemory)
+		a := app.TrainingAttributes{Intelligence: 20, Memory: 10}
+		// when
+		got, err := cs.CalcSkillTrainingTime(ctx, c.ID, skill.ID, 1, a)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 10*time.Minute, got)
+		}
+	})
+	t.Run("should return error for invalid level", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		skill := createSkill(1, app.EveDogmaAttributeIntelligence, app.EveDogmaAttributeMemory)
+		// when
+		_, err := cs.CalcSkillTrainingTime(ctx, c.ID, skill.ID, 6, app.NewTrainingAttributes(20))
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("can calculate training time for skill queue", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		skill1 := createSkill(1, app.EveDogmaAttributeIntelligence, app.EveDogmaAttributeMemory)
+		skill2 := createSkill(2, app.EveDogmaAttributePerception, app.EveDogmaAttributeWillpower)
+		now := time.Now()
+		factory.CreateCharacterSkillqueueItem(storage.SkillqueueItemParams{
+			CharacterID:   c.ID,
+			EveTypeID:     skill1.ID,
+			FinishedLevel: 1,
+			LevelStartSP:  0,
+			LevelEndSP:    250,
+			StartDate:     now.Add(-2 * time.Hour),
+			FinishDate:    now.Add(-1 * time.Hour),
+		})
+		factory.CreateCharacterSkillqueueItem(storage.SkillqueueItemParams{
+			CharacterID:     c.ID,
+			EveTypeID:       skill2.ID,
+			FinishedLevel:   1,
+			LevelStartSP:    0,
+			LevelEndSP:      500,
+			TrainingStartSP: 100,
+			StartDate:       now.Add(time.Hour),
+			FinishDate:      now.Add(2 * time.Hour),
+		})
+		a := app.TrainingAttributes{Perception: 30, Willpower: 20}
+		// when
+		got, err := cs.CalcSkillqueueTrainingTime(ctx, c.ID, a)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 10*time.Minute, got)
+		}
+	})
+	t.Run("can calculate skill points for requirements", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		skill1 := createSkill(1, app.EveDogmaAttributeIntelligence, app.EveDogmaAttributeMemory)
+		skill2 := createSkill(2, app.EveDogmaAttributePerception, app.EveDogmaAttributeWillpower)
+		factory.CreateCharacterSkill(storage.UpdateOrCreateCharacterSkillParams{
+			CharacterID:        c.ID,
+			EveTypeID:          skill1.ID,
+			SkillPointsInSkill: 256000,
+			TrainedSkillLevel:  5,
+			ActiveSkillLevel:   5,
+		})
+		requirements := []app.SkillRequirement{
+			{SkillID: skill1.ID, Level: 3},
+			{SkillID: skill2.ID, Level: 1},
+		}
+		// when
+		got, err := cs.CalcSkillRequirementsSP(ctx, c.ID, requirements)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, app.SkillRequirementsSP{Missing: 500, Trained: 8000}, got)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	"github.com/antihax/goesi/esi"
+	"github.com/dustin/go-humanize"
+)
+
+// CreateStructureTimer creates a manual structure timer and returns it's ID.
+func (s *CharacterService) CreateStructureTimer(ctx context.Context, arg app.CreateStructureTimerParams) (int64, error) {
+	if _, err := s.EveUniverseService.GetOrCreateSolarSystemESI(ctx, arg.SolarSystemID); err != nil {
+		return 0, err
+	}
+	if _, err := s.EveUniverseService.GetOrCreateTypeESI(ctx, arg.StructureTypeID); err != nil {
+		return 0, err
+	}
+	if arg.OwnerID != 0 {
+		if _, err := s.EveUniverseService.GetOrCreateEntityESI(ctx, arg.OwnerID); err != nil {
+			return 0, err
+		}
+	}
+	return s.st.UpdateOrCreateStructureTimer(ctx, storage.UpdateOrCreateStructureTimerParams{
+		ExitsAt:         arg.ExitsAt,
+		IsManual:        true,
+		Notes:           arg.Notes,
+		OwnerID:         arg.OwnerID,
+		SolarSystemID:   arg.SolarSystemID,
+		StructureName:   arg.StructureName,
+		StructureTypeID: arg.StructureTypeID,
+		TimerType:       arg.TimerType,
+	})
+}
+
+func (s *CharacterService) DeleteStructureTimer(ctx context.Context, id int64) error {
+	return s.st.DeleteStructureTimer(ctx, id)
+}
+
+func (s *CharacterService) ListStructureTimers(ctx context.Context) ([]*app.StructureTimer, error) {
+	return s.st.ListStructureTimers(ctx)
+}
+
+// NotifyStructureTimers sends a reminder for every timer which exits within the lead time.
+// Timers are shared between characters, so this needs to be called only once for all characters.
+// Concurrent calls are serialized, so that each timer is notified only once.
+func (s *CharacterService) NotifyStructureTimers(ctx context.Context, leadTime time.Duration, notify func(title, content string)) error {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	timers, err := s.st.ListStructureTimers(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, x := range timers {
+		if x.IsExpired(now) || x.TimeUntilExit(now) > leadTime {
+			continue
+		}
+		if x.LastNotified.ValueOrZero().Equal(x.ExitsAt) {
+			continue
+		}
+		title := fmt.Sprintf("%s timer for %s in %s", x.TimerType.Display(), x.DisplayName(), x.SolarSystem.Name)
+		content := fmt.Sprintf(
+			"The %s timer for %s in %s exits %s at %s.",
+			x.TimerType.Display(),
+			x.DisplayName(),
+			x.SolarSystem.Name,
+			humanize.RelTime(x.ExitsAt, now, "ago", "from now"),
+			x.ExitsAt.Format(app.DateTimeFormat),
+		)
+		if x.Owner != nil {
+			content += fmt.Sprintf(" Owner: %s", x.Owner.Name)
+		}
+		notify(title, content)
+		if err := s.st.UpdateStructureTimerLastNotified(ctx, x.ID, x.ExitsAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStructureTimers creates structure timers from new notifications of a character.
+func (s *CharacterService) updateStructureTimers(ctx context.Context, characterID int32, notifications []esi.GetCharactersCharacterIdNotifications200Ok) error {
+	types := set.New(evenotification.StructureTimerTypes()...)
+	var nn []esi.GetCharactersCharacterIdNotifications200Ok
+	for _, n := range notifications {
+		if types.Contains(evenotification.Type(n.Type_)) {
+			nn = append(nn, n)
+		}
+	}
+	if len(nn) == 0 {
+		return nil
+	}
+	character, err := s.GetCharacter(ctx, characterID)
+	if err != nil {
+		return err
+	}
+	for _, n := range nn {
+		if err := s.updateStructureTimer(ctx, character, n); err != nil {
+			slog.Error("Failed to update structure timer", "characterID", characterID, "notificationID", n.NotificationId, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *CharacterService) updateStructureTimer(ctx context.Context, character *app.Character, n esi.GetCharactersCharacterIdNotifications200Ok) error {
+	type_ := evenotification.Type(n.Type_)
+	data, err := evenotification.ParseStructureTimer(type_, n.Text)
+	if err != nil {
+		return err
+	}
+	if _, err := s.EveUniverseService.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID); err != nil {
+		return err
+	}
+	structureType, err := s.EveUniverseService.GetOrCreateTypeESI(ctx, data.StructureTypeID)
+	if err != nil {
+		return err
+	}
+	arg := storage.UpdateOrCreateStructureTimerParams{
+		ExitsAt:         data.ExitsAt,
+		SolarSystemID:   data.SolarSystemID,
+		StructureID:     data.StructureID,
+		StructureTypeID: structureType.ID,
+		TimerType:       data.TimerType,
+	}
+	// timer notifications are only sent to the owner of a structure
+	if ec := character.EveCharacter; ec != nil {
+		if type_ == evenotification.SovStructureReinforced && ec.HasAlliance() {
+			arg.OwnerID = ec.Alliance.ID
+		} else if ec.Corporation != nil {
+			arg.OwnerID = ec.Corporation.ID
+		}
+	}
+	switch type_ {
+	case evenotification.OrbitalReinforced:
+		planet, err := s.EveUniverseService.GetOrCreatePlanetESI(ctx, int32(data.StructureID))
+		if err != nil {
+			return err
+		}
+		arg.StructureName = planet.Name
+	case evenotification.StructureLostArmor, evenotification.StructureLostShields:
+		structure, err := s.EveUniverseService.GetOrCreateLocationESI(ctx, data.StructureID)
+		if err != nil {
+			return err
+		}
+		if structure.Variant() == app.EveLocationStructure {
+			arg.StructureName = structure.DisplayName2()
+		}
+	}
+	_, err = s.st.UpdateOrCreateStructureTimer(ctx, arg)
+	return err
+}
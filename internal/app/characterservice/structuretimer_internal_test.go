@@ -0,0 +1,113 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/antihax/goesi/esi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+func TestUpdateStructureTimers(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	s := newCharacterService(st)
+	ctx := context.Background()
+	exitsAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	t.Run("should create timer from lost shields", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		system := factory.CreateEveSolarSystem()
+		structureType := factory.CreateEveType()
+		structure := factory.CreateEveLocationStructure(storage.UpdateOrCreateLocationParams{
+			EveSolarSystemID: optional.New(system.ID),
+			EveTypeID:        optional.New(structureType.ID),
+			Name:             system.Name + " - Alpha",
+		})
+		nn := []esi.GetCharactersCharacterIdNotifications200Ok{{
+			NotificationId: 1,
+			Text: fmt.Sprintf(
+				"solarsystemID: %d\nstructureID: %d\nstructureTypeID: %d\ntimeLeft: 1727805401093\ntimestamp: %d\nvulnerableTime: 9000000000\n",
+				system.ID,
+				structure.ID,
+				structureType.ID,
+				toLDAPTime(exitsAt),
+			),
+			Timestamp: time.Now(),
+			Type_:     string(evenotification.StructureLostShields),
+		}}
+		// when
+		err := s.updateStructureTimers(ctx, c.ID, nn)
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListStructureTimers(ctx)
+			if assert.NoError(t, err) && assert.Len(t, oo, 1) {
+				x := oo[0]
+				assert.Equal(t, exitsAt, x.ExitsAt)
+				assert.Equal(t, system.ID, x.SolarSystem.ID)
+				assert.Equal(t, structure.ID, x.StructureID)
+				assert.Equal(t, "Alpha", x.StructureName)
+				assert.Equal(t, structureType.ID, x.StructureType.ID)
+				assert.Equal(t, app.StructureTimerTypeArmor, x.TimerType)
+				assert.Equal(t, c.EveCharacter.Corporation.ID, x.Owner.ID)
+				assert.False(t, x.IsManual)
+			}
+		}
+	})
+	t.Run("should create timer for sov structure only once", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c1 := factory.CreateCharacter()
+		c2 := factory.CreateCharacter()
+		system := factory.CreateEveSolarSystem()
+		factory.CreateEveType(storage.CreateEveTypeParams{ID: app.EveTypeTCU})
+		nn := []esi.GetCharactersCharacterIdNotifications200Ok{{
+			NotificationId: 2,
+			Text:           fmt.Sprintf("campaignEventType: 1\ndecloakTime: %d\nsolarSystemID: %d\n", toLDAPTime(exitsAt), system.ID),
+			Timestamp:      time.Now(),
+			Type_:          string(evenotification.SovStructureReinforced),
+		}}
+		// when
+		err1 := s.updateStructureTimers(ctx, c1.ID, nn)
+		err2 := s.updateStructureTimers(ctx, c2.ID, nn)
+		// then
+		if assert.NoError(t, err1) && assert.NoError(t, err2) {
+			oo, err := st.ListStructureTimers(ctx)
+			if assert.NoError(t, err) && assert.Len(t, oo, 1) {
+				x := oo[0]
+				assert.Equal(t, exitsAt, x.ExitsAt)
+				assert.Equal(t, int32(app.EveTypeTCU), x.StructureType.ID)
+				assert.Equal(t, app.StructureTimerTypeSovereignty, x.TimerType)
+			}
+		}
+	})
+	t.Run("should ignore other notifications", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		nn := []esi.GetCharactersCharacterIdNotifications200Ok{{
+			NotificationId: 3,
+			Text:           "",
+			Timestamp:      time.Now(),
+			Type_:          string(evenotification.StructureDestroyed),
+		}}
+		// when
+		err := s.updateStructureTimers(ctx, c.ID, nn)
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListStructureTimers(ctx)
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 0)
+			}
+		}
+	})
+}
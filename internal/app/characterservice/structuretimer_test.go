@@ -0,0 +1,129 @@
+package characterservice_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyStructureTimers(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	leadTime := time.Hour
+	cases := []struct {
+		name         string
+		exitsAt      time.Time
+		lastNotified bool
+		shouldNotify bool
+	}{
+		{"notify timer within lead time", now.Add(30 * time.Minute), false, true},
+		{"don't notify timer outside lead time", now.Add(3 * time.Hour), false, false},
+		{"don't notify expired timer", now.Add(-time.Minute), false, false},
+		{"don't notify again", now.Add(30 * time.Minute), true, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			testutil.TruncateTables(db)
+			x := factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{
+				ExitsAt: tc.exitsAt,
+			})
+			if tc.lastNotified {
+				err := st.UpdateStructureTimerLastNotified(ctx, x.ID, x.ExitsAt)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			var sendCount int
+			// when
+			err := cs.NotifyStructureTimers(ctx, leadTime, func(title string, content string) {
+				sendCount++
+			})
+			// then
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.shouldNotify, sendCount == 1)
+			}
+			if tc.shouldNotify {
+				x2, err := st.GetStructureTimer(ctx, x.ID)
+				if assert.NoError(t, err) {
+					assert.Equal(t, optional.New(x.ExitsAt), x2.LastNotified)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyStructureTimersConcurrently(t *testing.T) {
+	// given
+	db, st, factory := testutil.New()
+	defer db.Close()
+	db.SetMaxOpenConns(1) // each connection would get its own in-memory DB
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{
+		ExitsAt: time.Now().UTC().Add(30 * time.Minute),
+	})
+	var sendCount atomic.Int32
+	var wg sync.WaitGroup
+	// when
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := cs.NotifyStructureTimers(ctx, time.Hour, func(title string, content string) {
+				sendCount.Add(1)
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	// then
+	assert.Equal(t, int32(1), sendCount.Load())
+}
+
+func TestCreateStructureTimer(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("can create manual timer", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		system := factory.CreateEveSolarSystem()
+		structureType := factory.CreateEveType()
+		owner := factory.CreateEveEntityAlliance()
+		exitsAt := time.Now().Add(5 * time.Hour).UTC().Truncate(time.Second)
+		// when
+		id, err := cs.CreateStructureTimer(ctx, app.CreateStructureTimerParams{
+			ExitsAt:         exitsAt,
+			Notes:           "Bring friends",
+			OwnerID:         owner.ID,
+			SolarSystemID:   system.ID,
+			StructureName:   "Alpha",
+			StructureTypeID: structureType.ID,
+			TimerType:       app.StructureTimerTypeHull,
+		})
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.GetStructureTimer(ctx, id)
+			if assert.NoError(t, err) {
+				assert.True(t, x.IsManual)
+				assert.Equal(t, exitsAt, x.ExitsAt)
+				assert.Equal(t, "Bring friends", x.Notes)
+				assert.Equal(t, owner, x.Owner)
+				assert.Equal(t, "Alpha", x.StructureName)
+			}
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/icalendar"
+)
+
+const timerEventUIDDomain = "evebuddy"
+
+// ListTimerEvents returns all upcoming time based events of all characters
+// including structure timers ordered by time.
+func (s *CharacterService) ListTimerEvents(ctx context.Context) ([]*app.TimerEvent, error) {
+	now := time.Now()
+	events := make([]*app.TimerEvent, 0)
+	cc, err := s.ListCharactersShort(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cc {
+		ee, err := s.listCharacterTimerEvents(ctx, c, now)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ee...)
+	}
+	timers, err := s.ListStructureTimers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range timers {
+		if x.IsExpired(now) {
+			continue
+		}
+		description := fmt.Sprintf("%s timer for %s (%s)", x.TimerType.Display(), x.DisplayName(), x.StructureType.Name)
+		if x.Owner != nil {
+			description += fmt.Sprintf("\nOwner: %s", x.Owner.Name)
+		}
+		if x.Notes != "" {
+			description += "\n" + x.Notes
+		}
+		events = append(events, &app.TimerEvent{
+			Description: description,
+			Kind:        app.TimerEventStructure,
+			Time:        x.ExitsAt,
+			Title:       fmt.Sprintf("%s timer: %s in %s", x.TimerType.Display(), x.DisplayName(), x.SolarSystem.Name),
+			UID:         makeTimerEventUID("structure-timer", x.ID),
+		})
+	}
+	slices.SortStableFunc(events, func(a, b *app.TimerEvent) int {
+		return a.Time.Compare(b.Time)
+	})
+	return events, nil
+}
+
+func (s *CharacterService) listCharacterTimerEvents(ctx context.Context, cs *app.CharacterShort, now time.Time) ([]*app.TimerEvent, error) {
+	events := make([]*app.TimerEvent, 0)
+	character := &app.EntityShort[int32]{ID: cs.ID, Name: cs.Name}
+	training, err := s.GetTotalTrainingTime(ctx, cs.ID)
+	if err != nil {
+		return nil, err
+	}
+	if d := training.ValueOrZero(); d > 0 {
+		events = append(events, &app.TimerEvent{
+			Character:   character,
+			Description: fmt.Sprintf("The skill queue of %s ends.", cs.Name),
+			Kind:        app.TimerEventSkillQueue,
+			Time:        now.Add(d).Truncate(time.Minute), // avoid jitter between updates
+			Title:       fmt.Sprintf("%s: Skill queue ends", cs.Name),
+			UID:         makeTimerEventUID("skillqueue", cs.ID),
+		})
+	}
+	planets, err := s.ListPlanets(ctx, cs.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range planets {
+		expiry := p.ExtractionsExpiryTime()
+		if expiry.IsZero() || expiry.Before(now) {
+			continue
+		}
+		events = append(events, &app.TimerEvent{
+			Character:   character,
+			Description: fmt.Sprintf("Extractions on %s expire.", p.EvePlanet.Name),
+			Kind:        app.TimerEventExtraction,
+			Time:        expiry,
+			Title:       fmt.Sprintf("%s: Extractions on %s expire", cs.Name, p.EvePlanet.Name),
+			UID:         makeTimerEventUID("extraction", p.ID),
+		})
+	}
+	c, err := s.GetCharacter(ctx, cs.ID)
+	if err != nil {
+		return nil, err
+	}
+	if jump := c.NextCloneJump.ValueOrZero(); jump.After(now) {
+		events = append(events, &app.TimerEvent{
+			Character:   character,
+			Description: fmt.Sprintf("%s can jump clone again.", cs.Name),
+			Kind:        app.TimerEventCloneJump,
+			Time:        jump,
+			Title:       fmt.Sprintf("%s: Next clone jump", cs.Name),
+			UID:         makeTimerEventUID("clonejump", cs.ID),
+		})
+	}
+	contracts, err := s.ListContracts(ctx, cs.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range contracts {
+		var title string
+		switch x.Status {
+		case app.ContractStatusOutstanding:
+			title = "Contract expires"
+		case app.ContractStatusInProgress:
+			title = "Contract due"
+		default:
+			continue
+		}
+		expiry := x.DateExpiredEffective()
+		if expiry.Before(now) {
+			continue
+		}
+		events = append(events, &app.TimerEvent{
+			Character:   character,
+			Description: fmt.Sprintf("%s contract: %s", x.TypeDisplay(), x.NameDisplay()),
+			Kind:        app.TimerEventContract,
+			Time:        expiry,
+			Title:       fmt.Sprintf("%s: %s: %s", cs.Name, title, x.NameDisplay()),
+			UID:         makeTimerEventUID("contract", x.ID),
+		})
+	}
+	return events, nil
+}
+
+func makeTimerEventUID(kind string, id any) string {
+	return fmt.Sprintf("%s-%v@%s", kind, id, timerEventUIDDomain)
+}
+
+// WriteTimersCalendar writes all upcoming timer events as iCalendar to w.
+func (s *CharacterService) WriteTimersCalendar(ctx context.Context, w io.Writer) error {
+	events, err := s.ListTimerEvents(ctx)
+	if err != nil {
+		return err
+	}
+	c := icalendar.Calendar{
+		Name:   "EVE Buddy Timers",
+		ProdID: "-//EVE Buddy//Timers//EN",
+	}
+	for _, e := range events {
+		c.Events = append(c.Events, icalendar.Event{
+			UID:         e.UID,
+			Start:       e.Time,
+			Summary:     e.Title,
+			Description: e.Description,
+		})
+	}
+	return c.Write(w)
+}
@@ -0,0 +1,150 @@
+package characterservice_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTimerEvents(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	eventsOfKind := func(ee []*app.TimerEvent, kind app.TimerEventKind) []*app.TimerEvent {
+		var r []*app.TimerEvent
+		for _, e := range ee {
+			if e.Kind == kind {
+				r = append(r, e)
+			}
+		}
+		return r
+	}
+	t.Run("should return end of skill queue", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateCharacterSkillqueueItem(storage.SkillqueueItemParams{
+			CharacterID: c.ID,
+			StartDate:   now.Add(-time.Hour),
+			FinishDate:  now.Add(3 * time.Hour),
+		})
+		// when
+		ee, err := cs.ListTimerEvents(ctx)
+		// then
+		if assert.NoError(t, err) {
+			ee2 := eventsOfKind(ee, app.TimerEventSkillQueue)
+			if assert.Len(t, ee2, 1) {
+				assert.WithinDuration(t, now.Add(3*time.Hour), ee2[0].Time, 2*time.Minute)
+				assert.Equal(t, c.ID, ee2[0].Character.ID)
+			}
+		}
+	})
+	t.Run("should return upcoming extraction expiry", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		p1 := factory.CreateCharacterPlanet()
+		expiry := now.Add(5 * time.Hour).Truncate(time.Second)
+		factory.CreatePlanetPinExtractor(storage.CreatePlanetPinParams{
+			CharacterPlanetID: p1.ID,
+			ExpiryTime:        expiry,
+		})
+		// when
+		ee, err := cs.ListTimerEvents(ctx)
+		// then
+		if assert.NoError(t, err) {
+			ee2 := eventsOfKind(ee, app.TimerEventExtraction)
+			if assert.Len(t, ee2, 1) {
+				assert.True(t, expiry.Equal(ee2[0].Time))
+			}
+		}
+	})
+	t.Run("should ignore expired extractions", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		p := factory.CreateCharacterPlanet()
+		factory.CreatePlanetPinExtractor(storage.CreatePlanetPinParams{
+			CharacterPlanetID: p.ID,
+			ExpiryTime:        now.Add(-5 * time.Hour),
+		})
+		// when
+		ee, err := cs.ListTimerEvents(ctx)
+		// then
+		if assert.NoError(t, err) {
+			assert.Empty(t, eventsOfKind(ee, app.TimerEventExtraction))
+		}
+	})
+	t.Run("should return open contracts only", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		x := factory.CreateCharacterContract(storage.CreateCharacterContractParams{
+			CharacterID: c.ID,
+			DateExpired: now.Add(48 * time.Hour),
+			DateIssued:  now.Add(-time.Hour),
+			Status:      app.ContractStatusOutstanding,
+		})
+		factory.CreateCharacterContract(storage.CreateCharacterContractParams{
+			CharacterID: c.ID,
+			DateExpired: now.Add(48 * time.Hour),
+			DateIssued:  now.Add(-time.Hour),
+			Status:      app.ContractStatusFinished,
+		})
+		// when
+		ee, err := cs.ListTimerEvents(ctx)
+		// then
+		if assert.NoError(t, err) {
+			ee2 := eventsOfKind(ee, app.TimerEventContract)
+			if assert.Len(t, ee2, 1) {
+				assert.True(t, x.DateExpired.Equal(ee2[0].Time))
+			}
+		}
+	})
+	t.Run("should return upcoming structure timers ordered by time", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x1 := factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{ExitsAt: now.Add(2 * time.Hour)})
+		x2 := factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{ExitsAt: now.Add(1 * time.Hour)})
+		factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{ExitsAt: now.Add(-1 * time.Hour)})
+		// when
+		ee, err := cs.ListTimerEvents(ctx)
+		// then
+		if assert.NoError(t, err) {
+			ee2 := eventsOfKind(ee, app.TimerEventStructure)
+			if assert.Len(t, ee2, 2) {
+				assert.True(t, x2.ExitsAt.Equal(ee2[0].Time))
+				assert.True(t, x1.ExitsAt.Equal(ee2[1].Time))
+				assert.Nil(t, ee2[0].Character)
+			}
+		}
+	})
+}
+
+func TestWriteTimersCalendar(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	// given
+	x := factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{
+		ExitsAt: time.Date(2099, 5, 1, 12, 0, 0, 0, time.UTC),
+	})
+	var buf bytes.Buffer
+	// when
+	err := cs.WriteTimersCalendar(ctx, &buf)
+	// then
+	if assert.NoError(t, err) {
+		s := buf.String()
+		assert.Contains(t, s, "BEGIN:VCALENDAR\r\n")
+		assert.Contains(t, s, "DTSTART:20990501T120000Z\r\n")
+		assert.Contains(t, s, fmt.Sprintf("UID:structure-timer-%d@evebuddy\r\n", x.ID))
+	}
+}
@@ -0,0 +1,287 @@
+package characterservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/discord"
+)
+
+// Parameters for delivering webhook messages.
+const (
+	webhookMaxAttempts  = 10
+	webhookRetryBackoff = 30 * time.Second
+	webhookRetryMax     = time.Hour
+)
+
+// CreateWebhook creates a new webhook and returns it's ID.
+func (s *CharacterService) CreateWebhook(ctx context.Context, w *app.Webhook) (int64, error) {
+	return s.st.CreateWebhook(ctx, webhookParams(w))
+}
+
+func (s *CharacterService) DeleteWebhook(ctx context.Context, id int64) error {
+	return s.st.DeleteWebhook(ctx, id)
+}
+
+func (s *CharacterService) ListWebhooks(ctx context.Context) ([]*app.Webhook, error) {
+	return s.st.ListWebhooks(ctx)
+}
+
+func (s *CharacterService) UpdateWebhook(ctx context.Context, w *app.Webhook) error {
+	return s.st.UpdateWebhook(ctx, w.ID, webhookParams(w))
+}
+
+func webhookParams(w *app.Webhook) storage.UpdateOrCreateWebhookParams {
+	return storage.UpdateOrCreateWebhookParams{
+		CharacterIDs:  w.CharacterIDs,
+		ForwardAlerts: w.ForwardAlerts,
+		ForwardMails:  w.ForwardMails,
+		Groups:        w.Groups,
+		IsEnabled:     w.IsEnabled,
+		Name:          w.Name,
+		Types:         w.Types,
+		URL:           w.URL,
+	}
+}
+
+// ForwardAlert queues an app alert, e.g. expired training, for all webhooks which forward alerts.
+func (s *CharacterService) ForwardAlert(ctx context.Context, title, content string) error {
+	webhooks, err := s.st.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	em := discord.NewEmbed(title, content, "", "EVE Buddy", time.Now())
+	for _, w := range webhooks {
+		if !w.MatchesAlert() {
+			continue
+		}
+		if err := s.queueWebhookMessage(ctx, w.ID, em); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forwardNotifications queues new notifications of a character for all matching webhooks.
+// Notifications older then a webhook are ignored, so that a new webhook is not flooded with old ones.
+func (s *CharacterService) forwardNotifications(ctx context.Context, characterID int32, notificationIDs []int64) error {
+	webhooks, err := s.st.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	characterName, err := s.getCharacterName(ctx, characterID)
+	if err != nil {
+		return err
+	}
+	for _, id := range notificationIDs {
+		n, err := s.st.GetCharacterNotification(ctx, characterID, id)
+		if err != nil {
+			return err
+		}
+		group := evenotification.Type2group[evenotification.Type(n.Type)]
+		var em discord.Embed
+		var isPrepared bool
+		for _, w := range webhooks {
+			if !w.MatchesNotification(characterID, group, n.Type) || n.Timestamp.Before(w.CreatedAt) {
+				continue
+			}
+			if !isPrepared {
+				var sender string
+				if n.Sender != nil {
+					sender = n.Sender.Name
+				}
+				em = discord.NewEmbed(n.TitleDisplay(), n.Body.ValueOrZero(), characterName, sender, n.Timestamp)
+				isPrepared = true
+			}
+			if err := s.queueWebhookMessage(ctx, w.ID, em); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// forwardMail queues a new mail of a character for all matching webhooks.
+func (s *CharacterService) forwardMail(ctx context.Context, characterID, mailID int32) error {
+	webhooks, err := s.st.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	m, err := s.st.GetCharacterMail(ctx, characterID, mailID)
+	if err != nil {
+		return err
+	}
+	var em discord.Embed
+	var isPrepared bool
+	for _, w := range webhooks {
+		if !w.MatchesMail(characterID) || m.Timestamp.Before(w.CreatedAt) {
+			continue
+		}
+		if !isPrepared {
+			characterName, err := s.getCharacterName(ctx, characterID)
+			if err != nil {
+				return err
+			}
+			var from string
+			if m.From != nil {
+				from = fmt.Sprintf("From: %s", m.From.Name)
+			}
+			em = discord.NewEmbed(m.Subject, m.BodyToMarkdown(), characterName, from, m.Timestamp)
+			isPrepared = true
+		}
+		if err := s.queueWebhookMessage(ctx, w.ID, em); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueWebhookMessage adds a message with one embed to the outbox of a webhook.
+func (s *CharacterService) queueWebhookMessage(ctx context.Context, webhookID int64, em discord.Embed) error {
+	payload, err := json.Marshal(discord.Message{Embeds: []discord.Embed{em}})
+	if err != nil {
+		return err
+	}
+	return s.st.CreateWebhookMessage(ctx, storage.CreateWebhookMessageParams{
+		Payload:   payload,
+		WebhookID: webhookID,
+	})
+}
+
+// SendWebhookMessages tries to deliver all due messages in the outbox.
+// Messages which failed with a temporary error are retried later with an exponential backoff.
+// Messages which failed permanently or too often are discarded,
+// as are messages for webhooks which have been disabled or deleted in the meantime.
+func (s *CharacterService) SendWebhookMessages(ctx context.Context) error {
+	_, err, _ := s.sfg.Do("SendWebhookMessages", func() (any, error) {
+		return nil, s.sendWebhookMessages(ctx)
+	})
+	return err
+}
+
+func (s *CharacterService) sendWebhookMessages(ctx context.Context) error {
+	messages, err := s.st.ListWebhookMessagesDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	client := discord.NewClient(s.httpClient)
+	blocked := make(map[int64]time.Time) // webhooks which are rate limited
+	var sent int
+	for _, m := range messages {
+		if until, ok := blocked[m.WebhookID]; ok {
+			if err := s.st.UpdateWebhookMessageAttempt(ctx, storage.UpdateWebhookMessageAttemptParams{
+				ID:            m.ID,
+				Attempts:      m.Attempts,
+				LastError:     m.LastError,
+				NextAttemptAt: until,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		// the webhook might have been changed since the message was queued
+		w, err := s.st.GetWebhook(ctx, m.WebhookID)
+		if errors.Is(err, app.ErrNotFound) {
+			slog.Info("Discarding webhook message for deleted webhook", "ID", m.ID, "webhookID", m.WebhookID)
+			if err := s.st.DeleteWebhookMessage(ctx, m.ID); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !w.IsEnabled {
+			slog.Info("Discarding webhook message for disabled webhook", "ID", m.ID, "webhookID", m.WebhookID)
+			if err := s.st.DeleteWebhookMessage(ctx, m.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		var dm discord.Message
+		if err := json.Unmarshal(m.Payload, &dm); err != nil {
+			slog.Error("Discarding invalid webhook message", "ID", m.ID, "error", err)
+			if err := s.st.DeleteWebhookMessage(ctx, m.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		err = client.Send(ctx, w.URL, dm)
+		if err == nil {
+			if err := s.st.DeleteWebhookMessage(ctx, m.ID); err != nil {
+				return err
+			}
+			sent++
+			continue
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		attempts := m.Attempts + 1
+		retryAfter, isTemporary := webhookRetryAfter(err, attempts)
+		if !isTemporary || attempts >= webhookMaxAttempts {
+			slog.Warn("Discarding webhook message", "ID", m.ID, "webhookID", m.WebhookID, "attempts", attempts, "error", err)
+			if err := s.st.DeleteWebhookMessage(ctx, m.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		next := time.Now().Add(retryAfter)
+		var httpErr discord.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+			blocked[m.WebhookID] = next
+		}
+		slog.Info("Failed to send webhook message. Will retry.", "ID", m.ID, "webhookID", m.WebhookID, "attempts", attempts, "retryAfter", retryAfter, "error", err)
+		if err := s.st.UpdateWebhookMessageAttempt(ctx, storage.UpdateWebhookMessageAttemptParams{
+			ID:            m.ID,
+			Attempts:      attempts,
+			LastError:     err.Error(),
+			NextAttemptAt: next,
+		}); err != nil {
+			return err
+		}
+	}
+	if sent > 0 {
+		slog.Info("Sent webhook messages", "count", sent)
+	}
+	return nil
+}
+
+// webhookRetryAfter returns how long to wait before the next attempt
+// and reports whether an error is temporary.
+func webhookRetryAfter(err error, attempts int) (time.Duration, bool) {
+	d := webhookRetryBackoff << (attempts - 1)
+	if d > webhookRetryMax || d <= 0 {
+		d = webhookRetryMax
+	}
+	if errors.Is(err, discord.ErrInvalidURL) {
+		return 0, false
+	}
+	var err2 discord.HTTPError
+	if !errors.As(err, &err2) {
+		return d, true // network errors are always temporary
+	}
+	if !err2.IsTemporary() {
+		return 0, false
+	}
+	if err2.RetryAfter > 0 {
+		return err2.RetryAfter, true
+	}
+	return d, true
+}
@@ -0,0 +1,326 @@
+package characterservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/discord"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardNotifications(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("should queue matching notification", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		w := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{
+			Groups:    set.New(app.GroupStructure),
+			IsEnabled: true,
+		})
+		n := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Body:        optional.New("body"),
+			CharacterID: c.ID,
+			Timestamp:   time.Now().UTC(),
+			Title:       optional.New("title"),
+			Type:        "StructureUnderAttack",
+		})
+		// when
+		err := cs.forwardNotifications(ctx, c.ID, []int64{n.NotificationID})
+		// then
+		if assert.NoError(t, err) {
+			mm, err := st.ListWebhookMessagesDue(ctx, time.Now())
+			if assert.NoError(t, err) {
+				assert.Len(t, mm, 1)
+				assert.Equal(t, w.ID, mm[0].WebhookID)
+				var dm discord.Message
+				if err := json.Unmarshal(mm[0].Payload, &dm); err != nil {
+					t.Fatal(err)
+				}
+				em := dm.Embeds[0]
+				assert.Equal(t, "title", em.Title)
+				assert.Equal(t, "body", em.Description)
+				assert.Equal(t, c.EveCharacter.Name, em.Author.Name)
+				assert.Equal(t, n.Sender.Name, em.Footer.Text)
+			}
+		}
+	})
+	t.Run("should ignore notifications not matching", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{
+			Groups:    set.New(app.GroupWar),
+			IsEnabled: true,
+		})
+		n := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			CharacterID: c.ID,
+			Timestamp:   time.Now().UTC(),
+			Type:        "StructureUnderAttack",
+		})
+		// when
+		err := cs.forwardNotifications(ctx, c.ID, []int64{n.NotificationID})
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, x)
+			}
+		}
+	})
+	t.Run("should ignore notifications older then webhook", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{
+			Groups:    set.New(app.GroupStructure),
+			IsEnabled: true,
+		})
+		n := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			CharacterID: c.ID,
+			Timestamp:   time.Now().UTC().Add(-time.Hour),
+			Type:        "StructureUnderAttack",
+		})
+		// when
+		err := cs.forwardNotifications(ctx, c.ID, []int64{n.NotificationID})
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, x)
+			}
+		}
+	})
+}
+
+func TestForwardMail(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("should queue new mail", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{
+			ForwardMails: true,
+			IsEnabled:    true,
+		})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			Body:        "<b>body</b>",
+			CharacterID: c.ID,
+			Subject:     "subject",
+			Timestamp:   time.Now().UTC(),
+		})
+		// when
+		err := cs.forwardMail(ctx, c.ID, m.MailID)
+		// then
+		if assert.NoError(t, err) {
+			mm, err := st.ListWebhookMessagesDue(ctx, time.Now())
+			if assert.NoError(t, err) {
+				assert.Len(t, mm, 1)
+				var dm discord.Message
+				if err := json.Unmarshal(mm[0].Payload, &dm); err != nil {
+					t.Fatal(err)
+				}
+				em := dm.Embeds[0]
+				assert.Equal(t, "subject", em.Title)
+				assert.Equal(t, "**body**", em.Description)
+			}
+		}
+	})
+	t.Run("should not queue mail when disabled", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{
+			IsEnabled: true,
+		})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			Timestamp:   time.Now().UTC(),
+		})
+		// when
+		err := cs.forwardMail(ctx, c.ID, m.MailID)
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, x)
+			}
+		}
+	})
+}
+
+func TestSendWebhookMessages(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	cs := newCharacterService(st)
+	ctx := context.Background()
+	t.Run("should delete message after successful delivery", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		w := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{ForwardAlerts: true, IsEnabled: true})
+		httpmock.RegisterResponder("POST", w.URL, httpmock.NewStringResponder(204, ""))
+		if err := cs.ForwardAlert(ctx, "title", "content"); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := cs.SendWebhookMessages(ctx)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, httpmock.GetTotalCallCount())
+			x, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, x)
+			}
+		}
+	})
+	t.Run("should retry message after temporary error", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		w := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{ForwardAlerts: true, IsEnabled: true})
+		httpmock.RegisterResponder("POST", w.URL, httpmock.NewStringResponder(502, "Bad Gateway"))
+		if err := cs.ForwardAlert(ctx, "title", "content"); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := cs.SendWebhookMessages(ctx)
+		// then
+		if assert.NoError(t, err) {
+			mm, err := st.ListWebhookMessagesDue(ctx, time.Now().Add(time.Hour))
+			if assert.NoError(t, err) {
+				assert.Len(t, mm, 1)
+				assert.Equal(t, 1, mm[0].Attempts)
+				assert.WithinDuration(t, time.Now().Add(webhookRetryBackoff), mm[0].NextAttemptAt, 5*time.Second)
+			}
+		}
+	})
+	t.Run("should discard message after permanent error", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		w := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{ForwardAlerts: true, IsEnabled: true})
+		httpmock.RegisterResponder("POST", w.URL, httpmock.NewStringResponder(404, "Unknown Webhook"))
+		if err := cs.ForwardAlert(ctx, "title", "content"); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := cs.SendWebhookMessages(ctx)
+		// then
+		if assert.NoError(t, err) {
+			x, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, x)
+			}
+		}
+	})
+	t.Run("should discard message when webhook was disabled", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		w := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{ForwardAlerts: true, IsEnabled: true})
+		httpmock.RegisterResponder("POST", w.URL, httpmock.NewStringResponder(204, ""))
+		if err := cs.ForwardAlert(ctx, "title", "content"); err != nil {
+			t.Fatal(err)
+		}
+		w.IsEnabled = false
+		if err := cs.UpdateWebhook(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := cs.SendWebhookMessages(ctx)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, httpmock.GetTotalCallCount())
+			x, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, x)
+			}
+		}
+	})
+	t.Run("should send message to current URL of webhook", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		w := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{ForwardAlerts: true, IsEnabled: true})
+		if err := cs.ForwardAlert(ctx, "title", "content"); err != nil {
+			t.Fatal(err)
+		}
+		w.URL = "https://discord.com/api/webhooks/999/new"
+		if err := cs.UpdateWebhook(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+		httpmock.RegisterResponder("POST", w.URL, httpmock.NewStringResponder(204, ""))
+		// when
+		err := cs.SendWebhookMessages(ctx)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, httpmock.GetTotalCallCount())
+		}
+	})
+	t.Run("should discard message when URL is invalid", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		w := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{ForwardAlerts: true, IsEnabled: true})
+		if err := cs.ForwardAlert(ctx, "title", "content"); err != nil {
+			t.Fatal(err)
+		}
+		w.URL = "discord.com/api/webhooks/123/abc"
+		if err := cs.UpdateWebhook(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err := cs.SendWebhookMessages(ctx)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, httpmock.GetTotalCallCount())
+			x, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, x)
+			}
+		}
+	})
+}
+
+func TestWebhookRetryAfter(t *testing.T) {
+	cases := []struct {
+		name        string
+		err         error
+		attempts    int
+		want        time.Duration
+		isTemporary bool
+	}{
+		{"first retry", discord.HTTPError{StatusCode: 500}, 1, webhookRetryBackoff, true},
+		{"backoff", discord.HTTPError{StatusCode: 500}, 3, 4 * webhookRetryBackoff, true},
+		{"max backoff", discord.HTTPError{StatusCode: 500}, 9, webhookRetryMax, true},
+		{"rate limit", discord.HTTPError{StatusCode: 429, RetryAfter: 3 * time.Second}, 1, 3 * time.Second, true},
+		{"permanent", discord.HTTPError{StatusCode: 400}, 1, 0, false},
+		{"network error", context.DeadlineExceeded, 1, webhookRetryBackoff, true},
+		{"invalid URL", fmt.Errorf("%w: %q", discord.ErrInvalidURL, ""), 1, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, isTemporary := webhookRetryAfter(tc.err, tc.attempts)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.isTemporary, isTemporary)
+		})
+	}
+}
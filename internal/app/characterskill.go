@@ -35,11 +35,12 @@ type ListCharacterSkillGroupProgress struct {
 }
 
 type ListSkillProgress struct {
-	ActiveSkillLevel  int
-	TrainedSkillLevel int
-	TypeID            int32
-	TypeDescription   string
-	TypeName          string
+	ActiveSkillLevel   int
+	SkillPointsInSkill int
+	TrainedSkillLevel  int
+	TypeID             int32
+	TypeDescription    string
+	TypeName           string
 }
 
 type CharacterShipSkill struct {
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+// A CharacterSkillPointSnapshot records the skill points of a character at a point in time.
+type CharacterSkillPointSnapshot struct {
+	CharacterID   int32
+	ID            int64
+	IsTraining    optional.Optional[bool] // whether the skill queue was active at the time
+	RecordedAt    time.Time
+	TotalSP       int
+	UnallocatedSP int
+}
+
+// A SkillPointDay summarizes the skill points of a character for one day.
+type SkillPointDay struct {
+	Date          time.Time // start of the day in UTC
+	GainedSP      int       // skill points gained since the last snapshot of an earlier day
+	IsIdle        bool      // true when the skill queue of the character was not active on this day
+	TotalSP       int       // last known total skill points of the day
+	UnallocatedSP int       // last known unallocated skill points of the day
+}
+
+// SummarizeSkillPointDays returns a summary for each day with snapshots in ascending order.
+//
+// Days without any snapshots, e.g. when the app was not running, are not included.
+// Skill points are only updated when a skill level completes,
+// so a character training a long skill would not gain any skill points for days.
+// Therefore a day is idle when the skill queue was not active for any snapshot of that day.
+// Days where the state of the skill queue is unknown are never idle.
+func SummarizeSkillPointDays(snapshots []*CharacterSkillPointSnapshot) []SkillPointDay {
+	days := make([]SkillPointDay, 0)
+	var base *CharacterSkillPointSnapshot // last snapshot of the previous day
+	var first *CharacterSkillPointSnapshot
+	var hasStatus, isTraining bool
+	for i, s := range snapshots {
+		date := s.RecordedAt.UTC().Truncate(24 * time.Hour)
+		if first == nil {
+			first = s
+		}
+		if !s.IsTraining.IsEmpty() {
+			hasStatus = true
+			isTraining = isTraining || s.IsTraining.ValueOrZero()
+		}
+		isLastOfDay := i == len(snapshots)-1 || !snapshots[i+1].RecordedAt.UTC().Truncate(24*time.Hour).Equal(date)
+		if !isLastOfDay {
+			continue
+		}
+		d := SkillPointDay{
+			Date:          date,
+			IsIdle:        hasStatus && !isTraining,
+			TotalSP:       s.TotalSP,
+			UnallocatedSP: s.UnallocatedSP,
+		}
+		start := base
+		if start == nil && first != s {
+			start = first
+		}
+		if start != nil {
+			d.GainedSP = s.TotalSP - start.TotalSP
+		}
+		days = append(days, d)
+		base = s
+		first = nil
+		hasStatus, isTraining = false, false
+	}
+	return days
+}
+
+// CalcSkillPointRate returns the average skill points gained per hour between the first and the last snapshot.
+// Returns an empty value when there are not enough snapshots.
+func CalcSkillPointRate(snapshots []*CharacterSkillPointSnapshot) optional.Optional[float64] {
+	var r optional.Optional[float64]
+	if len(snapshots) < 2 {
+		return r
+	}
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	hours := last.RecordedAt.Sub(first.RecordedAt).Hours()
+	if hours <= 0 {
+		return r
+	}
+	return optional.New(float64(last.TotalSP-first.TotalSP) / hours)
+}
+
+// CharacterSkillPointHistory is the skill point history of a character.
+type CharacterSkillPointHistory struct {
+	CharacterID int32
+	Days        []SkillPointDay
+	// ActualRate is the average skill points trained per hour during the recorded period.
+	ActualRate optional.Optional[float64]
+	// TheoreticalRate is the skill points per hour for the skill currently in training
+	// as calculated from the character's attributes.
+	TheoreticalRate optional.Optional[float64]
+	// PotentialRate is the highest possible skill points per hour with the character's attributes.
+	PotentialRate optional.Optional[float64]
+}
+
+// IdleDays returns the number of days the character was not training.
+func (h CharacterSkillPointHistory) IdleDays() int {
+	var n int
+	for _, d := range h.Days {
+		if d.IsIdle {
+			n++
+		}
+	}
+	return n
+}
+
+// LostSP returns an estimate of the skill points the character could have trained on idle days.
+// The estimate is based on the theoretical rate or the potential rate when the character is not training.
+func (h CharacterSkillPointHistory) LostSP() optional.Optional[int] {
+	rate := h.TheoreticalRate
+	if rate.IsEmpty() {
+		rate = h.PotentialRate
+	}
+	if rate.IsEmpty() {
+		return optional.Optional[int]{}
+	}
+	return optional.New(int(float64(h.IdleDays()) * 24 * rate.ValueOrZero()))
+}
@@ -0,0 +1,117 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+func TestSummarizeSkillPointDays(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	makeSnapshot := func(d, h, sp int, isTraining optional.Optional[bool]) *app.CharacterSkillPointSnapshot {
+		return &app.CharacterSkillPointSnapshot{
+			IsTraining: isTraining,
+			RecordedAt: day.Add(time.Duration(d*24+h) * time.Hour),
+			TotalSP:    sp,
+		}
+	}
+	training := optional.New(true)
+	notTraining := optional.New(false)
+	unknown := optional.Optional[bool]{}
+	t.Run("should summarize days and flag idle days", func(t *testing.T) {
+		// given
+		snapshots := []*app.CharacterSkillPointSnapshot{
+			makeSnapshot(0, 1, 1000, training),
+			makeSnapshot(0, 13, 1500, training),
+			makeSnapshot(1, 12, 2500, training),
+			makeSnapshot(2, 12, 2500, notTraining),
+			makeSnapshot(4, 12, 3000, training),
+		}
+		// when
+		got := app.SummarizeSkillPointDays(snapshots)
+		// then
+		if assert.Len(t, got, 4) {
+			assert.Equal(t, day, got[0].Date)
+			assert.Equal(t, 500, got[0].GainedSP)
+			assert.Equal(t, 1500, got[0].TotalSP)
+			assert.False(t, got[0].IsIdle)
+			assert.Equal(t, 1000, got[1].GainedSP)
+			assert.False(t, got[1].IsIdle)
+			assert.Equal(t, 0, got[2].GainedSP)
+			assert.True(t, got[2].IsIdle)
+			assert.Equal(t, day.Add(4*24*time.Hour), got[3].Date)
+			assert.Equal(t, 500, got[3].GainedSP)
+		}
+	})
+	t.Run("should not flag day as idle when training a long skill without gaining SP", func(t *testing.T) {
+		snapshots := []*app.CharacterSkillPointSnapshot{
+			makeSnapshot(0, 12, 1000, training),
+			makeSnapshot(1, 12, 1000, training),
+			makeSnapshot(2, 12, 1000, training),
+		}
+		got := app.SummarizeSkillPointDays(snapshots)
+		if assert.Len(t, got, 3) {
+			for _, d := range got {
+				assert.False(t, d.IsIdle)
+			}
+		}
+	})
+	t.Run("should not flag day as idle when queue was active for some of it", func(t *testing.T) {
+		snapshots := []*app.CharacterSkillPointSnapshot{
+			makeSnapshot(0, 1, 1000, training),
+			makeSnapshot(0, 13, 1500, notTraining),
+		}
+		got := app.SummarizeSkillPointDays(snapshots)
+		if assert.Len(t, got, 1) {
+			assert.False(t, got[0].IsIdle)
+		}
+	})
+	t.Run("should not flag day with unknown queue state as idle", func(t *testing.T) {
+		snapshots := []*app.CharacterSkillPointSnapshot{
+			makeSnapshot(0, 12, 1000, unknown),
+			makeSnapshot(1, 12, 1000, unknown),
+		}
+		got := app.SummarizeSkillPointDays(snapshots)
+		if assert.Len(t, got, 2) {
+			assert.False(t, got[1].IsIdle)
+		}
+	})
+}
+
+func TestCalcSkillPointRate(t *testing.T) {
+	now := time.Now()
+	t.Run("should return average rate per hour", func(t *testing.T) {
+		snapshots := []*app.CharacterSkillPointSnapshot{
+			{RecordedAt: now.Add(-2 * time.Hour), TotalSP: 1000},
+			{RecordedAt: now.Add(-1 * time.Hour), TotalSP: 2000},
+			{RecordedAt: now, TotalSP: 5000},
+		}
+		got := app.CalcSkillPointRate(snapshots)
+		assert.InDelta(t, 2000, got.ValueOrZero(), 0.01)
+	})
+	t.Run("should return empty when not enough snapshots", func(t *testing.T) {
+		got := app.CalcSkillPointRate([]*app.CharacterSkillPointSnapshot{{RecordedAt: now, TotalSP: 1000}})
+		assert.True(t, got.IsEmpty())
+	})
+}
+
+func TestCharacterSkillPointHistory(t *testing.T) {
+	h := app.CharacterSkillPointHistory{
+		Days: []app.SkillPointDay{{IsIdle: true}, {}, {IsIdle: true}},
+	}
+	t.Run("can count idle days", func(t *testing.T) {
+		assert.Equal(t, 2, h.IdleDays())
+	})
+	t.Run("should use potential rate for lost SP when not training", func(t *testing.T) {
+		h.PotentialRate = optional.New(2000.0)
+		assert.Equal(t, 96000, h.LostSP().ValueOrZero())
+	})
+	t.Run("should prefer theoretical rate for lost SP", func(t *testing.T) {
+		h.TheoreticalRate = optional.New(1000.0)
+		assert.Equal(t, 48000, h.LostSP().ValueOrZero())
+	})
+}
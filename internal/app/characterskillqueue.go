@@ -18,6 +18,7 @@ type CharacterSkillqueueItem struct {
 	ID               int64
 	QueuePosition    int
 	StartDate        time.Time
+	SkillID          int32 // type ID of the skill
 	SkillName        string
 	SkillDescription string
 	TrainingStartSP  int
@@ -29,6 +29,7 @@ type ChartType uint
 const (
 	Pie ChartType = iota
 	Bar
+	Line // values are shown in the order of their labels, e.g. dates in ISO format
 )
 
 const (
@@ -37,6 +38,7 @@ const (
 	defaultHeight       = 300
 	chartPadding        = 0.05 // per cent
 	defaultFontSize     = 10
+	lineChartMaxTicks   = 6
 )
 
 type Value struct {
@@ -125,6 +127,8 @@ func (cb ChartBuilder) render(ct ChartType, size fyne.Size, title string, values
 		content, err = cb.makeBarChart(pixelW, pixelH, values)
 	case Pie:
 		content, err = cb.makePieChart(pixelW, pixelH, values)
+	case Line:
+		content, err = cb.makeLineChart(pixelW, pixelH, values)
 	}
 	if err != nil {
 		return nil, err
@@ -261,6 +265,64 @@ func (cb ChartBuilder) makeBarChart(width, height int, data []Value) ([]byte, er
 	return buf.Bytes(), nil
 }
 
+func (cb ChartBuilder) makeLineChart(width, height int, data []Value) ([]byte, error) {
+	xValues := make([]float64, len(data))
+	yValues := make([]float64, len(data))
+	for i, r := range data {
+		xValues[i] = float64(i)
+		yValues[i] = r.Value
+	}
+	step := max(1, (len(data)+lineChartMaxTicks-1)/lineChartMaxTicks)
+	ticks := make([]chart.Tick, 0)
+	for i := 0; i < len(data); i += step {
+		ticks = append(ticks, chart.Tick{Value: float64(i), Label: data[i].Label})
+	}
+	lineChart := chart.Chart{
+		Background: chart.Style{
+			FillColor: chart.ColorTransparent,
+		},
+		Canvas: chart.Style{
+			FillColor: chart.ColorTransparent,
+			FontSize:  cb.FontSize,
+		},
+		Width:  width,
+		Height: height,
+		XAxis: chart.XAxis{
+			Style: chart.Style{
+				FontColor:           cb.foregroundColor(),
+				FontSize:            cb.FontSize,
+				TextRotationDegrees: 90,
+			},
+			Ticks: ticks,
+		},
+		YAxis: chart.YAxis{
+			Style: chart.Style{
+				FontColor: cb.foregroundColor(),
+				FontSize:  cb.FontSize,
+			},
+			ValueFormatter: numericValueFormatter,
+		},
+		Series: []chart.Series{
+			chart.ContinuousSeries{
+				Style: chart.Style{
+					StrokeColor: cb.foregroundColor(),
+					StrokeWidth: 2,
+				},
+				XValues: xValues,
+				YValues: yValues,
+			},
+		},
+	}
+	if cb.Font != nil {
+		lineChart.Font = cb.Font
+	}
+	var buf bytes.Buffer
+	if err := lineChart.Render(chart.PNG, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func numericValueFormatter(v interface{}) string {
 	x := v.(float64)
 	return ihumanize.Number(x, 1)
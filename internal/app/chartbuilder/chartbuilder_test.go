@@ -25,6 +25,15 @@ func TestCharts(t *testing.T) {
 		}
 		cb.Render(chartbuilder.Pie, size, "Title", v)
 	})
+	t.Run("can create a line chart", func(t *testing.T) {
+		cb := chartbuilder.New(nil)
+		v := []chartbuilder.Value{
+			{"2025-01-01", 2},
+			{"2025-01-02", 3},
+			{"2025-01-03", 5},
+		}
+		cb.Render(chartbuilder.Line, size, "Title", v)
+	})
 	t.Run("can handle no data", func(t *testing.T) {
 		cb := chartbuilder.New(nil)
 		v := []chartbuilder.Value{}
@@ -0,0 +1,36 @@
+package app
+
+import "time"
+
+// DataSearchKind represents the kind of item found in a search of the local data.
+type DataSearchKind uint
+
+const (
+	DataSearchUndefined DataSearchKind = iota
+	DataSearchMail
+	DataSearchNotification
+	DataSearchContract
+)
+
+var dsk2String = map[DataSearchKind]string{
+	DataSearchContract:     "Contracts",
+	DataSearchMail:         "Mails",
+	DataSearchNotification: "Communications",
+}
+
+func (dsk DataSearchKind) String() string {
+	s, ok := dsk2String[dsk]
+	if !ok {
+		return "?"
+	}
+	return s
+}
+
+// DataSearchResult is an item found in a search of the local data.
+type DataSearchResult struct {
+	CharacterID int32
+	ItemID      int64 // EVE ID of the item, e.g. the mail ID for mails
+	Kind        DataSearchKind
+	Timestamp   time.Time
+	Title       string
+}
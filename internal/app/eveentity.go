@@ -5,6 +5,8 @@ import (
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+
+	"github.com/ErikKalkoken/evebuddy/internal/evehtml"
 )
 
 // An EveEntity in EveOnline.
@@ -23,6 +25,40 @@ func (ee EveEntity) IsCharacter() bool {
 	return ee.Category == EveEntityCharacter
 }
 
+// CanReceiveMail reports whether an entity can be a recipient of an Eve mail.
+func (ee EveEntity) CanReceiveMail() bool {
+	switch ee.Category {
+	case EveEntityAlliance, EveEntityCharacter, EveEntityCorporation, EveEntityMailList:
+		return true
+	}
+	return false
+}
+
+// ShowInfoLink returns an Eve Online HTML link for showing information about an entity.
+// Returns the escaped name for entities which can not be linked.
+func (ee EveEntity) ShowInfoLink() string {
+	var typeID int32
+	switch ee.Category {
+	case EveEntityAlliance:
+		typeID = 16159
+	case EveEntityCharacter:
+		typeID = 1376
+	case EveEntityConstellation:
+		typeID = 4
+	case EveEntityCorporation:
+		typeID = 2
+	case EveEntityInventoryType:
+		return evehtml.ShowInfoLink(ee.ID, 0, ee.Name)
+	case EveEntityRegion:
+		typeID = 3
+	case EveEntitySolarSystem:
+		typeID = 5
+	default:
+		return evehtml.FromPlain(ee.Name)
+	}
+	return evehtml.ShowInfoLink(typeID, ee.ID, ee.Name)
+}
+
 func (ee *EveEntity) Compare(other *EveEntity) int {
 	return cmp.Compare(ee.Name, other.Name)
 }
@@ -3,9 +3,9 @@ package evenotification
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
-	"github.com/ErikKalkoken/evebuddy/internal/optional"
 	"github.com/antihax/goesi/notification"
 	"github.com/dustin/go-humanize"
 	"gopkg.in/yaml.v3"
@@ -17,45 +17,48 @@ const (
 	billTypeInfrastructureHub = 7
 )
 
-func (s *EveNotificationService) renderBilling(ctx context.Context, type_ Type, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	switch type_ {
+// BillingData is the structured data of a billing notification.
+type BillingData struct {
+	BaseData
+	Amount        float64 // in ISK
+	BillTypeID    int32
+	Creditor      *app.EveEntity
+	Debtor        *app.EveEntity
+	DueAt         time.Time
+	External1     *app.EveEntity // what the bill is for, e.g. the leased structure
+	External2     *app.EveEntity // where the bill is for, e.g. the location of a leased structure
+	IssuedAt      time.Time
+	StructureType *app.EveType // infrastructure hub bills only
+}
+
+func (d BillingData) Entities() []*app.EveEntity {
+	return compactEntities(d.Creditor, d.Debtor, d.External1, d.External2)
+}
+
+func (s *EveNotificationService) dataBilling(ctx context.Context, base BaseData, text string) (Data, error) {
+	d := BillingData{BaseData: base}
+	switch base.Type {
 	case BillPaidCorpAllMsg:
-		title.Set("Bill payed")
 		var data notification.BillPaidCorpAllMsg
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
+			return nil, err
 		}
-		out := fmt.Sprintf(
-			"A bill of **%s** ISK, due **%s** was payed.",
-			humanize.Commaf(float64(data.Amount)),
-			fromLDAPTime(data.DueDate).Format(app.DateTimeFormat),
-		)
-		body.Set(out)
+		d.Amount = float64(data.Amount)
+		d.DueAt = fromLDAPTime(data.DueDate)
 
 	case BillOutOfMoneyMsg:
 		var data notification.CorpAllBillMsgV2
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
+			return nil, err
 		}
-		title.Set(fmt.Sprintf("Insufficient funds for %s bill", billTypeName(data.BillTypeID)))
-		out := fmt.Sprintf(
-			"The selected corporation wallet division for automatic payments "+
-				"does not have enough current funds available to pay the %s bill, "+
-				"due to be paid by %s. "+
-				"Transfer additional funds to the selected wallet "+
-				"division in order to meet your pending automatic bills.",
-			billTypeName(data.BillTypeID),
-			fromLDAPTime(data.DueDate).Format(app.DateTimeFormat),
-		)
-		body.Set(out)
+		d.BillTypeID = data.BillTypeID
+		d.DueAt = fromLDAPTime(data.DueDate)
 
 	case CorpAllBillMsg:
 		var data notification.CorpAllBillMsgV2
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
+			return nil, err
 		}
-		title.Set(fmt.Sprintf("Bill issued for %s", billTypeName(data.BillTypeID)))
 		ids := []int32{data.CreditorID, data.DebtorID}
 		if data.ExternalID != -1 && data.ExternalID == int64(int32(data.ExternalID)) {
 			ids = append(ids, int32(data.ExternalID))
@@ -65,22 +68,77 @@ func (s *EveNotificationService) renderBilling(ctx context.Context, type_ Type,
 		}
 		entities, err := s.eus.ToEntities(ctx, ids)
 		if err != nil {
-			return title, body, err
+			return nil, err
 		}
-		var external1 string
-		if x, ok := entities[int32(data.ExternalID)]; ok && x.Name != "" {
-			external1 = x.Name
-		} else {
-			external1 = "?"
+		d.Amount = data.Amount
+		d.BillTypeID = data.BillTypeID
+		d.Creditor = entities[data.CreditorID]
+		d.Debtor = entities[data.DebtorID]
+		d.DueAt = fromLDAPTime(data.DueDate)
+		d.External1 = entities[int32(data.ExternalID)]
+		d.External2 = entities[int32(data.ExternalID2)]
+		d.IssuedAt = fromLDAPTime(data.CurrentDate)
+
+	case InfrastructureHubBillAboutToExpire:
+		var data notification.InfrastructureHubBillAboutToExpire
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
 		}
-		var external2 string
-		if x, ok := entities[int32(data.ExternalID2)]; ok && x.Name != "" {
-			external2 = x.Name
-		} else {
-			external2 = "?"
+		solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
+		if err != nil {
+			return nil, err
+		}
+		d.SolarSystem = solarSystem
+		d.DueAt = fromLDAPTime(data.DueDate)
+
+	case IHubDestroyedByBillFailure:
+		var data notification.IHubDestroyedByBillFailure
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
 		}
+		solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
+		if err != nil {
+			return nil, err
+		}
+		structureType, err := s.eus.GetOrCreateTypeESI(ctx, int32(data.StructureTypeID))
+		if err != nil {
+			return nil, err
+		}
+		d.SolarSystem = solarSystem
+		d.StructureType = structureType
+	}
+	return d, nil
+}
+
+func (d BillingData) render() (string, string) {
+	var title, body string
+	switch d.Type {
+	case BillPaidCorpAllMsg:
+		title = "Bill payed"
+		body = fmt.Sprintf(
+			"A bill of **%s** ISK, due **%s** was payed.",
+			humanize.Commaf(d.Amount),
+			d.DueAt.Format(app.DateTimeFormat),
+		)
+
+	case BillOutOfMoneyMsg:
+		title = fmt.Sprintf("Insufficient funds for %s bill", billTypeName(d.BillTypeID))
+		body = fmt.Sprintf(
+			"The selected corporation wallet division for automatic payments "+
+				"does not have enough current funds available to pay the %s bill, "+
+				"due to be paid by %s. "+
+				"Transfer additional funds to the selected wallet "+
+				"division in order to meet your pending automatic bills.",
+			billTypeName(d.BillTypeID),
+			d.DueAt.Format(app.DateTimeFormat),
+		)
+
+	case CorpAllBillMsg:
+		title = fmt.Sprintf("Bill issued for %s", billTypeName(d.BillTypeID))
+		external1 := entityNameOrUnknown(d.External1)
+		external2 := entityNameOrUnknown(d.External2)
 		var billPurpose string
-		switch data.BillTypeID {
+		switch d.BillTypeID {
 		case billTypeLease:
 			billPurpose = fmt.Sprintf("extending the lease of **%s** at **%s**", external1, external2)
 		case billTypeAlliance:
@@ -90,57 +148,35 @@ func (s *EveNotificationService) renderBilling(ctx context.Context, type_ Type,
 		default:
 			billPurpose = "?"
 		}
-		body.Set(fmt.Sprintf(
+		body = fmt.Sprintf(
 			"A bill of **%s** ISK, due **%s** owed by %s to %s was issued on %s. This bill is for %s.",
-			humanize.Commaf(data.Amount),
-			fromLDAPTime(data.DueDate).Format(app.DateTimeFormat),
-			makeEveEntityProfileLink(entities[data.DebtorID]),
-			makeEveEntityProfileLink(entities[data.CreditorID]),
-			fromLDAPTime(data.CurrentDate).Format(app.DateTimeFormat),
+			humanize.Commaf(d.Amount),
+			d.DueAt.Format(app.DateTimeFormat),
+			makeEveEntityProfileLink(d.Debtor),
+			makeEveEntityProfileLink(d.Creditor),
+			d.IssuedAt.Format(app.DateTimeFormat),
 			billPurpose,
-		))
+		)
 
 	case InfrastructureHubBillAboutToExpire:
-		title.Set("IHub Bill About to Expire")
-		var data notification.InfrastructureHubBillAboutToExpire
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-		if err != nil {
-			return title, body, err
-		}
-		out := fmt.Sprintf("Maintenance bill for Infrastructure Hub in %s expires at %s, "+
+		title = "IHub Bill About to Expire"
+		body = fmt.Sprintf("Maintenance bill for Infrastructure Hub in %s expires at %s, "+
 			"if not paid in time this Infrastructure Hub will self-destruct.",
-			makeSolarSystemLink(solarSystem),
-			fromLDAPTime(data.DueDate).Format(app.DateTimeFormat),
+			makeSolarSystemLink(d.SolarSystem),
+			d.DueAt.Format(app.DateTimeFormat),
 		)
-		body.Set(out)
 
 	case IHubDestroyedByBillFailure:
-		var data notification.IHubDestroyedByBillFailure
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-		if err != nil {
-			return title, body, err
-		}
-		structureType, err := s.eus.GetOrCreateTypeESI(ctx, int32(data.StructureTypeID))
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf(
+		title = fmt.Sprintf(
 			"%s has self-destructed due to unpaid maintenance bills",
-			structureType.Name,
-		))
-		out := fmt.Sprintf("%s in %s has self-destructed, as the standard maintenance bills where not paid.",
-			structureType.Name,
-			makeSolarSystemLink(solarSystem),
+			d.StructureType.Name,
+		)
+		body = fmt.Sprintf("%s in %s has self-destructed, as the standard maintenance bills where not paid.",
+			d.StructureType.Name,
+			makeSolarSystemLink(d.SolarSystem),
 		)
-		body.Set(out)
 	}
-	return title, body, nil
+	return title, body
 }
 
 func billTypeName(id int32) string {
@@ -0,0 +1,139 @@
+package evenotification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/antihax/goesi/notification"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v3"
+)
+
+// BountyData is the structured data of a notification about bounties.
+type BountyData struct {
+	BaseData
+	Amount      float64        // in ISK
+	Character   *app.EveEntity // who placed, claimed or shared a bounty or who was killed
+	TotalAmount float64        // in ISK. ESS notifications only.
+}
+
+func (d BountyData) Entities() []*app.EveEntity {
+	return compactEntities(d.Character)
+}
+
+func (s *EveNotificationService) dataBounty(ctx context.Context, base BaseData, text string) (Data, error) {
+	var characterID int32
+	d := BountyData{BaseData: base}
+	switch base.Type {
+	case BountyClaimMsg:
+		var data notification.BountyClaimMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+		d.Amount = data.Amount
+
+	case BountyESSShared:
+		var data notification.BountyESSShared
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+		d.Amount = data.MyIsk
+		d.TotalAmount = data.TotalIsk
+
+	case BountyESSTaken:
+		var data notification.BountyESSTaken
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+		d.Amount = data.MyIsk
+		d.TotalAmount = data.TotalIsk
+
+	case BountyPlacedAlliance, BountyPlacedChar, BountyPlacedCorp:
+		// All three types share the same structure
+		var data notification.BountyPlacedChar
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.BountyPlacerID
+		d.Amount = data.Bounty
+
+	case BountyYourBountyClaimed:
+		var data notification.BountyYourBountyClaimed
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.VictimID
+		d.Amount = data.Bounty
+	}
+	entities, err := s.eus.ToEntities(ctx, []int32{characterID})
+	if err != nil {
+		return nil, err
+	}
+	if characterID != 0 {
+		d.Character = entities[characterID]
+	}
+	return d, nil
+}
+
+func (d BountyData) render() (string, string) {
+	var title, body string
+	amount := humanize.Commaf(d.Amount)
+	switch d.Type {
+	case BountyClaimMsg:
+		title = "Bounty claimed"
+		body = fmt.Sprintf(
+			"You have claimed a bounty of **%s** ISK for killing %s.",
+			amount,
+			makeEveEntityProfileLink(d.Character),
+		)
+
+	case BountyESSShared:
+		title = "ESS bounties shared"
+		body = fmt.Sprintf(
+			"%s has shared the ESS bounty pool of **%s** ISK. Your share is **%s** ISK.",
+			makeEveEntityProfileLink(d.Character),
+			humanize.Commaf(d.TotalAmount),
+			amount,
+		)
+
+	case BountyESSTaken:
+		title = "ESS bounties taken"
+		body = fmt.Sprintf(
+			"%s has taken the ESS bounty pool of **%s** ISK. Your share is **%s** ISK.",
+			makeEveEntityProfileLink(d.Character),
+			humanize.Commaf(d.TotalAmount),
+			amount,
+		)
+
+	case BountyPlacedAlliance, BountyPlacedChar, BountyPlacedCorp:
+		var target string
+		switch d.Type {
+		case BountyPlacedAlliance:
+			target = "your alliance"
+		case BountyPlacedChar:
+			target = "you"
+		case BountyPlacedCorp:
+			target = "your corporation"
+		}
+		title = fmt.Sprintf("Bounty placed on %s", target)
+		body = fmt.Sprintf(
+			"%s has placed a bounty of **%s** ISK on %s.",
+			makeEveEntityProfileLink(d.Character),
+			amount,
+			target,
+		)
+
+	case BountyYourBountyClaimed:
+		title = "Your bounty was claimed"
+		body = fmt.Sprintf(
+			"A bounty of **%s** ISK that you placed on %s has been claimed.",
+			amount,
+			makeEveEntityProfileLink(d.Character),
+		)
+	}
+	return title, body
+}
@@ -0,0 +1,213 @@
+package evenotification
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/antihax/goesi/notification"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v3"
+)
+
+// CloneData is the structured data of a notification about clones.
+type CloneData struct {
+	BaseData
+	Character       *app.EveEntity   // who killed the pod, moved the clone or destroyed the jump clone
+	Corporation     *app.EveEntity   // corporation owning the station
+	Implants        []*app.EveEntity // sorted by name. Jump clones only.
+	Location        *app.EveLocation // where the clone was located
+	LocationOwner   *app.EveEntity
+	NewLocation     *app.EveLocation // where the clone is located now
+	Skill           *app.EveEntity   // skill which lost skill points
+	SkillPointsLost int
+}
+
+// cloneMovedMsg replaces the goesi type, which defines the station ID as int32
+// and is therefore too small for structure IDs.
+type cloneMovedMsg struct {
+	CharsInCorpID int32 `yaml:"charsInCorpID"`
+	CorpID        int32 `yaml:"corpID"`
+	NewStationID  int64 `yaml:"newStationID"`
+	StationID     int64 `yaml:"stationID"`
+}
+
+func (d CloneData) Entities() []*app.EveEntity {
+	ee := []*app.EveEntity{d.Character, d.Corporation, d.LocationOwner, d.Skill}
+	ee = append(ee, d.Implants...)
+	return compactEntities(ee...)
+}
+
+func (s *EveNotificationService) dataClone(ctx context.Context, base BaseData, text string) (Data, error) {
+	var characterID, corporationID, locationOwnerID, skillID int32
+	var locationID, newLocationID int64
+	var implantIDs []int32
+	d := CloneData{BaseData: base}
+	switch base.Type {
+	case CloneActivationMsg:
+		var data notification.CloneActivationMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.PodKillerID
+		newLocationID = int64(data.CloneStationID)
+		skillID = data.SkillID
+		d.SkillPointsLost = int(data.SkillPointsLost)
+
+	case CloneActivationMsg2:
+		var data notification.CloneActivationMsg2
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.PodKillerID
+		newLocationID = int64(data.CloneStationID)
+
+	case CloneMovedMsg:
+		var data cloneMovedMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharsInCorpID
+		corporationID = data.CorpID
+		locationID = data.StationID
+		newLocationID = data.NewStationID
+
+	case CloneRevokedMsg1, CloneRevokedMsg2:
+		// Both types share the same structure
+		var data notification.CloneRevokedMsg2
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		corporationID = data.CorpID
+		locationID = data.StationID
+		newLocationID = int64(data.NewStationID)
+
+	case JumpCloneDeletedMsg1:
+		var data notification.JumpCloneDeletedMsg1
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		locationID = data.LocationID
+		locationOwnerID = data.LocationOwnerID
+		implantIDs = data.TypeIDs
+
+	case JumpCloneDeletedMsg2:
+		var data notification.JumpCloneDeletedMsg2
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.DestroyerID
+		locationID = int64(data.LocationID)
+		locationOwnerID = data.LocationOwnerID
+		implantIDs = data.TypeIDs
+	}
+	ids := slices.Concat([]int32{characterID, corporationID, locationOwnerID, skillID}, implantIDs)
+	entities, err := s.eus.ToEntities(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if characterID != 0 {
+		d.Character = entities[characterID]
+	}
+	if corporationID != 0 {
+		d.Corporation = entities[corporationID]
+	}
+	if locationOwnerID != 0 {
+		d.LocationOwner = entities[locationOwnerID]
+	}
+	if skillID != 0 {
+		d.Skill = entities[skillID]
+	}
+	for _, id := range implantIDs {
+		d.Implants = append(d.Implants, entities[id])
+	}
+	slices.SortFunc(d.Implants, func(a, b *app.EveEntity) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	if locationID != 0 {
+		d.Location, err = s.eus.GetOrCreateLocationESI(ctx, locationID)
+		if err != nil {
+			return nil, err
+		}
+		d.SolarSystem = d.Location.SolarSystem
+	}
+	if newLocationID != 0 {
+		d.NewLocation, err = s.eus.GetOrCreateLocationESI(ctx, newLocationID)
+		if err != nil {
+			return nil, err
+		}
+		d.SolarSystem = d.NewLocation.SolarSystem
+	}
+	return d, nil
+}
+
+func (d CloneData) render() (string, string) {
+	var title, body string
+	switch d.Type {
+	case CloneActivationMsg, CloneActivationMsg2:
+		title = "Clone activated"
+		body = fmt.Sprintf(
+			"Your pod has been killed by %s and your clone at **%s** has been activated.",
+			makeEveEntityProfileLink(d.Character),
+			locationName(d.NewLocation),
+		)
+		if d.SkillPointsLost > 0 {
+			body += fmt.Sprintf(
+				"\n\nYou have lost **%s** skill points in %s.",
+				humanize.Comma(int64(d.SkillPointsLost)),
+				entityNameOrUnknown(d.Skill),
+			)
+		}
+
+	case CloneMovedMsg:
+		title = "Clone moved"
+		body = fmt.Sprintf(
+			"Your clone has been moved from **%s** to **%s** by %s of %s.",
+			locationName(d.Location),
+			locationName(d.NewLocation),
+			makeEveEntityProfileLink(d.Character),
+			makeEveEntityProfileLink(d.Corporation),
+		)
+
+	case CloneRevokedMsg1, CloneRevokedMsg2:
+		title = "Clone revoked"
+		body = fmt.Sprintf(
+			"%s has revoked your clone rights at **%s**. "+
+				"Your clone has been moved to **%s**.",
+			makeEveEntityProfileLink(d.Corporation),
+			locationName(d.Location),
+			locationName(d.NewLocation),
+		)
+
+	case JumpCloneDeletedMsg1, JumpCloneDeletedMsg2:
+		title = fmt.Sprintf("Jump clone at %s deleted", locationName(d.Location))
+		body = fmt.Sprintf("Your jump clone at **%s**", locationName(d.Location))
+		if d.LocationOwner != nil {
+			body += fmt.Sprintf(" owned by %s", makeEveEntityProfileLink(d.LocationOwner))
+		}
+		if d.Character != nil {
+			body += fmt.Sprintf(" has been destroyed by %s.", makeEveEntityProfileLink(d.Character))
+		} else {
+			body += " has been deleted."
+		}
+		if len(d.Implants) > 0 {
+			lines := make([]string, 0)
+			for _, e := range d.Implants {
+				lines = append(lines, fmt.Sprintf("- %s", e.Name))
+			}
+			body += fmt.Sprintf("\n\nThe following implants were lost:\n\n%s", strings.Join(lines, "\n\n"))
+		}
+	}
+	return title, body
+}
+
+// locationName returns the name of a location or "?" when it is unknown.
+func locationName(l *app.EveLocation) string {
+	if l == nil {
+		return "?"
+	}
+	return l.DisplayName()
+}
@@ -4,149 +4,170 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/antihax/goesi/notification"
 	"gopkg.in/yaml.v3"
 )
 
-func (s *EveNotificationService) renderCorporate(ctx context.Context, type_ Type, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	switch type_ {
+// CorporateData is the structured data of a notification about corporation membership.
+type CorporateData struct {
+	BaseData
+	ApplicationText   string
+	Character         *app.EveEntity // the applicant or member
+	Corporation       *app.EveEntity
+	CustomMessage     string         // reply from the corporation, if any
+	InvokingCharacter *app.EveEntity // who sent an invitation
+}
+
+func (d CorporateData) Entities() []*app.EveEntity {
+	return compactEntities(d.Character, d.Corporation, d.InvokingCharacter)
+}
+
+func (s *EveNotificationService) dataCorporate(ctx context.Context, base BaseData, text string) (Data, error) {
+	var charID, corpID, invokingCharID int32
+	d := CorporateData{BaseData: base}
+	switch base.Type {
 	case CharAppAcceptMsg:
 		var data notification.CharAppAcceptMsg
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
+			return nil, err
+		}
+		charID, corpID = data.CharID, data.CorpID
+		d.ApplicationText = data.ApplicationText
+	case CorpAppNewMsg:
+		var data notification.CorpAppNewMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		charID, corpID = data.CharID, data.CorpID
+		d.ApplicationText = data.ApplicationText
+	case CorpAppInvitedMsg:
+		var data notification.CorpAppInvitedMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		charID, corpID, invokingCharID = data.CharID, data.CorpID, data.InvokingCharID
+		d.ApplicationText = data.ApplicationText
+	case CharAppRejectMsg:
+		var data notification.CharAppRejectMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		charID, corpID = data.CharID, data.CorpID
+		d.ApplicationText = data.ApplicationText
+	case CorpAppRejectCustomMsg:
+		var data notification.CorpAppRejectCustomMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
 		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.CharID, data.CorpID})
-		if err != nil {
-			return title, body, err
+		charID, corpID = data.CharID, data.CorpID
+		d.ApplicationText = data.ApplicationText
+		d.CustomMessage = data.CustomMessage
+	case CharAppWithdrawMsg:
+		var data notification.CharAppWithdrawMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
 		}
-		title.Set(fmt.Sprintf(
+		charID, corpID = data.CharID, data.CorpID
+		d.ApplicationText = data.ApplicationText
+	case CharLeftCorpMsg:
+		var data notification.CharLeftCorpMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		charID, corpID = data.CharID, data.CorpID
+	}
+	ids := []int32{charID, corpID}
+	if invokingCharID != 0 {
+		ids = append(ids, invokingCharID)
+	}
+	entities, err := s.eus.ToEntities(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	d.Character = entities[charID]
+	d.Corporation = entities[corpID]
+	if invokingCharID != 0 {
+		d.InvokingCharacter = entities[invokingCharID]
+	}
+	return d, nil
+}
+
+func (d CorporateData) render() (string, string) {
+	var title, body string
+	switch d.Type {
+	case CharAppAcceptMsg:
+		title = fmt.Sprintf(
 			"%s joins %s",
-			entities[data.CharID].Name,
-			entities[data.CorpID].Name,
-		))
-		out := fmt.Sprintf(
+			d.Character.Name,
+			d.Corporation.Name,
+		)
+		body = fmt.Sprintf(
 			"%s is now a member of %s.",
-			makeEveEntityProfileLink(entities[data.CorpID]),
-			makeEveEntityProfileLink(entities[data.CharID]),
+			makeEveEntityProfileLink(d.Corporation),
+			makeEveEntityProfileLink(d.Character),
 		)
-		body.Set(out)
 
 	case CorpAppNewMsg:
-		var data notification.CorpAppNewMsg
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.CharID, data.CorpID})
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("New application from %s", entities[data.CharID].Name))
-		out := fmt.Sprintf(
+		title = fmt.Sprintf("New application from %s", d.Character.Name)
+		body = fmt.Sprintf(
 			"New application from %s to join %s:\n\n> %s",
-			makeEveEntityProfileLink(entities[data.CharID]),
-			makeEveEntityProfileLink(entities[data.CorpID]),
-			data.ApplicationText,
+			makeEveEntityProfileLink(d.Character),
+			makeEveEntityProfileLink(d.Corporation),
+			d.ApplicationText,
 		)
-		body.Set(out)
 
 	case CorpAppInvitedMsg:
-		var data notification.CorpAppInvitedMsg
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.CharID, data.CorpID, data.InvokingCharID})
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("%s has been invited", entities[data.CharID].Name))
-		out := fmt.Sprintf(
+		title = fmt.Sprintf("%s has been invited", d.Character.Name)
+		body = fmt.Sprintf(
 			"%s has been invited to join %s by %s:\n\n> %s",
-			makeEveEntityProfileLink(entities[data.CharID]),
-			makeEveEntityProfileLink(entities[data.CorpID]),
-			makeEveEntityProfileLink(entities[data.InvokingCharID]),
-			data.ApplicationText,
+			makeEveEntityProfileLink(d.Character),
+			makeEveEntityProfileLink(d.Corporation),
+			makeEveEntityProfileLink(d.InvokingCharacter),
+			d.ApplicationText,
 		)
-		body.Set(out)
 
 	case CharAppRejectMsg:
-		var data notification.CharAppRejectMsg
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.CharID, data.CorpID})
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("%s rejected invitation", entities[data.CharID].Name))
-		out := fmt.Sprintf(
+		title = fmt.Sprintf("%s rejected invitation", d.Character.Name)
+		body = fmt.Sprintf(
 			"Application from %s to join %s has been rejected:\n\n> %s",
-			makeEveEntityProfileLink(entities[data.CharID]),
-			makeEveEntityProfileLink(entities[data.CorpID]),
-			data.ApplicationText,
+			makeEveEntityProfileLink(d.Character),
+			makeEveEntityProfileLink(d.Corporation),
+			d.ApplicationText,
 		)
-		body.Set(out)
 
 	case CorpAppRejectCustomMsg:
-		var data notification.CorpAppRejectCustomMsg
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.CharID, data.CorpID})
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("Application from %s rejected", entities[data.CharID].Name))
-		out := fmt.Sprintf(
+		title = fmt.Sprintf("Application from %s rejected", d.Character.Name)
+		body = fmt.Sprintf(
 			"%s has rejected application from %s:\n\n>%s",
-			makeEveEntityProfileLink(entities[data.CorpID]),
-			makeEveEntityProfileLink(entities[data.CharID]),
-			data.ApplicationText,
+			makeEveEntityProfileLink(d.Corporation),
+			makeEveEntityProfileLink(d.Character),
+			d.ApplicationText,
 		)
-		if data.CustomMessage != "" {
-			out += fmt.Sprintf("\n\nReply:\n\n>%s", data.CustomMessage)
+		if d.CustomMessage != "" {
+			body += fmt.Sprintf("\n\nReply:\n\n>%s", d.CustomMessage)
 		}
-		body.Set(out)
 
 	case CharAppWithdrawMsg:
-		var data notification.CharAppWithdrawMsg
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.CharID, data.CorpID})
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("%s withdrew application", entities[data.CharID].Name))
-		out := fmt.Sprintf(
+		title = fmt.Sprintf("%s withdrew application", d.Character.Name)
+		body = fmt.Sprintf(
 			"%s has withdrawn application to join %s:\n\n>%s",
-			makeEveEntityProfileLink(entities[data.CorpID]),
-			makeEveEntityProfileLink(entities[data.CharID]),
-			data.ApplicationText,
+			makeEveEntityProfileLink(d.Corporation),
+			makeEveEntityProfileLink(d.Character),
+			d.ApplicationText,
 		)
-		body.Set(out)
 
 	case CharLeftCorpMsg:
-		var data notification.CharLeftCorpMsg
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.CharID, data.CorpID})
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf(
+		title = fmt.Sprintf(
 			"%s left %s",
-			entities[data.CharID].Name,
-			entities[data.CorpID].Name,
-		))
-		out := fmt.Sprintf(
+			d.Character.Name,
+			d.Corporation.Name,
+		)
+		body = fmt.Sprintf(
 			"%s is no longer a member of %s.",
-			makeEveEntityProfileLink(entities[data.CorpID]),
-			makeEveEntityProfileLink(entities[data.CharID]),
+			makeEveEntityProfileLink(d.Corporation),
+			makeEveEntityProfileLink(d.Character),
 		)
-		body.Set(out)
 	}
-	return title, body, nil
+	return title, body
 }
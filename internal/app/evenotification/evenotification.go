@@ -5,6 +5,8 @@ package evenotification
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
@@ -31,16 +33,60 @@ func New(eus EveUniverseService) *EveNotificationService {
 	return s
 }
 
-// RenderESI renders title and body for all supported notification types and returns them.
-// Returns empty title and body for unsupported notification types.
-func (s *EveNotificationService) RenderESI(ctx context.Context, type_, text string, timestamp time.Time) (optional.Optional[string], optional.Optional[string], error) {
+// Data is the structured data of a supported notification type.
+//
+// The concrete types are: [BillingData], [BountyData], [CloneData], [CorporateData],
+// [InsuranceData], [KillData], [MoonMiningEventData], [OrbitalData], [SovData],
+// [StructureData], [TowerData] and [WarData].
+type Data interface {
+	// Base returns the data common to all notifications.
+	Base() BaseData
+	// Entities returns all entities involved in a notification.
+	Entities() []*app.EveEntity
+	// render returns title and body of a notification in markdown.
+	render() (title string, body string)
+}
+
+// BaseData contains the data common to all notifications.
+type BaseData struct {
+	Type        Type
+	Timestamp   time.Time
+	SolarSystem *app.EveSolarSystem // nil when not applicable
+}
+
+func (b BaseData) Base() BaseData {
+	return b
+}
+
+// DataESI returns the structured data for a notification from ESI.
+// Returns [app.ErrNotFound] for unsupported notification types.
+func (s *EveNotificationService) DataESI(ctx context.Context, type_, text string, timestamp time.Time) (Data, error) {
+	base := BaseData{Type: Type(type_), Timestamp: timestamp}
 	switch t := Type(type_); t {
 	case BillOutOfMoneyMsg,
 		BillPaidCorpAllMsg,
 		CorpAllBillMsg,
 		InfrastructureHubBillAboutToExpire,
 		IHubDestroyedByBillFailure:
-		return s.renderBilling(ctx, t, text)
+		return s.dataBilling(ctx, base, text)
+
+	case BountyClaimMsg,
+		BountyESSShared,
+		BountyESSTaken,
+		BountyPlacedAlliance,
+		BountyPlacedChar,
+		BountyPlacedCorp,
+		BountyYourBountyClaimed:
+		return s.dataBounty(ctx, base, text)
+
+	case CloneActivationMsg,
+		CloneActivationMsg2,
+		CloneMovedMsg,
+		CloneRevokedMsg1,
+		CloneRevokedMsg2,
+		JumpCloneDeletedMsg1,
+		JumpCloneDeletedMsg2:
+		return s.dataClone(ctx, base, text)
 
 	case CharAppAcceptMsg,
 		CharAppRejectMsg,
@@ -49,18 +95,35 @@ func (s *EveNotificationService) RenderESI(ctx context.Context, type_, text stri
 		CorpAppInvitedMsg,
 		CorpAppNewMsg,
 		CorpAppRejectCustomMsg:
-		return s.renderCorporate(ctx, t, text)
+		return s.dataCorporate(ctx, base, text)
+
+	case InsuranceExpirationMsg,
+		InsuranceFirstShipMsg,
+		InsuranceInvalidatedMsg,
+		InsuranceIssuedMsg,
+		InsurancePayoutMsg:
+		return s.dataInsurance(ctx, base, text)
+
+	case KillReportFinalBlow,
+		KillReportVictim,
+		KillRightAvailable,
+		KillRightAvailableOpen,
+		KillRightEarned,
+		KillRightUnavailable,
+		KillRightUnavailableOpen,
+		KillRightUsed:
+		return s.dataKill(ctx, base, text)
 
 	case OrbitalAttacked,
 		OrbitalReinforced:
-		return s.renderOrbital(ctx, t, text)
+		return s.dataOrbital(ctx, base, text)
 
 	case MoonminingExtractionStarted,
 		MoonminingExtractionFinished,
 		MoonminingAutomaticFracture,
 		MoonminingExtractionCancelled,
 		MoonminingLaserFired:
-		return s.renderMoonMining(ctx, t, text)
+		return s.dataMoonMining(ctx, base, text)
 
 	case OwnershipTransferred,
 		StructureAnchoring,
@@ -78,11 +141,11 @@ func (s *EveNotificationService) RenderESI(ctx context.Context, type_, text stri
 		StructureUnderAttack,
 		StructureWentHighPower,
 		StructureWentLowPower:
-		return s.renderStructure(ctx, t, text, timestamp)
+		return s.dataStructure(ctx, base, text)
 
 	case TowerAlertMsg,
 		TowerResourceAlertMsg:
-		return s.renderTower(ctx, t, text)
+		return s.dataTower(ctx, base, text)
 	case AllWarSurrenderMsg,
 		CorpWarSurrenderMsg,
 		DeclareWar,
@@ -92,14 +155,41 @@ func (s *EveNotificationService) RenderESI(ctx context.Context, type_, text stri
 		WarInherited,
 		WarInvalid,
 		WarRetractedByConcord:
-		return s.renderWar(ctx, t, text)
+		return s.dataWar(ctx, base, text)
 	case EntosisCaptureStarted,
 		SovAllClaimAcquiredMsg,
 		SovAllClaimLostMsg,
 		SovCommandNodeEventStarted,
 		SovStructureDestroyed,
 		SovStructureReinforced:
-		return s.renderSov(ctx, t, text)
+		return s.dataSov(ctx, base, text)
+	}
+	return nil, fmt.Errorf("unsupported notification type: %s: %w", type_, app.ErrNotFound)
+}
+
+// RenderESI renders title and body for all supported notification types and returns them.
+// Returns empty title and body for unsupported notification types.
+func (s *EveNotificationService) RenderESI(ctx context.Context, type_, text string, timestamp time.Time) (optional.Optional[string], optional.Optional[string], error) {
+	var title, body optional.Optional[string]
+	d, err := s.DataESI(ctx, type_, text, timestamp)
+	if errors.Is(err, app.ErrNotFound) {
+		return title, body, nil
+	} else if err != nil {
+		return title, body, err
+	}
+	t, b := d.render()
+	title.Set(t)
+	body.Set(b)
+	return title, body, nil
+}
+
+// compactEntities returns the given entities without nils and unresolved entities.
+func compactEntities(ee ...*app.EveEntity) []*app.EveEntity {
+	r := make([]*app.EveEntity, 0, len(ee))
+	for _, e := range ee {
+		if e != nil && e.ID != 0 {
+			r = append(r, e)
+		}
 	}
-	return optional.Optional[string]{}, optional.Optional[string]{}, nil
+	return r
 }
@@ -72,6 +72,15 @@ func TestShouldRenderAllNotifications(t *testing.T) {
 	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 32226}) // TCU
 	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 27})
 	factory.CreateEveEntity(app.EveEntity{ID: 60003760, Category: app.EveEntityStation})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 587})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 603})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 3300})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 9899})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 9941})
+	factory.CreateEveLocationStructure(storage.UpdateOrCreateLocationParams{
+		ID:               60003760,
+		EveSolarSystemID: optional.New(solarSystem.ID),
+	})
 	notifTypes := set.NewFromSlice(evenotification.SupportedGroups())
 	typeTested := make(map[evenotification.Type]bool)
 	for _, n := range notifications {
@@ -97,3 +106,54 @@ func TestShouldRenderAllNotifications(t *testing.T) {
 		}
 	}
 }
+
+func TestDataESI(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	eu := eveuniverseservice.New(st, nil)
+	en := evenotification.New(eu)
+	ctx := context.Background()
+	t.Run("should return structured data for supported type", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		solarSystem := factory.CreateEveSolarSystem(storage.CreateEveSolarSystemParams{ID: 30002537})
+		structureType := factory.CreateEveType(storage.CreateEveTypeParams{ID: 35835})
+		factory.CreateEveLocationStructure(storage.UpdateOrCreateLocationParams{
+			ID:               1000000000001,
+			EveSolarSystemID: optional.New(solarSystem.ID),
+			EveTypeID:        optional.New(structureType.ID),
+		})
+		text := `solarsystemID: 30002537
+structureID: &id001 1000000000001
+structureShowInfoData:
+- showinfo
+- 35835
+- *id001
+structureTypeID: 35835
+timeLeft: 1727805401093
+timestamp: 132148470780000000
+vulnerableTime: 9000000000
+`
+		timestamp := time.Date(2019, 10, 4, 14, 52, 0, 0, time.UTC)
+		// when
+		x, err := en.DataESI(ctx, string(evenotification.StructureLostShields), text, timestamp)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, evenotification.StructureLostShields, x.Base().Type)
+			assert.Equal(t, timestamp, x.Base().Timestamp)
+			assert.Equal(t, solarSystem.ID, x.Base().SolarSystem.ID)
+			d, ok := x.(evenotification.StructureData)
+			if assert.True(t, ok) {
+				assert.Equal(t, int64(1000000000001), d.Structure.ID)
+				assert.Equal(t, structureType.ID, d.Structure.Type.ID)
+				assert.Equal(t, time.Date(2019, 10, 6, 14, 51, 18, 0, time.UTC), d.ExitsAt.UTC())
+			}
+		}
+	})
+	t.Run("should return not found for unsupported type", func(t *testing.T) {
+		_, err := en.DataESI(ctx, "AllAnchoringMsg", "", time.Now())
+		assert.ErrorIs(t, err, app.ErrNotFound)
+	})
+}
@@ -0,0 +1,85 @@
+package evenotification_test
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/app/eveuniverseservice"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// TestRenderGolden compares rendered notifications with the golden files in testdata/golden.
+// Run with -update to re-create the golden files.
+func TestRenderGolden(t *testing.T) {
+	data, err := os.ReadFile("testdata/notifications.json")
+	if err != nil {
+		panic(err)
+	}
+	notifications := make([]notification, 0)
+	if err := json.Unmarshal(data, &notifications); err != nil {
+		panic(err)
+	}
+	db, st, factory := testutil.New()
+	defer db.Close()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	eu := eveuniverseservice.New(st, nil)
+	en := evenotification.New(eu)
+	ctx := context.Background()
+	solarSystem := factory.CreateEveSolarSystem(storage.CreateEveSolarSystemParams{ID: 30002537, Name: "Amamake"})
+	factory.CreateEveLocationStructure(storage.UpdateOrCreateLocationParams{
+		ID:               60003760,
+		EveSolarSystemID: optional.New(solarSystem.ID),
+		Name:             "Jita IV - Moon 4 - Caldari Navy Assembly Plant",
+	})
+	factory.CreateEveLocationStructure(storage.UpdateOrCreateLocationParams{
+		ID:               1000000000001,
+		EveSolarSystemID: optional.New(solarSystem.ID),
+		Name:             "Amamake - Test Structure Alpha",
+	})
+	factory.CreateEveEntityCharacter(app.EveEntity{ID: 1001, Name: "Bruce Wayne"})
+	factory.CreateEveEntityCharacter(app.EveEntity{ID: 1011, Name: "Clark Kent"})
+	factory.CreateEveEntityCorporation(app.EveEntity{ID: 2001, Name: "Wayne Enterprises"})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 587, Name: "Rifter"})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 603, Name: "Merlin"})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 3300, Name: "Gunnery"})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 9899, Name: "Ocular Filter - Basic"})
+	factory.CreateEveEntityInventoryType(app.EveEntity{ID: 9941, Name: "Memory Augmentation - Basic"})
+	groups := set.New(app.GroupBounties, app.GroupClones, app.GroupInsurance, app.GroupKills)
+	for _, n := range notifications {
+		t2 := evenotification.Type(n.Type)
+		if !groups.Contains(evenotification.Type2group[t2]) {
+			continue
+		}
+		t.Run(n.Type, func(t *testing.T) {
+			title, body, err := en.RenderESI(ctx, n.Type, n.Text, n.Timestamp)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got := title.ValueOrZero() + "\n\n" + body.ValueOrZero() + "\n"
+			path := filepath.Join("testdata", "golden", n.Type+".md")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if assert.NoError(t, err) {
+				assert.Equal(t, string(want), got)
+			}
+		})
+	}
+}
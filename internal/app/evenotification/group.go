@@ -26,13 +26,13 @@ var Type2group = map[Type]app.NotificationGroup{
 	BattlePunishFriendlyFire:                  app.GroupUnknown,
 	BillOutOfMoneyMsg:                         app.GroupBills,
 	BillPaidCorpAllMsg:                        app.GroupBills,
-	BountyClaimMsg:                            app.GroupUnknown,
-	BountyESSShared:                           app.GroupUnknown,
-	BountyESSTaken:                            app.GroupUnknown,
-	BountyPlacedAlliance:                      app.GroupUnknown,
-	BountyPlacedChar:                          app.GroupUnknown,
-	BountyPlacedCorp:                          app.GroupUnknown,
-	BountyYourBountyClaimed:                   app.GroupUnknown,
+	BountyClaimMsg:                            app.GroupBounties,
+	BountyESSShared:                           app.GroupBounties,
+	BountyESSTaken:                            app.GroupBounties,
+	BountyPlacedAlliance:                      app.GroupBounties,
+	BountyPlacedChar:                          app.GroupBounties,
+	BountyPlacedCorp:                          app.GroupBounties,
+	BountyYourBountyClaimed:                   app.GroupBounties,
 	BuddyConnectContactAdd:                    app.GroupUnknown,
 	CharAppAcceptMsg:                          app.GroupCorporate,
 	CharAppRejectMsg:                          app.GroupCorporate,
@@ -40,11 +40,11 @@ var Type2group = map[Type]app.NotificationGroup{
 	CharLeftCorpMsg:                           app.GroupCorporate,
 	CharMedalMsg:                              app.GroupCorporate,
 	CharTerminationMsg:                        app.GroupCorporate,
-	CloneActivationMsg:                        app.GroupUnknown,
-	CloneActivationMsg2:                       app.GroupUnknown,
-	CloneMovedMsg:                             app.GroupUnknown,
-	CloneRevokedMsg1:                          app.GroupUnknown,
-	CloneRevokedMsg2:                          app.GroupUnknown,
+	CloneActivationMsg:                        app.GroupClones,
+	CloneActivationMsg2:                       app.GroupClones,
+	CloneMovedMsg:                             app.GroupClones,
+	CloneRevokedMsg1:                          app.GroupClones,
+	CloneRevokedMsg2:                          app.GroupClones,
 	CombatOperationFinished:                   app.GroupUnknown,
 	ContactAdd:                                app.GroupContacts,
 	ContactEdit:                               app.GroupContacts,
@@ -119,24 +119,24 @@ var Type2group = map[Type]app.NotificationGroup{
 	IndustryTeamAuctionLost:                   app.GroupUnknown,
 	IndustryTeamAuctionWon:                    app.GroupUnknown,
 	InfrastructureHubBillAboutToExpire:        app.GroupSovereignty,
-	InsuranceExpirationMsg:                    app.GroupUnknown,
-	InsuranceFirstShipMsg:                     app.GroupUnknown,
-	InsuranceInvalidatedMsg:                   app.GroupUnknown,
-	InsuranceIssuedMsg:                        app.GroupUnknown,
-	InsurancePayoutMsg:                        app.GroupUnknown,
+	InsuranceExpirationMsg:                    app.GroupInsurance,
+	InsuranceFirstShipMsg:                     app.GroupInsurance,
+	InsuranceInvalidatedMsg:                   app.GroupInsurance,
+	InsuranceIssuedMsg:                        app.GroupInsurance,
+	InsurancePayoutMsg:                        app.GroupInsurance,
 	InvasionCompletedMsg:                      app.GroupUnknown,
 	InvasionSystemLogin:                       app.GroupUnknown,
 	InvasionSystemStart:                       app.GroupUnknown,
-	JumpCloneDeletedMsg1:                      app.GroupUnknown,
-	JumpCloneDeletedMsg2:                      app.GroupUnknown,
-	KillReportFinalBlow:                       app.GroupUnknown,
-	KillReportVictim:                          app.GroupUnknown,
-	KillRightAvailable:                        app.GroupUnknown,
-	KillRightAvailableOpen:                    app.GroupUnknown,
-	KillRightEarned:                           app.GroupUnknown,
-	KillRightUnavailable:                      app.GroupUnknown,
-	KillRightUnavailableOpen:                  app.GroupUnknown,
-	KillRightUsed:                             app.GroupUnknown,
+	JumpCloneDeletedMsg1:                      app.GroupClones,
+	JumpCloneDeletedMsg2:                      app.GroupClones,
+	KillReportFinalBlow:                       app.GroupKills,
+	KillReportVictim:                          app.GroupKills,
+	KillRightAvailable:                        app.GroupKills,
+	KillRightAvailableOpen:                    app.GroupKills,
+	KillRightEarned:                           app.GroupKills,
+	KillRightUnavailable:                      app.GroupKills,
+	KillRightUnavailableOpen:                  app.GroupKills,
+	KillRightUsed:                             app.GroupKills,
 	LPAutoRedeemed:                            app.GroupUnknown,
 	LocateCharMsg:                             app.GroupUnknown,
 	MadeWarMutual:                             app.GroupUnknown,
@@ -0,0 +1,164 @@
+package evenotification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/antihax/goesi/notification"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v3"
+)
+
+// InsuranceData is the structured data of a notification about ship insurance.
+type InsuranceData struct {
+	BaseData
+	Amount   float64 // payout in ISK
+	EndsAt   time.Time
+	Level    float64 // insurance coverage in percent
+	Owner    *app.EveEntity
+	ShipName string
+	ShipType *app.EveEntity
+	StartsAt time.Time
+	Weeks    int
+}
+
+// The goesi types for these notifications define item IDs as int32,
+// which is too small for real item IDs.
+
+type insuranceExpirationMsg struct {
+	EndDate   int64  `yaml:"endDate"`
+	ShipID    int64  `yaml:"shipID"`
+	ShipName  string `yaml:"shipName"`
+	StartDate int64  `yaml:"startDate"`
+}
+
+type insuranceInvalidatedMsg struct {
+	EndDate   int64 `yaml:"endDate"`
+	OwnerID   int32 `yaml:"ownerID"`
+	Reason    int32 `yaml:"reason"`
+	ShipID    int64 `yaml:"shipID"`
+	StartDate int64 `yaml:"startDate"`
+	TypeID    int32 `yaml:"typeID"`
+}
+
+type insurancePayoutMsg struct {
+	Amount float64 `yaml:"amount"`
+	ItemID int64   `yaml:"itemID"`
+	Payout int32   `yaml:"payout"`
+}
+
+func (d InsuranceData) Entities() []*app.EveEntity {
+	return compactEntities(d.Owner, d.ShipType)
+}
+
+func (s *EveNotificationService) dataInsurance(ctx context.Context, base BaseData, text string) (Data, error) {
+	var ownerID, shipTypeID int32
+	d := InsuranceData{BaseData: base}
+	switch base.Type {
+	case InsuranceExpirationMsg:
+		var data insuranceExpirationMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		d.EndsAt = fromLDAPTime(data.EndDate)
+		d.ShipName = data.ShipName
+		d.StartsAt = fromLDAPTime(data.StartDate)
+
+	case InsuranceFirstShipMsg:
+		var data notification.InsuranceFirstShipMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		shipTypeID = data.ShipTypeID
+
+	case InsuranceInvalidatedMsg:
+		var data insuranceInvalidatedMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		ownerID = data.OwnerID
+		shipTypeID = data.TypeID
+		d.EndsAt = fromLDAPTime(data.EndDate)
+		d.StartsAt = fromLDAPTime(data.StartDate)
+
+	case InsuranceIssuedMsg:
+		var data notification.InsuranceIssuedMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		shipTypeID = data.TypeID
+		d.EndsAt = fromLDAPTime(data.EndDate)
+		d.Level = data.Level
+		d.ShipName = data.ShipName
+		d.StartsAt = fromLDAPTime(data.StartDate)
+		d.Weeks = int(data.NumWeeks)
+
+	case InsurancePayoutMsg:
+		var data insurancePayoutMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		d.Amount = data.Amount
+	}
+	entities, err := s.eus.ToEntities(ctx, []int32{ownerID, shipTypeID})
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != 0 {
+		d.Owner = entities[ownerID]
+	}
+	if shipTypeID != 0 {
+		d.ShipType = entities[shipTypeID]
+	}
+	return d, nil
+}
+
+func (d InsuranceData) render() (string, string) {
+	var title, body string
+	switch d.Type {
+	case InsuranceExpirationMsg:
+		title = fmt.Sprintf("Insurance for %s expires soon", d.ShipName)
+		body = fmt.Sprintf(
+			"The insurance contract for your ship **%s** will expire at %s.",
+			d.ShipName,
+			d.EndsAt.Format(app.DateTimeFormat),
+		)
+
+	case InsuranceFirstShipMsg:
+		title = "Your new ship has been insured"
+		body = fmt.Sprintf(
+			"Your new %s has been insured free of charge.",
+			entityNameOrUnknown(d.ShipType),
+		)
+
+	case InsuranceInvalidatedMsg:
+		title = fmt.Sprintf("Insurance for %s has been invalidated", entityNameOrUnknown(d.ShipType))
+		body = fmt.Sprintf(
+			"The insurance contract for a %s owned by %s has been invalidated.",
+			entityNameOrUnknown(d.ShipType),
+			makeEveEntityProfileLink(d.Owner),
+		)
+
+	case InsuranceIssuedMsg:
+		title = fmt.Sprintf("Insurance issued for %s", d.ShipName)
+		body = fmt.Sprintf(
+			"A new insurance contract with a coverage of %.0f%% has been issued for your %s **%s**. "+
+				"It is valid for %d weeks until %s.",
+			d.Level,
+			entityNameOrUnknown(d.ShipType),
+			d.ShipName,
+			d.Weeks,
+			d.EndsAt.Format(app.DateTimeFormat),
+		)
+
+	case InsurancePayoutMsg:
+		title = "Insurance payout received"
+		body = fmt.Sprintf(
+			"You have received an insurance payout of **%s** ISK for the loss of your ship.",
+			humanize.Commaf(d.Amount),
+		)
+	}
+	return title, body
+}
@@ -0,0 +1,184 @@
+package evenotification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/antihax/goesi/notification"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v3"
+)
+
+// KillData is the structured data of a notification about kill reports and kill rights.
+type KillData struct {
+	BaseData
+	Character    *app.EveEntity // the victim or the character a kill right is for
+	KillmailHash string
+	KillmailID   int32
+	Price        float64        // price of a kill right in ISK
+	ShipType     *app.EveEntity // ship of the victim
+	ToEntity     *app.EveEntity // who a kill right is made available to
+}
+
+func (d KillData) Entities() []*app.EveEntity {
+	return compactEntities(d.Character, d.ShipType, d.ToEntity)
+}
+
+func (s *EveNotificationService) dataKill(ctx context.Context, base BaseData, text string) (Data, error) {
+	var characterID, shipTypeID, toEntityID int32
+	d := KillData{BaseData: base}
+	switch base.Type {
+	case KillReportFinalBlow:
+		var data notification.KillReportFinalBlow
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.VictimID
+		shipTypeID = data.VictimShipTypeID
+		d.KillmailHash = data.KillMailHash
+		d.KillmailID = data.KillMailID
+
+	case KillReportVictim:
+		var data notification.KillReportVictim
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		shipTypeID = data.VictimShipTypeID
+		d.KillmailHash = data.KillMailHash
+		d.KillmailID = data.KillMailID
+
+	case KillRightAvailable:
+		var data notification.KillRightAvailable
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+		toEntityID = data.ToEntityID
+		d.Price = data.Price
+
+	case KillRightAvailableOpen:
+		var data notification.KillRightAvailableOpen
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+		d.Price = data.Price
+
+	case KillRightEarned:
+		var data notification.KillRightEarned
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+
+	case KillRightUnavailable:
+		var data notification.KillRightUnavailable
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+		toEntityID = data.ToEntityID
+
+	case KillRightUnavailableOpen:
+		var data notification.KillRightUnavailableOpen
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+
+	case KillRightUsed:
+		var data notification.KillRightUsed
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		characterID = data.CharID
+	}
+	entities, err := s.eus.ToEntities(ctx, []int32{characterID, shipTypeID, toEntityID})
+	if err != nil {
+		return nil, err
+	}
+	if characterID != 0 {
+		d.Character = entities[characterID]
+	}
+	if shipTypeID != 0 {
+		d.ShipType = entities[shipTypeID]
+	}
+	if toEntityID != 0 {
+		d.ToEntity = entities[toEntityID]
+	}
+	return d, nil
+}
+
+func (d KillData) render() (string, string) {
+	var title, body string
+	switch d.Type {
+	case KillReportFinalBlow:
+		title = fmt.Sprintf(
+			"Final blow on %s's %s",
+			entityNameOrUnknown(d.Character),
+			entityNameOrUnknown(d.ShipType),
+		)
+		body = fmt.Sprintf(
+			"You have scored the final blow on the %s of %s.\n\n%s",
+			entityNameOrUnknown(d.ShipType),
+			makeEveEntityProfileLink(d.Character),
+			makeKillmailLink(d.KillmailID),
+		)
+
+	case KillReportVictim:
+		title = fmt.Sprintf("Lost a %s", entityNameOrUnknown(d.ShipType))
+		body = fmt.Sprintf(
+			"Your %s has been destroyed.\n\n%s",
+			entityNameOrUnknown(d.ShipType),
+			makeKillmailLink(d.KillmailID),
+		)
+
+	case KillRightAvailable:
+		title = fmt.Sprintf("Kill right on %s available", entityNameOrUnknown(d.Character))
+		body = fmt.Sprintf(
+			"A kill right on %s has been made available to %s for **%s** ISK.",
+			makeEveEntityProfileLink(d.Character),
+			makeEveEntityProfileLink(d.ToEntity),
+			humanize.Commaf(d.Price),
+		)
+
+	case KillRightAvailableOpen:
+		title = fmt.Sprintf("Kill right on %s available", entityNameOrUnknown(d.Character))
+		body = fmt.Sprintf(
+			"A kill right on %s has been made available to everyone for **%s** ISK.",
+			makeEveEntityProfileLink(d.Character),
+			humanize.Commaf(d.Price),
+		)
+
+	case KillRightEarned:
+		title = fmt.Sprintf("Kill right earned on %s", entityNameOrUnknown(d.Character))
+		body = fmt.Sprintf(
+			"You have earned a kill right on %s.",
+			makeEveEntityProfileLink(d.Character),
+		)
+
+	case KillRightUnavailable:
+		title = fmt.Sprintf("Kill right on %s no longer available", entityNameOrUnknown(d.Character))
+		body = fmt.Sprintf(
+			"The kill right on %s is no longer available to %s.",
+			makeEveEntityProfileLink(d.Character),
+			makeEveEntityProfileLink(d.ToEntity),
+		)
+
+	case KillRightUnavailableOpen:
+		title = fmt.Sprintf("Kill right on %s no longer available", entityNameOrUnknown(d.Character))
+		body = fmt.Sprintf(
+			"The kill right on %s is no longer available to everyone.",
+			makeEveEntityProfileLink(d.Character),
+		)
+
+	case KillRightUsed:
+		title = fmt.Sprintf("Kill right on %s used", entityNameOrUnknown(d.Character))
+		body = fmt.Sprintf(
+			"Your kill right on %s has been used.",
+			makeEveEntityProfileLink(d.Character),
+		)
+	}
+	return title, body
+}
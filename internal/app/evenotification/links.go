@@ -51,6 +51,40 @@ func makeEveEntityProfileLink(e *app.EveEntity) string {
 	return makeMarkDownLink(e.Name, url)
 }
 
+func makeKillmailLink(id int32) string {
+	return makeMarkDownLink("Killmail", fmt.Sprintf("https://zkillboard.com/kill/%d/", id))
+}
+
 func makeMarkDownLink(label, url string) string {
 	return fmt.Sprintf("[%s](%s)", label, url)
 }
+
+// entityNameOrUnknown returns the name of an entity or "?" when it has no name.
+func entityNameOrUnknown(e *app.EveEntity) string {
+	if e == nil || e.Name == "" {
+		return "?"
+	}
+	return e.Name
+}
+
+// makeAggressorText returns a text describing the attacking entities.
+func makeAggressorText(character, corporation, alliance *app.EveEntity) string {
+	t := fmt.Sprintf(
+		"Attacking Character: %s\n\n"+
+			"Attacking Corporation: %s",
+		makeEveEntityProfileLink(character),
+		makeEveEntityProfileLink(corporation),
+	)
+	if alliance != nil {
+		t += fmt.Sprintf("\n\nAttacking Alliance: %s", makeEveEntityProfileLink(alliance))
+	}
+	return t
+}
+
+// entityName returns the name of an entity or an empty string when it is nil.
+func entityName(e *app.EveEntity) string {
+	if e == nil {
+		return ""
+	}
+	return e.Name
+}
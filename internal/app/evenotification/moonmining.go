@@ -7,196 +7,243 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
-	"github.com/ErikKalkoken/evebuddy/internal/optional"
 	"github.com/antihax/goesi/notification"
 	"github.com/dustin/go-humanize"
 	"gopkg.in/yaml.v3"
 )
 
-func (s *EveNotificationService) renderMoonMining(ctx context.Context, type_ Type, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	switch type_ {
-	case MoonminingAutomaticFracture:
-		var data notification.MoonminingAutomaticFracture
+// MoonMiningEventData is the structured data of a moon mining notification.
+type MoonMiningEventData struct {
+	BaseData
+	AutoFractureAt time.Time      // zero when not included in notification
+	Character      *app.EveEntity // who cancelled the extraction or fired the drill. Nil when unknown.
+	ChunkArrivalAt time.Time      // zero when not included in notification
+	Moon           *app.EveMoon
+	Ores           []OreVolume // ordered by name
+	StructureName  string
+}
+
+// OreVolume is the estimated volume of an ore type in a moon chunk.
+type OreVolume struct {
+	Type   *app.EveEntity
+	Volume float64 // in m3
+}
+
+func (d MoonMiningEventData) Entities() []*app.EveEntity {
+	return compactEntities(d.Character)
+}
+
+func (s *EveNotificationService) dataMoonMining(ctx context.Context, base BaseData, text string) (Data, error) {
+	x, err := ParseMoonMining(base.Type, text)
+	if err != nil {
+		return nil, err
+	}
+	var characterID int32
+	switch base.Type {
+	case MoonminingExtractionCancelled:
+		var data notification.MoonminingExtractionCancelled
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
+			return nil, err
 		}
-		o, err := s.makeMoonMiningBaseText(ctx, data.MoonID, data.StructureName)
-		if err != nil {
-			return title, body, err
+		characterID = data.CancelledBy
+	case MoonminingLaserFired:
+		var data notification.MoonminingLaserFired
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
 		}
-		title.Set(fmt.Sprintf("Extraction for %s has autofractured", data.StructureName))
-		ores, err := s.makeOreText(ctx, data.OreVolumeByType)
+		characterID = data.FiredBy
+	}
+	moon, err := s.eus.GetOrCreateMoonESI(ctx, x.MoonID)
+	if err != nil {
+		return nil, err
+	}
+	d := MoonMiningEventData{
+		BaseData:       base,
+		AutoFractureAt: x.AutoFractureAt,
+		ChunkArrivalAt: x.ChunkArrivalAt,
+		Moon:           moon,
+		StructureName:  x.StructureName,
+	}
+	d.SolarSystem = moon.SolarSystem
+	if characterID != 0 {
+		c, err := s.eus.GetOrCreateEntityESI(ctx, characterID)
 		if err != nil {
-			return title, body, err
+			return nil, err
 		}
-		out := fmt.Sprintf("The extraction for %s "+
+		d.Character = c
+	}
+	ores, err := s.makeOreVolumes(ctx, x.OreVolumeByType)
+	if err != nil {
+		return nil, err
+	}
+	d.Ores = ores
+	return d, nil
+}
+
+func (s *EveNotificationService) makeOreVolumes(ctx context.Context, ores map[int32]float64) ([]OreVolume, error) {
+	ids := slices.Collect(maps.Keys(ores))
+	entities, err := s.eus.ToEntities(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]OreVolume, 0)
+	for id, v := range ores {
+		items = append(items, OreVolume{Type: entities[id], Volume: v})
+	}
+	slices.SortFunc(items, func(a, b OreVolume) int {
+		return cmp.Compare(a.Type.Name, b.Type.Name)
+	})
+	return items, nil
+}
+
+func (d MoonMiningEventData) render() (string, string) {
+	var title, body string
+	text := fmt.Sprintf(
+		"for **%s** at %s in %s",
+		d.StructureName,
+		d.Moon.Name,
+		makeSolarSystemLink(d.SolarSystem),
+	)
+	switch d.Type {
+	case MoonminingAutomaticFracture:
+		title = fmt.Sprintf("Extraction for %s has autofractured", d.StructureName)
+		body = fmt.Sprintf("The extraction for %s "+
 			"has reached the end of it's lifetime and has fractured automatically. The moon products are ready to be harvested.\n\n%s",
-			o.text,
-			ores,
+			text,
+			makeOreText(d.Ores),
 		)
-		body.Set(out)
 
 	case MoonminingExtractionStarted:
-		var data notification.MoonminingExtractionStarted
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		o, err := s.makeMoonMiningBaseText(ctx, data.MoonID, data.StructureName)
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("Extraction started at %s", data.StructureName))
-		ores, err := s.makeOreText(ctx, data.OreVolumeByType)
-		if err != nil {
-			return title, body, err
-		}
-		out := fmt.Sprintf("A moon mining extraction has been started %s.\n\n"+
+		title = fmt.Sprintf("Extraction started at %s", d.StructureName)
+		body = fmt.Sprintf("A moon mining extraction has been started %s.\n\n"+
 			"The chunk will be ready on location at %s, "+
 			"and will fracture automatically on %s.\n\n%s",
-			o.text,
-			fromLDAPTime(data.ReadyTime).Format(app.DateTimeFormat),
-			fromLDAPTime(data.AutoTime).Format(app.DateTimeFormat),
-			ores,
+			text,
+			d.ChunkArrivalAt.Format(app.DateTimeFormat),
+			d.AutoFractureAt.Format(app.DateTimeFormat),
+			makeOreText(d.Ores),
 		)
-		body.Set(out)
 
 	case MoonminingExtractionFinished:
-		var data notification.MoonminingExtractionFinished
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		o, err := s.makeMoonMiningBaseText(ctx, data.MoonID, data.StructureName)
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("Extraction finished at %s", data.StructureName))
-		ores, err := s.makeOreText(ctx, data.OreVolumeByType)
-		if err != nil {
-			return title, body, err
-		}
-		out := fmt.Sprintf("The extraction %s "+
+		title = fmt.Sprintf("Extraction finished at %s", d.StructureName)
+		body = fmt.Sprintf("The extraction %s "+
 			"is finished and the chunk is ready to be shot at.\n\n"+
 			"The chunk will automatically fracture on %s.\n\n%s",
-			o.text,
-			fromLDAPTime(data.AutoTime).Format(app.DateTimeFormat),
-			ores,
+			text,
+			d.AutoFractureAt.Format(app.DateTimeFormat),
+			makeOreText(d.Ores),
 		)
-		body.Set(out)
 
 	case MoonminingExtractionCancelled:
-		var data notification.MoonminingExtractionCancelled
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		o, err := s.makeMoonMiningBaseText(ctx, data.MoonID, data.StructureName)
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("Extraction canceled at %s", data.StructureName))
+		title = fmt.Sprintf("Extraction canceled at %s", d.StructureName)
 		cancelledBy := ""
-		if data.CancelledBy != 0 {
-			x, err := s.eus.GetOrCreateEntityESI(ctx, data.CancelledBy)
-			if err != nil {
-				return title, body, err
-			}
-			cancelledBy = fmt.Sprintf(" by %s", makeEveEntityProfileLink(x))
+		if d.Character != nil {
+			cancelledBy = fmt.Sprintf(" by %s", makeEveEntityProfileLink(d.Character))
 		}
-		out := fmt.Sprintf(
+		body = fmt.Sprintf(
 			"An ongoing extraction for %s has been cancelled%s.",
-			o.text,
+			text,
 			cancelledBy,
 		)
-		body.Set(out)
 
 	case MoonminingLaserFired:
-		var data notification.MoonminingLaserFired
-		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		o, err := s.makeMoonMiningBaseText(ctx, data.MoonID, data.StructureName)
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf("%s has fired it's moon drill", data.StructureName))
+		title = fmt.Sprintf("%s has fired it's moon drill", d.StructureName)
 		firedBy := ""
-		if data.FiredBy != 0 {
-			x, err := s.eus.GetOrCreateEntityESI(ctx, data.FiredBy)
-			if err != nil {
-				return title, body, err
-			}
-			firedBy = fmt.Sprintf("by %s ", makeEveEntityProfileLink(x))
-		}
-
-		ores, err := s.makeOreText(ctx, data.OreVolumeByType)
-		if err != nil {
-			return title, body, err
+		if d.Character != nil {
+			firedBy = fmt.Sprintf("by %s ", makeEveEntityProfileLink(d.Character))
 		}
-		out := fmt.Sprintf(
+		body = fmt.Sprintf(
 			"The moon drill fitted to %s has been fired %s"+
 				"and the moon products are ready to be harvested.\n\n%s",
-			o.text,
+			text,
 			firedBy,
-			ores,
+			makeOreText(d.Ores),
 		)
-		body.Set(out)
 	}
-	return title, body, nil
-}
-
-type moonMiningInfo struct {
-	moon *app.EveMoon
-	text string
+	return title, body
 }
 
-func (s *EveNotificationService) makeMoonMiningBaseText(ctx context.Context, moonID int32, structureName string) (moonMiningInfo, error) {
-	moon, err := s.eus.GetOrCreateMoonESI(ctx, moonID)
-	if err != nil {
-		return moonMiningInfo{}, err
-	}
-	text := fmt.Sprintf(
-		"for **%s** at %s in %s",
-		structureName,
-		moon.Name,
-		makeSolarSystemLink(moon.SolarSystem),
-	)
-	x := moonMiningInfo{
-		moon: moon,
-		text: text,
+func makeOreText(ores []OreVolume) string {
+	lines := []string{"Estimated ore composition:"}
+	for _, o := range ores {
+		text := fmt.Sprintf("%s: %s m3", o.Type.Name, humanize.Comma(int64(o.Volume)))
+		lines = append(lines, text)
 	}
-	return x, nil
+	return strings.Join(lines, "\n\n")
 }
 
-type oreItem struct {
-	id     int32
-	name   string
-	volume float64
+// MoonMiningData contains the structured data of a moon mining notification.
+type MoonMiningData struct {
+	AutoFractureAt  time.Time // zero when not included in notification
+	ChunkArrivalAt  time.Time // zero when not included in notification
+	MoonID          int32
+	OreVolumeByType map[int32]float64
+	StructureID     int64
+	StructureName   string
+	StructureTypeID int32
 }
 
-func (s *EveNotificationService) makeOreText(ctx context.Context, ores map[int32]float64) (string, error) {
-	ids := slices.Collect(maps.Keys(ores))
-	entities, err := s.eus.ToEntities(ctx, ids)
-	if err != nil {
-		return "", err
-	}
-	items := make([]oreItem, 0)
-	for id, v := range ores {
-		i := oreItem{
-			id:     id,
-			name:   entities[id].Name,
-			volume: v,
+// ParseMoonMining returns the structured data of a moon mining notification.
+func ParseMoonMining(type_ Type, text string) (MoonMiningData, error) {
+	var x MoonMiningData
+	switch type_ {
+	case MoonminingAutomaticFracture:
+		var data notification.MoonminingAutomaticFracture
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
 		}
-		items = append(items, i)
-	}
-	slices.SortFunc(items, func(a, b oreItem) int {
-		return cmp.Compare(a.name, b.name)
-	})
-	lines := []string{"Estimated ore composition:"}
-	for i := range slices.Values(items) {
-		text := fmt.Sprintf("%s: %s m3", i.name, humanize.Comma(int64(i.volume)))
-		lines = append(lines, text)
+		x.MoonID = data.MoonID
+		x.OreVolumeByType = data.OreVolumeByType
+		x.StructureID = data.StructureID
+		x.StructureName = data.StructureName
+		x.StructureTypeID = data.StructureTypeID
+	case MoonminingExtractionCancelled:
+		var data notification.MoonminingExtractionCancelled
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
+		}
+		x.MoonID = data.MoonID
+		x.StructureID = data.StructureID
+		x.StructureName = data.StructureName
+		x.StructureTypeID = data.StructureTypeID
+	case MoonminingExtractionFinished:
+		var data notification.MoonminingExtractionFinished
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
+		}
+		x.AutoFractureAt = fromLDAPTime(data.AutoTime)
+		x.MoonID = data.MoonID
+		x.OreVolumeByType = data.OreVolumeByType
+		x.StructureID = data.StructureID
+		x.StructureName = data.StructureName
+		x.StructureTypeID = data.StructureTypeID
+	case MoonminingExtractionStarted:
+		var data notification.MoonminingExtractionStarted
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
+		}
+		x.AutoFractureAt = fromLDAPTime(data.AutoTime)
+		x.ChunkArrivalAt = fromLDAPTime(data.ReadyTime)
+		x.MoonID = data.MoonID
+		x.OreVolumeByType = data.OreVolumeByType
+		x.StructureID = data.StructureID
+		x.StructureName = data.StructureName
+		x.StructureTypeID = data.StructureTypeID
+	case MoonminingLaserFired:
+		var data notification.MoonminingLaserFired
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
+		}
+		x.MoonID = data.MoonID
+		x.OreVolumeByType = data.OreVolumeByType
+		x.StructureID = data.StructureID
+		x.StructureName = data.StructureName
+		x.StructureTypeID = data.StructureTypeID
+	default:
+		return x, fmt.Errorf("not a moon mining notification: %s: %w", type_, app.ErrInvalid)
 	}
-	return strings.Join(lines, "\n\n"), nil
+	return x, nil
 }
@@ -0,0 +1,37 @@
+package evenotification_test
+
+import (
+	"testing"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoonMining(t *testing.T) {
+	t.Run("can parse extraction started", func(t *testing.T) {
+		text := "autoTime: 132186924601059151\nmoonID: 40161465\noreVolumeByType:\n  46300: 1288475.124715103\n  46301: 544691.7637724016\nreadyTime: 132186816601059151\nsolarSystemID: 30002537\nstartedBy: 1001\nstructureID: 1000000000002\nstructureName: Dummy\nstructureTypeID: 35835\n"
+		x, err := evenotification.ParseMoonMining(evenotification.MoonminingExtractionStarted, text)
+		if assert.NoError(t, err) {
+			assert.Equal(t, int32(40161465), x.MoonID)
+			assert.Equal(t, int64(1000000000002), x.StructureID)
+			assert.Equal(t, "Dummy", x.StructureName)
+			assert.Equal(t, int32(35835), x.StructureTypeID)
+			assert.Equal(t, map[int32]float64{46300: 1288475.124715103, 46301: 544691.7637724016}, x.OreVolumeByType)
+			assert.Equal(t, "2019-11-20 00:01:00", x.ChunkArrivalAt.Format("2006-01-02 15:04:05"))
+			assert.Equal(t, "2019-11-20 03:01:00", x.AutoFractureAt.Format("2006-01-02 15:04:05"))
+		}
+	})
+	t.Run("can parse extraction cancelled", func(t *testing.T) {
+		text := "cancelledBy: 1001\nmoonID: 40161465\nsolarSystemID: 30002537\nstructureID: 1000000000002\nstructureName: Dummy\nstructureTypeID: 35835\n"
+		x, err := evenotification.ParseMoonMining(evenotification.MoonminingExtractionCancelled, text)
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(1000000000002), x.StructureID)
+			assert.True(t, x.AutoFractureAt.IsZero())
+		}
+	})
+	t.Run("should return error for other types", func(t *testing.T) {
+		_, err := evenotification.ParseMoonMining(evenotification.StructureDestroyed, "")
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+}
@@ -3,110 +3,101 @@ package evenotification
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
-	"github.com/ErikKalkoken/evebuddy/internal/optional"
 	"github.com/antihax/goesi/notification"
 	"gopkg.in/yaml.v3"
 )
 
-func (s *EveNotificationService) renderOrbital(ctx context.Context, type_ Type, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	switch type_ {
+// OrbitalData is the structured data of a notification about a customs office or similar orbital.
+type OrbitalData struct {
+	BaseData
+	AggressorAlliance    *app.EveEntity // nil when the aggressor has no alliance
+	AggressorCharacter   *app.EveEntity
+	AggressorCorporation *app.EveEntity
+	ExitsAt              time.Time // when the orbital exits reinforcement. Zero when not applicable.
+	Planet               *app.EvePlanet
+	StructureType        *app.EveType
+}
+
+func (d OrbitalData) Entities() []*app.EveEntity {
+	return compactEntities(d.AggressorAlliance, d.AggressorCharacter, d.AggressorCorporation)
+}
+
+func (s *EveNotificationService) dataOrbital(ctx context.Context, base BaseData, text string) (Data, error) {
+	var aggressorAllianceID, aggressorCorpID, aggressorID, planetID, typeID int32
+	d := OrbitalData{BaseData: base}
+	switch base.Type {
 	case OrbitalAttacked:
 		var data notification.OrbitalAttacked
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		o, err := s.makeOrbitalBaseText(ctx, data.PlanetID, data.TypeID)
-		if err != nil {
-			return title, body, err
-		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.AggressorAllianceID, data.AggressorCorpID, data.AggressorID})
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf(
-			"%s at %s is under attack",
-			o.type_.Name,
-			o.planet.Name,
-		))
-		t := fmt.Sprintf("%s is under attack.\n\n"+
-			"Attacking Character: %s\n\n"+
-			"Attacking Corporation: %s",
-			o.intro,
-			makeEveEntityProfileLink(entities[data.AggressorID]),
-			makeEveEntityProfileLink(entities[data.AggressorCorpID]),
-		)
-		if data.AggressorAllianceID != 0 {
-			t += fmt.Sprintf(
-				"\n\nAttacking Alliance: %s",
-				makeEveEntityProfileLink(entities[data.AggressorAllianceID]),
-			)
+			return nil, err
 		}
-		body.Set(t)
-
+		aggressorAllianceID, aggressorCorpID, aggressorID = data.AggressorAllianceID, data.AggressorCorpID, data.AggressorID
+		planetID, typeID = data.PlanetID, data.TypeID
 	case OrbitalReinforced:
 		var data notification.OrbitalReinforced
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		o, err := s.makeOrbitalBaseText(ctx, data.PlanetID, data.TypeID)
-		if err != nil {
-			return title, body, err
-		}
-		entities, err := s.eus.ToEntities(ctx, []int32{data.AggressorAllianceID, data.AggressorCorpID, data.AggressorID})
-		if err != nil {
-			return title, body, err
-		}
-		title.Set(fmt.Sprintf(
-			"%s at %s has been reinforced",
-			o.type_.Name,
-			o.planet.Name,
-		))
-		t := fmt.Sprintf("has been reinforced and will come out at %s.\n\n"+
-			"Attacking Character: %s\n\n"+
-			"Attacking Corporation: %s",
-			fromLDAPTime(data.ReinforceExitTime).Format(app.DateTimeFormat),
-			makeEveEntityProfileLink(entities[data.AggressorID]),
-			makeEveEntityProfileLink(entities[data.AggressorCorpID]),
-		)
-		if data.AggressorAllianceID != 0 {
-			t += fmt.Sprintf(
-				"\n\nAttacking Alliance: %s",
-				makeEveEntityProfileLink(entities[data.AggressorAllianceID]),
-			)
+			return nil, err
 		}
-		body.Set(t)
+		aggressorAllianceID, aggressorCorpID, aggressorID = data.AggressorAllianceID, data.AggressorCorpID, data.AggressorID
+		planetID, typeID = data.PlanetID, data.TypeID
+		d.ExitsAt = fromLDAPTime(data.ReinforceExitTime)
 	}
-	return title, body, nil
-}
-
-type orbitalInfo struct {
-	type_  *app.EveType
-	planet *app.EvePlanet
-	intro  string
-}
-
-func (s *EveNotificationService) makeOrbitalBaseText(ctx context.Context, planetID, typeID int32) (orbitalInfo, error) {
 	structureType, err := s.eus.GetOrCreateTypeESI(ctx, typeID)
 	if err != nil {
-		return orbitalInfo{}, err
+		return nil, err
 	}
 	planet, err := s.eus.GetOrCreatePlanetESI(ctx, planetID)
 	if err != nil {
-		return orbitalInfo{}, err
+		return nil, err
+	}
+	entities, err := s.eus.ToEntities(ctx, []int32{aggressorAllianceID, aggressorCorpID, aggressorID})
+	if err != nil {
+		return nil, err
 	}
-	into := fmt.Sprintf(
+	d.Planet = planet
+	d.SolarSystem = planet.SolarSystem
+	d.StructureType = structureType
+	d.AggressorCharacter = entities[aggressorID]
+	d.AggressorCorporation = entities[aggressorCorpID]
+	if aggressorAllianceID != 0 {
+		d.AggressorAlliance = entities[aggressorAllianceID]
+	}
+	return d, nil
+}
+
+func (d OrbitalData) render() (string, string) {
+	var title, body string
+	intro := fmt.Sprintf(
 		"The %s at %s in %s ",
-		structureType.Name,
-		planet.Name,
-		makeSolarSystemLink(planet.SolarSystem),
+		d.StructureType.Name,
+		d.Planet.Name,
+		makeSolarSystemLink(d.SolarSystem),
 	)
-	x := orbitalInfo{
-		type_:  structureType,
-		planet: planet,
-		intro:  into,
+	switch d.Type {
+	case OrbitalAttacked:
+		title = fmt.Sprintf(
+			"%s at %s is under attack",
+			d.StructureType.Name,
+			d.Planet.Name,
+		)
+		body = fmt.Sprintf("%s is under attack.\n\n%s",
+			intro,
+			makeAggressorText(d.AggressorCharacter, d.AggressorCorporation, d.AggressorAlliance),
+		)
+
+	case OrbitalReinforced:
+		title = fmt.Sprintf(
+			"%s at %s has been reinforced",
+			d.StructureType.Name,
+			d.Planet.Name,
+		)
+		body = fmt.Sprintf("has been reinforced and will come out at %s.\n\n%s",
+			d.ExitsAt.Format(app.DateTimeFormat),
+			makeAggressorText(d.AggressorCharacter, d.AggressorCorporation, d.AggressorAlliance),
+		)
 	}
-	return x, nil
+	return title, body
 }
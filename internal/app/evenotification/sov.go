@@ -3,193 +3,160 @@ package evenotification
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
-	"github.com/ErikKalkoken/evebuddy/internal/optional"
 	"github.com/antihax/goesi/notification"
 	"gopkg.in/yaml.v3"
 )
 
-func (s *EveNotificationService) renderSov(ctx context.Context, type_ Type, text string) (optional.Optional[string], optional.Optional[string], error) {
-	switch type_ {
+// SovData is the structured data of a notification about sovereignty.
+type SovData struct {
+	BaseData
+	Corporation   *app.EveEntity // member corporation which claimed or lost sovereignty
+	DecloakAt     time.Time      // when command nodes begin decloaking. Zero when not applicable.
+	StructureType *app.EveEntity // nil when unknown
+}
+
+func (d SovData) Entities() []*app.EveEntity {
+	return compactEntities(d.Corporation)
+}
+
+func (s *EveNotificationService) dataSov(ctx context.Context, base BaseData, text string) (Data, error) {
+	var solarSystemID, structureTypeID int32
+	d := SovData{BaseData: base}
+	switch base.Type {
 	case EntosisCaptureStarted:
-		return s.renderEntosisCaptureStarted(ctx, text)
+		var data notification.EntosisCaptureStarted
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		solarSystemID, structureTypeID = data.SolarSystemID, data.StructureTypeID
 	case SovAllClaimAcquiredMsg:
-		return s.renderSovAllClaimAcquiredMsg(ctx, text)
+		var data notification.SovAllClaimAquiredMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		solarSystemID = data.SolarSystemID
+		corporation, err := s.eus.GetOrCreateEntityESI(ctx, data.CorpID)
+		if err != nil {
+			return nil, err
+		}
+		d.Corporation = corporation
 	case SovAllClaimLostMsg:
-		return s.renderSovAllClaimLostMsg(ctx, text)
+		var data notification.SovAllClaimLostMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		solarSystemID = data.SolarSystemID
+		corporation, err := s.eus.GetOrCreateEntityESI(ctx, data.CorpID)
+		if err != nil {
+			return nil, err
+		}
+		d.Corporation = corporation
 	case SovCommandNodeEventStarted:
-		return s.renderSovCommandNodeEventStarted(ctx, text)
+		var data notification.SovCommandNodeEventStarted
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		solarSystemID, structureTypeID = data.SolarSystemID, eventTypeToTypeID(data.CampaignEventType)
 	case SovStructureDestroyed:
-		return s.renderSovStructureDestroyed(ctx, text)
+		var data notification.SovStructureDestroyed
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		solarSystemID, structureTypeID = data.SolarSystemID, data.StructureTypeID
 	case SovStructureReinforced:
-		return s.renderSovStructureReinforced(ctx, text)
-	}
-	panic("Notification type not implemented: " + type_)
-}
-
-func (s *EveNotificationService) renderEntosisCaptureStarted(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.EntosisCaptureStarted
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-	if err != nil {
-		return title, body, err
-	}
-	structureType, err := s.eus.GetOrCreateEntityESI(ctx, data.StructureTypeID)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("%s in %s is being captured", structureType.Name, solarSystem.Name))
-	body.Set(fmt.Sprintf(
-		"A capsuleer has started to influence the **%s** in %s with an Entosis Link.",
-		structureType.Name,
-		makeSolarSystemLink(solarSystem),
-	))
-	return title, body, nil
-}
-
-func (s *EveNotificationService) renderSovAllClaimAcquiredMsg(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.SovAllClaimAquiredMsg
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-	if err != nil {
-		return title, body, err
-	}
-	corporation, err := s.eus.GetOrCreateEntityESI(ctx, data.CorpID)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("DED Sovereignty claim acknowledgement: %s", solarSystem.Name))
-	body.Set(fmt.Sprintf(
-		"This mail is your confirmation that DED now officially acknowledges "+
-			"that your member organization %s has claimed sovereignty "+
-			"on your behalf in the system %s.",
-		makeEveEntityProfileLink(corporation),
-		makeSolarSystemLink(solarSystem),
-	))
-	return title, body, nil
-}
-
-func (s *EveNotificationService) renderSovCommandNodeEventStarted(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.SovCommandNodeEventStarted
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	structureTypeName, err := s.eventTypeIDToName(ctx, data.CampaignEventType)
-	if err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"Command nodes for %s in %s have begun to decloak",
-		structureTypeName,
-		solarSystem.Name,
-	))
-	body.Set(fmt.Sprintf(
-		"Command nodes for %s in %s can now be found throughout the **%s** constellation",
-		structureTypeName,
-		makeSolarSystemLink(solarSystem),
-		solarSystem.Constellation.Name,
-	))
-	return title, body, nil
-}
-
-func (s *EveNotificationService) renderSovAllClaimLostMsg(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.SovAllClaimLostMsg
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
+		var data notification.SovStructureReinforced
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		solarSystemID, structureTypeID = data.SolarSystemID, eventTypeToTypeID(data.CampaignEventType)
+		d.DecloakAt = fromLDAPTime(data.DecloakTime)
+	}
+	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, solarSystemID)
 	if err != nil {
-		return title, body, err
+		return nil, err
 	}
-	corporation, err := s.eus.GetOrCreateEntityESI(ctx, data.CorpID)
-	if err != nil {
-		return title, body, err
+	d.SolarSystem = solarSystem
+	if structureTypeID != 0 {
+		structureType, err := s.eus.GetOrCreateEntityESI(ctx, structureTypeID)
+		if err != nil {
+			return nil, err
+		}
+		d.StructureType = structureType
 	}
-	title.Set(fmt.Sprintf("Lost sovereignty in: %s", solarSystem.Name))
-	body.Set(fmt.Sprintf(
-		"DED acknowledges that your member organization %s has lost its claim "+
-			"to sovereignty on your behalf in the system %s.",
-		makeEveEntityProfileLink(corporation),
-		makeSolarSystemLink(solarSystem),
-	))
-	return title, body, nil
+	return d, nil
 }
 
-func (s *EveNotificationService) renderSovStructureDestroyed(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.SovStructureDestroyed
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	structureType, err := s.eus.GetOrCreateEntityESI(ctx, data.StructureTypeID)
-	if err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("%s in %s has been destroyed", structureType.Name, solarSystem.Name))
-	body.Set(fmt.Sprintf(
-		"The command nodes for %s in %s have been destroyed by hostile forces.",
-		structureType.Name,
-		makeSolarSystemLink(solarSystem),
-	))
-	return title, body, nil
-}
-
-func (s *EveNotificationService) renderSovStructureReinforced(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.SovStructureReinforced
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	structureTypeName, err := s.eventTypeIDToName(ctx, data.CampaignEventType)
-	if err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("%s in %s has entered reinforced mode", structureTypeName, solarSystem.Name))
-	body.Set(fmt.Sprintf(
-		"The %s in %s has been reinforced by hostile forces "+
-			"and command nodes will begin decloaking at **%s**.",
-		structureTypeName,
-		makeSolarSystemLink(solarSystem),
-		fromLDAPTime(data.DecloakTime).Format(app.DateTimeFormat),
-	))
-	return title, body, nil
+func (d SovData) render() (string, string) {
+	var title, body string
+	structureTypeName := entityNameOrUnknown(d.StructureType)
+	switch d.Type {
+	case EntosisCaptureStarted:
+		title = fmt.Sprintf("%s in %s is being captured", structureTypeName, d.SolarSystem.Name)
+		body = fmt.Sprintf(
+			"A capsuleer has started to influence the **%s** in %s with an Entosis Link.",
+			structureTypeName,
+			makeSolarSystemLink(d.SolarSystem),
+		)
+	case SovAllClaimAcquiredMsg:
+		title = fmt.Sprintf("DED Sovereignty claim acknowledgement: %s", d.SolarSystem.Name)
+		body = fmt.Sprintf(
+			"This mail is your confirmation that DED now officially acknowledges "+
+				"that your member organization %s has claimed sovereignty "+
+				"on your behalf in the system %s.",
+			makeEveEntityProfileLink(d.Corporation),
+			makeSolarSystemLink(d.SolarSystem),
+		)
+	case SovAllClaimLostMsg:
+		title = fmt.Sprintf("Lost sovereignty in: %s", d.SolarSystem.Name)
+		body = fmt.Sprintf(
+			"DED acknowledges that your member organization %s has lost its claim "+
+				"to sovereignty on your behalf in the system %s.",
+			makeEveEntityProfileLink(d.Corporation),
+			makeSolarSystemLink(d.SolarSystem),
+		)
+	case SovCommandNodeEventStarted:
+		title = fmt.Sprintf(
+			"Command nodes for %s in %s have begun to decloak",
+			structureTypeName,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf(
+			"Command nodes for %s in %s can now be found throughout the **%s** constellation",
+			structureTypeName,
+			makeSolarSystemLink(d.SolarSystem),
+			d.SolarSystem.Constellation.Name,
+		)
+	case SovStructureDestroyed:
+		title = fmt.Sprintf("%s in %s has been destroyed", structureTypeName, d.SolarSystem.Name)
+		body = fmt.Sprintf(
+			"The command nodes for %s in %s have been destroyed by hostile forces.",
+			structureTypeName,
+			makeSolarSystemLink(d.SolarSystem),
+		)
+	case SovStructureReinforced:
+		title = fmt.Sprintf("%s in %s has entered reinforced mode", structureTypeName, d.SolarSystem.Name)
+		body = fmt.Sprintf(
+			"The %s in %s has been reinforced by hostile forces "+
+				"and command nodes will begin decloaking at **%s**.",
+			structureTypeName,
+			makeSolarSystemLink(d.SolarSystem),
+			d.DecloakAt.Format(app.DateTimeFormat),
+		)
+	}
+	return title, body
 }
 
-// Returns a structure name for an event type ID.
-func (s *EveNotificationService) eventTypeIDToName(ctx context.Context, eventType int32) (string, error) {
-	var typeID int32
+// eventTypeToTypeID returns the structure type ID for a sov campaign event type
+// or 0 if the event type is unknown.
+func eventTypeToTypeID(eventType int32) int32 {
 	switch eventType {
 	case 1:
-		typeID = app.EveTypeTCU
+		return app.EveTypeTCU
 	case 2:
-		typeID = app.EveTypeIHUB
-	default:
-		return "?", nil
-	}
-	structureType, err := s.eus.GetOrCreateEntityESI(ctx, typeID)
-	if err != nil {
-		return "", err
+		return app.EveTypeIHUB
 	}
-	return structureType.Name, nil
+	return 0
 }
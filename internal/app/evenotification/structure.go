@@ -4,576 +4,574 @@ import (
 	"cmp"
 	"context"
 	"fmt"
-	"maps"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/ErikKalkoken/evebuddy/internal/evehtml"
-	"github.com/ErikKalkoken/evebuddy/internal/optional"
 	"github.com/antihax/goesi/notification"
 	"gopkg.in/yaml.v3"
 )
 
-func (s *EveNotificationService) renderStructure(ctx context.Context, type_ Type, text string, timestamp time.Time) (optional.Optional[string], optional.Optional[string], error) {
-	switch type_ {
+// StructureInfo describes a structure referenced by a notification.
+type StructureInfo struct {
+	ID    int64
+	Name  string         // empty when unknown
+	Owner *app.EveEntity // nil when unknown
+	Type  *app.EveType   // nil when unknown
+}
+
+// ItemQuantity is a quantity of an item type.
+type ItemQuantity struct {
+	Quantity int32
+	Type     *app.EveEntity
+}
+
+// StructureData is the structured data of a notification about an Upwell structure.
+type StructureData struct {
+	BaseData
+	AggressorAlliance    *app.EveEntity // nil when the aggressor has no alliance
+	AggressorCharacter   *app.EveEntity
+	AggressorCorporation *app.EveEntity
+	AssetSafetyFullAt    time.Time // when assets are moved to the station automatically
+	AssetSafetyMinimumAt time.Time // earliest time assets can be moved
+	Character            *app.EveEntity
+	DaysUntilAbandon     int32
+	ExitsAt              time.Time // when the current reinforcement timer ends
+	Items                []ItemQuantity
+	NewOwner             *app.EveEntity
+	OldOwner             *app.EveEntity
+	ReinforceHour        int32
+	Services             []*app.EveEntity // sorted by name
+	Station              *app.EveEntity   // station receiving assets from asset safety
+	Structure            *StructureInfo   // nil when the notification is about multiple structures
+	Structures           []StructureInfo  // sorted by name
+	UnanchoredAt         time.Time
+}
+
+func (d StructureData) Entities() []*app.EveEntity {
+	ee := []*app.EveEntity{
+		d.AggressorAlliance,
+		d.AggressorCharacter,
+		d.AggressorCorporation,
+		d.Character,
+		d.NewOwner,
+		d.OldOwner,
+		d.Station,
+	}
+	if d.Structure != nil {
+		ee = append(ee, d.Structure.Owner)
+	}
+	for _, it := range d.Items {
+		ee = append(ee, it.Type)
+	}
+	ee = append(ee, d.Services...)
+	return compactEntities(ee...)
+}
+
+func (s *EveNotificationService) dataStructure(ctx context.Context, base BaseData, text string) (Data, error) {
+	d := StructureData{BaseData: base}
+	var err error
+	switch base.Type {
 	case OwnershipTransferred:
-		return s.renderOwnershipTransferred(ctx, text)
+		var characterID, newCorpID, oldCorpID, solarSystemID, structureTypeID int32
+		var structureID int64
+		var structureName string
+		if strings.Contains(text, "newOwnerCorpID") {
+			var data notification.OwnershipTransferredV2
+			if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+				return nil, err
+			}
+			characterID = data.CharID
+			newCorpID = data.NewOwnerCorpID
+			oldCorpID = data.OldOwnerCorpID
+			solarSystemID = data.SolarSystemID
+			structureID = data.StructureID
+			structureTypeID = data.StructureTypeID
+			structureName = data.StructureName
+		} else {
+			var data notification.OwnershipTransferred
+			if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+				return nil, err
+			}
+			characterID = int32(data.CharacterLinkData[2].(int))
+			newCorpID = int32(data.ToCorporationLinkData[2].(int))
+			oldCorpID = int32(data.FromCorporationLinkData[2].(int))
+			solarSystemID = int32(data.SolarSystemLinkData[2].(int))
+			structureID = int64(data.StructureLinkData[2].(int))
+			structureTypeID = int32(data.StructureLinkData[1].(int))
+			structureName = data.StructureName
+		}
+		entities, err := s.eus.ToEntities(ctx, []int32{oldCorpID, newCorpID, characterID})
+		if err != nil {
+			return nil, err
+		}
+		d.Character = entities[characterID]
+		d.NewOwner = entities[newCorpID]
+		d.OldOwner = entities[oldCorpID]
+		if err := s.setStructure(ctx, &d, structureTypeID, solarSystemID, structureID, structureName); err != nil {
+			return nil, err
+		}
+
 	case StructureAnchoring:
-		return s.renderStructureAnchoring(ctx, text)
+		var data notification.StructureAnchoring
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
 	case StructureDestroyed:
-		return s.renderStructureDestroyed(ctx, text)
+		var data notification.StructureDestroyed
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
 	case StructureFuelAlert:
-		return s.renderStructureFuelAlert(ctx, text)
+		var data notification.StructureFuelAlert
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
 	case StructureImpendingAbandonmentAssetsAtRisk:
-		return s.renderStructureImpendingAbandonmentAssetsAtRisk(ctx, text)
+		var data notification.StructureImpendingAbandonmentAssetsAtRisk
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		d.SolarSystem, err = s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
+		if err != nil {
+			return nil, err
+		}
+		d.Structure = &StructureInfo{Name: evehtml.Strip(data.StructureLink)}
+		d.DaysUntilAbandon = int32(data.DaysUntilAbandon)
+
 	case StructureItemsDelivered:
-		return s.renderStructureItemsDelivered(ctx, text)
+		var data notification.StructureItemsDelivered
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		ids := []int32{data.CharID}
+		for _, r := range data.ListOfTypesAndQty {
+			ids = append(ids, r[1])
+		}
+		entities, err := s.eus.ToEntities(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		d.Character = entities[data.CharID]
+		for _, r := range data.ListOfTypesAndQty {
+			d.Items = append(d.Items, ItemQuantity{Quantity: r[0], Type: entities[r[1]]})
+		}
+		d.SolarSystem, err = s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarsystemID)
+		if err != nil {
+			return nil, err
+		}
+		structure, err := s.eus.GetOrCreateLocationESI(ctx, data.StructureID)
+		if err != nil {
+			return nil, err
+		}
+		structureType, err := s.eus.GetOrCreateTypeESI(ctx, data.StructureTypeID)
+		if err != nil {
+			return nil, err
+		}
+		d.Structure = &StructureInfo{
+			ID:    data.StructureID,
+			Name:  structure.Name,
+			Owner: structure.Owner,
+			Type:  structureType,
+		}
+
 	case StructureItemsMovedToSafety:
-		return s.renderStructureItemsMovedToSafety(ctx, text)
+		var data notification.StructureItemsMovedToSafety
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		d.SolarSystem, err = s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
+		if err != nil {
+			return nil, err
+		}
+		d.Station, err = s.eus.GetOrCreateEntityESI(ctx, data.NewStationID)
+		if err != nil {
+			return nil, err
+		}
+		d.Structure = &StructureInfo{Name: evehtml.Strip(data.StructureLink)}
+		d.AssetSafetyMinimumAt = fromLDAPTime(data.AssetSafetyMinimumTimestamp)
+		d.AssetSafetyFullAt = fromLDAPTime(data.AssetSafetyFullTimestamp)
+
 	case StructureLostArmor:
-		return s.renderStructureLostArmor(ctx, text)
+		var data notification.StructureLostArmor
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		d.ExitsAt = fromLDAPTime(data.Timestamp)
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
 	case StructureLostShields:
-		return s.renderStructureLostShields(ctx, text)
+		var data notification.StructureLostShields
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		d.ExitsAt = fromLDAPTime(data.Timestamp)
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
 	case StructureOnline:
-		return s.renderStructureOnline(ctx, text)
+		var data notification.StructureOnline
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
 	case StructuresReinforcementChanged:
-		return s.renderStructuresReinforcementChanged(ctx, text)
+		var data notification.StructuresReinforcementChanged
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		for _, x := range data.AllStructureInfo {
+			structureType, err := s.eus.GetOrCreateTypeESI(ctx, int32(x[2].(int)))
+			if err != nil {
+				return nil, err
+			}
+			d.Structures = append(d.Structures, StructureInfo{
+				ID:   int64(x[0].(int)),
+				Name: x[1].(string),
+				Type: structureType,
+			})
+		}
+		slices.SortFunc(d.Structures, func(a, b StructureInfo) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+		d.ReinforceHour = int32(data.Hour)
+
 	case StructureServicesOffline:
-		return s.renderStructureServicesOffline(ctx, text)
+		var data notification.StructureServicesOffline
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		entities, err := s.eus.ToEntities(ctx, data.ListOfServiceModuleIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entities {
+			d.Services = append(d.Services, e)
+		}
+		slices.SortFunc(d.Services, func(a, b *app.EveEntity) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
 	case StructureUnanchoring:
-		return s.renderStructureUnanchoring(ctx, text, timestamp)
+		var data notification.StructureUnanchoring
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		d.UnanchoredAt = base.Timestamp.Add(fromLDAPDuration(data.TimeLeft))
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
 	case StructureUnderAttack:
-		return s.renderStructureUnderAttack(ctx, text)
-	case StructureWentHighPower:
-		return s.renderStructureWentHighPower(ctx, text)
-	case StructureWentLowPower:
-		return s.renderStructureWentLowPower(ctx, text)
-	}
-	panic("Notification type not implemented: " + type_)
-}
+		var data notification.StructureUnderAttack
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		d.AggressorCharacter, err = s.eus.GetOrCreateEntityESI(ctx, data.CharID)
+		if err != nil {
+			return nil, err
+		}
+		if data.CorpName != "" {
+			d.AggressorCorporation = &app.EveEntity{
+				ID:       linkDataID(data.CorpLinkData),
+				Name:     data.CorpName,
+				Category: app.EveEntityCorporation,
+			}
+		}
+		if data.AllianceName != "" {
+			d.AggressorAlliance = &app.EveEntity{
+				ID:       data.AllianceID,
+				Name:     data.AllianceName,
+				Category: app.EveEntityAlliance,
+			}
+		}
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
 
-func (s *EveNotificationService) renderOwnershipTransferred(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var d struct {
-		characterID     int32
-		newCorpID       int32
-		oldCorpID       int32
-		solarSystemID   int32
-		structureID     int64
-		structureName   string
-		structureTypeID int32
-	}
-	if strings.Contains(text, "newOwnerCorpID") {
-		var data notification.OwnershipTransferredV2
+	case StructureWentHighPower:
+		var data notification.StructureWentHighPower
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		d.characterID = data.CharID
-		d.newCorpID = data.NewOwnerCorpID
-		d.oldCorpID = data.OldOwnerCorpID
-		d.solarSystemID = data.SolarSystemID
-		d.structureID = data.StructureID
-		d.structureTypeID = data.StructureTypeID
-		d.structureName = data.StructureName
-	} else {
-		var data notification.OwnershipTransferred
+			return nil, err
+		}
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+
+	case StructureWentLowPower:
+		var data notification.StructureWentLowPower
 		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-			return title, body, err
-		}
-		d.characterID = int32(data.CharacterLinkData[2].(int))
-		d.newCorpID = int32(data.ToCorporationLinkData[2].(int))
-		d.oldCorpID = int32(data.FromCorporationLinkData[2].(int))
-		d.solarSystemID = int32(data.SolarSystemLinkData[2].(int))
-		d.structureID = int64(data.StructureLinkData[2].(int))
-		d.structureTypeID = int32(data.StructureLinkData[1].(int))
-		d.structureName = data.StructureName
-	}
-	entities, err := s.eus.ToEntities(ctx, []int32{d.oldCorpID, d.newCorpID, d.characterID})
-	if err != nil {
-		return title, body, err
+			return nil, err
+		}
+		err = s.setStructure(ctx, &d, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
 	}
-	o, err := s.makeStructureBaseText(ctx, d.structureTypeID, d.solarSystemID, d.structureID, d.structureName)
 	if err != nil {
-		return title, body, err
+		return nil, err
 	}
-	title.Set(fmt.Sprintf(
-		"%s ownership has been transferred to %s",
-		d.structureName,
-		entities[d.newCorpID].Name,
-	))
-	body.Set(fmt.Sprintf(
-		"%s has been transferred from %s to %s by %s.",
-		o.intro,
-		makeEveEntityProfileLink(entities[d.oldCorpID]),
-		makeEveEntityProfileLink(entities[d.newCorpID]),
-		makeEveEntityProfileLink(entities[d.characterID]),
-	))
-	return title, body, nil
+	return d, nil
 }
 
-func (s *EveNotificationService) renderStructureAnchoring(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureAnchoring
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
+// setStructure resolves a structure and it's solar system and sets them for d.
+func (s *EveNotificationService) setStructure(ctx context.Context, d *StructureData, typeID, systemID int32, structureID int64, structureName string) error {
+	var eveType *app.EveType
+	var err error
+	if typeID != 0 {
+		eveType, err = s.eus.GetOrCreateTypeESI(ctx, typeID)
+		if err != nil {
+			return err
+		}
 	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
+	system, err := s.eus.GetOrCreateSolarSystemESI(ctx, systemID)
 	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"A %s has started anchoring in %s",
-		o.eveType.Name,
-		o.solarSystem.Name,
-	))
-	body.Set(fmt.Sprintf("%s has started anchoring.", o.intro))
-	return title, body, nil
-}
-
-func (s *EveNotificationService) renderStructureDestroyed(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureDestroyed
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
+		return err
 	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
+	st := &StructureInfo{ID: structureID, Name: structureName, Type: eveType}
+	isUpwellStructure := eveType != nil && eveType.Group.Category.ID == app.EveCategoryStructure
+	if isUpwellStructure {
+		structure, err := s.eus.GetOrCreateLocationESI(ctx, structureID)
+		if err != nil {
+			return err
+		}
+		if structure.Variant() == app.EveLocationStructure {
+			st.Name = structure.DisplayName2()
+			st.Owner = structure.Owner
+		}
 	}
-	title.Set(fmt.Sprintf(
-		"%s in %s has been destroyed",
-		o.name,
-		o.solarSystem.Name,
-	))
-	body.Set(fmt.Sprintf(
-		"%s has been destroyed. Item located inside the structure are available for transfer to asset safety.",
-		o.intro,
-	))
-	return title, body, nil
+	d.SolarSystem = system
+	d.Structure = st
+	return nil
 }
 
-func (s *EveNotificationService) renderStructureFuelAlert(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureFuelAlert
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
+// linkDataID returns the ID from showinfo link data or 0 if there is none.
+func linkDataID(x []any) int32 {
+	if len(x) < 3 {
+		return 0
 	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
+	id, ok := x[2].(int)
+	if !ok {
+		return 0
 	}
-	title.Set(fmt.Sprintf(
-		"%s in %s is low on fuel",
-		o.name,
-		o.solarSystem.Name,
-	))
-	body.Set(fmt.Sprintf("%s is running out of fuel in 24hrs.", o.intro))
-	return title, body, nil
+	return int32(id)
 }
 
-func (s *EveNotificationService) renderStructureImpendingAbandonmentAssetsAtRisk(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureImpendingAbandonmentAssetsAtRisk
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-	if err != nil {
-		return title, body, err
+func (d StructureData) render() (string, string) {
+	var title, body string
+	var name, typeName string
+	if d.Structure != nil {
+		name = d.Structure.Name
+		if d.Structure.Type != nil {
+			typeName = d.Structure.Type.Name
+		}
 	}
-	name := evehtml.Strip(data.StructureLink)
-	title.Set(fmt.Sprintf("Your assets located in %s are at risk", name))
-	body.Set(fmt.Sprintf(
-		"You have assets located at **%s** in %s. "+
-			"These assets are at risk of loss as the structure is close to becoming abandoned.\n\n"+
-			"In approximately %d days this structure will become abandoned.",
-		name,
-		makeSolarSystemLink(solarSystem),
-		data.DaysUntilAbandon,
-	))
-	return title, body, nil
-}
+	switch d.Type {
+	case OwnershipTransferred:
+		title = fmt.Sprintf(
+			"%s ownership has been transferred to %s",
+			name,
+			entityName(d.NewOwner),
+		)
+		body = fmt.Sprintf(
+			"%s has been transferred from %s to %s by %s.",
+			d.structureIntro(),
+			makeEveEntityProfileLink(d.OldOwner),
+			makeEveEntityProfileLink(d.NewOwner),
+			makeEveEntityProfileLink(d.Character),
+		)
 
-func (s *EveNotificationService) renderStructureItemsDelivered(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureItemsDelivered
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	ids := []int32{data.CharID, data.StructureTypeID}
-	for _, r := range data.ListOfTypesAndQty {
-		ids = append(ids, r[1])
-	}
-	entities, err := s.eus.ToEntities(ctx, ids)
-	if err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarsystemID)
-	if err != nil {
-		return title, body, err
-	}
-	structure, err := s.eus.GetOrCreateLocationESI(ctx, data.StructureID)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("Items delivered from %s", entities[data.CharID].Name))
-	var location string
-	if structure.Name != "" {
-		location = fmt.Sprintf("**%s**", structure.Name)
-	} else {
-		location = fmt.Sprintf("a %s", makeEveEntityProfileLink(entities[data.StructureTypeID]))
-	}
-	b := fmt.Sprintf(
-		"%s has delivered the following items to %s in %s:\n\n",
-		makeEveEntityProfileLink(entities[data.CharID]),
-		location,
-		makeSolarSystemLink(solarSystem),
-	)
-	for _, r := range data.ListOfTypesAndQty {
-		b += fmt.Sprintf("%dx %s\n\n", r[0], entities[r[1]].Name)
-	}
-	body.Set(b)
-	return title, body, nil
-}
+	case StructureAnchoring:
+		title = fmt.Sprintf(
+			"A %s has started anchoring in %s",
+			typeName,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf("%s has started anchoring.", d.structureIntro())
 
-func (s *EveNotificationService) renderStructureItemsMovedToSafety(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureItemsMovedToSafety
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	solarSystem, err := s.eus.GetOrCreateSolarSystemESI(ctx, data.SolarSystemID)
-	if err != nil {
-		return title, body, err
-	}
-	station, err := s.eus.GetOrCreateEntityESI(ctx, data.NewStationID)
-	if err != nil {
-		return title, body, err
-	}
-	name := evehtml.Strip(data.StructureLink)
-	title.Set(fmt.Sprintf("Your assets located in %s have been moved to asset safety", name))
-	body.Set(fmt.Sprintf(
-		"You assets located at **%s** in %s have been moved to asset safety.\n\n"+
-			"They can be moved to a location of your choosing earliest at %s.\n\n"+
-			"They will be moved automatically to %s by %s.",
-		name,
-		makeSolarSystemLink(solarSystem),
-		fromLDAPTime(data.AssetSafetyMinimumTimestamp).Format(app.DateTimeFormat),
-		station.Name,
-		fromLDAPTime(data.AssetSafetyFullTimestamp).Format(app.DateTimeFormat),
-	))
-	return title, body, nil
-}
+	case StructureDestroyed:
+		title = fmt.Sprintf(
+			"%s in %s has been destroyed",
+			name,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf(
+			"%s has been destroyed. Item located inside the structure are available for transfer to asset safety.",
+			d.structureIntro(),
+		)
 
-func (s *EveNotificationService) renderStructureLostArmor(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureLostArmor
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"%s in %s has lost it's armor",
-		o.name,
-		o.solarSystem.Name,
-	))
-	body.Set(fmt.Sprintf(
-		"%s has lost it's armor. Hull timer ends at **%s**.",
-		o.intro,
-		fromLDAPTime(data.Timestamp).Format(app.DateTimeFormat),
-	))
-	return title, body, nil
-}
+	case StructureFuelAlert:
+		title = fmt.Sprintf(
+			"%s in %s is low on fuel",
+			name,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf("%s is running out of fuel in 24hrs.", d.structureIntro())
 
-func (s *EveNotificationService) renderStructureLostShields(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureLostShields
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"%s in %s has lost it's shields",
-		o.name,
-		o.solarSystem.Name,
-	))
-	body.Set(fmt.Sprintf(
-		"%s has lost it's shields and is now in reinforcement state. "+
-			"It will exit reinforcement at **%s** and will then be vulnerable for 15 minutes.",
-		o.intro,
-		fromLDAPTime(data.Timestamp).Format(app.DateTimeFormat),
-	))
-	return title, body, nil
-}
+	case StructureImpendingAbandonmentAssetsAtRisk:
+		title = fmt.Sprintf("Your assets located in %s are at risk", name)
+		body = fmt.Sprintf(
+			"You have assets located at **%s** in %s. "+
+				"These assets are at risk of loss as the structure is close to becoming abandoned.\n\n"+
+				"In approximately %d days this structure will become abandoned.",
+			name,
+			makeSolarSystemLink(d.SolarSystem),
+			d.DaysUntilAbandon,
+		)
 
-func (s *EveNotificationService) renderStructureOnline(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureOnline
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"%s in %s is now online",
-		o.name,
-		o.solarSystem.Name,
-	))
-	body.Set(fmt.Sprintf("%s is now online.", o.intro))
-	return title, body, nil
-}
+	case StructureItemsDelivered:
+		title = fmt.Sprintf("Items delivered from %s", entityName(d.Character))
+		var location string
+		if name != "" {
+			location = fmt.Sprintf("**%s**", name)
+		} else {
+			location = fmt.Sprintf("a %s", typeName)
+		}
+		body = fmt.Sprintf(
+			"%s has delivered the following items to %s in %s:\n\n",
+			makeEveEntityProfileLink(d.Character),
+			location,
+			makeSolarSystemLink(d.SolarSystem),
+		)
+		for _, it := range d.Items {
+			body += fmt.Sprintf("%dx %s\n\n", it.Quantity, entityName(it.Type))
+		}
 
-type structureReinforcementInfo struct {
-	structureID int64
-	name        string
-	typeID      int32
-}
+	case StructureItemsMovedToSafety:
+		title = fmt.Sprintf("Your assets located in %s have been moved to asset safety", name)
+		body = fmt.Sprintf(
+			"You assets located at **%s** in %s have been moved to asset safety.\n\n"+
+				"They can be moved to a location of your choosing earliest at %s.\n\n"+
+				"They will be moved automatically to %s by %s.",
+			name,
+			makeSolarSystemLink(d.SolarSystem),
+			d.AssetSafetyMinimumAt.Format(app.DateTimeFormat),
+			entityName(d.Station),
+			d.AssetSafetyFullAt.Format(app.DateTimeFormat),
+		)
 
-func (s *EveNotificationService) renderStructuresReinforcementChanged(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructuresReinforcementChanged
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	typeIDs := make([]int32, 0)
-	structures := make([]structureReinforcementInfo, 0)
-	for _, x := range data.AllStructureInfo {
-		typeID := int32(x[2].(int))
-		s := structureReinforcementInfo{
-			structureID: int64(x[0].(int)),
-			name:        x[1].(string),
-			typeID:      typeID,
-		}
-		structures = append(structures, s)
-		typeIDs = append(typeIDs, typeID)
-	}
-	slices.SortFunc(structures, func(a structureReinforcementInfo, b structureReinforcementInfo) int {
-		return cmp.Compare(a.name, b.name)
-	})
-	entities, err := s.eus.ToEntities(ctx, typeIDs)
-	if err != nil {
-		return title, body, err
-	}
-	lines := make([]string, 0)
-	for _, o := range structures {
-		lines = append(lines, fmt.Sprintf("- %s (%s)", o.name, entities[o.typeID].Name))
-	}
-	title.Set("Structure reinforcement time changed")
-	out := fmt.Sprintf(
-		"Reinforcement hour has been changed to %d:00 "+
-			"for the following structures:\n\n%s",
-		data.Hour,
-		strings.Join(lines, "\n\n"),
-	)
-	body.Set(out)
-	return title, body, nil
-}
+	case StructureLostArmor:
+		title = fmt.Sprintf(
+			"%s in %s has lost it's armor",
+			name,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf(
+			"%s has lost it's armor. Hull timer ends at **%s**.",
+			d.structureIntro(),
+			d.ExitsAt.Format(app.DateTimeFormat),
+		)
 
-func (s *EveNotificationService) renderStructureServicesOffline(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureServicesOffline
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	entities, err := s.eus.ToEntities(ctx, data.ListOfServiceModuleIDs)
-	if err != nil {
-		return title, body, err
-	}
-	lines := make([]string, 0)
-	for e := range maps.Values(entities) {
-		lines = append(lines, fmt.Sprintf("- %s", e.Name))
-	}
-	slices.Sort(lines)
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"%s in %s has all services off-lined",
-		o.name,
-		o.solarSystem.Name,
-	))
-	body.Set(fmt.Sprintf(
-		"%s has all services off-lined.\n\n%s",
-		o.intro,
-		strings.Join(lines, "\n\n"),
-	))
-	return title, body, nil
-}
+	case StructureLostShields:
+		title = fmt.Sprintf(
+			"%s in %s has lost it's shields",
+			name,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf(
+			"%s has lost it's shields and is now in reinforcement state. "+
+				"It will exit reinforcement at **%s** and will then be vulnerable for 15 minutes.",
+			d.structureIntro(),
+			d.ExitsAt.Format(app.DateTimeFormat),
+		)
 
-func (s *EveNotificationService) renderStructureUnanchoring(ctx context.Context, text string, timestamp time.Time) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureUnanchoring
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"%s has started unanchoring in %s",
-		o.name,
-		o.solarSystem.Name,
-	))
-	due := timestamp.Add(fromLDAPDuration(data.TimeLeft))
-	body.Set(fmt.Sprintf(
-		"%s has started un-anchoring. It will be fully un-anchored at: %s",
-		o.intro,
-		due.Format(app.DateTimeFormat),
-	))
-	return title, body, nil
-}
+	case StructureOnline:
+		title = fmt.Sprintf(
+			"%s in %s is now online",
+			name,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf("%s is now online.", d.structureIntro())
 
-func (s *EveNotificationService) renderStructureUnderAttack(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureUnderAttack
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"%s in %s is under attack",
-		o.name,
-		o.solarSystem.Name,
-	))
-	attackChar, err := s.eus.GetOrCreateEntityESI(ctx, data.CharID)
-	if err != nil {
-		return title, body, err
-	}
-	t := fmt.Sprintf("%s is under attack.\n\n"+
-		"Attacking Character: %s\n\n"+
-		"Attacking Corporation: %s",
-		o.intro,
-		makeEveEntityProfileLink(attackChar),
-		makeCorporationLink(data.CorpName),
-	)
-	if data.AllianceName != "" {
-		t += fmt.Sprintf(
-			"\n\nAttacking Alliance: %s",
-			makeAllianceLink(data.AllianceName),
+	case StructuresReinforcementChanged:
+		lines := make([]string, 0)
+		for _, o := range d.Structures {
+			var typeName string
+			if o.Type != nil {
+				typeName = o.Type.Name
+			}
+			lines = append(lines, fmt.Sprintf("- %s (%s)", o.Name, typeName))
+		}
+		title = "Structure reinforcement time changed"
+		body = fmt.Sprintf(
+			"Reinforcement hour has been changed to %d:00 "+
+				"for the following structures:\n\n%s",
+			d.ReinforceHour,
+			strings.Join(lines, "\n\n"),
 		)
-	}
-	body.Set(t)
-	return title, body, nil
-}
 
-func (s *EveNotificationService) renderStructureWentHighPower(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureWentHighPower
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("%s is now running on High Power", o.name))
-	body.Set(fmt.Sprintf("%s went to high power mode.", o.intro))
-	return title, body, nil
-}
+	case StructureServicesOffline:
+		lines := make([]string, 0)
+		for _, e := range d.Services {
+			lines = append(lines, fmt.Sprintf("- %s", e.Name))
+		}
+		title = fmt.Sprintf(
+			"%s in %s has all services off-lined",
+			name,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf(
+			"%s has all services off-lined.\n\n%s",
+			d.structureIntro(),
+			strings.Join(lines, "\n\n"),
+		)
 
-func (s *EveNotificationService) renderStructureWentLowPower(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.StructureWentLowPower
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeStructureBaseText(ctx, data.StructureTypeID, data.SolarsystemID, data.StructureID, "")
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("%s is now running on Low Power", o.name))
-	body.Set(fmt.Sprintf("%s went to low power mode.", o.intro))
-	return title, body, nil
-}
+	case StructureUnanchoring:
+		title = fmt.Sprintf(
+			"%s has started unanchoring in %s",
+			name,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf(
+			"%s has started un-anchoring. It will be fully un-anchored at: %s",
+			d.structureIntro(),
+			d.UnanchoredAt.Format(app.DateTimeFormat),
+		)
 
-type eveObj struct {
-	ID   int
-	Name string
-}
+	case StructureUnderAttack:
+		title = fmt.Sprintf(
+			"%s in %s is under attack",
+			name,
+			d.SolarSystem.Name,
+		)
+		body = fmt.Sprintf(
+			"%s is under attack.\n\n%s",
+			d.structureIntro(),
+			makeAggressorText(d.AggressorCharacter, d.AggressorCorporation, d.AggressorAlliance),
+		)
 
-type structureInfo struct {
-	eveType     eveObj
-	intro       string
-	name        string
-	owner       eveObj
-	solarSystem eveObj
-}
+	case StructureWentHighPower:
+		title = fmt.Sprintf("%s is now running on High Power", name)
+		body = fmt.Sprintf("%s went to high power mode.", d.structureIntro())
 
-func (s *EveNotificationService) makeStructureBaseText(ctx context.Context, typeID, systemID int32, structureID int64, structureName string) (structureInfo, error) {
-	var eveType *app.EveType
-	var err error
-	if typeID != 0 {
-		eveType, err = s.eus.GetOrCreateTypeESI(ctx, typeID)
-		if err != nil {
-			return structureInfo{}, err
-		}
-	}
-	system, err := s.eus.GetOrCreateSolarSystemESI(ctx, systemID)
-	if err != nil {
-		return structureInfo{}, err
-	}
-	var ownerLink string
-	var owner *app.EveEntity
-	isUpwellStructure := eveType != nil && eveType.Group.Category.ID == app.EveCategoryStructure
-	if isUpwellStructure {
-		structure, err := s.eus.GetOrCreateLocationESI(ctx, structureID)
-		if err != nil {
-			return structureInfo{}, err
-		}
-		if structure.Variant() == app.EveLocationStructure {
-			structureName = structure.DisplayName2()
-			if structure.Owner != nil {
-				owner = structure.Owner
-				ownerLink = makeEveEntityProfileLink(structure.Owner)
-			}
-		}
+	case StructureWentLowPower:
+		title = fmt.Sprintf("%s is now running on Low Power", name)
+		body = fmt.Sprintf("%s went to low power mode.", d.structureIntro())
 	}
+	return title, body
+}
+
+// structureIntro returns the introduction sentence for a structure notification.
+func (d StructureData) structureIntro() string {
 	var name string
-	if eveType != nil {
-		isOrbital := eveType.Group.Category.ID == app.EveCategoryOrbitals
-		if isOrbital && structureName != "" {
-			name = fmt.Sprintf("**%s**", structureName)
-		} else if structureName != "" {
-			name = fmt.Sprintf("%s **%s**", eveType.Name, structureName)
+	st := d.Structure
+	if st != nil && st.Type != nil {
+		isOrbital := st.Type.Group.Category.ID == app.EveCategoryOrbitals
+		if isOrbital && st.Name != "" {
+			name = fmt.Sprintf("**%s**", st.Name)
+		} else if st.Name != "" {
+			name = fmt.Sprintf("%s **%s**", st.Type.Name, st.Name)
 		} else {
-			name = eveType.Name
+			name = st.Type.Name
 		}
-	} else if structureName != "" {
-		name = structureName
+	} else if st != nil && st.Name != "" {
+		name = st.Name
 	} else {
 		name = "unknown structure"
 	}
-	text := fmt.Sprintf("The %s in %s", name, makeSolarSystemLink(system))
-	if ownerLink != "" {
-		text += fmt.Sprintf(" belonging to %s", ownerLink)
-	}
-	x := structureInfo{
-		solarSystem: eveObj{ID: int(system.ID), Name: system.Name},
-		name:        structureName,
-		intro:       text,
-	}
-	if eveType != nil {
-		x.eveType.ID = int(eveType.ID)
-		x.eveType.Name = eveType.Name
-	}
-	if owner != nil {
-		x.owner.ID = int(owner.ID)
-		x.owner.Name = owner.Name
+	text := fmt.Sprintf("The %s in %s", name, makeSolarSystemLink(d.SolarSystem))
+	if st != nil && st.Owner != nil {
+		text += fmt.Sprintf(" belonging to %s", makeEveEntityProfileLink(st.Owner))
 	}
-	return x, nil
+	return text
 }
@@ -10,7 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestMakeStructureBaseText(t *testing.T) {
+func TestSetStructure(t *testing.T) {
 	db, st, factory := testutil.New()
 	defer db.Close()
 	httpmock.Activate()
@@ -18,36 +18,38 @@ func TestMakeStructureBaseText(t *testing.T) {
 	eus := eveuniverseservice.New(st, nil)
 	s := New(eus)
 	ctx := context.Background()
-	t.Run("can create base text from complete input data", func(t *testing.T) {
+	t.Run("can set structure from complete input data", func(t *testing.T) {
 		// given
 		testutil.TruncateTables(db)
 		httpmock.Reset()
 		o := factory.CreateEveLocationStructure()
 		// when
-		x, err := s.makeStructureBaseText(ctx, o.Type.ID, o.SolarSystem.ID, o.ID, o.Name)
+		var x StructureData
+		err := s.setStructure(ctx, &x, o.Type.ID, o.SolarSystem.ID, o.ID, o.Name)
 		// then
 		if assert.NoError(t, err) {
-			assert.Equal(t, o.Name, x.name)
-			assert.Equal(t, o.SolarSystem.Name, x.solarSystem.Name)
-			assert.Equal(t, o.Type.Name, x.eveType.Name)
-			assert.Equal(t, o.Owner.Name, x.owner.Name)
-			assert.NotEmpty(t, x.intro)
+			assert.Equal(t, o.Name, x.Structure.Name)
+			assert.Equal(t, o.SolarSystem.Name, x.SolarSystem.Name)
+			assert.Equal(t, o.Type.Name, x.Structure.Type.Name)
+			assert.Equal(t, o.Owner.Name, x.Structure.Owner.Name)
+			assert.NotEmpty(t, x.structureIntro())
 		}
 	})
-	t.Run("can create base text from minimal input data", func(t *testing.T) {
+	t.Run("can set structure from minimal input data", func(t *testing.T) {
 		// given
 		testutil.TruncateTables(db)
 		httpmock.Reset()
 		es := factory.CreateEveSolarSystem()
 		// when
-		x, err := s.makeStructureBaseText(ctx, 0, es.ID, 1_000_000_000_000, "")
+		var x StructureData
+		err := s.setStructure(ctx, &x, 0, es.ID, 1_000_000_000_000, "")
 		// then
 		if assert.NoError(t, err) {
-			assert.Empty(t, x.name)
-			assert.Equal(t, es.Name, x.solarSystem.Name)
-			assert.Empty(t, x.eveType)
-			assert.Empty(t, x.owner)
-			assert.NotEmpty(t, x.intro)
+			assert.Empty(t, x.Structure.Name)
+			assert.Equal(t, es.Name, x.SolarSystem.Name)
+			assert.Nil(t, x.Structure.Type)
+			assert.Nil(t, x.Structure.Owner)
+			assert.NotEmpty(t, x.structureIntro())
 		}
 	})
 }
@@ -0,0 +1,85 @@
+package evenotification
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/antihax/goesi/notification"
+	"gopkg.in/yaml.v3"
+)
+
+// StructureTimerData contains the structured data of a notification with a reinforcement timer.
+type StructureTimerData struct {
+	ExitsAt         time.Time
+	SolarSystemID   int32
+	StructureID     int64 // planet ID for orbitals, zero for sovereignty structures
+	StructureTypeID int32
+	TimerType       app.StructureTimerType
+}
+
+// StructureTimerTypes returns the notification types which contain a structure timer.
+func StructureTimerTypes() []Type {
+	return []Type{
+		OrbitalReinforced,
+		SovStructureReinforced,
+		StructureLostArmor,
+		StructureLostShields,
+	}
+}
+
+// ParseStructureTimer returns the reinforcement timer of a notification.
+func ParseStructureTimer(type_ Type, text string) (StructureTimerData, error) {
+	var x StructureTimerData
+	switch type_ {
+	case OrbitalReinforced:
+		var data notification.OrbitalReinforced
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
+		}
+		x.ExitsAt = fromLDAPTime(data.ReinforceExitTime)
+		x.SolarSystemID = data.SolarSystemID
+		x.StructureID = int64(data.PlanetID)
+		x.StructureTypeID = data.TypeID
+		x.TimerType = app.StructureTimerTypeReinforced
+	case SovStructureReinforced:
+		var data notification.SovStructureReinforced
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
+		}
+		switch data.CampaignEventType {
+		case 1:
+			x.StructureTypeID = app.EveTypeTCU
+		case 2:
+			x.StructureTypeID = app.EveTypeIHUB
+		default:
+			return x, fmt.Errorf("unknown campaign event type %d: %w", data.CampaignEventType, app.ErrInvalid)
+		}
+		x.ExitsAt = fromLDAPTime(data.DecloakTime)
+		x.SolarSystemID = data.SolarSystemID
+		x.TimerType = app.StructureTimerTypeSovereignty
+	case StructureLostArmor:
+		var data notification.StructureLostArmor
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
+		}
+		x.ExitsAt = fromLDAPTime(data.Timestamp)
+		x.SolarSystemID = data.SolarsystemID
+		x.StructureID = data.StructureID
+		x.StructureTypeID = data.StructureTypeID
+		x.TimerType = app.StructureTimerTypeHull
+	case StructureLostShields:
+		var data notification.StructureLostShields
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return x, err
+		}
+		x.ExitsAt = fromLDAPTime(data.Timestamp)
+		x.SolarSystemID = data.SolarsystemID
+		x.StructureID = data.StructureID
+		x.StructureTypeID = data.StructureTypeID
+		x.TimerType = app.StructureTimerTypeArmor
+	default:
+		return x, fmt.Errorf("not a structure timer notification: %s: %w", type_, app.ErrInvalid)
+	}
+	return x, nil
+}
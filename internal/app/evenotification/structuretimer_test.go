@@ -0,0 +1,61 @@
+package evenotification_test
+
+import (
+	"testing"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStructureTimer(t *testing.T) {
+	const layout = "2006-01-02 15:04:05"
+	t.Run("can parse lost shields", func(t *testing.T) {
+		text := "solarsystemID: 30002537\nstructureID: &id001 1000000000001\nstructureShowInfoData:\n- showinfo\n- 35835\n- *id001\nstructureTypeID: 35835\ntimeLeft: 1727805401093\ntimestamp: 132148470780000000\nvulnerableTime: 9000000000\n"
+		x, err := evenotification.ParseStructureTimer(evenotification.StructureLostShields, text)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "2019-10-06 14:51:18", x.ExitsAt.Format(layout))
+			assert.Equal(t, int32(30002537), x.SolarSystemID)
+			assert.Equal(t, int64(1000000000001), x.StructureID)
+			assert.Equal(t, int32(35835), x.StructureTypeID)
+			assert.Equal(t, app.StructureTimerTypeArmor, x.TimerType)
+		}
+	})
+	t.Run("can parse lost armor", func(t *testing.T) {
+		text := "solarsystemID: 30002537\nstructureID: &id001 1000000000001\nstructureShowInfoData:\n- showinfo\n- 35835\n- *id001\nstructureTypeID: 35835\ntimeLeft: 7333797161804\ntimestamp: 131988727900000000\nvulnerableTime: 18000000000\n"
+		x, err := evenotification.ParseStructureTimer(evenotification.StructureLostArmor, text)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "2019-04-04 17:33:10", x.ExitsAt.Format(layout))
+			assert.Equal(t, app.StructureTimerTypeHull, x.TimerType)
+		}
+	})
+	t.Run("can parse orbital reinforced", func(t *testing.T) {
+		text := "aggressorAllianceID: 3011\naggressorCorpID: 2011\naggressorID: 1011\nplanetID: 40161469\nplanetTypeID: 2016\nreinforceExitTime: 132154723470000000\nsolarSystemID: 30002537\ntypeID: 2233\n"
+		x, err := evenotification.ParseStructureTimer(evenotification.OrbitalReinforced, text)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "2019-10-13 20:32:27", x.ExitsAt.Format(layout))
+			assert.Equal(t, int64(40161469), x.StructureID)
+			assert.Equal(t, int32(2233), x.StructureTypeID)
+			assert.Equal(t, app.StructureTimerTypeReinforced, x.TimerType)
+		}
+	})
+	t.Run("can parse sov structure reinforced", func(t *testing.T) {
+		text := "campaignEventType: 1\ndecloakTime: 131897990021334067\nsolarSystemID: 30002537\n"
+		x, err := evenotification.ParseStructureTimer(evenotification.SovStructureReinforced, text)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "2018-12-20 17:03:22", x.ExitsAt.Format(layout))
+			assert.Equal(t, int64(0), x.StructureID)
+			assert.Equal(t, int32(app.EveTypeTCU), x.StructureTypeID)
+			assert.Equal(t, app.StructureTimerTypeSovereignty, x.TimerType)
+		}
+	})
+	t.Run("should return error for unknown sov campaign type", func(t *testing.T) {
+		text := "campaignEventType: 9\ndecloakTime: 131897990021334067\nsolarSystemID: 30002537\n"
+		_, err := evenotification.ParseStructureTimer(evenotification.SovStructureReinforced, text)
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("should return error for other types", func(t *testing.T) {
+		_, err := evenotification.ParseStructureTimer(evenotification.StructureDestroyed, "")
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+}
@@ -11,90 +11,81 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-func (s *EveNotificationService) renderTower(ctx context.Context, type_ Type, text string) (optional.Optional[string], optional.Optional[string], error) {
-	switch type_ {
-	case TowerAlertMsg:
-		return s.renderTowerAlertMsg(ctx, text)
-	case TowerResourceAlertMsg:
-		return s.renderTowerResourceAlertMsg(ctx, text)
-	}
-	panic("Notification type not implemented: " + type_)
+// TowerData is the structured data of a notification about a starbase.
+type TowerData struct {
+	BaseData
+	AggressorAlliance    *app.EveEntity // nil when the aggressor has no alliance
+	AggressorCharacter   *app.EveEntity
+	AggressorCorporation *app.EveEntity
+	FuelRemaining        optional.Optional[int] // units of fuel remaining
+	Moon                 *app.EveMoon
+	StructureType        *app.EveType
 }
 
-func (s *EveNotificationService) renderTowerAlertMsg(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.TowerAlertMsg
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeTowerBaseText(ctx, data.MoonID, data.TypeID)
-	if err != nil {
-		return title, body, err
-	}
-	entities, err := s.eus.ToEntities(ctx, []int32{data.AggressorAllianceID, data.AggressorCorpID, data.AggressorID})
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("Starbase at %s is under attack", o.moon.Name))
-	b := fmt.Sprintf(
-		"%s is under attack.\n\n"+
-			"Attacking Character: %s\n\n"+
-			"Attacking Corporation: %s",
-		o.intro,
-		makeEveEntityProfileLink(entities[data.AggressorID]),
-		makeEveEntityProfileLink(entities[data.AggressorCorpID]),
-	)
-	if data.AggressorAllianceID != 0 {
-		b += fmt.Sprintf(
-			"\n\nAttacking Alliance: %s",
-			makeEveEntityProfileLink(entities[data.AggressorAllianceID]),
-		)
-	}
-	body.Set(b)
-	return title, body, nil
+func (d TowerData) Entities() []*app.EveEntity {
+	return compactEntities(d.AggressorAlliance, d.AggressorCharacter, d.AggressorCorporation)
 }
 
-func (s *EveNotificationService) renderTowerResourceAlertMsg(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.TowerResourceAlertMsg
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	o, err := s.makeTowerBaseText(ctx, data.MoonID, data.TypeID)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("Starbase at %s is running out of fuel", o.moon.Name))
-	b := fmt.Sprintf("%s is running out of fuel in less then 24hrs.\n\n", o.intro)
-	if len(data.Wants) > 0 {
-		b += fmt.Sprintf(
-			"Fuel remaining: %s units", humanize.Comma(int64(data.Wants[0].Quantity)),
-		)
+func (s *EveNotificationService) dataTower(ctx context.Context, base BaseData, text string) (Data, error) {
+	var moonID, typeID int32
+	d := TowerData{BaseData: base}
+	switch base.Type {
+	case TowerAlertMsg:
+		var data notification.TowerAlertMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		moonID, typeID = data.MoonID, data.TypeID
+		entities, err := s.eus.ToEntities(ctx, []int32{data.AggressorAllianceID, data.AggressorCorpID, data.AggressorID})
+		if err != nil {
+			return nil, err
+		}
+		d.AggressorCharacter = entities[data.AggressorID]
+		d.AggressorCorporation = entities[data.AggressorCorpID]
+		if data.AggressorAllianceID != 0 {
+			d.AggressorAlliance = entities[data.AggressorAllianceID]
+		}
+	case TowerResourceAlertMsg:
+		var data notification.TowerResourceAlertMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		moonID, typeID = data.MoonID, data.TypeID
+		if len(data.Wants) > 0 {
+			d.FuelRemaining.Set(int(data.Wants[0].Quantity))
+		}
 	}
-	body.Set(b)
-	return title, body, nil
-}
-
-type towerInfo struct {
-	type_ *app.EveType
-	moon  *app.EveMoon
-	intro string
-}
-
-func (s *EveNotificationService) makeTowerBaseText(ctx context.Context, moonID, typeID int32) (towerInfo, error) {
 	structureType, err := s.eus.GetOrCreateTypeESI(ctx, typeID)
 	if err != nil {
-		return towerInfo{}, err
+		return nil, err
 	}
 	moon, err := s.eus.GetOrCreateMoonESI(ctx, moonID)
 	if err != nil {
-		return towerInfo{}, err
+		return nil, err
 	}
-	intro := fmt.Sprintf("The %s at %s in %s ", structureType.Name, moon.Name, makeSolarSystemLink(moon.SolarSystem))
-	x := towerInfo{
-		type_: structureType,
-		moon:  moon,
-		intro: intro,
+	d.Moon = moon
+	d.SolarSystem = moon.SolarSystem
+	d.StructureType = structureType
+	return d, nil
+}
+
+func (d TowerData) render() (string, string) {
+	var title, body string
+	intro := fmt.Sprintf("The %s at %s in %s ", d.StructureType.Name, d.Moon.Name, makeSolarSystemLink(d.SolarSystem))
+	switch d.Type {
+	case TowerAlertMsg:
+		title = fmt.Sprintf("Starbase at %s is under attack", d.Moon.Name)
+		body = fmt.Sprintf(
+			"%s is under attack.\n\n%s",
+			intro,
+			makeAggressorText(d.AggressorCharacter, d.AggressorCorporation, d.AggressorAlliance),
+		)
+	case TowerResourceAlertMsg:
+		title = fmt.Sprintf("Starbase at %s is running out of fuel", d.Moon.Name)
+		body = fmt.Sprintf("%s is running out of fuel in less then 24hrs.\n\n", intro)
+		if !d.FuelRemaining.IsEmpty() {
+			body += fmt.Sprintf("Fuel remaining: %s units", humanize.Comma(int64(d.FuelRemaining.ValueOrZero())))
+		}
 	}
-	return x, nil
+	return title, body
 }
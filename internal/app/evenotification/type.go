@@ -3,6 +3,8 @@ package evenotification
 import (
 	"strings"
 	"unicode"
+
+	"github.com/ErikKalkoken/evebuddy/internal/set"
 )
 
 type Type string
@@ -323,9 +325,57 @@ var supportedTypes = []Type{
 	EntosisCaptureStarted,
 	SovStructureReinforced,
 	SovStructureDestroyed,
+	BountyClaimMsg,
+	BountyESSShared,
+	BountyESSTaken,
+	BountyPlacedAlliance,
+	BountyPlacedChar,
+	BountyPlacedCorp,
+	BountyYourBountyClaimed,
+	CloneActivationMsg,
+	CloneActivationMsg2,
+	CloneMovedMsg,
+	CloneRevokedMsg1,
+	CloneRevokedMsg2,
+	JumpCloneDeletedMsg1,
+	JumpCloneDeletedMsg2,
+	InsuranceExpirationMsg,
+	InsuranceFirstShipMsg,
+	InsuranceInvalidatedMsg,
+	InsuranceIssuedMsg,
+	InsurancePayoutMsg,
+	KillReportFinalBlow,
+	KillReportVictim,
+	KillRightAvailable,
+	KillRightAvailableOpen,
+	KillRightEarned,
+	KillRightUnavailable,
+	KillRightUnavailableOpen,
+	KillRightUsed,
 }
 
 // SupportedGroups returns a list of all supported notification types.
 func SupportedGroups() []Type {
 	return supportedTypes
 }
+
+// criticalTypes are notification types which require immediate attention,
+// e.g. because an asset is under attack.
+var criticalTypes = set.New(
+	EntosisCaptureStarted,
+	OrbitalAttacked,
+	OrbitalReinforced,
+	SkyhookUnderAttack,
+	SovStructureReinforced,
+	StructureDestroyed,
+	StructureLostArmor,
+	StructureLostShields,
+	StructureUnderAttack,
+	TowerAlertMsg,
+)
+
+// IsCritical reports whether a type requires immediate attention.
+// Critical types are delivered during quiet hours.
+func (nt Type) IsCritical() bool {
+	return criticalTypes.Contains(nt)
+}
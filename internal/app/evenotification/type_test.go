@@ -16,4 +16,8 @@ func TestType(t *testing.T) {
 		x := evenotification.SovereigntyTCUDamageMsg
 		assert.Equal(t, "Sovereignty TCU Damage Msg", x.Display())
 	})
+	t.Run("can report critical types", func(t *testing.T) {
+		assert.True(t, evenotification.StructureUnderAttack.IsCritical())
+		assert.False(t, evenotification.BountyClaimMsg.IsCritical())
+	})
 }
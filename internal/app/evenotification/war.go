@@ -3,278 +3,256 @@ package evenotification
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
-	"github.com/ErikKalkoken/evebuddy/internal/optional"
 	"github.com/antihax/goesi/notification"
 	"gopkg.in/yaml.v3"
 )
 
-func (s *EveNotificationService) renderWar(ctx context.Context, type_ Type, text string) (optional.Optional[string], optional.Optional[string], error) {
-	switch type_ {
+// WarData is the structured data of a notification about a war.
+type WarData struct {
+	BaseData
+	Against    *app.EveEntity // party the war was declared against
+	Alliance   *app.EveEntity // alliance a party left. Nil when not applicable.
+	Character  *app.EveEntity // character who declared the war. Nil when not applicable.
+	DeclaredAt time.Time      // zero when not included in notification
+	DeclaredBy *app.EveEntity // party which declared the war
+	DelayHours int            // hours until fighting can start or the war ends
+	EndsAt     time.Time      // when fighting must cease. Zero when not included in notification.
+	Opponent   *app.EveEntity // nil when not applicable
+	Quitter    *app.EveEntity // party which left an alliance. Nil when not applicable.
+	WarHQ      string
+}
+
+func (d WarData) Entities() []*app.EveEntity {
+	return compactEntities(d.Against, d.Alliance, d.Character, d.DeclaredBy, d.Opponent, d.Quitter)
+}
+
+func (s *EveNotificationService) dataWar(ctx context.Context, base BaseData, text string) (Data, error) {
+	var againstID, allianceID, characterID, declaredByID, opponentID, quitterID int32
+	d := WarData{BaseData: base}
+	switch base.Type {
 	case AllWarSurrenderMsg:
-		return s.renderAllWarSurrenderMsg(ctx, text)
+		var data notification.AllWarSurrenderMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, declaredByID = data.AgainstID, data.DeclaredByID
+		d.DelayHours = int(data.DelayHours)
 	case CorpWarSurrenderMsg:
-		return s.renderCorpWarSurrenderMsg(ctx, text)
+		var data notification.CorpWarSurrenderMsg
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, declaredByID = data.AgainstID, data.DeclaredByID
+		d.DelayHours = 24
 	case DeclareWar:
-		return s.renderDeclareWar(ctx, text)
+		var data notification.DeclareWar
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, characterID, declaredByID = data.DefenderID, data.CharID, data.EntityID
 	case WarAdopted:
-		return s.renderWarAdopted(ctx, text)
+		var data notification.WarAdopted
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, allianceID, declaredByID = data.AgainstID, data.AllianceID, data.DeclaredByID
 	case WarDeclared:
-		return s.renderWarDeclared(ctx, text)
+		var data notification.WarDeclared
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, declaredByID = data.AgainstID, data.DeclaredByID
+		d.DelayHours = int(data.DelayHours)
+		d.WarHQ = data.WarHQ
 	case WarHQRemovedFromSpace:
-		return s.renderWarHQRemovedFromSpace(ctx, text)
+		var data notification.WarHQRemovedFromSpace
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, declaredByID = data.AgainstID, data.DeclaredByID
+		d.DeclaredAt = fromLDAPTime(data.TimeDeclared)
+		d.WarHQ = data.WarHQ
 	case WarInherited:
-		return s.renderWarInherited(ctx, text)
+		var data notification.WarInherited
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, allianceID, declaredByID = data.AgainstID, data.AllianceID, data.DeclaredByID
+		opponentID, quitterID = data.OpponentID, data.QuitterID
 	case WarInvalid:
-		return s.renderWarInvalid(ctx, text)
+		var data notification.WarInvalid
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, declaredByID = data.AgainstID, data.DeclaredByID
+		d.EndsAt = fromLDAPTime(data.EndDate)
 	case WarRetractedByConcord:
-		return s.renderWarRetractedByConcord(ctx, text)
+		var data notification.WarRetractedByConcord
+		if err := yaml.Unmarshal([]byte(text), &data); err != nil {
+			return nil, err
+		}
+		againstID, declaredByID = data.AgainstID, data.DeclaredByID
+		d.EndsAt = fromLDAPTime(data.EndDate)
 	}
-	panic("Notification type not implemented: " + type_)
-}
-
-func (s *EveNotificationService) renderAllWarSurrenderMsg(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.AllWarSurrenderMsg
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
+	ids := []int32{againstID, declaredByID}
+	for _, id := range []int32{allianceID, characterID, opponentID, quitterID} {
+		if id != 0 {
+			ids = append(ids, id)
+		}
 	}
-	entities, err := s.eus.ToEntities(ctx, []int32{data.AgainstID, data.DeclaredByID})
+	entities, err := s.eus.ToEntities(ctx, ids)
 	if err != nil {
-		return title, body, err
+		return nil, err
 	}
-	title.Set(fmt.Sprintf(
-		"%s has surrendered in the war against %s",
-		entities[data.DeclaredByID].Name,
-		entities[data.AgainstID].Name,
-	))
-	out := fmt.Sprintf(
-		"%s has surrendered in the war against %s.\n\n"+
-			"The war will be declared as being over after approximately %d hours.",
-		makeEveEntityProfileLink(entities[data.DeclaredByID]),
-		makeEveEntityProfileLink(entities[data.AgainstID]),
-		data.DelayHours,
-	)
-	body.Set(out)
-	return title, body, nil
-}
-
-func (s *EveNotificationService) renderCorpWarSurrenderMsg(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.CorpWarSurrenderMsg
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
+	d.Against = entities[againstID]
+	d.DeclaredBy = entities[declaredByID]
+	if allianceID != 0 {
+		d.Alliance = entities[allianceID]
 	}
-	entities, err := s.eus.ToEntities(ctx, []int32{data.AgainstID, data.DeclaredByID})
-	if err != nil {
-		return title, body, err
+	if characterID != 0 {
+		d.Character = entities[characterID]
 	}
-	title.Set("One party has surrendered")
-	out := fmt.Sprintf(
-		"The war between %s and %s is coming to an end as one party has surrendered.\n\n"+
-			"The war will be declared as being over after approximately 24 hours.",
-		makeEveEntityProfileLink(entities[data.DeclaredByID]),
-		makeEveEntityProfileLink(entities[data.AgainstID]),
-	)
-	body.Set(out)
-	return title, body, nil
-}
-
-func (s *EveNotificationService) renderDeclareWar(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.DeclareWar
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
+	if opponentID != 0 {
+		d.Opponent = entities[opponentID]
 	}
-	entities, err := s.eus.ToEntities(ctx, []int32{data.CharID, data.DefenderID, data.EntityID})
-	if err != nil {
-		return title, body, err
+	if quitterID != 0 {
+		d.Quitter = entities[quitterID]
 	}
-	title.Set(fmt.Sprintf("%s declared war", entities[data.EntityID].Name))
-	out := fmt.Sprintf(
-		"%s has declared war on %s on behalf of %s.",
-		makeEveEntityProfileLink(entities[data.CharID]),
-		makeEveEntityProfileLink(entities[data.DefenderID]),
-		makeEveEntityProfileLink(entities[data.EntityID]),
-	)
-	body.Set(out)
-	return title, body, nil
+	return d, nil
 }
 
-func (s *EveNotificationService) renderWarAdopted(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.WarAdopted
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	entities, err := s.eus.ToEntities(
-		ctx, []int32{data.AgainstID, data.DeclaredByID, data.AllianceID},
-	)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"War update: %s has left %s",
-		entities[data.AgainstID].Name,
-		entities[data.AllianceID].Name,
-	))
-	declaredBy := makeEveEntityProfileLink(entities[data.DeclaredByID])
-	alliance := makeEveEntityProfileLink(entities[data.AllianceID])
-	against := makeEveEntityProfileLink(entities[data.AgainstID])
-	out := fmt.Sprintf(
-		"There has been a development in the war between %s and %s.\n"+
-			"%s is no longer a member of %s, "+
-			"and therefore a new war between %s and %s has begun.",
-		declaredBy,
-		alliance,
-		against,
-		alliance,
-		declaredBy,
-		alliance,
-	)
-	body.Set(out)
-	return title, body, nil
-}
+func (d WarData) render() (string, string) {
+	var title, body string
+	switch d.Type {
+	case AllWarSurrenderMsg:
+		title = fmt.Sprintf(
+			"%s has surrendered in the war against %s",
+			d.DeclaredBy.Name,
+			d.Against.Name,
+		)
+		body = fmt.Sprintf(
+			"%s has surrendered in the war against %s.\n\n"+
+				"The war will be declared as being over after approximately %d hours.",
+			makeEveEntityProfileLink(d.DeclaredBy),
+			makeEveEntityProfileLink(d.Against),
+			d.DelayHours,
+		)
 
-func (s *EveNotificationService) renderWarDeclared(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.WarDeclared
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	entities, err := s.eus.ToEntities(ctx, []int32{data.AgainstID, data.DeclaredByID})
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"%s Declares War Against %s",
-		entities[data.DeclaredByID].Name,
-		entities[data.AgainstID].Name,
-	))
-	out := fmt.Sprintf(
-		"%s has declared war on %s with **%s** "+
-			"as the designated war headquarters.\n\n"+
-			"Within **%d** hours fighting can legally occur between those involved.",
-		makeEveEntityProfileLink(entities[data.DeclaredByID]),
-		makeEveEntityProfileLink(entities[data.AgainstID]),
-		data.WarHQ,
-		data.DelayHours,
-	)
-	body.Set(out)
-	return title, body, nil
-}
+	case CorpWarSurrenderMsg:
+		title = "One party has surrendered"
+		body = fmt.Sprintf(
+			"The war between %s and %s is coming to an end as one party has surrendered.\n\n"+
+				"The war will be declared as being over after approximately %d hours.",
+			makeEveEntityProfileLink(d.DeclaredBy),
+			makeEveEntityProfileLink(d.Against),
+			d.DelayHours,
+		)
 
-func (s *EveNotificationService) renderWarHQRemovedFromSpace(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.WarHQRemovedFromSpace
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	entities, err := s.eus.ToEntities(ctx, []int32{data.AgainstID, data.DeclaredByID})
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf("WarHQ %s lost", data.WarHQ))
-	out := fmt.Sprintf(
-		"The war HQ **%s** is no more. "+
-			"As a consequence, the war declared by %s against %s on %s "+
-			"has been declared invalid by CONCORD and has entered its cooldown period.",
-		data.WarHQ,
-		makeEveEntityProfileLink(entities[data.DeclaredByID]),
-		makeEveEntityProfileLink(entities[data.AgainstID]),
-		fromLDAPTime(data.TimeDeclared).Format(app.DateTimeFormat),
-	)
-	body.Set(out)
-	return title, body, nil
-}
+	case DeclareWar:
+		title = fmt.Sprintf("%s declared war", d.DeclaredBy.Name)
+		body = fmt.Sprintf(
+			"%s has declared war on %s on behalf of %s.",
+			makeEveEntityProfileLink(d.Character),
+			makeEveEntityProfileLink(d.Against),
+			makeEveEntityProfileLink(d.DeclaredBy),
+		)
 
-func (s *EveNotificationService) renderWarInherited(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.WarInherited
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	entities, err := s.eus.ToEntities(
-		ctx,
-		[]int32{
-			data.AgainstID,
-			data.AllianceID,
-			data.DeclaredByID,
-			data.OpponentID,
-			data.QuitterID,
-		},
-	)
-	if err != nil {
-		return title, body, err
-	}
-	title.Set(fmt.Sprintf(
-		"War update: %s has left %s",
-		entities[data.QuitterID].Name,
-		entities[data.AllianceID].Name,
-	))
-	alliance := makeEveEntityProfileLink(entities[data.AllianceID])
-	against := makeEveEntityProfileLink(entities[data.AgainstID])
-	quitter := makeEveEntityProfileLink(entities[data.QuitterID])
-	out := fmt.Sprintf(
-		"There has been a development in the war between %s and %s.\n\n"+
-			"%s is no longer a member of %s, and therefore a new war between %s and %s has begun.",
-		alliance,
-		against,
-		quitter,
-		alliance,
-		against,
-		quitter,
-	)
-	body.Set(out)
-	return title, body, nil
-}
+	case WarAdopted:
+		title = fmt.Sprintf(
+			"War update: %s has left %s",
+			d.Against.Name,
+			entityName(d.Alliance),
+		)
+		declaredBy := makeEveEntityProfileLink(d.DeclaredBy)
+		alliance := makeEveEntityProfileLink(d.Alliance)
+		against := makeEveEntityProfileLink(d.Against)
+		body = fmt.Sprintf(
+			"There has been a development in the war between %s and %s.\n"+
+				"%s is no longer a member of %s, "+
+				"and therefore a new war between %s and %s has begun.",
+			declaredBy,
+			alliance,
+			against,
+			alliance,
+			declaredBy,
+			alliance,
+		)
 
-func (s *EveNotificationService) renderWarInvalid(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.WarInvalid
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	entities, err := s.eus.ToEntities(ctx, []int32{data.AgainstID, data.DeclaredByID})
-	if err != nil {
-		return title, body, err
-	}
-	title.Set("CONCORD invalidates war")
-	out := fmt.Sprintf(
-		"The war between %s and %s "+
-			"has been invalidated by CONCORD, "+
-			"because at least one of the involved parties "+
-			"has become ineligible for war declarations.\n\n"+
-			"Fighting must cease on %s.",
-		makeEveEntityProfileLink(entities[data.DeclaredByID]),
-		makeEveEntityProfileLink(entities[data.AgainstID]),
-		fromLDAPTime(data.EndDate).Format(app.DateTimeFormat),
-	)
-	body.Set(out)
-	return title, body, nil
-}
+	case WarDeclared:
+		title = fmt.Sprintf(
+			"%s Declares War Against %s",
+			d.DeclaredBy.Name,
+			d.Against.Name,
+		)
+		body = fmt.Sprintf(
+			"%s has declared war on %s with **%s** "+
+				"as the designated war headquarters.\n\n"+
+				"Within **%d** hours fighting can legally occur between those involved.",
+			makeEveEntityProfileLink(d.DeclaredBy),
+			makeEveEntityProfileLink(d.Against),
+			d.WarHQ,
+			d.DelayHours,
+		)
 
-func (s *EveNotificationService) renderWarRetractedByConcord(ctx context.Context, text string) (optional.Optional[string], optional.Optional[string], error) {
-	var title, body optional.Optional[string]
-	var data notification.WarRetractedByConcord
-	if err := yaml.Unmarshal([]byte(text), &data); err != nil {
-		return title, body, err
-	}
-	entities, err := s.eus.ToEntities(ctx, []int32{data.AgainstID, data.DeclaredByID})
-	if err != nil {
-		return title, body, err
+	case WarHQRemovedFromSpace:
+		title = fmt.Sprintf("WarHQ %s lost", d.WarHQ)
+		body = fmt.Sprintf(
+			"The war HQ **%s** is no more. "+
+				"As a consequence, the war declared by %s against %s on %s "+
+				"has been declared invalid by CONCORD and has entered its cooldown period.",
+			d.WarHQ,
+			makeEveEntityProfileLink(d.DeclaredBy),
+			makeEveEntityProfileLink(d.Against),
+			d.DeclaredAt.Format(app.DateTimeFormat),
+		)
+
+	case WarInherited:
+		title = fmt.Sprintf(
+			"War update: %s has left %s",
+			entityName(d.Quitter),
+			entityName(d.Alliance),
+		)
+		alliance := makeEveEntityProfileLink(d.Alliance)
+		against := makeEveEntityProfileLink(d.Against)
+		quitter := makeEveEntityProfileLink(d.Quitter)
+		body = fmt.Sprintf(
+			"There has been a development in the war between %s and %s.\n\n"+
+				"%s is no longer a member of %s, and therefore a new war between %s and %s has begun.",
+			alliance,
+			against,
+			quitter,
+			alliance,
+			against,
+			quitter,
+		)
+
+	case WarInvalid:
+		title = "CONCORD invalidates war"
+		body = fmt.Sprintf(
+			"The war between %s and %s "+
+				"has been invalidated by CONCORD, "+
+				"because at least one of the involved parties "+
+				"has become ineligible for war declarations.\n\n"+
+				"Fighting must cease on %s.",
+			makeEveEntityProfileLink(d.DeclaredBy),
+			makeEveEntityProfileLink(d.Against),
+			d.EndsAt.Format(app.DateTimeFormat),
+		)
+
+	case WarRetractedByConcord:
+		title = "CONCORD retracts war"
+		body = fmt.Sprintf(
+			"The war between %s and %s "+
+				"has been retracted by CONCORD. \n\n"+
+				"After %s CONCORD will again respond to any hostilities "+
+				"between those involved with full force.",
+			makeEveEntityProfileLink(d.DeclaredBy),
+			makeEveEntityProfileLink(d.Against),
+			d.EndsAt.Format(app.DateTimeFormat),
+		)
 	}
-	title.Set("CONCORD retracts war")
-	out := fmt.Sprintf(
-		"The war between %s and %s "+
-			"has been retracted by CONCORD. \n\n"+
-			"After %s CONCORD will again respond to any hostilities "+
-			"between those involved with full force.",
-		makeEveEntityProfileLink(entities[data.DeclaredByID]),
-		makeEveEntityProfileLink(entities[data.AgainstID]),
-		fromLDAPTime(data.EndDate).Format(app.DateTimeFormat),
-	)
-	body.Set(out)
-	return title, body, nil
+	return title, body
 }
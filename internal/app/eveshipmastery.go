@@ -0,0 +1,83 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+// EveShipMasteryLevelMax is the highest mastery level of a ship.
+const EveShipMasteryLevelMax = 5
+
+// EveShipMasterySkill is a skill required for a mastery level of a ship.
+type EveShipMasterySkill struct {
+	Level       int // mastery level from 1 to 5
+	ShipTypeID  int32
+	SkillLevel  int
+	SkillName   string
+	SkillTypeID int32
+}
+
+// EveShipMastery represents the skill requirements for the mastery levels of a ship.
+// Mastery levels are based on certificates and show how well a pilot can fly a ship,
+// which goes well beyond the skills required to fly the hull.
+type EveShipMastery struct {
+	ShipTypeID   int32
+	Requirements [EveShipMasteryLevelMax][]SkillRequirement // requirements by level, starting with level 1
+}
+
+// NewEveShipMasteries returns the masteries for the given mastery skills by ship type ID.
+func NewEveShipMasteries(skills []EveShipMasterySkill) map[int32]*EveShipMastery {
+	m := make(map[int32]*EveShipMastery)
+	for _, s := range skills {
+		if s.Level < 1 || s.Level > EveShipMasteryLevelMax {
+			continue
+		}
+		x, ok := m[s.ShipTypeID]
+		if !ok {
+			x = &EveShipMastery{ShipTypeID: s.ShipTypeID}
+			m[s.ShipTypeID] = x
+		}
+		x.Requirements[s.Level-1] = append(x.Requirements[s.Level-1], SkillRequirement{
+			Level:     s.SkillLevel,
+			SkillID:   s.SkillTypeID,
+			SkillName: s.SkillName,
+		})
+	}
+	return m
+}
+
+// RequiredSkills returns the skills required to reach a mastery level,
+// which includes the skills required for all lower levels.
+func (m EveShipMastery) RequiredSkills(level int) []SkillRequirement {
+	level = max(0, min(level, EveShipMasteryLevelMax))
+	return MergeSkillRequirements(m.Requirements[:level]...)
+}
+
+// Level returns the highest mastery level reached with the trained skill levels
+// or 0 if no level has been reached.
+func (m EveShipMastery) Level(levels map[int32]int) int {
+	for l := 1; l <= EveShipMasteryLevelMax; l++ {
+		if len(CheckSkillRequirements(levels, m.RequiredSkills(l))) > 0 {
+			return l - 1
+		}
+	}
+	return EveShipMasteryLevelMax
+}
+
+// CharacterShipMastery is the mastery of a ship reached by a character.
+type CharacterShipMastery struct {
+	CharacterID  int32
+	Level        int            // mastery level reached or 0 if none
+	Missing      []MissingSkill // skills missing for the next level
+	ShipTypeID   int32
+	TrainingTime optional.Optional[time.Duration] // time to train the missing skills with the current attributes
+}
+
+// NextLevel returns the next mastery level or 0 when the highest level has been reached.
+func (m CharacterShipMastery) NextLevel() int {
+	if m.Level >= EveShipMasteryLevelMax {
+		return 0
+	}
+	return m.Level + 1
+}
@@ -0,0 +1,56 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+func TestEveShipMastery(t *testing.T) {
+	skills := []app.EveShipMasterySkill{
+		{ShipTypeID: 42, Level: 1, SkillTypeID: 1, SkillName: "Alpha", SkillLevel: 1},
+		{ShipTypeID: 42, Level: 2, SkillTypeID: 1, SkillName: "Alpha", SkillLevel: 3},
+		{ShipTypeID: 42, Level: 2, SkillTypeID: 2, SkillName: "Bravo", SkillLevel: 2},
+		{ShipTypeID: 42, Level: 3, SkillTypeID: 2, SkillName: "Bravo", SkillLevel: 4},
+		{ShipTypeID: 42, Level: 4, SkillTypeID: 3, SkillName: "Charlie", SkillLevel: 4},
+		{ShipTypeID: 42, Level: 5, SkillTypeID: 3, SkillName: "Charlie", SkillLevel: 5},
+		{ShipTypeID: 43, Level: 1, SkillTypeID: 1, SkillName: "Alpha", SkillLevel: 5},
+	}
+	mm := app.NewEveShipMasteries(skills)
+	t.Run("can group skills by ship", func(t *testing.T) {
+		assert.Len(t, mm, 2)
+		assert.Len(t, mm[42].Requirements[1], 2)
+	})
+	t.Run("can return required skills for a level", func(t *testing.T) {
+		got := mm[42].RequiredSkills(3)
+		want := []app.SkillRequirement{
+			{SkillID: 1, SkillName: "Alpha", Level: 3},
+			{SkillID: 2, SkillName: "Bravo", Level: 4},
+		}
+		assert.Equal(t, want, got)
+	})
+	cases := []struct {
+		name   string
+		levels map[int32]int
+		want   int
+	}{
+		{"no skills", map[int32]int{}, 0},
+		{"first level", map[int32]int{1: 2}, 1},
+		{"third level", map[int32]int{1: 3, 2: 4}, 3},
+		{"higher level requires lower levels", map[int32]int{1: 2, 2: 5, 3: 5}, 1},
+		{"all levels", map[int32]int{1: 5, 2: 5, 3: 5}, 5},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, mm[42].Level(tc.levels))
+		})
+	}
+}
+
+func TestCharacterShipMasteryNextLevel(t *testing.T) {
+	assert.Equal(t, 1, app.CharacterShipMastery{Level: 0}.NextLevel())
+	assert.Equal(t, 4, app.CharacterShipMastery{Level: 3}.NextLevel())
+	assert.Equal(t, 0, app.CharacterShipMastery{Level: 5}.NextLevel())
+}
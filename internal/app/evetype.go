@@ -11,7 +11,11 @@ const (
 	EveTypeIHUB                        = 32458
 	EveTypeInfomorphSynchronizing      = 33399
 	EveTypeInterplanetaryConsolidation = 2495
+	EveTypeLargeSkillInjector          = 40520
+	EveTypeMoon                        = 14
 	EveTypePlanetTemperate             = 11
+	EveTypeSkillExtractor              = 40519
+	EveTypeSmallSkillInjector          = 45635
 	EveTypeSolarSystem                 = 5
 	EveTypeTCU                         = 32226
 )
@@ -33,7 +33,9 @@ type EveUniverseService interface {
 	GetOrCreateGroupESI(ctx context.Context, id int32) (*EveGroup, error)
 	GetOrCreateTypeESI(ctx context.Context, id int32) (*EveType, error)
 	AddMissingTypes(ctx context.Context, ids []int32) error
+	ResolveTypeNamesESI(ctx context.Context, names []string) (map[string]int32, error)
 	UpdateCategoryWithChildrenESI(ctx context.Context, categoryID int32) error
+	UpdateShipMasteries(ctx context.Context) error
 	UpdateShipSkills(ctx context.Context) error
 	ListTypeDogmaAttributesForType(ctx context.Context, typeID int32) ([]*EveTypeDogmaAttribute, error)
 	GetLocation(ctx context.Context, id int64) (*EveLocation, error)
@@ -2,6 +2,7 @@
 package eveuniverseservice
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
@@ -12,6 +13,8 @@ import (
 
 // EveUniverseService provides access to Eve Online models with on-demand loading from ESI and persistent local caching.
 type EveUniverseService struct {
+	// HTTPClient is used for fetching data from sources other than ESI.
+	HTTPClient         *http.Client
 	StatusCacheService app.StatusCacheService
 	// Now returns the current time in UTC. Can be overwritten for tests.
 	Now func() time.Time
@@ -24,9 +27,10 @@ type EveUniverseService struct {
 // New returns a new instance of an Eve universe service.
 func New(st *storage.Storage, esiClient *goesi.APIClient) *EveUniverseService {
 	eu := &EveUniverseService{
-		esiClient: esiClient,
-		st:        st,
-		sfg:       new(singleflight.Group),
+		HTTPClient: http.DefaultClient,
+		esiClient:  esiClient,
+		st:         st,
+		sfg:        new(singleflight.Group),
 		Now: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -165,6 +165,22 @@ func (s *EveUniverseService) createTypeFromESI(ctx context.Context, id int32) (*
 	return x.(*app.EveType), nil
 }
 
+// ResolveTypeNamesESI returns the IDs of types by their exact names as fetched from ESI.
+// Names which can not be resolved are not included in the result.
+func (s *EveUniverseService) ResolveTypeNamesESI(ctx context.Context, names []string) (map[string]int32, error) {
+	ids := make(map[string]int32)
+	for chunk := range slices.Chunk(names, 500) { // PostUniverseIds max is 500 names
+		r, _, err := s.esiClient.ESI.UniverseApi.PostUniverseIds(ctx, chunk, nil)
+		if err != nil {
+			return nil, fmt.Errorf("resolve type names: %w", err)
+		}
+		for _, o := range r.InventoryTypes {
+			ids[o.Name] = o.Id
+		}
+	}
+	return ids, nil
+}
+
 func (s *EveUniverseService) AddMissingTypes(ctx context.Context, ids []int32) error {
 	missingIDs, err := s.st.MissingEveTypes(ctx, ids)
 	if err != nil {
@@ -270,3 +270,35 @@ func TestGetOrCreateEveTypeESI(t *testing.T) {
 		}
 	})
 }
+
+func TestResolveTypeNamesESI(t *testing.T) {
+	db, r, _ := testutil.New()
+	defer db.Close()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	client := goesi.NewAPIClient(nil, "")
+	s := eveuniverseservice.New(r, client)
+	ctx := context.Background()
+	t.Run("should return IDs for resolved type names", func(t *testing.T) {
+		// given
+		httpmock.Reset()
+		data := `{
+			"inventory_types": [
+			  {
+				"id": 29984,
+				"name": "Tengu"
+			  }
+			]
+		  }`
+		httpmock.RegisterResponder(
+			"POST",
+			"https://esi.evetech.net/v1/universe/ids/",
+			httpmock.NewStringResponder(200, data).HeaderSet(http.Header{"Content-Type": []string{"application/json"}}))
+		// when
+		got, err := s.ResolveTypeNamesESI(ctx, []string{"Tengu", "Unknown"})
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]int32{"Tengu": 29984}, got)
+		}
+	})
+}
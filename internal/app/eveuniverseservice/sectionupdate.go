@@ -41,6 +41,8 @@ func (s *EveUniverseService) UpdateSection(ctx context.Context, section app.Gene
 		f = s.UpdateAllCharactersESI
 	case app.SectionEveMarketPrices:
 		f = s.updateMarketPricesESI
+	case app.SectionEveMasteries:
+		f = s.UpdateShipMasteries
 	}
 	key := fmt.Sprintf("Update-section-%s", section)
 	_, err, _ = s.sfg.Do(key, func() (any, error) {
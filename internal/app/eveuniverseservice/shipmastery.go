@@ -0,0 +1,158 @@
+package eveuniverseservice
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+)
+
+// sdeDumpURL is the location of CSV dumps of the static data export (SDE).
+const sdeDumpURL = "https://www.fuzzwork.co.uk/dump/latest"
+
+// errSDETableUnavailable signals that a table of the SDE dump does not exist
+// or does not have the expected format.
+var errSDETableUnavailable = errors.New("SDE table unavailable")
+
+// UpdateShipMasteries replaces all certificates and ship masteries with the current definitions.
+//
+// Certificates and masteries are not provided by ESI
+// and are therefore fetched from a dump of the static data export.
+// When the dump no longer provides the expected tables or columns
+// the existing definitions are kept and a warning is logged.
+func (s *EveUniverseService) UpdateShipMasteries(ctx context.Context) error {
+	err := s.updateShipMasteries(ctx)
+	if errors.Is(err, errSDETableUnavailable) {
+		slog.Warn("Ship masteries not updated. Keeping existing data", "source", sdeDumpURL, "error", err)
+		return nil
+	}
+	return err
+}
+
+func (s *EveUniverseService) updateShipMasteries(ctx context.Context) error {
+	certificates, err := s.fetchSDETable(ctx, "certCerts", "certID", "name", "description")
+	if err != nil {
+		return err
+	}
+	certificateSkills, err := s.fetchSDETable(ctx, "certSkills", "certID", "certLevelInt", "skillID", "skillLevel")
+	if err != nil {
+		return err
+	}
+	masteries, err := s.fetchSDETable(ctx, "certMasteries", "certID", "masteryLevel", "typeID")
+	if err != nil {
+		return err
+	}
+	args1 := make([]storage.CreateEveCertificateParams, len(certificates))
+	for i, r := range certificates {
+		id, err := strconv.Atoi(r[0])
+		if err != nil {
+			return fmt.Errorf("certificate %v: %w", r, err)
+		}
+		args1[i] = storage.CreateEveCertificateParams{
+			ID:          int32(id),
+			Name:        r[1],
+			Description: r[2],
+		}
+	}
+	args2 := make([]storage.CreateEveCertificateSkillParams, 0, len(certificateSkills))
+	for _, r := range certificateSkills {
+		v, err := atoiAll(r)
+		if err != nil {
+			return fmt.Errorf("certificate skill %v: %w", r, err)
+		}
+		if v[3] == 0 {
+			continue // skill not required at this level
+		}
+		args2 = append(args2, storage.CreateEveCertificateSkillParams{
+			CertificateID: int32(v[0]),
+			Level:         v[1] + 1, // levels in the SDE start with 0
+			SkillTypeID:   int32(v[2]),
+			SkillLevel:    v[3],
+		})
+	}
+	args3 := make([]storage.CreateEveShipMasteryParams, len(masteries))
+	for i, r := range masteries {
+		v, err := atoiAll(r)
+		if err != nil {
+			return fmt.Errorf("ship mastery %v: %w", r, err)
+		}
+		args3[i] = storage.CreateEveShipMasteryParams{
+			CertificateID: int32(v[0]),
+			Level:         v[1] + 1, // levels in the SDE start with 0
+			ShipTypeID:    int32(v[2]),
+		}
+	}
+	if err := s.st.ReplaceEveShipMasteries(ctx, args1, args2, args3); err != nil {
+		return err
+	}
+	slog.Info("Updated ship masteries", "certificates", len(args1), "masteries", len(args3))
+	return nil
+}
+
+// fetchSDETable fetches a table from the SDE dump
+// and returns the values of the requested columns for each row.
+func (s *EveUniverseService) fetchSDETable(ctx context.Context, name string, columns ...string) ([][]string, error) {
+	url := fmt.Sprintf("%s/%s.csv", sdeDumpURL, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("fetch SDE table %s: %w", name, errSDETableUnavailable)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("fetch SDE table %s: %s", name, resp.Status)
+	}
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("fetch SDE table %s: %w", name, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("fetch SDE table %s: no header: %w", name, errSDETableUnavailable)
+	}
+	header := make(map[string]int)
+	for i, c := range records[0] {
+		header[c] = i
+	}
+	idx := make([]int, len(columns))
+	for i, c := range columns {
+		x, ok := header[c]
+		if !ok {
+			return nil, fmt.Errorf("fetch SDE table %s: missing column %s: %w", name, c, errSDETableUnavailable)
+		}
+		idx[i] = x
+	}
+	rows := make([][]string, 0, len(records)-1)
+	for _, r := range records[1:] {
+		row := make([]string, len(idx))
+		for i, x := range idx {
+			if x < len(r) {
+				row[i] = r[x]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func atoiAll(ss []string) ([]int, error) {
+	vv := make([]int, len(ss))
+	for i, s := range ss {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		vv[i] = v
+	}
+	return vv, nil
+}
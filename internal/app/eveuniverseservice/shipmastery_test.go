@@ -0,0 +1,135 @@
+package eveuniverseservice_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/antihax/goesi"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/eveuniverseservice"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestUpdateShipMasteries(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	s := eveuniverseservice.New(st, goesi.NewAPIClient(nil, ""))
+	ctx := context.Background()
+	t.Run("should load masteries from SDE dump", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		skill := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Spaceship Command"})
+		httpmock.RegisterResponder(
+			"GET",
+			"https://www.fuzzwork.co.uk/dump/latest/certCerts.csv",
+			httpmock.NewStringResponder(200, "certID,description,groupID,name\n1,\"Fly ships, well\",2,Navigation\n"),
+		)
+		httpmock.RegisterResponder(
+			"GET",
+			"https://www.fuzzwork.co.uk/dump/latest/certSkills.csv",
+			httpmock.NewStringResponder(200, fmt.Sprintf(
+				"certID,skillID,certLevelInt,skillLevel,certLevelText\n"+
+					"1,%d,0,1,basic\n"+
+					"1,%d,1,3,standard\n"+
+					"1,99,1,0,standard\n",
+				skill.ID,
+				skill.ID,
+			)),
+		)
+		httpmock.RegisterResponder(
+			"GET",
+			"https://www.fuzzwork.co.uk/dump/latest/certMasteries.csv",
+			httpmock.NewStringResponder(200, "typeID,masteryLevel,certID\n42,0,1\n42,1,1\n"),
+		)
+		// when
+		err := s.UpdateShipMasteries(ctx)
+		// then
+		if assert.NoError(t, err) {
+			got, err := st.ListEveShipMasterySkillsForShip(ctx, 42)
+			if assert.NoError(t, err) {
+				want := []app.EveShipMasterySkill{
+					{ShipTypeID: 42, Level: 1, SkillTypeID: skill.ID, SkillName: "Spaceship Command", SkillLevel: 1},
+					{ShipTypeID: 42, Level: 2, SkillTypeID: skill.ID, SkillName: "Spaceship Command", SkillLevel: 3},
+				}
+				assert.Equal(t, want, got)
+			}
+		}
+	})
+	t.Run("should keep existing masteries when column is missing", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		skill := factory.CreateEveType()
+		createShipMastery(t, st, 42, skill.ID)
+		httpmock.RegisterResponder(
+			"GET",
+			"https://www.fuzzwork.co.uk/dump/latest/certCerts.csv",
+			httpmock.NewStringResponder(200, "certID,groupID,name\n1,2,Navigation\n"),
+		)
+		// when
+		err := s.UpdateShipMasteries(ctx)
+		// then
+		if assert.NoError(t, err) {
+			got, err := st.ListEveShipMasterySkillsForShip(ctx, 42)
+			if assert.NoError(t, err) {
+				assert.Len(t, got, 1)
+			}
+		}
+	})
+	t.Run("should keep existing masteries when table is missing", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		skill := factory.CreateEveType()
+		createShipMastery(t, st, 42, skill.ID)
+		httpmock.RegisterResponder(
+			"GET",
+			"https://www.fuzzwork.co.uk/dump/latest/certCerts.csv",
+			httpmock.NewStringResponder(404, ""),
+		)
+		// when
+		err := s.UpdateShipMasteries(ctx)
+		// then
+		if assert.NoError(t, err) {
+			got, err := st.ListEveShipMasterySkillsForShip(ctx, 42)
+			if assert.NoError(t, err) {
+				assert.Len(t, got, 1)
+			}
+		}
+	})
+	t.Run("should return error when dump can not be fetched", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		httpmock.Reset()
+		httpmock.RegisterResponder(
+			"GET",
+			"https://www.fuzzwork.co.uk/dump/latest/certCerts.csv",
+			httpmock.NewStringResponder(503, ""),
+		)
+		// when
+		err := s.UpdateShipMasteries(ctx)
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func createShipMastery(t *testing.T, st *storage.Storage, shipTypeID, skillTypeID int32) {
+	t.Helper()
+	err := st.ReplaceEveShipMasteries(
+		context.Background(),
+		[]storage.CreateEveCertificateParams{{ID: 1, Name: "Navigation"}},
+		[]storage.CreateEveCertificateSkillParams{{CertificateID: 1, Level: 1, SkillTypeID: skillTypeID, SkillLevel: 1}},
+		[]storage.CreateEveShipMasteryParams{{CertificateID: 1, Level: 1, ShipTypeID: shipTypeID}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+// FittingCheck reports which characters have the skills to use a ship fitting.
+type FittingCheck struct {
+	Characters   []FittingCheckCharacter
+	FittingName  string
+	Requirements []SkillRequirement // combined skill requirements of all types in the fitting
+	ShipTypeID   int32
+	ShipTypeName string
+	UnknownTypes []string // names of types which could not be identified
+}
+
+// CanUse returns the characters which can use the fitting.
+func (fc FittingCheck) CanUse() []FittingCheckCharacter {
+	cc := make([]FittingCheckCharacter, 0)
+	for _, c := range fc.Characters {
+		if c.CanUse() {
+			cc = append(cc, c)
+		}
+	}
+	return cc
+}
+
+// FittingCheckCharacter is the result of a fitting check for a character.
+type FittingCheckCharacter struct {
+	CharacterID   int32
+	CharacterName string
+	Missing       []MissingSkill
+	TrainingTime  optional.Optional[time.Duration] // time to train the missing skills with the current attributes
+}
+
+// CanUse reports whether the character has all skills required for the fitting.
+func (c FittingCheckCharacter) CanUse() bool {
+	return len(c.Missing) == 0
+}
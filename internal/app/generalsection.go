@@ -18,18 +18,21 @@ const (
 	SectionEveCategories   GeneralSection = "Eve_Categories"
 	SectionEveCharacters   GeneralSection = "Eve_Characters"
 	SectionEveMarketPrices GeneralSection = "Eve_MarketPrices"
+	SectionEveMasteries    GeneralSection = "Eve_Masteries"
 )
 
 var GeneralSections = []GeneralSection{
 	SectionEveCategories,
 	SectionEveCharacters,
 	SectionEveMarketPrices,
+	SectionEveMasteries,
 }
 
 var generalSectionTimeouts = map[GeneralSection]time.Duration{
 	SectionEveCategories:   24 * time.Hour,
 	SectionEveCharacters:   1 * time.Hour,
 	SectionEveMarketPrices: 6 * time.Hour,
+	SectionEveMasteries:    24 * time.Hour,
 }
 
 func (gs GeneralSection) DisplayName() string {
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+type MoonExtractionStatus uint
+
+const (
+	MoonExtractionStatusUndefined MoonExtractionStatus = iota
+	MoonExtractionStatusCancelled
+	MoonExtractionStatusFractured
+	MoonExtractionStatusStarted
+)
+
+var mes2String = map[MoonExtractionStatus]string{
+	MoonExtractionStatusUndefined: "undefined",
+	MoonExtractionStatusCancelled: "cancelled",
+	MoonExtractionStatusFractured: "fractured",
+	MoonExtractionStatusStarted:   "started",
+}
+
+func (mes MoonExtractionStatus) String() string {
+	return mes2String[mes]
+}
+
+// MoonExtraction is a moon mining extraction of a corporation refinery.
+// Extractions are derived from the moon mining notifications of characters.
+type MoonExtraction struct {
+	ID             int64
+	AutoFractureAt time.Time // natural decay of the chunk
+	ChunkArrivalAt time.Time
+	Corporation    *EveEntity
+	LastNotified   optional.Optional[time.Time]
+	Moon           *EveMoon
+	Ores           []*MoonExtractionOre
+	StartedAt      optional.Optional[time.Time]
+	Status         MoonExtractionStatus
+	StructureID    int64
+	StructureName  string
+	UpdatedAt      time.Time
+}
+
+// IsActive reports whether the extraction has neither been fractured nor cancelled at time t.
+func (me MoonExtraction) IsActive(t time.Time) bool {
+	return me.Status == MoonExtractionStatusStarted && t.Before(me.AutoFractureAt)
+}
+
+// IsReady reports whether the chunk has arrived, but not yet fractured at time t.
+func (me MoonExtraction) IsReady(t time.Time) bool {
+	return me.IsActive(t) && !t.Before(me.ChunkArrivalAt)
+}
+
+// StatusDisplay returns the current status of an extraction for display.
+func (me MoonExtraction) StatusDisplay() string {
+	now := time.Now()
+	switch me.Status {
+	case MoonExtractionStatusCancelled:
+		return "Cancelled"
+	case MoonExtractionStatusFractured:
+		return "Fractured"
+	case MoonExtractionStatusStarted:
+		if !now.Before(me.AutoFractureAt) {
+			return "Fractured"
+		}
+		if me.IsReady(now) {
+			return "Ready"
+		}
+		return "Extracting"
+	}
+	return "?"
+}
+
+// EstimatedValue returns the estimated value of all ores in an extraction.
+// Returns an empty value when no prices are known.
+func (me MoonExtraction) EstimatedValue() optional.Optional[float64] {
+	var total float64
+	var found bool
+	for _, o := range me.Ores {
+		v := o.EstimatedValue()
+		if v.IsEmpty() {
+			continue
+		}
+		total += v.ValueOrZero()
+		found = true
+	}
+	if !found {
+		return optional.Optional[float64]{}
+	}
+	return optional.New(total)
+}
+
+// MoonExtractionOre is the estimated amount of an ore in a moon extraction.
+type MoonExtractionOre struct {
+	AveragePrice optional.Optional[float64]
+	Type         *EntityShort[int32]
+	TypeVolume   float64 // volume of one unit in m3
+	Volume       float64 // total volume in m3
+}
+
+// Quantity returns the estimated number of units.
+func (o MoonExtractionOre) Quantity() int {
+	if o.TypeVolume == 0 {
+		return 0
+	}
+	return int(o.Volume / o.TypeVolume)
+}
+
+// EstimatedValue returns the estimated value of an ore or an empty value when the price is unknown.
+func (o MoonExtractionOre) EstimatedValue() optional.Optional[float64] {
+	if o.AveragePrice.IsEmpty() {
+		return optional.Optional[float64]{}
+	}
+	return optional.New(float64(o.Quantity()) * o.AveragePrice.ValueOrZero())
+}
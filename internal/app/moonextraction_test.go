@@ -0,0 +1,56 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoonExtractionStatusDisplay(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name           string
+		status         app.MoonExtractionStatus
+		chunkArrivalAt time.Time
+		autoFractureAt time.Time
+		want           string
+	}{
+		{"extracting", app.MoonExtractionStatusStarted, now.Add(time.Hour), now.Add(4 * time.Hour), "Extracting"},
+		{"ready", app.MoonExtractionStatusStarted, now.Add(-time.Hour), now.Add(2 * time.Hour), "Ready"},
+		{"auto fractured", app.MoonExtractionStatusStarted, now.Add(-4 * time.Hour), now.Add(-time.Hour), "Fractured"},
+		{"fractured", app.MoonExtractionStatusFractured, now.Add(-time.Hour), now.Add(2 * time.Hour), "Fractured"},
+		{"cancelled", app.MoonExtractionStatusCancelled, now.Add(time.Hour), now.Add(4 * time.Hour), "Cancelled"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			x := app.MoonExtraction{
+				Status:         tc.status,
+				ChunkArrivalAt: tc.chunkArrivalAt,
+				AutoFractureAt: tc.autoFractureAt,
+			}
+			assert.Equal(t, tc.want, x.StatusDisplay())
+		})
+	}
+}
+
+func TestMoonExtractionEstimatedValue(t *testing.T) {
+	t.Run("should return sum of ores with known prices", func(t *testing.T) {
+		x := app.MoonExtraction{
+			Ores: []*app.MoonExtractionOre{
+				{AveragePrice: optional.New(100.0), TypeVolume: 10, Volume: 1000},
+				{AveragePrice: optional.New(5.0), TypeVolume: 10, Volume: 200},
+				{TypeVolume: 10, Volume: 5000},
+			},
+		}
+		assert.Equal(t, optional.New(10100.0), x.EstimatedValue())
+	})
+	t.Run("should return empty when no prices are known", func(t *testing.T) {
+		x := app.MoonExtraction{
+			Ores: []*app.MoonExtractionOre{{TypeVolume: 10, Volume: 5000}},
+		}
+		assert.True(t, x.EstimatedValue().IsEmpty())
+	})
+}
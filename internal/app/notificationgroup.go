@@ -4,11 +4,14 @@ type NotificationGroup uint
 
 const (
 	GroupBills NotificationGroup = iota + 1
+	GroupBounties
+	GroupClones
 	GroupFactionWarfare
 	GroupContacts
 	GroupCorporate
 	GroupInsurance
 	GroupInsurgencies
+	GroupKills
 	GroupMoonMining
 	GroupMiscellaneous
 	GroupOld
@@ -28,11 +31,14 @@ func (c NotificationGroup) String() string {
 var group2Name = map[NotificationGroup]string{
 	GroupAll:            "All",
 	GroupBills:          "Bills",
+	GroupBounties:       "Bounties",
+	GroupClones:         "Clones",
 	GroupContacts:       "Contacts",
 	GroupCorporate:      "Corporate",
 	GroupFactionWarfare: "Faction Warfare",
 	GroupInsurance:      "Insurance",
 	GroupInsurgencies:   "Insurgencies",
+	GroupKills:          "Kills",
 	GroupMiscellaneous:  "Miscellaneous",
 	GroupMoonMining:     "Moon Mining",
 	GroupOld:            "Old",
@@ -47,11 +53,14 @@ var group2Name = map[NotificationGroup]string{
 func NotificationGroups() []NotificationGroup {
 	return []NotificationGroup{
 		GroupBills,
+		GroupBounties,
+		GroupClones,
 		GroupFactionWarfare,
 		GroupContacts,
 		GroupCorporate,
 		GroupInsurance,
 		GroupInsurgencies,
+		GroupKills,
 		GroupMoonMining,
 		GroupMiscellaneous,
 		GroupOld,
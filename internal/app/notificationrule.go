@@ -0,0 +1,146 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+// NotificationRuleAction is the action of a notification rule.
+type NotificationRuleAction uint
+
+const (
+	RuleActionUndefined NotificationRuleAction = iota
+	RuleActionEscalate                         // deliver to all channels, even during quiet hours
+	RuleActionRoute                            // deliver to the channels of the rule
+	RuleActionSuppress                         // do not deliver
+)
+
+func (a NotificationRuleAction) String() string {
+	switch a {
+	case RuleActionEscalate:
+		return "escalate"
+	case RuleActionRoute:
+		return "route"
+	case RuleActionSuppress:
+		return "suppress"
+	}
+	return "undefined"
+}
+
+func (a NotificationRuleAction) Display() string {
+	titler := cases.Title(language.English)
+	return titler.String(a.String())
+}
+
+// NotificationRuleActions returns all defined actions.
+func NotificationRuleActions() []NotificationRuleAction {
+	return []NotificationRuleAction{RuleActionRoute, RuleActionSuppress, RuleActionEscalate}
+}
+
+// NotificationChannel is a channel for delivering notifications.
+type NotificationChannel uint
+
+const (
+	ChannelUndefined NotificationChannel = iota
+	ChannelDesktop
+	ChannelGotify
+	ChannelNtfy
+	ChannelSMTP
+	ChannelWebhook
+)
+
+func (c NotificationChannel) String() string {
+	switch c {
+	case ChannelDesktop:
+		return "Desktop"
+	case ChannelGotify:
+		return "Gotify"
+	case ChannelNtfy:
+		return "ntfy"
+	case ChannelSMTP:
+		return "Email (SMTP)"
+	case ChannelWebhook:
+		return "Webhook"
+	}
+	return "Undefined"
+}
+
+// NotificationChannels returns all defined channels.
+func NotificationChannels() []NotificationChannel {
+	return []NotificationChannel{ChannelDesktop, ChannelGotify, ChannelNtfy, ChannelSMTP, ChannelWebhook}
+}
+
+// NotificationRule decides how a communication is delivered.
+// A rule matches when all of it's filters match. Empty filters match everything.
+type NotificationRule struct {
+	ID                int64
+	Action            NotificationRuleAction
+	Channels          set.Set[NotificationChannel] // channels a communication is routed to
+	CharacterIDs      set.Set[int32]
+	Groups            set.Set[NotificationGroup]
+	IsEnabled         bool
+	Keyword           string // matches title or body of a communication, case insensitive
+	Name              string
+	Position          int    // rules are applied in order of their position
+	QuietHoursEnabled bool   // whether to suppress routed communications during quiet hours
+	QuietHoursEnd     int    // minutes after midnight in local time
+	QuietHoursStart   int    // minutes after midnight in local time
+	Sender            string // matches the name of the sender, case insensitive
+	Types             set.Set[string]
+}
+
+// Matches reports whether a rule matches a communication.
+func (r NotificationRule) Matches(n *CharacterNotification, group NotificationGroup) bool {
+	if !r.IsEnabled {
+		return false
+	}
+	if r.CharacterIDs.Size() > 0 && !r.CharacterIDs.Contains(n.CharacterID) {
+		return false
+	}
+	if (r.Groups.Size() > 0 || r.Types.Size() > 0) && !r.Groups.Contains(group) && !r.Types.Contains(n.Type) {
+		return false
+	}
+	if r.Sender != "" && (n.Sender == nil || !strings.EqualFold(n.Sender.Name, r.Sender)) {
+		return false
+	}
+	if r.Keyword != "" {
+		k := strings.ToLower(r.Keyword)
+		if !strings.Contains(strings.ToLower(n.TitleDisplay()), k) && !strings.Contains(strings.ToLower(n.Body.ValueOrZero()), k) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsQuiet reports whether t is within the quiet hours of a rule.
+// Quiet hours can span midnight, e.g. from 22:00 to 07:00.
+func (r NotificationRule) IsQuiet(t time.Time) bool {
+	if !r.QuietHoursEnabled || r.QuietHoursStart == r.QuietHoursEnd {
+		return false
+	}
+	m := t.Hour()*60 + t.Minute()
+	if r.QuietHoursStart < r.QuietHoursEnd {
+		return m >= r.QuietHoursStart && m < r.QuietHoursEnd
+	}
+	return m >= r.QuietHoursStart || m < r.QuietHoursEnd
+}
+
+// FormatTimeOfDay returns minutes after midnight as time of day, e.g. "07:30".
+func FormatTimeOfDay(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// ParseTimeOfDay parses a time of day like "07:30" and returns it as minutes after midnight.
+func ParseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day: %s: %w", s, ErrInvalid)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,91 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRuleMatches(t *testing.T) {
+	n := &app.CharacterNotification{
+		Body:        optional.New("The Astrahus in Amamake is under attack."),
+		CharacterID: 1,
+		Sender:      &app.EveEntity{Name: "Wayne Enterprises"},
+		Title:       optional.New("Structure under attack"),
+		Type:        "StructureUnderAttack",
+	}
+	cases := []struct {
+		name string
+		rule app.NotificationRule
+		want bool
+	}{
+		{"should match all when no filters", app.NotificationRule{IsEnabled: true}, true},
+		{"should not match when disabled", app.NotificationRule{}, false},
+		{"should match character", app.NotificationRule{IsEnabled: true, CharacterIDs: set.New[int32](1)}, true},
+		{"should not match other character", app.NotificationRule{IsEnabled: true, CharacterIDs: set.New[int32](2)}, false},
+		{"should match group", app.NotificationRule{IsEnabled: true, Groups: set.New(app.GroupStructure)}, true},
+		{"should not match other group", app.NotificationRule{IsEnabled: true, Groups: set.New(app.GroupWar)}, false},
+		{"should match type", app.NotificationRule{IsEnabled: true, Types: set.New("StructureUnderAttack")}, true},
+		{"should match sender", app.NotificationRule{IsEnabled: true, Sender: "wayne enterprises"}, true},
+		{"should not match other sender", app.NotificationRule{IsEnabled: true, Sender: "Alpha"}, false},
+		{"should match keyword in title", app.NotificationRule{IsEnabled: true, Keyword: "ATTACK"}, true},
+		{"should match keyword in body", app.NotificationRule{IsEnabled: true, Keyword: "amamake"}, true},
+		{"should not match other keyword", app.NotificationRule{IsEnabled: true, Keyword: "Jita"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.rule.Matches(n, app.GroupStructure)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNotificationRuleIsQuiet(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 3, 14, hour, minute, 0, 0, time.Local)
+	}
+	cases := []struct {
+		name  string
+		start int
+		end   int
+		t     time.Time
+		want  bool
+	}{
+		{"within quiet hours", 8 * 60, 12 * 60, at(9, 0), true},
+		{"before quiet hours", 8 * 60, 12 * 60, at(7, 59), false},
+		{"end is not quiet", 8 * 60, 12 * 60, at(12, 0), false},
+		{"spanning midnight evening", 22 * 60, 7 * 60, at(23, 0), true},
+		{"spanning midnight morning", 22 * 60, 7 * 60, at(6, 59), true},
+		{"spanning midnight day", 22 * 60, 7 * 60, at(12, 0), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := app.NotificationRule{QuietHoursEnabled: true, QuietHoursStart: tc.start, QuietHoursEnd: tc.end}
+			assert.Equal(t, tc.want, r.IsQuiet(tc.t))
+		})
+	}
+	t.Run("should never be quiet when disabled", func(t *testing.T) {
+		r := app.NotificationRule{QuietHoursStart: 8 * 60, QuietHoursEnd: 12 * 60}
+		assert.False(t, r.IsQuiet(at(9, 0)))
+	})
+}
+
+func TestTimeOfDay(t *testing.T) {
+	t.Run("can parse", func(t *testing.T) {
+		x, err := app.ParseTimeOfDay("07:30")
+		if assert.NoError(t, err) {
+			assert.Equal(t, 7*60+30, x)
+		}
+	})
+	t.Run("should return error when invalid", func(t *testing.T) {
+		_, err := app.ParseTimeOfDay("25:00")
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("can format", func(t *testing.T) {
+		assert.Equal(t, "07:05", app.FormatTimeOfDay(7*60+5))
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"fyne.io/fyne/v2"
+	"github.com/ErikKalkoken/evebuddy/internal/notifier"
 	"github.com/ErikKalkoken/evebuddy/internal/set"
 )
 
@@ -28,6 +29,9 @@ type Settings interface {
 	SysTrayEnabled() bool
 	ResetSysTrayEnabled()
 	SetSysTrayEnabled(v bool)
+	TimersCalendarPath() string
+	ResetTimersCalendarPath()
+	SetTimersCalendarPath(v string)
 	WindowSize() fyne.Size
 	ResetWindowSize()
 	SetWindowSize(v fyne.Size)
@@ -44,6 +48,10 @@ type Settings interface {
 	NotifyTimeoutHoursPresets() (min int, max int, def int)
 	ResetNotifyTimeoutHours()
 	SetNotifyTimeoutHours(v int)
+	NotifyTimersLeadMinutes() int
+	NotifyTimersLeadMinutesPresets() (min int, max int, def int)
+	ResetNotifyTimersLeadMinutes()
+	SetNotifyTimersLeadMinutes(v int)
 	NotificationTypesEnabled() set.Set[string]
 	ResetNotificationTypesEnabled()
 	SetNotificationTypesEnabled(v set.Set[string])
@@ -53,6 +61,8 @@ type Settings interface {
 	SetNotifyContractsEarliest(t time.Time)
 	NotifyMailsEarliest() time.Time
 	SetNotifyMailsEarliest(t time.Time)
+	NotifyMoonMiningEarliest() time.Time
+	SetNotifyMoonMiningEarliest(t time.Time)
 	NotifyPIEarliest() time.Time
 	SetNotifyPIEarliest(t time.Time)
 	NotifyTrainingEarliest() time.Time
@@ -66,13 +76,32 @@ type Settings interface {
 	NotifyMailsEnabled() bool
 	ResetNotifyMailsEnabled()
 	SetNotifyMailsEnabled(v bool)
+	NotifyMoonMiningEnabled() bool
+	ResetNotifyMoonMiningEnabled()
+	SetNotifyMoonMiningEnabled(v bool)
 	NotifyPIEnabled() bool
 	ResetNotifyPIEnabled()
 	SetNotifyPIEnabled(v bool)
+	NotifyTimersEnabled() bool
+	ResetNotifyTimersEnabled()
+	SetNotifyTimersEnabled(v bool)
 	NotifyTrainingEnabled() bool
 	ResetNotifyTrainingEnabled()
 	SetNotifyTrainingEnabled(v bool)
 	TabsMainID() int
 	RecentSearches() []int32
 	SetRecentSearches(v []int32)
+	NotifierGotify() notifier.Gotify
+	ResetNotifierGotify()
+	SetNotifierGotify(v notifier.Gotify)
+	NotifierNtfy() notifier.Ntfy
+	ResetNotifierNtfy()
+	SetNotifierNtfy(v notifier.Ntfy)
+	NotifierSMTP() notifier.SMTP
+	ResetNotifierSMTP()
+	SetNotifierSMTP(v notifier.SMTP)
+	NotifierWebhook() notifier.Webhook
+	ResetNotifierWebhook()
+	SetNotifierWebhook(v notifier.Webhook)
+	NotifiersEnabled() []notifier.Notifier
 }
@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"encoding/json"
 	"log/slog"
 	"maps"
 	"slices"
@@ -8,6 +9,7 @@ import (
 
 	"fyne.io/fyne/v2"
 	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/notifier"
 	"github.com/ErikKalkoken/evebuddy/internal/set"
 	"github.com/ErikKalkoken/evebuddy/internal/xslices"
 )
@@ -26,6 +28,10 @@ const (
 	settingMaxWalletTransactionsDefault       = 1_000
 	settingMaxWalletTransactionsMax           = 10_000
 	settingNotificationTypesEnabled           = "settingNotificationsTypesEnabled"
+	settingNotifierGotify                     = "settingNotifierGotify"
+	settingNotifierNtfy                       = "settingNotifierNtfy"
+	settingNotifierSMTP                       = "settingNotifierSMTP"
+	settingNotifierWebhook                    = "settingNotifierWebhook"
 	settingNotifyCommunicationsEarliest       = "settingNotifyCommunicationsEarliest"
 	settingNotifyCommunicationsEnabled        = "settingNotifyCommunicationsEnabled"
 	settingNotifyCommunicationsEnabledDefault = false
@@ -35,6 +41,9 @@ const (
 	settingNotifyMailsEarliest                = "settingNotifyMailsEarliest"
 	settingNotifyMailsEnabled                 = "settingNotifyMailsEnabled"
 	settingNotifyMailsEnabledDefault          = false
+	settingNotifyMoonMiningEarliest           = "settingNotifyMoonMiningEarliest"
+	settingNotifyMoonMiningEnabled            = "settingNotifyMoonMiningEnabled"
+	settingNotifyMoonMiningEnabledDefault     = false
 	settingNotifyPIEarliest                   = "settingNotifyPIEarliest"
 	settingNotifyPIEnabled                    = "settingNotifyPIEnabled"
 	settingNotifyPIEnabledDefault             = false
@@ -42,6 +51,12 @@ const (
 	settingNotifyTimeoutHoursDefault          = 30 * 24
 	settingNotifyTimeoutHoursMax              = 90 * 24
 	settingNotifyTimeoutHoursMin              = 1
+	settingNotifyTimersEnabled                = "settingNotifyTimersEnabled"
+	settingNotifyTimersEnabledDefault         = false
+	settingNotifyTimersLeadMinutes            = "settingNotifyTimersLeadMinutes"
+	settingNotifyTimersLeadMinutesDefault     = 60
+	settingNotifyTimersLeadMinutesMax         = 24 * 60
+	settingNotifyTimersLeadMinutesMin         = 5
 	settingNotifyTrainingEarliest             = "settingNotifyTrainingEarliest"
 	settingNotifyTrainingEnabled              = "settingNotifyTrainingEnabled"
 	settingNotifyTrainingEnabledDefault       = false
@@ -49,6 +64,8 @@ const (
 	settingSysTrayEnabledDefault              = false
 	settingTabsMainID                         = "tabs-main-id"
 	settingTabsMainIDDefault                  = -1
+	settingTimersCalendarPath                 = "settingTimersCalendarPath"
+	settingTimersCalendarPathDefault          = ""
 	settingWindowHeightDefault                = 600
 	settingWindowsSize                        = "window-size"
 	settingWindowWidthDefault                 = 1000
@@ -148,6 +165,19 @@ func (s Settings) SetSysTrayEnabled(v bool) {
 	s.p.SetBool(settingSysTrayEnabled, v)
 }
 
+// TimersCalendarPath returns the path of the iCalendar file for timers.
+// An empty path means that the file is not written.
+func (s Settings) TimersCalendarPath() string {
+	return s.p.StringWithFallback(settingTimersCalendarPath, settingTimersCalendarPathDefault)
+}
+func (s Settings) ResetTimersCalendarPath() {
+	s.SetTimersCalendarPath(settingTimersCalendarPathDefault)
+}
+
+func (s Settings) SetTimersCalendarPath(v string) {
+	s.p.SetString(settingTimersCalendarPath, v)
+}
+
 func (s Settings) WindowSize() fyne.Size {
 	x := s.p.FloatList(settingWindowsSize)
 	if len(x) < 2 {
@@ -222,6 +252,26 @@ func (s Settings) SetNotifyTimeoutHours(v int) {
 	s.p.SetInt(settingNotifyTimeoutHours, v)
 }
 
+// NotifyTimersLeadMinutes returns how many minutes before a structure timer exits a reminder is sent.
+func (s Settings) NotifyTimersLeadMinutes() int {
+	return s.p.IntWithFallback(settingNotifyTimersLeadMinutes, settingNotifyTimersLeadMinutesDefault)
+}
+
+func (s Settings) NotifyTimersLeadMinutesPresets() (min int, max int, def int) {
+	min = settingNotifyTimersLeadMinutesMin
+	max = settingNotifyTimersLeadMinutesMax
+	def = settingNotifyTimersLeadMinutesDefault
+	return
+}
+
+func (s Settings) ResetNotifyTimersLeadMinutes() {
+	s.SetNotifyTimersLeadMinutes(settingNotifyTimersLeadMinutesDefault)
+}
+
+func (s Settings) SetNotifyTimersLeadMinutes(v int) {
+	s.p.SetInt(settingNotifyTimersLeadMinutes, v)
+}
+
 func (s Settings) NotificationTypesEnabled() set.Set[string] {
 	return set.NewFromSlice(s.p.StringList(settingNotificationTypesEnabled))
 }
@@ -257,6 +307,13 @@ func (s Settings) SetNotifyMailsEarliest(t time.Time) {
 	s.setEarliest(settingNotifyMailsEarliest, t)
 }
 
+func (s Settings) NotifyMoonMiningEarliest() time.Time {
+	return s.calcNotifyEarliest(settingNotifyMoonMiningEarliest)
+}
+func (s Settings) SetNotifyMoonMiningEarliest(t time.Time) {
+	s.setEarliest(settingNotifyMoonMiningEarliest, t)
+}
+
 func (s Settings) NotifyPIEarliest() time.Time {
 	return s.calcNotifyEarliest(settingNotifyPIEarliest)
 }
@@ -354,6 +411,17 @@ func (s Settings) SetNotifyMailsEnabled(v bool) {
 	s.p.SetBool(settingNotifyMailsEnabled, v)
 }
 
+func (s Settings) NotifyMoonMiningEnabled() bool {
+	return s.p.BoolWithFallback(settingNotifyMoonMiningEnabled, settingNotifyMoonMiningEnabledDefault)
+}
+func (s Settings) ResetNotifyMoonMiningEnabled() {
+	s.SetNotifyMoonMiningEnabled(settingNotifyMoonMiningEnabledDefault)
+}
+
+func (s Settings) SetNotifyMoonMiningEnabled(v bool) {
+	s.p.SetBool(settingNotifyMoonMiningEnabled, v)
+}
+
 func (s Settings) NotifyPIEnabled() bool {
 	return s.p.BoolWithFallback(settingNotifyPIEnabled, settingNotifyPIEnabledDefault)
 }
@@ -365,6 +433,17 @@ func (s Settings) SetNotifyPIEnabled(v bool) {
 	s.p.SetBool(settingNotifyPIEnabled, v)
 }
 
+func (s Settings) NotifyTimersEnabled() bool {
+	return s.p.BoolWithFallback(settingNotifyTimersEnabled, settingNotifyTimersEnabledDefault)
+}
+func (s Settings) ResetNotifyTimersEnabled() {
+	s.SetNotifyTimersEnabled(settingNotifyTimersEnabledDefault)
+}
+
+func (s Settings) SetNotifyTimersEnabled(v bool) {
+	s.p.SetBool(settingNotifyTimersEnabled, v)
+}
+
 func (s Settings) NotifyTrainingEnabled() bool {
 	return s.p.BoolWithFallback(settingNotifyTrainingEnabled, settingNotifyTrainingEnabledDefault)
 }
@@ -392,6 +471,102 @@ func (s Settings) SetRecentSearches(v []int32) {
 	}))
 }
 
+// Notifier backends are stored as JSON.
+
+func (s Settings) NotifierGotify() notifier.Gotify {
+	var x notifier.Gotify
+	s.getJSON(settingNotifierGotify, &x)
+	return x
+}
+
+func (s Settings) ResetNotifierGotify() {
+	s.SetNotifierGotify(notifier.Gotify{})
+}
+
+func (s Settings) SetNotifierGotify(v notifier.Gotify) {
+	s.setJSON(settingNotifierGotify, v)
+}
+
+func (s Settings) NotifierNtfy() notifier.Ntfy {
+	var x notifier.Ntfy
+	s.getJSON(settingNotifierNtfy, &x)
+	return x
+}
+
+func (s Settings) ResetNotifierNtfy() {
+	s.SetNotifierNtfy(notifier.Ntfy{})
+}
+
+func (s Settings) SetNotifierNtfy(v notifier.Ntfy) {
+	s.setJSON(settingNotifierNtfy, v)
+}
+
+func (s Settings) NotifierSMTP() notifier.SMTP {
+	var x notifier.SMTP
+	s.getJSON(settingNotifierSMTP, &x)
+	return x
+}
+
+func (s Settings) ResetNotifierSMTP() {
+	s.SetNotifierSMTP(notifier.SMTP{})
+}
+
+func (s Settings) SetNotifierSMTP(v notifier.SMTP) {
+	s.setJSON(settingNotifierSMTP, v)
+}
+
+func (s Settings) NotifierWebhook() notifier.Webhook {
+	var x notifier.Webhook
+	s.getJSON(settingNotifierWebhook, &x)
+	return x
+}
+
+func (s Settings) ResetNotifierWebhook() {
+	s.SetNotifierWebhook(notifier.Webhook{})
+}
+
+func (s Settings) SetNotifierWebhook(v notifier.Webhook) {
+	s.setJSON(settingNotifierWebhook, v)
+}
+
+// NotifiersEnabled returns all notifier backends which are enabled.
+func (s Settings) NotifiersEnabled() []notifier.Notifier {
+	var nn []notifier.Notifier
+	if x := s.NotifierGotify(); x.IsEnabled {
+		nn = append(nn, x)
+	}
+	if x := s.NotifierNtfy(); x.IsEnabled {
+		nn = append(nn, x)
+	}
+	if x := s.NotifierSMTP(); x.IsEnabled {
+		nn = append(nn, x)
+	}
+	if x := s.NotifierWebhook(); x.IsEnabled {
+		nn = append(nn, x)
+	}
+	return nn
+}
+
+// getJSON decodes the JSON value of a setting into v. v is not changed when the setting does not exist.
+func (s Settings) getJSON(key string, v any) {
+	data := s.p.String(key)
+	if data == "" {
+		return
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		slog.Error("Failed to decode setting", "key", key, "error", err)
+	}
+}
+
+func (s Settings) setJSON(key string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("Failed to encode setting", "key", key, "error", err)
+		return
+	}
+	s.p.SetString(key, string(data))
+}
+
 // Keys returns all setting keys. Mostly to know what to delete.
 func Keys() []string {
 	return []string{
@@ -400,20 +575,29 @@ func Keys() []string {
 		settingMaxMails,
 		settingMaxWalletTransactions,
 		settingNotificationTypesEnabled,
+		settingNotifierGotify,
+		settingNotifierNtfy,
+		settingNotifierSMTP,
+		settingNotifierWebhook,
 		settingNotifyCommunicationsEarliest,
 		settingNotifyCommunicationsEnabled,
 		settingNotifyContractsEarliest,
 		settingNotifyContractsEnabled,
 		settingNotifyMailsEarliest,
 		settingNotifyMailsEnabled,
+		settingNotifyMoonMiningEarliest,
+		settingNotifyMoonMiningEnabled,
 		settingNotifyPIEarliest,
 		settingNotifyPIEnabled,
 		settingNotifyTimeoutHours,
+		settingNotifyTimersEnabled,
+		settingNotifyTimersLeadMinutes,
 		settingNotifyTrainingEarliest,
 		settingNotifyTrainingEnabled,
 		settingRecentSearches,
 		settingSysTrayEnabled,
 		settingTabsMainID,
+		settingTimersCalendarPath,
 		settingWindowsSize,
 	}
 }
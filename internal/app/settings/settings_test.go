@@ -6,6 +6,7 @@ import (
 
 	"fyne.io/fyne/v2"
 	"github.com/ErikKalkoken/evebuddy/internal/app/settings"
+	"github.com/ErikKalkoken/evebuddy/internal/notifier"
 	"github.com/ErikKalkoken/evebuddy/internal/set"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,4 +41,24 @@ func TestAppSettings(t *testing.T) {
 		s.SetNotificationTypesEnabled(x)
 		assert.Equal(t, x, s.NotificationTypesEnabled())
 	})
+	t.Run("Notifier", func(t *testing.T) {
+		p := settings.NewMyPref()
+		s := settings.New(p)
+		x := notifier.Ntfy{IsEnabled: true, Topic: "alpha"}
+		s.SetNotifierNtfy(x)
+		assert.Equal(t, x, s.NotifierNtfy())
+	})
+	t.Run("Notifier not set", func(t *testing.T) {
+		p := settings.NewMyPref()
+		s := settings.New(p)
+		assert.Equal(t, notifier.Gotify{}, s.NotifierGotify())
+	})
+	t.Run("NotifiersEnabled", func(t *testing.T) {
+		p := settings.NewMyPref()
+		s := settings.New(p)
+		x := notifier.Webhook{IsEnabled: true, URL: "https://www.example.com"}
+		s.SetNotifierWebhook(x)
+		s.SetNotifierSMTP(notifier.SMTP{Host: "localhost"})
+		assert.Equal(t, []notifier.Notifier{x}, s.NotifiersEnabled())
+	})
 }
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A SkillRequirement is a skill, which needs to be trained to at least a certain level.
+type SkillRequirement struct {
+	Level     int
+	SkillID   int32
+	SkillName string
+}
+
+func (r SkillRequirement) String() string {
+	return fmt.Sprintf("%s %d", r.SkillName, r.Level)
+}
+
+// A MissingSkill is a skill, which a character has not yet trained to a required level.
+type MissingSkill struct {
+	CurrentLevel  int
+	RequiredLevel int
+	SkillID       int32
+	SkillName     string
+}
+
+// CheckSkillRequirements returns the skills from requirements, which are not trained to the required level.
+// Levels are the trained levels of a character by skill ID.
+// The order of the requirements is preserved.
+func CheckSkillRequirements(levels map[int32]int, requirements []SkillRequirement) []MissingSkill {
+	missing := make([]MissingSkill, 0)
+	for _, r := range requirements {
+		current := levels[r.SkillID]
+		if current >= r.Level {
+			continue
+		}
+		missing = append(missing, MissingSkill{
+			CurrentLevel:  current,
+			RequiredLevel: r.Level,
+			SkillID:       r.SkillID,
+			SkillName:     r.SkillName,
+		})
+	}
+	return missing
+}
+
+// SkillQueueText returns the missing skills as plain text with one skill level per line,
+// e.g. "Heavy Assault Missiles 4".
+// This format can be pasted into the skill queue of the Eve Online client.
+func SkillQueueText(missing []MissingSkill) string {
+	var b strings.Builder
+	for _, m := range missing {
+		for l := m.CurrentLevel + 1; l <= m.RequiredLevel; l++ {
+			fmt.Fprintf(&b, "%s %d\n", m.SkillName, l)
+		}
+	}
+	return b.String()
+}
+
+// SkillComparison compares the trained skills of several characters.
+type SkillComparison struct {
+	CharacterIDs []int32 // compared characters in the order of the levels in each skill
+	Groups       []SkillComparisonGroup
+}
+
+// A SkillComparisonGroup is a skill group in a skill comparison.
+type SkillComparisonGroup struct {
+	GroupID   int32
+	GroupName string
+	Skills    []SkillComparisonSkill
+}
+
+// HasDifferences reports whether any skill in this group is trained to different levels.
+func (g SkillComparisonGroup) HasDifferences() bool {
+	return slices.ContainsFunc(g.Skills, func(s SkillComparisonSkill) bool {
+		return s.HasDifferences()
+	})
+}
+
+// A SkillComparisonSkill is a skill in a skill comparison.
+type SkillComparisonSkill struct {
+	Levels   []int // trained levels for each compared character
+	TypeID   int32
+	TypeName string
+}
+
+// HasDifferences reports whether the characters have trained this skill to different levels.
+func (s SkillComparisonSkill) HasDifferences() bool {
+	for _, l := range s.Levels {
+		if l != s.Levels[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// Levels returns the trained levels by skill ID for a character.
+// Returns nil when the character is not part of the comparison.
+func (c SkillComparison) Levels(characterID int32) map[int32]int {
+	idx := slices.Index(c.CharacterIDs, characterID)
+	if idx == -1 {
+		return nil
+	}
+	levels := make(map[int32]int)
+	for _, g := range c.Groups {
+		for _, s := range g.Skills {
+			if s.Levels[idx] > 0 {
+				levels[s.TypeID] = s.Levels[idx]
+			}
+		}
+	}
+	return levels
+}
+
+// MissingSkills returns the skills a character needs to train to match the skills of another character.
+// The skills are in the order of the comparison. Use [SortMissingSkills] to order them by prerequisites.
+func (c SkillComparison) MissingSkills(characterID, otherID int32) []MissingSkill {
+	idx := slices.Index(c.CharacterIDs, otherID)
+	if idx == -1 {
+		return []MissingSkill{}
+	}
+	requirements := make([]SkillRequirement, 0)
+	for _, g := range c.Groups {
+		for _, s := range g.Skills {
+			if s.Levels[idx] > 0 {
+				requirements = append(requirements, SkillRequirement{
+					Level:     s.Levels[idx],
+					SkillID:   s.TypeID,
+					SkillName: s.TypeName,
+				})
+			}
+		}
+	}
+	return CheckSkillRequirements(c.Levels(characterID), requirements)
+}
+
+// SortMissingSkills returns the missing skills ordered so that
+// each skill comes after the skills it requires.
+// Prerequisites are the IDs of the directly required skills by skill ID.
+// Otherwise the original order is preserved.
+func SortMissingSkills(missing []MissingSkill, prerequisites map[int32][]int32) []MissingSkill {
+	bySkill := make(map[int32]MissingSkill)
+	for _, m := range missing {
+		bySkill[m.SkillID] = m
+	}
+	sorted := make([]MissingSkill, 0, len(missing))
+	visited := make(map[int32]bool)
+	var visit func(skillID int32)
+	visit = func(skillID int32) {
+		if visited[skillID] {
+			return
+		}
+		visited[skillID] = true
+		for _, id := range prerequisites[skillID] {
+			visit(id)
+		}
+		if m, ok := bySkill[skillID]; ok {
+			sorted = append(sorted, m)
+		}
+	}
+	for _, m := range missing {
+		visit(m.SkillID)
+	}
+	return sorted
+}
+
+// MergeSkillRequirements returns the combined requirements from several lists.
+// Each skill is included only once with the highest required level
+// and at the position of its first occurrence.
+func MergeSkillRequirements(lists ...[]SkillRequirement) []SkillRequirement {
+	merged := make([]SkillRequirement, 0)
+	index := make(map[int32]int)
+	for _, requirements := range lists {
+		for _, r := range requirements {
+			i, ok := index[r.SkillID]
+			if !ok {
+				index[r.SkillID] = len(merged)
+				merged = append(merged, r)
+				continue
+			}
+			merged[i].Level = max(merged[i].Level, r.Level)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,119 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/xslices"
+)
+
+func TestCheckSkillRequirements(t *testing.T) {
+	requirements := []app.SkillRequirement{
+		{SkillID: 1, SkillName: "Alpha", Level: 3},
+		{SkillID: 2, SkillName: "Bravo", Level: 5},
+		{SkillID: 3, SkillName: "Charlie", Level: 1},
+	}
+	levels := map[int32]int{1: 3, 2: 4}
+	got := app.CheckSkillRequirements(levels, requirements)
+	want := []app.MissingSkill{
+		{SkillID: 2, SkillName: "Bravo", CurrentLevel: 4, RequiredLevel: 5},
+		{SkillID: 3, SkillName: "Charlie", CurrentLevel: 0, RequiredLevel: 1},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestSkillQueueText(t *testing.T) {
+	missing := []app.MissingSkill{
+		{SkillName: "Alpha", CurrentLevel: 3, RequiredLevel: 5},
+		{SkillName: "Bravo", CurrentLevel: 0, RequiredLevel: 1},
+	}
+	got := app.SkillQueueText(missing)
+	assert.Equal(t, "Alpha 4\nAlpha 5\nBravo 1\n", got)
+}
+
+func TestSkillComparison(t *testing.T) {
+	c := app.SkillComparison{
+		CharacterIDs: []int32{42, 43},
+		Groups: []app.SkillComparisonGroup{
+			{
+				GroupName: "Missiles",
+				Skills: []app.SkillComparisonSkill{
+					{TypeID: 1, TypeName: "Alpha", Levels: []int{5, 5}},
+					{TypeID: 2, TypeName: "Bravo", Levels: []int{2, 4}},
+				},
+			},
+			{
+				GroupName: "Drones",
+				Skills: []app.SkillComparisonSkill{
+					{TypeID: 3, TypeName: "Charlie", Levels: []int{0, 0}},
+					{TypeID: 4, TypeName: "Delta", Levels: []int{1, 0}},
+				},
+			},
+		},
+	}
+	t.Run("can report differences", func(t *testing.T) {
+		assert.False(t, c.Groups[0].Skills[0].HasDifferences())
+		assert.True(t, c.Groups[0].Skills[1].HasDifferences())
+		assert.True(t, c.Groups[0].HasDifferences())
+	})
+	t.Run("can return levels for character", func(t *testing.T) {
+		assert.Equal(t, map[int32]int{1: 5, 2: 2, 4: 1}, c.Levels(42))
+		assert.Nil(t, c.Levels(99))
+	})
+	t.Run("can return missing skills", func(t *testing.T) {
+		got := c.MissingSkills(42, 43)
+		want := []app.MissingSkill{{SkillID: 2, SkillName: "Bravo", CurrentLevel: 2, RequiredLevel: 4}}
+		assert.Equal(t, want, got)
+		got = c.MissingSkills(43, 42)
+		want = []app.MissingSkill{{SkillID: 4, SkillName: "Delta", CurrentLevel: 0, RequiredLevel: 1}}
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestSortMissingSkills(t *testing.T) {
+	t.Run("should order skills after their prerequisites", func(t *testing.T) {
+		missing := []app.MissingSkill{
+			{SkillID: 1, SkillName: "Alpha", RequiredLevel: 5},
+			{SkillID: 2, SkillName: "Bravo", RequiredLevel: 4},
+			{SkillID: 3, SkillName: "Charlie", RequiredLevel: 3},
+			{SkillID: 4, SkillName: "Delta", RequiredLevel: 1},
+		}
+		prerequisites := map[int32][]int32{
+			1: {3},
+			3: {5}, // 5 is already trained
+			5: {4},
+		}
+		got := app.SortMissingSkills(missing, prerequisites)
+		assert.Equal(t, []int32{4, 3, 1, 2}, xslices.Map(got, func(x app.MissingSkill) int32 {
+			return x.SkillID
+		}))
+	})
+	t.Run("should keep order when there are no prerequisites", func(t *testing.T) {
+		missing := []app.MissingSkill{
+			{SkillID: 2, SkillName: "Bravo", RequiredLevel: 4},
+			{SkillID: 1, SkillName: "Alpha", RequiredLevel: 5},
+		}
+		got := app.SortMissingSkills(missing, nil)
+		assert.Equal(t, missing, got)
+	})
+}
+
+func TestMergeSkillRequirements(t *testing.T) {
+	a := []app.SkillRequirement{
+		{SkillID: 1, SkillName: "Alpha", Level: 1},
+		{SkillID: 2, SkillName: "Bravo", Level: 3},
+	}
+	b := []app.SkillRequirement{
+		{SkillID: 1, SkillName: "Alpha", Level: 4},
+		{SkillID: 3, SkillName: "Charlie", Level: 2},
+	}
+	got := app.MergeSkillRequirements(a, b)
+	want := []app.SkillRequirement{
+		{SkillID: 1, SkillName: "Alpha", Level: 4},
+		{SkillID: 2, SkillName: "Bravo", Level: 3},
+		{SkillID: 3, SkillName: "Charlie", Level: 2},
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,79 @@
+package app
+
+const (
+	// SkillExtractorSP is the amount of skill points removed by one skill extractor.
+	SkillExtractorSP = 500_000
+	// SkillExtractorMinimumSP is the amount of skill points a character must keep when using extractors.
+	SkillExtractorMinimumSP = 5_000_000
+)
+
+// LargeSkillInjectorSP returns the skill points a large skill injector gives to a character
+// with the given total skill points.
+// The amount diminishes with higher total skill points.
+func LargeSkillInjectorSP(totalSP int) int {
+	switch {
+	case totalSP < 5_000_000:
+		return 500_000
+	case totalSP < 50_000_000:
+		return 400_000
+	case totalSP < 80_000_000:
+		return 300_000
+	}
+	return 150_000
+}
+
+// SmallSkillInjectorSP returns the skill points a small skill injector gives to a character
+// with the given total skill points. A small injector gives 1/5 of a large injector.
+func SmallSkillInjectorSP(totalSP int) int {
+	return LargeSkillInjectorSP(totalSP) / 5
+}
+
+// SkillInjectorNeeds are the skill injectors needed to reach a target amount of skill points.
+type SkillInjectorNeeds struct {
+	Large   int // number of large skill injectors
+	Small   int // number of small skill injectors
+	FinalSP int // total skill points after using all injectors
+}
+
+// CalcSkillInjectors returns the skill injectors needed to get from the current total skill points
+// to a target amount of skill points.
+//
+// Injectors are used one after the other, so each injector gives skill points
+// according to the diminishing-returns tier the character is in at that time.
+// Large injectors are used as long as more than four small injectors would still be needed.
+// The remainder, which four or less small injectors can cover, is filled with small injectors.
+func CalcSkillInjectors(currentSP, targetSP int) SkillInjectorNeeds {
+	r := SkillInjectorNeeds{FinalSP: currentSP}
+	for r.FinalSP < targetSP {
+		small := SmallSkillInjectorSP(r.FinalSP)
+		if targetSP-r.FinalSP > 4*small {
+			r.FinalSP += LargeSkillInjectorSP(r.FinalSP)
+			r.Large++
+		} else {
+			r.FinalSP += small
+			r.Small++
+		}
+	}
+	return r
+}
+
+// CalcSkillExtractors returns how many skill extractors can be used on a character with the given total skill points,
+// while keeping at least keepSP skill points in skills and the minimum skill points required for extraction.
+func CalcSkillExtractors(totalSP, keepSP int) int {
+	extractable := totalSP - max(keepSP, SkillExtractorMinimumSP)
+	if extractable <= 0 {
+		return 0
+	}
+	return extractable / SkillExtractorSP
+}
+
+// SkillRequirementsSP are the skill points of a character towards a set of skill requirements.
+type SkillRequirementsSP struct {
+	Missing int // skill points which still need to be trained
+	Trained int // skill points already trained towards the required levels
+}
+
+// Total returns the total skill points needed for all requirements.
+func (x SkillRequirementsSP) Total() int {
+	return x.Missing + x.Trained
+}
@@ -0,0 +1,70 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+func TestSkillInjectorSP(t *testing.T) {
+	cases := []struct {
+		totalSP int
+		large   int
+		small   int
+	}{
+		{0, 500_000, 100_000},
+		{4_999_999, 500_000, 100_000},
+		{5_000_000, 400_000, 80_000},
+		{49_999_999, 400_000, 80_000},
+		{50_000_000, 300_000, 60_000},
+		{79_999_999, 300_000, 60_000},
+		{80_000_000, 150_000, 30_000},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.large, app.LargeSkillInjectorSP(tc.totalSP), "large %d", tc.totalSP)
+		assert.Equal(t, tc.small, app.SmallSkillInjectorSP(tc.totalSP), "small %d", tc.totalSP)
+	}
+}
+
+func TestCalcSkillInjectors(t *testing.T) {
+	cases := []struct {
+		name      string
+		currentSP int
+		targetSP  int
+		want      app.SkillInjectorNeeds
+	}{
+		{"target already reached", 6_000_000, 5_000_000, app.SkillInjectorNeeds{FinalSP: 6_000_000}},
+		{"large injectors only", 10_000_000, 10_800_000, app.SkillInjectorNeeds{Large: 2, FinalSP: 10_800_000}},
+		{"small injectors for remainder", 10_000_000, 10_500_000, app.SkillInjectorNeeds{Large: 1, Small: 2, FinalSP: 10_560_000}},
+		{"crossing a tier", 4_800_000, 6_000_000, app.SkillInjectorNeeds{Large: 2, Small: 4, FinalSP: 6_020_000}},
+		{"small injectors only", 80_000_000, 80_100_000, app.SkillInjectorNeeds{Small: 4, FinalSP: 80_120_000}},
+		{"exactly four small injectors needed", 10_000_000, 10_320_000, app.SkillInjectorNeeds{Small: 4, FinalSP: 10_320_000}},
+		{"more than four small injectors needed", 10_000_000, 10_320_001, app.SkillInjectorNeeds{Large: 1, FinalSP: 10_400_000}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, app.CalcSkillInjectors(tc.currentSP, tc.targetSP))
+		})
+	}
+}
+
+func TestCalcSkillExtractors(t *testing.T) {
+	cases := []struct {
+		name    string
+		totalSP int
+		keepSP  int
+		want    int
+	}{
+		{"below minimum", 5_400_000, 0, 0},
+		{"above minimum", 6_200_000, 0, 2},
+		{"keep skills", 20_000_000, 18_000_000, 4},
+		{"keep more than total", 20_000_000, 25_000_000, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, app.CalcSkillExtractors(tc.totalSP, tc.keepSP))
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// SkillLevelMax is the highest level a skill can be trained to.
+const SkillLevelMax = 5
+
+// TrainingAttributes are the values of the five character attributes,
+// which determine how fast skills are trained.
+type TrainingAttributes struct {
+	Charisma     int
+	Intelligence int
+	Memory       int
+	Perception   int
+	Willpower    int
+}
+
+// NewTrainingAttributes returns training attributes with the same value for all attributes,
+// e.g. the bonuses of a full set of +5 implants.
+func NewTrainingAttributes(v int) TrainingAttributes {
+	return TrainingAttributes{
+		Charisma:     v,
+		Intelligence: v,
+		Memory:       v,
+		Perception:   v,
+		Willpower:    v,
+	}
+}
+
+// Add returns the sum of two attribute sets.
+func (a TrainingAttributes) Add(other TrainingAttributes) TrainingAttributes {
+	return TrainingAttributes{
+		Charisma:     a.Charisma + other.Charisma,
+		Intelligence: a.Intelligence + other.Intelligence,
+		Memory:       a.Memory + other.Memory,
+		Perception:   a.Perception + other.Perception,
+		Willpower:    a.Willpower + other.Willpower,
+	}
+}
+
+// Sub returns the difference of two attribute sets.
+func (a TrainingAttributes) Sub(other TrainingAttributes) TrainingAttributes {
+	return a.Add(TrainingAttributes{
+		Charisma:     -other.Charisma,
+		Intelligence: -other.Intelligence,
+		Memory:       -other.Memory,
+		Perception:   -other.Perception,
+		Willpower:    -other.Willpower,
+	})
+}
+
+// Value returns the value of an attribute identified by its dogma attribute ID, e.g. [EveDogmaAttributeMemory].
+// Returns 0 for IDs of other dogma attributes.
+func (a TrainingAttributes) Value(attributeID int32) int {
+	switch attributeID {
+	case EveDogmaAttributeCharisma:
+		return a.Charisma
+	case EveDogmaAttributeIntelligence:
+		return a.Intelligence
+	case EveDogmaAttributeMemory:
+		return a.Memory
+	case EveDogmaAttributePerception:
+		return a.Perception
+	case EveDogmaAttributeWillpower:
+		return a.Willpower
+	}
+	return 0
+}
+
+// MaxSkillPointsPerMinute returns how many skill points are trained per minute at best,
+// i.e. for skills with the two highest attributes as primary and secondary attribute.
+func (a TrainingAttributes) MaxSkillPointsPerMinute() float64 {
+	v := []int{a.Charisma, a.Intelligence, a.Memory, a.Perception, a.Willpower}
+	slices.Sort(v)
+	return float64(v[4]) + float64(v[3])/2
+}
+
+// TrainingAttributes returns the attributes used for skill training.
+func (ca CharacterAttributes) TrainingAttributes() TrainingAttributes {
+	return TrainingAttributes{
+		Charisma:     ca.Charisma,
+		Intelligence: ca.Intelligence,
+		Memory:       ca.Memory,
+		Perception:   ca.Perception,
+		Willpower:    ca.Willpower,
+	}
+}
+
+// CharacterTrainingAttributes are the training attributes of a character.
+type CharacterTrainingAttributes struct {
+	CharacterID int32
+	Current     TrainingAttributes // current attributes including the bonuses from implants
+	Implants    TrainingAttributes // bonuses from the currently plugged in implants
+}
+
+// Base returns the attributes of a character without implants.
+func (ca CharacterTrainingAttributes) Base() TrainingAttributes {
+	return ca.Current.Sub(ca.Implants)
+}
+
+// WithImplants returns the attributes a character would have with implants providing the given bonuses
+// instead of the current implants.
+func (ca CharacterTrainingAttributes) WithImplants(bonuses TrainingAttributes) TrainingAttributes {
+	return ca.Base().Add(bonuses)
+}
+
+// A TrainableSkill is a skill type with the properties which determine its training time.
+type TrainableSkill struct {
+	PrimaryAttribute   int32 // dogma attribute ID
+	Rank               int
+	SecondaryAttribute int32 // dogma attribute ID
+	TypeID             int32
+	TypeName           string
+}
+
+// SkillPoints returns the skill points needed for a level of a skill.
+func (s TrainableSkill) SkillPoints(level int) int {
+	return SkillPointsForLevel(s.Rank, level)
+}
+
+// SkillPointsPerMinute returns how many skill points are trained per minute with the given attributes.
+// This is the training speed of omega clones. Alpha clones train at half the speed.
+func (s TrainableSkill) SkillPointsPerMinute(a TrainingAttributes) float64 {
+	return float64(a.Value(s.PrimaryAttribute)) + float64(a.Value(s.SecondaryAttribute))/2
+}
+
+// TrainingTime returns how long it takes to train a skill from the current skill points to a level.
+func (s TrainableSkill) TrainingTime(a TrainingAttributes, skillPoints, level int) time.Duration {
+	missing := s.SkillPoints(level) - skillPoints
+	rate := s.SkillPointsPerMinute(a)
+	if missing <= 0 || rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(missing) / rate * float64(time.Minute))
+}
+
+// SkillPointsForLevel returns the skill points needed for a level of a skill with the given rank,
+// e.g. 256.000 skill points for level 5 of a rank 1 skill.
+func SkillPointsForLevel(rank, level int) int {
+	if level <= 0 || rank <= 0 {
+		return 0
+	}
+	level = min(level, SkillLevelMax)
+	x := 250 * float64(rank) * math.Pow(math.Sqrt(32), float64(level-1))
+	return int(math.Ceil(x - 1e-6)) // compensate for rounding errors of the floating point calculation
+}
@@ -0,0 +1,75 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+func TestSkillPointsForLevel(t *testing.T) {
+	cases := []struct {
+		rank  int
+		level int
+		want  int
+	}{
+		{1, 0, 0},
+		{1, 1, 250},
+		{1, 2, 1415},
+		{1, 3, 8000},
+		{1, 4, 45255},
+		{1, 5, 256000},
+		{3, 5, 768000},
+		{16, 5, 4096000},
+		{0, 5, 0},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, app.SkillPointsForLevel(tc.rank, tc.level), "rank %d level %d", tc.rank, tc.level)
+	}
+}
+
+func TestTrainableSkill(t *testing.T) {
+	s := app.TrainableSkill{
+		PrimaryAttribute:   app.EveDogmaAttributeIntelligence,
+		Rank:               1,
+		SecondaryAttribute: app.EveDogmaAttributeMemory,
+	}
+	a := app.TrainingAttributes{Intelligence: 27, Memory: 21}
+	t.Run("can calculate skill points per minute", func(t *testing.T) {
+		assert.InDelta(t, 37.5, s.SkillPointsPerMinute(a), 0.001)
+	})
+	t.Run("can calculate training time", func(t *testing.T) {
+		got := s.TrainingTime(a, 250, 3)
+		assert.Equal(t, 206*time.Minute+40*time.Second, got)
+	})
+	t.Run("should return zero when already trained", func(t *testing.T) {
+		got := s.TrainingTime(a, 8000, 3)
+		assert.Equal(t, time.Duration(0), got)
+	})
+	t.Run("should return zero when attributes are unknown", func(t *testing.T) {
+		got := s.TrainingTime(app.TrainingAttributes{}, 0, 1)
+		assert.Equal(t, time.Duration(0), got)
+	})
+}
+
+func TestCharacterTrainingAttributes(t *testing.T) {
+	ca := app.CharacterTrainingAttributes{
+		Current:  app.TrainingAttributes{Charisma: 20, Intelligence: 27, Memory: 24, Perception: 20, Willpower: 20},
+		Implants: app.TrainingAttributes{Intelligence: 4, Memory: 4},
+	}
+	t.Run("can return base attributes", func(t *testing.T) {
+		want := app.TrainingAttributes{Charisma: 20, Intelligence: 23, Memory: 20, Perception: 20, Willpower: 20}
+		assert.Equal(t, want, ca.Base())
+	})
+	t.Run("can return attributes with other implants", func(t *testing.T) {
+		want := app.TrainingAttributes{Charisma: 25, Intelligence: 28, Memory: 25, Perception: 25, Willpower: 25}
+		assert.Equal(t, want, ca.WithImplants(app.NewTrainingAttributes(5)))
+	})
+}
+
+func TestTrainingAttributesMaxSkillPointsPerMinute(t *testing.T) {
+	a := app.TrainingAttributes{Charisma: 17, Intelligence: 27, Memory: 21, Perception: 20, Willpower: 20}
+	assert.InDelta(t, 37.5, a.MaxSkillPointsPerMinute(), 0.001)
+}
@@ -199,6 +199,27 @@ func (st *Storage) ListCharacterMailIDs(ctx context.Context, characterID int32)
 	return set.NewFromSlice(convertNumericSlice[int32](ids)), nil
 }
 
+// ListCharacterMailParticipants returns the IDs of the sender and recipients for each mail of a character.
+func (st *Storage) ListCharacterMailParticipants(ctx context.Context, characterID int32) (map[int32]set.Set[int32], error) {
+	rows, err := st.qRO.ListMailParticipants(ctx, int64(characterID))
+	if err != nil {
+		return nil, fmt.Errorf("list mail participants for character %d: %w", characterID, err)
+	}
+	result := make(map[int32]set.Set[int32])
+	for _, r := range rows {
+		mailID := int32(r.MailID)
+		ids, ok := result[mailID]
+		if !ok {
+			ids = set.New(int32(r.FromID))
+			result[mailID] = ids
+		}
+		if r.RecipientID.Valid {
+			ids.Add(int32(r.RecipientID.Int64))
+		}
+	}
+	return result, nil
+}
+
 func (st *Storage) ListCharacterMailListsOrdered(ctx context.Context, characterID int32) ([]*app.EveEntity, error) {
 	ll, err := st.qRO.ListCharacterMailListsOrdered(ctx, int64(characterID))
 	if err != nil {
@@ -98,6 +98,27 @@ func TestMail(t *testing.T) {
 		want := set.NewFromSlice([]int32{10, 11, 12})
 		assert.Equal(t, want, got)
 	})
+	t.Run("can list mail participants", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		e1 := factory.CreateEveEntityCharacter()
+		e2 := factory.CreateEveEntityCharacter()
+		e3 := factory.CreateEveEntityCharacter()
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID:  c.ID,
+			FromID:       e1.ID,
+			RecipientIDs: []int32{e2.ID, e3.ID},
+		})
+		factory.CreateCharacterMail()
+		// when
+		got, err := r.ListCharacterMailParticipants(ctx, c.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.Len(t, got, 1)
+			assert.Equal(t, set.New(e1.ID, e2.ID, e3.ID), got[m.MailID])
+		}
+	})
 	t.Run("can delete existing mail", func(t *testing.T) {
 		// given
 		testutil.TruncateTables(db)
@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
+)
+
+type UpdateOrCreateCharacterMailDraftParams struct {
+	Body         string
+	CharacterID  int32
+	QuotedBody   string
+	RecipientIDs []int32
+	Status       app.CharacterMailDraftStatus
+	Subject      string
+}
+
+func (arg UpdateOrCreateCharacterMailDraftParams) isValid() bool {
+	return arg.CharacterID != 0 && arg.Status != app.MailDraftUndefined
+}
+
+// CreateCharacterMailDraft creates a new mail draft and returns it's ID.
+// A queued draft is due for sending immediately.
+func (st *Storage) CreateCharacterMailDraft(ctx context.Context, arg UpdateOrCreateCharacterMailDraftParams) (int64, error) {
+	if !arg.isValid() {
+		return 0, fmt.Errorf("CreateCharacterMailDraft: %+v: %w", arg, app.ErrInvalid)
+	}
+	recipientIDs, err := json.Marshal(nonNilSlice(arg.RecipientIDs))
+	if err != nil {
+		return 0, fmt.Errorf("create mail draft: %+v: %w", arg, err)
+	}
+	now := time.Now().UTC()
+	arg2 := queries.CreateCharacterMailDraftParams{
+		Body:          arg.Body,
+		CharacterID:   int64(arg.CharacterID),
+		NextAttemptAt: now,
+		QuotedBody:    arg.QuotedBody,
+		RecipientIds:  string(recipientIDs),
+		Status:        int64(arg.Status),
+		Subject:       arg.Subject,
+		UpdatedAt:     now,
+	}
+	id, err := st.qRW.CreateCharacterMailDraft(ctx, arg2)
+	if err != nil {
+		return 0, fmt.Errorf("create mail draft: %+v: %w", arg, err)
+	}
+	return id, nil
+}
+
+func (st *Storage) DeleteCharacterMailDraft(ctx context.Context, characterID int32, id int64) error {
+	arg := queries.DeleteCharacterMailDraftParams{
+		CharacterID: int64(characterID),
+		ID:          id,
+	}
+	if err := st.qRW.DeleteCharacterMailDraft(ctx, arg); err != nil {
+		return fmt.Errorf("delete mail draft %d for character %d: %w", id, characterID, err)
+	}
+	return nil
+}
+
+func (st *Storage) GetCharacterMailDraft(ctx context.Context, characterID int32, id int64) (*app.CharacterMailDraft, error) {
+	arg := queries.GetCharacterMailDraftParams{
+		CharacterID: int64(characterID),
+		ID:          id,
+	}
+	r, err := st.qRO.GetCharacterMailDraft(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = app.ErrNotFound
+		}
+		return nil, fmt.Errorf("get mail draft %d for character %d: %w", id, characterID, err)
+	}
+	o, err := st.characterMailDraftFromDBModel(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("get mail draft %d for character %d: %w", id, characterID, err)
+	}
+	return o, nil
+}
+
+// ListCharacterMailDrafts returns all drafts and queued mails of a character.
+// Recently updated drafts come first.
+func (st *Storage) ListCharacterMailDrafts(ctx context.Context, characterID int32) ([]*app.CharacterMailDraft, error) {
+	rows, err := st.qRO.ListCharacterMailDrafts(ctx, int64(characterID))
+	if err != nil {
+		return nil, fmt.Errorf("list mail drafts for character %d: %w", characterID, err)
+	}
+	return st.characterMailDraftsFromDBModels(ctx, rows)
+}
+
+// ListCharacterMailDraftsDue returns all queued mails of all characters which are due for sending at a time
+// in the order they were created.
+func (st *Storage) ListCharacterMailDraftsDue(ctx context.Context, now time.Time) ([]*app.CharacterMailDraft, error) {
+	arg := queries.ListCharacterMailDraftsDueParams{
+		Status:        int64(app.MailDraftQueued),
+		NextAttemptAt: now.UTC(),
+	}
+	rows, err := st.qRO.ListCharacterMailDraftsDue(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list mail drafts due: %w", err)
+	}
+	return st.characterMailDraftsFromDBModels(ctx, rows)
+}
+
+// UpdateCharacterMailDraft updates the content and status of a mail draft.
+// A queued draft is due for sending immediately.
+func (st *Storage) UpdateCharacterMailDraft(ctx context.Context, id int64, arg UpdateOrCreateCharacterMailDraftParams) error {
+	if !arg.isValid() {
+		return fmt.Errorf("UpdateCharacterMailDraft: %+v: %w", arg, app.ErrInvalid)
+	}
+	recipientIDs, err := json.Marshal(nonNilSlice(arg.RecipientIDs))
+	if err != nil {
+		return fmt.Errorf("update mail draft %d: %+v: %w", id, arg, err)
+	}
+	now := time.Now().UTC()
+	arg2 := queries.UpdateCharacterMailDraftParams{
+		Body:          arg.Body,
+		NextAttemptAt: now,
+		QuotedBody:    arg.QuotedBody,
+		RecipientIds:  string(recipientIDs),
+		Status:        int64(arg.Status),
+		Subject:       arg.Subject,
+		UpdatedAt:     now,
+		CharacterID:   int64(arg.CharacterID),
+		ID:            id,
+	}
+	if err := st.qRW.UpdateCharacterMailDraft(ctx, arg2); err != nil {
+		return fmt.Errorf("update mail draft %d: %+v: %w", id, arg, err)
+	}
+	return nil
+}
+
+type UpdateCharacterMailDraftAttemptParams struct {
+	ID            int64
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	Status        app.CharacterMailDraftStatus
+}
+
+// UpdateCharacterMailDraftAttempt records a failed attempt to send a queued mail.
+func (st *Storage) UpdateCharacterMailDraftAttempt(ctx context.Context, arg UpdateCharacterMailDraftAttemptParams) error {
+	arg2 := queries.UpdateCharacterMailDraftAttemptParams{
+		Attempts:      int64(arg.Attempts),
+		LastError:     arg.LastError,
+		NextAttemptAt: arg.NextAttemptAt.UTC(),
+		Status:        int64(arg.Status),
+		ID:            arg.ID,
+	}
+	if err := st.qRW.UpdateCharacterMailDraftAttempt(ctx, arg2); err != nil {
+		return fmt.Errorf("update mail draft attempt: %+v: %w", arg, err)
+	}
+	return nil
+}
+
+// UpdateCharacterMailDraftStatusIf changes the status of a mail draft only when it currently has status from
+// and reports whether it was changed.
+// This allows to claim a draft, e.g. for sending it, without racing others.
+func (st *Storage) UpdateCharacterMailDraftStatusIf(ctx context.Context, id int64, from, to app.CharacterMailDraftStatus) (bool, error) {
+	arg := queries.UpdateCharacterMailDraftStatusIfParams{
+		Status:   int64(to),
+		ID:       id,
+		Status_2: int64(from),
+	}
+	n, err := st.qRW.UpdateCharacterMailDraftStatusIf(ctx, arg)
+	if err != nil {
+		return false, fmt.Errorf("update status of mail draft %d from %s to %s: %w", id, from, to, err)
+	}
+	return n > 0, nil
+}
+
+// UpdateCharacterMailDraftsStatus changes the status of all mail drafts with status from.
+func (st *Storage) UpdateCharacterMailDraftsStatus(ctx context.Context, from, to app.CharacterMailDraftStatus) error {
+	arg := queries.UpdateCharacterMailDraftsStatusParams{
+		Status:   int64(to),
+		Status_2: int64(from),
+	}
+	if err := st.qRW.UpdateCharacterMailDraftsStatus(ctx, arg); err != nil {
+		return fmt.Errorf("update status of mail drafts from %s to %s: %w", from, to, err)
+	}
+	return nil
+}
+
+func (st *Storage) characterMailDraftsFromDBModels(ctx context.Context, rows []queries.CharacterMailDraft) ([]*app.CharacterMailDraft, error) {
+	oo := make([]*app.CharacterMailDraft, len(rows))
+	for i, r := range rows {
+		o, err := st.characterMailDraftFromDBModel(ctx, r)
+		if err != nil {
+			return nil, fmt.Errorf("mail draft %d: %w", r.ID, err)
+		}
+		oo[i] = o
+	}
+	return oo, nil
+}
+
+func (st *Storage) characterMailDraftFromDBModel(ctx context.Context, r queries.CharacterMailDraft) (*app.CharacterMailDraft, error) {
+	var ids []int32
+	if err := json.Unmarshal([]byte(r.RecipientIds), &ids); err != nil {
+		return nil, err
+	}
+	recipients, err := st.ListEveEntitiesForIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	o := &app.CharacterMailDraft{
+		ID:            r.ID,
+		Attempts:      int(r.Attempts),
+		Body:          r.Body,
+		CharacterID:   int32(r.CharacterID),
+		LastError:     r.LastError,
+		NextAttemptAt: r.NextAttemptAt,
+		QuotedBody:    r.QuotedBody,
+		Recipients:    recipients,
+		Status:        app.CharacterMailDraftStatus(r.Status),
+		Subject:       r.Subject,
+		UpdatedAt:     r.UpdatedAt,
+	}
+	return o, nil
+}
@@ -0,0 +1,189 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestCharacterMailDraft(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can create new", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		r1 := factory.CreateEveEntityCharacter()
+		r2 := factory.CreateEveEntity(app.EveEntity{Category: app.EveEntityMailList})
+		arg := storage.UpdateOrCreateCharacterMailDraftParams{
+			Body:         "body",
+			CharacterID:  c.ID,
+			QuotedBody:   "quote",
+			RecipientIDs: []int32{r2.ID, r1.ID},
+			Status:       app.MailDraftSaved,
+			Subject:      "subject",
+		}
+		// when
+		id, err := st.CreateCharacterMailDraft(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetCharacterMailDraft(ctx, c.ID, id)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "body", o.Body)
+				assert.Equal(t, c.ID, o.CharacterID)
+				assert.Equal(t, "quote", o.QuotedBody)
+				assert.Equal(t, []int32{r2.ID, r1.ID}, o.RecipientIDs())
+				assert.Equal(t, app.MailDraftSaved, o.Status)
+				assert.Equal(t, "subject", o.Subject)
+				assert.Equal(t, 0, o.Attempts)
+				assert.WithinDuration(t, time.Now(), o.UpdatedAt, 5*time.Second)
+			}
+		}
+	})
+	t.Run("should return error when params invalid", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		_, err := st.CreateCharacterMailDraft(ctx, storage.UpdateOrCreateCharacterMailDraftParams{CharacterID: c.ID})
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("can update existing and reset attempts", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o1 := factory.CreateCharacterMailDraft()
+		err := st.UpdateCharacterMailDraftAttempt(ctx, storage.UpdateCharacterMailDraftAttemptParams{
+			ID:            o1.ID,
+			Attempts:      3,
+			LastError:     "error",
+			NextAttemptAt: time.Now().Add(time.Hour),
+			Status:        app.MailDraftQueued,
+		})
+		if !assert.NoError(t, err) {
+			t.Fatal()
+		}
+		r := factory.CreateEveEntityCharacter()
+		// when
+		err = st.UpdateCharacterMailDraft(ctx, o1.ID, storage.UpdateOrCreateCharacterMailDraftParams{
+			Body:         "new body",
+			CharacterID:  o1.CharacterID,
+			RecipientIDs: []int32{r.ID},
+			Status:       app.MailDraftQueued,
+			Subject:      "new subject",
+		})
+		// then
+		if assert.NoError(t, err) {
+			o2, err := st.GetCharacterMailDraft(ctx, o1.CharacterID, o1.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "new body", o2.Body)
+				assert.Equal(t, []int32{r.ID}, o2.RecipientIDs())
+				assert.Equal(t, "new subject", o2.Subject)
+				assert.Equal(t, app.MailDraftQueued, o2.Status)
+				assert.Equal(t, 0, o2.Attempts)
+				assert.Equal(t, "", o2.LastError)
+			}
+		}
+	})
+	t.Run("can delete", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o := factory.CreateCharacterMailDraft()
+		// when
+		err := st.DeleteCharacterMailDraft(ctx, o.CharacterID, o.ID)
+		// then
+		if assert.NoError(t, err) {
+			_, err := st.GetCharacterMailDraft(ctx, o.CharacterID, o.ID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+		}
+	})
+	t.Run("can list drafts of a character", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		o1 := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{CharacterID: c.ID})
+		o2 := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{
+			CharacterID: c.ID,
+			Status:      app.MailDraftQueued,
+		})
+		factory.CreateCharacterMailDraft()
+		// when
+		oo, err := st.ListCharacterMailDrafts(ctx, c.ID)
+		// then
+		if assert.NoError(t, err) {
+			got := make([]int64, len(oo))
+			for i, o := range oo {
+				got[i] = o.ID
+			}
+			assert.ElementsMatch(t, []int64{o1.ID, o2.ID}, got)
+		}
+	})
+	t.Run("can list queued drafts which are due", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o1 := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{Status: app.MailDraftQueued})
+		o2 := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{Status: app.MailDraftQueued})
+		err := st.UpdateCharacterMailDraftAttempt(ctx, storage.UpdateCharacterMailDraftAttemptParams{
+			ID:            o2.ID,
+			Attempts:      1,
+			NextAttemptAt: time.Now().Add(time.Hour),
+			Status:        app.MailDraftQueued,
+		})
+		if !assert.NoError(t, err) {
+			t.Fatal()
+		}
+		factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{Status: app.MailDraftSaved})
+		factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{Status: app.MailDraftFailed})
+		// when
+		oo, err := st.ListCharacterMailDraftsDue(ctx, time.Now().Add(time.Second))
+		// then
+		if assert.NoError(t, err) {
+			if assert.Len(t, oo, 1) {
+				assert.Equal(t, o1.ID, oo[0].ID)
+			}
+		}
+	})
+	t.Run("can claim draft by changing it's status", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{Status: app.MailDraftQueued})
+		// when
+		ok1, err1 := st.UpdateCharacterMailDraftStatusIf(ctx, o.ID, app.MailDraftQueued, app.MailDraftSending)
+		ok2, err2 := st.UpdateCharacterMailDraftStatusIf(ctx, o.ID, app.MailDraftQueued, app.MailDraftSending)
+		// then
+		if assert.NoError(t, err1) && assert.NoError(t, err2) {
+			assert.True(t, ok1)
+			assert.False(t, ok2)
+			o2, err := st.GetCharacterMailDraft(ctx, o.CharacterID, o.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MailDraftSending, o2.Status)
+			}
+		}
+	})
+	t.Run("can change status of all drafts with a status", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o1 := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{Status: app.MailDraftSending})
+		o2 := factory.CreateCharacterMailDraft(storage.UpdateOrCreateCharacterMailDraftParams{Status: app.MailDraftQueued})
+		// when
+		err := st.UpdateCharacterMailDraftsStatus(ctx, app.MailDraftSending, app.MailDraftUnconfirmed)
+		// then
+		if assert.NoError(t, err) {
+			x1, err := st.GetCharacterMailDraft(ctx, o1.CharacterID, o1.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MailDraftUnconfirmed, x1.Status)
+			}
+			x2, err := st.GetCharacterMailDraft(ctx, o2.CharacterID, o2.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MailDraftQueued, x2.Status)
+			}
+		}
+	})
+}
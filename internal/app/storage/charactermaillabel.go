@@ -10,6 +10,18 @@ import (
 	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
 )
 
+// DeleteCharacterMailLabel deletes a mail label and removes it from all mails.
+func (st *Storage) DeleteCharacterMailLabel(ctx context.Context, characterID, labelID int32) error {
+	arg := queries.DeleteCharacterMailLabelParams{
+		CharacterID: int64(characterID),
+		LabelID:     int64(labelID),
+	}
+	if err := st.qRW.DeleteCharacterMailLabel(ctx, arg); err != nil {
+		return fmt.Errorf("delete mail label %d for character %d: %w", labelID, characterID, err)
+	}
+	return nil
+}
+
 func (st *Storage) DeleteObsoleteCharacterMailLabels(ctx context.Context, characterID int32) error {
 	arg := queries.DeleteObsoleteCharacterMailLabelsParams{
 		CharacterID:   int64(characterID),
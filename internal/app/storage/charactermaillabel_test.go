@@ -133,6 +133,30 @@ func TestMailLabel(t *testing.T) {
 			assert.Equal(t, want, got)
 		}
 	})
+	t.Run("can delete a mail label and remove it from mails", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		l1 := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		l2 := factory.CreateCharacterMailLabel(app.CharacterMailLabel{CharacterID: c.ID})
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			LabelIDs:    []int32{l1.LabelID, l2.LabelID},
+		})
+		// when
+		err := r.DeleteCharacterMailLabel(ctx, c.ID, l1.LabelID)
+		// then
+		if assert.NoError(t, err) {
+			_, err := r.GetCharacterMailLabel(ctx, c.ID, l1.LabelID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+			m2, err := r.GetCharacterMail(ctx, c.ID, m.MailID)
+			if assert.NoError(t, err) {
+				if assert.Len(t, m2.Labels, 1) {
+					assert.Equal(t, l2.LabelID, m2.Labels[0].LabelID)
+				}
+			}
+		}
+	})
 	t.Run("should return empty list when character has no mail labels", func(t *testing.T) {
 		// given
 		testutil.TruncateTables(db)
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
+)
+
+type UpdateOrCreateCharacterMailRuleParams struct {
+	CharacterID          int32
+	IsEnabled            bool
+	Keyword              string
+	LabelID              int32
+	MailListID           int32
+	MarkAsRead           bool
+	Name                 string
+	Sender               string
+	SuppressNotification bool
+}
+
+func (arg UpdateOrCreateCharacterMailRuleParams) isValid() bool {
+	return arg.CharacterID != 0 && arg.Name != ""
+}
+
+// CreateCharacterMailRule creates a new mail rule and returns it's ID.
+func (st *Storage) CreateCharacterMailRule(ctx context.Context, arg UpdateOrCreateCharacterMailRuleParams) (int64, error) {
+	if !arg.isValid() {
+		return 0, fmt.Errorf("CreateCharacterMailRule: %+v: %w", arg, app.ErrInvalid)
+	}
+	arg2 := queries.CreateCharacterMailRuleParams{
+		CharacterID:          int64(arg.CharacterID),
+		IsEnabled:            arg.IsEnabled,
+		Keyword:              arg.Keyword,
+		LabelID:              int64(arg.LabelID),
+		MailListID:           int64(arg.MailListID),
+		MarkAsRead:           arg.MarkAsRead,
+		Name:                 arg.Name,
+		Sender:               arg.Sender,
+		SuppressNotification: arg.SuppressNotification,
+	}
+	id, err := st.qRW.CreateCharacterMailRule(ctx, arg2)
+	if err != nil {
+		return 0, fmt.Errorf("create mail rule: %+v: %w", arg, err)
+	}
+	return id, nil
+}
+
+func (st *Storage) DeleteCharacterMailRule(ctx context.Context, characterID int32, id int64) error {
+	arg := queries.DeleteCharacterMailRuleParams{
+		CharacterID: int64(characterID),
+		ID:          id,
+	}
+	if err := st.qRW.DeleteCharacterMailRule(ctx, arg); err != nil {
+		return fmt.Errorf("delete mail rule %d for character %d: %w", id, characterID, err)
+	}
+	return nil
+}
+
+func (st *Storage) GetCharacterMailRule(ctx context.Context, characterID int32, id int64) (*app.CharacterMailRule, error) {
+	arg := queries.GetCharacterMailRuleParams{
+		CharacterID: int64(characterID),
+		ID:          id,
+	}
+	r, err := st.qRO.GetCharacterMailRule(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = app.ErrNotFound
+		}
+		return nil, fmt.Errorf("get mail rule %d for character %d: %w", id, characterID, err)
+	}
+	return characterMailRuleFromDBModel(r), nil
+}
+
+// ListCharacterMailRules returns the mail rules of a character ordered by name.
+func (st *Storage) ListCharacterMailRules(ctx context.Context, characterID int32) ([]*app.CharacterMailRule, error) {
+	rows, err := st.qRO.ListCharacterMailRules(ctx, int64(characterID))
+	if err != nil {
+		return nil, fmt.Errorf("list mail rules for character %d: %w", characterID, err)
+	}
+	oo := make([]*app.CharacterMailRule, len(rows))
+	for i, r := range rows {
+		oo[i] = characterMailRuleFromDBModel(r)
+	}
+	return oo, nil
+}
+
+func (st *Storage) UpdateCharacterMailRule(ctx context.Context, id int64, arg UpdateOrCreateCharacterMailRuleParams) error {
+	if !arg.isValid() {
+		return fmt.Errorf("UpdateCharacterMailRule: %+v: %w", arg, app.ErrInvalid)
+	}
+	arg2 := queries.UpdateCharacterMailRuleParams{
+		IsEnabled:            arg.IsEnabled,
+		Keyword:              arg.Keyword,
+		LabelID:              int64(arg.LabelID),
+		MailListID:           int64(arg.MailListID),
+		MarkAsRead:           arg.MarkAsRead,
+		Name:                 arg.Name,
+		Sender:               arg.Sender,
+		SuppressNotification: arg.SuppressNotification,
+		CharacterID:          int64(arg.CharacterID),
+		ID:                   id,
+	}
+	if err := st.qRW.UpdateCharacterMailRule(ctx, arg2); err != nil {
+		return fmt.Errorf("update mail rule %d: %+v: %w", id, arg, err)
+	}
+	return nil
+}
+
+func characterMailRuleFromDBModel(r queries.CharacterMailRule) *app.CharacterMailRule {
+	return &app.CharacterMailRule{
+		ID:                   r.ID,
+		CharacterID:          int32(r.CharacterID),
+		IsEnabled:            r.IsEnabled,
+		Keyword:              r.Keyword,
+		LabelID:              int32(r.LabelID),
+		MailListID:           int32(r.MailListID),
+		MarkAsRead:           r.MarkAsRead,
+		Name:                 r.Name,
+		Sender:               r.Sender,
+		SuppressNotification: r.SuppressNotification,
+	}
+}
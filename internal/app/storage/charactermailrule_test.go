@@ -0,0 +1,113 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestCharacterMailRule(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can create new", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		arg := storage.UpdateOrCreateCharacterMailRuleParams{
+			CharacterID:          c.ID,
+			IsEnabled:            true,
+			Keyword:              "keyword",
+			LabelID:              3,
+			MailListID:           42,
+			MarkAsRead:           true,
+			Name:                 "name",
+			Sender:               "sender",
+			SuppressNotification: true,
+		}
+		// when
+		id, err := st.CreateCharacterMailRule(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetCharacterMailRule(ctx, c.ID, id)
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.ID, o.CharacterID)
+				assert.True(t, o.IsEnabled)
+				assert.Equal(t, "keyword", o.Keyword)
+				assert.Equal(t, int32(3), o.LabelID)
+				assert.Equal(t, int32(42), o.MailListID)
+				assert.True(t, o.MarkAsRead)
+				assert.Equal(t, "name", o.Name)
+				assert.Equal(t, "sender", o.Sender)
+				assert.True(t, o.SuppressNotification)
+			}
+		}
+	})
+	t.Run("should return error when params invalid", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		_, err := st.CreateCharacterMailRule(ctx, storage.UpdateOrCreateCharacterMailRuleParams{CharacterID: c.ID})
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("can update existing", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o1 := factory.CreateCharacterMailRule()
+		// when
+		err := st.UpdateCharacterMailRule(ctx, o1.ID, storage.UpdateOrCreateCharacterMailRuleParams{
+			CharacterID: o1.CharacterID,
+			IsEnabled:   true,
+			Keyword:     "new keyword",
+			MarkAsRead:  true,
+			Name:        "new name",
+		})
+		// then
+		if assert.NoError(t, err) {
+			o2, err := st.GetCharacterMailRule(ctx, o1.CharacterID, o1.ID)
+			if assert.NoError(t, err) {
+				assert.True(t, o2.IsEnabled)
+				assert.Equal(t, "new keyword", o2.Keyword)
+				assert.True(t, o2.MarkAsRead)
+				assert.Equal(t, "new name", o2.Name)
+			}
+		}
+	})
+	t.Run("can delete", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o := factory.CreateCharacterMailRule()
+		// when
+		err := st.DeleteCharacterMailRule(ctx, o.CharacterID, o.ID)
+		// then
+		if assert.NoError(t, err) {
+			_, err := st.GetCharacterMailRule(ctx, o.CharacterID, o.ID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+		}
+	})
+	t.Run("can list rules of a character ordered by name", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		o1 := factory.CreateCharacterMailRule(storage.UpdateOrCreateCharacterMailRuleParams{CharacterID: c.ID, Name: "beta"})
+		o2 := factory.CreateCharacterMailRule(storage.UpdateOrCreateCharacterMailRuleParams{CharacterID: c.ID, Name: "alpha"})
+		factory.CreateCharacterMailRule()
+		// when
+		oo, err := st.ListCharacterMailRules(ctx, c.ID)
+		// then
+		if assert.NoError(t, err) {
+			got := make([]int64, len(oo))
+			for i, o := range oo {
+				got[i] = o.ID
+			}
+			assert.Equal(t, []int64{o2.ID, o1.ID}, got)
+		}
+	})
+}
@@ -91,6 +91,19 @@ func (st *Storage) GetOrCreateNotificationType(ctx context.Context, name string)
 	return id, nil
 }
 
+// ListAllCharacterNotifications returns the notifications of all characters.
+func (st *Storage) ListAllCharacterNotifications(ctx context.Context) ([]*app.CharacterNotification, error) {
+	rows, err := st.qRO.ListAllCharacterNotifications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications for all characters: %w", err)
+	}
+	ee := make([]*app.CharacterNotification, len(rows))
+	for i, r := range rows {
+		ee[i] = characterNotificationFromDBModel(r.CharacterNotification, r.EveEntity, r.NotificationType)
+	}
+	return ee, nil
+}
+
 func (st *Storage) ListCharacterNotificationIDs(ctx context.Context, characterID int32) (set.Set[int64], error) {
 	ids, err := st.qRO.ListCharacterNotificationIDs(ctx, int64(characterID))
 	if err != nil {
@@ -139,6 +152,25 @@ func (st *Storage) ListCharacterNotificationsUnread(ctx context.Context, charact
 	return ee, nil
 }
 
+// ListCharacterNotificationsForTypeAndTimespan returns the notifications of all characters
+// with a type and a timestamp within a timespan.
+func (st *Storage) ListCharacterNotificationsForTypeAndTimespan(ctx context.Context, type_ string, start, end time.Time) ([]*app.CharacterNotification, error) {
+	arg := queries.ListCharacterNotificationsForTypeAndTimespanParams{
+		Name:        type_,
+		Timestamp:   start,
+		Timestamp_2: end,
+	}
+	rows, err := st.qRO.ListCharacterNotificationsForTypeAndTimespan(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications for type and timespan %+v: %w", arg, err)
+	}
+	ee := make([]*app.CharacterNotification, len(rows))
+	for i, row := range rows {
+		ee[i] = characterNotificationFromDBModel(row.CharacterNotification, row.EveEntity, row.NotificationType)
+	}
+	return ee, nil
+}
+
 func (st *Storage) ListCharacterNotificationsUnprocessed(ctx context.Context, characterID int32, earliest time.Time) ([]*app.CharacterNotification, error) {
 	arg := queries.ListCharacterNotificationsUnprocessedParams{
 		CharacterID: int64(characterID),
@@ -202,6 +234,16 @@ func (st *Storage) UpdateCharacterNotificationSetProcessed(ctx context.Context,
 	return nil
 }
 
+func (st *Storage) UpdateCharacterNotificationsSetProcessed(ctx context.Context, ids set.Set[int64]) error {
+	if ids.Size() == 0 {
+		return nil
+	}
+	if err := st.qRW.UpdateCharacterNotificationsSetProcessed(ctx, ids.ToSlice()); err != nil {
+		return fmt.Errorf("update notifications set processed for ids %v: %w", ids, err)
+	}
+	return nil
+}
+
 func characterNotificationFromDBModel(o queries.CharacterNotification, sender queries.EveEntity, type_ queries.NotificationType) *app.CharacterNotification {
 	o2 := &app.CharacterNotification{
 		ID:             o.ID,
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
 	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
 	"github.com/ErikKalkoken/evebuddy/internal/optional"
@@ -276,6 +277,80 @@ func TestCharacterNotification(t *testing.T) {
 			assert.Equal(t, o.ID, ee[0].ID)
 		}
 	})
+	t.Run("can list notifs of all characters", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		now := time.Now().UTC()
+		o1 := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Timestamp: now.Add(-time.Hour),
+		})
+		o2 := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Timestamp: now,
+		})
+		// when
+		ee, err := r.ListAllCharacterNotifications(ctx)
+		// then
+		if assert.NoError(t, err) {
+			if assert.Len(t, ee, 2) {
+				assert.Equal(t, o2.ID, ee[0].ID)
+				assert.Equal(t, o1.ID, ee[1].ID)
+				assert.NotEqual(t, ee[0].CharacterID, ee[1].CharacterID)
+			}
+		}
+	})
+	t.Run("can set processed for many", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		o1 := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{CharacterID: c.ID})
+		o2 := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{CharacterID: c.ID})
+		o3 := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{CharacterID: c.ID})
+		// when
+		err := r.UpdateCharacterNotificationsSetProcessed(ctx, set.New(o1.ID, o2.ID))
+		// then
+		if assert.NoError(t, err) {
+			for _, x := range []struct {
+				n    *app.CharacterNotification
+				want bool
+			}{{o1, true}, {o2, true}, {o3, false}} {
+				o, err := r.GetCharacterNotification(ctx, c.ID, x.n.NotificationID)
+				if assert.NoError(t, err) {
+					assert.Equal(t, x.want, o.IsProcessed)
+				}
+			}
+		}
+	})
+	t.Run("can list notifs for type and timespan", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		now := time.Now().UTC()
+		o1 := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Type:      "alpha",
+			Timestamp: now,
+		})
+		o2 := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Type:      "alpha",
+			Timestamp: now.Add(time.Minute),
+		})
+		factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Type:      "bravo",
+			Timestamp: now,
+		})
+		factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Type:      "alpha",
+			Timestamp: now.Add(time.Hour),
+		})
+		// when
+		ee, err := r.ListCharacterNotificationsForTypeAndTimespan(ctx, "alpha", now.Add(-5*time.Minute), now.Add(5*time.Minute))
+		// then
+		if assert.NoError(t, err) {
+			got := set.New[int64]()
+			for _, e := range ee {
+				got.Add(e.ID)
+			}
+			assert.Equal(t, set.New(o1.ID, o2.ID), got)
+		}
+	})
 }
 
 func TestNotificationType(t *testing.T) {
@@ -60,11 +60,12 @@ func (st *Storage) ListCharacterSkillProgress(ctx context.Context, characterID,
 	oo := make([]app.ListSkillProgress, len(rows))
 	for i, r := range rows {
 		oo[i] = app.ListSkillProgress{
-			ActiveSkillLevel:  int(r.ActiveSkillLevel.Int64),
-			TypeDescription:   r.Description,
-			TypeID:            int32(r.ID),
-			TypeName:          r.Name,
-			TrainedSkillLevel: int(r.TrainedSkillLevel.Int64),
+			ActiveSkillLevel:   int(r.ActiveSkillLevel.Int64),
+			SkillPointsInSkill: int(r.SkillPointsInSkill.Int64),
+			TypeDescription:    r.Description,
+			TypeID:             int32(r.ID),
+			TypeName:           r.Name,
+			TrainedSkillLevel:  int(r.TrainedSkillLevel.Int64),
 		}
 	}
 	return oo, nil
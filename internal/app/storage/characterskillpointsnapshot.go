@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+type CreateCharacterSkillPointSnapshotParams struct {
+	CharacterID   int32
+	IsTraining    optional.Optional[bool]
+	RecordedAt    time.Time
+	TotalSP       int
+	UnallocatedSP int
+}
+
+func (st *Storage) CreateCharacterSkillPointSnapshot(ctx context.Context, arg CreateCharacterSkillPointSnapshotParams) error {
+	if arg.CharacterID == 0 || arg.RecordedAt.IsZero() {
+		return fmt.Errorf("CreateCharacterSkillPointSnapshot: %+v: %w", arg, app.ErrInvalid)
+	}
+	arg2 := queries.CreateCharacterSkillPointSnapshotParams{
+		CharacterID:   int64(arg.CharacterID),
+		RecordedAt:    arg.RecordedAt.UTC(),
+		TotalSp:       int64(arg.TotalSP),
+		UnallocatedSp: int64(arg.UnallocatedSP),
+		IsTraining:    optional.ToNullBool(arg.IsTraining),
+	}
+	if err := st.qRW.CreateCharacterSkillPointSnapshot(ctx, arg2); err != nil {
+		return fmt.Errorf("create skill point snapshot for character %d: %w", arg.CharacterID, err)
+	}
+	return nil
+}
+
+// DeleteCharacterSkillPointSnapshotsBefore deletes all skill point snapshots of a character
+// recorded before a point in time.
+func (st *Storage) DeleteCharacterSkillPointSnapshotsBefore(ctx context.Context, characterID int32, before time.Time) error {
+	arg := queries.DeleteCharacterSkillPointSnapshotsBeforeParams{
+		CharacterID: int64(characterID),
+		RecordedAt:  before.UTC(),
+	}
+	if err := st.qRW.DeleteCharacterSkillPointSnapshotsBefore(ctx, arg); err != nil {
+		return fmt.Errorf("delete skill point snapshots for character %d: %w", characterID, err)
+	}
+	return nil
+}
+
+// GetLatestCharacterSkillPointSnapshot returns the latest skill point snapshot of a character.
+func (st *Storage) GetLatestCharacterSkillPointSnapshot(ctx context.Context, characterID int32) (*app.CharacterSkillPointSnapshot, error) {
+	r, err := st.qRO.GetLatestCharacterSkillPointSnapshot(ctx, int64(characterID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = app.ErrNotFound
+		}
+		return nil, fmt.Errorf("get latest skill point snapshot for character %d: %w", characterID, err)
+	}
+	return characterSkillPointSnapshotFromDBModel(r), nil
+}
+
+// ListCharacterSkillPointSnapshots returns the skill point snapshots of a character
+// recorded since a point in time in ascending order.
+func (st *Storage) ListCharacterSkillPointSnapshots(ctx context.Context, characterID int32, since time.Time) ([]*app.CharacterSkillPointSnapshot, error) {
+	arg := queries.ListCharacterSkillPointSnapshotsParams{
+		CharacterID: int64(characterID),
+		RecordedAt:  since.UTC(),
+	}
+	rows, err := st.qRO.ListCharacterSkillPointSnapshots(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("list skill point snapshots for character %d: %w", characterID, err)
+	}
+	oo := make([]*app.CharacterSkillPointSnapshot, len(rows))
+	for i, r := range rows {
+		oo[i] = characterSkillPointSnapshotFromDBModel(r)
+	}
+	return oo, nil
+}
+
+func characterSkillPointSnapshotFromDBModel(o queries.CharacterSkillPointSnapshot) *app.CharacterSkillPointSnapshot {
+	return &app.CharacterSkillPointSnapshot{
+		CharacterID:   int32(o.CharacterID),
+		ID:            o.ID,
+		IsTraining:    optional.FromNullBool(o.IsTraining),
+		RecordedAt:    o.RecordedAt,
+		TotalSP:       int(o.TotalSp),
+		UnallocatedSP: int(o.UnallocatedSp),
+	}
+}
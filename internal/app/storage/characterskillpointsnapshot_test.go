@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+func TestCharacterSkillPointSnapshot(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can create and list snapshots", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		now := time.Now().UTC()
+		for i, sp := range []int{1000, 2000, 3000} {
+			arg := storage.CreateCharacterSkillPointSnapshotParams{
+				CharacterID:   c.ID,
+				IsTraining:    optional.New(i == 2),
+				RecordedAt:    now.Add(time.Duration(i-2) * 24 * time.Hour),
+				TotalSP:       sp,
+				UnallocatedSP: 42,
+			}
+			if err := st.CreateCharacterSkillPointSnapshot(ctx, arg); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// when
+		oo, err := st.ListCharacterSkillPointSnapshots(ctx, c.ID, now.Add(-36*time.Hour))
+		// then
+		if assert.NoError(t, err) {
+			if assert.Len(t, oo, 2) {
+				assert.Equal(t, 2000, oo[0].TotalSP)
+				assert.Equal(t, 3000, oo[1].TotalSP)
+				assert.Equal(t, 42, oo[1].UnallocatedSP)
+				assert.Equal(t, c.ID, oo[1].CharacterID)
+				assert.Equal(t, optional.New(true), oo[1].IsTraining)
+				assert.WithinDuration(t, now, oo[1].RecordedAt, time.Second)
+			}
+		}
+	})
+	t.Run("can get latest snapshot", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		now := time.Now().UTC()
+		for i, sp := range []int{1000, 2000} {
+			arg := storage.CreateCharacterSkillPointSnapshotParams{
+				CharacterID: c.ID,
+				RecordedAt:  now.Add(time.Duration(i-1) * time.Hour),
+				TotalSP:     sp,
+			}
+			if err := st.CreateCharacterSkillPointSnapshot(ctx, arg); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// when
+		o, err := st.GetLatestCharacterSkillPointSnapshot(ctx, c.ID)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2000, o.TotalSP)
+		}
+	})
+	t.Run("should return not found when there is no snapshot", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		// when
+		_, err := st.GetLatestCharacterSkillPointSnapshot(ctx, c.ID)
+		// then
+		assert.ErrorIs(t, err, app.ErrNotFound)
+	})
+	t.Run("can delete old snapshots", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		now := time.Now().UTC()
+		for i, sp := range []int{1000, 2000, 3000} {
+			arg := storage.CreateCharacterSkillPointSnapshotParams{
+				CharacterID: c.ID,
+				RecordedAt:  now.Add(time.Duration(i-2) * 24 * time.Hour),
+				TotalSP:     sp,
+			}
+			if err := st.CreateCharacterSkillPointSnapshot(ctx, arg); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// when
+		err := st.DeleteCharacterSkillPointSnapshotsBefore(ctx, c.ID, now.Add(-36*time.Hour))
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListCharacterSkillPointSnapshots(ctx, c.ID, now.Add(-365*24*time.Hour))
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 2)
+			}
+		}
+	})
+	t.Run("should return error when character is missing", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		arg := storage.CreateCharacterSkillPointSnapshotParams{RecordedAt: time.Now()}
+		// when
+		err := st.CreateCharacterSkillPointSnapshot(ctx, arg)
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+}
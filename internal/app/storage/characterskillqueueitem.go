@@ -135,6 +135,7 @@ func skillqueueItemFromDBModel(o queries.CharacterSkillqueueItem, skillName, gro
 		FinishedLevel:    int(o.FinishedLevel),
 		ID:               o.ID,
 		QueuePosition:    int(o.QueuePosition),
+		SkillID:          int32(o.EveTypeID),
 		SkillName:        skillName,
 		SkillDescription: description,
 	}
@@ -32,6 +32,7 @@ func TestSkillqueueItems(t *testing.T) {
 			i, err := r.GetCharacterSkillqueueItem(ctx, c.ID, 4)
 			if assert.NoError(t, err) {
 				assert.Equal(t, 5, i.FinishedLevel)
+				assert.Equal(t, eveType.ID, i.SkillID)
 			}
 		}
 	})
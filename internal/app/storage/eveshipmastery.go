@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
+)
+
+type CreateEveCertificateParams struct {
+	ID          int32
+	Description string
+	Name        string
+}
+
+type CreateEveCertificateSkillParams struct {
+	CertificateID int32
+	Level         int // certificate level from 1 to 5
+	SkillTypeID   int32
+	SkillLevel    int
+}
+
+type CreateEveShipMasteryParams struct {
+	CertificateID int32
+	Level         int // mastery level from 1 to 5
+	ShipTypeID    int32
+}
+
+// ReplaceEveShipMasteries replaces all certificates and ship masteries with the given ones.
+func (st *Storage) ReplaceEveShipMasteries(ctx context.Context, certificates []CreateEveCertificateParams, skills []CreateEveCertificateSkillParams, masteries []CreateEveShipMasteryParams) error {
+	err := func() error {
+		for _, arg := range skills {
+			if arg.CertificateID == 0 || arg.SkillTypeID == 0 || !isValidMasteryLevel(arg.Level) {
+				return fmt.Errorf("certificate skill %+v: %w", arg, app.ErrInvalid)
+			}
+		}
+		for _, arg := range masteries {
+			if arg.CertificateID == 0 || arg.ShipTypeID == 0 || !isValidMasteryLevel(arg.Level) {
+				return fmt.Errorf("ship mastery %+v: %w", arg, app.ErrInvalid)
+			}
+		}
+		tx, err := st.dbRW.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		qtx := st.qRW.WithTx(tx)
+		if err := qtx.TruncateEveShipMasteries(ctx); err != nil {
+			return err
+		}
+		if err := qtx.TruncateEveCertificateSkills(ctx); err != nil {
+			return err
+		}
+		if err := qtx.TruncateEveCertificates(ctx); err != nil {
+			return err
+		}
+		for _, arg := range certificates {
+			err := qtx.CreateEveCertificate(ctx, queries.CreateEveCertificateParams{
+				ID:          int64(arg.ID),
+				Description: arg.Description,
+				Name:        arg.Name,
+			})
+			if err != nil {
+				return fmt.Errorf("create certificate %d: %w", arg.ID, err)
+			}
+		}
+		for _, arg := range skills {
+			err := qtx.CreateEveCertificateSkill(ctx, queries.CreateEveCertificateSkillParams{
+				CertificateID: int64(arg.CertificateID),
+				Level:         int64(arg.Level),
+				SkillTypeID:   int64(arg.SkillTypeID),
+				SkillLevel:    int64(arg.SkillLevel),
+			})
+			if err != nil {
+				return fmt.Errorf("create certificate skill %+v: %w", arg, err)
+			}
+		}
+		for _, arg := range masteries {
+			err := qtx.CreateEveShipMastery(ctx, queries.CreateEveShipMasteryParams{
+				CertificateID: int64(arg.CertificateID),
+				Level:         int64(arg.Level),
+				ShipTypeID:    int64(arg.ShipTypeID),
+			})
+			if err != nil {
+				return fmt.Errorf("create ship mastery %+v: %w", arg, err)
+			}
+		}
+		return tx.Commit()
+	}()
+	if err != nil {
+		return fmt.Errorf("replace ship masteries: %w", err)
+	}
+	return nil
+}
+
+// ListEveShipMasterySkills returns the skills required for the mastery levels of all ships.
+func (st *Storage) ListEveShipMasterySkills(ctx context.Context) ([]app.EveShipMasterySkill, error) {
+	rows, err := st.qRO.ListEveShipMasterySkills(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list ship mastery skills: %w", err)
+	}
+	oo := make([]app.EveShipMasterySkill, len(rows))
+	for i, r := range rows {
+		oo[i] = eveShipMasterySkillFromDBModel(r.ShipTypeID, r.Level, r.SkillTypeID, r.SkillName.String, r.SkillLevel)
+	}
+	return oo, nil
+}
+
+// ListEveShipMasterySkillsForShip returns the skills required for the mastery levels of a ship.
+func (st *Storage) ListEveShipMasterySkillsForShip(ctx context.Context, shipTypeID int32) ([]app.EveShipMasterySkill, error) {
+	rows, err := st.qRO.ListEveShipMasterySkillsForShip(ctx, int64(shipTypeID))
+	if err != nil {
+		return nil, fmt.Errorf("list ship mastery skills for ship %d: %w", shipTypeID, err)
+	}
+	oo := make([]app.EveShipMasterySkill, len(rows))
+	for i, r := range rows {
+		oo[i] = eveShipMasterySkillFromDBModel(r.ShipTypeID, r.Level, r.SkillTypeID, r.SkillName.String, r.SkillLevel)
+	}
+	return oo, nil
+}
+
+func isValidMasteryLevel(level int) bool {
+	return level >= 1 && level <= app.EveShipMasteryLevelMax
+}
+
+func eveShipMasterySkillFromDBModel(shipTypeID, level, skillTypeID int64, skillName string, skillLevel int64) app.EveShipMasterySkill {
+	return app.EveShipMasterySkill{
+		Level:       int(level),
+		ShipTypeID:  int32(shipTypeID),
+		SkillLevel:  int(skillLevel),
+		SkillName:   skillName,
+		SkillTypeID: int32(skillTypeID),
+	}
+}
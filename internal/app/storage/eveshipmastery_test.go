@@ -0,0 +1,77 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+)
+
+func TestEveShipMastery(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can replace and list ship masteries", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		skill1 := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Alpha"})
+		skill2 := factory.CreateEveType(storage.CreateEveTypeParams{Name: "Bravo"})
+		err := st.ReplaceEveShipMasteries(
+			ctx,
+			[]storage.CreateEveCertificateParams{{ID: 1, Name: "Old"}},
+			nil,
+			[]storage.CreateEveShipMasteryParams{{CertificateID: 1, Level: 1, ShipTypeID: 99}},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certificates := []storage.CreateEveCertificateParams{
+			{ID: 10, Name: "Core Fitting"},
+			{ID: 11, Name: "Navigation"},
+		}
+		skills := []storage.CreateEveCertificateSkillParams{
+			{CertificateID: 10, Level: 1, SkillTypeID: skill1.ID, SkillLevel: 1},
+			{CertificateID: 10, Level: 2, SkillTypeID: skill1.ID, SkillLevel: 3},
+			{CertificateID: 11, Level: 1, SkillTypeID: skill1.ID, SkillLevel: 2},
+			{CertificateID: 11, Level: 1, SkillTypeID: skill2.ID, SkillLevel: 1},
+		}
+		masteries := []storage.CreateEveShipMasteryParams{
+			{CertificateID: 10, Level: 1, ShipTypeID: 42},
+			{CertificateID: 11, Level: 1, ShipTypeID: 42},
+			{CertificateID: 10, Level: 2, ShipTypeID: 42},
+			{CertificateID: 10, Level: 1, ShipTypeID: 43},
+		}
+		// when
+		err = st.ReplaceEveShipMasteries(ctx, certificates, skills, masteries)
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListEveShipMasterySkillsForShip(ctx, 42)
+			if assert.NoError(t, err) {
+				want := []app.EveShipMasterySkill{
+					{ShipTypeID: 42, Level: 1, SkillTypeID: skill1.ID, SkillName: "Alpha", SkillLevel: 2},
+					{ShipTypeID: 42, Level: 1, SkillTypeID: skill2.ID, SkillName: "Bravo", SkillLevel: 1},
+					{ShipTypeID: 42, Level: 2, SkillTypeID: skill1.ID, SkillName: "Alpha", SkillLevel: 3},
+				}
+				assert.Equal(t, want, oo)
+			}
+			oo, err = st.ListEveShipMasterySkills(ctx)
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 4)
+			}
+		}
+	})
+	t.Run("should return error when level is invalid", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		certificates := []storage.CreateEveCertificateParams{{ID: 10, Name: "Core Fitting"}}
+		masteries := []storage.CreateEveShipMasteryParams{{CertificateID: 10, Level: 0, ShipTypeID: 42}}
+		// when
+		err := st.ReplaceEveShipMasteries(ctx, certificates, nil, masteries)
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+}
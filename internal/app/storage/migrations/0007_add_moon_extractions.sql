@@ -0,0 +1,36 @@
+CREATE TABLE moon_extractions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    auto_fracture_at DATETIME NOT NULL,
+    chunk_arrival_at DATETIME NOT NULL,
+    corporation_id INTEGER NOT NULL,
+    eve_moon_id INTEGER NOT NULL,
+    last_notified DATETIME,
+    started_at DATETIME,
+    status TEXT NOT NULL,
+    structure_id INTEGER NOT NULL,
+    structure_name TEXT NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (corporation_id) REFERENCES eve_entities(id) ON DELETE CASCADE,
+    FOREIGN KEY (eve_moon_id) REFERENCES eve_moons(id) ON DELETE CASCADE,
+    UNIQUE (structure_id, auto_fracture_at)
+);
+
+CREATE INDEX moon_extractions_idx1 ON moon_extractions (corporation_id);
+
+CREATE INDEX moon_extractions_idx2 ON moon_extractions (eve_moon_id);
+
+CREATE INDEX moon_extractions_idx3 ON moon_extractions (chunk_arrival_at);
+
+CREATE TABLE moon_extraction_ores (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    moon_extraction_id INTEGER NOT NULL,
+    eve_type_id INTEGER NOT NULL,
+    volume REAL NOT NULL,
+    FOREIGN KEY (moon_extraction_id) REFERENCES moon_extractions(id) ON DELETE CASCADE,
+    FOREIGN KEY (eve_type_id) REFERENCES eve_types(id) ON DELETE CASCADE,
+    UNIQUE (moon_extraction_id, eve_type_id)
+);
+
+CREATE INDEX moon_extraction_ores_idx1 ON moon_extraction_ores (moon_extraction_id);
+
+CREATE INDEX moon_extraction_ores_idx2 ON moon_extraction_ores (eve_type_id);
@@ -0,0 +1,26 @@
+CREATE TABLE structure_timers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME NOT NULL,
+    eve_solar_system_id INTEGER NOT NULL,
+    eve_type_id INTEGER NOT NULL,
+    exits_at DATETIME NOT NULL,
+    is_manual BOOL NOT NULL,
+    last_notified DATETIME,
+    notes TEXT NOT NULL,
+    owner_id INTEGER,
+    structure_id INTEGER NOT NULL,
+    structure_name TEXT NOT NULL,
+    timer_type TEXT NOT NULL,
+    FOREIGN KEY (eve_solar_system_id) REFERENCES eve_solar_systems(id) ON DELETE CASCADE,
+    FOREIGN KEY (eve_type_id) REFERENCES eve_types(id) ON DELETE CASCADE,
+    FOREIGN KEY (owner_id) REFERENCES eve_entities(id) ON DELETE SET NULL,
+    UNIQUE (eve_solar_system_id, structure_id, eve_type_id, timer_type, exits_at)
+);
+
+CREATE INDEX structure_timers_idx1 ON structure_timers (eve_solar_system_id);
+
+CREATE INDEX structure_timers_idx2 ON structure_timers (eve_type_id);
+
+CREATE INDEX structure_timers_idx3 ON structure_timers (owner_id);
+
+CREATE INDEX structure_timers_idx4 ON structure_timers (exits_at);
@@ -0,0 +1,27 @@
+CREATE TABLE webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_ids TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    forward_alerts BOOL NOT NULL,
+    forward_mails BOOL NOT NULL,
+    notification_groups TEXT NOT NULL,
+    notification_types TEXT NOT NULL,
+    is_enabled BOOL NOT NULL,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL
+);
+
+CREATE TABLE webhook_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    attempts INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    last_error TEXT NOT NULL,
+    next_attempt_at DATETIME NOT NULL,
+    payload BLOB NOT NULL,
+    webhook_id INTEGER NOT NULL,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_messages_idx1 ON webhook_messages (webhook_id);
+
+CREATE INDEX webhook_messages_idx2 ON webhook_messages (next_attempt_at);
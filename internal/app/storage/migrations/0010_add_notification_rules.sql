@@ -0,0 +1,16 @@
+CREATE TABLE notification_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action TEXT NOT NULL,
+    channels TEXT NOT NULL,
+    character_ids TEXT NOT NULL,
+    is_enabled BOOL NOT NULL,
+    keyword TEXT NOT NULL,
+    name TEXT NOT NULL,
+    notification_groups TEXT NOT NULL,
+    notification_types TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    quiet_hours_enabled BOOL NOT NULL,
+    quiet_hours_end INTEGER NOT NULL,
+    quiet_hours_start INTEGER NOT NULL,
+    sender TEXT NOT NULL
+);
@@ -0,0 +1,18 @@
+CREATE TABLE character_mail_drafts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    attempts INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    character_id INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    next_attempt_at DATETIME NOT NULL,
+    quoted_body TEXT NOT NULL,
+    recipient_ids TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+CREATE INDEX character_mail_drafts_idx1 ON character_mail_drafts (character_id);
+
+CREATE INDEX character_mail_drafts_idx2 ON character_mail_drafts (status, next_attempt_at);
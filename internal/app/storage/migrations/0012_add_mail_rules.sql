@@ -0,0 +1,15 @@
+CREATE TABLE character_mail_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    is_enabled BOOL NOT NULL,
+    keyword TEXT NOT NULL,
+    label_id INTEGER NOT NULL,
+    mail_list_id INTEGER NOT NULL,
+    mark_as_read BOOL NOT NULL,
+    name TEXT NOT NULL,
+    sender TEXT NOT NULL,
+    suppress_notification BOOL NOT NULL,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+CREATE INDEX character_mail_rules_idx1 ON character_mail_rules (character_id);
@@ -0,0 +1,10 @@
+CREATE TABLE character_skill_point_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character_id INTEGER NOT NULL,
+    recorded_at DATETIME NOT NULL,
+    total_sp INTEGER NOT NULL,
+    unallocated_sp INTEGER NOT NULL,
+    FOREIGN KEY (character_id) REFERENCES characters(id) ON DELETE CASCADE
+);
+
+CREATE INDEX character_skill_point_snapshots_idx1 ON character_skill_point_snapshots (character_id, recorded_at);
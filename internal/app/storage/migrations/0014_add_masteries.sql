@@ -0,0 +1,28 @@
+CREATE TABLE eve_certificates (
+    id INTEGER PRIMARY KEY NOT NULL,
+    description TEXT NOT NULL,
+    name TEXT NOT NULL
+);
+
+CREATE TABLE eve_certificate_skills (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    certificate_id INTEGER NOT NULL,
+    level INTEGER NOT NULL,
+    skill_type_id INTEGER NOT NULL,
+    skill_level INTEGER NOT NULL,
+    FOREIGN KEY (certificate_id) REFERENCES eve_certificates(id) ON DELETE CASCADE,
+    UNIQUE (certificate_id, level, skill_type_id)
+);
+
+CREATE INDEX eve_certificate_skills_idx1 ON eve_certificate_skills (certificate_id, level);
+
+CREATE TABLE eve_ship_masteries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    certificate_id INTEGER NOT NULL,
+    level INTEGER NOT NULL,
+    ship_type_id INTEGER NOT NULL,
+    FOREIGN KEY (certificate_id) REFERENCES eve_certificates(id) ON DELETE CASCADE,
+    UNIQUE (ship_type_id, level, certificate_id)
+);
+
+CREATE INDEX eve_ship_masteries_idx1 ON eve_ship_masteries (ship_type_id);
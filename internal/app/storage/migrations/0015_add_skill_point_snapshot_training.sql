@@ -0,0 +1,2 @@
+-- whether the character was training when the snapshot was recorded (NULL = unknown)
+ALTER TABLE character_skill_point_snapshots ADD COLUMN is_training BOOLEAN;
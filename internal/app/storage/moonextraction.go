@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+var moonExtractionStatusFromDBValue = map[string]app.MoonExtractionStatus{
+	"":          app.MoonExtractionStatusUndefined,
+	"cancelled": app.MoonExtractionStatusCancelled,
+	"fractured": app.MoonExtractionStatusFractured,
+	"started":   app.MoonExtractionStatusStarted,
+}
+
+var moonExtractionStatusToDBValue = map[app.MoonExtractionStatus]string{}
+
+func init() {
+	for k, v := range moonExtractionStatusFromDBValue {
+		moonExtractionStatusToDBValue[v] = k
+	}
+}
+
+func (st *Storage) GetMoonExtraction(ctx context.Context, structureID int64, autoFractureAt time.Time) (*app.MoonExtraction, error) {
+	arg := queries.GetMoonExtractionParams{
+		StructureID:    structureID,
+		AutoFractureAt: autoFractureAt.UTC(),
+	}
+	r, err := st.qRO.GetMoonExtraction(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = app.ErrNotFound
+		}
+		return nil, fmt.Errorf("get moon extraction for %+v: %w", arg, err)
+	}
+	ores, err := st.listMoonExtractionOres(ctx, r.MoonExtraction.ID)
+	if err != nil {
+		return nil, err
+	}
+	return moonExtractionFromDBModel(queries.ListMoonExtractionsRow(r), ores), nil
+}
+
+func (st *Storage) ListMoonExtractions(ctx context.Context) ([]*app.MoonExtraction, error) {
+	rows, err := st.qRO.ListMoonExtractions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list moon extractions: %w", err)
+	}
+	oo := make([]*app.MoonExtraction, len(rows))
+	for i, r := range rows {
+		ores, err := st.listMoonExtractionOres(ctx, r.MoonExtraction.ID)
+		if err != nil {
+			return nil, err
+		}
+		oo[i] = moonExtractionFromDBModel(r, ores)
+	}
+	return oo, nil
+}
+
+// ListMoonExtractionsForStructure returns the extractions of a structure with the latest first.
+func (st *Storage) ListMoonExtractionsForStructure(ctx context.Context, structureID int64) ([]*app.MoonExtraction, error) {
+	rows, err := st.qRO.ListMoonExtractionsForStructure(ctx, structureID)
+	if err != nil {
+		return nil, fmt.Errorf("list moon extractions for structure %d: %w", structureID, err)
+	}
+	oo := make([]*app.MoonExtraction, len(rows))
+	for i, r := range rows {
+		ores, err := st.listMoonExtractionOres(ctx, r.MoonExtraction.ID)
+		if err != nil {
+			return nil, err
+		}
+		oo[i] = moonExtractionFromDBModel(queries.ListMoonExtractionsRow(r), ores)
+	}
+	return oo, nil
+}
+
+func (st *Storage) listMoonExtractionOres(ctx context.Context, extractionID int64) ([]*app.MoonExtractionOre, error) {
+	rows, err := st.qRO.ListMoonExtractionOres(ctx, extractionID)
+	if err != nil {
+		return nil, fmt.Errorf("list ores for moon extraction %d: %w", extractionID, err)
+	}
+	oo := make([]*app.MoonExtractionOre, len(rows))
+	for i, r := range rows {
+		oo[i] = &app.MoonExtractionOre{
+			AveragePrice: optional.FromNullFloat64(r.AveragePrice),
+			Type:         &app.EntityShort[int32]{ID: int32(r.EveTypeID), Name: r.TypeName},
+			TypeVolume:   r.TypeVolume,
+			Volume:       r.Volume,
+		}
+	}
+	return oo, nil
+}
+
+func moonExtractionFromDBModel(r queries.ListMoonExtractionsRow, ores []*app.MoonExtractionOre) *app.MoonExtraction {
+	ess := eveSolarSystemFromDBModel(r.EveSolarSystem, r.EveConstellation, r.EveRegion)
+	o := &app.MoonExtraction{
+		ID:             r.MoonExtraction.ID,
+		AutoFractureAt: r.MoonExtraction.AutoFractureAt,
+		ChunkArrivalAt: r.MoonExtraction.ChunkArrivalAt,
+		Corporation:    eveEntityFromDBModel(r.EveEntity),
+		LastNotified:   optional.FromNullTime(r.MoonExtraction.LastNotified),
+		Moon:           EveMoonFromDBModel(r.EveMoon, ess),
+		Ores:           ores,
+		StartedAt:      optional.FromNullTime(r.MoonExtraction.StartedAt),
+		Status:         moonExtractionStatusFromDBValue[r.MoonExtraction.Status],
+		StructureID:    r.MoonExtraction.StructureID,
+		StructureName:  r.MoonExtraction.StructureName,
+		UpdatedAt:      r.MoonExtraction.UpdatedAt,
+	}
+	return o
+}
+
+func (st *Storage) UpdateMoonExtractionLastNotified(ctx context.Context, id int64, lastNotified time.Time) error {
+	arg := queries.UpdateMoonExtractionLastNotifiedParams{
+		ID:           id,
+		LastNotified: NewNullTimeFromTime(lastNotified),
+	}
+	if err := st.qRW.UpdateMoonExtractionLastNotified(ctx, arg); err != nil {
+		return fmt.Errorf("update moon extraction last notified: %+v: %w", arg, err)
+	}
+	return nil
+}
+
+func (st *Storage) UpdateMoonExtractionStatus(ctx context.Context, id int64, status app.MoonExtractionStatus) error {
+	if status == app.MoonExtractionStatusUndefined {
+		return fmt.Errorf("update moon extraction status: %d: %w", id, app.ErrInvalid)
+	}
+	arg := queries.UpdateMoonExtractionStatusParams{
+		ID:        id,
+		Status:    moonExtractionStatusToDBValue[status],
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := st.qRW.UpdateMoonExtractionStatus(ctx, arg); err != nil {
+		return fmt.Errorf("update moon extraction status: %+v: %w", arg, err)
+	}
+	return nil
+}
+
+type UpdateOrCreateMoonExtractionParams struct {
+	AutoFractureAt time.Time
+	ChunkArrivalAt time.Time
+	CorporationID  int32
+	MoonID         int32
+	Ores           map[int32]float64 // volumes in m3 by type ID
+	StartedAt      optional.Optional[time.Time]
+	Status         app.MoonExtractionStatus
+	StructureID    int64
+	StructureName  string
+}
+
+func (arg UpdateOrCreateMoonExtractionParams) isValid() bool {
+	return arg.CorporationID != 0 &&
+		arg.MoonID != 0 &&
+		arg.StructureID != 0 &&
+		!arg.AutoFractureAt.IsZero() &&
+		!arg.ChunkArrivalAt.IsZero() &&
+		arg.Status != app.MoonExtractionStatusUndefined
+}
+
+// UpdateOrCreateMoonExtraction updates or creates a moon extraction and replaces it's ores.
+func (st *Storage) UpdateOrCreateMoonExtraction(ctx context.Context, arg UpdateOrCreateMoonExtractionParams) (int64, error) {
+	if !arg.isValid() {
+		return 0, fmt.Errorf("UpdateOrCreateMoonExtraction: %+v: %w", arg, app.ErrInvalid)
+	}
+	id, err := func() (int64, error) {
+		tx, err := st.dbRW.Begin()
+		if err != nil {
+			return 0, err
+		}
+		defer tx.Rollback()
+		qtx := st.qRW.WithTx(tx)
+		id, err := qtx.UpdateOrCreateMoonExtraction(ctx, queries.UpdateOrCreateMoonExtractionParams{
+			AutoFractureAt: arg.AutoFractureAt.UTC(),
+			ChunkArrivalAt: arg.ChunkArrivalAt.UTC(),
+			CorporationID:  int64(arg.CorporationID),
+			EveMoonID:      int64(arg.MoonID),
+			StartedAt:      optional.ToNullTime(arg.StartedAt),
+			Status:         moonExtractionStatusToDBValue[arg.Status],
+			StructureID:    arg.StructureID,
+			StructureName:  arg.StructureName,
+			UpdatedAt:      time.Now().UTC(),
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := qtx.DeleteMoonExtractionOres(ctx, id); err != nil {
+			return 0, err
+		}
+		for typeID, volume := range arg.Ores {
+			err := qtx.CreateMoonExtractionOre(ctx, queries.CreateMoonExtractionOreParams{
+				MoonExtractionID: id,
+				EveTypeID:        int64(typeID),
+				Volume:           volume,
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}()
+	if err != nil {
+		return 0, fmt.Errorf("update or create moon extraction: %+v: %w", arg, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,172 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/ErikKalkoken/evebuddy/internal/xslices"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoonExtraction(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can create new", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		corporation := factory.CreateEveEntityCorporation()
+		moon := factory.CreateEveMoon()
+		oreType := factory.CreateEveType(storage.CreateEveTypeParams{Volume: 10})
+		factory.CreateEveMarketPrice(storage.UpdateOrCreateEveMarketPriceParams{
+			TypeID:       oreType.ID,
+			AveragePrice: 50,
+		})
+		chunkArrivalAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		autoFractureAt := chunkArrivalAt.Add(3 * time.Hour)
+		startedAt := time.Now().UTC().Truncate(time.Second)
+		arg := storage.UpdateOrCreateMoonExtractionParams{
+			AutoFractureAt: autoFractureAt,
+			ChunkArrivalAt: chunkArrivalAt,
+			CorporationID:  corporation.ID,
+			MoonID:         moon.ID,
+			Ores:           map[int32]float64{oreType.ID: 1000},
+			StartedAt:      optional.New(startedAt),
+			Status:         app.MoonExtractionStatusStarted,
+			StructureID:    1_000_000_000_001,
+			StructureName:  "Alpha",
+		}
+		// when
+		_, err := st.UpdateOrCreateMoonExtraction(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetMoonExtraction(ctx, arg.StructureID, autoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, chunkArrivalAt, o.ChunkArrivalAt)
+				assert.Equal(t, autoFractureAt, o.AutoFractureAt)
+				assert.Equal(t, corporation, o.Corporation)
+				assert.Equal(t, moon, o.Moon)
+				assert.Equal(t, optional.New(startedAt), o.StartedAt)
+				assert.Equal(t, app.MoonExtractionStatusStarted, o.Status)
+				assert.Equal(t, "Alpha", o.StructureName)
+				if assert.Len(t, o.Ores, 1) {
+					ore := o.Ores[0]
+					assert.Equal(t, oreType.ID, ore.Type.ID)
+					assert.Equal(t, 1000.0, ore.Volume)
+					assert.Equal(t, 10.0, ore.TypeVolume)
+					assert.Equal(t, optional.New(50.0), ore.AveragePrice)
+				}
+				assert.Equal(t, optional.New(5000.0), o.EstimatedValue())
+			}
+		}
+	})
+	t.Run("can update existing and replace ores", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x1 := factory.CreateMoonExtraction()
+		oreType := factory.CreateEveType()
+		arg := storage.UpdateOrCreateMoonExtractionParams{
+			AutoFractureAt: x1.AutoFractureAt,
+			ChunkArrivalAt: x1.ChunkArrivalAt,
+			CorporationID:  x1.Corporation.ID,
+			MoonID:         x1.Moon.ID,
+			Ores:           map[int32]float64{oreType.ID: 42},
+			Status:         app.MoonExtractionStatusFractured,
+			StructureID:    x1.StructureID,
+			StructureName:  "Bravo",
+		}
+		// when
+		id, err := st.UpdateOrCreateMoonExtraction(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, x1.ID, id)
+			x2, err := st.GetMoonExtraction(ctx, x1.StructureID, x1.AutoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MoonExtractionStatusFractured, x2.Status)
+				assert.Equal(t, "Bravo", x2.StructureName)
+				if assert.Len(t, x2.Ores, 1) {
+					assert.Equal(t, oreType.ID, x2.Ores[0].Type.ID)
+				}
+			}
+		}
+	})
+	t.Run("should return not found error", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		// when
+		_, err := st.GetMoonExtraction(ctx, 42, time.Now())
+		// then
+		assert.ErrorIs(t, err, app.ErrNotFound)
+	})
+	t.Run("can list extractions", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x1 := factory.CreateMoonExtraction()
+		x2 := factory.CreateMoonExtraction()
+		// when
+		oo, err := st.ListMoonExtractions(ctx)
+		// then
+		if assert.NoError(t, err) {
+			got := xslices.Map(oo, func(x *app.MoonExtraction) int64 {
+				return x.ID
+			})
+			assert.ElementsMatch(t, []int64{x1.ID, x2.ID}, got)
+		}
+	})
+	t.Run("can list extractions for a structure with latest first", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		now := time.Now().UTC()
+		x1 := factory.CreateMoonExtraction(storage.UpdateOrCreateMoonExtractionParams{
+			StructureID:    1_000_000_000_001,
+			ChunkArrivalAt: now.Add(-30 * 24 * time.Hour),
+		})
+		x2 := factory.CreateMoonExtraction(storage.UpdateOrCreateMoonExtractionParams{
+			StructureID:    1_000_000_000_001,
+			ChunkArrivalAt: now.Add(-2 * 24 * time.Hour),
+		})
+		factory.CreateMoonExtraction()
+		// when
+		oo, err := st.ListMoonExtractionsForStructure(ctx, 1_000_000_000_001)
+		// then
+		if assert.NoError(t, err) {
+			got := xslices.Map(oo, func(x *app.MoonExtraction) int64 {
+				return x.ID
+			})
+			assert.Equal(t, []int64{x2.ID, x1.ID}, got)
+		}
+	})
+	t.Run("can update status", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x1 := factory.CreateMoonExtraction()
+		// when
+		err := st.UpdateMoonExtractionStatus(ctx, x1.ID, app.MoonExtractionStatusCancelled)
+		// then
+		if assert.NoError(t, err) {
+			x2, err := st.GetMoonExtraction(ctx, x1.StructureID, x1.AutoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.MoonExtractionStatusCancelled, x2.Status)
+			}
+		}
+	})
+	t.Run("can update last notified", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x1 := factory.CreateMoonExtraction()
+		// when
+		err := st.UpdateMoonExtractionLastNotified(ctx, x1.ID, x1.ChunkArrivalAt)
+		// then
+		if assert.NoError(t, err) {
+			x2, err := st.GetMoonExtraction(ctx, x1.StructureID, x1.AutoFractureAt)
+			if assert.NoError(t, err) {
+				assert.Equal(t, optional.New(x1.ChunkArrivalAt), x2.LastNotified)
+			}
+		}
+	})
+}
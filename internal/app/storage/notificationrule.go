@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+var notificationRuleActionFromDBValue = map[string]app.NotificationRuleAction{
+	"":         app.RuleActionUndefined,
+	"escalate": app.RuleActionEscalate,
+	"route":    app.RuleActionRoute,
+	"suppress": app.RuleActionSuppress,
+}
+
+var notificationRuleActionToDBValue = map[app.NotificationRuleAction]string{}
+
+var notificationChannelFromDBValue = map[string]app.NotificationChannel{
+	"desktop": app.ChannelDesktop,
+	"gotify":  app.ChannelGotify,
+	"ntfy":    app.ChannelNtfy,
+	"smtp":    app.ChannelSMTP,
+	"webhook": app.ChannelWebhook,
+}
+
+var notificationChannelToDBValue = map[app.NotificationChannel]string{}
+
+func init() {
+	for k, v := range notificationRuleActionFromDBValue {
+		notificationRuleActionToDBValue[v] = k
+	}
+	for k, v := range notificationChannelFromDBValue {
+		notificationChannelToDBValue[v] = k
+	}
+}
+
+type UpdateOrCreateNotificationRuleParams struct {
+	Action            app.NotificationRuleAction
+	Channels          set.Set[app.NotificationChannel]
+	CharacterIDs      set.Set[int32]
+	Groups            set.Set[app.NotificationGroup]
+	IsEnabled         bool
+	Keyword           string
+	Name              string
+	Position          int
+	QuietHoursEnabled bool
+	QuietHoursEnd     int
+	QuietHoursStart   int
+	Sender            string
+	Types             set.Set[string]
+}
+
+func (arg UpdateOrCreateNotificationRuleParams) isValid() bool {
+	isValidTime := func(m int) bool {
+		return m >= 0 && m < 24*60
+	}
+	return arg.Name != "" &&
+		arg.Action != app.RuleActionUndefined &&
+		isValidTime(arg.QuietHoursStart) &&
+		isValidTime(arg.QuietHoursEnd)
+}
+
+// notificationRuleDBValues contains the values of a rule which are stored as JSON.
+type notificationRuleDBValues struct {
+	channels     string
+	characterIDs string
+	groups       string
+	types        string
+}
+
+func (arg UpdateOrCreateNotificationRuleParams) dbValues() (notificationRuleDBValues, error) {
+	var x notificationRuleDBValues
+	channels := set.New[string]()
+	for c := range arg.Channels.Values() {
+		channels.Add(notificationChannelToDBValue[c])
+	}
+	var err error
+	x.channels, err = setToDBValue(channels)
+	if err != nil {
+		return x, err
+	}
+	x.characterIDs, err = setToDBValue(arg.CharacterIDs)
+	if err != nil {
+		return x, err
+	}
+	x.groups, err = notificationGroupsToDBValue(arg.Groups)
+	if err != nil {
+		return x, err
+	}
+	x.types, err = setToDBValue(arg.Types)
+	if err != nil {
+		return x, err
+	}
+	return x, nil
+}
+
+// CreateNotificationRule creates a new notification rule and returns it's ID.
+func (st *Storage) CreateNotificationRule(ctx context.Context, arg UpdateOrCreateNotificationRuleParams) (int64, error) {
+	if !arg.isValid() {
+		return 0, fmt.Errorf("CreateNotificationRule: %+v: %w", arg, app.ErrInvalid)
+	}
+	x, err := arg.dbValues()
+	if err != nil {
+		return 0, fmt.Errorf("create notification rule: %+v: %w", arg, err)
+	}
+	arg2 := queries.CreateNotificationRuleParams{
+		Action:             notificationRuleActionToDBValue[arg.Action],
+		Channels:           x.channels,
+		CharacterIds:       x.characterIDs,
+		IsEnabled:          arg.IsEnabled,
+		Keyword:            arg.Keyword,
+		Name:               arg.Name,
+		NotificationGroups: x.groups,
+		NotificationTypes:  x.types,
+		Position:           int64(arg.Position),
+		QuietHoursEnabled:  arg.QuietHoursEnabled,
+		QuietHoursEnd:      int64(arg.QuietHoursEnd),
+		QuietHoursStart:    int64(arg.QuietHoursStart),
+		Sender:             arg.Sender,
+	}
+	id, err := st.qRW.CreateNotificationRule(ctx, arg2)
+	if err != nil {
+		return 0, fmt.Errorf("create notification rule: %+v: %w", arg, err)
+	}
+	return id, nil
+}
+
+func (st *Storage) DeleteNotificationRule(ctx context.Context, id int64) error {
+	if err := st.qRW.DeleteNotificationRule(ctx, id); err != nil {
+		return fmt.Errorf("delete notification rule %d: %w", id, err)
+	}
+	return nil
+}
+
+func (st *Storage) GetNotificationRule(ctx context.Context, id int64) (*app.NotificationRule, error) {
+	r, err := st.qRO.GetNotificationRule(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = app.ErrNotFound
+		}
+		return nil, fmt.Errorf("get notification rule %d: %w", id, err)
+	}
+	o, err := notificationRuleFromDBModel(r)
+	if err != nil {
+		return nil, fmt.Errorf("get notification rule %d: %w", id, err)
+	}
+	return o, nil
+}
+
+// ListNotificationRules returns all notification rules in the order they are applied.
+func (st *Storage) ListNotificationRules(ctx context.Context) ([]*app.NotificationRule, error) {
+	rows, err := st.qRO.ListNotificationRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list notification rules: %w", err)
+	}
+	oo := make([]*app.NotificationRule, len(rows))
+	for i, r := range rows {
+		o, err := notificationRuleFromDBModel(r)
+		if err != nil {
+			return nil, fmt.Errorf("list notification rules: %w", err)
+		}
+		oo[i] = o
+	}
+	return oo, nil
+}
+
+func (st *Storage) UpdateNotificationRule(ctx context.Context, id int64, arg UpdateOrCreateNotificationRuleParams) error {
+	if !arg.isValid() {
+		return fmt.Errorf("UpdateNotificationRule: %+v: %w", arg, app.ErrInvalid)
+	}
+	x, err := arg.dbValues()
+	if err != nil {
+		return fmt.Errorf("update notification rule %d: %w", id, err)
+	}
+	arg2 := queries.UpdateNotificationRuleParams{
+		Action:             notificationRuleActionToDBValue[arg.Action],
+		Channels:           x.channels,
+		CharacterIds:       x.characterIDs,
+		IsEnabled:          arg.IsEnabled,
+		Keyword:            arg.Keyword,
+		Name:               arg.Name,
+		NotificationGroups: x.groups,
+		NotificationTypes:  x.types,
+		Position:           int64(arg.Position),
+		QuietHoursEnabled:  arg.QuietHoursEnabled,
+		QuietHoursEnd:      int64(arg.QuietHoursEnd),
+		QuietHoursStart:    int64(arg.QuietHoursStart),
+		Sender:             arg.Sender,
+		ID:                 id,
+	}
+	if err := st.qRW.UpdateNotificationRule(ctx, arg2); err != nil {
+		return fmt.Errorf("update notification rule %d: %+v: %w", id, arg, err)
+	}
+	return nil
+}
+
+func notificationRuleFromDBModel(r queries.NotificationRule) (*app.NotificationRule, error) {
+	channelNames, err := setFromDBValue[string](r.Channels)
+	if err != nil {
+		return nil, err
+	}
+	channels := set.New[app.NotificationChannel]()
+	for n := range channelNames.Values() {
+		if c, ok := notificationChannelFromDBValue[n]; ok {
+			channels.Add(c)
+		}
+	}
+	characterIDs, err := setFromDBValue[int32](r.CharacterIds)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := notificationGroupsFromDBValue(r.NotificationGroups)
+	if err != nil {
+		return nil, err
+	}
+	types, err := setFromDBValue[string](r.NotificationTypes)
+	if err != nil {
+		return nil, err
+	}
+	o := &app.NotificationRule{
+		ID:                r.ID,
+		Action:            notificationRuleActionFromDBValue[r.Action],
+		Channels:          channels,
+		CharacterIDs:      characterIDs,
+		Groups:            groups,
+		IsEnabled:         r.IsEnabled,
+		Keyword:           r.Keyword,
+		Name:              r.Name,
+		Position:          int(r.Position),
+		QuietHoursEnabled: r.QuietHoursEnabled,
+		QuietHoursEnd:     int(r.QuietHoursEnd),
+		QuietHoursStart:   int(r.QuietHoursStart),
+		Sender:            r.Sender,
+		Types:             types,
+	}
+	return o, nil
+}
@@ -0,0 +1,112 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRule(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can create new", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		arg := storage.UpdateOrCreateNotificationRuleParams{
+			Action:            app.RuleActionRoute,
+			Channels:          set.New(app.ChannelDesktop, app.ChannelNtfy),
+			CharacterIDs:      set.New[int32](42),
+			Groups:            set.New(app.GroupStructure),
+			IsEnabled:         true,
+			Keyword:           "keyword",
+			Name:              "Alpha",
+			Position:          3,
+			QuietHoursEnabled: true,
+			QuietHoursEnd:     7 * 60,
+			QuietHoursStart:   22 * 60,
+			Sender:            "sender",
+			Types:             set.New("StructureUnderAttack"),
+		}
+		// when
+		id, err := st.CreateNotificationRule(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetNotificationRule(ctx, id)
+			if assert.NoError(t, err) {
+				assert.Equal(t, app.RuleActionRoute, o.Action)
+				assert.Equal(t, set.New(app.ChannelDesktop, app.ChannelNtfy), o.Channels)
+				assert.Equal(t, set.New[int32](42), o.CharacterIDs)
+				assert.Equal(t, set.New(app.GroupStructure), o.Groups)
+				assert.True(t, o.IsEnabled)
+				assert.Equal(t, "keyword", o.Keyword)
+				assert.Equal(t, "Alpha", o.Name)
+				assert.Equal(t, 3, o.Position)
+				assert.True(t, o.QuietHoursEnabled)
+				assert.Equal(t, 7*60, o.QuietHoursEnd)
+				assert.Equal(t, 22*60, o.QuietHoursStart)
+				assert.Equal(t, "sender", o.Sender)
+				assert.Equal(t, set.New("StructureUnderAttack"), o.Types)
+			}
+		}
+	})
+	t.Run("should return error when params invalid", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		// when
+		_, err := st.CreateNotificationRule(ctx, storage.UpdateOrCreateNotificationRuleParams{Name: "Alpha"})
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("can update existing", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x := factory.CreateNotificationRule()
+		// when
+		err := st.UpdateNotificationRule(ctx, x.ID, storage.UpdateOrCreateNotificationRuleParams{
+			Action: app.RuleActionSuppress,
+			Name:   "Bravo",
+		})
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetNotificationRule(ctx, x.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "Bravo", o.Name)
+				assert.Equal(t, app.RuleActionSuppress, o.Action)
+			}
+		}
+	})
+	t.Run("can list rules in order", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x1 := factory.CreateNotificationRule(storage.UpdateOrCreateNotificationRuleParams{Position: 2})
+		x2 := factory.CreateNotificationRule(storage.UpdateOrCreateNotificationRuleParams{Position: 1})
+		// when
+		oo, err := st.ListNotificationRules(ctx)
+		// then
+		if assert.NoError(t, err) {
+			got := make([]int64, 0)
+			for _, o := range oo {
+				got = append(got, o.ID)
+			}
+			assert.Equal(t, []int64{x2.ID, x1.ID}, got)
+		}
+	})
+	t.Run("can delete rule", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x := factory.CreateNotificationRule()
+		// when
+		err := st.DeleteNotificationRule(ctx, x.ID)
+		// then
+		if assert.NoError(t, err) {
+			_, err := st.GetNotificationRule(ctx, x.ID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+		}
+	})
+}
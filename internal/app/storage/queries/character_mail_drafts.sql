@@ -0,0 +1,98 @@
+-- name: CreateCharacterMailDraft :one
+INSERT INTO
+    character_mail_drafts (
+        attempts,
+        body,
+        character_id,
+        last_error,
+        next_attempt_at,
+        quoted_body,
+        recipient_ids,
+        status,
+        subject,
+        updated_at
+    )
+VALUES
+    (0, ?, ?, '', ?, ?, ?, ?, ?, ?) RETURNING id;
+
+-- name: DeleteCharacterMailDraft :exec
+DELETE FROM
+    character_mail_drafts
+WHERE
+    character_id = ?
+    AND id = ?;
+
+-- name: GetCharacterMailDraft :one
+SELECT
+    *
+FROM
+    character_mail_drafts
+WHERE
+    character_id = ?
+    AND id = ?;
+
+-- name: ListCharacterMailDrafts :many
+SELECT
+    *
+FROM
+    character_mail_drafts
+WHERE
+    character_id = ?
+ORDER BY
+    updated_at DESC;
+
+-- name: ListCharacterMailDraftsDue :many
+SELECT
+    *
+FROM
+    character_mail_drafts
+WHERE
+    status = ?
+    AND next_attempt_at <= ?
+ORDER BY
+    id;
+
+-- name: UpdateCharacterMailDraft :exec
+UPDATE
+    character_mail_drafts
+SET
+    attempts = 0,
+    body = ?,
+    last_error = '',
+    next_attempt_at = ?,
+    quoted_body = ?,
+    recipient_ids = ?,
+    status = ?,
+    subject = ?,
+    updated_at = ?
+WHERE
+    character_id = ?
+    AND id = ?;
+
+-- name: UpdateCharacterMailDraftAttempt :exec
+UPDATE
+    character_mail_drafts
+SET
+    attempts = ?,
+    last_error = ?,
+    next_attempt_at = ?,
+    status = ?
+WHERE
+    id = ?;
+
+-- name: UpdateCharacterMailDraftStatusIf :execrows
+UPDATE
+    character_mail_drafts
+SET
+    status = ?
+WHERE
+    id = ?
+    AND status = ?;
+
+-- name: UpdateCharacterMailDraftsStatus :exec
+UPDATE
+    character_mail_drafts
+SET
+    status = ?
+WHERE
+    status = ?;
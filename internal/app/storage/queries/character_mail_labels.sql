@@ -11,6 +11,10 @@ VALUES (
 )
 RETURNING *;
 
+-- name: DeleteCharacterMailLabel :exec
+DELETE FROM character_mail_labels
+WHERE character_id = ? AND label_id = ?;
+
 -- name: DeleteObsoleteCharacterMailLabels :exec
 DELETE FROM character_mail_labels
 WHERE character_mail_labels.character_id = ?
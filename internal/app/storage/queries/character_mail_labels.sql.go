@@ -52,6 +52,21 @@ func (q *Queries) CreateCharacterMailLabel(ctx context.Context, arg CreateCharac
 	return i, err
 }
 
+const deleteCharacterMailLabel = `-- name: DeleteCharacterMailLabel :exec
+DELETE FROM character_mail_labels
+WHERE character_id = ? AND label_id = ?
+`
+
+type DeleteCharacterMailLabelParams struct {
+	CharacterID int64
+	LabelID     int64
+}
+
+func (q *Queries) DeleteCharacterMailLabel(ctx context.Context, arg DeleteCharacterMailLabelParams) error {
+	_, err := q.db.ExecContext(ctx, deleteCharacterMailLabel, arg.CharacterID, arg.LabelID)
+	return err
+}
+
 const deleteObsoleteCharacterMailLabels = `-- name: DeleteObsoleteCharacterMailLabels :exec
 DELETE FROM character_mail_labels
 WHERE character_mail_labels.character_id = ?
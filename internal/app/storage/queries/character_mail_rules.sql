@@ -0,0 +1,58 @@
+-- name: CreateCharacterMailRule :one
+INSERT INTO
+    character_mail_rules (
+        character_id,
+        is_enabled,
+        keyword,
+        label_id,
+        mail_list_id,
+        mark_as_read,
+        name,
+        sender,
+        suppress_notification
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id;
+
+-- name: DeleteCharacterMailRule :exec
+DELETE FROM
+    character_mail_rules
+WHERE
+    character_id = ?
+    AND id = ?;
+
+-- name: GetCharacterMailRule :one
+SELECT
+    *
+FROM
+    character_mail_rules
+WHERE
+    character_id = ?
+    AND id = ?;
+
+-- name: ListCharacterMailRules :many
+SELECT
+    *
+FROM
+    character_mail_rules
+WHERE
+    character_id = ?
+ORDER BY
+    name,
+    id;
+
+-- name: UpdateCharacterMailRule :exec
+UPDATE
+    character_mail_rules
+SET
+    is_enabled = ?,
+    keyword = ?,
+    label_id = ?,
+    mail_list_id = ?,
+    mark_as_read = ?,
+    name = ?,
+    sender = ?,
+    suppress_notification = ?
+WHERE
+    character_id = ?
+    AND id = ?;
@@ -132,6 +132,17 @@ FROM
 WHERE
     character_id = ?;
 
+-- name: ListMailParticipants :many
+SELECT
+    cm.mail_id,
+    cm.from_id,
+    cmr.eve_entity_id AS recipient_id
+FROM
+    character_mails cm
+    LEFT JOIN character_mails_recipients cmr ON cmr.mail_id = cm.id
+WHERE
+    cm.character_id = ?;
+
 -- name: ListMailsOrdered :many
 SELECT
     sqlc.embed(cm),
@@ -7,6 +7,7 @@ package queries
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
@@ -413,6 +414,47 @@ func (q *Queries) ListMailIDs(ctx context.Context, characterID int64) ([]int64,
 	return items, nil
 }
 
+const listMailParticipants = `-- name: ListMailParticipants :many
+SELECT
+    cm.mail_id,
+    cm.from_id,
+    cmr.eve_entity_id AS recipient_id
+FROM
+    character_mails cm
+    LEFT JOIN character_mails_recipients cmr ON cmr.mail_id = cm.id
+WHERE
+    cm.character_id = ?
+`
+
+type ListMailParticipantsRow struct {
+	MailID      int64
+	FromID      int64
+	RecipientID sql.NullInt64
+}
+
+func (q *Queries) ListMailParticipants(ctx context.Context, characterID int64) ([]ListMailParticipantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMailParticipants, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMailParticipantsRow
+	for rows.Next() {
+		var i ListMailParticipantsRow
+		if err := rows.Scan(&i.MailID, &i.FromID, &i.RecipientID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMailsForLabelOrdered = `-- name: ListMailsForLabelOrdered :many
 SELECT
     cm.id, cm.body, cm.character_id, cm.from_id, cm.is_processed, cm.is_read, cm.mail_id, cm.subject, cm.timestamp,
@@ -80,6 +80,18 @@ WHERE
 ORDER BY
     timestamp DESC;
 
+-- name: ListAllCharacterNotifications :many
+SELECT
+    sqlc.embed(cn),
+    sqlc.embed(ee),
+    sqlc.embed(nt)
+FROM
+    character_notifications cn
+    JOIN eve_entities ee ON ee.id = cn.sender_id
+    JOIN notification_types nt ON nt.id = cn.type_id
+ORDER BY
+    timestamp DESC;
+
 -- name: ListCharacterNotificationsUnread :many
 SELECT
     sqlc.embed(cn),
@@ -95,6 +107,22 @@ WHERE
 ORDER BY
     timestamp DESC;
 
+-- name: ListCharacterNotificationsForTypeAndTimespan :many
+SELECT
+    sqlc.embed(cn),
+    sqlc.embed(ee),
+    sqlc.embed(nt)
+FROM
+    character_notifications cn
+    JOIN eve_entities ee ON ee.id = cn.sender_id
+    JOIN notification_types nt ON nt.id = cn.type_id
+WHERE
+    nt.name = ?
+    AND timestamp >= ?
+    AND timestamp <= ?
+ORDER BY
+    timestamp;
+
 -- name: ListCharacterNotificationsUnprocessed :many
 SELECT
     sqlc.embed(cn),
@@ -131,6 +159,14 @@ SET
 WHERE
     id = ?1;
 
+-- name: UpdateCharacterNotificationsSetProcessed :exec
+UPDATE
+    character_notifications
+SET
+    is_processed = TRUE
+WHERE
+    id IN (sqlc.slice('ids'));
+
 -- name: CreateNotificationType :one
 INSERT INTO
     notification_types (name)
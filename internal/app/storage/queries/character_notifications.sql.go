@@ -189,6 +189,65 @@ func (q *Queries) GetNotificationTypeID(ctx context.Context, name string) (int64
 	return id, err
 }
 
+const listAllCharacterNotifications = `-- name: ListAllCharacterNotifications :many
+SELECT
+    cn.id, cn.body, cn.character_id, cn.is_processed, cn.is_read, cn.notification_id, cn.sender_id, cn.text, cn.timestamp, cn.title, cn.type_id,
+    ee.id, ee.category, ee.name,
+    nt.id, nt.name
+FROM
+    character_notifications cn
+    JOIN eve_entities ee ON ee.id = cn.sender_id
+    JOIN notification_types nt ON nt.id = cn.type_id
+ORDER BY
+    timestamp DESC
+`
+
+type ListAllCharacterNotificationsRow struct {
+	CharacterNotification CharacterNotification
+	EveEntity             EveEntity
+	NotificationType      NotificationType
+}
+
+func (q *Queries) ListAllCharacterNotifications(ctx context.Context) ([]ListAllCharacterNotificationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllCharacterNotifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllCharacterNotificationsRow
+	for rows.Next() {
+		var i ListAllCharacterNotificationsRow
+		if err := rows.Scan(
+			&i.CharacterNotification.ID,
+			&i.CharacterNotification.Body,
+			&i.CharacterNotification.CharacterID,
+			&i.CharacterNotification.IsProcessed,
+			&i.CharacterNotification.IsRead,
+			&i.CharacterNotification.NotificationID,
+			&i.CharacterNotification.SenderID,
+			&i.CharacterNotification.Text,
+			&i.CharacterNotification.Timestamp,
+			&i.CharacterNotification.Title,
+			&i.CharacterNotification.TypeID,
+			&i.EveEntity.ID,
+			&i.EveEntity.Category,
+			&i.EveEntity.Name,
+			&i.NotificationType.ID,
+			&i.NotificationType.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCharacterNotificationIDs = `-- name: ListCharacterNotificationIDs :many
 SELECT
     notification_id
@@ -282,6 +341,75 @@ func (q *Queries) ListCharacterNotificationsAll(ctx context.Context, characterID
 	return items, nil
 }
 
+const listCharacterNotificationsForTypeAndTimespan = `-- name: ListCharacterNotificationsForTypeAndTimespan :many
+SELECT
+    cn.id, cn.body, cn.character_id, cn.is_processed, cn.is_read, cn.notification_id, cn.sender_id, cn.text, cn.timestamp, cn.title, cn.type_id,
+    ee.id, ee.category, ee.name,
+    nt.id, nt.name
+FROM
+    character_notifications cn
+    JOIN eve_entities ee ON ee.id = cn.sender_id
+    JOIN notification_types nt ON nt.id = cn.type_id
+WHERE
+    nt.name = ?
+    AND timestamp >= ?
+    AND timestamp <= ?
+ORDER BY
+    timestamp
+`
+
+type ListCharacterNotificationsForTypeAndTimespanParams struct {
+	Name        string
+	Timestamp   time.Time
+	Timestamp_2 time.Time
+}
+
+type ListCharacterNotificationsForTypeAndTimespanRow struct {
+	CharacterNotification CharacterNotification
+	EveEntity             EveEntity
+	NotificationType      NotificationType
+}
+
+func (q *Queries) ListCharacterNotificationsForTypeAndTimespan(ctx context.Context, arg ListCharacterNotificationsForTypeAndTimespanParams) ([]ListCharacterNotificationsForTypeAndTimespanRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacterNotificationsForTypeAndTimespan, arg.Name, arg.Timestamp, arg.Timestamp_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCharacterNotificationsForTypeAndTimespanRow
+	for rows.Next() {
+		var i ListCharacterNotificationsForTypeAndTimespanRow
+		if err := rows.Scan(
+			&i.CharacterNotification.ID,
+			&i.CharacterNotification.Body,
+			&i.CharacterNotification.CharacterID,
+			&i.CharacterNotification.IsProcessed,
+			&i.CharacterNotification.IsRead,
+			&i.CharacterNotification.NotificationID,
+			&i.CharacterNotification.SenderID,
+			&i.CharacterNotification.Text,
+			&i.CharacterNotification.Timestamp,
+			&i.CharacterNotification.Title,
+			&i.CharacterNotification.TypeID,
+			&i.EveEntity.ID,
+			&i.EveEntity.Category,
+			&i.EveEntity.Name,
+			&i.NotificationType.ID,
+			&i.NotificationType.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCharacterNotificationsTypes = `-- name: ListCharacterNotificationsTypes :many
 SELECT
     cn.id, cn.body, cn.character_id, cn.is_processed, cn.is_read, cn.notification_id, cn.sender_id, cn.text, cn.timestamp, cn.title, cn.type_id,
@@ -533,3 +661,27 @@ func (q *Queries) UpdateCharacterNotificationSetProcessed(ctx context.Context, i
 	_, err := q.db.ExecContext(ctx, updateCharacterNotificationSetProcessed, id)
 	return err
 }
+
+const updateCharacterNotificationsSetProcessed = `-- name: UpdateCharacterNotificationsSetProcessed :exec
+UPDATE
+    character_notifications
+SET
+    is_processed = TRUE
+WHERE
+    id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) UpdateCharacterNotificationsSetProcessed(ctx context.Context, ids []int64) error {
+	query := updateCharacterNotificationsSetProcessed
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	_, err := q.db.ExecContext(ctx, query, queryParams...)
+	return err
+}
@@ -0,0 +1,41 @@
+-- name: CreateCharacterSkillPointSnapshot :exec
+INSERT INTO
+    character_skill_point_snapshots (
+        character_id,
+        recorded_at,
+        total_sp,
+        unallocated_sp,
+        is_training
+    )
+VALUES
+    (?, ?, ?, ?, ?);
+
+-- name: DeleteCharacterSkillPointSnapshotsBefore :exec
+DELETE FROM
+    character_skill_point_snapshots
+WHERE
+    character_id = ?
+    AND recorded_at < ?;
+
+-- name: GetLatestCharacterSkillPointSnapshot :one
+SELECT
+    *
+FROM
+    character_skill_point_snapshots
+WHERE
+    character_id = ?
+ORDER BY
+    recorded_at DESC
+LIMIT
+    1;
+
+-- name: ListCharacterSkillPointSnapshots :many
+SELECT
+    *
+FROM
+    character_skill_point_snapshots
+WHERE
+    character_id = ?
+    AND recorded_at >= ?
+ORDER BY
+    recorded_at;
@@ -101,6 +101,7 @@ SELECT
     eve_types.name,
     eve_types.description,
     character_skills.active_skill_level,
+    character_skills.skill_points_in_skill,
     character_skills.trained_skill_level
 FROM
     eve_types
@@ -339,6 +339,7 @@ SELECT
     eve_types.name,
     eve_types.description,
     character_skills.active_skill_level,
+    character_skills.skill_points_in_skill,
     character_skills.trained_skill_level
 FROM
     eve_types
@@ -357,11 +358,12 @@ type ListCharacterSkillProgressParams struct {
 }
 
 type ListCharacterSkillProgressRow struct {
-	ID                int64
-	Name              string
-	Description       string
-	ActiveSkillLevel  sql.NullInt64
-	TrainedSkillLevel sql.NullInt64
+	ID                 int64
+	Name               string
+	Description        string
+	ActiveSkillLevel   sql.NullInt64
+	SkillPointsInSkill sql.NullInt64
+	TrainedSkillLevel  sql.NullInt64
 }
 
 func (q *Queries) ListCharacterSkillProgress(ctx context.Context, arg ListCharacterSkillProgressParams) ([]ListCharacterSkillProgressRow, error) {
@@ -378,6 +380,7 @@ func (q *Queries) ListCharacterSkillProgress(ctx context.Context, arg ListCharac
 			&i.Name,
 			&i.Description,
 			&i.ActiveSkillLevel,
+			&i.SkillPointsInSkill,
 			&i.TrainedSkillLevel,
 		); err != nil {
 			return nil, err
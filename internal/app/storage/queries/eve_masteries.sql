@@ -0,0 +1,66 @@
+-- name: CreateEveCertificate :exec
+INSERT INTO eve_certificates (
+    id,
+    description,
+    name
+)
+VALUES (
+    ?, ?, ?
+);
+
+-- name: CreateEveCertificateSkill :exec
+INSERT INTO eve_certificate_skills (
+    certificate_id,
+    level,
+    skill_type_id,
+    skill_level
+)
+VALUES (
+    ?, ?, ?, ?
+);
+
+-- name: CreateEveShipMastery :exec
+INSERT INTO eve_ship_masteries (
+    certificate_id,
+    level,
+    ship_type_id
+)
+VALUES (
+    ?, ?, ?
+);
+
+-- name: ListEveShipMasterySkills :many
+SELECT
+    esm.ship_type_id,
+    esm.level,
+    ecs.skill_type_id,
+    skt.name as skill_name,
+    CAST(MAX(ecs.skill_level) AS INTEGER) as skill_level
+FROM eve_ship_masteries esm
+JOIN eve_certificate_skills ecs ON ecs.certificate_id = esm.certificate_id AND ecs.level = esm.level
+LEFT JOIN eve_types skt ON skt.id = ecs.skill_type_id
+GROUP BY esm.ship_type_id, esm.level, ecs.skill_type_id
+ORDER BY esm.ship_type_id, esm.level, skt.name;
+
+-- name: ListEveShipMasterySkillsForShip :many
+SELECT
+    esm.ship_type_id,
+    esm.level,
+    ecs.skill_type_id,
+    skt.name as skill_name,
+    CAST(MAX(ecs.skill_level) AS INTEGER) as skill_level
+FROM eve_ship_masteries esm
+JOIN eve_certificate_skills ecs ON ecs.certificate_id = esm.certificate_id AND ecs.level = esm.level
+LEFT JOIN eve_types skt ON skt.id = ecs.skill_type_id
+WHERE esm.ship_type_id = ?
+GROUP BY esm.ship_type_id, esm.level, ecs.skill_type_id
+ORDER BY esm.level, skt.name;
+
+-- name: TruncateEveCertificateSkills :exec
+DELETE FROM eve_certificate_skills;
+
+-- name: TruncateEveCertificates :exec
+DELETE FROM eve_certificates;
+
+-- name: TruncateEveShipMasteries :exec
+DELETE FROM eve_ship_masteries;
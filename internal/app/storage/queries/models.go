@@ -138,6 +138,20 @@ type CharacterMail struct {
 	Timestamp   time.Time
 }
 
+type CharacterMailDraft struct {
+	ID            int64
+	Attempts      int64
+	Body          string
+	CharacterID   int64
+	LastError     string
+	NextAttemptAt time.Time
+	QuotedBody    string
+	RecipientIds  string
+	Status        int64
+	Subject       string
+	UpdatedAt     time.Time
+}
+
 type CharacterMailLabel struct {
 	ID          int64
 	CharacterID int64
@@ -159,6 +173,19 @@ type CharacterMailMailLabel struct {
 	CharacterMailID      int64
 }
 
+type CharacterMailRule struct {
+	ID                   int64
+	CharacterID          int64
+	IsEnabled            bool
+	Keyword              string
+	LabelID              int64
+	MailListID           int64
+	MarkAsRead           bool
+	Name                 string
+	Sender               string
+	SuppressNotification bool
+}
+
 type CharacterMailsRecipient struct {
 	ID          int64
 	MailID      int64
@@ -209,6 +236,15 @@ type CharacterSkill struct {
 	TrainedSkillLevel  int64
 }
 
+type CharacterSkillPointSnapshot struct {
+	ID            int64
+	CharacterID   int64
+	RecordedAt    time.Time
+	TotalSp       int64
+	UnallocatedSp int64
+	IsTraining    sql.NullBool
+}
+
 type CharacterSkillqueueItem struct {
 	ID              int64
 	CharacterID     int64
@@ -276,6 +312,20 @@ type EveCategory struct {
 	IsPublished bool
 }
 
+type EveCertificate struct {
+	ID          int64
+	Description string
+	Name        string
+}
+
+type EveCertificateSkill struct {
+	ID            int64
+	CertificateID int64
+	Level         int64
+	SkillTypeID   int64
+	SkillLevel    int64
+}
+
 type EveCharacter struct {
 	AllianceID     sql.NullInt64
 	Birthday       time.Time
@@ -368,6 +418,13 @@ type EveSchematic struct {
 	CycleTime int64
 }
 
+type EveShipMastery struct {
+	ID            int64
+	CertificateID int64
+	Level         int64
+	ShipTypeID    int64
+}
+
 type EveShipSkill struct {
 	ID          int64
 	Rank        int64
@@ -425,6 +482,44 @@ type GeneralSectionStatus struct {
 	StartedAt   sql.NullTime
 }
 
+type MoonExtraction struct {
+	ID             int64
+	AutoFractureAt time.Time
+	ChunkArrivalAt time.Time
+	CorporationID  int64
+	EveMoonID      int64
+	LastNotified   sql.NullTime
+	StartedAt      sql.NullTime
+	Status         string
+	StructureID    int64
+	StructureName  string
+	UpdatedAt      time.Time
+}
+
+type MoonExtractionOre struct {
+	ID               int64
+	MoonExtractionID int64
+	EveTypeID        int64
+	Volume           float64
+}
+
+type NotificationRule struct {
+	ID                 int64
+	Action             string
+	Channels           string
+	CharacterIds       string
+	IsEnabled          bool
+	Keyword            string
+	Name               string
+	NotificationGroups string
+	NotificationTypes  string
+	Position           int64
+	QuietHoursEnabled  bool
+	QuietHoursEnd      int64
+	QuietHoursStart    int64
+	Sender             string
+}
+
 type NotificationType struct {
 	ID   int64
 	Name string
@@ -447,3 +542,41 @@ type Scope struct {
 	ID   int64
 	Name string
 }
+
+type StructureTimer struct {
+	ID               int64
+	CreatedAt        time.Time
+	EveSolarSystemID int64
+	EveTypeID        int64
+	ExitsAt          time.Time
+	IsManual         bool
+	LastNotified     sql.NullTime
+	Notes            string
+	OwnerID          sql.NullInt64
+	StructureID      int64
+	StructureName    string
+	TimerType        string
+}
+
+type Webhook struct {
+	ID                 int64
+	CharacterIds       string
+	CreatedAt          time.Time
+	ForwardAlerts      bool
+	ForwardMails       bool
+	NotificationGroups string
+	NotificationTypes  string
+	IsEnabled          bool
+	Name               string
+	Url                string
+}
+
+type WebhookMessage struct {
+	ID            int64
+	Attempts      int64
+	CreatedAt     time.Time
+	LastError     string
+	NextAttemptAt time.Time
+	Payload       []byte
+	WebhookID     int64
+}
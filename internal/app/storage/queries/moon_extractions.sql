@@ -0,0 +1,129 @@
+-- name: CreateMoonExtractionOre :exec
+INSERT INTO
+    moon_extraction_ores (moon_extraction_id, eve_type_id, volume)
+VALUES
+    (?, ?, ?);
+
+-- name: DeleteMoonExtractionOres :exec
+DELETE FROM
+    moon_extraction_ores
+WHERE
+    moon_extraction_id = ?;
+
+-- name: GetMoonExtraction :one
+SELECT
+    sqlc.embed(me),
+    sqlc.embed(em),
+    sqlc.embed(ess),
+    sqlc.embed(ecs),
+    sqlc.embed(er),
+    sqlc.embed(ee)
+FROM
+    moon_extractions me
+    JOIN eve_moons em ON em.id = me.eve_moon_id
+    JOIN eve_solar_systems ess ON ess.id = em.eve_solar_system_id
+    JOIN eve_constellations ecs ON ecs.id = ess.eve_constellation_id
+    JOIN eve_regions er ON er.id = ecs.eve_region_id
+    JOIN eve_entities ee ON ee.id = me.corporation_id
+WHERE
+    me.structure_id = ?
+    AND me.auto_fracture_at = ?;
+
+-- name: ListMoonExtractions :many
+SELECT
+    sqlc.embed(me),
+    sqlc.embed(em),
+    sqlc.embed(ess),
+    sqlc.embed(ecs),
+    sqlc.embed(er),
+    sqlc.embed(ee)
+FROM
+    moon_extractions me
+    JOIN eve_moons em ON em.id = me.eve_moon_id
+    JOIN eve_solar_systems ess ON ess.id = em.eve_solar_system_id
+    JOIN eve_constellations ecs ON ecs.id = ess.eve_constellation_id
+    JOIN eve_regions er ON er.id = ecs.eve_region_id
+    JOIN eve_entities ee ON ee.id = me.corporation_id
+ORDER BY
+    me.chunk_arrival_at DESC;
+
+-- name: ListMoonExtractionsForStructure :many
+SELECT
+    sqlc.embed(me),
+    sqlc.embed(em),
+    sqlc.embed(ess),
+    sqlc.embed(ecs),
+    sqlc.embed(er),
+    sqlc.embed(ee)
+FROM
+    moon_extractions me
+    JOIN eve_moons em ON em.id = me.eve_moon_id
+    JOIN eve_solar_systems ess ON ess.id = em.eve_solar_system_id
+    JOIN eve_constellations ecs ON ecs.id = ess.eve_constellation_id
+    JOIN eve_regions er ON er.id = ecs.eve_region_id
+    JOIN eve_entities ee ON ee.id = me.corporation_id
+WHERE
+    me.structure_id = ?
+ORDER BY
+    me.chunk_arrival_at DESC;
+
+-- name: ListMoonExtractionOres :many
+SELECT
+    meo.eve_type_id,
+    et.name AS type_name,
+    et.volume AS type_volume,
+    meo.volume,
+    emp.average_price
+FROM
+    moon_extraction_ores meo
+    JOIN eve_types et ON et.id = meo.eve_type_id
+    LEFT JOIN eve_market_prices emp ON emp.type_id = meo.eve_type_id
+WHERE
+    meo.moon_extraction_id = ?
+ORDER BY
+    et.name;
+
+-- name: UpdateMoonExtractionLastNotified :exec
+UPDATE
+    moon_extractions
+SET
+    last_notified = ?
+WHERE
+    id = ?;
+
+-- name: UpdateMoonExtractionStatus :exec
+UPDATE
+    moon_extractions
+SET
+    status = ?,
+    updated_at = ?
+WHERE
+    id = ?;
+
+-- name: UpdateOrCreateMoonExtraction :one
+INSERT INTO
+    moon_extractions (
+        auto_fracture_at,
+        chunk_arrival_at,
+        corporation_id,
+        eve_moon_id,
+        started_at,
+        status,
+        structure_id,
+        structure_name,
+        updated_at
+    )
+VALUES
+    (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9) ON CONFLICT(structure_id, auto_fracture_at) DO
+UPDATE
+SET
+    chunk_arrival_at = ?2,
+    corporation_id = ?3,
+    eve_moon_id = ?4,
+    started_at = ?5,
+    status = CASE
+        WHEN moon_extractions.status IN ('', 'started') THEN ?6
+        ELSE moon_extractions.status
+    END,
+    structure_name = ?8,
+    updated_at = ?9 RETURNING id;
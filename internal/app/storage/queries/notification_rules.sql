@@ -0,0 +1,62 @@
+-- name: CreateNotificationRule :one
+INSERT INTO
+    notification_rules (
+        action,
+        channels,
+        character_ids,
+        is_enabled,
+        keyword,
+        name,
+        notification_groups,
+        notification_types,
+        position,
+        quiet_hours_enabled,
+        quiet_hours_end,
+        quiet_hours_start,
+        sender
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id;
+
+-- name: DeleteNotificationRule :exec
+DELETE FROM
+    notification_rules
+WHERE
+    id = ?;
+
+-- name: GetNotificationRule :one
+SELECT
+    *
+FROM
+    notification_rules
+WHERE
+    id = ?;
+
+-- name: ListNotificationRules :many
+SELECT
+    *
+FROM
+    notification_rules
+ORDER BY
+    position,
+    id;
+
+-- name: UpdateNotificationRule :exec
+UPDATE
+    notification_rules
+SET
+    action = ?,
+    channels = ?,
+    character_ids = ?,
+    is_enabled = ?,
+    keyword = ?,
+    name = ?,
+    notification_groups = ?,
+    notification_types = ?,
+    position = ?,
+    quiet_hours_enabled = ?,
+    quiet_hours_end = ?,
+    quiet_hours_start = ?,
+    sender = ?
+WHERE
+    id = ?;
@@ -0,0 +1,78 @@
+-- name: DeleteStructureTimer :exec
+DELETE FROM
+    structure_timers
+WHERE
+    id = ?;
+
+-- name: GetStructureTimer :one
+SELECT
+    sqlc.embed(stm),
+    sqlc.embed(ess),
+    sqlc.embed(ecs),
+    sqlc.embed(er),
+    et.name as structure_type_name,
+    owner.name as owner_name,
+    owner.category as owner_category
+FROM
+    structure_timers stm
+    JOIN eve_solar_systems ess ON ess.id = stm.eve_solar_system_id
+    JOIN eve_constellations ecs ON ecs.id = ess.eve_constellation_id
+    JOIN eve_regions er ON er.id = ecs.eve_region_id
+    JOIN eve_types et ON et.id = stm.eve_type_id
+    LEFT JOIN eve_entities AS owner ON owner.id = stm.owner_id
+WHERE
+    stm.id = ?;
+
+-- name: ListStructureTimers :many
+SELECT
+    sqlc.embed(stm),
+    sqlc.embed(ess),
+    sqlc.embed(ecs),
+    sqlc.embed(er),
+    et.name as structure_type_name,
+    owner.name as owner_name,
+    owner.category as owner_category
+FROM
+    structure_timers stm
+    JOIN eve_solar_systems ess ON ess.id = stm.eve_solar_system_id
+    JOIN eve_constellations ecs ON ecs.id = ess.eve_constellation_id
+    JOIN eve_regions er ON er.id = ecs.eve_region_id
+    JOIN eve_types et ON et.id = stm.eve_type_id
+    LEFT JOIN eve_entities AS owner ON owner.id = stm.owner_id
+ORDER BY
+    stm.exits_at;
+
+-- name: UpdateOrCreateStructureTimer :one
+INSERT INTO
+    structure_timers (
+        created_at,
+        eve_solar_system_id,
+        eve_type_id,
+        exits_at,
+        is_manual,
+        notes,
+        owner_id,
+        structure_id,
+        structure_name,
+        timer_type
+    )
+VALUES
+    (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10) ON CONFLICT(
+        eve_solar_system_id,
+        structure_id,
+        eve_type_id,
+        timer_type,
+        exits_at
+    ) DO
+UPDATE
+SET
+    owner_id = ?7,
+    structure_name = ?9 RETURNING id;
+
+-- name: UpdateStructureTimerLastNotified :exec
+UPDATE
+    structure_timers
+SET
+    last_notified = ?
+WHERE
+    id = ?;
@@ -0,0 +1,44 @@
+-- name: CountWebhookMessages :one
+SELECT
+    COUNT(*)
+FROM
+    webhook_messages;
+
+-- name: CreateWebhookMessage :exec
+INSERT INTO
+    webhook_messages (
+        attempts,
+        created_at,
+        last_error,
+        next_attempt_at,
+        payload,
+        webhook_id
+    )
+VALUES
+    (0, ?, '', ?, ?, ?);
+
+-- name: DeleteWebhookMessage :exec
+DELETE FROM
+    webhook_messages
+WHERE
+    id = ?;
+
+-- name: ListWebhookMessagesDue :many
+SELECT
+    *
+FROM
+    webhook_messages
+WHERE
+    next_attempt_at <= ?
+ORDER BY
+    id;
+
+-- name: UpdateWebhookMessageAttempt :exec
+UPDATE
+    webhook_messages
+SET
+    attempts = ?,
+    last_error = ?,
+    next_attempt_at = ?
+WHERE
+    id = ?;
@@ -0,0 +1,52 @@
+-- name: CreateWebhook :one
+INSERT INTO
+    webhooks (
+        character_ids,
+        created_at,
+        forward_alerts,
+        forward_mails,
+        notification_groups,
+        notification_types,
+        is_enabled,
+        name,
+        url
+    )
+VALUES
+    (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id;
+
+-- name: DeleteWebhook :exec
+DELETE FROM
+    webhooks
+WHERE
+    id = ?;
+
+-- name: GetWebhook :one
+SELECT
+    *
+FROM
+    webhooks
+WHERE
+    id = ?;
+
+-- name: ListWebhooks :many
+SELECT
+    *
+FROM
+    webhooks
+ORDER BY
+    name;
+
+-- name: UpdateWebhook :exec
+UPDATE
+    webhooks
+SET
+    character_ids = ?,
+    forward_alerts = ?,
+    forward_mails = ?,
+    notification_groups = ?,
+    notification_types = ?,
+    is_enabled = ?,
+    name = ?,
+    url = ?
+WHERE
+    id = ?;
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+// MaxSearchDataResults is the maximum number of results per kind returned from a data search.
+const MaxSearchDataResults = 250
+
+// searchIndex is a full text search index for columns of a table.
+type searchIndex struct {
+	name    string
+	source  string
+	columns []string
+}
+
+var searchIndexes = []searchIndex{
+	{name: "character_contracts_fts", source: "character_contracts", columns: []string{"title"}},
+	{name: "character_mails_fts", source: "character_mails", columns: []string{"subject", "body"}},
+	{name: "character_notifications_fts", source: "character_notifications", columns: []string{"title", "body"}},
+}
+
+// setupSearchIndexes creates missing full text search indexes.
+// Requires SQLite with FTS5, which is enabled with the build tag "sqlite_fts5".
+// Without FTS5 searches fall back to pattern matching.
+func setupSearchIndexes(db *sql.DB) error {
+	var hasFTS5 bool
+	if err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&hasFTS5); err != nil {
+		return err
+	}
+	if !hasFTS5 {
+		slog.Info("SQLite without FTS5. Full text search disabled")
+	}
+	return updateSearchIndexes(db, hasFTS5)
+}
+
+// updateSearchIndexes enables or disables the search indexes.
+//
+// The same database can be opened by builds with and without FTS5.
+// Without FTS5 the triggers of an index would break all writes to its source table,
+// so they are removed. The FTS5 tables themselves can not be dropped without FTS5 and are left unused.
+// When FTS5 is available again the triggers are recreated and the indexes are rebuilt.
+func updateSearchIndexes(db *sql.DB, hasFTS5 bool) error {
+	for _, idx := range searchIndexes {
+		if !hasFTS5 {
+			if err := disableSearchIndex(db, idx); err != nil {
+				return fmt.Errorf("disable search index %s: %w", idx.name, err)
+			}
+			continue
+		}
+		ok, err := hasSearchIndex(db, idx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			continue
+		}
+		if err := createSearchIndex(db, idx); err != nil {
+			return fmt.Errorf("create search index %s: %w", idx.name, err)
+		}
+		slog.Info("Created search index", "name", idx.name)
+	}
+	return nil
+}
+
+// hasSearchIndex reports whether a search index exists and is kept in sync by its triggers.
+func hasSearchIndex(db *sql.DB, idx searchIndex) (bool, error) {
+	ok, err := hasTable(db, idx.name)
+	if err != nil || !ok {
+		return false, err
+	}
+	var c int
+	err = db.QueryRow(
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN (?, ?, ?)",
+		idx.name+"_ai",
+		idx.name+"_ad",
+		idx.name+"_au",
+	).Scan(&c)
+	if err != nil {
+		return false, err
+	}
+	return c == 3, nil
+}
+
+func hasTable(db *sql.DB, name string) (bool, error) {
+	var c int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&c)
+	if err != nil {
+		return false, err
+	}
+	return c > 0, nil
+}
+
+// disableSearchIndex removes the triggers of a search index.
+func disableSearchIndex(db *sql.DB, idx searchIndex) error {
+	ok, err := hasTable(db, idx.name)
+	if err != nil || !ok {
+		return err
+	}
+	for _, s := range []string{"_ai", "_ad", "_au"} {
+		if _, err := db.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS %s%s;", idx.name, s)); err != nil {
+			return err
+		}
+	}
+	slog.Info("Disabled search index", "name", idx.name)
+	return nil
+}
+
+// createSearchIndex creates an external content FTS5 table for a search index if it does not exist,
+// adds triggers to keep it in sync with the source table and fills it with existing data.
+func createSearchIndex(db *sql.DB, idx searchIndex) error {
+	cols := strings.Join(idx.columns, ", ")
+	var newCols, oldCols []string
+	for _, c := range idx.columns {
+		newCols = append(newCols, "new."+c)
+		oldCols = append(oldCols, "old."+c)
+	}
+	insertNew := fmt.Sprintf("INSERT INTO %s(rowid, %s) VALUES (new.id, %s);", idx.name, cols, strings.Join(newCols, ", "))
+	deleteOld := fmt.Sprintf("INSERT INTO %[1]s(%[1]s, rowid, %[2]s) VALUES ('delete', old.id, %[3]s);", idx.name, cols, strings.Join(oldCols, ", "))
+	statements := []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content='%s', content_rowid='id');", idx.name, cols, idx.source),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s_ai;", idx.name),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s_ad;", idx.name),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s_au;", idx.name),
+		fmt.Sprintf("CREATE TRIGGER %s_ai AFTER INSERT ON %s BEGIN %s END;", idx.name, idx.source, insertNew),
+		fmt.Sprintf("CREATE TRIGGER %s_ad AFTER DELETE ON %s BEGIN %s END;", idx.name, idx.source, deleteOld),
+		fmt.Sprintf("CREATE TRIGGER %s_au AFTER UPDATE OF %s ON %s BEGIN %s %s END;", idx.name, cols, idx.source, deleteOld, insertNew),
+		fmt.Sprintf("INSERT INTO %[1]s(%[1]s) VALUES ('rebuild');", idx.name),
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, s := range statements {
+		if _, err := tx.Exec(s); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SearchData returns mails, notifications and contracts of all characters matching a search query.
+// All words of the query must match. Words match as prefix, e.g. "doc" matches "doctrine".
+// Results are ordered by timestamp with the newest first.
+func (st *Storage) SearchData(ctx context.Context, query string) ([]*app.DataSearchResult, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return []*app.DataSearchResult{}, nil
+	}
+	type search struct {
+		kind  app.DataSearchKind
+		index searchIndex
+		query string // must return: character ID, item ID, timestamp, title, notification type
+	}
+	searches := []search{
+		{
+			kind:  app.DataSearchContract,
+			index: searchIndexes[0],
+			query: `
+				SELECT s.character_id, s.contract_id, s.date_issued, s.title, ''
+				FROM character_contracts s`,
+		},
+		{
+			kind:  app.DataSearchMail,
+			index: searchIndexes[1],
+			query: `
+				SELECT s.character_id, s.mail_id, s.timestamp, s.subject, ''
+				FROM character_mails s`,
+		},
+		{
+			kind:  app.DataSearchNotification,
+			index: searchIndexes[2],
+			query: `
+				SELECT s.character_id, s.notification_id, s.timestamp, s.title, nt.name
+				FROM character_notifications s
+				JOIN notification_types nt ON nt.id = s.type_id`,
+		},
+	}
+	results := make([]*app.DataSearchResult, 0)
+	for _, x := range searches {
+		hasIndex, err := hasSearchIndex(st.dbRO, x.index)
+		if err != nil {
+			return nil, fmt.Errorf("search data: %w", err)
+		}
+		var q string
+		var args []any
+		if hasIndex {
+			q = fmt.Sprintf("%s JOIN %[2]s f ON f.rowid = s.id WHERE %[2]s MATCH ? ORDER BY rank LIMIT ?", x.query, x.index.name)
+			args = []any{makeFTSQuery(words), MaxSearchDataResults}
+		} else {
+			q, args = makeLikeSearch(x.query, x.index.columns, words)
+		}
+		rows, err := st.dbRO.QueryContext(ctx, q, args...)
+		if err != nil {
+			return nil, fmt.Errorf("search data for %s: %w", x.kind, err)
+		}
+		for rows.Next() {
+			r := &app.DataSearchResult{Kind: x.kind}
+			var title sql.NullString
+			var type_ string
+			if err := rows.Scan(&r.CharacterID, &r.ItemID, &r.Timestamp, &title, &type_); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("search data for %s: %w", x.kind, err)
+			}
+			if title.Valid {
+				r.Title = title.String
+			} else {
+				n := app.CharacterNotification{Type: type_}
+				r.Title = n.TitleFake()
+			}
+			results = append(results, r)
+		}
+		if err := rows.Close(); err != nil {
+			return nil, err
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortStableFunc(results, func(a, b *app.DataSearchResult) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+	return results, nil
+}
+
+// makeFTSQuery returns a FTS5 query which matches all words as prefix.
+// Words are quoted so that FTS5 syntax in the user's query is matched literally.
+func makeFTSQuery(words []string) string {
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"*`
+	}
+	return strings.Join(terms, " ")
+}
+
+// makeLikeSearch returns a query and its arguments,
+// which matches all words with any of the columns.
+// This is used as fallback when no search index exists.
+func makeLikeSearch(query string, columns []string, words []string) (string, []any) {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	var conditions []string
+	var args []any
+	for _, w := range words {
+		var cc []string
+		for _, c := range columns {
+			cc = append(cc, fmt.Sprintf(`s.%s LIKE ? ESCAPE '\'`, c))
+			args = append(args, "%"+r.Replace(w)+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(cc, " OR ")+")")
+	}
+	args = append(args, MaxSearchDataResults)
+	q := fmt.Sprintf("%s WHERE %s ORDER BY s.id DESC LIMIT ?", query, strings.Join(conditions, " AND "))
+	return q, args
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateSearchIndexes(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file::memory:?_fk=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if err := ApplyMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+	var hasFTS5 bool
+	if err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&hasFTS5); err != nil {
+		t.Fatal(err)
+	}
+	if !hasFTS5 {
+		t.Skip("requires SQLite with FTS5")
+	}
+	idx := searchIndexes[1]
+	t.Run("should remove triggers when FTS5 is not available", func(t *testing.T) {
+		// when
+		err := updateSearchIndexes(db, false)
+		// then
+		if assert.NoError(t, err) {
+			ok, err := hasSearchIndex(db, idx)
+			if assert.NoError(t, err) {
+				assert.False(t, ok)
+			}
+			var c int
+			if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger'").Scan(&c); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, 0, c)
+		}
+	})
+	t.Run("should restore triggers when FTS5 is available again", func(t *testing.T) {
+		// when
+		err := updateSearchIndexes(db, true)
+		// then
+		if assert.NoError(t, err) {
+			for _, idx := range searchIndexes {
+				ok, err := hasSearchIndex(db, idx)
+				if assert.NoError(t, err) {
+					assert.True(t, ok)
+				}
+			}
+		}
+	})
+}
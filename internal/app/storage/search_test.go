@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+func TestSearchData(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can find mails, notifications and contracts", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		c := factory.CreateCharacter()
+		m := factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			Subject:     "New doctrine",
+			Body:        "Please find the new fits attached.",
+			Timestamp:   time.Now().Add(-3 * time.Hour),
+		})
+		factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			CharacterID: c.ID,
+			Subject:     "Hello",
+			Body:        "Nothing to see here.",
+		})
+		n := factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			CharacterID: c.ID,
+			Title:       optional.New("Doctrine ships delivered"),
+			Body:        optional.New("Your fits have arrived."),
+			Timestamp:   time.Now().Add(-2 * time.Hour),
+		})
+		o := factory.CreateCharacterContract(storage.CreateCharacterContractParams{
+			CharacterID: c.ID,
+			Title:       "Doctrine fits",
+			DateIssued:  time.Now().Add(-1 * time.Hour),
+		})
+		// when
+		got, err := st.SearchData(ctx, "doctrine FIT")
+		// then
+		if assert.NoError(t, err) {
+			assert.Len(t, got, 3)
+			assert.Equal(t, app.DataSearchContract, got[0].Kind)
+			assert.Equal(t, int64(o.ContractID), got[0].ItemID)
+			assert.Equal(t, "Doctrine fits", got[0].Title)
+			assert.Equal(t, app.DataSearchNotification, got[1].Kind)
+			assert.Equal(t, n.NotificationID, got[1].ItemID)
+			assert.Equal(t, app.DataSearchMail, got[2].Kind)
+			assert.Equal(t, int64(m.MailID), got[2].ItemID)
+			assert.Equal(t, c.ID, got[2].CharacterID)
+		}
+	})
+	t.Run("should return title from type when notification has no title", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		factory.CreateCharacterNotification(storage.CreateCharacterNotificationParams{
+			Body: optional.New("Alpha"),
+			Type: "StructureUnderAttack",
+		})
+		// when
+		got, err := st.SearchData(ctx, "alpha")
+		// then
+		if assert.NoError(t, err) {
+			if assert.Len(t, got, 1) {
+				assert.Equal(t, "Structure Under Attack", got[0].Title)
+			}
+		}
+	})
+	t.Run("should find nothing when not all words match", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			Subject: "New doctrine",
+			Body:    "Alpha",
+		})
+		// when
+		got, err := st.SearchData(ctx, "doctrine bravo")
+		// then
+		if assert.NoError(t, err) {
+			assert.Len(t, got, 0)
+		}
+	})
+	t.Run("should treat special characters literally", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		factory.CreateCharacterMail(storage.CreateCharacterMailParams{
+			Subject: "Alpha",
+			Body:    "Bravo",
+		})
+		// when
+		got, err := st.SearchData(ctx, `"al%" OR *`)
+		// then
+		if assert.NoError(t, err) {
+			assert.Len(t, got, 0)
+		}
+	})
+	t.Run("should return nothing for empty query", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		factory.CreateCharacterMail()
+		// when
+		got, err := st.SearchData(ctx, "  ")
+		// then
+		if assert.NoError(t, err) {
+			assert.Len(t, got, 0)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+// setToDBValue returns a set as sorted JSON array for storing it in a text column.
+func setToDBValue[T cmp.Ordered](s set.Set[T]) (string, error) {
+	x := s.ToSlice()
+	if x == nil {
+		x = []T{}
+	}
+	slices.Sort(x)
+	b, err := json.Marshal(x)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// setFromDBValue returns a set from a JSON array stored in a text column.
+func setFromDBValue[T comparable](data string) (set.Set[T], error) {
+	var x []T
+	if err := json.Unmarshal([]byte(data), &x); err != nil {
+		return nil, err
+	}
+	return set.NewFromSlice(x), nil
+}
+
+// notificationGroupsToDBValue returns notification groups for storing them in a text column.
+// Groups are stored by name, so they remain valid when new groups are added.
+func notificationGroupsToDBValue(groups set.Set[app.NotificationGroup]) (string, error) {
+	names := set.New[string]()
+	for g := range groups.Values() {
+		names.Add(g.String())
+	}
+	return setToDBValue(names)
+}
+
+// notificationGroupsFromDBValue returns notification groups from a text column.
+// Groups which no longer exist are ignored.
+func notificationGroupsFromDBValue(data string) (set.Set[app.NotificationGroup], error) {
+	names, err := setFromDBValue[string](data)
+	if err != nil {
+		return nil, err
+	}
+	name2group := make(map[string]app.NotificationGroup)
+	for _, g := range app.NotificationGroups() {
+		name2group[g.String()] = g
+	}
+	groups := set.New[app.NotificationGroup]()
+	for n := range names.Values() {
+		g, ok := name2group[n]
+		if !ok {
+			continue
+		}
+		groups.Add(g)
+	}
+	return groups, nil
+}
@@ -10,3 +10,11 @@ func convertNumericSlice[Y constraints.Integer, X constraints.Integer](s []X) []
 	}
 	return s2
 }
+
+// nonNilSlice returns an empty slice instead of nil, e.g. for encoding it as JSON array.
+func nonNilSlice[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -62,7 +62,10 @@ func InitDB(dsn string) (dbRW *sql.DB, dbRO *sql.DB, err error) {
 }
 
 func ApplyMigrations(db *sql.DB) error {
-	return migrate.Run(db, embedMigrations)
+	if err := migrate.Run(db, embedMigrations); err != nil {
+		return err
+	}
+	return setupSearchIndexes(db)
 }
 
 func sqliteDSN(dsn string, isReadonly bool) string {
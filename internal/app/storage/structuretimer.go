@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+var structureTimerTypeFromDBValue = map[string]app.StructureTimerType{
+	"":            app.StructureTimerTypeUndefined,
+	"armor":       app.StructureTimerTypeArmor,
+	"hull":        app.StructureTimerTypeHull,
+	"reinforced":  app.StructureTimerTypeReinforced,
+	"sovereignty": app.StructureTimerTypeSovereignty,
+}
+
+var structureTimerTypeToDBValue = map[app.StructureTimerType]string{}
+
+func init() {
+	for k, v := range structureTimerTypeFromDBValue {
+		structureTimerTypeToDBValue[v] = k
+	}
+}
+
+func (st *Storage) DeleteStructureTimer(ctx context.Context, id int64) error {
+	if err := st.qRW.DeleteStructureTimer(ctx, id); err != nil {
+		return fmt.Errorf("delete structure timer %d: %w", id, err)
+	}
+	return nil
+}
+
+func (st *Storage) GetStructureTimer(ctx context.Context, id int64) (*app.StructureTimer, error) {
+	r, err := st.qRO.GetStructureTimer(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = app.ErrNotFound
+		}
+		return nil, fmt.Errorf("get structure timer %d: %w", id, err)
+	}
+	return structureTimerFromDBModel(queries.ListStructureTimersRow(r)), nil
+}
+
+// ListStructureTimers returns all structure timers ordered by exit time.
+func (st *Storage) ListStructureTimers(ctx context.Context) ([]*app.StructureTimer, error) {
+	rows, err := st.qRO.ListStructureTimers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list structure timers: %w", err)
+	}
+	oo := make([]*app.StructureTimer, len(rows))
+	for i, r := range rows {
+		oo[i] = structureTimerFromDBModel(r)
+	}
+	return oo, nil
+}
+
+func structureTimerFromDBModel(r queries.ListStructureTimersRow) *app.StructureTimer {
+	o := &app.StructureTimer{
+		ID:           r.StructureTimer.ID,
+		CreatedAt:    r.StructureTimer.CreatedAt,
+		ExitsAt:      r.StructureTimer.ExitsAt,
+		IsManual:     r.StructureTimer.IsManual,
+		LastNotified: optional.FromNullTime(r.StructureTimer.LastNotified),
+		Notes:        r.StructureTimer.Notes,
+		Owner: eveEntityFromNullableDBModel(nullEveEntry{
+			ID:       r.StructureTimer.OwnerID,
+			Name:     r.OwnerName,
+			Category: r.OwnerCategory,
+		}),
+		SolarSystem:   eveSolarSystemFromDBModel(r.EveSolarSystem, r.EveConstellation, r.EveRegion),
+		StructureID:   r.StructureTimer.StructureID,
+		StructureName: r.StructureTimer.StructureName,
+		StructureType: &app.EntityShort[int32]{
+			ID:   int32(r.StructureTimer.EveTypeID),
+			Name: r.StructureTypeName,
+		},
+		TimerType: structureTimerTypeFromDBValue[r.StructureTimer.TimerType],
+	}
+	return o
+}
+
+func (st *Storage) UpdateStructureTimerLastNotified(ctx context.Context, id int64, lastNotified time.Time) error {
+	arg := queries.UpdateStructureTimerLastNotifiedParams{
+		ID:           id,
+		LastNotified: NewNullTimeFromTime(lastNotified),
+	}
+	if err := st.qRW.UpdateStructureTimerLastNotified(ctx, arg); err != nil {
+		return fmt.Errorf("update structure timer last notified: %+v: %w", arg, err)
+	}
+	return nil
+}
+
+type UpdateOrCreateStructureTimerParams struct {
+	ExitsAt         time.Time
+	IsManual        bool
+	Notes           string
+	OwnerID         int32 // optional
+	SolarSystemID   int32
+	StructureID     int64 // optional
+	StructureName   string
+	StructureTypeID int32
+	TimerType       app.StructureTimerType
+}
+
+func (arg UpdateOrCreateStructureTimerParams) isValid() bool {
+	return arg.SolarSystemID != 0 &&
+		arg.StructureTypeID != 0 &&
+		!arg.ExitsAt.IsZero() &&
+		arg.TimerType != app.StructureTimerTypeUndefined
+}
+
+// UpdateOrCreateStructureTimer updates or creates a structure timer and returns it's ID.
+// A timer is identified by it's location, structure, type and exit time,
+// so that the same timer reported by several characters is only stored once.
+func (st *Storage) UpdateOrCreateStructureTimer(ctx context.Context, arg UpdateOrCreateStructureTimerParams) (int64, error) {
+	if !arg.isValid() {
+		return 0, fmt.Errorf("UpdateOrCreateStructureTimer: %+v: %w", arg, app.ErrInvalid)
+	}
+	arg2 := queries.UpdateOrCreateStructureTimerParams{
+		CreatedAt:        time.Now().UTC(),
+		EveSolarSystemID: int64(arg.SolarSystemID),
+		EveTypeID:        int64(arg.StructureTypeID),
+		ExitsAt:          arg.ExitsAt.UTC(),
+		IsManual:         arg.IsManual,
+		Notes:            arg.Notes,
+		StructureID:      arg.StructureID,
+		StructureName:    arg.StructureName,
+		TimerType:        structureTimerTypeToDBValue[arg.TimerType],
+	}
+	if arg.OwnerID != 0 {
+		arg2.OwnerID = NewNullInt64(int64(arg.OwnerID))
+	}
+	id, err := st.qRW.UpdateOrCreateStructureTimer(ctx, arg2)
+	if err != nil {
+		return 0, fmt.Errorf("update or create structure timer: %+v: %w", arg, err)
+	}
+	return id, nil
+}
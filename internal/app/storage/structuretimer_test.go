@@ -0,0 +1,164 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructureTimer(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can create new", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		system := factory.CreateEveSolarSystem()
+		structureType := factory.CreateEveType()
+		owner := factory.CreateEveEntityCorporation()
+		exitsAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+		arg := storage.UpdateOrCreateStructureTimerParams{
+			ExitsAt:         exitsAt,
+			Notes:           "notes",
+			OwnerID:         owner.ID,
+			SolarSystemID:   system.ID,
+			StructureID:     1_000_000_000_001,
+			StructureName:   "Alpha",
+			StructureTypeID: structureType.ID,
+			TimerType:       app.StructureTimerTypeHull,
+		}
+		// when
+		id, err := st.UpdateOrCreateStructureTimer(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetStructureTimer(ctx, id)
+			if assert.NoError(t, err) {
+				assert.Equal(t, exitsAt, o.ExitsAt)
+				assert.False(t, o.IsManual)
+				assert.Equal(t, "notes", o.Notes)
+				assert.Equal(t, owner, o.Owner)
+				assert.Equal(t, system, o.SolarSystem)
+				assert.Equal(t, int64(1_000_000_000_001), o.StructureID)
+				assert.Equal(t, "Alpha", o.StructureName)
+				assert.Equal(t, structureType.ID, o.StructureType.ID)
+				assert.Equal(t, structureType.Name, o.StructureType.Name)
+				assert.Equal(t, app.StructureTimerTypeHull, o.TimerType)
+			}
+		}
+	})
+	t.Run("can create new without owner", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		system := factory.CreateEveSolarSystem()
+		structureType := factory.CreateEveType()
+		arg := storage.UpdateOrCreateStructureTimerParams{
+			ExitsAt:         time.Now().Add(time.Hour),
+			IsManual:        true,
+			SolarSystemID:   system.ID,
+			StructureTypeID: structureType.ID,
+			TimerType:       app.StructureTimerTypeReinforced,
+		}
+		// when
+		id, err := st.UpdateOrCreateStructureTimer(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetStructureTimer(ctx, id)
+			if assert.NoError(t, err) {
+				assert.Nil(t, o.Owner)
+				assert.True(t, o.IsManual)
+			}
+		}
+	})
+	t.Run("should store same timer only once", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o1 := factory.CreateStructureTimer()
+		arg := storage.UpdateOrCreateStructureTimerParams{
+			ExitsAt:         o1.ExitsAt,
+			OwnerID:         o1.Owner.ID,
+			SolarSystemID:   o1.SolarSystem.ID,
+			StructureID:     o1.StructureID,
+			StructureName:   "Bravo",
+			StructureTypeID: o1.StructureType.ID,
+			TimerType:       o1.TimerType,
+		}
+		// when
+		id, err := st.UpdateOrCreateStructureTimer(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, o1.ID, id)
+			oo, err := st.ListStructureTimers(ctx)
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 1)
+				assert.Equal(t, "Bravo", oo[0].StructureName)
+			}
+		}
+	})
+	t.Run("should return error when params invalid", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		// when
+		_, err := st.UpdateOrCreateStructureTimer(ctx, storage.UpdateOrCreateStructureTimerParams{})
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("should return not found error", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		// when
+		_, err := st.GetStructureTimer(ctx, 42)
+		// then
+		assert.ErrorIs(t, err, app.ErrNotFound)
+	})
+	t.Run("can list timers ordered by exit time", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		now := time.Now().UTC()
+		o1 := factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{ExitsAt: now.Add(3 * time.Hour)})
+		o2 := factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{ExitsAt: now.Add(1 * time.Hour)})
+		o3 := factory.CreateStructureTimer(storage.UpdateOrCreateStructureTimerParams{ExitsAt: now.Add(2 * time.Hour)})
+		// when
+		oo, err := st.ListStructureTimers(ctx)
+		// then
+		if assert.NoError(t, err) {
+			got := make([]int64, 0)
+			for _, o := range oo {
+				got = append(got, o.ID)
+			}
+			assert.Equal(t, []int64{o2.ID, o3.ID, o1.ID}, got)
+		}
+	})
+	t.Run("can delete", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o := factory.CreateStructureTimer()
+		// when
+		err := st.DeleteStructureTimer(ctx, o.ID)
+		// then
+		if assert.NoError(t, err) {
+			_, err := st.GetStructureTimer(ctx, o.ID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+		}
+	})
+	t.Run("can update last notified", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		o := factory.CreateStructureTimer()
+		lastNotified := time.Now().UTC().Truncate(time.Second)
+		// when
+		err := st.UpdateStructureTimerLastNotified(ctx, o.ID, lastNotified)
+		// then
+		if assert.NoError(t, err) {
+			o2, err := st.GetStructureTimer(ctx, o.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, optional.New(lastNotified), o2.LastNotified)
+			}
+		}
+	})
+}
@@ -449,6 +449,40 @@ func (f Factory) CreateCharacterMail(args ...storage.CreateCharacterMailParams)
 	return mail
 }
 
+func (f Factory) CreateCharacterMailDraft(args ...storage.UpdateOrCreateCharacterMailDraftParams) *app.CharacterMailDraft {
+	var arg storage.UpdateOrCreateCharacterMailDraftParams
+	ctx := context.TODO()
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	if arg.CharacterID == 0 {
+		c := f.CreateCharacter()
+		arg.CharacterID = c.ID
+	}
+	if arg.Body == "" {
+		arg.Body = fake.Paragraph()
+	}
+	if arg.Subject == "" {
+		arg.Subject = fake.Sentence()
+	}
+	if arg.Status == app.MailDraftUndefined {
+		arg.Status = app.MailDraftSaved
+	}
+	if len(arg.RecipientIDs) == 0 {
+		e1 := f.CreateEveEntityCharacter()
+		arg.RecipientIDs = []int32{e1.ID}
+	}
+	id, err := f.st.CreateCharacterMailDraft(ctx, arg)
+	if err != nil {
+		panic(err)
+	}
+	o, err := f.st.GetCharacterMailDraft(ctx, arg.CharacterID, id)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
 func (f Factory) CreateCharacterMailLabel(args ...app.CharacterMailLabel) *app.CharacterMailLabel {
 	ctx := context.TODO()
 	var arg storage.MailLabelParams
@@ -505,6 +539,30 @@ func (f Factory) CreateCharacterMailList(characterID int32, args ...app.EveEntit
 	return &e
 }
 
+func (f Factory) CreateCharacterMailRule(args ...storage.UpdateOrCreateCharacterMailRuleParams) *app.CharacterMailRule {
+	var arg storage.UpdateOrCreateCharacterMailRuleParams
+	ctx := context.TODO()
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	if arg.CharacterID == 0 {
+		c := f.CreateCharacter()
+		arg.CharacterID = c.ID
+	}
+	if arg.Name == "" {
+		arg.Name = fake.Color()
+	}
+	id, err := f.st.CreateCharacterMailRule(ctx, arg)
+	if err != nil {
+		panic(err)
+	}
+	o, err := f.st.GetCharacterMailRule(ctx, arg.CharacterID, id)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
 func (f Factory) CreateCharacterPlanet(args ...storage.CreateCharacterPlanetParams) *app.CharacterPlanet {
 	ctx := context.TODO()
 	var arg storage.CreateCharacterPlanetParams
@@ -1493,6 +1551,143 @@ func (f Factory) CreateEveMarketPrice(args ...storage.UpdateOrCreateEveMarketPri
 	return o
 }
 
+func (f Factory) CreateMoonExtraction(args ...storage.UpdateOrCreateMoonExtractionParams) *app.MoonExtraction {
+	var arg storage.UpdateOrCreateMoonExtractionParams
+	ctx := context.TODO()
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	if arg.CorporationID == 0 {
+		x := f.CreateEveEntityCorporation()
+		arg.CorporationID = x.ID
+	}
+	if arg.MoonID == 0 {
+		x := f.CreateEveMoon()
+		arg.MoonID = x.ID
+	}
+	if arg.StructureID == 0 {
+		arg.StructureID = f.calcNewID("moon_extractions", "structure_id", startIDStructure)
+	}
+	if arg.StructureName == "" {
+		arg.StructureName = fake.Color() + " Refinery"
+	}
+	if arg.ChunkArrivalAt.IsZero() {
+		arg.ChunkArrivalAt = time.Now().Add(time.Duration(rand.IntN(14*24)) * time.Hour).UTC()
+	}
+	if arg.AutoFractureAt.IsZero() {
+		arg.AutoFractureAt = arg.ChunkArrivalAt.Add(3 * time.Hour)
+	}
+	if arg.StartedAt.IsEmpty() {
+		arg.StartedAt = optional.New(arg.ChunkArrivalAt.Add(-14 * 24 * time.Hour))
+	}
+	if arg.Status == app.MoonExtractionStatusUndefined {
+		arg.Status = app.MoonExtractionStatusStarted
+	}
+	if arg.Ores == nil {
+		arg.Ores = make(map[int32]float64)
+		for range 3 {
+			et := f.CreateEveType()
+			arg.Ores[et.ID] = float64(rand.IntN(1_000_000))
+		}
+	}
+	_, err := f.st.UpdateOrCreateMoonExtraction(ctx, arg)
+	if err != nil {
+		panic(err)
+	}
+	o, err := f.st.GetMoonExtraction(ctx, arg.StructureID, arg.AutoFractureAt)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+func (f Factory) CreateStructureTimer(args ...storage.UpdateOrCreateStructureTimerParams) *app.StructureTimer {
+	var arg storage.UpdateOrCreateStructureTimerParams
+	ctx := context.TODO()
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	if arg.SolarSystemID == 0 {
+		x := f.CreateEveSolarSystem()
+		arg.SolarSystemID = x.ID
+	}
+	if arg.StructureTypeID == 0 {
+		x := f.CreateEveType()
+		arg.StructureTypeID = x.ID
+	}
+	if arg.StructureID == 0 && !arg.IsManual {
+		arg.StructureID = f.calcNewID("structure_timers", "structure_id", startIDStructure)
+	}
+	if arg.StructureName == "" {
+		arg.StructureName = fake.Color()
+	}
+	if arg.OwnerID == 0 {
+		x := f.CreateEveEntityCorporation()
+		arg.OwnerID = x.ID
+	}
+	if arg.ExitsAt.IsZero() {
+		arg.ExitsAt = time.Now().Add(time.Duration(rand.IntN(48*60)+1) * time.Minute).UTC()
+	}
+	if arg.TimerType == app.StructureTimerTypeUndefined {
+		arg.TimerType = app.StructureTimerTypeArmor
+	}
+	id, err := f.st.UpdateOrCreateStructureTimer(ctx, arg)
+	if err != nil {
+		panic(err)
+	}
+	o, err := f.st.GetStructureTimer(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+func (f Factory) CreateNotificationRule(args ...storage.UpdateOrCreateNotificationRuleParams) *app.NotificationRule {
+	var arg storage.UpdateOrCreateNotificationRuleParams
+	ctx := context.TODO()
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	if arg.Name == "" {
+		arg.Name = fake.Color()
+	}
+	if arg.Action == app.RuleActionUndefined {
+		arg.Action = app.RuleActionRoute
+	}
+	id, err := f.st.CreateNotificationRule(ctx, arg)
+	if err != nil {
+		panic(err)
+	}
+	o, err := f.st.GetNotificationRule(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+func (f Factory) CreateWebhook(args ...storage.UpdateOrCreateWebhookParams) *app.Webhook {
+	var arg storage.UpdateOrCreateWebhookParams
+	ctx := context.TODO()
+	if len(args) > 0 {
+		arg = args[0]
+	}
+	if arg.Name == "" {
+		arg.Name = fake.Color()
+	}
+	if arg.URL == "" {
+		arg.URL = fmt.Sprintf("https://discord.com/api/webhooks/%d/%s", rand.IntN(1_000_000), fake.CharactersN(16))
+	}
+	id, err := f.st.CreateWebhook(ctx, arg)
+	if err != nil {
+		panic(err)
+	}
+	o, err := f.st.GetWebhook(ctx, id)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
 func (f *Factory) calcNewID(table, id_field string, start int64) int64 {
 	if start < 1 {
 		panic("start must be a positive number")
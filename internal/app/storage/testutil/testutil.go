@@ -47,7 +47,8 @@ func TruncateTables(dbRW *sql.DB) {
 	if err != nil {
 		panic(err)
 	}
-	sql := `SELECT name FROM sqlite_master WHERE type = "table"`
+	// search indexes are kept in sync by triggers and must not be purged directly
+	sql := `SELECT name FROM sqlite_master WHERE type = "table" AND name NOT LIKE '%\_fts%' ESCAPE '\'`
 	rows, err := dbRW.Query(sql)
 	if err != nil {
 		panic(err)
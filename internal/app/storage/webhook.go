@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/queries"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+type UpdateOrCreateWebhookParams struct {
+	CharacterIDs  set.Set[int32]
+	ForwardAlerts bool
+	ForwardMails  bool
+	Groups        set.Set[app.NotificationGroup]
+	IsEnabled     bool
+	Name          string
+	Types         set.Set[string]
+	URL           string
+}
+
+func (arg UpdateOrCreateWebhookParams) isValid() bool {
+	return arg.Name != "" && arg.URL != ""
+}
+
+// CreateWebhook creates a new webhook and returns it's ID.
+func (st *Storage) CreateWebhook(ctx context.Context, arg UpdateOrCreateWebhookParams) (int64, error) {
+	if !arg.isValid() {
+		return 0, fmt.Errorf("CreateWebhook: %+v: %w", arg, app.ErrInvalid)
+	}
+	characterIDs, groups, types, err := webhookFiltersToDBValues(arg)
+	if err != nil {
+		return 0, fmt.Errorf("create webhook: %+v: %w", arg, err)
+	}
+	arg2 := queries.CreateWebhookParams{
+		CharacterIds:       characterIDs,
+		CreatedAt:          time.Now().UTC(),
+		ForwardAlerts:      arg.ForwardAlerts,
+		ForwardMails:       arg.ForwardMails,
+		NotificationGroups: groups,
+		NotificationTypes:  types,
+		IsEnabled:          arg.IsEnabled,
+		Name:               arg.Name,
+		Url:                arg.URL,
+	}
+	id, err := st.qRW.CreateWebhook(ctx, arg2)
+	if err != nil {
+		return 0, fmt.Errorf("create webhook: %+v: %w", arg, err)
+	}
+	return id, nil
+}
+
+func (st *Storage) DeleteWebhook(ctx context.Context, id int64) error {
+	if err := st.qRW.DeleteWebhook(ctx, id); err != nil {
+		return fmt.Errorf("delete webhook %d: %w", id, err)
+	}
+	return nil
+}
+
+func (st *Storage) GetWebhook(ctx context.Context, id int64) (*app.Webhook, error) {
+	r, err := st.qRO.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = app.ErrNotFound
+		}
+		return nil, fmt.Errorf("get webhook %d: %w", id, err)
+	}
+	o, err := webhookFromDBModel(r)
+	if err != nil {
+		return nil, fmt.Errorf("get webhook %d: %w", id, err)
+	}
+	return o, nil
+}
+
+// ListWebhooks returns all webhooks ordered by name.
+func (st *Storage) ListWebhooks(ctx context.Context) ([]*app.Webhook, error) {
+	rows, err := st.qRO.ListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	oo := make([]*app.Webhook, len(rows))
+	for i, r := range rows {
+		o, err := webhookFromDBModel(r)
+		if err != nil {
+			return nil, fmt.Errorf("list webhooks: %w", err)
+		}
+		oo[i] = o
+	}
+	return oo, nil
+}
+
+func (st *Storage) UpdateWebhook(ctx context.Context, id int64, arg UpdateOrCreateWebhookParams) error {
+	if !arg.isValid() {
+		return fmt.Errorf("UpdateWebhook: %+v: %w", arg, app.ErrInvalid)
+	}
+	characterIDs, groups, types, err := webhookFiltersToDBValues(arg)
+	if err != nil {
+		return fmt.Errorf("update webhook %d: %w", id, err)
+	}
+	arg2 := queries.UpdateWebhookParams{
+		CharacterIds:       characterIDs,
+		ForwardAlerts:      arg.ForwardAlerts,
+		ForwardMails:       arg.ForwardMails,
+		NotificationGroups: groups,
+		NotificationTypes:  types,
+		IsEnabled:          arg.IsEnabled,
+		Name:               arg.Name,
+		Url:                arg.URL,
+		ID:                 id,
+	}
+	if err := st.qRW.UpdateWebhook(ctx, arg2); err != nil {
+		return fmt.Errorf("update webhook %d: %+v: %w", id, arg, err)
+	}
+	return nil
+}
+
+// webhookFiltersToDBValues returns the filters of a webhook as JSON encoded values.
+func webhookFiltersToDBValues(arg UpdateOrCreateWebhookParams) (string, string, string, error) {
+	characterIDs, err := setToDBValue(arg.CharacterIDs)
+	if err != nil {
+		return "", "", "", err
+	}
+	groups, err := notificationGroupsToDBValue(arg.Groups)
+	if err != nil {
+		return "", "", "", err
+	}
+	types, err := setToDBValue(arg.Types)
+	if err != nil {
+		return "", "", "", err
+	}
+	return characterIDs, groups, types, nil
+}
+
+func webhookFromDBModel(r queries.Webhook) (*app.Webhook, error) {
+	characterIDs, err := setFromDBValue[int32](r.CharacterIds)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := notificationGroupsFromDBValue(r.NotificationGroups)
+	if err != nil {
+		return nil, err
+	}
+	types, err := setFromDBValue[string](r.NotificationTypes)
+	if err != nil {
+		return nil, err
+	}
+	o := &app.Webhook{
+		ID:            r.ID,
+		CharacterIDs:  characterIDs,
+		CreatedAt:     r.CreatedAt,
+		ForwardAlerts: r.ForwardAlerts,
+		ForwardMails:  r.ForwardMails,
+		Groups:        groups,
+		IsEnabled:     r.IsEnabled,
+		Name:          r.Name,
+		Types:         types,
+		URL:           r.Url,
+	}
+	return o, nil
+}
+
+func (st *Storage) CountWebhookMessages(ctx context.Context) (int, error) {
+	n, err := st.qRO.CountWebhookMessages(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("count webhook messages: %w", err)
+	}
+	return int(n), nil
+}
+
+type CreateWebhookMessageParams struct {
+	Payload   []byte
+	WebhookID int64
+}
+
+// CreateWebhookMessage adds a new message to the outbox. It is due immediately.
+func (st *Storage) CreateWebhookMessage(ctx context.Context, arg CreateWebhookMessageParams) error {
+	if arg.WebhookID == 0 || len(arg.Payload) == 0 {
+		return fmt.Errorf("CreateWebhookMessage: %+v: %w", arg, app.ErrInvalid)
+	}
+	now := time.Now().UTC()
+	arg2 := queries.CreateWebhookMessageParams{
+		CreatedAt:     now,
+		NextAttemptAt: now,
+		Payload:       arg.Payload,
+		WebhookID:     arg.WebhookID,
+	}
+	if err := st.qRW.CreateWebhookMessage(ctx, arg2); err != nil {
+		return fmt.Errorf("create webhook message for webhook %d: %w", arg.WebhookID, err)
+	}
+	return nil
+}
+
+func (st *Storage) DeleteWebhookMessage(ctx context.Context, id int64) error {
+	if err := st.qRW.DeleteWebhookMessage(ctx, id); err != nil {
+		return fmt.Errorf("delete webhook message %d: %w", id, err)
+	}
+	return nil
+}
+
+// ListWebhookMessagesDue returns all messages which are due for delivery at a time
+// in the order they were created.
+func (st *Storage) ListWebhookMessagesDue(ctx context.Context, now time.Time) ([]*app.WebhookMessage, error) {
+	rows, err := st.qRO.ListWebhookMessagesDue(ctx, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("list webhook messages due: %w", err)
+	}
+	oo := make([]*app.WebhookMessage, len(rows))
+	for i, r := range rows {
+		oo[i] = webhookMessageFromDBModel(r)
+	}
+	return oo, nil
+}
+
+func webhookMessageFromDBModel(r queries.WebhookMessage) *app.WebhookMessage {
+	o := &app.WebhookMessage{
+		ID:            r.ID,
+		Attempts:      int(r.Attempts),
+		CreatedAt:     r.CreatedAt,
+		LastError:     r.LastError,
+		NextAttemptAt: r.NextAttemptAt,
+		Payload:       r.Payload,
+		WebhookID:     r.WebhookID,
+	}
+	return o
+}
+
+type UpdateWebhookMessageAttemptParams struct {
+	ID            int64
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
+
+// UpdateWebhookMessageAttempt records a failed delivery attempt.
+func (st *Storage) UpdateWebhookMessageAttempt(ctx context.Context, arg UpdateWebhookMessageAttemptParams) error {
+	arg2 := queries.UpdateWebhookMessageAttemptParams{
+		Attempts:      int64(arg.Attempts),
+		LastError:     arg.LastError,
+		NextAttemptAt: arg.NextAttemptAt.UTC(),
+		ID:            arg.ID,
+	}
+	if err := st.qRW.UpdateWebhookMessageAttempt(ctx, arg2); err != nil {
+		return fmt.Errorf("update webhook message attempt: %+v: %w", arg, err)
+	}
+	return nil
+}
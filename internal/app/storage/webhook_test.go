@@ -0,0 +1,207 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage"
+	"github.com/ErikKalkoken/evebuddy/internal/app/storage/testutil"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can create new", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		arg := storage.UpdateOrCreateWebhookParams{
+			CharacterIDs:  set.New[int32](42),
+			ForwardAlerts: true,
+			ForwardMails:  true,
+			Groups:        set.New(app.GroupStructure, app.GroupWar),
+			IsEnabled:     true,
+			Name:          "Alpha",
+			Types:         set.New("StructureUnderAttack"),
+			URL:           "https://discord.com/api/webhooks/123/abc",
+		}
+		// when
+		id, err := st.CreateWebhook(ctx, arg)
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetWebhook(ctx, id)
+			if assert.NoError(t, err) {
+				assert.Equal(t, set.New[int32](42), o.CharacterIDs)
+				assert.True(t, o.ForwardAlerts)
+				assert.True(t, o.ForwardMails)
+				assert.Equal(t, set.New(app.GroupStructure, app.GroupWar), o.Groups)
+				assert.True(t, o.IsEnabled)
+				assert.Equal(t, "Alpha", o.Name)
+				assert.Equal(t, set.New("StructureUnderAttack"), o.Types)
+				assert.Equal(t, "https://discord.com/api/webhooks/123/abc", o.URL)
+				assert.WithinDuration(t, time.Now(), o.CreatedAt, 5*time.Second)
+			}
+		}
+	})
+	t.Run("should return error when params invalid", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		// when
+		_, err := st.CreateWebhook(ctx, storage.UpdateOrCreateWebhookParams{Name: "Alpha"})
+		// then
+		assert.ErrorIs(t, err, app.ErrInvalid)
+	})
+	t.Run("can update existing", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{
+			Groups: set.New(app.GroupStructure),
+		})
+		// when
+		err := st.UpdateWebhook(ctx, x.ID, storage.UpdateOrCreateWebhookParams{
+			Groups: set.New(app.GroupBills),
+			Name:   "Bravo",
+			URL:    x.URL,
+		})
+		// then
+		if assert.NoError(t, err) {
+			o, err := st.GetWebhook(ctx, x.ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "Bravo", o.Name)
+				assert.Equal(t, set.New(app.GroupBills), o.Groups)
+				assert.False(t, o.IsEnabled)
+			}
+		}
+	})
+	t.Run("can list webhooks", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x1 := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{Name: "Bravo"})
+		x2 := factory.CreateWebhook(storage.UpdateOrCreateWebhookParams{Name: "Alpha"})
+		// when
+		oo, err := st.ListWebhooks(ctx)
+		// then
+		if assert.NoError(t, err) {
+			got := make([]int64, 0)
+			for _, o := range oo {
+				got = append(got, o.ID)
+			}
+			assert.Equal(t, []int64{x2.ID, x1.ID}, got)
+		}
+	})
+	t.Run("can delete webhook with its messages", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x := factory.CreateWebhook()
+		err := st.CreateWebhookMessage(ctx, storage.CreateWebhookMessageParams{
+			Payload:   []byte("{}"),
+			WebhookID: x.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err = st.DeleteWebhook(ctx, x.ID)
+		// then
+		if assert.NoError(t, err) {
+			_, err := st.GetWebhook(ctx, x.ID)
+			assert.ErrorIs(t, err, app.ErrNotFound)
+			n, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, n)
+			}
+		}
+	})
+}
+
+func TestWebhookMessage(t *testing.T) {
+	db, st, factory := testutil.New()
+	defer db.Close()
+	ctx := context.Background()
+	t.Run("can create and list due messages", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x := factory.CreateWebhook()
+		// when
+		err := st.CreateWebhookMessage(ctx, storage.CreateWebhookMessageParams{
+			Payload:   []byte(`{"content":"alpha"}`),
+			WebhookID: x.ID,
+		})
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListWebhookMessagesDue(ctx, time.Now())
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 1)
+				o := oo[0]
+				assert.Equal(t, []byte(`{"content":"alpha"}`), o.Payload)
+				assert.Equal(t, x.ID, o.WebhookID)
+				assert.Equal(t, 0, o.Attempts)
+			}
+		}
+	})
+	t.Run("should not list messages which are not due", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x := factory.CreateWebhook()
+		err := st.CreateWebhookMessage(ctx, storage.CreateWebhookMessageParams{
+			Payload:   []byte("{}"),
+			WebhookID: x.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		oo, err := st.ListWebhookMessagesDue(ctx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err = st.UpdateWebhookMessageAttempt(ctx, storage.UpdateWebhookMessageAttemptParams{
+			ID:            oo[0].ID,
+			Attempts:      1,
+			LastError:     "error",
+			NextAttemptAt: time.Now().Add(time.Hour),
+		})
+		// then
+		if assert.NoError(t, err) {
+			oo, err := st.ListWebhookMessagesDue(ctx, time.Now())
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 0)
+			}
+			oo, err = st.ListWebhookMessagesDue(ctx, time.Now().Add(2*time.Hour))
+			if assert.NoError(t, err) {
+				assert.Len(t, oo, 1)
+				assert.Equal(t, 1, oo[0].Attempts)
+				assert.Equal(t, "error", oo[0].LastError)
+			}
+		}
+	})
+	t.Run("can delete message", func(t *testing.T) {
+		// given
+		testutil.TruncateTables(db)
+		x := factory.CreateWebhook()
+		err := st.CreateWebhookMessage(ctx, storage.CreateWebhookMessageParams{
+			Payload:   []byte("{}"),
+			WebhookID: x.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		oo, err := st.ListWebhookMessagesDue(ctx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// when
+		err = st.DeleteWebhookMessage(ctx, oo[0].ID)
+		// then
+		if assert.NoError(t, err) {
+			n, err := st.CountWebhookMessages(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, n)
+			}
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+// StructureTimerType represents the kind of a structure timer,
+// i.e. what will happen when the timer exits.
+type StructureTimerType uint
+
+const (
+	StructureTimerTypeUndefined StructureTimerType = iota
+	StructureTimerTypeArmor
+	StructureTimerTypeHull
+	StructureTimerTypeReinforced
+	StructureTimerTypeSovereignty
+)
+
+var stt2String = map[StructureTimerType]string{
+	StructureTimerTypeUndefined:   "undefined",
+	StructureTimerTypeArmor:       "armor",
+	StructureTimerTypeHull:        "hull",
+	StructureTimerTypeReinforced:  "reinforced",
+	StructureTimerTypeSovereignty: "sovereignty",
+}
+
+func (stt StructureTimerType) String() string {
+	return stt2String[stt]
+}
+
+// Display returns a string for display.
+func (stt StructureTimerType) Display() string {
+	switch stt {
+	case StructureTimerTypeArmor:
+		return "Armor"
+	case StructureTimerTypeHull:
+		return "Hull"
+	case StructureTimerTypeReinforced:
+		return "Reinforced"
+	case StructureTimerTypeSovereignty:
+		return "Sovereignty"
+	}
+	return "?"
+}
+
+// StructureTimerTypes returns all defined structure timer types.
+func StructureTimerTypes() []StructureTimerType {
+	return []StructureTimerType{
+		StructureTimerTypeArmor,
+		StructureTimerTypeHull,
+		StructureTimerTypeReinforced,
+		StructureTimerTypeSovereignty,
+	}
+}
+
+// StructureTimer is a reinforcement timer of a structure.
+// Timers are either derived from notifications or were added manually by the user.
+type StructureTimer struct {
+	ID            int64
+	CreatedAt     time.Time
+	ExitsAt       time.Time
+	IsManual      bool
+	LastNotified  optional.Optional[time.Time]
+	Notes         string
+	Owner         *EveEntity // optional
+	SolarSystem   *EveSolarSystem
+	StructureID   int64 // planet ID for orbitals, zero when unknown
+	StructureName string
+	StructureType *EntityShort[int32]
+	TimerType     StructureTimerType
+}
+
+// DisplayName returns the name of the structure for display.
+func (st StructureTimer) DisplayName() string {
+	if st.StructureName != "" {
+		return st.StructureName
+	}
+	return st.StructureType.Name
+}
+
+// IsExpired reports whether the timer has exited at time t.
+func (st StructureTimer) IsExpired(t time.Time) bool {
+	return !t.Before(st.ExitsAt)
+}
+
+// TimeUntilExit returns the remaining duration until the timer exits.
+// Returns a negative duration for expired timers.
+func (st StructureTimer) TimeUntilExit(t time.Time) time.Duration {
+	return st.ExitsAt.Sub(t)
+}
+
+// CreateStructureTimerParams contains the parameters for creating a structure timer manually.
+type CreateStructureTimerParams struct {
+	ExitsAt         time.Time
+	Notes           string
+	OwnerID         int32 // optional
+	SolarSystemID   int32
+	StructureName   string
+	StructureTypeID int32
+	TimerType       StructureTimerType
+}
@@ -0,0 +1,29 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructureTimer(t *testing.T) {
+	now := time.Now()
+	t.Run("should report remaining time until exit", func(t *testing.T) {
+		x := app.StructureTimer{ExitsAt: now.Add(3 * time.Hour)}
+		assert.Equal(t, 3*time.Hour, x.TimeUntilExit(now))
+		assert.False(t, x.IsExpired(now))
+	})
+	t.Run("should report expired timers", func(t *testing.T) {
+		x := app.StructureTimer{ExitsAt: now.Add(-time.Minute)}
+		assert.True(t, x.IsExpired(now))
+		assert.Less(t, x.TimeUntilExit(now), time.Duration(0))
+	})
+	t.Run("should use structure type as fallback for display name", func(t *testing.T) {
+		x := app.StructureTimer{StructureType: &app.EntityShort[int32]{ID: 1, Name: "Astrahus"}}
+		assert.Equal(t, "Astrahus", x.DisplayName())
+		x.StructureName = "Alpha"
+		assert.Equal(t, "Alpha", x.DisplayName())
+	})
+}
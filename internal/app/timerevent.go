@@ -0,0 +1,57 @@
+package app
+
+import (
+	"time"
+)
+
+// TimerEventKind represents the source of a timer event.
+type TimerEventKind uint
+
+const (
+	TimerEventUndefined TimerEventKind = iota
+	TimerEventCloneJump
+	TimerEventContract
+	TimerEventExtraction
+	TimerEventSkillQueue
+	TimerEventStructure
+)
+
+// Display returns a string for display.
+func (k TimerEventKind) Display() string {
+	switch k {
+	case TimerEventCloneJump:
+		return "Clone Jump"
+	case TimerEventContract:
+		return "Contract"
+	case TimerEventExtraction:
+		return "PI Extraction"
+	case TimerEventSkillQueue:
+		return "Skill Queue"
+	case TimerEventStructure:
+		return "Structure"
+	}
+	return "?"
+}
+
+// TimerEvent is a time based event, e.g. when the skill queue of a character ends.
+type TimerEvent struct {
+	Character   *EntityShort[int32] // character the event belongs to. Not set for structure timers.
+	Description string
+	Kind        TimerEventKind
+	Time        time.Time
+	Title       string
+	UID         string // stable ID of an event, e.g. for calendar exports
+}
+
+// CharacterName returns the name of the character or an empty string if not set.
+func (te TimerEvent) CharacterName() string {
+	if te.Character == nil {
+		return ""
+	}
+	return te.Character.Name
+}
+
+// IsExpired reports whether the event has expired at time t.
+func (te TimerEvent) IsExpired(t time.Time) bool {
+	return !te.Time.After(t)
+}
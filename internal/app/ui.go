@@ -37,6 +37,7 @@ type UI interface {
 	ShowEveEntityInfoWindow(o *EveEntity)
 	ShowInfoWindow(c EveEntityCategory, id int32)
 	ShowLocationInfoWindow(id int64)
+	ShowMoonInfoWindow(id int32)
 	ShowRaceInfoWindow(id int32)
 	ShowSnackbar(text string)
 	ShowTypeInfoWindow(id int32)
@@ -13,16 +13,14 @@ import (
 type Biography struct {
 	widget.BaseWidget
 
-	text *widget.Label
+	text *appwidget.EveHTML
 	top  *widget.Label
 	u    app.UI
 }
 
 func NewBiography(u app.UI) *Biography {
-	text := widget.NewLabel("")
-	text.Wrapping = fyne.TextWrapWord
 	w := &Biography{
-		text: text,
+		text: appwidget.NewEveHTMLWithUI(u),
 		top:  appwidget.MakeTopLabel(),
 		u:    u,
 	}
@@ -45,7 +43,7 @@ func (a *Biography) Update() {
 		i = widget.WarningImportance
 		a.text.SetText("")
 	} else {
-		a.text.SetText(c.EveCharacter.DescriptionPlain())
+		a.text.SetText(c.EveCharacter.Description)
 	}
 	a.top.Text = t
 	a.top.Importance = i
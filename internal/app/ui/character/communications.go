@@ -17,6 +17,7 @@ import (
 	"github.com/dustin/go-humanize"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
 	ihumanize "github.com/ErikKalkoken/evebuddy/internal/humanize"
 	"github.com/ErikKalkoken/evebuddy/internal/optional"
 	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
@@ -312,14 +313,15 @@ func (a *Communications) setDetail(n *app.CharacterNotification) {
 	h := NewMailHeader(a.u.EveImageService(), a.u.ShowEveEntityInfoWindow)
 	h.Set(n.Sender, n.Timestamp, a.u.CurrentCharacter().EveCharacter.ToEveEntity())
 	a.Detail.Add(h)
-	s, err := n.BodyPlain() // using markdown blocked by #61
+	s, err := n.BodyHTML()
 	if err != nil {
 		slog.Warn("failed to convert markdown", "notificationID", n.ID, "text", n.Body.ValueOrZero())
 	}
-	body := widget.NewRichTextWithText(s.ValueOrZero())
-	body.Wrapping = fyne.TextWrapWord
+	body := appwidget.NewEveHTMLWithUI(a.u)
 	if n.Body.IsEmpty() {
-		body.ParseMarkdown("*This notification type is not fully supported yet*")
+		body.SetMarkdown("*This notification type is not fully supported yet*")
+	} else {
+		body.SetText(s.ValueOrZero())
 	}
 	a.Detail.Add(body)
 	a.current = n
@@ -223,7 +223,12 @@ func (a *Contracts) updateEntries() error {
 }
 
 func (a *Contracts) showContract(c *app.CharacterContract) {
-	w := a.u.App().NewWindow(a.u.MakeWindowTitle("Contract"))
+	ShowContractWindow(a.u, c)
+}
+
+// ShowContractWindow shows the details of a contract in a new window.
+func ShowContractWindow(u app.UI, c *app.CharacterContract) {
+	w := u.App().NewWindow(u.MakeWindowTitle("Contract"))
 	makeExpiresString := func(c *app.CharacterContract) string {
 		t := c.DateExpiredEffective()
 		ts := t.Format(app.DateTimeFormat)
@@ -237,7 +242,7 @@ func (a *Contracts) showContract(c *app.CharacterContract) {
 	}
 	makeLocation := func(l *app.EntityShort[int64]) fyne.CanvasObject {
 		x := iwidget.NewCustomHyperlink(l.Name, func() {
-			a.u.ShowLocationInfoWindow(l.ID)
+			u.ShowLocationInfoWindow(l.ID)
 		})
 		return x
 	}
@@ -250,7 +255,7 @@ func (a *Contracts) showContract(c *app.CharacterContract) {
 	}
 	makeBaseInfo := func(c *app.CharacterContract) fyne.CanvasObject {
 		f := widget.NewForm()
-		if a.u.IsMobile() {
+		if u.IsMobile() {
 			f.Orientation = widget.Vertical
 		}
 		f.Append("Info by issuer", widget.NewLabel(c.TitleDisplay()))
@@ -270,7 +275,7 @@ func (a *Contracts) showContract(c *app.CharacterContract) {
 	}
 	makePaymentInfo := func(c *app.CharacterContract) fyne.CanvasObject {
 		f := widget.NewForm()
-		if a.u.IsMobile() {
+		if u.IsMobile() {
 			f.Orientation = widget.Vertical
 		}
 		if c.Price > 0 {
@@ -304,9 +309,9 @@ func (a *Contracts) showContract(c *app.CharacterContract) {
 	}
 	makeBidInfo := func(c *app.CharacterContract) fyne.CanvasObject {
 		ctx := context.TODO()
-		total, err := a.u.CharacterService().CountContractBids(ctx, c.ID)
+		total, err := u.CharacterService().CountContractBids(ctx, c.ID)
 		if err != nil {
-			d := a.u.NewErrorDialog("Failed to count contract bids", err, w)
+			d := u.NewErrorDialog("Failed to count contract bids", err, w)
 			d.SetOnClosed(w.Hide)
 			d.Show()
 		}
@@ -314,9 +319,9 @@ func (a *Contracts) showContract(c *app.CharacterContract) {
 		if total == 0 {
 			currentBid = "(None)"
 		} else {
-			top, err := a.u.CharacterService().GetContractTopBid(ctx, c.ID)
+			top, err := u.CharacterService().GetContractTopBid(ctx, c.ID)
 			if err != nil {
-				d := a.u.NewErrorDialog("Failed to get top bid", err, w)
+				d := u.NewErrorDialog("Failed to get top bid", err, w)
 				d.SetOnClosed(w.Hide)
 				d.Show()
 			}
@@ -334,9 +339,9 @@ func (a *Contracts) showContract(c *app.CharacterContract) {
 	}
 	makeItemsInfo := func(c *app.CharacterContract) fyne.CanvasObject {
 		vb := container.NewVBox()
-		items, err := a.u.CharacterService().ListContractItems(context.TODO(), c.ID)
+		items, err := u.CharacterService().ListContractItems(context.TODO(), c.ID)
 		if err != nil {
-			d := a.u.NewErrorDialog("Failed to fetch contract items", err, w)
+			d := u.NewErrorDialog("Failed to fetch contract items", err, w)
 			d.SetOnClosed(w.Hide)
 			d.Show()
 		}
@@ -350,7 +355,7 @@ func (a *Contracts) showContract(c *app.CharacterContract) {
 		}
 		makeItem := func(it *app.CharacterContractItem) fyne.CanvasObject {
 			x := iwidget.NewCustomHyperlink(it.Type.Name, func() {
-				a.u.ShowTypeInfoWindow(it.Type.ID)
+				u.ShowTypeInfoWindow(it.Type.ID)
 			})
 			return container.NewHBox(
 				x,
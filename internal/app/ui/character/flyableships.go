@@ -2,17 +2,22 @@ package character
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/ErikKalkoken/evebuddy/internal/app/icons"
+	ihumanize "github.com/ErikKalkoken/evebuddy/internal/humanize"
 	"github.com/ErikKalkoken/evebuddy/internal/set"
 )
 
@@ -29,6 +34,7 @@ type FlyableShips struct {
 	grid            *widget.GridWrap
 	groupSelect     *widget.Select
 	groupSelected   string
+	masteryLevels   map[int32]int
 	searchBox       *widget.Entry
 	ships           []*app.CharacterShipAbility
 	top             *widget.Label
@@ -38,10 +44,11 @@ type FlyableShips struct {
 
 func NewFlyableShips(u app.UI) *FlyableShips {
 	a := &FlyableShips{
-		ships:     make([]*app.CharacterShipAbility, 0),
-		top:       widget.NewLabel(""),
-		foundText: widget.NewLabel(""),
-		u:         u,
+		masteryLevels: make(map[int32]int),
+		ships:         make([]*app.CharacterShipAbility, 0),
+		top:           widget.NewLabel(""),
+		foundText:     widget.NewLabel(""),
+		u:             u,
 	}
 	a.ExtendBaseWidget(a)
 
@@ -124,7 +131,11 @@ func (a *FlyableShips) makeShipsGrid() *widget.GridWrap {
 			}
 			o := a.ships[id]
 			item := co.(*ShipItem)
-			item.Set(o.Type.ID, o.Type.Name, o.CanFly)
+			label := o.Type.Name
+			if level, ok := a.masteryLevels[o.Type.ID]; ok {
+				label += fmt.Sprintf("\nMastery %d", level)
+			}
+			item.Set(o.Type.ID, label, o.CanFly)
 		})
 	g.OnSelected = func(id widget.GridWrapItemID) {
 		defer g.UnselectAll()
@@ -132,11 +143,70 @@ func (a *FlyableShips) makeShipsGrid() *widget.GridWrap {
 			return
 		}
 		o := a.ships[id]
-		a.u.ShowTypeInfoWindow(o.Type.ID)
+		a.showShipMastery(o)
 	}
 	return g
 }
 
+// showShipMastery shows the mastery level of a ship and the skills missing for the next level.
+// Shows the info window for ships without mastery data.
+func (a *FlyableShips) showShipMastery(o *app.CharacterShipAbility) {
+	if !a.u.HasCharacter() {
+		a.u.ShowTypeInfoWindow(o.Type.ID)
+		return
+	}
+	w := a.u.MainWindow()
+	m, err := a.u.CharacterService().GetShipMastery(context.Background(), a.u.CurrentCharacterID(), o.Type.ID)
+	if errors.Is(err, app.ErrNotFound) {
+		a.u.ShowTypeInfoWindow(o.Type.ID)
+		return
+	}
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load ship mastery", err, w)
+		return
+	}
+	info := widget.NewLabel(fmt.Sprintf("Mastery level: %d / %d", m.Level, app.EveShipMasteryLevelMax))
+	var missing fyne.CanvasObject
+	copyButton := widget.NewButtonWithIcon("Copy skill queue", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(app.SkillQueueText(m.Missing))
+		a.u.ShowSnackbar("Skill queue copied to clipboard")
+	})
+	if next := m.NextLevel(); next == 0 {
+		missing = widget.NewLabel("This character has reached the highest mastery level.")
+		copyButton.Disable()
+	} else {
+		lines := make([]string, len(m.Missing))
+		for i, x := range m.Missing {
+			lines[i] = fmt.Sprintf("%s %d / %d", x.SkillName, x.CurrentLevel, x.RequiredLevel)
+		}
+		missing = container.NewBorder(
+			widget.NewLabel(fmt.Sprintf(
+				"Skills missing for level %d (training time: %s)",
+				next,
+				ihumanize.Optional(m.TrainingTime, "?"),
+			)),
+			nil,
+			nil,
+			nil,
+			container.NewVScroll(widget.NewLabel(strings.Join(lines, "\n"))),
+		)
+	}
+	infoButton := widget.NewButtonWithIcon("Show ship", theme.InfoIcon(), func() {
+		a.u.ShowTypeInfoWindow(o.Type.ID)
+	})
+	content := container.NewBorder(
+		info,
+		container.NewHBox(copyButton, layout.NewSpacer(), infoButton),
+		nil,
+		nil,
+		missing,
+	)
+	d := dialog.NewCustom(fmt.Sprintf("%s Mastery", o.Type.Name), "Close", content, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 400))
+	d.Show()
+}
+
 func (a *FlyableShips) Update() {
 	t, i, enabled, err := func() (string, widget.Importance, bool, error) {
 		exists := a.u.StatusCacheService().GeneralSectionExists(app.SectionEveCategories)
@@ -167,6 +237,7 @@ func (a *FlyableShips) Update() {
 
 func (a *FlyableShips) updateEntries() error {
 	if !a.u.HasCharacter() {
+		a.masteryLevels = make(map[int32]int)
 		a.ships = make([]*app.CharacterShipAbility, 0)
 		a.grid.Refresh()
 		a.searchBox.SetText("")
@@ -196,6 +267,11 @@ func (a *FlyableShips) updateEntries() error {
 			ships = append(ships, o)
 		}
 	}
+	levels, err := a.u.CharacterService().ListShipMasteryLevels(context.Background(), characterID)
+	if err != nil {
+		return err
+	}
+	a.masteryLevels = levels
 	a.ships = ships
 	a.grid.Refresh()
 	g := set.New[string]()
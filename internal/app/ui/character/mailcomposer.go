@@ -0,0 +1,171 @@
+package character
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"image/color"
+	"net/url"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/icons"
+	"github.com/ErikKalkoken/evebuddy/internal/evehtml"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+// mailFontSizes are the font sizes offered in the composer. 12 is the default of the Eve client.
+var mailFontSizes = []int{8, 10, 12, 14, 18, 24, 32}
+
+// mailLinkCategories are the categories of Eve entities which can be linked in a mail.
+var mailLinkCategories = []app.EveEntityCategory{
+	app.EveEntityAlliance,
+	app.EveEntityCharacter,
+	app.EveEntityCorporation,
+	app.EveEntityInventoryType,
+	app.EveEntitySolarSystem,
+}
+
+// makeFormatBar returns a bar with actions for formatting the body of a mail.
+// Formatting is done by wrapping the selected text in the Eve Online HTML tags shown by the Eve client.
+func (a *SendMail) makeFormatBar() fyne.CanvasObject {
+	bold := iwidget.NewIconButton(theme.NewThemedResource(icons.FormatboldSvg), func() {
+		a.wrapSelection("<b>", "</b>")
+	})
+	italic := iwidget.NewIconButton(theme.NewThemedResource(icons.FormatitalicSvg), func() {
+		a.wrapSelection("<i>", "</i>")
+	})
+	underline := iwidget.NewIconButton(theme.NewThemedResource(icons.FormatunderlinedSvg), func() {
+		a.wrapSelection("<u>", "</u>")
+	})
+	colour := iwidget.NewIconButton(theme.ColorPaletteIcon(), func() {
+		d := dialog.NewColorPicker("Font color", "Pick a color for the selected text", func(c color.Color) {
+			a.wrapSelection(fmt.Sprintf(`<font color="%s">`, eveColor(c)), "</font>")
+		}, a.w)
+		d.Advanced = true
+		d.Show()
+	})
+	sizeItems := make([]*fyne.MenuItem, len(mailFontSizes))
+	for i, v := range mailFontSizes {
+		sizeItems[i] = fyne.NewMenuItem(strconv.Itoa(v), func() {
+			a.wrapSelection(fmt.Sprintf(`<font size="%d">`, v), "</font>")
+		})
+	}
+	size := iwidget.NewIconButtonWithMenu(
+		theme.NewThemedResource(icons.FormatsizeSvg), fyne.NewMenu("", sizeItems...),
+	)
+	link := iwidget.NewIconButton(theme.NewThemedResource(icons.LinkSvg), func() {
+		a.showLinkDialog()
+	})
+	entityLink := iwidget.NewIconButton(theme.AccountIcon(), func() {
+		showAddDialog(a.u, a.character.ID, "Add Link", mailLinkCategories, func(ee *app.EveEntity) {
+			a.replaceSelection(ee.ShowInfoLink())
+		}, a.w)
+	})
+	a.previewButton = iwidget.NewIconButton(theme.VisibilityIcon(), func() {
+		a.setPreview(!a.preview.Visible())
+	})
+	return container.NewHBox(
+		bold,
+		italic,
+		underline,
+		colour,
+		size,
+		widget.NewSeparator(),
+		link,
+		entityLink,
+		layout.NewSpacer(),
+		a.previewButton,
+	)
+}
+
+// setPreview shows or hides a preview of how the body will look in the Eve client.
+func (a *SendMail) setPreview(enabled bool) {
+	if !enabled {
+		a.preview.Hide()
+		a.previewButton.SetIcon(theme.VisibilityIcon())
+		a.body.Show()
+		return
+	}
+	a.preview.SetText(evehtml.FromRichText(a.body.Text))
+	a.body.Hide()
+	a.previewButton.SetIcon(theme.VisibilityOffIcon())
+	a.preview.Show()
+}
+
+// wrapSelection surrounds the selected text of the body with a start and an end tag.
+// When no text is selected both tags are inserted at the cursor.
+func (a *SendMail) wrapSelection(start, end string) {
+	a.replaceSelection(start + a.body.SelectedText() + end)
+}
+
+// replaceSelection replaces the selected text of the body with s.
+// When no text is selected s is inserted at the cursor.
+func (a *SendMail) replaceSelection(s string) {
+	a.setPreview(false)
+	a.body.TypedShortcut(&fyne.ShortcutPaste{Clipboard: &textClipboard{content: s}})
+	if c := fyne.CurrentApp().Driver().CanvasForObject(a.body); c != nil {
+		c.Focus(a.body)
+	}
+}
+
+// showLinkDialog shows a dialog for inserting a link to a web page.
+func (a *SendMail) showLinkDialog() {
+	address := widget.NewEntry()
+	address.PlaceHolder = "https://"
+	address.Validator = func(s string) error {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("must be a web address")
+		}
+		return nil
+	}
+	text := widget.NewEntry()
+	text.SetText(a.body.SelectedText())
+	text.PlaceHolder = "Defaults to the address"
+	items := []*widget.FormItem{
+		widget.NewFormItem("Address", address),
+		widget.NewFormItem("Text", text),
+	}
+	d := dialog.NewForm("Add Link", "Add", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		s := text.Text
+		if s == "" {
+			s = address.Text
+		}
+		a.replaceSelection(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(address.Text), html.EscapeString(s)))
+	}, a.w)
+	d.Resize(fyne.NewSize(400, 200))
+	d.Show()
+}
+
+// eveColor returns a color in the #AARRGGBB format used by the Eve client.
+func eveColor(c color.Color) string {
+	x := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x%02x", x.A, x.R, x.G, x.B)
+}
+
+// textClipboard is a clipboard with a fixed content.
+// It allows to insert text into an entry through a paste shortcut, which replaces the current selection.
+type textClipboard struct {
+	content string
+}
+
+var _ fyne.Clipboard = (*textClipboard)(nil)
+
+func (c *textClipboard) Content() string {
+	return c.content
+}
+
+func (c *textClipboard) SetContent(content string) {
+	c.content = content
+}
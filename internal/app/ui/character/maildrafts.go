@@ -0,0 +1,175 @@
+package character
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+// showDraftsDialog shows a dialog with the mail drafts and the outbox of the current character.
+func (a *Mails) showDraftsDialog() {
+	c := a.u.CurrentCharacter()
+	if c == nil {
+		return
+	}
+	w := a.u.MainWindow()
+	var drafts []*app.CharacterMailDraft
+	var d dialog.Dialog
+	var list *widget.List
+	empty := widget.NewLabel("No drafts and no mails in the outbox")
+	empty.Importance = widget.LowImportance
+	reload := func() {
+		var err error
+		drafts, err = a.u.CharacterService().ListMailDrafts(context.Background(), c.ID)
+		if err != nil {
+			a.u.ShowErrorDialog("Failed to load mail drafts", err, w)
+			return
+		}
+		if len(drafts) == 0 {
+			empty.Show()
+		} else {
+			empty.Hide()
+		}
+		list.Refresh()
+	}
+	list = widget.NewList(
+		func() int {
+			return len(drafts)
+		},
+		func() fyne.CanvasObject {
+			subject := widget.NewLabel("Template")
+			subject.Truncation = fyne.TextTruncateEllipsis
+			subject.TextStyle.Bold = true
+			info := iwidget.NewLabelWithSize("Template", theme.SizeNameCaptionText)
+			info.Truncation = fyne.TextTruncateEllipsis
+			status := iwidget.NewLabelWithSize("Template", theme.SizeNameCaptionText)
+			status.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(
+				nil,
+				nil,
+				nil,
+				container.NewHBox(
+					iwidget.NewIconButton(theme.MailSendIcon(), nil),
+					iwidget.NewIconButton(theme.DocumentCreateIcon(), nil),
+					iwidget.NewIconButton(theme.DeleteIcon(), nil),
+				),
+				container.NewVBox(subject, info, status),
+			)
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(drafts) {
+				return
+			}
+			o := drafts[id]
+			border := co.(*fyne.Container).Objects
+			main := border[0].(*fyne.Container).Objects
+			subject := o.Subject
+			if subject == "" {
+				subject = "(no subject)"
+			}
+			main[0].(*widget.Label).SetText(subject)
+			names := make([]string, len(o.Recipients))
+			for i, r := range o.Recipients {
+				names[i] = r.Name
+			}
+			main[1].(*iwidget.Label).SetText(fmt.Sprintf(
+				"To: %s • %s", strings.Join(names, ", "), o.UpdatedAt.Local().Format(app.DateTimeFormat),
+			))
+			status := main[2].(*iwidget.Label)
+			status.SetText(o.StatusDisplay())
+			switch o.Status {
+			case app.MailDraftFailed, app.MailDraftUnconfirmed:
+				status.Importance = widget.DangerImportance
+			case app.MailDraftQueued, app.MailDraftSending:
+				status.Importance = widget.WarningImportance
+			default:
+				status.Importance = widget.MediumImportance
+			}
+			status.Refresh()
+			buttons := border[1].(*fyne.Container).Objects
+			send := buttons[0].(*iwidget.IconButton)
+			send.OnTapped = func() {
+				queue := func() {
+					o2 := *o
+					o2.Status = app.MailDraftQueued
+					a.runMailOperation("Queuing mail...", func() error {
+						_, err := a.u.CharacterService().SaveMailDraft(context.Background(), &o2)
+						return err
+					}, func() {
+						reload()
+						a.u.ShowSnackbar(fmt.Sprintf("Mail \"%s\" queued in outbox", subject))
+					})
+				}
+				if o.Status != app.MailDraftUnconfirmed {
+					queue()
+					return
+				}
+				a.u.ShowConfirmDialog(
+					"Send mail again",
+					fmt.Sprintf("The mail \"%s\" might have been sent already. Please check your sent mails. Are you sure you want to send it again?", subject),
+					"Send",
+					func(confirmed bool) {
+						if confirmed {
+							queue()
+						}
+					}, w)
+			}
+			if o.Status == app.MailDraftQueued || o.Status == app.MailDraftSending {
+				send.Disable()
+			} else {
+				send.Enable()
+			}
+			buttons[1].(*iwidget.IconButton).OnTapped = func() {
+				if a.OnEditDraft == nil {
+					return
+				}
+				d.Hide()
+				a.OnEditDraft(c, o)
+			}
+			buttons[2].(*iwidget.IconButton).OnTapped = func() {
+				a.u.ShowConfirmDialog(
+					"Delete draft",
+					fmt.Sprintf("Are you sure you want to delete the draft \"%s\"?", subject),
+					"Delete",
+					func(confirmed bool) {
+						if !confirmed {
+							return
+						}
+						a.runMailOperation("Deleting draft...", func() error {
+							return a.u.CharacterService().DeleteMailDraft(context.Background(), c.ID, o.ID)
+						}, reload)
+					}, w)
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		defer list.UnselectAll()
+		if id >= len(drafts) || a.OnEditDraft == nil {
+			return
+		}
+		d.Hide()
+		a.OnEditDraft(c, drafts[id])
+	}
+	reload()
+	refresh := iwidget.NewIconButton(theme.ViewRefreshIcon(), reload)
+	content := container.NewBorder(
+		container.NewBorder(nil, nil, nil, refresh, empty),
+		nil,
+		nil,
+		nil,
+		list,
+	)
+	d = dialog.NewCustom("Drafts & Outbox", "Close", content, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(600, 450))
+	d.Show()
+}
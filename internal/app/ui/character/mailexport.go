@@ -0,0 +1,211 @@
+package character
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+const (
+	mailExportAll     = "All"
+	mailExportAnyList = "Any"
+)
+
+// showExportDialog shows a dialog for exporting the mails of the current character
+// as mbox archive or as EML files.
+func (a *Mails) showExportDialog() {
+	c := a.u.CurrentCharacter()
+	if c == nil {
+		return
+	}
+	ctx := context.Background()
+	w := a.u.MainWindow()
+	labels, err := a.u.CharacterService().ListMailLabelsOrdered(ctx, c.ID)
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load mail labels", err, w)
+		return
+	}
+	lists, err := a.u.CharacterService().ListMailLists(ctx, c.ID)
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load mailing lists", err, w)
+		return
+	}
+
+	formats := map[string]app.MailExportFormat{
+		"mbox archive": app.MailExportMbox,
+		"EML files":    app.MailExportEML,
+	}
+	formatSelect := widget.NewRadioGroup([]string{"mbox archive", "EML files"}, nil)
+	formatSelect.Required = true
+	formatSelect.SetSelected("mbox archive")
+
+	labelIDs := map[string]int32{mailExportAll: 0}
+	labelNames := []string{mailExportAll}
+	for _, l := range labels {
+		if _, found := labelIDs[l.Name]; found {
+			continue
+		}
+		labelIDs[l.Name] = l.LabelID
+		labelNames = append(labelNames, l.Name)
+	}
+	labelSelect := widget.NewSelect(labelNames, nil)
+	labelSelect.SetSelected(mailExportAll)
+
+	listIDs := map[string]int32{mailExportAnyList: 0}
+	listNames := []string{mailExportAnyList}
+	for _, o := range lists {
+		listIDs[o.Name] = o.ID
+		listNames = append(listNames, o.Name)
+	}
+	listSelect := widget.NewSelect(listNames, nil)
+	listSelect.SetSelected(mailExportAnyList)
+
+	validateDate := func(s string) error {
+		if _, err := parseMailExportDate(s); err != nil {
+			return errors.New("must be a date like " + app.DateFormat)
+		}
+		return nil
+	}
+	since := widget.NewEntry()
+	since.PlaceHolder = app.DateFormat
+	since.Validator = validateDate
+	until := widget.NewEntry()
+	until.PlaceHolder = app.DateFormat
+	until.Validator = validateDate
+	keepHTML := widget.NewCheck("", nil)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Format", formatSelect),
+		{
+			Text:     "Label",
+			Widget:   labelSelect,
+			HintText: "Export only mails with this label.",
+		},
+		{
+			Text:     "Mailing list",
+			Widget:   listSelect,
+			HintText: "Export only mails sent to this mailing list. Overrides the label.",
+		},
+		{
+			Text:     "From date",
+			Widget:   since,
+			HintText: "Export only mails sent on or after this date.",
+		},
+		{
+			Text:     "To date",
+			Widget:   until,
+			HintText: "Export only mails sent on or before this date.",
+		},
+		{
+			Text:     "Keep HTML",
+			Widget:   keepHTML,
+			HintText: "Keep mail bodies as HTML instead of converting them to plain text.",
+		},
+	}
+	d := dialog.NewForm("Export mails", "Export", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		arg := app.MailExportParams{
+			CharacterID: c.ID,
+			KeepHTML:    keepHTML.Checked,
+			LabelID:     labelIDs[labelSelect.Selected],
+			ListID:      listIDs[listSelect.Selected],
+		}
+		arg.Since, _ = parseMailExportDate(since.Text)
+		if t, _ := parseMailExportDate(until.Text); !t.IsZero() {
+			arg.Until = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		switch formats[formatSelect.Selected] {
+		case app.MailExportMbox:
+			a.showExportMboxDialog(c, arg)
+		case app.MailExportEML:
+			a.showExportEMLDialog(arg)
+		}
+	}, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 450))
+	d.Show()
+}
+
+func (a *Mails) showExportMboxDialog(c *app.Character, arg app.MailExportParams) {
+	w := a.u.MainWindow()
+	d := dialog.NewFileSave(
+		func(writer fyne.URIWriteCloser, err error) {
+			err2 := func() error {
+				if err != nil {
+					return err
+				}
+				if writer == nil {
+					return nil
+				}
+				defer writer.Close()
+				n, err := a.u.CharacterService().ExportMailsMbox(context.Background(), arg, writer)
+				if err != nil {
+					return err
+				}
+				a.u.ShowSnackbar(fmt.Sprintf("%d mails exported to %s", n, writer.URI().Name()))
+				return nil
+			}()
+			if err2 != nil {
+				a.u.ShowErrorDialog("Failed to export mails", err2, w)
+			}
+		}, w,
+	)
+	d.SetFileName(strings.ReplaceAll(c.EveCharacter.Name, " ", "_") + "_mails.mbox")
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Show()
+}
+
+func (a *Mails) showExportEMLDialog(arg app.MailExportParams) {
+	w := a.u.MainWindow()
+	d := dialog.NewFolderOpen(
+		func(dir fyne.ListableURI, err error) {
+			err2 := func() error {
+				if err != nil {
+					return err
+				}
+				if dir == nil {
+					return nil
+				}
+				create := func(name string) (io.WriteCloser, error) {
+					u, err := storage.Child(dir, name)
+					if err != nil {
+						return nil, err
+					}
+					return storage.Writer(u)
+				}
+				n, err := a.u.CharacterService().ExportMailsEML(context.Background(), arg, create)
+				if err != nil {
+					return err
+				}
+				a.u.ShowSnackbar(fmt.Sprintf("%d mails exported to %s", n, dir.Name()))
+				return nil
+			}()
+			if err2 != nil {
+				a.u.ShowErrorDialog("Failed to export mails", err2, w)
+			}
+		}, w,
+	)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Show()
+}
+
+// parseMailExportDate parses a date in local time. An empty string returns the zero time.
+func parseMailExportDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(app.DateFormat, s, time.Local)
+}
@@ -0,0 +1,201 @@
+package character
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	kxmodal "github.com/ErikKalkoken/fyne-kx/modal"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+// showManageLabelsDialog shows a dialog for creating and deleting the mail labels of the current character.
+func (a *Mails) showManageLabelsDialog() {
+	characterID := a.u.CurrentCharacterID()
+	if characterID == 0 {
+		return
+	}
+	w := a.u.MainWindow()
+	labels, err := a.u.CharacterService().ListMailLabelsOrdered(context.Background(), characterID)
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load mail labels", err, w)
+		return
+	}
+	var d dialog.Dialog
+	list := widget.NewList(
+		func() int {
+			return len(labels)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(
+				nil,
+				nil,
+				makeLabelColorSwatch(""),
+				iwidget.NewIconButton(theme.DeleteIcon(), nil),
+				widget.NewLabel("Template"),
+			)
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(labels) {
+				return
+			}
+			l := labels[id]
+			c := co.(*fyne.Container).Objects
+			c[0].(*widget.Label).SetText(l.Name)
+			swatch := c[1].(*canvas.Rectangle)
+			swatch.FillColor = parseLabelColor(l.Color)
+			swatch.Refresh()
+			c[2].(*iwidget.IconButton).OnTapped = func() {
+				a.u.ShowConfirmDialog(
+					"Delete label",
+					fmt.Sprintf("Are you sure you want to delete the label \"%s\"?\n\nThe label will be removed from all mails.", l.Name),
+					"Delete",
+					func(confirmed bool) {
+						if !confirmed {
+							return
+						}
+						a.runMailOperation("Deleting label...", func() error {
+							return a.u.CharacterService().DeleteMailLabel(context.Background(), characterID, l.LabelID)
+						}, func() {
+							d.Hide()
+							a.Update()
+							a.u.ShowSnackbar(fmt.Sprintf("Label \"%s\" deleted", l.Name))
+						})
+					}, w)
+			}
+		},
+	)
+	name := widget.NewEntry()
+	name.PlaceHolder = "Name of new label"
+	colorSelect := widget.NewSelect(app.MailLabelColors, nil)
+	colorSelect.SetSelected(app.MailLabelColors[len(app.MailLabelColors)-1])
+	create := widget.NewButtonWithIcon("Create", theme.ContentAddIcon(), func() {
+		if name.Text == "" {
+			return
+		}
+		a.runMailOperation("Creating label...", func() error {
+			_, err := a.u.CharacterService().CreateMailLabel(context.Background(), characterID, name.Text, colorSelect.Selected)
+			return err
+		}, func() {
+			d.Hide()
+			a.Update()
+			a.u.ShowSnackbar(fmt.Sprintf("Label \"%s\" created", name.Text))
+		})
+	})
+	var top fyne.CanvasObject
+	if len(labels) == 0 {
+		top = widget.NewLabel("No labels")
+	} else {
+		top = widget.NewLabel("Labels")
+	}
+	c := container.NewBorder(
+		top,
+		container.NewBorder(nil, nil, nil, container.NewHBox(colorSelect, create), name),
+		nil,
+		nil,
+		list,
+	)
+	d = dialog.NewCustom("Manage labels", "Close", c, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 400))
+	d.Show()
+}
+
+// showApplyLabelDialog shows a dialog for adding a label to or removing a label from mails.
+func (a *Mails) showApplyLabelDialog(characterID int32, mailIDs []int32, onSuccess func()) {
+	w := a.u.MainWindow()
+	labels, err := a.u.CharacterService().ListMailLabelsOrdered(context.Background(), characterID)
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load mail labels", err, w)
+		return
+	}
+	names := []string{"Inbox"}
+	name2id := map[string]int32{"Inbox": app.MailLabelInbox}
+	for _, l := range labels {
+		if _, found := name2id[l.Name]; found {
+			continue
+		}
+		names = append(names, l.Name)
+		name2id[l.Name] = l.LabelID
+	}
+	labelSelect := widget.NewSelect(names, nil)
+	labelSelect.PlaceHolder = "Select label"
+	var d dialog.Dialog
+	run := func(add bool) {
+		labelID, ok := name2id[labelSelect.Selected]
+		if !ok {
+			return
+		}
+		d.Hide()
+		a.runMailOperation("Updating mails...", func() error {
+			ctx := context.Background()
+			if add {
+				return a.u.CharacterService().AddMailsLabel(ctx, characterID, mailIDs, labelID)
+			}
+			return a.u.CharacterService().RemoveMailsLabel(ctx, characterID, mailIDs, labelID)
+		}, onSuccess)
+	}
+	c := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Add a label to or remove a label from %d mails", len(mailIDs))),
+		labelSelect,
+		container.NewHBox(
+			widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), func() {
+				run(true)
+			}),
+			widget.NewButtonWithIcon("Remove", theme.ContentRemoveIcon(), func() {
+				run(false)
+			}),
+		),
+	)
+	d = dialog.NewCustom("Labels", "Cancel", c, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Show()
+}
+
+// runMailOperation runs a mail operation with a progress modal and reports errors.
+func (a *Mails) runMailOperation(title string, f func() error, onSuccess func()) {
+	m := kxmodal.NewProgressInfinite(title, "", f, a.u.MainWindow())
+	m.OnSuccess = func() {
+		if onSuccess != nil {
+			onSuccess()
+		}
+		a.u.UpdateMailIndicator()
+	}
+	m.OnError = func(err error) {
+		slog.Error("Mail operation failed", "title", title, "err", err)
+		a.u.ShowSnackbar(fmt.Sprintf("ERROR: %s", a.u.ErrorDisplay(err)))
+		// some mails might have been updated nevertheless
+		a.headerRefresh()
+		a.u.UpdateMailIndicator()
+	}
+	m.Start()
+}
+
+func makeLabelColorSwatch(s string) *canvas.Rectangle {
+	r := canvas.NewRectangle(parseLabelColor(s))
+	r.SetMinSize(fyne.NewSquareSize(theme.IconInlineSize()))
+	r.CornerRadius = theme.InputRadiusSize()
+	return r
+}
+
+// parseLabelColor returns the color for a label color in hex format, e.g. "#ff6600".
+func parseLabelColor(s string) color.Color {
+	if len(s) != 7 || s[0] != '#' {
+		return color.Transparent
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.Transparent
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+}
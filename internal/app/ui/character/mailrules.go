@@ -0,0 +1,273 @@
+package character
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+const (
+	mailRuleAnyList = "Any"
+	mailRuleNoLabel = "None"
+)
+
+// showMailRulesDialog shows a dialog for managing the mail rules of the current character.
+func (a *Mails) showMailRulesDialog() {
+	characterID := a.u.CurrentCharacterID()
+	if characterID == 0 {
+		return
+	}
+	w := a.u.MainWindow()
+	var rules []*app.CharacterMailRule
+	var list *widget.List
+	empty := widget.NewLabel("No rules")
+	empty.Importance = widget.LowImportance
+	reload := func() {
+		var err error
+		rules, err = a.u.CharacterService().ListMailRules(context.Background(), characterID)
+		if err != nil {
+			a.u.ShowErrorDialog("Failed to load mail rules", err, w)
+			return
+		}
+		if len(rules) == 0 {
+			empty.Show()
+		} else {
+			empty.Hide()
+		}
+		list.Refresh()
+	}
+	list = widget.NewList(
+		func() int {
+			return len(rules)
+		},
+		func() fyne.CanvasObject {
+			info := iwidget.NewLabelWithSize("Template", theme.SizeNameCaptionText)
+			info.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(
+				nil,
+				nil,
+				widget.NewIcon(theme.ConfirmIcon()),
+				nil,
+				container.NewVBox(widget.NewLabel("Template"), info),
+			)
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(rules) {
+				return
+			}
+			r := rules[id]
+			border := co.(*fyne.Container).Objects
+			main := border[0].(*fyne.Container).Objects
+			main[0].(*widget.Label).SetText(r.Name)
+			main[1].(*iwidget.Label).SetText(mailRuleActionsDisplay(r))
+			icon := border[1].(*widget.Icon)
+			if r.IsEnabled {
+				icon.SetResource(theme.ConfirmIcon())
+			} else {
+				icon.SetResource(theme.CancelIcon())
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		defer list.UnselectAll()
+		if id >= len(rules) {
+			return
+		}
+		a.showMailRuleDialog(rules[id], reload)
+	}
+	reload()
+	add := widget.NewButtonWithIcon("Add rule", theme.ContentAddIcon(), func() {
+		a.showMailRuleDialog(&app.CharacterMailRule{CharacterID: characterID, IsEnabled: true}, reload)
+	})
+	hint := widget.NewLabel("Rules are applied to new mails when they are received.")
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	c := container.NewBorder(
+		container.NewVBox(hint, empty),
+		container.NewHBox(add),
+		nil,
+		nil,
+		list,
+	)
+	d := dialog.NewCustom("Mail rules", "Close", c, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 450))
+	d.Show()
+}
+
+// showMailRuleDialog shows a dialog for editing a mail rule.
+// A new rule is created when r has no ID.
+func (a *Mails) showMailRuleDialog(r *app.CharacterMailRule, onChanged func()) {
+	ctx := context.Background()
+	w := a.u.MainWindow()
+	isNew := r.ID == 0
+	labels, err := a.u.CharacterService().ListMailLabelsOrdered(ctx, r.CharacterID)
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load mail labels", err, w)
+		return
+	}
+	lists, err := a.u.CharacterService().ListMailLists(ctx, r.CharacterID)
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load mailing lists", err, w)
+		return
+	}
+
+	name := widget.NewEntry()
+	name.SetText(r.Name)
+	name.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return errors.New("can not be empty")
+		}
+		return nil
+	}
+	isEnabled := widget.NewCheck("", nil)
+	isEnabled.SetChecked(r.IsEnabled)
+	sender := widget.NewEntry()
+	sender.SetText(r.Sender)
+	keyword := widget.NewEntry()
+	keyword.SetText(r.Keyword)
+
+	listIDs := map[string]int32{mailRuleAnyList: 0}
+	listNames := []string{mailRuleAnyList}
+	listSelected := mailRuleAnyList
+	for _, o := range lists {
+		listIDs[o.Name] = o.ID
+		listNames = append(listNames, o.Name)
+		if o.ID == r.MailListID {
+			listSelected = o.Name
+		}
+	}
+	listSelect := widget.NewSelect(listNames, nil)
+	listSelect.SetSelected(listSelected)
+
+	labelIDs := map[string]int32{mailRuleNoLabel: 0}
+	labelNames := []string{mailRuleNoLabel}
+	labelSelected := mailRuleNoLabel
+	for _, l := range labels {
+		if _, found := labelIDs[l.Name]; found {
+			continue
+		}
+		labelIDs[l.Name] = l.LabelID
+		labelNames = append(labelNames, l.Name)
+		if l.LabelID == r.LabelID {
+			labelSelected = l.Name
+		}
+	}
+	labelSelect := widget.NewSelect(labelNames, nil)
+	labelSelect.SetSelected(labelSelected)
+
+	markAsRead := widget.NewCheck("", nil)
+	markAsRead.SetChecked(r.MarkAsRead)
+	suppressNotification := widget.NewCheck("", nil)
+	suppressNotification.SetChecked(r.SuppressNotification)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", name),
+		widget.NewFormItem("Enabled", isEnabled),
+		{
+			Text:     "Sender",
+			Widget:   sender,
+			HintText: "Match only mails from this sender.",
+		},
+		{
+			Text:     "Mailing list",
+			Widget:   listSelect,
+			HintText: "Match only mails sent to this mailing list.",
+		},
+		{
+			Text:     "Keyword",
+			Widget:   keyword,
+			HintText: "Match only mails with this keyword in subject or body.",
+		},
+		{
+			Text:     "Apply label",
+			Widget:   labelSelect,
+			HintText: "Add this label to matching mails.",
+		},
+		widget.NewFormItem("Mark as read", markAsRead),
+		{
+			Text:     "No notification",
+			Widget:   suppressNotification,
+			HintText: "Do not show a desktop notification for matching mails.",
+		},
+	}
+	var d dialog.Dialog
+	if !isNew {
+		deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+			a.u.ShowConfirmDialog("Delete rule", "Are you sure you want to delete "+r.Name+"?", "Delete", func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				if err := a.u.CharacterService().DeleteMailRule(ctx, r.CharacterID, r.ID); err != nil {
+					a.u.ShowErrorDialog("Failed to delete rule", err, w)
+					return
+				}
+				d.Hide()
+				onChanged()
+			}, w)
+		})
+		deleteButton.Importance = widget.DangerImportance
+		items = append(items, widget.NewFormItem("", container.NewHBox(deleteButton)))
+	}
+	var title, confirm string
+	if isNew {
+		title, confirm = "Add rule", "Add"
+	} else {
+		title, confirm = "Edit rule", "Save"
+	}
+	d = dialog.NewForm(title, confirm, "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		r.Name = strings.TrimSpace(name.Text)
+		r.IsEnabled = isEnabled.Checked
+		r.Sender = strings.TrimSpace(sender.Text)
+		r.Keyword = strings.TrimSpace(keyword.Text)
+		r.MailListID = listIDs[listSelect.Selected]
+		r.LabelID = labelIDs[labelSelect.Selected]
+		r.MarkAsRead = markAsRead.Checked
+		r.SuppressNotification = suppressNotification.Checked
+		var err error
+		if isNew {
+			_, err = a.u.CharacterService().CreateMailRule(ctx, r)
+		} else {
+			err = a.u.CharacterService().UpdateMailRule(ctx, r)
+		}
+		if err != nil {
+			a.u.ShowErrorDialog(fmt.Sprintf("Failed to save rule %s", r.Name), err, w)
+			return
+		}
+		onChanged()
+	}, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 550))
+	d.Show()
+}
+
+// mailRuleActionsDisplay returns a short description of what a rule does.
+func mailRuleActionsDisplay(r *app.CharacterMailRule) string {
+	if !r.HasActions() {
+		return "No actions"
+	}
+	var parts []string
+	if r.LabelID != 0 {
+		parts = append(parts, "Apply label")
+	}
+	if r.MarkAsRead {
+		parts = append(parts, "Mark as read")
+	}
+	if r.SuppressNotification {
+		parts = append(parts, "No notification")
+	}
+	return strings.Join(parts, " • ")
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ErikKalkoken/evebuddy/internal/app/icons"
 	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
 	"github.com/ErikKalkoken/evebuddy/internal/optional"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
 	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
 )
 
@@ -94,31 +95,46 @@ type Mails struct {
 	Headers       fyne.CanvasObject
 	OnSelected    func()
 	OnUpdate      func(count int)
+	OnEditDraft   func(character *app.Character, draft *app.CharacterMailDraft)
 	OnSendMessage func(character *app.Character, mode app.SendMailMode, mail *app.CharacterMail)
 
-	body          *widget.Label
+	body          *appwidget.EveHTML
 	folders       *iwidget.Tree[FolderNode]
 	header        *MailHeader
 	headerList    *widget.List
 	headers       []*app.CharacterMailHeader
 	headerTop     *widget.Label
 	folderDefault FolderNode
+	isSelecting   bool
 	lastSelected  widget.ListItemID
 	lastFolder    FolderNode
 	mail          *app.CharacterMail
+	mailDetail    fyne.CanvasObject
+	selected      set.Set[int32] // mail IDs of selected headers
+	selectionBar  *fyne.Container
+	selectionInfo *widget.Label
+	showThreads   bool // whether to group mails into conversations
 	subject       *iwidget.Label
+	thread        *fyne.Container
+	threadDetail  *container.Scroll
+	threadMode    *iwidget.IconButton
+	threads       []*app.CharacterMailThread // items shown in the header list
 	toolbar       *widget.Toolbar
 	u             app.UI
 }
 
 func NewMail(u app.UI) *Mails {
 	a := &Mails{
-		body:      widget.NewLabel(""),
-		header:    NewMailHeader(u.EveImageService(), u.ShowEveEntityInfoWindow),
-		headers:   make([]*app.CharacterMailHeader, 0),
-		headerTop: appwidget.MakeTopLabel(),
-		subject:   iwidget.NewLabelWithSize("", theme.SizeNameSubHeadingText),
-		u:         u,
+		body:          appwidget.NewEveHTMLWithUI(u),
+		header:        NewMailHeader(u.EveImageService(), u.ShowEveEntityInfoWindow),
+		headers:       make([]*app.CharacterMailHeader, 0),
+		headerTop:     appwidget.MakeTopLabel(),
+		selected:      set.New[int32](),
+		selectionInfo: widget.NewLabel(""),
+		subject:       iwidget.NewLabelWithSize("", theme.SizeNameSubHeadingText),
+		thread:        container.NewVBox(),
+		threads:       make([]*app.CharacterMailThread, 0),
+		u:             u,
 	}
 	a.ExtendBaseWidget(a)
 
@@ -126,12 +142,31 @@ func NewMail(u app.UI) *Mails {
 	a.toolbar = a.makeToolbar()
 	a.toolbar.Hide()
 	a.subject.Truncation = fyne.TextTruncateClip
-	a.body.Wrapping = fyne.TextWrapWord
-	a.Detail = container.NewBorder(container.NewVBox(a.subject, a.header), nil, nil, nil, container.NewVScroll(a.body))
+	a.mailDetail = container.NewBorder(a.header, nil, nil, nil, container.NewVScroll(a.body))
+	a.threadDetail = container.NewVScroll(a.thread)
+	a.threadDetail.Hide()
+	a.Detail = container.NewBorder(a.subject, nil, nil, nil, container.NewStack(a.mailDetail, a.threadDetail))
 
 	// Headers
 	a.headerList = a.makeHeaderList()
-	a.Headers = container.NewBorder(a.headerTop, nil, nil, nil, a.headerList)
+	a.selectionBar = a.makeSelectionBar()
+	a.selectionBar.Hide()
+	selectMode := iwidget.NewIconButton(icons.ChecklistrtlSvg, func() {
+		a.setSelecting(!a.isSelecting)
+	})
+	a.threadMode = iwidget.NewIconButton(icons.MessageOutlineSvg, func() {
+		a.setShowThreads(!a.showThreads)
+	})
+	a.Headers = container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, nil, container.NewHBox(a.threadMode, selectMode), a.headerTop),
+			a.selectionBar,
+		),
+		nil,
+		nil,
+		nil,
+		a.headerList,
+	)
 
 	// Folders
 	a.folders = a.makeFolderTree()
@@ -147,9 +182,21 @@ func (a *Mails) CreateRenderer() fyne.WidgetRenderer {
 	compose := widget.NewButtonWithIcon("Compose", r, f)
 	compose.Importance = widget.HighImportance
 
+	manageLabels := widget.NewButton("Manage labels", func() {
+		a.showManageLabelsDialog()
+	})
+	drafts := widget.NewButton("Drafts & Outbox", func() {
+		a.showDraftsDialog()
+	})
+	rules := widget.NewButton("Mail rules", func() {
+		a.showMailRulesDialog()
+	})
+	export := widget.NewButton("Export", func() {
+		a.showExportDialog()
+	})
 	split2 := container.NewHSplit(container.NewBorder(
 		container.NewCenter(container.NewPadded(compose)),
-		nil,
+		container.NewCenter(container.NewVBox(drafts, manageLabels, rules, export)),
 		nil,
 		nil,
 		a.folders,
@@ -228,6 +275,15 @@ func (a *Mails) MakeFolderMenu() []*fyne.MenuItem {
 		// }
 		items1 = append(items1, it)
 	}
+	items1 = append(items1, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("Drafts & outbox...", func() {
+		a.showDraftsDialog()
+	}), fyne.NewMenuItem("Manage labels...", func() {
+		a.showManageLabelsDialog()
+	}), fyne.NewMenuItem("Mail rules...", func() {
+		a.showMailRulesDialog()
+	}), fyne.NewMenuItem("Export mails...", func() {
+		a.showExportDialog()
+	}))
 	return items1
 }
 
@@ -408,28 +464,62 @@ func calcUnreadTotals(labelCounts, listCounts map[int32]int) (int, int, int) {
 func (a *Mails) makeHeaderList() *widget.List {
 	l := widget.NewList(
 		func() int {
-			return len(a.headers)
+			return len(a.threads)
 		},
 		func() fyne.CanvasObject {
-			return NewMailHeaderItem(a.u.EveImageService())
+			check := widget.NewIcon(theme.CheckButtonIcon())
+			check.Hide()
+			return container.NewBorder(nil, nil, check, nil, NewMailHeaderItem(a.u.EveImageService()))
 		},
 		func(id widget.ListItemID, co fyne.CanvasObject) {
-			if id >= len(a.headers) {
+			if id >= len(a.threads) {
 				return
 			}
-			m := a.headers[id]
+			t := a.threads[id]
 			if !a.u.HasCharacter() {
 				return
 			}
-			item := co.(*MailHeaderItem)
-			item.Set(m.From, m.Subject, m.Timestamp, m.IsRead)
+			m := t.Latest()
+			subject := m.Subject
+			if n := len(t.Mails); n > 1 {
+				subject = fmt.Sprintf("%s (%d)", t.Subject, n)
+			}
+			c := co.(*fyne.Container).Objects
+			item := c[0].(*MailHeaderItem)
+			item.Set(m.From, subject, m.Timestamp, t.IsRead())
+			check := c[1].(*widget.Icon)
+			if a.isSelecting {
+				if set.NewFromSlice(t.MailIDs()).IsSubset(a.selected) {
+					check.SetResource(theme.CheckButtonCheckedIcon())
+				} else {
+					check.SetResource(theme.CheckButtonIcon())
+				}
+				check.Show()
+			} else {
+				check.Hide()
+			}
 		})
 	l.OnSelected = func(id widget.ListItemID) {
-		if id >= len(a.headers) {
+		if id >= len(a.threads) {
+			return
+		}
+		t := a.threads[id]
+		if a.isSelecting {
+			l.UnselectAll()
+			ids := set.NewFromSlice(t.MailIDs())
+			if ids.IsSubset(a.selected) {
+				a.selected = a.selected.Difference(ids)
+			} else {
+				a.selected = a.selected.Union(ids)
+			}
+			a.refreshSelection()
 			return
 		}
-		r := a.headers[id]
-		a.setMail(r.MailID)
+		if len(t.Mails) > 1 {
+			a.setThread(t)
+		} else {
+			a.setMail(t.Latest().MailID)
+		}
 		a.lastSelected = id
 		if a.OnSelected != nil {
 			a.OnSelected()
@@ -439,12 +529,119 @@ func (a *Mails) makeHeaderList() *widget.List {
 	return l
 }
 
+func (a *Mails) makeSelectionBar() *fyne.Container {
+	selectedIDs := func() []int32 {
+		return a.selected.ToSlice()
+	}
+	onSuccess := func() {
+		a.setSelecting(false)
+		a.update()
+	}
+	bar := container.NewBorder(
+		nil,
+		nil,
+		nil,
+		container.NewHBox(
+			iwidget.NewIconButton(theme.CheckButtonCheckedIcon(), func() {
+				for _, h := range a.headers {
+					a.selected.Add(h.MailID)
+				}
+				a.refreshSelection()
+			}),
+			iwidget.NewIconButton(theme.VisibilityIcon(), func() {
+				a.updateSelectedRead(selectedIDs(), true, onSuccess)
+			}),
+			iwidget.NewIconButton(theme.VisibilityOffIcon(), func() {
+				a.updateSelectedRead(selectedIDs(), false, onSuccess)
+			}),
+			iwidget.NewIconButton(theme.FolderIcon(), func() {
+				ids := selectedIDs()
+				if len(ids) == 0 {
+					return
+				}
+				a.showApplyLabelDialog(a.u.CurrentCharacterID(), ids, onSuccess)
+			}),
+			iwidget.NewIconButton(theme.DeleteIcon(), func() {
+				a.deleteSelected(selectedIDs(), onSuccess)
+			}),
+		),
+		a.selectionInfo,
+	)
+	return bar
+}
+
+// setSelecting enables or disables the selection mode for bulk operations on mails.
+func (a *Mails) setSelecting(enabled bool) {
+	a.isSelecting = enabled
+	a.selected.Clear()
+	if enabled {
+		a.selectionBar.Show()
+	} else {
+		a.selectionBar.Hide()
+	}
+	a.refreshSelection()
+}
+
+// setShowThreads enables or disables grouping of mails into conversations.
+func (a *Mails) setShowThreads(enabled bool) {
+	a.showThreads = enabled
+	if enabled {
+		a.threadMode.SetIcon(icons.MessageSvg)
+	} else {
+		a.threadMode.SetIcon(icons.MessageOutlineSvg)
+	}
+	a.setSelecting(false)
+	a.headerRefresh()
+	a.headerList.ScrollToTop()
+	a.headerList.UnselectAll()
+	a.clearMail()
+}
+
+func (a *Mails) refreshSelection() {
+	a.selectionInfo.SetText(fmt.Sprintf("%d selected", a.selected.Size()))
+	a.headerList.Refresh()
+}
+
+func (a *Mails) updateSelectedRead(mailIDs []int32, isRead bool, onSuccess func()) {
+	if len(mailIDs) == 0 {
+		return
+	}
+	characterID := a.u.CurrentCharacterID()
+	a.runMailOperation("Updating mails...", func() error {
+		return a.u.CharacterService().UpdateMailsRead(context.Background(), characterID, mailIDs, isRead)
+	}, onSuccess)
+}
+
+func (a *Mails) deleteSelected(mailIDs []int32, onSuccess func()) {
+	if len(mailIDs) == 0 {
+		return
+	}
+	characterID := a.u.CurrentCharacterID()
+	a.u.ShowConfirmDialog(
+		"Delete mails",
+		fmt.Sprintf("Are you sure you want to permanently delete %d mails?", len(mailIDs)),
+		"Delete",
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			a.runMailOperation("Deleting mails...", func() error {
+				return a.u.CharacterService().DeleteMails(context.Background(), characterID, mailIDs)
+			}, func() {
+				onSuccess()
+				a.clearMail()
+				a.u.ShowSnackbar(fmt.Sprintf("%d mails deleted", len(mailIDs)))
+			})
+		}, a.u.MainWindow())
+}
+
 func (a *Mails) ResetCurrentFolder() {
 	a.SetCurrentFolder(a.folderDefault)
 }
 
 func (a *Mails) SetCurrentFolder(folder FolderNode) {
 	a.CurrentFolder = optional.New(folder)
+	a.setSelecting(false)
 	a.headerRefresh()
 	a.headerList.ScrollToTop()
 	a.headerList.UnselectAll()
@@ -454,6 +651,7 @@ func (a *Mails) SetCurrentFolder(folder FolderNode) {
 func (a *Mails) clearFolder() {
 	a.CurrentFolder = optional.Optional[FolderNode]{}
 	a.headers = make([]*app.CharacterMailHeader, 0)
+	a.threads = make([]*app.CharacterMailThread, 0)
 	a.headerList.Refresh()
 	a.headerTop.SetText("")
 	a.clearMail()
@@ -483,25 +681,55 @@ func (a *Mails) updateHeaders() (FolderNode, error) {
 	}
 	folder := folderOption.ValueOrZero()
 	var headers []*app.CharacterMailHeader
+	var threads []*app.CharacterMailThread
 	var err error
 	switch folder.Category {
 	case nodeCategoryLabel:
-		headers, err = a.u.CharacterService().ListMailHeadersForLabelOrdered(
-			ctx,
-			folder.CharacterID,
-			folder.ObjID,
-		)
+		if a.showThreads {
+			threads, err = a.u.CharacterService().ListMailThreadsForLabelOrdered(
+				ctx,
+				folder.CharacterID,
+				folder.ObjID,
+			)
+		} else {
+			headers, err = a.u.CharacterService().ListMailHeadersForLabelOrdered(
+				ctx,
+				folder.CharacterID,
+				folder.ObjID,
+			)
+		}
 	case nodeCategoryList:
-		headers, err = a.u.CharacterService().ListMailHeadersForListOrdered(
-			ctx,
-			folder.CharacterID,
-			folder.ObjID,
-		)
+		if a.showThreads {
+			threads, err = a.u.CharacterService().ListMailThreadsForListOrdered(
+				ctx,
+				folder.CharacterID,
+				folder.ObjID,
+			)
+		} else {
+			headers, err = a.u.CharacterService().ListMailHeadersForListOrdered(
+				ctx,
+				folder.CharacterID,
+				folder.ObjID,
+			)
+		}
 	}
 	if err != nil {
 		return FolderNode{}, err
 	}
+	if a.showThreads {
+		headers = make([]*app.CharacterMailHeader, 0)
+		for _, t := range threads {
+			headers = append(headers, t.Mails...)
+		}
+	} else {
+		// show each mail as its own thread
+		threads = make([]*app.CharacterMailThread, len(headers))
+		for i, h := range headers {
+			threads[i] = &app.CharacterMailThread{Subject: h.Subject, Mails: []*app.CharacterMailHeader{h}}
+		}
+	}
 	a.headers = headers
+	a.threads = threads
 	a.headerList.Refresh()
 	if len(headers) == 0 {
 		a.clearMail()
@@ -519,6 +747,9 @@ func (a *Mails) makeFolderTopText(f FolderNode) (string, widget.Importance) {
 	}
 	p := message.NewPrinter(language.English)
 	s := p.Sprintf("%s • %d mails", f.Name, len(a.headers))
+	if a.showThreads {
+		s += p.Sprintf(" in %d conversations", len(a.threads))
+	}
 	return s, widget.MediumImportance
 }
 
@@ -591,6 +822,25 @@ func (a *Mails) MakeReplyAllAction() (fyne.Resource, func()) {
 	}
 }
 
+func (a *Mails) MakeLabelAction() (fyne.Resource, func()) {
+	return theme.FolderIcon(), func() {
+		a.showApplyLabelDialog(a.mail.CharacterID, []int32{a.mail.MailID}, func() {
+			a.update()
+		})
+	}
+}
+
+func (a *Mails) MakeMarkUnreadAction(onSuccess func()) (fyne.Resource, func()) {
+	return theme.VisibilityOffIcon(), func() {
+		a.updateSelectedRead([]int32{a.mail.MailID}, false, func() {
+			a.update()
+			if onSuccess != nil {
+				onSuccess()
+			}
+		})
+	}
+}
+
 func (a *Mails) makeToolbar() *widget.Toolbar {
 	toolbar := widget.NewToolbar(
 		widget.NewToolbarAction(a.MakeReplyAction()),
@@ -599,6 +849,9 @@ func (a *Mails) makeToolbar() *widget.Toolbar {
 		widget.NewToolbarAction(theme.ContentCopyIcon(), func() {
 			a.u.MainWindow().Clipboard().SetContent(a.mail.String())
 		}),
+		widget.NewToolbarSeparator(),
+		widget.NewToolbarAction(a.MakeMarkUnreadAction(nil)),
+		widget.NewToolbarAction(a.MakeLabelAction()),
 		widget.NewToolbarSpacer(),
 		widget.NewToolbarAction(a.MakeDeleteAction(nil)),
 	)
@@ -609,6 +862,9 @@ func (a *Mails) clearMail() {
 	a.subject.SetText("")
 	a.header.Clear()
 	a.body.SetText("")
+	a.thread.RemoveAll()
+	a.threadDetail.Hide()
+	a.mailDetail.Show()
 	a.toolbar.Hide()
 }
 
@@ -637,6 +893,60 @@ func (a *Mails) setMail(mailID int32) {
 	}
 	a.subject.SetText(a.mail.Subject)
 	a.header.Set(a.mail.From, a.mail.Timestamp, a.mail.Recipients...)
-	a.body.SetText(a.mail.BodyPlain())
+	a.body.SetText(a.mail.Body)
+	a.threadDetail.Hide()
+	a.mailDetail.Show()
+	a.toolbar.Show()
+}
+
+// setThread shows all mails of a conversation in chronological order.
+// The toolbar actions apply to the latest mail.
+func (a *Mails) setThread(t *app.CharacterMailThread) {
+	ctx := context.TODO()
+	characterID := a.u.CurrentCharacterID()
+	mails := make([]*app.CharacterMail, len(t.Mails))
+	for i, h := range t.Mails {
+		m, err := a.u.CharacterService().GetMail(ctx, characterID, h.MailID)
+		if err != nil {
+			slog.Error("Failed to fetch mail", "mailID", h.MailID, "error", err)
+			a.u.ShowSnackbar("ERROR: Failed to fetch mail")
+			return
+		}
+		mails[i] = m
+	}
+	a.mail = mails[len(mails)-1]
+	a.thread.RemoveAll()
+	unread := make([]int32, 0)
+	for i, m := range mails {
+		if i > 0 {
+			a.thread.Add(widget.NewSeparator())
+		}
+		header := NewMailHeader(a.u.EveImageService(), a.u.ShowEveEntityInfoWindow)
+		header.Set(m.From, m.Timestamp, m.Recipients...)
+		body := appwidget.NewEveHTMLWithUI(a.u)
+		body.SetText(m.Body)
+		a.thread.Add(header)
+		a.thread.Add(body)
+		if !m.IsRead {
+			unread = append(unread, m.MailID)
+		}
+	}
+	if !a.u.IsOffline() && len(unread) > 0 {
+		go func() {
+			err := a.u.CharacterService().UpdateMailsRead(ctx, characterID, unread, true)
+			if err != nil {
+				slog.Error("Failed to mark mails as read", "characterID", characterID, "mailIDs", unread, "error", err)
+				a.u.ShowSnackbar("ERROR: Failed to mark mails as read")
+				return
+			}
+			a.update()
+			a.u.UpdateCrossPages()
+			a.u.UpdateMailIndicator()
+		}()
+	}
+	a.subject.SetText(t.Subject)
+	a.mailDetail.Hide()
+	a.threadDetail.Show()
+	a.threadDetail.ScrollToTop()
 	a.toolbar.Show()
 }
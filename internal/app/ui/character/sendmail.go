@@ -2,9 +2,11 @@ package character
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -14,6 +16,8 @@ import (
 
 	"github.com/ErikKalkoken/evebuddy/internal/app"
 	"github.com/ErikKalkoken/evebuddy/internal/app/icons"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	"github.com/ErikKalkoken/evebuddy/internal/evehtml"
 	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
 )
 
@@ -22,16 +26,53 @@ const labelWith = 45
 type SendMail struct {
 	widget.BaseWidget
 
-	body      *widget.Entry
-	character *app.Character
-	from      *EveEntityEntry
-	subject   *widget.Entry
-	to        *EveEntityEntry
-	u         app.UI
-	w         fyne.Window
+	body          *widget.Entry
+	character     *app.Character
+	draftID       int64 // ID of the draft being edited or 0 for a new mail
+	formatBar     fyne.CanvasObject
+	from          *EveEntityEntry
+	preview       *appwidget.EveHTML
+	previewButton *iwidget.IconButton
+	quote         *widget.Label
+	quotedBody    string // original mail in Eve Online HTML when replying or forwarding
+	subject       *widget.Entry
+	to            *EveEntityEntry
+	u             app.UI
+	w             fyne.Window
 }
 
 func NewSendMail(u app.UI, c *app.Character, mode app.SendMailMode, m *app.CharacterMail) *SendMail {
+	a := newSendMail(u, c)
+	if m != nil {
+		switch mode {
+		case app.SendMailReply:
+			a.to.Set(m.ReplyRecipients(false))
+			a.subject.SetText(m.ReplySubject())
+		case app.SendMailReplyAll:
+			a.to.Set(m.ReplyRecipients(true))
+			a.subject.SetText(m.ReplySubject())
+		case app.SendMailForward:
+			a.subject.SetText(m.ForwardSubject())
+		default:
+			panic(fmt.Errorf("undefined mode for create message: %v", mode))
+		}
+		a.setQuotedBody(m.QuotedBody())
+	}
+	return a
+}
+
+// NewSendMailFromDraft returns a new SendMail for editing an existing draft.
+func NewSendMailFromDraft(u app.UI, c *app.Character, d *app.CharacterMailDraft) *SendMail {
+	a := newSendMail(u, c)
+	a.draftID = d.ID
+	a.to.Set(d.Recipients)
+	a.subject.SetText(d.Subject)
+	a.body.SetText(d.Body)
+	a.setQuotedBody(d.QuotedBody)
+	return a
+}
+
+func newSendMail(u app.UI, c *app.Character) *SendMail {
 	a := &SendMail{
 		character: c,
 		u:         u,
@@ -45,7 +86,7 @@ func NewSendMail(u app.UI, c *app.Character, mode app.SendMailMode, m *app.Chara
 	a.from.Disable()
 
 	toButton := widget.NewButton("To", func() {
-		showAddDialog(u, c.ID, func(ee *app.EveEntity) {
+		showAddDialog(u, c.ID, "Add Recipient", nil, func(ee *app.EveEntity) {
 			a.to.Add(ee)
 		}, a.w)
 	})
@@ -61,38 +102,24 @@ func NewSendMail(u app.UI, c *app.Character, mode app.SendMailMode, m *app.Chara
 	a.body.SetMinRowsVisible(14)
 	a.body.PlaceHolder = "Compose message"
 
-	if m != nil {
-		const sep = "\n\n--------------------------------\n"
-		switch mode {
-		case app.SendMailReply:
-			a.to.Set([]*app.EveEntity{m.From})
-			a.subject.SetText(fmt.Sprintf("Re: %s", m.Subject))
-			a.body.SetText(sep + m.String())
-		case app.SendMailReplyAll:
-			oo := slices.Concat([]*app.EveEntity{m.From}, m.Recipients)
-			oo = slices.DeleteFunc(oo, func(o *app.EveEntity) bool {
-				return o.ID == c.EveCharacter.ID
-			})
-			a.to.Set(oo)
-			a.subject.SetText(fmt.Sprintf("Re: %s", m.Subject))
-			a.body.SetText(sep + m.String())
-		case app.SendMailForward:
-			a.subject.SetText(fmt.Sprintf("Fw: %s", m.Subject))
-			a.body.SetText(sep + m.String())
-		default:
-			panic(fmt.Errorf("undefined mode for create message: %v", mode))
-		}
-	}
+	a.preview = appwidget.NewEveHTMLWithUI(u)
+	a.preview.Hide()
+	a.formatBar = a.makeFormatBar()
+
+	a.quote = widget.NewLabel("")
+	a.quote.Wrapping = fyne.TextWrapWord
+	a.quote.Importance = widget.LowImportance
+	a.quote.Hide()
 	return a
 }
 
 func (a *SendMail) CreateRenderer() fyne.WidgetRenderer {
 	c := container.NewBorder(
-		container.NewVBox(a.from, a.to, a.subject),
+		container.NewVBox(a.from, a.to, a.subject, a.formatBar),
 		nil,
 		nil,
 		nil,
-		a.body,
+		container.NewVScroll(container.NewVBox(a.body, a.preview, a.quote)),
 	)
 	return widget.NewSimpleRenderer(c)
 }
@@ -101,6 +128,41 @@ func (a *SendMail) SetWindow(w fyne.Window) {
 	a.w = w
 }
 
+func (a *SendMail) setQuotedBody(s string) {
+	a.quotedBody = s
+	if s == "" {
+		a.quote.Hide()
+		return
+	}
+	a.quote.SetText(strings.TrimSpace(evehtml.ToPlain(s)))
+	a.quote.Show()
+}
+
+// makeDraft returns the current mail as draft.
+func (a *SendMail) makeDraft(status app.CharacterMailDraftStatus) *app.CharacterMailDraft {
+	return &app.CharacterMailDraft{
+		ID:          a.draftID,
+		Body:        a.body.Text,
+		CharacterID: a.character.ID,
+		QuotedBody:  a.quotedBody,
+		Recipients:  a.to.Items(),
+		Status:      status,
+		Subject:     a.subject.Text,
+	}
+}
+
+// SaveDraftAction saves the current mail as draft and reports whether it was successful.
+func (a *SendMail) SaveDraftAction() bool {
+	id, err := a.u.CharacterService().SaveMailDraft(context.Background(), a.makeDraft(app.MailDraftSaved))
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to save draft", err, a.w)
+		return false
+	}
+	a.draftID = id
+	a.u.ShowSnackbar("Your mail has been saved as draft.")
+	return true
+}
+
 // sendAction tries to send the current mail and reports whether it was successful
 func (a *SendMail) SendAction() bool {
 	showErrorDialog := func(message string) {
@@ -114,22 +176,37 @@ func (a *SendMail) SendAction() bool {
 		showErrorDialog("The subject can not be empty.")
 		return false
 	}
-	if a.body.Text == "" {
+	if a.body.Text == "" && a.quotedBody == "" {
 		showErrorDialog("The message can not be empty.")
 		return false
 	}
 	ctx := context.Background()
-	_, err := a.u.CharacterService().SendMail(
-		ctx,
-		a.character.ID,
-		a.subject.Text,
-		a.to.Items(),
-		a.body.Text,
-	)
+	d := a.makeDraft(app.MailDraftQueued)
+	if a.u.IsOffline() {
+		if _, err := a.u.CharacterService().SaveMailDraft(ctx, d); err != nil {
+			showErrorDialog(err.Error())
+			return false
+		}
+		a.u.ShowSnackbar(fmt.Sprintf("Your mail to %s has been queued in the outbox and will be sent when online.", a.to))
+		return true
+	}
+	isSent, err := a.u.CharacterService().SendMailDraft(ctx, d)
+	if errors.Is(err, app.ErrMailDraftUnconfirmed) {
+		a.u.ShowSnackbar(fmt.Sprintf("Your mail to %s might have been sent. Please check your sent mails. It was kept in the drafts.", a.to))
+		return true
+	}
+	if errors.Is(err, app.ErrMailDraftSending) {
+		showErrorDialog("This mail is already being sent from the outbox. Please check your sent mails.")
+		return false
+	}
 	if err != nil {
 		showErrorDialog(err.Error())
 		return false
 	}
+	if !isSent {
+		a.u.ShowSnackbar(fmt.Sprintf("ESI not reachable. Your mail to %s has been queued in the outbox.", a.to))
+		return true
+	}
 	a.u.ShowSnackbar(fmt.Sprintf("Your mail to %s has been sent.", a.to))
 	return true
 }
@@ -144,9 +221,28 @@ func (a *SendMail) SendAction() bool {
 
 // }
 
-func showAddDialog(u app.UI, characterID int32, onSelected func(ee *app.EveEntity), w fyne.Window) {
+// showAddDialog shows a dialog for searching and selecting an Eve entity.
+// Only entities of the given categories are shown.
+// When no categories are given it shows entities which can receive mails.
+func showAddDialog(u app.UI, characterID int32, title string, categories []app.EveEntityCategory, onSelected func(ee *app.EveEntity), w fyne.Window) {
 	var modal *widget.PopUp
 	results := make([]*app.EveEntity, 0)
+	isShown := func(ee *app.EveEntity) bool {
+		if len(categories) == 0 {
+			return ee.CanReceiveMail()
+		}
+		return slices.Contains(categories, ee.Category)
+	}
+	listEntities := func(ctx context.Context, search string) ([]*app.EveEntity, error) {
+		ee, err := u.EveUniverseService().ListEntitiesByPartialName(ctx, search)
+		if err != nil {
+			return nil, err
+		}
+		ee = slices.DeleteFunc(ee, func(x *app.EveEntity) bool {
+			return !isShown(x)
+		})
+		return ee, nil
+	}
 	fallbackIcon := icons.Questionmark32Png
 	list := widget.NewList(
 		func() int {
@@ -209,7 +305,7 @@ func showAddDialog(u app.UI, characterID int32, onSelected func(ee *app.EveEntit
 		}
 		go func() {
 			var err error
-			results, err = u.EveUniverseService().ListEntitiesByPartialName(context.Background(), search)
+			results, err = listEntities(context.Background(), search)
 			if err != nil {
 				showErrorDialog(search, err)
 				return
@@ -222,6 +318,7 @@ func showAddDialog(u app.UI, characterID int32, onSelected func(ee *app.EveEntit
 				ctx,
 				characterID,
 				search,
+				categories...,
 			)
 			if err != nil {
 				showErrorDialog(search, err)
@@ -230,7 +327,7 @@ func showAddDialog(u app.UI, characterID int32, onSelected func(ee *app.EveEntit
 			if len(missingIDs) == 0 {
 				return // no need to update when not changed
 			}
-			results, err = u.EveUniverseService().ListEntitiesByPartialName(ctx, search)
+			results, err = listEntities(ctx, search)
 			if err != nil {
 				showErrorDialog(search, err)
 				return
@@ -240,7 +337,7 @@ func showAddDialog(u app.UI, characterID int32, onSelected func(ee *app.EveEntit
 	}
 	c := container.NewBorder(
 		container.NewBorder(
-			widget.NewLabel("Add Recipient"),
+			widget.NewLabel(title),
 			nil,
 			nil,
 			widget.NewButton("Cancel", func() {
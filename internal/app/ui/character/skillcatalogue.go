@@ -2,11 +2,14 @@ package character
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/ErikKalkoken/evebuddy/internal/app"
@@ -33,14 +36,28 @@ type skillTrained struct {
 	groupName    string
 	id           int32
 	name         string
+	skillPoints  int
+	trainable    *app.TrainableSkill // nil when unknown
 	trainedLevel int
 }
 
+// trainingTime returns the time needed to train a skill to the max level with the given attributes
+// and reports whether it is known.
+func (s skillTrained) trainingTime(a app.TrainingAttributes) (time.Duration, bool) {
+	if s.trainable == nil || s.trainedLevel == app.SkillLevelMax {
+		return 0, false
+	}
+	d := s.trainable.TrainingTime(a, s.skillPoints, app.SkillLevelMax)
+	return d, d > 0
+}
+
 type SkillCatalogue struct {
 	widget.BaseWidget
 
+	attributes     *app.CharacterTrainingAttributes // nil when unknown
 	groups         []skillGroupProgress
 	groupsGrid     fyne.CanvasObject
+	implants       *widget.Select
 	levelBlocked   *theme.ErrorThemedResource
 	levelTrained   *theme.PrimaryThemedResource
 	levelUnTrained *theme.DisabledResource
@@ -63,11 +80,22 @@ func NewSkillCatalogue(u app.UI) *SkillCatalogue {
 	a.ExtendBaseWidget(a)
 	a.groupsGrid = a.makeGroupsGrid()
 	a.skillsGrid = a.makeSkillsGrid()
+	a.implants = widget.NewSelect(implantOptionLabels(), func(string) {
+		a.skillsGrid.Refresh()
+	})
+	a.implants.SetSelectedIndex(0)
 	return a
 }
 
 func (a *SkillCatalogue) CreateRenderer() fyne.WidgetRenderer {
-	s := container.NewVSplit(a.groupsGrid, a.skillsGrid)
+	skills := container.NewBorder(
+		container.NewHBox(layout.NewSpacer(), widget.NewLabel("Training time to V with"), a.implants),
+		nil,
+		nil,
+		nil,
+		a.skillsGrid,
+	)
+	s := container.NewVSplit(a.groupsGrid, skills)
 	c := container.NewBorder(a.total, nil, nil, nil, s)
 	return widget.NewSimpleRenderer(c)
 }
@@ -131,12 +159,18 @@ func (a *SkillCatalogue) makeGroupsGrid() fyne.CanvasObject {
 			}
 			skills := make([]skillTrained, len(oo))
 			for i, o := range oo {
+				ts, err := a.u.CharacterService().GetTrainableSkill(context.TODO(), o.TypeID)
+				if err != nil && !errors.Is(err, app.ErrNotFound) {
+					slog.Error("Failed to fetch trainable skill", "typeID", o.TypeID, "err", err)
+				}
 				skills[i] = skillTrained{
 					activeLevel:  o.ActiveSkillLevel,
 					description:  o.TypeDescription,
 					groupName:    group.name,
 					id:           o.TypeID,
 					name:         o.TypeName,
+					skillPoints:  o.SkillPointsInSkill,
+					trainable:    ts,
 					trainedLevel: o.TrainedSkillLevel,
 				}
 			}
@@ -159,7 +193,7 @@ func (a *SkillCatalogue) makeSkillsGrid() fyne.CanvasObject {
 				nil,
 				nil,
 				appwidget.NewSkillLevel(),
-				nil,
+				widget.NewLabel("9w 9d 99h"),
 				title,
 			)
 			return c
@@ -175,6 +209,15 @@ func (a *SkillCatalogue) makeSkillsGrid() fyne.CanvasObject {
 		label.SetText(skill.name)
 		level := row[1].(*appwidget.SkillLevel)
 		level.Set(skill.activeLevel, skill.trainedLevel, 0)
+		duration := row[2].(*widget.Label)
+		var s string
+		if a.attributes != nil {
+			attributes := trainingAttributesForImplants(a.attributes, a.implants.SelectedIndex())
+			if d, ok := skill.trainingTime(attributes); ok {
+				s = ihumanize.Duration(d)
+			}
+		}
+		duration.SetText(s)
 	}
 	makeOnSelected := func(unselectAll func()) func(int) {
 		unselectAll()
@@ -236,6 +279,11 @@ func (a *SkillCatalogue) updateGroups() error {
 	if !a.u.HasCharacter() {
 		return nil
 	}
+	ta, err := a.u.CharacterService().GetTrainingAttributes(context.TODO(), a.u.CurrentCharacterID())
+	if err != nil && !errors.Is(err, app.ErrNotFound) {
+		return err
+	}
+	a.attributes = ta // nil when the attributes have not been loaded yet
 	gg, err := a.u.CharacterService().ListSkillGroupsProgress(context.TODO(), a.u.CurrentCharacterID())
 	if err != nil {
 		return err
@@ -0,0 +1,289 @@
+package character
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	ihumanize "github.com/ErikKalkoken/evebuddy/internal/humanize"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+const (
+	injectorTargetSP     = "Skill points"
+	injectorTargetSkills = "Skills to use a type"
+)
+
+// SkillInjectors is a calculator for the skill injectors and extractors of the current character.
+type SkillInjectors struct {
+	widget.BaseWidget
+
+	extractorResult *widget.Label
+	injectorResult  *widget.Label
+	keepType        *widget.Entry
+	prices          *widget.Label
+	target          *widget.Entry
+	targetMode      *widget.Select
+	top             *widget.Label
+	u               app.UI
+}
+
+func NewSkillInjectors(u app.UI) *SkillInjectors {
+	a := &SkillInjectors{
+		extractorResult: widget.NewLabel(""),
+		injectorResult:  widget.NewLabel(""),
+		keepType:        widget.NewEntry(),
+		prices:          widget.NewLabel(""),
+		target:          widget.NewEntry(),
+		top:             appwidget.MakeTopLabel(),
+		u:               u,
+	}
+	a.ExtendBaseWidget(a)
+	a.extractorResult.Wrapping = fyne.TextWrapWord
+	a.injectorResult.Wrapping = fyne.TextWrapWord
+	a.prices.Importance = widget.LowImportance
+	a.keepType.SetPlaceHolder("Exact name of a type, e.g. Tengu (optional)")
+	a.keepType.OnSubmitted = func(string) {
+		a.calcExtractors()
+	}
+	a.target.OnSubmitted = func(string) {
+		a.calcInjectors()
+	}
+	a.targetMode = widget.NewSelect([]string{injectorTargetSP, injectorTargetSkills}, func(s string) {
+		if s == injectorTargetSkills {
+			a.target.SetPlaceHolder("Exact name of a type, e.g. Heavy Assault Missile Launcher II")
+		} else {
+			a.target.SetPlaceHolder("Target total skill points, e.g. 10000000")
+		}
+	})
+	a.targetMode.SetSelected(injectorTargetSP)
+	return a
+}
+
+func (a *SkillInjectors) CreateRenderer() fyne.WidgetRenderer {
+	injectors := widget.NewForm(
+		widget.NewFormItem("Target", a.targetMode),
+		widget.NewFormItem("", a.target),
+	)
+	injectors.SubmitText = "Calculate"
+	injectors.OnSubmit = a.calcInjectors
+	extractors := widget.NewForm(
+		widget.NewFormItem("Keep skills for", a.keepType),
+	)
+	extractors.SubmitText = "Calculate"
+	extractors.OnSubmit = a.calcExtractors
+	c := container.NewBorder(
+		a.top,
+		a.prices,
+		nil,
+		nil,
+		container.NewVScroll(container.NewVBox(
+			widget.NewCard("Skill injectors", "How many injectors are needed to reach a target", container.NewVBox(
+				injectors,
+				a.injectorResult,
+			)),
+			widget.NewCard("Skill extractors", "How many extractors can be used while keeping skills", container.NewVBox(
+				extractors,
+				a.extractorResult,
+			)),
+		)),
+	)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *SkillInjectors) Update() {
+	t, i := func() (string, widget.Importance) {
+		c := a.u.CurrentCharacter()
+		if c == nil {
+			return "No character", widget.LowImportance
+		}
+		if c.TotalSP.IsEmpty() {
+			return "Waiting for character data to be loaded...", widget.WarningImportance
+		}
+		s := fmt.Sprintf(
+			"%s SP • %s unallocated SP • %d extractors possible",
+			humanize.Comma(int64(c.TotalSP.ValueOrZero())),
+			ihumanize.OptionalComma(c.UnallocatedSP, "?"),
+			app.CalcSkillExtractors(c.TotalSP.ValueOrZero(), 0),
+		)
+		return s, widget.MediumImportance
+	}()
+	a.top.Text = t
+	a.top.Importance = i
+	a.top.Refresh()
+	a.injectorResult.SetText("")
+	a.extractorResult.SetText("")
+	a.prices.SetText(fmt.Sprintf(
+		"Average market prices: large injector %s • small injector %s • extractor %s",
+		formatISK(a.marketPrice(app.EveTypeLargeSkillInjector)),
+		formatISK(a.marketPrice(app.EveTypeSmallSkillInjector)),
+		formatISK(a.marketPrice(app.EveTypeSkillExtractor)),
+	))
+}
+
+func (a *SkillInjectors) calcInjectors() {
+	c := a.u.CurrentCharacter()
+	if c == nil || c.TotalSP.IsEmpty() {
+		return
+	}
+	input := strings.TrimSpace(a.target.Text)
+	if input == "" {
+		return
+	}
+	currentSP := c.TotalSP.ValueOrZero() + c.UnallocatedSP.ValueOrZero()
+	calc := func() (string, error) {
+		var targetSP int
+		var s string
+		if a.targetMode.Selected == injectorTargetSkills {
+			name, requirements, err := a.requiredSkills(input)
+			if err != nil {
+				return "", err
+			}
+			x, err := a.u.CharacterService().CalcSkillRequirementsSP(context.Background(), c.ID, requirements)
+			if err != nil {
+				return "", err
+			}
+			missing := max(0, x.Missing-c.UnallocatedSP.ValueOrZero())
+			targetSP = currentSP + missing
+			s = fmt.Sprintf("%s SP missing for %s after allocating unallocated SP.\n", humanize.Comma(int64(missing)), name)
+		} else {
+			v, err := strconv.Atoi(strings.ReplaceAll(input, ",", ""))
+			if err != nil {
+				return "", fmt.Errorf("invalid number of skill points: %s: %w", input, app.ErrInvalid)
+			}
+			targetSP = v
+		}
+		r := app.CalcSkillInjectors(currentSP, targetSP)
+		if r.Large == 0 && r.Small == 0 {
+			return s + "No injectors needed.", nil
+		}
+		large := a.marketPrice(app.EveTypeLargeSkillInjector)
+		small := a.marketPrice(app.EveTypeSmallSkillInjector)
+		var cost optional.Optional[float64]
+		if !large.IsEmpty() && !small.IsEmpty() {
+			cost.Set(float64(r.Large)*large.ValueOrZero() + float64(r.Small)*small.ValueOrZero())
+		}
+		s += fmt.Sprintf(
+			"%d large and %d small skill injectors needed.\nTotal SP afterwards: %s\nCost: %s",
+			r.Large,
+			r.Small,
+			humanize.Comma(int64(r.FinalSP)),
+			formatISK(cost),
+		)
+		return s, nil
+	}
+	a.injectorResult.SetText("Calculating...")
+	go func() {
+		s, err := calc()
+		if err != nil {
+			slog.Error("Failed to calculate skill injectors", "characterID", c.ID, "err", err)
+			s = a.u.ErrorDisplay(err)
+		}
+		a.injectorResult.SetText(s)
+	}()
+}
+
+func (a *SkillInjectors) calcExtractors() {
+	c := a.u.CurrentCharacter()
+	if c == nil || c.TotalSP.IsEmpty() {
+		return
+	}
+	input := strings.TrimSpace(a.keepType.Text)
+	calc := func() (string, error) {
+		var keepSP int
+		var s string
+		if input != "" {
+			name, requirements, err := a.requiredSkills(input)
+			if err != nil {
+				return "", err
+			}
+			x, err := a.u.CharacterService().CalcSkillRequirementsSP(context.Background(), c.ID, requirements)
+			if err != nil {
+				return "", err
+			}
+			keepSP = x.Trained
+			s = fmt.Sprintf("Keeping %s SP in skills for %s.\n", humanize.Comma(int64(keepSP)), name)
+		}
+		n := app.CalcSkillExtractors(c.TotalSP.ValueOrZero(), keepSP)
+		if n == 0 {
+			return s + "No extractors can be used.", nil
+		}
+		var cost, value optional.Optional[float64]
+		if x := a.marketPrice(app.EveTypeSkillExtractor); !x.IsEmpty() {
+			cost.Set(float64(n) * x.ValueOrZero())
+		}
+		if x := a.marketPrice(app.EveTypeLargeSkillInjector); !x.IsEmpty() {
+			value.Set(float64(n) * x.ValueOrZero())
+		}
+		s += fmt.Sprintf(
+			"%d extractors can be used, extracting %s SP.\nCost of extractors: %s\nValue of resulting large injectors: %s",
+			n,
+			humanize.Comma(int64(n*app.SkillExtractorSP)),
+			formatISK(cost),
+			formatISK(value),
+		)
+		return s, nil
+	}
+	a.extractorResult.SetText("Calculating...")
+	go func() {
+		s, err := calc()
+		if err != nil {
+			slog.Error("Failed to calculate skill extractors", "characterID", c.ID, "err", err)
+			s = a.u.ErrorDisplay(err)
+		}
+		a.extractorResult.SetText(s)
+	}()
+}
+
+// requiredSkills returns the name and the required skills of a type identified by its exact name.
+func (a *SkillInjectors) requiredSkills(name string) (string, []app.SkillRequirement, error) {
+	ctx := context.Background()
+	r, _, err := a.u.CharacterService().SearchESI(
+		ctx,
+		a.u.CurrentCharacterID(),
+		name,
+		[]app.SearchCategory{app.SearchType},
+		true,
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	types := r[app.SearchType]
+	if len(types) == 0 {
+		return "", nil, fmt.Errorf("no type found with the name \"%s\": %w", name, app.ErrNotFound)
+	}
+	requirements, err := a.u.CharacterService().ListRequiredSkills(ctx, types[0].ID)
+	if err != nil {
+		return "", nil, err
+	}
+	return types[0].Name, requirements, nil
+}
+
+func (a *SkillInjectors) marketPrice(typeID int32) optional.Optional[float64] {
+	var v optional.Optional[float64]
+	p, err := a.u.EveUniverseService().GetMarketPrice(context.Background(), typeID)
+	if err != nil {
+		if !errors.Is(err, app.ErrNotFound) {
+			slog.Error("Failed to load market price", "typeID", typeID, "err", err)
+		}
+		return v
+	}
+	return optional.New(p.AveragePrice)
+}
+
+func formatISK(v optional.Optional[float64]) string {
+	if v.IsEmpty() {
+		return "?"
+	}
+	return ihumanize.Number(v.ValueOrZero(), 1) + " ISK"
+}
@@ -0,0 +1,156 @@
+package character
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/golang/freetype/truetype"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/chartbuilder"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	ihumanize "github.com/ErikKalkoken/evebuddy/internal/humanize"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+const (
+	skillPointChartWidth  = 600
+	skillPointChartHeight = 300
+)
+
+var skillPointHistoryPeriods = map[string]time.Duration{
+	"Last 30 days":  30 * 24 * time.Hour,
+	"Last 90 days":  90 * 24 * time.Hour,
+	"Last 365 days": 365 * 24 * time.Hour,
+}
+
+// SkillPointHistory shows how the skill points of the current character developed over time.
+type SkillPointHistory struct {
+	widget.BaseWidget
+
+	chart  *fyne.Container
+	idle   *widget.Label
+	period *widget.Select
+	rates  *widget.Label
+	top    *widget.Label
+	u      app.UI
+}
+
+func NewSkillPointHistory(u app.UI) *SkillPointHistory {
+	a := &SkillPointHistory{
+		chart: container.NewStack(),
+		idle:  widget.NewLabel(""),
+		rates: widget.NewLabel(""),
+		top:   appwidget.MakeTopLabel(),
+		u:     u,
+	}
+	a.ExtendBaseWidget(a)
+	a.idle.Wrapping = fyne.TextWrapWord
+	a.period = widget.NewSelect([]string{"Last 30 days", "Last 90 days", "Last 365 days"}, func(string) {
+		a.Update()
+	})
+	a.period.Selected = "Last 30 days"
+	return a
+}
+
+func (a *SkillPointHistory) CreateRenderer() fyne.WidgetRenderer {
+	c := container.NewBorder(
+		container.NewBorder(nil, nil, nil, a.period, a.top),
+		nil,
+		nil,
+		nil,
+		container.NewVScroll(container.NewVBox(
+			a.chart,
+			a.rates,
+			widget.NewLabelWithStyle("Days not training", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			a.idle,
+		)),
+	)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *SkillPointHistory) Update() {
+	var h *app.CharacterSkillPointHistory
+	t, i, err := func() (string, widget.Importance, error) {
+		if !a.u.HasCharacter() {
+			return "No character", widget.LowImportance, nil
+		}
+		since := time.Now().Add(-skillPointHistoryPeriods[a.period.Selected])
+		var err error
+		h, err = a.u.CharacterService().GetSkillPointHistory(context.TODO(), a.u.CurrentCharacterID(), since)
+		if err != nil {
+			return "", 0, err
+		}
+		if len(h.Days) == 0 {
+			return "No skill point history recorded yet", widget.LowImportance, nil
+		}
+		s := fmt.Sprintf(
+			"%d days recorded • %d days not training • %s SP lost",
+			len(h.Days),
+			h.IdleDays(),
+			ihumanize.Optional(h.LostSP(), "?"),
+		)
+		if h.IdleDays() > 0 {
+			return s, widget.WarningImportance, nil
+		}
+		return s, widget.MediumImportance, nil
+	}()
+	if err != nil {
+		slog.Error("Failed to refresh skill point history UI", "err", err)
+		t = "ERROR"
+		i = widget.DangerImportance
+	}
+	a.top.Text = t
+	a.top.Importance = i
+	a.top.Refresh()
+	if h == nil {
+		h = &app.CharacterSkillPointHistory{}
+	}
+	a.updateChart(h)
+	a.rates.SetText(fmt.Sprintf(
+		"Actual rate for period: %s SP/h\nTheoretical rate for active queue: %s SP/h",
+		formatSkillPointRate(h.ActualRate),
+		formatSkillPointRate(h.TheoreticalRate),
+	))
+	idle := make([]string, 0)
+	for _, d := range h.Days {
+		if d.IsIdle {
+			idle = append(idle, d.Date.Format("2006-01-02 (Mon)"))
+		}
+	}
+	if len(idle) == 0 {
+		a.idle.SetText("None")
+	} else {
+		a.idle.SetText(strings.Join(idle, "\n"))
+	}
+}
+
+func (a *SkillPointHistory) updateChart(h *app.CharacterSkillPointHistory) {
+	cb := chartbuilder.New(a.u.MainWindow())
+	cb.ForegroundColor = theme.Color(theme.ColorNameForeground)
+	cb.BackgroundColor = theme.Color(theme.ColorNameBackground)
+	font, err := truetype.Parse(theme.DefaultTextFont().Content())
+	if err != nil {
+		slog.Error("Failed to initialize TTF", "error", err)
+	} else {
+		cb.Font = font
+	}
+	values := make([]chartbuilder.Value, len(h.Days))
+	for i, d := range h.Days {
+		values[i] = chartbuilder.Value{Label: d.Date.Format("2006-01-02"), Value: float64(d.TotalSP)}
+	}
+	size := fyne.NewSize(skillPointChartWidth, skillPointChartHeight)
+	a.chart.Objects = []fyne.CanvasObject{cb.Render(chartbuilder.Line, size, "Total Skill Points", values)}
+	a.chart.Refresh()
+}
+
+func formatSkillPointRate(v optional.Optional[float64]) string {
+	return ihumanize.OptionalFloat(v, 0, "?")
+}
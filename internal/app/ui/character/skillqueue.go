@@ -1,6 +1,8 @@
 package character
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -135,18 +137,46 @@ func (a *SkillQueue) Update() {
 		if a.OnUpdate != nil {
 			a.OnUpdate(s1, s2)
 		}
-		var total optional.Optional[time.Duration]
+		var total, withImplants optional.Optional[time.Duration]
 		if isActive {
 			total = a.sq.Remaining()
+			withImplants = a.calcTrainingTimeWithImplants()
 		}
-		t, i = a.makeTopText(total)
+		t, i = a.makeTopText(total, withImplants)
 	}
 	a.top.Text = t
 	a.top.Importance = i
 	a.top.Refresh()
 }
 
-func (a *SkillQueue) makeTopText(total optional.Optional[time.Duration]) (string, widget.Importance) {
+// calcTrainingTimeWithImplants returns the estimated training time for the skill queue with +5 implants.
+// Returns an empty value when the character has those implants already or the estimate is not available.
+func (a *SkillQueue) calcTrainingTimeWithImplants() optional.Optional[time.Duration] {
+	var r optional.Optional[time.Duration]
+	ctx := context.TODO()
+	characterID := a.u.CurrentCharacterID()
+	ta, err := a.u.CharacterService().GetTrainingAttributes(ctx, characterID)
+	if err != nil {
+		if !errors.Is(err, app.ErrNotFound) {
+			slog.Error("Failed to fetch training attributes", "characterID", characterID, "err", err)
+		}
+		return r
+	}
+	bonuses := app.NewTrainingAttributes(5)
+	if ta.Implants == bonuses {
+		return r
+	}
+	d, err := a.u.CharacterService().CalcSkillqueueTrainingTime(ctx, characterID, ta.WithImplants(bonuses))
+	if err != nil {
+		if !errors.Is(err, app.ErrNotFound) {
+			slog.Error("Failed to estimate skill queue training time", "characterID", characterID, "err", err)
+		}
+		return r
+	}
+	return optional.New(d)
+}
+
+func (a *SkillQueue) makeTopText(total, withImplants optional.Optional[time.Duration]) (string, widget.Importance) {
 	hasData := a.u.StatusCacheService().CharacterSectionExists(a.u.CurrentCharacterID(), app.SectionSkillqueue)
 	if !hasData {
 		return "Waiting for character data to be loaded...", widget.WarningImportance
@@ -155,6 +185,9 @@ func (a *SkillQueue) makeTopText(total optional.Optional[time.Duration]) (string
 		return "Training not active", widget.WarningImportance
 	}
 	t := fmt.Sprintf("Total training time: %s", ihumanize.Optional(total, "?"))
+	if !withImplants.IsEmpty() {
+		t += fmt.Sprintf(" (with +5 implants: %s)", ihumanize.Duration(withImplants.ValueOrZero()))
+	}
 	return t, widget.MediumImportance
 }
 
@@ -0,0 +1,49 @@
+package character
+
+import (
+	"fmt"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/optional"
+)
+
+// implantOption is an implant set training times can be estimated for.
+type implantOption struct {
+	label string
+	bonus optional.Optional[int] // attribute bonus of the implants or empty for the current implants
+}
+
+// implantOptions are the implant sets training times can be estimated for.
+var implantOptions = func() []implantOption {
+	oo := []implantOption{
+		{label: "Current implants"},
+		{label: "No implants", bonus: optional.New(0)},
+	}
+	for v := 1; v <= 5; v++ {
+		oo = append(oo, implantOption{label: fmt.Sprintf("+%d implants", v), bonus: optional.New(v)})
+	}
+	return oo
+}()
+
+// implantOptionLabels returns the labels of [implantOptions].
+func implantOptionLabels() []string {
+	labels := make([]string, len(implantOptions))
+	for i, o := range implantOptions {
+		labels[i] = o.label
+	}
+	return labels
+}
+
+// trainingAttributesForImplants returns the training attributes of a character
+// with the implant set of the option at index in [implantOptions].
+// Returns the current attributes when no option is selected.
+func trainingAttributesForImplants(ta *app.CharacterTrainingAttributes, index int) app.TrainingAttributes {
+	if index < 0 || index >= len(implantOptions) {
+		return ta.Current
+	}
+	o := implantOptions[index]
+	if o.bonus.IsEmpty() {
+		return ta.Current
+	}
+	return ta.WithImplants(app.NewTrainingAttributes(o.bonus.ValueOrZero()))
+}
@@ -0,0 +1,351 @@
+package characteroverview
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+type communicationRow struct {
+	characterIDs   []int32
+	characterNames string
+	group          app.NotificationGroup
+	isRead         bool
+	notification   *app.CollapsedNotification
+	searchText     string // lower case text for searching
+	sender         string
+	timestamp      string
+	title          string
+}
+
+// Communications is an inbox showing the communications of all characters.
+// The same communication received by several characters is shown only once.
+type Communications struct {
+	widget.BaseWidget
+
+	OnUpdate func(unread int)
+
+	body            fyne.CanvasObject
+	characterIDs    map[string]int32
+	characterSelect *widget.Select
+	found           *widget.Label
+	groupSelect     *widget.Select
+	markProcessed   *widget.Button
+	rows            []communicationRow
+	rowsFiltered    []communicationRow
+	search          *widget.Entry
+	top             *widget.Label
+	typeSelect      *widget.Select
+	u               app.UI
+	unreadCheck     *widget.Check
+}
+
+func NewCommunications(u app.UI) *Communications {
+	a := &Communications{
+		characterIDs: make(map[string]int32),
+		found:        widget.NewLabel(""),
+		rows:         make([]communicationRow, 0),
+		rowsFiltered: make([]communicationRow, 0),
+		search:       widget.NewEntry(),
+		top:          appwidget.MakeTopLabel(),
+		u:            u,
+	}
+	a.ExtendBaseWidget(a)
+	a.search.PlaceHolder = "Search title and body"
+	a.search.ActionItem = iwidget.NewIconButton(theme.CancelIcon(), func() {
+		a.search.SetText("")
+	})
+	a.search.OnChanged = func(_ string) {
+		a.filterRows()
+	}
+	a.groupSelect = widget.NewSelect([]string{}, func(_ string) {
+		a.filterRows()
+	})
+	a.groupSelect.PlaceHolder = "(Group)"
+	a.typeSelect = widget.NewSelect([]string{}, func(_ string) {
+		a.filterRows()
+	})
+	a.typeSelect.PlaceHolder = "(Type)"
+	a.characterSelect = widget.NewSelect([]string{}, func(_ string) {
+		a.filterRows()
+	})
+	a.characterSelect.PlaceHolder = "(Character)"
+	a.unreadCheck = widget.NewCheck("Unread", func(_ bool) {
+		a.filterRows()
+	})
+	a.markProcessed = widget.NewButtonWithIcon("Mark processed", theme.ConfirmIcon(), func() {
+		a.markShownAsProcessed()
+	})
+	a.found.Hide()
+
+	headers := []iwidget.HeaderDef{
+		{Text: "Date", Width: 150},
+		{Text: "Group", Width: 150},
+		{Text: "Title", Width: 350},
+		{Text: "From", Width: 200},
+		{Text: "Characters", Width: characterColumnWidth},
+	}
+	makeCell := func(col int, r communicationRow) []widget.RichTextSegment {
+		style := widget.RichTextStyle{TextStyle: fyne.TextStyle{Bold: !r.isRead}}
+		switch col {
+		case 0:
+			return iwidget.NewRichTextSegmentFromText(r.timestamp, style)
+		case 1:
+			return iwidget.NewRichTextSegmentFromText(r.group.String(), style)
+		case 2:
+			return iwidget.NewRichTextSegmentFromText(r.title, style)
+		case 3:
+			return iwidget.NewRichTextSegmentFromText(r.sender, style)
+		case 4:
+			return iwidget.NewRichTextSegmentFromText(r.characterNames, style)
+		}
+		return iwidget.NewRichTextSegmentFromText("?")
+	}
+	if a.u.IsDesktop() {
+		a.body = iwidget.MakeDataTableForDesktop2(headers, &a.rowsFiltered, makeCell, func(_ int, r communicationRow) {
+			a.showCommunicationDialog(r)
+		})
+	} else {
+		a.body = iwidget.MakeDataTableForMobile2(headers, &a.rowsFiltered, makeCell, func(r communicationRow) {
+			a.showCommunicationDialog(r)
+		})
+	}
+	return a
+}
+
+func (a *Communications) CreateRenderer() fyne.WidgetRenderer {
+	top := container.NewHBox(
+		a.top,
+		a.found,
+		layout.NewSpacer(),
+		a.markProcessed,
+		widget.NewButton("Reset", func() {
+			a.reset()
+		}),
+	)
+	topBox := container.NewVBox(
+		top,
+		container.NewBorder(
+			nil,
+			nil,
+			nil,
+			container.NewHBox(a.groupSelect, a.typeSelect, a.characterSelect, a.unreadCheck),
+			a.search,
+		),
+	)
+	c := container.NewBorder(topBox, nil, nil, nil, a.body)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *Communications) reset() {
+	a.search.SetText("")
+	a.groupSelect.ClearSelected()
+	a.typeSelect.ClearSelected()
+	a.characterSelect.ClearSelected()
+	a.unreadCheck.SetChecked(false)
+	a.filterRows()
+}
+
+func (a *Communications) Update() {
+	var s string
+	var i widget.Importance
+	var unread int
+	if err := a.updateRows(); err != nil {
+		slog.Error("Failed to refresh communications UI", "err", err)
+		s = "ERROR"
+		i = widget.DangerImportance
+	} else {
+		for _, r := range a.rows {
+			if !r.isRead {
+				unread++
+			}
+		}
+		s = fmt.Sprintf("%s communications • %s unread", humanize.Comma(int64(len(a.rows))), humanize.Comma(int64(unread)))
+	}
+	a.top.Text = s
+	a.top.Importance = i
+	a.top.Refresh()
+	a.filterRows()
+	if a.OnUpdate != nil {
+		a.OnUpdate(unread)
+	}
+}
+
+func (a *Communications) updateRows() error {
+	ctx := context.TODO()
+	cc, err := a.u.CharacterService().ListCharactersShort(ctx)
+	if err != nil {
+		return err
+	}
+	characterNames := make(map[int32]string)
+	a.characterIDs = make(map[string]int32)
+	for _, c := range cc {
+		characterNames[c.ID] = c.Name
+		a.characterIDs[c.Name] = c.ID
+	}
+	nn, err := a.u.CharacterService().ListAllNotifications(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([]communicationRow, 0)
+	groups := set.New[app.NotificationGroup]()
+	types := set.New[string]()
+	for _, cn := range app.CollapseNotifications(nn) {
+		n := cn.Notification
+		names := make([]string, 0)
+		for _, id := range cn.CharacterIDs {
+			names = append(names, characterNames[id])
+		}
+		slices.Sort(names)
+		body, err := n.BodyPlain()
+		if err != nil {
+			slog.Warn("failed to convert markdown", "notificationID", n.ID, "error", err)
+		}
+		title := n.TitleDisplay()
+		group := evenotification.Type2group[evenotification.Type(n.Type)]
+		r := communicationRow{
+			characterIDs:   cn.CharacterIDs,
+			characterNames: strings.Join(names, ", "),
+			group:          group,
+			isRead:         cn.IsRead(),
+			notification:   cn,
+			searchText:     strings.ToLower(title + "\n" + body.ValueOrZero()),
+			timestamp:      n.Timestamp.Format(app.DateTimeFormat),
+			title:          title,
+		}
+		if n.Sender != nil {
+			r.sender = n.Sender.Name
+		}
+		rows = append(rows, r)
+		groups.Add(group)
+		types.Add(n.Type)
+	}
+	a.rows = rows
+
+	groupNames := make([]string, 0)
+	for g := range groups.Values() {
+		groupNames = append(groupNames, g.String())
+	}
+	slices.Sort(groupNames)
+	a.groupSelect.SetOptions(groupNames)
+	typeNames := types.ToSlice()
+	slices.Sort(typeNames)
+	a.typeSelect.SetOptions(typeNames)
+	names := make([]string, 0)
+	for n := range a.characterIDs {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	a.characterSelect.SetOptions(names)
+	return nil
+}
+
+// filterRows applies the current filters and search to the rows.
+func (a *Communications) filterRows() {
+	search := strings.ToLower(strings.TrimSpace(a.search.Text))
+	group := a.groupSelect.Selected
+	typ := a.typeSelect.Selected
+	characterID, hasCharacter := a.characterIDs[a.characterSelect.Selected]
+	rows := make([]communicationRow, 0)
+	for _, r := range a.rows {
+		if group != "" && r.group.String() != group {
+			continue
+		}
+		if typ != "" && r.notification.Notification.Type != typ {
+			continue
+		}
+		if hasCharacter && !slices.Contains(r.characterIDs, characterID) {
+			continue
+		}
+		if a.unreadCheck.Checked && r.isRead {
+			continue
+		}
+		if search != "" && !strings.Contains(r.searchText, search) {
+			continue
+		}
+		rows = append(rows, r)
+	}
+	a.rowsFiltered = rows
+	if len(rows) < len(a.rows) {
+		a.found.SetText(fmt.Sprintf("%s found", humanize.Comma(int64(len(rows)))))
+		a.found.Show()
+	} else {
+		a.found.Hide()
+	}
+	a.body.Refresh()
+	switch x := a.body.(type) {
+	case *widget.Table:
+		x.ScrollToTop()
+	}
+}
+
+// markShownAsProcessed marks all currently shown communications as processed,
+// so that no more desktop notifications are sent for them.
+func (a *Communications) markShownAsProcessed() {
+	ids := set.New[int64]()
+	for _, r := range a.rowsFiltered {
+		for _, n := range slices.Concat([]*app.CharacterNotification{r.notification.Notification}, r.notification.Duplicates) {
+			if !n.IsProcessed {
+				ids.Add(n.ID)
+			}
+		}
+	}
+	w := a.u.MainWindow()
+	if ids.Size() == 0 {
+		a.u.ShowInformationDialog("Mark processed", "All shown communications are already processed.", w)
+		return
+	}
+	a.u.ShowConfirmDialog(
+		"Mark processed",
+		fmt.Sprintf("Are you sure you want to mark %s communications as processed?", humanize.Comma(int64(ids.Size()))),
+		"Mark",
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := a.u.CharacterService().UpdateNotificationsSetProcessed(context.Background(), ids); err != nil {
+				a.u.ShowErrorDialog("Failed to mark communications as processed", err, w)
+				return
+			}
+			a.Update()
+		}, w)
+}
+
+func (a *Communications) showCommunicationDialog(r communicationRow) {
+	n := r.notification.Notification
+	subject := iwidget.NewLabelWithSize(r.title, theme.SizeNameSubHeadingText)
+	subject.Wrapping = fyne.TextWrapWord
+	header := widget.NewLabel(fmt.Sprintf("From: %s\nSent: %s\nTo: %s", r.sender, r.timestamp, r.characterNames))
+	s, err := n.BodyHTML()
+	if err != nil {
+		slog.Warn("failed to convert markdown", "notificationID", n.ID, "text", n.Body.ValueOrZero())
+	}
+	body := appwidget.NewEveHTMLWithUI(a.u)
+	if n.Body.IsEmpty() {
+		body.SetMarkdown("*This notification type is not fully supported yet*")
+	} else {
+		body.SetText(s.ValueOrZero())
+	}
+	c := container.NewVScroll(container.NewVBox(subject, header, body))
+	w := a.u.MainWindow()
+	d := dialog.NewCustom("Communication", "Close", c, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(600, 500))
+	d.Show()
+}
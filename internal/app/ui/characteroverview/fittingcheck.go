@@ -0,0 +1,234 @@
+package characteroverview
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	"github.com/ErikKalkoken/evebuddy/internal/eft"
+	ihumanize "github.com/ErikKalkoken/evebuddy/internal/humanize"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+// FittingCheck shows which characters have the skills to use a ship fitting in the EFT format.
+type FittingCheck struct {
+	widget.BaseWidget
+
+	body    fyne.CanvasObject
+	fitting *widget.Entry
+	result  *app.FittingCheck
+	rows    []app.FittingCheckCharacter
+	top     *widget.Label
+	u       app.UI
+}
+
+func NewFittingCheck(u app.UI) *FittingCheck {
+	a := &FittingCheck{
+		fitting: widget.NewMultiLineEntry(),
+		rows:    make([]app.FittingCheckCharacter, 0),
+		top:     appwidget.MakeTopLabel(),
+		u:       u,
+	}
+	a.ExtendBaseWidget(a)
+	a.fitting.SetPlaceHolder("Paste a fitting in the EFT format, e.g.\n[Tengu, My Tengu]\nBallistic Control System II\n...")
+	a.fitting.SetMinRowsVisible(6)
+	headers := []iwidget.HeaderDef{
+		{Text: "Character", Width: 250},
+		{Text: "Status", Width: 150},
+		{Text: "Missing Skills", Width: 120},
+		{Text: "Training Time", Width: 150},
+	}
+	makeDataLabel := func(col int, c app.FittingCheckCharacter) (string, fyne.TextAlign, widget.Importance) {
+		var align fyne.TextAlign
+		var importance widget.Importance
+		var text string
+		switch col {
+		case 0:
+			text = c.CharacterName
+		case 1:
+			if c.CanUse() {
+				text = "Can use"
+				importance = widget.SuccessImportance
+			} else {
+				text = "Missing skills"
+				importance = widget.WarningImportance
+			}
+		case 2:
+			text = fmt.Sprint(len(c.Missing))
+			align = fyne.TextAlignTrailing
+		case 3:
+			if c.CanUse() {
+				text = "-"
+			} else {
+				text = ihumanize.Optional(c.TrainingTime, "?")
+			}
+		}
+		return text, align, importance
+	}
+	if a.u.IsDesktop() {
+		a.body = iwidget.MakeDataTableForDesktop(headers, &a.rows, makeDataLabel, func(_ int, c app.FittingCheckCharacter) {
+			a.showMissingSkills(c)
+		})
+	} else {
+		a.body = iwidget.MakeDataTableForMobile(headers, &a.rows, makeDataLabel, a.showMissingSkills)
+	}
+	a.updateTop()
+	return a
+}
+
+func (a *FittingCheck) CreateRenderer() fyne.WidgetRenderer {
+	check := widget.NewButtonWithIcon("Check", theme.ConfirmIcon(), func() {
+		a.check()
+	})
+	check.Importance = widget.HighImportance
+	paste := widget.NewButtonWithIcon("Paste", theme.ContentPasteIcon(), func() {
+		a.fitting.SetText(a.u.MainWindow().Clipboard().Content())
+		a.check()
+	})
+	importFile := widget.NewButtonWithIcon("Import", theme.FolderOpenIcon(), func() {
+		a.showImportDialog()
+	})
+	c := container.NewBorder(
+		container.NewVBox(
+			a.fitting,
+			container.NewHBox(layout.NewSpacer(), paste, importFile, check),
+			a.top,
+		),
+		nil,
+		nil,
+		nil,
+		a.body,
+	)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *FittingCheck) check() {
+	text := strings.TrimSpace(a.fitting.Text)
+	if text == "" {
+		return
+	}
+	a.top.Text = "Checking..."
+	a.top.Importance = widget.LowImportance
+	a.top.Refresh()
+	go func() {
+		err := func() error {
+			f, err := eft.Parse(text)
+			if err != nil {
+				return err
+			}
+			r, err := a.u.CharacterService().CheckFitting(context.Background(), f)
+			if err != nil {
+				return err
+			}
+			a.result = r
+			a.rows = r.Characters
+			return nil
+		}()
+		if err != nil {
+			slog.Error("Failed to check fitting", "err", err)
+			a.result = nil
+			a.rows = make([]app.FittingCheckCharacter, 0)
+			a.top.Text = a.u.ErrorDisplay(err)
+			a.top.Importance = widget.DangerImportance
+			a.top.Refresh()
+		} else {
+			a.updateTop()
+		}
+		a.body.Refresh()
+	}()
+}
+
+func (a *FittingCheck) updateTop() {
+	if a.result == nil {
+		a.top.Text = "No fitting checked"
+		a.top.Importance = widget.LowImportance
+		a.top.Refresh()
+		return
+	}
+	s := fmt.Sprintf(
+		"%s (%s) • %d required skills • %d of %d characters can use",
+		a.result.FittingName,
+		a.result.ShipTypeName,
+		len(a.result.Requirements),
+		len(a.result.CanUse()),
+		len(a.result.Characters),
+	)
+	a.top.Importance = widget.MediumImportance
+	if len(a.result.UnknownTypes) > 0 {
+		s += fmt.Sprintf(" • Unknown types: %s", strings.Join(a.result.UnknownTypes, ", "))
+		a.top.Importance = widget.WarningImportance
+	}
+	a.top.Text = s
+	a.top.Refresh()
+}
+
+func (a *FittingCheck) showImportDialog() {
+	w := a.u.MainWindow()
+	d := dialog.NewFileOpen(
+		func(reader fyne.URIReadCloser, err error) {
+			err2 := func() error {
+				if err != nil {
+					return err
+				}
+				if reader == nil {
+					return nil
+				}
+				defer reader.Close()
+				b, err := io.ReadAll(reader)
+				if err != nil {
+					return err
+				}
+				a.fitting.SetText(string(b))
+				a.check()
+				return nil
+			}()
+			if err2 != nil {
+				a.u.ShowErrorDialog("Failed to import fitting", err2, w)
+			}
+		}, w,
+	)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".txt", ".cfg"}))
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Show()
+}
+
+func (a *FittingCheck) showMissingSkills(c app.FittingCheckCharacter) {
+	w := a.u.MainWindow()
+	if c.CanUse() {
+		a.u.ShowInformationDialog(c.CharacterName, "This character has all required skills.", w)
+		return
+	}
+	lines := make([]string, len(c.Missing))
+	for i, m := range c.Missing {
+		lines[i] = fmt.Sprintf("%s %d / %d", m.SkillName, m.CurrentLevel, m.RequiredLevel)
+	}
+	missing := widget.NewLabel(strings.Join(lines, "\n"))
+	copyButton := widget.NewButtonWithIcon("Copy skill queue", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(app.SkillQueueText(c.Missing))
+		a.u.ShowSnackbar("Skill queue copied to clipboard")
+	})
+	info := widget.NewLabel(fmt.Sprintf("Training time: %s", ihumanize.Optional(c.TrainingTime, "?")))
+	content := container.NewBorder(
+		info,
+		container.NewHBox(copyButton),
+		nil,
+		nil,
+		container.NewVScroll(missing),
+	)
+	d := dialog.NewCustom(fmt.Sprintf("Missing skills for %s", c.CharacterName), "Close", content, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 400))
+	d.Show()
+}
@@ -0,0 +1,191 @@
+package characteroverview
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+type moonExtractionRow struct {
+	autoFracture  string
+	chunkArrival  string
+	corporation   *app.EveEntity
+	isReady       bool
+	moon          app.EntityShort[int32]
+	refinery      string
+	region        app.EntityShort[int32]
+	security      string
+	securityColor fyne.ThemeColorName
+	status        string
+	statusColor   fyne.ThemeColorName
+	value         string
+}
+
+// MoonExtractions shows the moon extraction schedule of all corporations.
+type MoonExtractions struct {
+	widget.BaseWidget
+
+	OnUpdate func(total, ready int)
+
+	body fyne.CanvasObject
+	rows []moonExtractionRow
+	top  *widget.Label
+	u    app.UI
+}
+
+func NewMoonExtractions(u app.UI) *MoonExtractions {
+	a := &MoonExtractions{
+		rows: make([]moonExtractionRow, 0),
+		top:  appwidget.MakeTopLabel(),
+		u:    u,
+	}
+	a.ExtendBaseWidget(a)
+	headers := []iwidget.HeaderDef{
+		{Text: "Chunk Arrival", Width: 150},
+		{Text: "Natural Decay", Width: 150},
+		{Text: "Status", Width: 100},
+		{Text: "Refinery", Width: 200},
+		{Text: "Moon", Width: 200},
+		{Text: "Region", Width: 150},
+		{Text: "Corporation", Width: 200},
+		{Text: "Est. Value", Width: 150},
+	}
+	makeCell := func(col int, r moonExtractionRow) []widget.RichTextSegment {
+		switch col {
+		case 0:
+			return iwidget.NewRichTextSegmentFromText(r.chunkArrival)
+		case 1:
+			return iwidget.NewRichTextSegmentFromText(r.autoFracture)
+		case 2:
+			return iwidget.NewRichTextSegmentFromText(r.status, widget.RichTextStyle{
+				ColorName: r.statusColor,
+			})
+		case 3:
+			return iwidget.NewRichTextSegmentFromText(r.refinery)
+		case 4:
+			return slices.Concat(
+				iwidget.NewRichTextSegmentFromText(r.security, widget.RichTextStyle{
+					ColorName: r.securityColor,
+					Inline:    true,
+				}),
+				iwidget.NewRichTextSegmentFromText("  "+r.moon.Name),
+			)
+		case 5:
+			return iwidget.NewRichTextSegmentFromText(r.region.Name)
+		case 6:
+			return iwidget.NewRichTextSegmentFromText(r.corporation.Name)
+		case 7:
+			return iwidget.NewRichTextSegmentFromText(r.value, widget.RichTextStyle{
+				Alignment: fyne.TextAlignTrailing,
+			})
+		}
+		return iwidget.NewRichTextSegmentFromText("?")
+	}
+	if a.u.IsDesktop() {
+		a.body = iwidget.MakeDataTableForDesktop2(headers, &a.rows, makeCell, func(col int, r moonExtractionRow) {
+			switch col {
+			case 5:
+				a.u.ShowInfoWindow(app.EveEntityRegion, r.region.ID)
+			case 6:
+				a.u.ShowEveEntityInfoWindow(r.corporation)
+			default:
+				a.u.ShowMoonInfoWindow(r.moon.ID)
+			}
+		})
+	} else {
+		a.body = iwidget.MakeDataTableForMobile2(headers, &a.rows, makeCell, func(r moonExtractionRow) {
+			a.u.ShowMoonInfoWindow(r.moon.ID)
+		})
+	}
+	return a
+}
+
+func (a *MoonExtractions) CreateRenderer() fyne.WidgetRenderer {
+	c := container.NewBorder(a.top, nil, nil, nil, a.body)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *MoonExtractions) Update() {
+	var s string
+	var i widget.Importance
+	var total, ready int
+	if err := a.updateEntries(); err != nil {
+		slog.Error("Failed to refresh moon extractions UI", "err", err)
+		s = "ERROR"
+		i = widget.DangerImportance
+	} else {
+		total = len(a.rows)
+		for _, r := range a.rows {
+			if r.isReady {
+				ready++
+			}
+		}
+		s = fmt.Sprintf("%d extractions", total)
+		if ready > 0 {
+			s += fmt.Sprintf(" • %d ready", ready)
+		}
+	}
+	a.top.Text = s
+	a.top.Importance = i
+	a.top.Refresh()
+	a.body.Refresh()
+	if a.OnUpdate != nil {
+		a.OnUpdate(total, ready)
+	}
+}
+
+func (a *MoonExtractions) updateEntries() error {
+	extractions, err := a.u.CharacterService().ListMoonExtractions(context.TODO())
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	rows := make([]moonExtractionRow, len(extractions))
+	for i, x := range extractions {
+		ss := x.Moon.SolarSystem
+		r := moonExtractionRow{
+			autoFracture: x.AutoFractureAt.Format(app.DateTimeFormat),
+			chunkArrival: x.ChunkArrivalAt.Format(app.DateTimeFormat),
+			corporation:  x.Corporation,
+			isReady:      x.IsReady(now),
+			moon:         app.EntityShort[int32]{ID: x.Moon.ID, Name: x.Moon.Name},
+			refinery:     x.StructureName,
+			region: app.EntityShort[int32]{
+				ID:   ss.Constellation.Region.ID,
+				Name: ss.Constellation.Region.Name,
+			},
+			security:      fmt.Sprintf("%0.1f", ss.SecurityStatus),
+			securityColor: ss.SecurityType().ToColorName(),
+			status:        x.StatusDisplay(),
+			statusColor:   theme.ColorNameForeground,
+		}
+		switch {
+		case r.isReady:
+			r.statusColor = theme.ColorNameSuccess
+		case x.IsActive(now):
+			r.statusColor = theme.ColorNameWarning
+		case x.Status == app.MoonExtractionStatusCancelled:
+			r.statusColor = theme.ColorNameError
+		}
+		if v := x.EstimatedValue(); v.IsEmpty() {
+			r.value = "?"
+		} else {
+			r.value = humanize.Comma(int64(v.ValueOrZero()))
+		}
+		rows[i] = r
+	}
+	a.rows = rows
+	return nil
+}
@@ -0,0 +1,407 @@
+package characteroverview
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	"github.com/ErikKalkoken/evebuddy/internal/xslices"
+)
+
+const (
+	skillComparisonNameWidth  = 250
+	skillComparisonLevelWidth = 120
+)
+
+type skillComparisonRow struct {
+	groupName string
+	isGroup   bool
+	skill     app.SkillComparisonSkill
+}
+
+// SkillComparison compares the skills of several characters side by side.
+type SkillComparison struct {
+	widget.BaseWidget
+
+	body            *widget.Table
+	characters      []*app.CharacterShort // all characters
+	comparison      *app.SkillComparison
+	onlyDifferences *widget.Check
+	rows            []skillComparisonRow
+	selected        []*app.CharacterShort // characters shown in the comparison
+	selection       *widget.CheckGroup
+	top             *widget.Label
+	u               app.UI
+}
+
+func NewSkillComparison(u app.UI) *SkillComparison {
+	a := &SkillComparison{
+		characters: make([]*app.CharacterShort, 0),
+		rows:       make([]skillComparisonRow, 0),
+		selected:   make([]*app.CharacterShort, 0),
+		top:        appwidget.MakeTopLabel(),
+		u:          u,
+	}
+	a.ExtendBaseWidget(a)
+	a.selection = widget.NewCheckGroup([]string{}, func([]string) {
+		a.updateComparison()
+	})
+	a.selection.Horizontal = true
+	a.onlyDifferences = widget.NewCheck("Only differences", func(bool) {
+		a.updateRows()
+		a.body.Refresh()
+	})
+	a.body = a.makeTable()
+	return a
+}
+
+func (a *SkillComparison) CreateRenderer() fyne.WidgetRenderer {
+	canUse := widget.NewButtonWithIcon("Who can use...", theme.SearchIcon(), func() {
+		a.showCanUseDialog()
+	})
+	missing := widget.NewButtonWithIcon("Missing skills...", theme.ListIcon(), func() {
+		a.showMissingSkillsDialog()
+	})
+	c := container.NewBorder(
+		container.NewVBox(
+			a.top,
+			container.NewHScroll(a.selection),
+			container.NewHBox(a.onlyDifferences, layout.NewSpacer(), canUse, missing),
+		),
+		nil,
+		nil,
+		nil,
+		a.body,
+	)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *SkillComparison) makeTable() *widget.Table {
+	t := widget.NewTable(
+		func() (rows int, cols int) {
+			return len(a.rows), len(a.selected) + 1
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("Template")
+		},
+		func(tci widget.TableCellID, co fyne.CanvasObject) {
+			cell := co.(*widget.Label)
+			if tci.Row >= len(a.rows) || tci.Row < 0 {
+				return
+			}
+			r := a.rows[tci.Row]
+			cell.Alignment = fyne.TextAlignLeading
+			cell.Importance = widget.MediumImportance
+			cell.TextStyle = fyne.TextStyle{}
+			cell.Truncation = fyne.TextTruncateClip
+			switch {
+			case r.isGroup:
+				cell.TextStyle.Bold = true
+				if tci.Col == 0 {
+					cell.Text = r.groupName
+				} else {
+					cell.Text = ""
+				}
+			case tci.Col == 0:
+				cell.Text = r.skill.TypeName
+			default:
+				level := r.skill.Levels[tci.Col-1]
+				if level == 0 {
+					cell.Text = "-"
+				} else {
+					cell.Text = fmt.Sprint(level)
+				}
+				cell.Alignment = fyne.TextAlignCenter
+				if r.skill.HasDifferences() {
+					if level == slices.Max(r.skill.Levels) {
+						cell.Importance = widget.SuccessImportance
+					} else {
+						cell.Importance = widget.WarningImportance
+					}
+				}
+			}
+			cell.Refresh()
+		},
+	)
+	t.ShowHeaderRow = true
+	t.StickyColumnCount = 1
+	t.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabel("Template")
+	}
+	t.UpdateHeader = func(tci widget.TableCellID, co fyne.CanvasObject) {
+		label := co.(*widget.Label)
+		if tci.Col == 0 {
+			label.SetText("Skill")
+			return
+		}
+		if tci.Col-1 < len(a.selected) {
+			label.SetText(a.selected[tci.Col-1].Name)
+		}
+	}
+	t.OnSelected = func(tci widget.TableCellID) {
+		defer t.UnselectAll()
+		if tci.Row >= len(a.rows) || tci.Row < 0 {
+			return
+		}
+		if r := a.rows[tci.Row]; !r.isGroup {
+			a.u.ShowTypeInfoWindow(r.skill.TypeID)
+		}
+	}
+	return t
+}
+
+func (a *SkillComparison) Update() {
+	cc, err := a.u.CharacterService().ListCharactersShort(context.TODO())
+	if err != nil {
+		slog.Error("Failed to refresh skill comparison UI", "err", err)
+		cc = []*app.CharacterShort{}
+	}
+	a.characters = cc
+	names := xslices.Map(cc, func(c *app.CharacterShort) string {
+		return c.Name
+	})
+	selected := slices.DeleteFunc(slices.Clone(a.selection.Selected), func(n string) bool {
+		return !slices.Contains(names, n)
+	})
+	a.selection.Options = names
+	a.selection.Selected = selected
+	a.selection.Refresh()
+	a.updateComparison()
+}
+
+func (a *SkillComparison) updateComparison() {
+	t, i, err := func() (string, widget.Importance, error) {
+		a.comparison = nil
+		a.selected = slices.DeleteFunc(slices.Clone(a.characters), func(c *app.CharacterShort) bool {
+			return !slices.Contains(a.selection.Selected, c.Name)
+		})
+		if len(a.characters) < 2 {
+			return "Skill comparison requires at least two characters", widget.LowImportance, nil
+		}
+		if len(a.selected) < 2 {
+			return "Select at least two characters to compare", widget.LowImportance, nil
+		}
+		ids := xslices.Map(a.selected, func(c *app.CharacterShort) int32 {
+			return c.ID
+		})
+		var err error
+		a.comparison, err = a.u.CharacterService().CompareSkills(context.TODO(), ids)
+		if err != nil {
+			return "", 0, err
+		}
+		var n int
+		for _, g := range a.comparison.Groups {
+			for _, s := range g.Skills {
+				if s.HasDifferences() {
+					n++
+				}
+			}
+		}
+		return fmt.Sprintf("%d characters • %d skills with differences", len(a.selected), n), widget.MediumImportance, nil
+	}()
+	if err != nil {
+		slog.Error("Failed to refresh skill comparison UI", "err", err)
+		t = "ERROR"
+		i = widget.DangerImportance
+	}
+	a.top.Text = t
+	a.top.Importance = i
+	a.top.Refresh()
+	a.updateRows()
+	for col := range len(a.selected) + 1 {
+		w := float32(skillComparisonLevelWidth)
+		if col == 0 {
+			w = skillComparisonNameWidth
+		}
+		a.body.SetColumnWidth(col, w)
+	}
+	a.body.Refresh()
+}
+
+func (a *SkillComparison) updateRows() {
+	rows := make([]skillComparisonRow, 0)
+	if a.comparison != nil {
+		for _, g := range a.comparison.Groups {
+			if a.onlyDifferences.Checked && !g.HasDifferences() {
+				continue
+			}
+			rows = append(rows, skillComparisonRow{groupName: g.GroupName, isGroup: true})
+			for _, s := range g.Skills {
+				if a.onlyDifferences.Checked && !s.HasDifferences() {
+					continue
+				}
+				rows = append(rows, skillComparisonRow{groupName: g.GroupName, skill: s})
+			}
+		}
+	}
+	a.rows = rows
+}
+
+// showCanUseDialog shows a dialog for checking which characters have the skills to use a type.
+func (a *SkillComparison) showCanUseDialog() {
+	w := a.u.MainWindow()
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("Exact name of a type, e.g. Heavy Assault Missile Launcher II")
+	result := widget.NewLabel("")
+	result.Wrapping = fyne.TextWrapWord
+	check := func() {
+		name := strings.TrimSpace(entry.Text)
+		if name == "" || !a.u.HasCharacter() {
+			return
+		}
+		result.SetText("Checking...")
+		go func() {
+			s, err := a.checkCanUse(name)
+			if err != nil {
+				slog.Error("Failed to check required skills", "name", name, "err", err)
+				s = a.u.ErrorDisplay(err)
+			}
+			result.SetText(s)
+		}()
+	}
+	entry.OnSubmitted = func(string) {
+		check()
+	}
+	c := container.NewBorder(
+		container.NewBorder(nil, nil, nil, widget.NewButton("Check", check), entry),
+		nil,
+		nil,
+		nil,
+		container.NewVScroll(result),
+	)
+	d := dialog.NewCustom("Which characters can use a type?", "Close", c, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(600, 400))
+	d.Show()
+	w.Canvas().Focus(entry)
+}
+
+func (a *SkillComparison) checkCanUse(name string) (string, error) {
+	ctx := context.Background()
+	r, _, err := a.u.CharacterService().SearchESI(
+		ctx,
+		a.u.CurrentCharacterID(),
+		name,
+		[]app.SearchCategory{app.SearchType},
+		true,
+	)
+	if err != nil {
+		return "", err
+	}
+	types := r[app.SearchType]
+	if len(types) == 0 {
+		return fmt.Sprintf("No type found with the name \"%s\"", name), nil
+	}
+	et := types[0]
+	requirements, err := a.u.CharacterService().ListRequiredSkills(ctx, et.ID)
+	if err != nil {
+		return "", err
+	}
+	if len(requirements) == 0 {
+		return fmt.Sprintf("%s requires no skills", et.Name), nil
+	}
+	ids := xslices.Map(a.characters, func(c *app.CharacterShort) int32 {
+		return c.ID
+	})
+	comparison, err := a.u.CharacterService().CompareSkills(ctx, ids)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Required skills for %s:\n", et.Name)
+	for _, r := range requirements {
+		fmt.Fprintf(&b, "  %s\n", r)
+	}
+	for _, c := range a.characters {
+		missing := app.CheckSkillRequirements(comparison.Levels(c.ID), requirements)
+		if len(missing) == 0 {
+			fmt.Fprintf(&b, "\n%s: can use\n", c.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s: missing %d skills\n", c.Name, len(missing))
+		for _, m := range missing {
+			fmt.Fprintf(&b, "  %s %d / %d\n", m.SkillName, m.CurrentLevel, m.RequiredLevel)
+		}
+	}
+	return b.String(), nil
+}
+
+// showMissingSkillsDialog shows a dialog with the skills one character is missing to match another.
+func (a *SkillComparison) showMissingSkillsDialog() {
+	w := a.u.MainWindow()
+	if a.comparison == nil {
+		a.u.ShowInformationDialog("Missing skills", "Select at least two characters to compare.", w)
+		return
+	}
+	names := xslices.Map(a.selected, func(c *app.CharacterShort) string {
+		return c.Name
+	})
+	queue := widget.NewEntry()
+	queue.MultiLine = true
+	queue.Wrapping = fyne.TextWrapOff
+	info := widget.NewLabel("")
+	character := widget.NewSelect(names, nil)
+	other := widget.NewSelect(names, nil)
+	update := func(string) {
+		info.SetText("")
+		queue.SetText("")
+		if character.SelectedIndex() == -1 || other.SelectedIndex() == -1 {
+			return
+		}
+		c := a.selected[character.SelectedIndex()]
+		o := a.selected[other.SelectedIndex()]
+		missing, err := a.u.CharacterService().SortMissingSkills(context.Background(), a.comparison.MissingSkills(c.ID, o.ID))
+		if err != nil {
+			slog.Error("Failed to sort missing skills", "error", err)
+			info.SetText("ERROR: " + a.u.ErrorDisplay(err))
+			return
+		}
+		if len(missing) == 0 {
+			info.SetText(fmt.Sprintf("%s has all skills of %s", c.Name, o.Name))
+			return
+		}
+		info.SetText(fmt.Sprintf("%s is missing %d skills of %s", c.Name, len(missing), o.Name))
+		queue.SetText(app.SkillQueueText(missing))
+	}
+	character.OnChanged = update
+	other.OnChanged = update
+	character.SetSelectedIndex(0)
+	other.SetSelectedIndex(1)
+	copyButton := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		if queue.Text == "" {
+			return
+		}
+		w.Clipboard().SetContent(queue.Text)
+		a.u.ShowSnackbar("Skill queue copied to clipboard")
+	})
+	note := widget.NewLabel("The skill queue can be pasted into the skill queue of the Eve client.")
+	note.Importance = widget.LowImportance
+	c := container.NewBorder(
+		container.NewVBox(
+			widget.NewForm(
+				widget.NewFormItem("Character", character),
+				widget.NewFormItem("To match", other),
+			),
+			container.NewBorder(nil, nil, nil, copyButton, info),
+		),
+		note,
+		nil,
+		nil,
+		queue,
+	)
+	d := dialog.NewCustom("Missing skills", "Close", c, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(600, 500))
+	d.Show()
+}
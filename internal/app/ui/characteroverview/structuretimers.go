@@ -0,0 +1,380 @@
+package characteroverview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	ihumanize "github.com/ErikKalkoken/evebuddy/internal/humanize"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+	"github.com/ErikKalkoken/evebuddy/internal/xslices"
+)
+
+// structureTimerRetention is how long expired timers are still shown.
+const structureTimerRetention = 24 * time.Hour
+
+type structureTimerRow struct {
+	exitsAt       string
+	isExpired     bool
+	owner         *app.EveEntity
+	region        app.EntityShort[int32]
+	security      string
+	securityColor fyne.ThemeColorName
+	solarSystem   app.EntityShort[int32]
+	timeLeft      string
+	timeLeftColor fyne.ThemeColorName
+	timer         *app.StructureTimer
+}
+
+// StructureTimers is a timerboard showing the reinforcement timers of structures.
+type StructureTimers struct {
+	widget.BaseWidget
+
+	OnUpdate func(upcoming int)
+
+	add  *widget.Button
+	body fyne.CanvasObject
+	rows []structureTimerRow
+	top  *widget.Label
+	u    app.UI
+}
+
+func NewStructureTimers(u app.UI) *StructureTimers {
+	a := &StructureTimers{
+		rows: make([]structureTimerRow, 0),
+		top:  appwidget.MakeTopLabel(),
+		u:    u,
+	}
+	a.ExtendBaseWidget(a)
+	a.add = widget.NewButtonWithIcon("Add timer", theme.ContentAddIcon(), func() {
+		a.showAddTimerDialog()
+	})
+	headers := []iwidget.HeaderDef{
+		{Text: "Exits At", Width: 150},
+		{Text: "Time Left", Width: 100},
+		{Text: "Timer", Width: 100},
+		{Text: "Structure", Width: 200},
+		{Text: "Type", Width: 150},
+		{Text: "System", Width: 150},
+		{Text: "Region", Width: regionColumnWidth},
+		{Text: "Owner", Width: 200},
+		{Text: "Notes", Width: 200},
+	}
+	makeCell := func(col int, r structureTimerRow) []widget.RichTextSegment {
+		switch col {
+		case 0:
+			return iwidget.NewRichTextSegmentFromText(r.exitsAt)
+		case 1:
+			return iwidget.NewRichTextSegmentFromText(r.timeLeft, widget.RichTextStyle{
+				ColorName: r.timeLeftColor,
+			})
+		case 2:
+			return iwidget.NewRichTextSegmentFromText(r.timer.TimerType.Display())
+		case 3:
+			return iwidget.NewRichTextSegmentFromText(r.timer.DisplayName())
+		case 4:
+			return iwidget.NewRichTextSegmentFromText(r.timer.StructureType.Name)
+		case 5:
+			return slices.Concat(
+				iwidget.NewRichTextSegmentFromText(r.security, widget.RichTextStyle{
+					ColorName: r.securityColor,
+					Inline:    true,
+				}),
+				iwidget.NewRichTextSegmentFromText("  "+r.solarSystem.Name),
+			)
+		case 6:
+			return iwidget.NewRichTextSegmentFromText(r.region.Name)
+		case 7:
+			if r.owner == nil {
+				return iwidget.NewRichTextSegmentFromText("?")
+			}
+			return iwidget.NewRichTextSegmentFromText(r.owner.Name)
+		case 8:
+			return iwidget.NewRichTextSegmentFromText(r.timer.Notes)
+		}
+		return iwidget.NewRichTextSegmentFromText("?")
+	}
+	if a.u.IsDesktop() {
+		a.body = iwidget.MakeDataTableForDesktop2(headers, &a.rows, makeCell, func(col int, r structureTimerRow) {
+			switch col {
+			case 4:
+				a.u.ShowTypeInfoWindow(r.timer.StructureType.ID)
+			case 5:
+				a.u.ShowInfoWindow(app.EveEntitySolarSystem, r.solarSystem.ID)
+			case 6:
+				a.u.ShowInfoWindow(app.EveEntityRegion, r.region.ID)
+			case 7:
+				if r.owner != nil {
+					a.u.ShowEveEntityInfoWindow(r.owner)
+				}
+			default:
+				a.showTimerDialog(r.timer)
+			}
+		})
+	} else {
+		a.body = iwidget.MakeDataTableForMobile2(headers, &a.rows, makeCell, func(r structureTimerRow) {
+			a.showTimerDialog(r.timer)
+		})
+	}
+	return a
+}
+
+func (a *StructureTimers) CreateRenderer() fyne.WidgetRenderer {
+	top := container.NewBorder(nil, nil, nil, a.add, a.top)
+	c := container.NewBorder(top, nil, nil, nil, a.body)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *StructureTimers) Update() {
+	var s string
+	var i widget.Importance
+	var upcoming int
+	if err := a.updateEntries(); err != nil {
+		slog.Error("Failed to refresh structure timers UI", "err", err)
+		s = "ERROR"
+		i = widget.DangerImportance
+	} else {
+		for _, r := range a.rows {
+			if !r.isExpired {
+				upcoming++
+			}
+		}
+		s = fmt.Sprintf("%d upcoming timers", upcoming)
+	}
+	a.top.Text = s
+	a.top.Importance = i
+	a.top.Refresh()
+	a.body.Refresh()
+	if a.OnUpdate != nil {
+		a.OnUpdate(upcoming)
+	}
+}
+
+// updateEntries updates the rows with upcoming timers first, followed by recently expired timers.
+func (a *StructureTimers) updateEntries() error {
+	timers, err := a.u.CharacterService().ListStructureTimers(context.TODO())
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var upcoming, expired []structureTimerRow
+	for _, x := range timers {
+		if x.TimeUntilExit(now) < -structureTimerRetention {
+			continue
+		}
+		ss := x.SolarSystem
+		r := structureTimerRow{
+			exitsAt:   x.ExitsAt.Format(app.DateTimeFormat),
+			isExpired: x.IsExpired(now),
+			owner:     x.Owner,
+			region: app.EntityShort[int32]{
+				ID:   ss.Constellation.Region.ID,
+				Name: ss.Constellation.Region.Name,
+			},
+			security:      fmt.Sprintf("%0.1f", ss.SecurityStatus),
+			securityColor: ss.SecurityType().ToColorName(),
+			solarSystem:   app.EntityShort[int32]{ID: ss.ID, Name: ss.Name},
+			timer:         x,
+		}
+		if r.isExpired {
+			r.timeLeft = "EXPIRED"
+			r.timeLeftColor = theme.ColorNameDisabled
+			expired = append(expired, r)
+		} else {
+			r.timeLeft = ihumanize.Duration(x.TimeUntilExit(now))
+			if x.TimeUntilExit(now) < time.Hour {
+				r.timeLeftColor = theme.ColorNameWarning
+			} else {
+				r.timeLeftColor = theme.ColorNameForeground
+			}
+			upcoming = append(upcoming, r)
+		}
+	}
+	slices.Reverse(expired)
+	a.rows = slices.Concat(upcoming, expired)
+	return nil
+}
+
+func (a *StructureTimers) showTimerDialog(x *app.StructureTimer) {
+	items := []*widget.FormItem{
+		widget.NewFormItem("Structure", widget.NewLabel(x.DisplayName())),
+		widget.NewFormItem("Type", widget.NewLabel(x.StructureType.Name)),
+		widget.NewFormItem("System", widget.NewLabel(x.SolarSystem.Name)),
+		widget.NewFormItem("Timer", widget.NewLabel(x.TimerType.Display())),
+		widget.NewFormItem("Exits At", widget.NewLabel(x.ExitsAt.Format(app.DateTimeFormat))),
+	}
+	if x.Owner != nil {
+		items = append(items, widget.NewFormItem("Owner", widget.NewLabel(x.Owner.Name)))
+	}
+	if x.Notes != "" {
+		notes := widget.NewLabel(x.Notes)
+		notes.Wrapping = fyne.TextWrapWord
+		items = append(items, widget.NewFormItem("Notes", notes))
+	}
+	source := "Notification"
+	if x.IsManual {
+		source = "Manual"
+	}
+	items = append(items, widget.NewFormItem("Source", widget.NewLabel(source)))
+	w := a.u.MainWindow()
+	var d dialog.Dialog
+	deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		a.u.ShowConfirmDialog("Delete timer", "Are you sure you want to delete this timer?", "Delete", func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := a.u.CharacterService().DeleteStructureTimer(context.Background(), x.ID); err != nil {
+				a.u.ShowErrorDialog("Failed to delete timer", err, w)
+				return
+			}
+			d.Hide()
+			a.Update()
+		}, w)
+	})
+	deleteButton.Importance = widget.DangerImportance
+	c := container.NewBorder(nil, container.NewHBox(deleteButton), nil, nil, widget.NewForm(items...))
+	d = dialog.NewCustom("Structure Timer", "Close", c, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Show()
+}
+
+func (a *StructureTimers) showAddTimerDialog() {
+	w := a.u.MainWindow()
+	solarSystem := widget.NewEntry()
+	solarSystem.PlaceHolder = "Exact name of solar system"
+	structureType := widget.NewEntry()
+	structureType.PlaceHolder = "Exact name of structure type, e.g. Astrahus"
+	structureName := widget.NewEntry()
+	structureName.PlaceHolder = "Optional"
+	owner := widget.NewEntry()
+	owner.PlaceHolder = "Optional: exact name of corporation or alliance"
+	timerTypes := app.StructureTimerTypes()
+	timerType := widget.NewSelect(xslices.Map(timerTypes, func(x app.StructureTimerType) string {
+		return x.Display()
+	}), nil)
+	timerType.SetSelectedIndex(0)
+	exitsIn := widget.NewEntry()
+	exitsIn.PlaceHolder = "e.g. 1d 4h 30m"
+	exitsIn.Validator = func(s string) error {
+		_, err := parseTimerDuration(s)
+		return err
+	}
+	notes := widget.NewMultiLineEntry()
+	items := []*widget.FormItem{
+		widget.NewFormItem("Solar System", solarSystem),
+		widget.NewFormItem("Structure Type", structureType),
+		widget.NewFormItem("Structure Name", structureName),
+		widget.NewFormItem("Timer", timerType),
+		widget.NewFormItem("Exits In", exitsIn),
+		widget.NewFormItem("Owner", owner),
+		widget.NewFormItem("Notes", notes),
+	}
+	d := dialog.NewForm("Add timer", "Add", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		go func() {
+			err := func() error {
+				ctx := context.Background()
+				duration, err := parseTimerDuration(exitsIn.Text)
+				if err != nil {
+					return err
+				}
+				arg := app.CreateStructureTimerParams{
+					ExitsAt:       time.Now().Add(duration),
+					Notes:         notes.Text,
+					StructureName: structureName.Text,
+					TimerType:     timerTypes[timerType.SelectedIndex()],
+				}
+				arg.SolarSystemID, err = a.resolveName(ctx, solarSystem.Text, app.SearchSolarSystem)
+				if err != nil {
+					return err
+				}
+				arg.StructureTypeID, err = a.resolveName(ctx, structureType.Text, app.SearchType)
+				if err != nil {
+					return err
+				}
+				if owner.Text != "" {
+					arg.OwnerID, err = a.resolveName(ctx, owner.Text, app.SearchCorporation, app.SearchAlliance)
+					if err != nil {
+						return err
+					}
+				}
+				_, err = a.u.CharacterService().CreateStructureTimer(ctx, arg)
+				return err
+			}()
+			if err != nil {
+				a.u.ShowErrorDialog("Failed to add timer", err, w)
+				return
+			}
+			a.Update()
+		}()
+	}, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 400))
+	d.Show()
+}
+
+// resolveName returns the ID of the entity with the given name.
+func (a *StructureTimers) resolveName(ctx context.Context, name string, categories ...app.SearchCategory) (int32, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, errors.New("name can not be empty")
+	}
+	r, _, err := a.u.CharacterService().SearchESI(ctx, a.u.CurrentCharacterID(), name, categories, true)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range categories {
+		for _, ee := range r[c] {
+			if strings.EqualFold(ee.Name, name) {
+				return ee.ID, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("not found: %s", name)
+}
+
+var timerDurationRE = regexp.MustCompile(`^(\d+)([dhm])$`)
+
+// parseTimerDuration parses durations in the form used for timers, e.g. "1d 4h 30m".
+func parseTimerDuration(s string) (time.Duration, error) {
+	parts := strings.Fields(s)
+	if len(parts) == 0 {
+		return 0, errors.New("duration can not be empty")
+	}
+	var d time.Duration
+	for _, p := range parts {
+		m := timerDurationRE.FindStringSubmatch(strings.ToLower(p))
+		if m == nil {
+			return 0, fmt.Errorf("invalid duration: %s", p)
+		}
+		v, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, err
+		}
+		switch m[2] {
+		case "d":
+			d += time.Duration(v) * 24 * time.Hour
+		case "h":
+			d += time.Duration(v) * time.Hour
+		case "m":
+			d += time.Duration(v) * time.Minute
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,35 @@
+package characteroverview
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimerDuration(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"1d 4h 30m", 28*time.Hour + 30*time.Minute, true},
+		{"45m", 45 * time.Minute, true},
+		{"2H", 2 * time.Hour, true},
+		{"", 0, false},
+		{"1x", 0, false},
+		{"4h abc", 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseTimerDuration(tc.in)
+			if tc.ok {
+				if assert.NoError(t, err) {
+					assert.Equal(t, tc.want, got)
+				}
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
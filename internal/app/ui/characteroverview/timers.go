@@ -0,0 +1,183 @@
+package characteroverview
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	ihumanize "github.com/ErikKalkoken/evebuddy/internal/humanize"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+const timersCalendarFilename = "evebuddy-timers.ics"
+
+type timerRow struct {
+	event         *app.TimerEvent
+	time          string
+	timeLeft      string
+	timeLeftColor fyne.ThemeColorName
+}
+
+// Timers shows all upcoming time based events of all characters in one list.
+type Timers struct {
+	widget.BaseWidget
+
+	body   fyne.CanvasObject
+	export *widget.Button
+	rows   []timerRow
+	top    *widget.Label
+	u      app.UI
+}
+
+func NewTimers(u app.UI) *Timers {
+	a := &Timers{
+		rows: make([]timerRow, 0),
+		top:  appwidget.MakeTopLabel(),
+		u:    u,
+	}
+	a.ExtendBaseWidget(a)
+	a.export = widget.NewButtonWithIcon("Export", theme.DownloadIcon(), func() {
+		a.showExportDialog()
+	})
+	headers := []iwidget.HeaderDef{
+		{Text: "Time", Width: 150},
+		{Text: "Time Left", Width: 100},
+		{Text: "Event", Width: 120},
+		{Text: "Character", Width: 200},
+		{Text: "Details", Width: 400},
+	}
+	makeCell := func(col int, r timerRow) []widget.RichTextSegment {
+		switch col {
+		case 0:
+			return iwidget.NewRichTextSegmentFromText(r.time)
+		case 1:
+			return iwidget.NewRichTextSegmentFromText(r.timeLeft, widget.RichTextStyle{
+				ColorName: r.timeLeftColor,
+			})
+		case 2:
+			return iwidget.NewRichTextSegmentFromText(r.event.Kind.Display())
+		case 3:
+			return iwidget.NewRichTextSegmentFromText(r.event.CharacterName())
+		case 4:
+			return iwidget.NewRichTextSegmentFromText(r.event.Description)
+		}
+		return iwidget.NewRichTextSegmentFromText("?")
+	}
+	if a.u.IsDesktop() {
+		a.body = iwidget.MakeDataTableForDesktop2(headers, &a.rows, makeCell, func(col int, r timerRow) {
+			if col == 3 && r.event.Character != nil {
+				a.u.ShowInfoWindow(app.EveEntityCharacter, r.event.Character.ID)
+				return
+			}
+			a.showEventDialog(r.event)
+		})
+	} else {
+		a.body = iwidget.MakeDataTableForMobile2(headers, &a.rows, makeCell, func(r timerRow) {
+			a.showEventDialog(r.event)
+		})
+	}
+	return a
+}
+
+func (a *Timers) CreateRenderer() fyne.WidgetRenderer {
+	top := container.NewBorder(nil, nil, nil, a.export, a.top)
+	c := container.NewBorder(top, nil, nil, nil, a.body)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *Timers) Update() {
+	var s string
+	var i widget.Importance
+	if err := a.updateEntries(); err != nil {
+		slog.Error("Failed to refresh timers UI", "err", err)
+		s = "ERROR"
+		i = widget.DangerImportance
+	} else {
+		s = fmt.Sprintf("%d upcoming events", len(a.rows))
+	}
+	a.top.Text = s
+	a.top.Importance = i
+	a.top.Refresh()
+	a.body.Refresh()
+}
+
+func (a *Timers) updateEntries() error {
+	events, err := a.u.CharacterService().ListTimerEvents(context.TODO())
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	rows := make([]timerRow, 0)
+	for _, e := range events {
+		d := e.Time.Sub(now)
+		r := timerRow{
+			event:    e,
+			time:     e.Time.Format(app.DateTimeFormat),
+			timeLeft: ihumanize.Duration(d),
+		}
+		if d < time.Hour {
+			r.timeLeftColor = theme.ColorNameWarning
+		} else {
+			r.timeLeftColor = theme.ColorNameForeground
+		}
+		rows = append(rows, r)
+	}
+	a.rows = rows
+	return nil
+}
+
+func (a *Timers) showEventDialog(e *app.TimerEvent) {
+	items := []*widget.FormItem{
+		widget.NewFormItem("Time", widget.NewLabel(e.Time.Format(app.DateTimeFormat))),
+		widget.NewFormItem("Event", widget.NewLabel(e.Kind.Display())),
+	}
+	if e.Character != nil {
+		items = append(items, widget.NewFormItem("Character", widget.NewLabel(e.Character.Name)))
+	}
+	details := widget.NewLabel(e.Description)
+	details.Wrapping = fyne.TextWrapWord
+	items = append(items, widget.NewFormItem("Details", details))
+	w := a.u.MainWindow()
+	d := dialog.NewCustom(e.Title, "Close", widget.NewForm(items...), w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 300))
+	d.Show()
+}
+
+// showExportDialog lets the user save all upcoming events as iCalendar file.
+func (a *Timers) showExportDialog() {
+	w := a.u.MainWindow()
+	d := dialog.NewFileSave(
+		func(writer fyne.URIWriteCloser, err error) {
+			err2 := func() error {
+				if err != nil {
+					return err
+				}
+				if writer == nil {
+					return nil
+				}
+				defer writer.Close()
+				if err := a.u.CharacterService().WriteTimersCalendar(context.Background(), writer); err != nil {
+					return err
+				}
+				a.u.ShowSnackbar("Timers exported to " + writer.URI().Name())
+				return nil
+			}()
+			if err2 != nil {
+				a.u.ShowErrorDialog("Failed to export timers", err2, w)
+			}
+		}, w,
+	)
+	d.SetFileName(timersCalendarFilename)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Show()
+}
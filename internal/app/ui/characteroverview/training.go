@@ -17,8 +17,13 @@ import (
 	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
 )
 
+// trainingIdlePeriod is the period for which idle days are reported.
+const trainingIdlePeriod = 30 * 24 * time.Hour
+
 type trainingCharacter struct {
 	id            int32
+	idleDays      int
+	lostSP        optional.Optional[int]
 	name          string
 	totalSP       optional.Optional[int]
 	training      optional.Optional[time.Duration]
@@ -46,6 +51,8 @@ func NewTraining(u app.UI) *Training {
 		{Text: "SP", Width: 100},
 		{Text: "Unall. SP", Width: 100},
 		{Text: "Training", Width: 100},
+		{Text: "Idle Days (30d)", Width: 130},
+		{Text: "Lost SP (30d)", Width: 130},
 	}
 	makeDataLabel := func(col int, c trainingCharacter) (string, fyne.TextAlign, widget.Importance) {
 		var align fyne.TextAlign
@@ -67,6 +74,19 @@ func NewTraining(u app.UI) *Training {
 			} else {
 				text = ihumanize.Duration(c.training.ValueOrZero())
 			}
+		case 4:
+			text = fmt.Sprint(c.idleDays)
+			align = fyne.TextAlignTrailing
+			if c.idleDays > 0 {
+				importance = widget.WarningImportance
+			}
+		case 5:
+			if c.idleDays > 0 {
+				text = ihumanize.Optional(c.lostSP, "?")
+			} else {
+				text = "0"
+			}
+			align = fyne.TextAlignTrailing
 		}
 		return text, align, importance
 	}
@@ -131,6 +151,12 @@ func (a *Training) updateCharacters() (optional.Optional[int], error) {
 			return totalSP, err
 		}
 		cc[i].training = v
+		h, err := a.u.CharacterService().GetSkillPointHistory(ctx, c.id, time.Now().Add(-trainingIdlePeriod))
+		if err != nil {
+			return totalSP, err
+		}
+		cc[i].idleDays = h.IdleDays()
+		cc[i].lostSP = h.LostSP()
 	}
 	for _, c := range cc {
 		if !c.totalSP.IsEmpty() {
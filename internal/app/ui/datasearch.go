@@ -0,0 +1,268 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/ui/character"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+// DataSearch is a full text search of mails, communications and contracts of all characters.
+type DataSearch struct {
+	widget.BaseWidget
+
+	entry       *widget.Entry
+	indicator   *widget.ProgressBarInfinite
+	resultCount *widget.Label
+	results     *iwidget.Tree[dataSearchNode]
+	u           *BaseUI
+	w           fyne.Window
+}
+
+func NewDataSearch(u *BaseUI) *DataSearch {
+	a := &DataSearch{
+		entry:       widget.NewEntry(),
+		indicator:   widget.NewProgressBarInfinite(),
+		resultCount: widget.NewLabel(""),
+		u:           u,
+		w:           u.MainWindow(),
+	}
+	a.ExtendBaseWidget(a)
+	a.results = a.makeResults()
+	a.resultCount.Hide()
+	a.indicator.Hide()
+	a.entry.PlaceHolder = "Search mails, communications and contracts"
+	a.entry.ActionItem = iwidget.NewIconButton(theme.CancelIcon(), func() {
+		a.Reset()
+	})
+	a.entry.OnSubmitted = func(s string) {
+		go a.doSearch(s)
+	}
+	return a
+}
+
+func (a *DataSearch) CreateRenderer() fyne.WidgetRenderer {
+	c := container.NewBorder(
+		container.NewVBox(
+			a.entry,
+			a.indicator,
+			a.resultCount,
+			widget.NewSeparator(),
+		),
+		nil,
+		nil,
+		nil,
+		a.results,
+	)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *DataSearch) Focus() {
+	a.w.Canvas().Focus(a.entry)
+}
+
+func (a *DataSearch) Reset() {
+	a.entry.SetText("")
+	a.results.Clear()
+	a.resultCount.Hide()
+}
+
+func (a *DataSearch) SetWindow(w fyne.Window) {
+	a.w = w
+}
+
+func (a *DataSearch) makeResults() *iwidget.Tree[dataSearchNode] {
+	t := iwidget.NewTree(
+		func(isBranch bool) fyne.CanvasObject {
+			l := widget.NewLabel("Template")
+			l.Truncation = fyne.TextTruncateEllipsis
+			return l
+		},
+		func(n dataSearchNode, isBranch bool, co fyne.CanvasObject) {
+			co.(*widget.Label).SetText(n.String())
+		},
+	)
+	t.OnSelected = func(n dataSearchNode) {
+		defer t.UnselectAll()
+		if n.result == nil {
+			t.ToggleBranch(n)
+			return
+		}
+		a.showResult(n.result)
+	}
+	return t
+}
+
+func (a *DataSearch) doSearch(search string) {
+	a.results.Clear()
+	a.resultCount.Hide()
+	if search == "" {
+		return
+	}
+	a.indicator.Show()
+	a.indicator.Start()
+	defer func() {
+		a.indicator.Stop()
+		a.indicator.Hide()
+	}()
+	ctx := context.Background()
+	results, err := a.u.CharacterService().SearchData(ctx, search)
+	if err != nil {
+		a.u.ShowErrorDialog("Search failed", err, a.w)
+		return
+	}
+	cc, err := a.u.CharacterService().ListCharactersShort(ctx)
+	if err != nil {
+		a.u.ShowErrorDialog("Search failed", err, a.w)
+		return
+	}
+	characterNames := make(map[int32]string)
+	for _, c := range cc {
+		characterNames[c.ID] = c.Name
+	}
+	a.resultCount.SetText(fmt.Sprintf("%s Results", humanize.Comma(int64(len(results)))))
+	a.resultCount.Show()
+	if len(results) == 0 {
+		return
+	}
+	// group results by kind and character
+	kind2characters := make(map[app.DataSearchKind]map[int32][]*app.DataSearchResult)
+	for _, r := range results {
+		if kind2characters[r.Kind] == nil {
+			kind2characters[r.Kind] = make(map[int32][]*app.DataSearchResult)
+		}
+		kind2characters[r.Kind][r.CharacterID] = append(kind2characters[r.Kind][r.CharacterID], r)
+	}
+	t := iwidget.NewTreeData[dataSearchNode]()
+	for _, kind := range []app.DataSearchKind{app.DataSearchMail, app.DataSearchNotification, app.DataSearchContract} {
+		characters, ok := kind2characters[kind]
+		if !ok {
+			continue
+		}
+		var count int
+		for _, rr := range characters {
+			count += len(rr)
+		}
+		kindUID := t.MustAdd(iwidget.RootUID, dataSearchNode{kind: kind, count: count})
+		for _, c := range cc {
+			rr, ok := characters[c.ID]
+			if !ok {
+				continue
+			}
+			characterUID := t.MustAdd(kindUID, dataSearchNode{
+				characterID:   c.ID,
+				characterName: c.Name,
+				count:         len(rr),
+				kind:          kind,
+			})
+			for _, r := range rr {
+				t.MustAdd(characterUID, dataSearchNode{
+					characterID: r.CharacterID,
+					kind:        kind,
+					result:      r,
+				})
+			}
+		}
+	}
+	a.results.Set(t)
+	if len(kind2characters) == 1 {
+		a.results.OpenAllBranches()
+	}
+}
+
+// showResult opens the item of a search result.
+func (a *DataSearch) showResult(r *app.DataSearchResult) {
+	ctx := context.Background()
+	switch r.Kind {
+	case app.DataSearchContract:
+		c, err := a.u.CharacterService().GetContract(ctx, r.CharacterID, int32(r.ItemID))
+		if err != nil {
+			a.u.ShowErrorDialog("Failed to open contract", err, a.w)
+			return
+		}
+		character.ShowContractWindow(a.u, c)
+	case app.DataSearchMail:
+		m, err := a.u.CharacterService().GetMail(ctx, r.CharacterID, int32(r.ItemID))
+		if err != nil {
+			a.u.ShowErrorDialog("Failed to open mail", err, a.w)
+			return
+		}
+		h := character.NewMailHeader(a.u.EveImageService(), a.u.ShowEveEntityInfoWindow)
+		h.Set(m.From, m.Timestamp, m.Recipients...)
+		body := appwidget.NewEveHTMLWithUI(a.u)
+		body.SetText(m.Body)
+		a.showItemWindow("Mail", m.Subject, h, body)
+	case app.DataSearchNotification:
+		n, err := a.u.CharacterService().GetNotification(ctx, r.CharacterID, r.ItemID)
+		if err != nil {
+			a.u.ShowErrorDialog("Failed to open communication", err, a.w)
+			return
+		}
+		c, err := a.u.CharacterService().GetCharacter(ctx, r.CharacterID)
+		if err != nil {
+			a.u.ShowErrorDialog("Failed to open communication", err, a.w)
+			return
+		}
+		h := character.NewMailHeader(a.u.EveImageService(), a.u.ShowEveEntityInfoWindow)
+		h.Set(n.Sender, n.Timestamp, c.EveCharacter.ToEveEntity())
+		s, err := n.BodyHTML()
+		if err != nil {
+			slog.Warn("failed to convert markdown", "notificationID", n.ID, "text", n.Body.ValueOrZero())
+		}
+		body := appwidget.NewEveHTMLWithUI(a.u)
+		if n.Body.IsEmpty() {
+			body.SetMarkdown("*This notification type is not fully supported yet*")
+		} else {
+			body.SetText(s.ValueOrZero())
+		}
+		a.showItemWindow("Communication", n.TitleDisplay(), h, body)
+	}
+}
+
+func (a *DataSearch) showItemWindow(title, subject string, header, body fyne.CanvasObject) {
+	w := a.u.App().NewWindow(a.u.MakeWindowTitle(title))
+	s := iwidget.NewLabelWithSize(subject, theme.SizeNameSubHeadingText)
+	s.Wrapping = fyne.TextWrapWord
+	c := container.NewBorder(container.NewVBox(s, header), nil, nil, nil, container.NewVScroll(body))
+	w.SetContent(container.NewPadded(c))
+	w.Resize(fyne.NewSize(600, 500))
+	w.Show()
+}
+
+type dataSearchNode struct {
+	characterID   int32
+	characterName string
+	count         int
+	kind          app.DataSearchKind
+	result        *app.DataSearchResult
+}
+
+func (n dataSearchNode) UID() widget.TreeNodeID {
+	if n.result != nil {
+		return fmt.Sprintf("R_%d_%d_%d", n.kind, n.characterID, n.result.ItemID)
+	}
+	if n.characterID != 0 {
+		return fmt.Sprintf("C_%d_%d", n.kind, n.characterID)
+	}
+	return fmt.Sprintf("K_%d", n.kind)
+}
+
+func (n dataSearchNode) String() string {
+	if n.result != nil {
+		return fmt.Sprintf("%s  %s", n.result.Timestamp.Format(app.DateTimeFormat), n.result.Title)
+	}
+	if n.characterID != 0 {
+		return fmt.Sprintf("%s (%d)", n.characterName, n.count)
+	}
+	return fmt.Sprintf("%s (%d)", n.kind.String(), n.count)
+}
@@ -39,9 +39,10 @@ type shortcutDef struct {
 type DesktopUI struct {
 	*BaseUI
 
-	accountWindow  fyne.Window
-	searchWindow   fyne.Window
-	settingsWindow fyne.Window
+	accountWindow    fyne.Window
+	dataSearchWindow fyne.Window
+	searchWindow     fyne.Window
+	settingsWindow   fyne.Window
 
 	shortcuts map[string]shortcutDef
 	sfg       *singleflight.Group
@@ -114,6 +115,7 @@ func NewUIDesktop(bui *BaseUI) *DesktopUI {
 		characterNav.SetItemBadge(mail, formatBadge(count, 99))
 	}
 	u.characterMail.OnSendMessage = u.showSendMailWindow
+	u.characterMail.OnEditDraft = u.showEditMailDraftWindow
 
 	communications := iwidget.NewNavPage(
 		"Communications",
@@ -144,6 +146,8 @@ func NewUIDesktop(bui *BaseUI) *DesktopUI {
 			container.NewAppTabs(
 				container.NewTabItem("Training Queue", u.characterSkillQueue),
 				container.NewTabItem("Skill Catalogue", u.characterSkillCatalogue),
+				container.NewTabItem("SP History", u.characterSkillPoints),
+				container.NewTabItem("Injectors", u.characterSkillInjectors),
 				container.NewTabItem("Ships", u.characterShips),
 			)))
 
@@ -231,6 +235,42 @@ func NewUIDesktop(bui *BaseUI) *DesktopUI {
 		}
 		collectiveNav.SetItemBadge(overviewColonies, s)
 	}
+	overviewCommunications := iwidget.NewNavPage(
+		"Communications",
+		theme.NewThemedResource(icons.MessageOutlineSvg),
+		makePageWithTitle("Communications", u.overviewCommunications),
+	)
+	u.overviewCommunications.OnUpdate = func(unread int) {
+		var s string
+		if unread > 0 {
+			s = fmt.Sprint(unread)
+		}
+		collectiveNav.SetItemBadge(overviewCommunications, s)
+	}
+	overviewMoonExtractions := iwidget.NewNavPage(
+		"Moon Mining",
+		theme.NewThemedResource(icons.ToolsSvg),
+		makePageWithTitle("Moon Mining", u.overviewMoonExtractions),
+	)
+	u.overviewMoonExtractions.OnUpdate = func(_, ready int) {
+		var s string
+		if ready > 0 {
+			s = fmt.Sprint(ready)
+		}
+		collectiveNav.SetItemBadge(overviewMoonExtractions, s)
+	}
+	overviewStructureTimers := iwidget.NewNavPage(
+		"Timerboard",
+		theme.NewThemedResource(icons.AccesstimeSvg),
+		makePageWithTitle("Timerboard", u.overviewStructureTimers),
+	)
+	u.overviewStructureTimers.OnUpdate = func(upcoming int) {
+		var s string
+		if upcoming > 0 {
+			s = fmt.Sprint(upcoming)
+		}
+		collectiveNav.SetItemBadge(overviewStructureTimers, s)
+	}
 	collectiveNav = iwidget.NewNavDrawer("All Characters",
 		overview,
 		allAssets,
@@ -240,16 +280,34 @@ func NewUIDesktop(bui *BaseUI) *DesktopUI {
 			makePageWithTitle("Clones", u.overviewClones),
 		),
 		overviewColonies,
+		overviewCommunications,
 		iwidget.NewNavPage(
 			"Locations",
 			theme.NewThemedResource(icons.MapMarkerSvg),
 			makePageWithTitle("Locations", u.overviewLocations),
 		),
+		overviewMoonExtractions,
+		overviewStructureTimers,
+		iwidget.NewNavPage(
+			"Timers",
+			theme.NewThemedResource(icons.ChecklistrtlSvg),
+			makePageWithTitle("Timers", u.overviewTimers),
+		),
 		iwidget.NewNavPage(
 			"Training",
 			theme.NewThemedResource(icons.SchoolSvg),
 			makePageWithTitle("Training", u.overviewTraining),
 		),
+		iwidget.NewNavPage(
+			"Skill Comparison",
+			theme.NewThemedResource(icons.SchoolSvg),
+			makePageWithTitle("Skill Comparison", u.overviewSkillComparison),
+		),
+		iwidget.NewNavPage(
+			"Fitting Check",
+			theme.NewThemedResource(icons.WrenchCogSvg),
+			makePageWithTitle("Fitting Check", u.overviewFittingCheck),
+		),
 		wealth,
 	)
 	collectiveNav.OnSelectItem = func(it *iwidget.NavItem) {
@@ -366,9 +424,16 @@ func (u *DesktopUI) ShowSettingsWindow() {
 }
 
 func (u *DesktopUI) showSendMailWindow(c *app.Character, mode app.SendMailMode, mail *app.CharacterMail) {
+	u.showSendMailPageWindow(c, character.NewSendMail(u, c, mode, mail))
+}
+
+func (u *DesktopUI) showEditMailDraftWindow(c *app.Character, d *app.CharacterMailDraft) {
+	u.showSendMailPageWindow(c, character.NewSendMailFromDraft(u, c, d))
+}
+
+func (u *DesktopUI) showSendMailPageWindow(c *app.Character, page *character.SendMail) {
 	title := fmt.Sprintf("New message [%s]", c.EveCharacter.Name)
 	w := u.App().NewWindow(u.MakeWindowTitle(title))
-	page := character.NewSendMail(u, c, mode, mail)
 	page.SetWindow(w)
 	send := widget.NewButtonWithIcon("Send", theme.MailSendIcon(), func() {
 		if page.SendAction() {
@@ -376,10 +441,15 @@ func (u *DesktopUI) showSendMailWindow(c *app.Character, mode app.SendMailMode,
 		}
 	})
 	send.Importance = widget.HighImportance
+	save := widget.NewButtonWithIcon("Save draft", theme.DocumentSaveIcon(), func() {
+		if page.SaveDraftAction() {
+			w.Hide()
+		}
+	})
 	p := theme.Padding()
 	x := container.NewBorder(
 		nil,
-		container.NewCenter(container.New(layout.NewCustomPaddedLayout(p, p, 0, 0), send)),
+		container.NewCenter(container.New(layout.NewCustomPaddedLayout(p, p, 0, 0), container.NewHBox(save, send))),
 		nil,
 		nil,
 		page,
@@ -444,6 +514,23 @@ func (u *DesktopUI) showSearchWindow() {
 	u.gameSearch.Focus()
 }
 
+func (u *DesktopUI) showDataSearchWindow() {
+	if u.dataSearchWindow != nil {
+		u.dataSearchWindow.Show()
+		return
+	}
+	w := u.App().NewWindow(u.MakeWindowTitle("Search My Data"))
+	u.dataSearchWindow = w
+	w.SetOnClosed(func() {
+		u.dataSearchWindow = nil
+	})
+	w.Resize(fyne.Size{Width: 700, Height: 400})
+	w.SetContent(u.dataSearch)
+	w.Show()
+	u.dataSearch.SetWindow(w)
+	u.dataSearch.Focus()
+}
+
 func (u *DesktopUI) defineShortcuts() {
 	u.shortcuts = map[string]shortcutDef{
 		"snackbar": {
@@ -517,6 +604,14 @@ func (u *DesktopUI) defineShortcuts() {
 			func(fyne.Shortcut) {
 				u.showSearchWindow()
 			}},
+		"dataSearch": {
+			&desktop.CustomShortcut{
+				KeyName:  fyne.KeyD,
+				Modifier: fyne.KeyModifierAlt,
+			},
+			func(fyne.Shortcut) {
+				u.showDataSearchWindow()
+			}},
 		"settings": {
 			&desktop.CustomShortcut{
 				KeyName:  fyne.KeyComma,
@@ -135,8 +135,7 @@ func (a *characterInfo) load() error {
 		go a.iw.showZoomWindow(o.Name, a.id, a.iw.u.EveImageService().CharacterPortrait, a.iw.w)
 	}
 	if s := o.DescriptionPlain(); s != "" {
-		bio := widget.NewLabel(s)
-		bio.Wrapping = fyne.TextWrapWord
+		bio := a.iw.makeEveHTML(o.Description)
 		a.tabs.Append(container.NewTabItem("Bio", container.NewVScroll(bio)))
 	}
 	if o.Title != "" {
@@ -117,10 +117,8 @@ func (a *corporationInfo) load() error {
 		a.alliance.Hide()
 		a.allianceLogo.Hide()
 	}
-	desc := o.DescriptionPlain()
-	if desc != "" {
-		description := widget.NewLabel(desc)
-		description.Wrapping = fyne.TextWrapWord
+	if o.DescriptionPlain() != "" {
+		description := a.iw.makeEveHTML(o.Description)
 		a.tabs.Append(container.NewTabItem("Description", container.NewVScroll(description)))
 	}
 	if o.HomeStation != nil {
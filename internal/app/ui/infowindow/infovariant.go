@@ -17,6 +17,7 @@ const (
 	infoCorporation
 	infoInventoryType
 	infoLocation
+	infoMoon
 	infoRegion
 	infoRace
 	infoSolarSystem
@@ -8,6 +8,7 @@ import (
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"github.com/ErikKalkoken/evebuddy/internal/app"
+	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
 
 	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
 )
@@ -66,6 +67,10 @@ func (iw *InfoWindow) ShowLocation(id int64) {
 	iw.show(infoLocation, id)
 }
 
+func (iw *InfoWindow) ShowMoon(id int32) {
+	iw.show(infoMoon, int64(id))
+}
+
 func (iw *InfoWindow) ShowRace(id int32) {
 	iw.show(infoRace, int64(id))
 }
@@ -104,6 +109,9 @@ func (iw *InfoWindow) show(t infoVariant, id int64) {
 		}
 		title = a.title()
 		page = a
+	case infoMoon:
+		title = "Moon"
+		page = newMoonInfo(iw, int32(id))
 	case infoRace:
 		title = "Race"
 		page = newRaceInfo(iw, int32(id))
@@ -137,6 +145,19 @@ func (iw *InfoWindow) show(t infoVariant, id int64) {
 	}
 }
 
+// makeEveHTML returns a widget showing an Eve Online HTML text with links opening in this info window.
+func (iw *InfoWindow) makeEveHTML(s string) *appwidget.EveHTML {
+	w := appwidget.NewEveHTML(func(c app.EveEntityCategory, id int64) {
+		if c == app.EveEntityStation {
+			iw.ShowLocation(id)
+			return
+		}
+		iw.Show(c, int32(id))
+	})
+	w.SetText(s)
+	return w
+}
+
 func (iw *InfoWindow) showZoomWindow(title string, id int32, load func(int32, int) (fyne.Resource, error), w fyne.Window) {
 	s := float32(zoomImagePixelSize) / w.Canvas().Scale()
 	r, err := load(id, zoomImagePixelSize)
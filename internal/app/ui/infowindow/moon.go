@@ -0,0 +1,140 @@
+package infowindow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	kxlayout "github.com/ErikKalkoken/fyne-kx/layout"
+	kxwidget "github.com/ErikKalkoken/fyne-kx/widget"
+	"github.com/dustin/go-humanize"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+)
+
+type moonInfo struct {
+	widget.BaseWidget
+
+	iw *InfoWindow
+
+	id          int32
+	logo        *canvas.Image
+	name        *widget.Label
+	region      *kxwidget.TappableLabel
+	solarSystem *kxwidget.TappableLabel
+	tabs        *container.AppTabs
+}
+
+func newMoonInfo(iw *InfoWindow, id int32) *moonInfo {
+	region := kxwidget.NewTappableLabel("", nil)
+	region.Wrapping = fyne.TextWrapWord
+	solarSystem := kxwidget.NewTappableLabel("", nil)
+	solarSystem.Wrapping = fyne.TextWrapWord
+	a := &moonInfo{
+		iw:          iw,
+		id:          id,
+		logo:        makeInfoLogo(),
+		name:        makeInfoName(),
+		region:      region,
+		solarSystem: solarSystem,
+		tabs:        container.NewAppTabs(),
+	}
+	a.ExtendBaseWidget(a)
+	return a
+}
+
+func (a *moonInfo) CreateRenderer() fyne.WidgetRenderer {
+	go func() {
+		err := a.load()
+		if err != nil {
+			slog.Error("moon info update failed", "moon", a.id, "error", err)
+			a.name.Text = fmt.Sprintf("ERROR: Failed to load moon: %s", a.iw.u.ErrorDisplay(err))
+			a.name.Importance = widget.DangerImportance
+			a.name.Refresh()
+		}
+	}()
+	colums := kxlayout.NewColumns(120)
+	p := theme.Padding()
+	main := container.NewVBox(
+		container.New(layout.NewCustomPaddedVBoxLayout(-2*p),
+			a.name,
+			widget.NewLabel("Moon"),
+		),
+		container.New(layout.NewCustomPaddedVBoxLayout(-2*p),
+			container.New(colums, widget.NewLabel("Solar System"), a.solarSystem),
+			container.New(colums, widget.NewLabel("Region"), a.region),
+		))
+	top := container.NewBorder(nil, nil, container.NewVBox(container.NewPadded(a.logo)), nil, main)
+	c := container.NewBorder(top, nil, nil, nil, a.tabs)
+	return widget.NewSimpleRenderer(c)
+}
+
+func (a *moonInfo) load() error {
+	ctx := context.Background()
+	o, err := a.iw.u.EveUniverseService().GetOrCreateMoonESI(ctx, a.id)
+	if err != nil {
+		return err
+	}
+	a.name.SetText(o.Name)
+	a.solarSystem.SetText(o.SolarSystem.Name)
+	a.solarSystem.OnTapped = func() {
+		a.iw.ShowEveEntity(o.SolarSystem.ToEveEntity())
+	}
+	a.region.SetText(o.SolarSystem.Constellation.Region.Name)
+	a.region.OnTapped = func() {
+		a.iw.ShowEveEntity(o.SolarSystem.Constellation.Region.ToEveEntity())
+	}
+	go func() {
+		r, err := a.iw.u.EveImageService().InventoryTypeIcon(app.EveTypeMoon, app.IconPixelSize)
+		if err != nil {
+			slog.Error("moon info: Failed to load logo", "moon", a.id, "error", err)
+			return
+		}
+		a.logo.Resource = r
+		a.logo.Refresh()
+	}()
+
+	extractions, err := a.iw.u.CharacterService().ListMoonExtractions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, x := range extractions {
+		if x.Moon.ID != a.id {
+			continue
+		}
+		// extractions are ordered with the latest first
+		items := []AttributeItem{
+			NewAtributeItem("Refinery", x.StructureName),
+			NewAtributeItem("Owner", x.Corporation),
+			NewAtributeItem("Status", x.StatusDisplay()),
+			NewAtributeItem("Chunk Arrival", x.ChunkArrivalAt),
+			NewAtributeItem("Natural Decay", x.AutoFractureAt),
+		}
+		if v := x.EstimatedValue(); !v.IsEmpty() {
+			items = append(items, NewAtributeItem("Estimated Value", humanize.Comma(int64(v.ValueOrZero()))+" ISK"))
+		}
+		for _, ore := range x.Ores {
+			items = append(items, NewAtributeItem(ore.Type.Name, humanize.Comma(int64(ore.Volume))+" m3"))
+		}
+		a.tabs.Append(container.NewTabItem("Extraction", NewAttributeList(a.iw, items...)))
+		break
+	}
+
+	if a.iw.u.IsDeveloperMode() {
+		x := NewAtributeItem("EVE ID", fmt.Sprint(o.ID))
+		x.Action = func(v any) {
+			a.iw.w.Clipboard().SetContent(v.(string))
+		}
+		attributeList := NewAttributeList(a.iw, []AttributeItem{x}...)
+		attributesTab := container.NewTabItem("Attributes", attributeList)
+		a.tabs.Append(attributesTab)
+	}
+	a.tabs.Refresh()
+	return nil
+}
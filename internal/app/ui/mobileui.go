@@ -43,15 +43,16 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 	var characterNav *iwidget.Navigator
 	mailMenu := fyne.NewMenu("")
 	communicationsMenu := fyne.NewMenu("")
-	u.characterMail.OnSendMessage = func(c *app.Character, mode app.SendMailMode, mail *app.CharacterMail) {
-		page := character.NewSendMail(u, c, mode, mail)
-		if mode != app.SendMailNew {
-			characterNav.Pop() // FIXME: Workaround to avoid pushing upon page w/o navbar
-		}
+	pushSendMailPage := func(page *character.SendMail) {
 		characterNav.PushHideNavBar(
 			newCharacterAppBar(
 				"",
 				page,
+				iwidget.NewIconButton(theme.DocumentSaveIcon(), func() {
+					if page.SaveDraftAction() {
+						characterNav.Pop()
+					}
+				}),
 				iwidget.NewIconButton(theme.MailSendIcon(), func() {
 					if page.SendAction() {
 						characterNav.Pop()
@@ -60,6 +61,16 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 			),
 		)
 	}
+	u.characterMail.OnSendMessage = func(c *app.Character, mode app.SendMailMode, mail *app.CharacterMail) {
+		page := character.NewSendMail(u, c, mode, mail)
+		if mode != app.SendMailNew {
+			characterNav.Pop() // FIXME: Workaround to avoid pushing upon page w/o navbar
+		}
+		pushSendMailPage(page)
+	}
+	u.characterMail.OnEditDraft = func(c *app.Character, d *app.CharacterMailDraft) {
+		pushSendMailPage(character.NewSendMailFromDraft(u, c, d))
+	}
 
 	navItemAssets := iwidget.NewListItemWithIcon(
 		"Assets",
@@ -108,6 +119,10 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 						iwidget.NewIconButton(u.characterMail.MakeReplyAction()),
 						iwidget.NewIconButton(u.characterMail.MakeReplyAllAction()),
 						iwidget.NewIconButton(u.characterMail.MakeForwardAction()),
+						iwidget.NewIconButton(u.characterMail.MakeMarkUnreadAction(func() {
+							characterNav.Pop()
+						})),
+						iwidget.NewIconButton(u.characterMail.MakeLabelAction()),
 						iwidget.NewIconButton(u.characterMail.MakeDeleteAction(func() {
 							characterNav.Pop()
 						})),
@@ -133,6 +148,8 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 					container.NewAppTabs(
 						container.NewTabItem("Training", u.characterSkillQueue),
 						container.NewTabItem("Catalogue", u.characterSkillCatalogue),
+						container.NewTabItem("SP", u.characterSkillPoints),
+						container.NewTabItem("Injectors", u.characterSkillInjectors),
 						container.NewTabItem("Ships", u.characterShips),
 					),
 				))
@@ -251,6 +268,27 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 			crossNav.Push(iwidget.NewAppBar("Colonies", u.overviewColonies))
 		},
 	)
+	navItemCommunications2 := iwidget.NewListItemWithIcon(
+		"Communications",
+		theme.NewThemedResource(icons.MessageOutlineSvg),
+		func() {
+			crossNav.Push(iwidget.NewAppBar("Communications", u.overviewCommunications))
+		},
+	)
+	navItemMoonExtractions := iwidget.NewListItemWithIcon(
+		"Moon Mining",
+		theme.NewThemedResource(icons.ToolsSvg),
+		func() {
+			crossNav.Push(iwidget.NewAppBar("Moon Mining", u.overviewMoonExtractions))
+		},
+	)
+	navItemStructureTimers := iwidget.NewListItemWithIcon(
+		"Timerboard",
+		theme.NewThemedResource(icons.AccesstimeSvg),
+		func() {
+			crossNav.Push(iwidget.NewAppBar("Timerboard", u.overviewStructureTimers))
+		},
+	)
 	crossList := iwidget.NewNavList(
 		iwidget.NewListItemWithIcon(
 			"Characters",
@@ -275,6 +313,7 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 			},
 		),
 		navItemColonies2,
+		navItemCommunications2,
 		iwidget.NewListItemWithIcon(
 			"Locations",
 			theme.NewThemedResource(icons.MapMarkerSvg),
@@ -282,6 +321,15 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 				crossNav.Push(iwidget.NewAppBar("Locations", u.overviewLocations))
 			},
 		),
+		navItemMoonExtractions,
+		navItemStructureTimers,
+		iwidget.NewListItemWithIcon(
+			"Timers",
+			theme.NewThemedResource(icons.ChecklistrtlSvg),
+			func() {
+				crossNav.Push(iwidget.NewAppBar("Timers", u.overviewTimers))
+			},
+		),
 		iwidget.NewListItemWithIcon(
 			"Training",
 			theme.NewThemedResource(icons.SchoolSvg),
@@ -289,6 +337,20 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 				crossNav.Push(iwidget.NewAppBar("Training", u.overviewTraining))
 			},
 		),
+		iwidget.NewListItemWithIcon(
+			"Skill Comparison",
+			theme.NewThemedResource(icons.SchoolSvg),
+			func() {
+				crossNav.Push(iwidget.NewAppBar("Skill Comparison", u.overviewSkillComparison))
+			},
+		),
+		iwidget.NewListItemWithIcon(
+			"Fitting Check",
+			theme.NewThemedResource(icons.WrenchCogSvg),
+			func() {
+				crossNav.Push(iwidget.NewAppBar("Fitting Check", u.overviewFittingCheck))
+			},
+		),
 		navItemWealth,
 	)
 	crossNav = iwidget.NewNavigatorWithAppBar(iwidget.NewAppBar("Characters", crossList))
@@ -296,6 +358,18 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 		navItemColonies2.Supporting = fmt.Sprintf("%d expired", expired)
 		crossList.Refresh()
 	}
+	u.overviewCommunications.OnUpdate = func(unread int) {
+		navItemCommunications2.Supporting = fmt.Sprintf("%s unread", humanize.Comma(int64(unread)))
+		crossList.Refresh()
+	}
+	u.overviewMoonExtractions.OnUpdate = func(_, ready int) {
+		navItemMoonExtractions.Supporting = fmt.Sprintf("%d ready", ready)
+		crossList.Refresh()
+	}
+	u.overviewStructureTimers.OnUpdate = func(upcoming int) {
+		navItemStructureTimers.Supporting = fmt.Sprintf("%d upcoming", upcoming)
+		crossList.Refresh()
+	}
 	u.overviewWealth.OnUpdate = func(wallet, assets float64) {
 		navItemWealth.Supporting = fmt.Sprintf(
 			"Wallet: %s • Assets: %s",
@@ -306,8 +380,19 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 	}
 
 	// info destination
+	searchTabs := container.NewAppTabs(
+		container.NewTabItem("New Eden", u.gameSearch),
+		container.NewTabItem("My Data", u.dataSearch),
+	)
+	searchTabs.OnSelected = func(ti *container.TabItem) {
+		if ti.Content == u.dataSearch {
+			u.dataSearch.Focus()
+		} else {
+			u.gameSearch.Focus()
+		}
+	}
 	searchNav := iwidget.NewNavigatorWithAppBar(
-		newCharacterAppBar("Search", u.gameSearch),
+		newCharacterAppBar("Search", searchTabs),
 	)
 
 	// more destination
@@ -369,6 +454,36 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 			))
 		},
 	)
+	navItemNotifiers := iwidget.NewListItem(
+		"Notifiers",
+		func() {
+			moreNav.Push(iwidget.NewAppBar(
+				"Notifiers",
+				u.userSettings.NotifiersContent,
+				iwidget.NewIconButtonWithMenu(makeSettingsMenu(u.userSettings.NotifiersActions)),
+			))
+		},
+	)
+	navItemRules := iwidget.NewListItem(
+		"Rules",
+		func() {
+			moreNav.Push(iwidget.NewAppBar(
+				"Rules",
+				u.userSettings.RulesContent,
+				iwidget.NewIconButtonWithMenu(makeSettingsMenu(u.userSettings.RulesActions)),
+			))
+		},
+	)
+	navItemWebhooks := iwidget.NewListItem(
+		"Webhooks",
+		func() {
+			moreNav.Push(iwidget.NewAppBar(
+				"Webhooks",
+				u.userSettings.WebhooksContent,
+				iwidget.NewIconButtonWithMenu(makeSettingsMenu(u.userSettings.WebhooksActions)),
+			))
+		},
+	)
 
 	navItemAbout := iwidget.NewListItemWithIcon(
 		"About",
@@ -387,6 +502,9 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 					iwidget.NewNavList(
 						navItemGeneralSettings,
 						navItemNotificationSettings,
+						navItemNotifiers,
+						navItemRules,
+						navItemWebhooks,
 					),
 				))
 			},
@@ -413,10 +531,15 @@ func NewUIMobile(bui *BaseUI) *MobileUI {
 
 	searchDest := iwidget.NewDestinationDef("Search", theme.SearchIcon(), searchNav)
 	searchDest.OnSelected = func() {
-		u.gameSearch.Focus()
+		if searchTabs.Selected().Content == u.dataSearch {
+			u.dataSearch.Focus()
+		} else {
+			u.gameSearch.Focus()
+		}
 	}
 	searchDest.OnSelectedAgain = func() {
 		u.gameSearch.Reset()
+		u.dataSearch.Reset()
 	}
 
 	moreDest := iwidget.NewDestinationDef("More", theme.MenuIcon(), moreNav)
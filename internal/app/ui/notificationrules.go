@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+// makeNotificationRulesPage returns a page for managing the rules
+// which decide how communications are delivered.
+func (a *UserSettings) makeNotificationRulesPage() (fyne.CanvasObject, []app.SettingAction) {
+	rules := make([]*app.NotificationRule, 0)
+	list := widget.NewList(
+		func() int {
+			return len(rules)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(
+				nil,
+				nil,
+				widget.NewIcon(theme.ConfirmIcon()),
+				widget.NewLabel("Template"),
+				widget.NewLabel("Template"),
+			)
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(rules) {
+				return
+			}
+			r := rules[id]
+			border := co.(*fyne.Container).Objects
+			border[0].(*widget.Label).SetText(r.Name)
+			icon := border[1].(*widget.Icon)
+			if r.IsEnabled {
+				icon.SetResource(theme.ConfirmIcon())
+			} else {
+				icon.SetResource(theme.CancelIcon())
+			}
+			border[2].(*widget.Label).SetText(r.Action.Display())
+		},
+	)
+	update := func() {
+		oo, err := a.u.CharacterService().ListNotificationRules(context.Background())
+		if err != nil {
+			slog.Error("Failed to list notification rules", "error", err)
+			return
+		}
+		rules = oo
+		list.Refresh()
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		defer list.UnselectAll()
+		if id >= len(rules) {
+			return
+		}
+		a.showNotificationRuleDialog(rules[id], update)
+	}
+	update()
+	add := app.SettingAction{
+		Label: "Add rule",
+		Action: func() {
+			r := &app.NotificationRule{
+				Action:    app.RuleActionRoute,
+				IsEnabled: true,
+				Position:  len(rules) + 1,
+			}
+			a.showNotificationRuleDialog(r, update)
+		},
+	}
+	return list, []app.SettingAction{add}
+}
+
+// showNotificationRuleDialog shows a dialog for editing a notification rule.
+// A new rule is created when r has no ID.
+func (a *UserSettings) showNotificationRuleDialog(r *app.NotificationRule, onChanged func()) {
+	ctx := context.Background()
+	isNew := r.ID == 0
+	name := widget.NewEntry()
+	name.SetText(r.Name)
+	name.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return errors.New("can not be empty")
+		}
+		return nil
+	}
+	isEnabled := widget.NewCheck("", nil)
+	isEnabled.SetChecked(r.IsEnabled)
+	position := widget.NewEntry()
+	position.SetText(strconv.Itoa(r.Position))
+	position.Validator = validateInt
+
+	actions := make(map[string]app.NotificationRuleAction)
+	actionNames := make([]string, 0)
+	for _, x := range app.NotificationRuleActions() {
+		actions[x.Display()] = x
+		actionNames = append(actionNames, x.Display())
+	}
+	action := widget.NewSelect(actionNames, nil)
+	action.SetSelected(r.Action.Display())
+
+	channels := make(map[string]app.NotificationChannel)
+	channelNames := make([]string, 0)
+	selectedChannels := make([]string, 0)
+	for _, c := range app.NotificationChannels() {
+		channels[c.String()] = c
+		channelNames = append(channelNames, c.String())
+		if r.Channels.Contains(c) {
+			selectedChannels = append(selectedChannels, c.String())
+		}
+	}
+	channelChecks := widget.NewCheckGroup(channelNames, nil)
+	channelChecks.SetSelected(selectedChannels)
+
+	characters, err := a.u.CharacterService().ListCharactersShort(ctx)
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load characters", err, a.currentWindow())
+		return
+	}
+	characterIDs := make(map[string]int32)
+	characterNames := make([]string, 0)
+	selectedCharacters := make([]string, 0)
+	for _, c := range characters {
+		characterIDs[c.Name] = c.ID
+		characterNames = append(characterNames, c.Name)
+		if r.CharacterIDs.Contains(c.ID) {
+			selectedCharacters = append(selectedCharacters, c.Name)
+		}
+	}
+	characterChecks := widget.NewCheckGroup(characterNames, nil)
+	characterChecks.SetSelected(selectedCharacters)
+
+	groups := make(map[string]app.NotificationGroup)
+	groupNames := make([]string, 0)
+	selectedGroups := make([]string, 0)
+	for _, g := range app.NotificationGroups() {
+		groups[g.String()] = g
+		groupNames = append(groupNames, g.String())
+		if r.Groups.Contains(g) {
+			selectedGroups = append(selectedGroups, g.String())
+		}
+	}
+	groupChecks := widget.NewCheckGroup(groupNames, nil)
+	groupChecks.SetSelected(selectedGroups)
+
+	typeNames := make([]string, 0)
+	for _, t := range evenotification.SupportedGroups() {
+		typeNames = append(typeNames, t.String())
+	}
+	slices.Sort(typeNames)
+	typeChecks := widget.NewCheckGroup(typeNames, nil)
+	typeChecks.SetSelected(r.Types.ToSlice())
+	types := container.NewVScroll(typeChecks)
+	types.SetMinSize(fyne.NewSize(0, 200))
+
+	sender := widget.NewEntry()
+	sender.SetText(r.Sender)
+	keyword := widget.NewEntry()
+	keyword.SetText(r.Keyword)
+
+	validateTime := func(s string) error {
+		_, err := app.ParseTimeOfDay(s)
+		if err != nil {
+			return errors.New("must be a time like 22:00")
+		}
+		return nil
+	}
+	quietHoursEnabled := widget.NewCheck("", nil)
+	quietHoursEnabled.SetChecked(r.QuietHoursEnabled)
+	quietHoursStart := widget.NewEntry()
+	quietHoursStart.SetText(app.FormatTimeOfDay(r.QuietHoursStart))
+	quietHoursStart.Validator = validateTime
+	quietHoursEnd := widget.NewEntry()
+	quietHoursEnd.SetText(app.FormatTimeOfDay(r.QuietHoursEnd))
+	quietHoursEnd.Validator = validateTime
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", name),
+		widget.NewFormItem("Enabled", isEnabled),
+		{
+			Text:     "Position",
+			Widget:   position,
+			HintText: "Rules are applied in order of their position. The first matching rule wins.",
+		},
+		{
+			Text:     "Action",
+			Widget:   action,
+			HintText: "Route to the selected channels, suppress or escalate to all channels.",
+		},
+		{
+			Text:     "Channels",
+			Widget:   channelChecks,
+			HintText: "Channels for routed communications. Default channels when none selected.",
+		},
+		{
+			Text:     "Characters",
+			Widget:   characterChecks,
+			HintText: "Match only these characters. All when none selected.",
+		},
+		{
+			Text:     "Groups",
+			Widget:   groupChecks,
+			HintText: "Match communications of these groups.",
+		},
+		{
+			Text:     "Types",
+			Widget:   types,
+			HintText: "Match communications of these types.",
+		},
+		{
+			Text:     "Sender",
+			Widget:   sender,
+			HintText: "Match only communications from this sender.",
+		},
+		{
+			Text:     "Keyword",
+			Widget:   keyword,
+			HintText: "Match only communications containing this keyword.",
+		},
+		{
+			Text:     "Quiet hours",
+			Widget:   quietHoursEnabled,
+			HintText: "Suppress routed communications during quiet hours, except critical ones.",
+		},
+		widget.NewFormItem("Quiet hours start", quietHoursStart),
+		widget.NewFormItem("Quiet hours end", quietHoursEnd),
+	}
+	parent := a.currentWindow()
+	var d dialog.Dialog
+	if !isNew {
+		deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+			a.u.ShowConfirmDialog("Delete rule", "Are you sure you want to delete "+r.Name+"?", "Delete", func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				if err := a.u.CharacterService().DeleteNotificationRule(ctx, r.ID); err != nil {
+					a.u.ShowErrorDialog("Failed to delete rule", err, parent)
+					return
+				}
+				d.Hide()
+				onChanged()
+			}, parent)
+		})
+		deleteButton.Importance = widget.DangerImportance
+		items = append(items, widget.NewFormItem("", container.NewHBox(deleteButton)))
+	}
+	var title, confirm string
+	if isNew {
+		title, confirm = "Add rule", "Add"
+	} else {
+		title, confirm = "Edit rule", "Save"
+	}
+	d = dialog.NewForm(title, confirm, "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		r.Name = strings.TrimSpace(name.Text)
+		r.IsEnabled = isEnabled.Checked
+		r.Position, _ = strconv.Atoi(position.Text)
+		r.Action = actions[action.Selected]
+		r.Channels = set.New[app.NotificationChannel]()
+		for _, n := range channelChecks.Selected {
+			r.Channels.Add(channels[n])
+		}
+		r.CharacterIDs = set.New[int32]()
+		for _, n := range characterChecks.Selected {
+			r.CharacterIDs.Add(characterIDs[n])
+		}
+		r.Groups = set.New[app.NotificationGroup]()
+		for _, n := range groupChecks.Selected {
+			r.Groups.Add(groups[n])
+		}
+		r.Types = set.NewFromSlice(typeChecks.Selected)
+		r.Sender = strings.TrimSpace(sender.Text)
+		r.Keyword = strings.TrimSpace(keyword.Text)
+		r.QuietHoursEnabled = quietHoursEnabled.Checked
+		r.QuietHoursStart, _ = app.ParseTimeOfDay(quietHoursStart.Text)
+		r.QuietHoursEnd, _ = app.ParseTimeOfDay(quietHoursEnd.Text)
+		var err error
+		if isNew {
+			_, err = a.u.CharacterService().CreateNotificationRule(ctx, r)
+		} else {
+			err = a.u.CharacterService().UpdateNotificationRule(ctx, r)
+		}
+		if err != nil {
+			a.u.ShowErrorDialog(fmt.Sprintf("Failed to save rule %s", r.Name), err, parent)
+			return
+		}
+		onChanged()
+	}, parent)
+	a.u.ModifyShortcutsForDialog(d, parent)
+	d.Resize(fyne.NewSize(600, 700))
+	d.Show()
+}
@@ -0,0 +1,336 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/notifier"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
+)
+
+// notifierTimeout is the maximum duration for sending a notification to a notifier backend.
+const notifierTimeout = 30 * time.Second
+
+// sendToNotifiers sends a notification to all enabled notifier backends.
+func (u *BaseUI) sendToNotifiers(title, content string) {
+	u.sendToNotifiersWithChannels(title, content, set.New(app.NotificationChannels()...))
+}
+
+// sendToNotifiersWithChannels sends a notification to the enabled notifier backends
+// of the given channels.
+func (u *BaseUI) sendToNotifiersWithChannels(title, content string, channels set.Set[app.NotificationChannel]) {
+	if u.IsOffline() {
+		return
+	}
+	for _, n := range u.Settings().NotifiersEnabled() {
+		if !channels.Contains(notifierChannel(n)) {
+			continue
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), notifierTimeout)
+			defer cancel()
+			if err := n.Send(ctx, title, content); err != nil {
+				slog.Error("Failed to send notification", "notifier", n.Name(), "error", err)
+			}
+		}()
+	}
+}
+
+// notifierChannel returns the notification channel of a notifier backend.
+func notifierChannel(n notifier.Notifier) app.NotificationChannel {
+	switch n.(type) {
+	case notifier.Gotify:
+		return app.ChannelGotify
+	case notifier.Ntfy:
+		return app.ChannelNtfy
+	case notifier.SMTP:
+		return app.ChannelSMTP
+	case notifier.Webhook:
+		return app.ChannelWebhook
+	}
+	return app.ChannelUndefined
+}
+
+// makeNotifiersPage returns a page for configuring the notifier backends.
+func (a *UserSettings) makeNotifiersPage() (fyne.CanvasObject, []app.SettingAction) {
+	status := func(isEnabled bool) any {
+		if isEnabled {
+			return "On"
+		}
+		return "Off"
+	}
+	items := []iwidget.SettingItem{
+		iwidget.NewSettingItemCustom(
+			"ntfy",
+			"Send notifications to a topic on a ntfy server",
+			func() any {
+				return status(a.u.Settings().NotifierNtfy().IsEnabled)
+			},
+			func(_ iwidget.SettingItem, refresh func()) {
+				a.showNtfyDialog(refresh)
+			},
+		),
+		iwidget.NewSettingItemCustom(
+			"Gotify",
+			"Send notifications to a Gotify server",
+			func() any {
+				return status(a.u.Settings().NotifierGotify().IsEnabled)
+			},
+			func(_ iwidget.SettingItem, refresh func()) {
+				a.showGotifyDialog(refresh)
+			},
+		),
+		iwidget.NewSettingItemCustom(
+			"Email (SMTP)",
+			"Send notifications as email",
+			func() any {
+				return status(a.u.Settings().NotifierSMTP().IsEnabled)
+			},
+			func(_ iwidget.SettingItem, refresh func()) {
+				a.showSMTPDialog(refresh)
+			},
+		),
+		iwidget.NewSettingItemCustom(
+			"Webhook",
+			"Send notifications as JSON to a webhook",
+			func() any {
+				return status(a.u.Settings().NotifierWebhook().IsEnabled)
+			},
+			func(_ iwidget.SettingItem, refresh func()) {
+				a.showNotifierWebhookDialog(refresh)
+			},
+		),
+	}
+	list := iwidget.NewSettingList(items)
+	reset := app.SettingAction{
+		Label: "Reset to defaults",
+		Action: func() {
+			a.u.Settings().ResetNotifierGotify()
+			a.u.Settings().ResetNotifierNtfy()
+			a.u.Settings().ResetNotifierSMTP()
+			a.u.Settings().ResetNotifierWebhook()
+			list.Refresh()
+		},
+	}
+	send := app.SettingAction{
+		Label: "Send test notification to all enabled",
+		Action: func() {
+			a.u.sendToNotifiers("Test", "This is a test notification from EVE Buddy.")
+		},
+	}
+	return list, []app.SettingAction{reset, send}
+}
+
+func (a *UserSettings) showNtfyDialog(refresh func()) {
+	x := a.u.Settings().NotifierNtfy()
+	isEnabled := widget.NewCheck("", nil)
+	isEnabled.SetChecked(x.IsEnabled)
+	server := widget.NewEntry()
+	server.SetText(x.Server)
+	server.PlaceHolder = "https://ntfy.sh"
+	topic := widget.NewEntry()
+	topic.SetText(x.Topic)
+	token := widget.NewPasswordEntry()
+	token.SetText(x.Token)
+	token.PlaceHolder = "Optional"
+	current := func() notifier.Ntfy {
+		return notifier.Ntfy{
+			IsEnabled: isEnabled.Checked,
+			Server:    server.Text,
+			Token:     token.Text,
+			Topic:     topic.Text,
+		}
+	}
+	a.showNotifierDialog(
+		"ntfy",
+		[]*widget.FormItem{
+			widget.NewFormItem("Enabled", isEnabled),
+			{Text: "Server", Widget: server, HintText: "Leave empty for the public server"},
+			widget.NewFormItem("Topic", topic),
+			widget.NewFormItem("Token", token),
+		},
+		func() notifier.Notifier {
+			return current()
+		},
+		func() {
+			a.u.Settings().SetNotifierNtfy(current())
+		},
+		refresh,
+	)
+}
+
+func (a *UserSettings) showGotifyDialog(refresh func()) {
+	x := a.u.Settings().NotifierGotify()
+	isEnabled := widget.NewCheck("", nil)
+	isEnabled.SetChecked(x.IsEnabled)
+	url := widget.NewEntry()
+	url.SetText(x.URL)
+	url.PlaceHolder = "https://gotify.example.com"
+	token := widget.NewPasswordEntry()
+	token.SetText(x.Token)
+	priority := widget.NewEntry()
+	priority.SetText(strconv.Itoa(x.Priority))
+	priority.Validator = validateInt
+	current := func() notifier.Gotify {
+		p, _ := strconv.Atoi(priority.Text)
+		return notifier.Gotify{
+			IsEnabled: isEnabled.Checked,
+			Priority:  p,
+			Token:     token.Text,
+			URL:       url.Text,
+		}
+	}
+	a.showNotifierDialog(
+		"Gotify",
+		[]*widget.FormItem{
+			widget.NewFormItem("Enabled", isEnabled),
+			widget.NewFormItem("URL", url),
+			{Text: "Token", Widget: token, HintText: "Token of the application"},
+			widget.NewFormItem("Priority", priority),
+		},
+		func() notifier.Notifier {
+			return current()
+		},
+		func() {
+			a.u.Settings().SetNotifierGotify(current())
+		},
+		refresh,
+	)
+}
+
+func (a *UserSettings) showSMTPDialog(refresh func()) {
+	x := a.u.Settings().NotifierSMTP()
+	isEnabled := widget.NewCheck("", nil)
+	isEnabled.SetChecked(x.IsEnabled)
+	host := widget.NewEntry()
+	host.SetText(x.Host)
+	host.PlaceHolder = "smtp.example.com"
+	port := widget.NewEntry()
+	if x.Port == 0 {
+		x.Port = 587
+	}
+	port.SetText(strconv.Itoa(x.Port))
+	port.Validator = validateInt
+	username := widget.NewEntry()
+	username.SetText(x.Username)
+	username.PlaceHolder = "Optional"
+	password := widget.NewPasswordEntry()
+	password.SetText(x.Password)
+	from := widget.NewEntry()
+	from.SetText(x.From)
+	to := widget.NewEntry()
+	to.SetText(x.To)
+	current := func() notifier.SMTP {
+		p, _ := strconv.Atoi(port.Text)
+		return notifier.SMTP{
+			From:      from.Text,
+			Host:      host.Text,
+			IsEnabled: isEnabled.Checked,
+			Password:  password.Text,
+			Port:      p,
+			To:        to.Text,
+			Username:  username.Text,
+		}
+	}
+	a.showNotifierDialog(
+		"Email (SMTP)",
+		[]*widget.FormItem{
+			widget.NewFormItem("Enabled", isEnabled),
+			widget.NewFormItem("Host", host),
+			widget.NewFormItem("Port", port),
+			widget.NewFormItem("Username", username),
+			widget.NewFormItem("Password", password),
+			widget.NewFormItem("From", from),
+			{Text: "To", Widget: to, HintText: "Comma separated list of addresses"},
+		},
+		func() notifier.Notifier {
+			return current()
+		},
+		func() {
+			a.u.Settings().SetNotifierSMTP(current())
+		},
+		refresh,
+	)
+}
+
+func (a *UserSettings) showNotifierWebhookDialog(refresh func()) {
+	x := a.u.Settings().NotifierWebhook()
+	isEnabled := widget.NewCheck("", nil)
+	isEnabled.SetChecked(x.IsEnabled)
+	url := widget.NewEntry()
+	url.SetText(x.URL)
+	current := func() notifier.Webhook {
+		return notifier.Webhook{
+			IsEnabled: isEnabled.Checked,
+			URL:       url.Text,
+		}
+	}
+	a.showNotifierDialog(
+		"Webhook",
+		[]*widget.FormItem{
+			widget.NewFormItem("Enabled", isEnabled),
+			{Text: "URL", Widget: url, HintText: "Receives title, content and timestamp as JSON"},
+		},
+		func() notifier.Notifier {
+			return current()
+		},
+		func() {
+			a.u.Settings().SetNotifierWebhook(current())
+		},
+		refresh,
+	)
+}
+
+// showNotifierDialog shows a dialog for configuring a notifier backend.
+// The test button sends a notification with the current configuration, which does not need to be saved.
+func (a *UserSettings) showNotifierDialog(
+	title string,
+	items []*widget.FormItem,
+	current func() notifier.Notifier,
+	save func(),
+	refresh func(),
+) {
+	w := a.currentWindow()
+	var test *widget.Button
+	test = widget.NewButtonWithIcon("Send test notification", theme.MailSendIcon(), func() {
+		test.Disable()
+		go func() {
+			defer test.Enable()
+			ctx, cancel := context.WithTimeout(context.Background(), notifierTimeout)
+			defer cancel()
+			n := current()
+			if err := n.Send(ctx, "Test", "This is a test notification from EVE Buddy."); err != nil {
+				a.u.ShowErrorDialog(fmt.Sprintf("Failed to send test notification with %s", n.Name()), err, w)
+				return
+			}
+			a.showSnackbar("Test notification sent")
+		}()
+	})
+	c := container.NewBorder(nil, container.NewHBox(test), nil, nil, widget.NewForm(items...))
+	d := dialog.NewCustomConfirm(title, "Save", "Cancel", c, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		save()
+		refresh()
+	}, w)
+	a.u.ModifyShortcutsForDialog(d, w)
+	d.Resize(fyne.NewSize(500, 300))
+	d.Show()
+}
+
+func validateInt(s string) error {
+	_, err := strconv.Atoi(s)
+	return err
+}
@@ -55,6 +55,8 @@ func NewToolbar(u *DesktopUI) *Toolbar {
 		makeMenuItem("Manage Characters", u.shortcuts["manageCharacters"]),
 		makeMenuItem("Update Status", u.shortcuts["updateStatus"]),
 		fyne.NewMenuItemSeparator(),
+		makeMenuItem("Search My Data", u.shortcuts["dataSearch"]),
+		fyne.NewMenuItemSeparator(),
 		fyne.NewMenuItem("User Data", u.showUserDataDialog),
 		fyne.NewMenuItem("About", u.ShowAboutDialog),
 		fyne.NewMenuItemSeparator(),
@@ -84,9 +86,14 @@ func (a *Toolbar) CreateRenderer() fyne.WidgetRenderer {
 	x := container.NewGridWithColumns(
 		3,
 		container.NewHBox(),
-		container.NewBorder(nil, nil, nil, iwidget.NewIconButton(theme.SearchIcon(), func() {
-			a.u.showAdvancedSearch()
-		}),
+		container.NewBorder(nil, nil, nil, container.NewHBox(
+			iwidget.NewIconButton(theme.SearchIcon(), func() {
+				a.u.showAdvancedSearch()
+			}),
+			iwidget.NewIconButton(theme.FileTextIcon(), func() {
+				a.u.showDataSearchWindow()
+			}),
+		),
 			a.searchbar,
 		),
 		container.New(layout.NewCustomPaddedHBoxLayout(2*p), layout.NewSpacer(), a.hamburger),
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,6 +33,7 @@ import (
 	appwidget "github.com/ErikKalkoken/evebuddy/internal/app/widget"
 	"github.com/ErikKalkoken/evebuddy/internal/fynetools"
 	"github.com/ErikKalkoken/evebuddy/internal/github"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
 	iwidget "github.com/ErikKalkoken/evebuddy/internal/widget"
 )
 
@@ -45,6 +48,7 @@ const (
 const (
 	characterSectionsUpdateTicker = 60 * time.Second
 	generalSectionsUpdateTicker   = 300 * time.Second
+	mailOutboxTicker              = 30 * time.Second
 )
 
 // BaseUI represents the core UI logic and is used by both the desktop and mobile UI.
@@ -78,15 +82,24 @@ type BaseUI struct {
 	characterSheet             *character.Sheet
 	characterShips             *character.FlyableShips
 	characterSkillCatalogue    *character.SkillCatalogue
+	characterSkillInjectors    *character.SkillInjectors
+	characterSkillPoints       *character.SkillPointHistory
 	characterSkillQueue        *character.SkillQueue
 	characterWalletJournal     *character.WalletJournal
 	characterWalletTransaction *character.WalletTransaction
+	dataSearch                 *DataSearch
 	gameSearch                 *GameSearch
 	manageCharacters           *ManageCharacters
 	overviewAssets             *characteroverview.Assets
 	overviewClones             *characteroverview.Clones
 	overviewColonies           *characteroverview.Colonies
+	overviewCommunications     *characteroverview.Communications
+	overviewFittingCheck       *characteroverview.FittingCheck
 	overviewLocations          *characteroverview.Locations
+	overviewMoonExtractions    *characteroverview.MoonExtractions
+	overviewSkillComparison    *characteroverview.SkillComparison
+	overviewStructureTimers    *characteroverview.StructureTimers
+	overviewTimers             *characteroverview.Timers
 	overviewTraining           *characteroverview.Training
 	overviewWealth             *characteroverview.Wealth
 	userSettings               *UserSettings
@@ -163,15 +176,24 @@ func NewBaseUI(
 	u.characterSheet = character.NewSheet(u)
 	u.characterShips = character.NewFlyableShips(u)
 	u.characterSkillCatalogue = character.NewSkillCatalogue(u)
+	u.characterSkillInjectors = character.NewSkillInjectors(u)
+	u.characterSkillPoints = character.NewSkillPointHistory(u)
 	u.characterSkillQueue = character.NewSkillQueue(u)
 	u.characterWalletJournal = character.NewWalletJournal(u)
 	u.characterWalletTransaction = character.NewWalletTransaction(u)
+	u.dataSearch = NewDataSearch(u)
 	u.gameSearch = NewGameSearch(u)
 	u.manageCharacters = NewManageCharacters(u)
 	u.overviewAssets = characteroverview.NewAssets(u)
 	u.overviewClones = characteroverview.NewClones(u)
 	u.overviewColonies = characteroverview.NewColonies(u)
+	u.overviewCommunications = characteroverview.NewCommunications(u)
+	u.overviewFittingCheck = characteroverview.NewFittingCheck(u)
 	u.overviewLocations = characteroverview.NewLocations(u)
+	u.overviewMoonExtractions = characteroverview.NewMoonExtractions(u)
+	u.overviewSkillComparison = characteroverview.NewSkillComparison(u)
+	u.overviewStructureTimers = characteroverview.NewStructureTimers(u)
+	u.overviewTimers = characteroverview.NewTimers(u)
 	u.overviewTraining = characteroverview.NewTraining(u)
 	u.overviewWealth = characteroverview.NewWealth(u)
 	u.snackbar = iwidget.NewSnackbar(u.window)
@@ -323,6 +345,9 @@ func (u *BaseUI) ShowAndRun() {
 		} else {
 			slog.Info("Update ticker disabled")
 		}
+		if !u.isOffline {
+			go u.startMailOutbox()
+		}
 		go u.characterJumpClones.StartUpdateTicker()
 		if u.onAppFirstStarted != nil {
 			u.onAppFirstStarted()
@@ -415,6 +440,8 @@ func (u *BaseUI) updateCharacter() {
 		"sheet":             u.characterSheet.Update,
 		"ships":             u.characterShips.Update,
 		"skillCatalogue":    u.characterSkillCatalogue.Update,
+		"skillInjectors":    u.characterSkillInjectors.Update,
+		"skillPoints":       u.characterSkillPoints.Update,
 		"skillqueue":        u.characterSkillQueue.Update,
 		"walletJournal":     u.characterWalletJournal.Update,
 		"walletTransaction": u.characterWalletTransaction.Update,
@@ -439,13 +466,18 @@ func (u *BaseUI) updateCharacter() {
 // UpdateCrossPages refreshed all pages that contain information about multiple characters.
 func (u *BaseUI) UpdateCrossPages() {
 	ff := map[string]func(){
-		"assetSearch": u.overviewAssets.Update,
-		"cloneSeach":  u.overviewClones.Update,
-		"colony":      u.overviewColonies.Update,
-		"locations":   u.overviewLocations.Update,
-		"overview":    u.overviewCharacters.Update,
-		"training":    u.overviewTraining.Update,
-		"wealth":      u.overviewWealth.Update,
+		"assetSearch":     u.overviewAssets.Update,
+		"cloneSeach":      u.overviewClones.Update,
+		"colony":          u.overviewColonies.Update,
+		"communications":  u.overviewCommunications.Update,
+		"locations":       u.overviewLocations.Update,
+		"moonExtractions": u.overviewMoonExtractions.Update,
+		"overview":        u.overviewCharacters.Update,
+		"skillComparison": u.overviewSkillComparison.Update,
+		"structureTimers": u.overviewStructureTimers.Update,
+		"timers":          u.overviewTimers.Update,
+		"training":        u.overviewTraining.Update,
+		"wealth":          u.overviewWealth.Update,
 	}
 	if u.onRefreshCross != nil {
 		ff["onRefreshCross"] = u.onRefreshCross
@@ -587,6 +619,30 @@ func (u *BaseUI) makeCharacterSwitchMenu(refresh func()) []*fyne.MenuItem {
 func (u *BaseUI) sendDesktopNotification(title, content string) {
 	u.app.SendNotification(fyne.NewNotification(title, content))
 	slog.Info("desktop notification sent", "title", title, "content", content)
+	u.sendToNotifiers(title, content)
+}
+
+// sendNotification sends a notification to the given channels.
+// When no channels are given it is send to the desktop and all enabled notifiers.
+func (u *BaseUI) sendNotification(title, content string, channels set.Set[app.NotificationChannel]) {
+	if channels.Size() == 0 {
+		u.sendDesktopNotification(title, content)
+		return
+	}
+	if channels.Contains(app.ChannelDesktop) {
+		u.app.SendNotification(fyne.NewNotification(title, content))
+		slog.Info("desktop notification sent", "title", title, "content", content)
+	}
+	u.sendToNotifiersWithChannels(title, content, channels)
+}
+
+// sendAlert sends a desktop notification about an app alert, e.g. expired training,
+// and forwards it to webhooks.
+func (u *BaseUI) sendAlert(title, content string) {
+	u.sendDesktopNotification(title, content)
+	if err := u.CharacterService().ForwardAlert(context.Background(), title, content); err != nil {
+		slog.Error("Failed to forward alert", "title", title, "error", err)
+	}
 }
 
 func (u *BaseUI) startUpdateTickerGeneralSections() {
@@ -635,6 +691,10 @@ func (u *BaseUI) updateGeneralSectionAndRefreshIfNeeded(ctx context.Context, sec
 		u.overviewCharacters.Update()
 		u.overviewAssets.Update()
 		u.reloadCurrentCharacter()
+	case app.SectionEveMasteries:
+		if needsRefresh {
+			u.characterShips.Update()
+		}
 	default:
 		slog.Warn(fmt.Sprintf("section not part of the update ticker refresh: %s", section))
 	}
@@ -651,6 +711,8 @@ func (u *BaseUI) startUpdateTickerCharacters() {
 			if err := u.notifyCharactersIfNeeded(ctx); err != nil {
 				slog.Error("Failed to notify characters", "error", err)
 			}
+			u.writeTimersCalendarIfNeeded(ctx)
+			u.sendWebhookMessagesIfNeeded(ctx)
 			<-ticker.C
 		}
 	}()
@@ -677,6 +739,8 @@ func (u *BaseUI) notifyCharactersIfNeeded(ctx context.Context) error {
 		go u.notifyExpiredExtractionsIfNeeded(ctx, c.ID)
 		go u.notifyExpiredTrainingIfneeded(ctx, c.ID)
 	}
+	u.notifyMoonExtractionsIfNeeded(ctx)
+	u.notifyStructureTimersIfNeeded(ctx)
 	slog.Debug("started notify characters")
 	return nil
 }
@@ -690,7 +754,9 @@ func (u *BaseUI) updateCharacterAndRefreshIfNeeded(ctx context.Context, characte
 	var sections []app.CharacterSection
 	if u.IsMobile() && !u.isForeground.Load() {
 		// only update what is needed for notifications on mobile when running in background to save battery
-		if u.Settings().NotifyCommunicationsEnabled() {
+		if u.Settings().NotifyCommunicationsEnabled() ||
+			u.Settings().NotifyMoonMiningEnabled() ||
+			u.Settings().NotifyTimersEnabled() {
 			sections = append(sections, app.SectionNotifications)
 		}
 		if u.Settings().NotifyContractsEnabled() {
@@ -757,8 +823,11 @@ func (u *BaseUI) updateCharacterSectionAndRefreshIfNeeded(ctx context.Context, c
 			u.characterAttributes.Update()
 		}
 	case app.SectionContracts:
-		if isShown && needsRefresh {
-			u.characterContracts.Update()
+		if needsRefresh {
+			u.overviewTimers.Update()
+			if isShown {
+				u.characterContracts.Update()
+			}
 		}
 		if u.Settings().NotifyContractsEnabled() {
 			go func() {
@@ -776,6 +845,7 @@ func (u *BaseUI) updateCharacterSectionAndRefreshIfNeeded(ctx context.Context, c
 		if needsRefresh {
 			u.overviewCharacters.Update()
 			u.overviewClones.Update()
+			u.overviewTimers.Update()
 			if isShown {
 				u.reloadCurrentCharacter()
 				u.characterJumpClones.Update()
@@ -793,6 +863,7 @@ func (u *BaseUI) updateCharacterSectionAndRefreshIfNeeded(ctx context.Context, c
 	case app.SectionPlanets:
 		if needsRefresh {
 			u.overviewColonies.Update()
+			u.overviewTimers.Update()
 			u.notifyExpiredExtractionsIfNeeded(ctx, characterID)
 			if isShown {
 				u.characterPlanets.Update()
@@ -823,14 +894,22 @@ func (u *BaseUI) updateCharacterSectionAndRefreshIfNeeded(ctx context.Context, c
 			}()
 		}
 	case app.SectionNotifications:
-		if isShown && needsRefresh {
-			u.characterCommunications.Update()
+		if needsRefresh {
+			u.overviewCommunications.Update()
+			u.overviewMoonExtractions.Update()
+			u.overviewStructureTimers.Update()
+			u.overviewTimers.Update()
+			u.notifyMoonExtractionsIfNeeded(ctx)
+			u.notifyStructureTimersIfNeeded(ctx)
+			if isShown {
+				u.characterCommunications.Update()
+			}
 		}
 		if u.Settings().NotifyCommunicationsEnabled() {
 			go func() {
 				earliest := u.Settings().NotifyCommunicationsEarliest()
 				typesEnabled := u.Settings().NotificationTypesEnabled()
-				if err := u.CharacterService().NotifyCommunications(ctx, characterID, earliest, typesEnabled, u.sendDesktopNotification); err != nil {
+				if err := u.CharacterService().NotifyCommunications(ctx, characterID, earliest, typesEnabled, u.sendNotification); err != nil {
 					slog.Error("notify communications", "characterID", characterID, "error", err)
 				}
 			}()
@@ -838,9 +917,12 @@ func (u *BaseUI) updateCharacterSectionAndRefreshIfNeeded(ctx context.Context, c
 	case app.SectionSkills:
 		if needsRefresh {
 			u.overviewTraining.Update()
+			u.overviewSkillComparison.Update()
 			if isShown {
 				u.reloadCurrentCharacter()
 				u.characterSkillCatalogue.Refresh()
+				u.characterSkillInjectors.Update()
+				u.characterSkillPoints.Update()
 				u.characterShips.Update()
 				u.characterPlanets.Update()
 			}
@@ -857,6 +939,7 @@ func (u *BaseUI) updateCharacterSectionAndRefreshIfNeeded(ctx context.Context, c
 			u.characterSkillQueue.Update()
 		}
 		if needsRefresh {
+			u.overviewTimers.Update()
 			u.overviewTraining.Update()
 			u.notifyExpiredTrainingIfneeded(ctx, characterID)
 		}
@@ -886,7 +969,7 @@ func (u *BaseUI) notifyExpiredTrainingIfneeded(ctx context.Context, characerID i
 	if u.Settings().NotifyTrainingEnabled() {
 		go func() {
 			// TODO: earliest := calcNotifyEarliest(u.fyneApp.Preferences(), settingNotifyTrainingEarliest)
-			if err := u.CharacterService().NotifyExpiredTraining(ctx, characerID, u.sendDesktopNotification); err != nil {
+			if err := u.CharacterService().NotifyExpiredTraining(ctx, characerID, u.sendAlert); err != nil {
 				slog.Error("notify expired training", "error", err)
 			}
 		}()
@@ -897,13 +980,99 @@ func (u *BaseUI) notifyExpiredExtractionsIfNeeded(ctx context.Context, character
 	if u.Settings().NotifyPIEnabled() {
 		go func() {
 			earliest := u.Settings().NotifyPIEarliest()
-			if err := u.CharacterService().NotifyExpiredExtractions(ctx, characterID, earliest, u.sendDesktopNotification); err != nil {
+			if err := u.CharacterService().NotifyExpiredExtractions(ctx, characterID, earliest, u.sendAlert); err != nil {
 				slog.Error("notify expired extractions", "characterID", characterID, "error", err)
 			}
 		}()
 	}
 }
 
+// notifyMoonExtractionsIfNeeded notifies about arrived moon chunks.
+// Extractions are shared between characters, so this is not done per character.
+func (u *BaseUI) notifyMoonExtractionsIfNeeded(ctx context.Context) {
+	if u.Settings().NotifyMoonMiningEnabled() {
+		go func() {
+			earliest := u.Settings().NotifyMoonMiningEarliest()
+			if err := u.CharacterService().NotifyMoonExtractions(ctx, earliest, u.sendDesktopNotification); err != nil {
+				slog.Error("notify moon extractions", "error", err)
+			}
+		}()
+	}
+}
+
+// notifyStructureTimersIfNeeded reminds about structure timers which are about to exit.
+func (u *BaseUI) notifyStructureTimersIfNeeded(ctx context.Context) {
+	if u.Settings().NotifyTimersEnabled() {
+		go func() {
+			leadTime := time.Duration(u.Settings().NotifyTimersLeadMinutes()) * time.Minute
+			if err := u.CharacterService().NotifyStructureTimers(ctx, leadTime, u.sendDesktopNotification); err != nil {
+				slog.Error("notify structure timers", "error", err)
+			}
+		}()
+	}
+}
+
+// startMailOutbox starts the worker for sending queued mails
+// and periodically adds due mails to it, e.g. for retrying mails after an ESI outage.
+func (u *BaseUI) startMailOutbox() {
+	ctx := context.Background()
+	go u.CharacterService().RunMailOutbox(ctx, func(characterID int32) {
+		if characterID == u.CurrentCharacterID() {
+			u.characterMail.Update()
+		}
+		u.UpdateMailIndicator()
+	})
+	ticker := time.NewTicker(mailOutboxTicker)
+	for {
+		if err := u.CharacterService().QueueDueMails(ctx); err != nil {
+			slog.Error("queue due mails", "error", err)
+		}
+		<-ticker.C
+	}
+}
+
+// sendWebhookMessagesIfNeeded delivers queued messages to webhooks.
+func (u *BaseUI) sendWebhookMessagesIfNeeded(ctx context.Context) {
+	if u.IsOffline() {
+		return
+	}
+	go func() {
+		if err := u.CharacterService().SendWebhookMessages(ctx); err != nil {
+			slog.Error("send webhook messages", "error", err)
+		}
+	}()
+}
+
+// writeTimersCalendarIfNeeded writes all upcoming timer events to the configured calendar file,
+// so that calendar apps can subscribe to it.
+func (u *BaseUI) writeTimersCalendarIfNeeded(ctx context.Context) {
+	path := u.Settings().TimersCalendarPath()
+	if path == "" {
+		return
+	}
+	go func() {
+		err := func() error {
+			// write to a temporary file first, so subscribers never see a partial file
+			f, err := os.CreateTemp(filepath.Dir(path), ".evebuddy-timers-*.ics")
+			if err != nil {
+				return err
+			}
+			defer os.Remove(f.Name())
+			if err := u.CharacterService().WriteTimersCalendar(ctx, f); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			return os.Rename(f.Name(), path)
+		}()
+		if err != nil {
+			slog.Error("Failed to write timers calendar", "path", path, "error", err)
+		}
+	}()
+}
+
 func (u *BaseUI) availableUpdate() (github.VersionInfo, error) {
 	current := u.app.Metadata().Version
 	v, err := github.AvailableUpdate(githubOwner, githubRepo, current)
@@ -980,6 +1149,11 @@ func (u *BaseUI) ShowLocationInfoWindow(id int64) {
 	iw.ShowLocation(id)
 }
 
+func (u *BaseUI) ShowMoonInfoWindow(id int32) {
+	iw := infowindow.New(u)
+	iw.ShowMoon(id)
+}
+
 func (u *BaseUI) ShowRaceInfoWindow(id int32) {
 	iw := infowindow.New(u)
 	iw.ShowRace(id)
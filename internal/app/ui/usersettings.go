@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -34,6 +35,12 @@ type UserSettings struct {
 	NotificationSettings         fyne.CanvasObject // TODO: Refactor into widget
 	GeneralActions               []app.SettingAction
 	GeneralContent               fyne.CanvasObject // TODO: Refactor into widget
+	NotifiersActions             []app.SettingAction
+	NotifiersContent             fyne.CanvasObject
+	RulesActions                 []app.SettingAction
+	RulesContent                 fyne.CanvasObject
+	WebhooksActions              []app.SettingAction
+	WebhooksContent              fyne.CanvasObject
 	CommunicationGroupContent    fyne.CanvasObject // TODO: Refactor into widget
 	OnCommunicationGroupSelected func(title string, content fyne.CanvasObject, actions []app.SettingAction)
 
@@ -52,6 +59,9 @@ func NewSettings(u *BaseUI) *UserSettings {
 	a.ExtendBaseWidget(a)
 	a.GeneralContent, a.GeneralActions = a.makeGeneralSettingsPage()
 	a.NotificationSettings, a.NotificationActions = a.makeNotificationPage()
+	a.NotifiersContent, a.NotifiersActions = a.makeNotifiersPage()
+	a.RulesContent, a.RulesActions = a.makeNotificationRulesPage()
+	a.WebhooksContent, a.WebhooksActions = a.makeWebhooksPage()
 	return a
 }
 
@@ -75,6 +85,9 @@ func (a *UserSettings) CreateRenderer() fyne.WidgetRenderer {
 	tabs := container.NewAppTabs(
 		container.NewTabItem("General", makeSettingsPage("General", a.GeneralContent, a.GeneralActions)),
 		container.NewTabItem("Notifications", makeSettingsPage("Notifications", a.NotificationSettings, a.NotificationActions)),
+		container.NewTabItem("Notifiers", makeSettingsPage("Notifiers", a.NotifiersContent, a.NotifiersActions)),
+		container.NewTabItem("Rules", makeSettingsPage("Rules", a.RulesContent, a.RulesActions)),
+		container.NewTabItem("Webhooks", makeSettingsPage("Webhooks", a.WebhooksContent, a.WebhooksActions)),
 	)
 	tabs.SetTabLocation(container.TabLocationLeading)
 	return widget.NewSimpleRenderer(tabs)
@@ -173,8 +186,23 @@ func (a *UserSettings) makeGeneralSettingsPage() (fyne.CanvasObject, []app.Setti
 			a.u.Settings().SetSysTrayEnabled(v)
 		},
 	)
+	timersCalendar := iwidget.NewSettingItemEntry(
+		"Timers calendar file",
+		"Path of an iCalendar file with all upcoming timers, which is updated regularly. "+
+			"Calendar apps can subscribe to this file. Leave empty to disable.",
+		"/path/to/evebuddy-timers.ics",
+		"",
+		func() string {
+			return a.u.Settings().TimersCalendarPath()
+		},
+		func(v string) {
+			a.u.Settings().SetTimersCalendarPath(strings.TrimSpace(v))
+		},
+		a.currentWindow,
+	)
 	if a.u.IsDesktop() {
 		items = slices.Insert(items, 2, systray)
+		items = append(items, timersCalendar)
 	}
 
 	list := iwidget.NewSettingList(items)
@@ -219,6 +247,7 @@ func (a *UserSettings) makeGeneralSettingsPage() (fyne.CanvasObject, []app.Setti
 			a.u.Settings().ResetMaxMails()
 			a.u.Settings().ResetMaxWalletTransactions()
 			a.u.Settings().ResetSysTrayEnabled()
+			a.u.Settings().ResetTimersCalendarPath()
 			list.Refresh()
 		},
 	}
@@ -382,6 +411,44 @@ func (a *UserSettings) makeNotificationPage() (fyne.CanvasObject, []app.SettingA
 			}
 		},
 	)
+	notifyMoonMining := iwidget.NewSettingItemSwitch(
+		"Moon Mining",
+		"Whether to notify when a moon chunk has arrived",
+		func() bool {
+			return a.u.Settings().NotifyMoonMiningEnabled()
+		},
+		func(on bool) {
+			a.u.Settings().SetNotifyMoonMiningEnabled(on)
+			if on {
+				a.u.Settings().SetNotifyMoonMiningEarliest(time.Now())
+			}
+		},
+	)
+	notifyTimers := iwidget.NewSettingItemSwitch(
+		"Structure Timers",
+		"Whether to remind about structure timers before they exit",
+		func() bool {
+			return a.u.Settings().NotifyTimersEnabled()
+		},
+		func(on bool) {
+			a.u.Settings().SetNotifyTimersEnabled(on)
+		},
+	)
+	vMin, vMax, vDef := a.u.Settings().NotifyTimersLeadMinutesPresets()
+	notifyTimersLead := iwidget.NewSettingItemSlider(
+		"Structure Timers Reminder",
+		"Minutes before a structure timer exits to send a reminder",
+		float64(vMin),
+		float64(vMax),
+		float64(vDef),
+		func() float64 {
+			return float64(a.u.Settings().NotifyTimersLeadMinutes())
+		},
+		func(v float64) {
+			a.u.Settings().SetNotifyTimersLeadMinutes(int(v))
+		},
+		a.currentWindow,
+	)
 	notifyTraining := iwidget.NewSettingItemSwitch(
 		"Notify Training",
 		"Whether to notify abouthen skillqueue is empty",
@@ -420,7 +487,7 @@ func (a *UserSettings) makeNotificationPage() (fyne.CanvasObject, []app.SettingA
 			}
 		},
 	)
-	vMin, vMax, vDef := a.u.Settings().NotifyTimeoutHoursPresets()
+	vMin, vMax, vDef = a.u.Settings().NotifyTimeoutHoursPresets()
 	notifTimeout := iwidget.NewSettingItemSlider(
 		"Notify Timeout",
 		"Events older then this value in hours will not be notified",
@@ -440,6 +507,9 @@ func (a *UserSettings) makeNotificationPage() (fyne.CanvasObject, []app.SettingA
 		notifyCommunications,
 		notifyMails,
 		notifyPI,
+		notifyMoonMining,
+		notifyTimers,
+		notifyTimersLead,
 		notifyTraining,
 		notifyContracts,
 		notifTimeout,
@@ -556,8 +626,11 @@ func (a *UserSettings) makeNotificationPage() (fyne.CanvasObject, []app.SettingA
 			a.u.Settings().ResetNotifyCommunicationsEnabled()
 			a.u.Settings().ResetNotifyContractsEnabled()
 			a.u.Settings().ResetNotifyMailsEnabled()
+			a.u.Settings().ResetNotifyMoonMiningEnabled()
 			a.u.Settings().ResetNotifyPIEnabled()
 			a.u.Settings().ResetNotifyTimeoutHours()
+			a.u.Settings().ResetNotifyTimersEnabled()
+			a.u.Settings().ResetNotifyTimersLeadMinutes()
 			a.u.Settings().ResetNotifyTrainingEnabled()
 			typesEnabled.Clear()
 			a.u.Settings().ResetNotificationTypesEnabled()
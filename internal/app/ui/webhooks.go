@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/app/evenotification"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+// makeWebhooksPage returns a page for managing the Discord webhooks
+// which receive forwarded communications, mails and alerts.
+func (a *UserSettings) makeWebhooksPage() (fyne.CanvasObject, []app.SettingAction) {
+	webhooks := make([]*app.Webhook, 0)
+	list := widget.NewList(
+		func() int {
+			return len(webhooks)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(
+				nil,
+				nil,
+				widget.NewIcon(theme.ConfirmIcon()),
+				nil,
+				widget.NewLabel("Template"),
+			)
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			if id >= len(webhooks) {
+				return
+			}
+			w := webhooks[id]
+			border := co.(*fyne.Container).Objects
+			border[0].(*widget.Label).SetText(w.Name)
+			icon := border[1].(*widget.Icon)
+			if w.IsEnabled {
+				icon.SetResource(theme.ConfirmIcon())
+			} else {
+				icon.SetResource(theme.CancelIcon())
+			}
+		},
+	)
+	update := func() {
+		oo, err := a.u.CharacterService().ListWebhooks(context.Background())
+		if err != nil {
+			slog.Error("Failed to list webhooks", "error", err)
+			return
+		}
+		webhooks = oo
+		list.Refresh()
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		defer list.UnselectAll()
+		if id >= len(webhooks) {
+			return
+		}
+		a.showWebhookDialog(webhooks[id], update)
+	}
+	update()
+	add := app.SettingAction{
+		Label: "Add webhook",
+		Action: func() {
+			a.showWebhookDialog(nil, update)
+		},
+	}
+	return list, []app.SettingAction{add}
+}
+
+// showWebhookDialog shows a dialog for editing a webhook.
+// A new webhook is created when w is nil.
+func (a *UserSettings) showWebhookDialog(w *app.Webhook, onChanged func()) {
+	ctx := context.Background()
+	isNew := w == nil
+	if isNew {
+		w = &app.Webhook{IsEnabled: true}
+	}
+	name := widget.NewEntry()
+	name.SetText(w.Name)
+	name.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return errors.New("can not be empty")
+		}
+		return nil
+	}
+	webhookURL := widget.NewEntry()
+	webhookURL.SetText(w.URL)
+	webhookURL.PlaceHolder = "https://discord.com/api/webhooks/..."
+	webhookURL.Validator = func(s string) error {
+		u, err := url.ParseRequestURI(s)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return errors.New("not a valid URL")
+		}
+		return nil
+	}
+	isEnabled := widget.NewCheck("", nil)
+	isEnabled.SetChecked(w.IsEnabled)
+	forwardMails := widget.NewCheck("", nil)
+	forwardMails.SetChecked(w.ForwardMails)
+	forwardAlerts := widget.NewCheck("", nil)
+	forwardAlerts.SetChecked(w.ForwardAlerts)
+
+	characters, err := a.u.CharacterService().ListCharactersShort(ctx)
+	if err != nil {
+		a.u.ShowErrorDialog("Failed to load characters", err, a.currentWindow())
+		return
+	}
+	characterIDs := make(map[string]int32)
+	characterNames := make([]string, 0)
+	selectedCharacters := make([]string, 0)
+	for _, c := range characters {
+		characterIDs[c.Name] = c.ID
+		characterNames = append(characterNames, c.Name)
+		if w.CharacterIDs.Contains(c.ID) {
+			selectedCharacters = append(selectedCharacters, c.Name)
+		}
+	}
+	characterChecks := widget.NewCheckGroup(characterNames, nil)
+	characterChecks.SetSelected(selectedCharacters)
+
+	groups := make(map[string]app.NotificationGroup)
+	groupNames := make([]string, 0)
+	selectedGroups := make([]string, 0)
+	for _, g := range app.NotificationGroups() {
+		groups[g.String()] = g
+		groupNames = append(groupNames, g.String())
+		if w.Groups.Contains(g) {
+			selectedGroups = append(selectedGroups, g.String())
+		}
+	}
+	groupChecks := widget.NewCheckGroup(groupNames, nil)
+	groupChecks.SetSelected(selectedGroups)
+
+	typeNames := make([]string, 0)
+	for _, t := range evenotification.SupportedGroups() {
+		typeNames = append(typeNames, t.String())
+	}
+	slices.Sort(typeNames)
+	typeChecks := widget.NewCheckGroup(typeNames, nil)
+	typeChecks.SetSelected(w.Types.ToSlice())
+	types := container.NewVScroll(typeChecks)
+	types.SetMinSize(fyne.NewSize(0, 200))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", name),
+		widget.NewFormItem("URL", webhookURL),
+		widget.NewFormItem("Enabled", isEnabled),
+		widget.NewFormItem("Forward mails", forwardMails),
+		widget.NewFormItem("Forward alerts", forwardAlerts),
+		{
+			Text:     "Characters",
+			Widget:   characterChecks,
+			HintText: "Forward only for these characters. All when none selected.",
+		},
+		{
+			Text:     "Groups",
+			Widget:   groupChecks,
+			HintText: "Forward communications of these groups.",
+		},
+		{
+			Text:     "Types",
+			Widget:   types,
+			HintText: "Forward communications of these types.",
+		},
+	}
+	parent := a.currentWindow()
+	var d dialog.Dialog
+	if !isNew {
+		deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+			a.u.ShowConfirmDialog("Delete webhook", "Are you sure you want to delete "+w.Name+"?", "Delete", func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				if err := a.u.CharacterService().DeleteWebhook(ctx, w.ID); err != nil {
+					a.u.ShowErrorDialog("Failed to delete webhook", err, parent)
+					return
+				}
+				d.Hide()
+				onChanged()
+			}, parent)
+		})
+		deleteButton.Importance = widget.DangerImportance
+		items = append(items, widget.NewFormItem("", container.NewHBox(deleteButton)))
+	}
+	var title, confirm string
+	if isNew {
+		title, confirm = "Add webhook", "Add"
+	} else {
+		title, confirm = "Edit webhook", "Save"
+	}
+	d = dialog.NewForm(title, confirm, "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		w.Name = strings.TrimSpace(name.Text)
+		w.URL = strings.TrimSpace(webhookURL.Text)
+		w.IsEnabled = isEnabled.Checked
+		w.ForwardMails = forwardMails.Checked
+		w.ForwardAlerts = forwardAlerts.Checked
+		w.CharacterIDs = set.New[int32]()
+		for _, n := range characterChecks.Selected {
+			w.CharacterIDs.Add(characterIDs[n])
+		}
+		w.Groups = set.New[app.NotificationGroup]()
+		for _, n := range groupChecks.Selected {
+			w.Groups.Add(groups[n])
+		}
+		w.Types = set.NewFromSlice(typeChecks.Selected)
+		var err error
+		if isNew {
+			_, err = a.u.CharacterService().CreateWebhook(ctx, w)
+		} else {
+			err = a.u.CharacterService().UpdateWebhook(ctx, w)
+		}
+		if err != nil {
+			a.u.ShowErrorDialog("Failed to save webhook", err, parent)
+			return
+		}
+		onChanged()
+	}, parent)
+	a.u.ModifyShortcutsForDialog(d, parent)
+	d.Resize(fyne.NewSize(600, 700))
+	d.Show()
+}
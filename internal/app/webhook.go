@@ -0,0 +1,58 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+)
+
+// Webhook is a Discord webhook which receives forwarded communications, mails and alerts.
+type Webhook struct {
+	ID            int64
+	CharacterIDs  set.Set[int32] // forward only for these characters. All characters when empty.
+	CreatedAt     time.Time
+	ForwardAlerts bool                       // whether to forward app alerts, e.g. expired training
+	ForwardMails  bool                       // whether to forward new mails
+	Groups        set.Set[NotificationGroup] // communications of these groups are forwarded
+	IsEnabled     bool
+	Name          string
+	Types         set.Set[string] // communications of these types are forwarded
+	URL           string
+}
+
+// MatchesCharacter reports whether a webhook forwards items of a character.
+func (w Webhook) MatchesCharacter(characterID int32) bool {
+	if !w.IsEnabled {
+		return false
+	}
+	return w.CharacterIDs.Size() == 0 || w.CharacterIDs.Contains(characterID)
+}
+
+// MatchesNotification reports whether a webhook forwards a communication.
+func (w Webhook) MatchesNotification(characterID int32, group NotificationGroup, type_ string) bool {
+	if !w.MatchesCharacter(characterID) {
+		return false
+	}
+	return w.Groups.Contains(group) || w.Types.Contains(type_)
+}
+
+// MatchesMail reports whether a webhook forwards a mail.
+func (w Webhook) MatchesMail(characterID int32) bool {
+	return w.ForwardMails && w.MatchesCharacter(characterID)
+}
+
+// MatchesAlert reports whether a webhook forwards app alerts.
+func (w Webhook) MatchesAlert() bool {
+	return w.IsEnabled && w.ForwardAlerts
+}
+
+// WebhookMessage is a message in the outbox waiting to be delivered to a webhook.
+type WebhookMessage struct {
+	ID            int64
+	Attempts      int
+	CreatedAt     time.Time
+	LastError     string
+	NextAttemptAt time.Time
+	Payload       []byte // JSON encoded message
+	WebhookID     int64
+}
@@ -0,0 +1,61 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/set"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookMatchesNotification(t *testing.T) {
+	cases := []struct {
+		name        string
+		webhook     app.Webhook
+		characterID int32
+		want        bool
+	}{
+		{
+			"should match group",
+			app.Webhook{IsEnabled: true, Groups: set.New(app.GroupStructure)},
+			1,
+			true,
+		},
+		{
+			"should match type",
+			app.Webhook{IsEnabled: true, Types: set.New("StructureUnderAttack")},
+			1,
+			true,
+		},
+		{
+			"should match character",
+			app.Webhook{IsEnabled: true, Groups: set.New(app.GroupStructure), CharacterIDs: set.New[int32](1)},
+			1,
+			true,
+		},
+		{
+			"should not match other character",
+			app.Webhook{IsEnabled: true, Groups: set.New(app.GroupStructure), CharacterIDs: set.New[int32](2)},
+			1,
+			false,
+		},
+		{
+			"should not match when disabled",
+			app.Webhook{Groups: set.New(app.GroupStructure)},
+			1,
+			false,
+		},
+		{
+			"should not match without groups and types",
+			app.Webhook{IsEnabled: true},
+			1,
+			false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.webhook.MatchesNotification(tc.characterID, app.GroupStructure, "StructureUnderAttack")
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package widget
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/ErikKalkoken/evebuddy/internal/app"
+	"github.com/ErikKalkoken/evebuddy/internal/evehtml"
+)
+
+const (
+	eveHTMLColorPrefix = "evehtml-color-"
+	eveHTMLSizePrefix  = "evehtml-size-"
+	eveHTMLDefaultSize = 12 // default font size of the Eve client
+)
+
+// EveHTML is a widget for showing Eve Online HTML texts, e.g. the body of a mail.
+//
+// It keeps the font colors and sizes of the text.
+// Showinfo links open the info window of the linked object and killmail links open zKillboard.
+type EveHTML struct {
+	widget.BaseWidget
+
+	// ShowInfo is called when a showinfo link is tapped with the category and ID of the linked object.
+	// Inventory types are reported with their type ID and locations like stations with [app.EveEntityStation].
+	ShowInfo func(c app.EveEntityCategory, id int64)
+
+	text *widget.RichText
+}
+
+// NewEveHTML returns a new EveHTML widget.
+func NewEveHTML(showInfo func(c app.EveEntityCategory, id int64)) *EveHTML {
+	w := &EveHTML{
+		ShowInfo: showInfo,
+		text:     widget.NewRichText(),
+	}
+	w.text.Wrapping = fyne.TextWrapWord
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+// NewEveHTMLWithUI returns a new EveHTML widget, which shows info windows through u.
+func NewEveHTMLWithUI(u app.UI) *EveHTML {
+	return NewEveHTML(func(c app.EveEntityCategory, id int64) {
+		if c == app.EveEntityStation {
+			u.ShowLocationInfoWindow(id)
+			return
+		}
+		u.ShowInfoWindow(c, int32(id))
+	})
+}
+
+// SetText sets the content of the widget from an Eve Online HTML text.
+func (w *EveHTML) SetText(s string) {
+	w.text.Segments = w.makeSegments(evehtml.Parse(s))
+	w.text.Refresh()
+}
+
+// SetMarkdown sets the content of the widget from a markdown text.
+func (w *EveHTML) SetMarkdown(s string) {
+	w.text.ParseMarkdown(s)
+}
+
+func (w *EveHTML) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewThemeOverride(w.text, eveHTMLTheme{}))
+}
+
+func (w *EveHTML) makeSegments(spans []evehtml.Span) []widget.RichTextSegment {
+	segs := make([]widget.RichTextSegment, 0, len(spans))
+	for _, s := range spans {
+		if s.Link != "" {
+			if seg := w.makeLinkSegment(s); seg != nil {
+				segs = append(segs, seg)
+				continue
+			}
+		}
+		style := widget.RichTextStyle{
+			Inline: true,
+			TextStyle: fyne.TextStyle{
+				Bold:      s.Bold,
+				Italic:    s.Italic,
+				Underline: s.Underline,
+			},
+		}
+		if isColorful(s.Color) {
+			style.ColorName = fyne.ThemeColorName(eveHTMLColorPrefix + s.Color)
+		}
+		if s.Size > 0 && s.Size != eveHTMLDefaultSize {
+			style.SizeName = fyne.ThemeSizeName(eveHTMLSizePrefix + strconv.Itoa(s.Size))
+		}
+		segs = append(segs, &widget.TextSegment{Text: s.Text, Style: style})
+	}
+	return segs
+}
+
+// makeLinkSegment returns a segment for a link or nil when the link is not supported.
+func (w *EveHTML) makeLinkSegment(s evehtml.Span) widget.RichTextSegment {
+	l := evehtml.ParseLink(s.Link)
+	switch l.Kind {
+	case evehtml.LinkWeb:
+		u, err := url.Parse(l.URL)
+		if err != nil {
+			return nil
+		}
+		return &widget.HyperlinkSegment{Text: s.Text, URL: u}
+	case evehtml.LinkKillReport:
+		u, _ := url.Parse(fmt.Sprintf("https://zkillboard.com/kill/%d/", l.ItemID))
+		return &widget.HyperlinkSegment{Text: s.Text, URL: u}
+	case evehtml.LinkShowInfo:
+		c, id, ok := showInfoTarget(l)
+		if !ok {
+			return nil
+		}
+		return &widget.HyperlinkSegment{Text: s.Text, OnTapped: func() {
+			if w.ShowInfo != nil {
+				w.ShowInfo(c, id)
+			}
+		}}
+	}
+	return nil
+}
+
+// showInfoTarget returns the category and ID of the object linked by a showinfo link
+// and reports whether it is supported.
+func showInfoTarget(l evehtml.Link) (app.EveEntityCategory, int64, bool) {
+	switch t := l.TypeID; {
+	case t >= 1373 && t <= 1386:
+		return app.EveEntityCharacter, l.ItemID, l.ItemID != 0
+	case t == 2:
+		return app.EveEntityCorporation, l.ItemID, l.ItemID != 0
+	case t == 3:
+		return app.EveEntityRegion, l.ItemID, l.ItemID != 0
+	case t == 4:
+		return app.EveEntityConstellation, l.ItemID, l.ItemID != 0
+	case t == 5:
+		return app.EveEntitySolarSystem, l.ItemID, l.ItemID != 0
+	case t == 16159:
+		return app.EveEntityAlliance, l.ItemID, l.ItemID != 0
+	case isLocationID(l.ItemID):
+		return app.EveEntityStation, l.ItemID, true
+	}
+	return app.EveEntityInventoryType, int64(l.TypeID), l.TypeID != 0
+}
+
+// isLocationID reports whether an ID belongs to a station or structure.
+func isLocationID(id int64) bool {
+	return (id >= 60_000_000 && id < 64_000_000) || id >= 1_000_000_000_000
+}
+
+// isColorful reports whether a color in #AARRGGBB format should be shown.
+// Shades of gray are ignored, because the Eve client uses them for normal text on a dark background,
+// which would be hard to read with a light theme.
+func isColorful(s string) bool {
+	c, ok := parseEveColor(s)
+	if !ok {
+		return false
+	}
+	return max(c.R, c.G, c.B)-min(c.R, c.G, c.B) > 0x20
+}
+
+func parseEveColor(s string) (color.NRGBA, bool) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 8 {
+		return color.NRGBA{}, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, false
+	}
+	return color.NRGBA{A: uint8(v >> 24), R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, true
+}
+
+// eveHTMLTheme is a theme which provides the colors and sizes of Eve Online HTML texts.
+// All other values are taken from the current app theme.
+type eveHTMLTheme struct{}
+
+var _ fyne.Theme = (*eveHTMLTheme)(nil)
+
+func (eveHTMLTheme) base() fyne.Theme {
+	return fyne.CurrentApp().Settings().Theme()
+}
+
+func (t eveHTMLTheme) Color(n fyne.ThemeColorName, v fyne.ThemeVariant) color.Color {
+	if s, ok := strings.CutPrefix(string(n), eveHTMLColorPrefix); ok {
+		if c, ok := parseEveColor(s); ok {
+			return c
+		}
+		slog.Warn("Invalid color in Eve HTML", "color", s)
+		n = theme.ColorNameForeground
+	}
+	return t.base().Color(n, v)
+}
+
+func (t eveHTMLTheme) Font(s fyne.TextStyle) fyne.Resource {
+	return t.base().Font(s)
+}
+
+func (t eveHTMLTheme) Icon(n fyne.ThemeIconName) fyne.Resource {
+	return t.base().Icon(n)
+}
+
+func (t eveHTMLTheme) Size(n fyne.ThemeSizeName) float32 {
+	if s, ok := strings.CutPrefix(string(n), eveHTMLSizePrefix); ok {
+		x := t.base().Size(theme.SizeNameText)
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return x
+		}
+		return x * float32(v) / eveHTMLDefaultSize
+	}
+	return t.base().Size(n)
+}
@@ -0,0 +1,167 @@
+// Package discord contains a client for sending messages to Discord webhooks.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits for messages as defined by Discord.
+const (
+	MaxEmbedTitle       = 256
+	MaxEmbedDescription = 4096
+	MaxEmbedFooter      = 2048
+	MaxEmbedAuthorName  = 256
+)
+
+// Errors returned by the client.
+var (
+	ErrHTTP       = errors.New("HTTP error")  // webhook responded with an HTTP error
+	ErrInvalidURL = errors.New("invalid URL") // URL of the webhook is missing or invalid
+)
+
+// Message is a message for a Discord webhook.
+type Message struct {
+	Username string  `json:"username,omitempty"`
+	Content  string  `json:"content,omitempty"`
+	Embeds   []Embed `json:"embeds,omitempty"`
+}
+
+// Embed is a rich content block of a [Message].
+type Embed struct {
+	Author      *EmbedAuthor `json:"author,omitempty"`
+	Color       int          `json:"color,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Footer      *EmbedFooter `json:"footer,omitempty"`
+	Timestamp   string       `json:"timestamp,omitempty"` // in ISO8601 format
+	Title       string       `json:"title,omitempty"`
+	URL         string       `json:"url,omitempty"`
+}
+
+type EmbedAuthor struct {
+	Name string `json:"name"`
+}
+
+type EmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// NewEmbed returns a new embed and ensures all texts are within Discord's limits.
+func NewEmbed(title, description, author, footer string, timestamp time.Time) Embed {
+	em := Embed{
+		Title:       truncate(title, MaxEmbedTitle),
+		Description: truncate(description, MaxEmbedDescription),
+	}
+	if author != "" {
+		em.Author = &EmbedAuthor{Name: truncate(author, MaxEmbedAuthorName)}
+	}
+	if footer != "" {
+		em.Footer = &EmbedFooter{Text: truncate(footer, MaxEmbedFooter)}
+	}
+	if !timestamp.IsZero() {
+		em.Timestamp = timestamp.UTC().Format(time.RFC3339)
+	}
+	return em
+}
+
+// truncate returns s shortened to at most max characters.
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:max-1]) + "…"
+}
+
+// HTTPError represents an error response from a webhook.
+type HTTPError struct {
+	StatusCode int
+	RetryAfter time.Duration // how long to wait before retrying. Only set for rate limits.
+	Message    string
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("%s: %d: %s", ErrHTTP, e.StatusCode, e.Message)
+}
+
+func (e HTTPError) Unwrap() error {
+	return ErrHTTP
+}
+
+// IsTemporary reports whether a request might succeed when retried later.
+func (e HTTPError) IsTemporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Client is a client for sending messages to Discord webhooks.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient returns a new client. When nil is passed a default HTTP client is used.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	c := &Client{httpClient: httpClient}
+	return c
+}
+
+// Send sends a message to the webhook at webhookURL.
+// It returns an [HTTPError] when the webhook responds with an error
+// and [ErrInvalidURL] when webhookURL is not a valid HTTP URL.
+func (c *Client) Send(ctx context.Context, webhookURL string, m Message) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidURL, webhookURL)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	err2 := HTTPError{StatusCode: resp.StatusCode, Message: string(body)}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		err2.RetryAfter = retryAfter(resp.Header, body)
+	}
+	return err2
+}
+
+// retryAfter returns the retry duration of a rate limited response.
+func retryAfter(h http.Header, body []byte) time.Duration {
+	var x struct {
+		RetryAfter float64 `json:"retry_after"` // in seconds
+	}
+	if err := json.Unmarshal(body, &x); err == nil && x.RetryAfter > 0 {
+		return time.Duration(x.RetryAfter * float64(time.Second))
+	}
+	if v, err := strconv.ParseFloat(h.Get("Retry-After"), 64); err == nil && v > 0 {
+		return time.Duration(v * float64(time.Second))
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+package discord_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/discord"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	const url = "https://discord.com/api/webhooks/123/abc"
+	c := discord.NewClient(nil)
+	ctx := context.Background()
+	m := discord.Message{Embeds: []discord.Embed{{Title: "title", Description: "description"}}}
+	t.Run("can send message", func(t *testing.T) {
+		// given
+		httpmock.Reset()
+		var body string
+		httpmock.RegisterResponder("POST", url, func(r *http.Request) (*http.Response, error) {
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				return nil, err
+			}
+			body = string(b)
+			return httpmock.NewStringResponse(204, ""), nil
+		})
+		// when
+		err := c.Send(ctx, url, m)
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, httpmock.GetTotalCallCount())
+			assert.JSONEq(t, `{"embeds":[{"title":"title","description":"description"}]}`, body)
+		}
+	})
+	t.Run("should report rate limit with retry duration", func(t *testing.T) {
+		// given
+		httpmock.Reset()
+		httpmock.RegisterResponder("POST", url, httpmock.NewJsonResponderOrPanic(429, map[string]any{
+			"message":     "You are being rate limited.",
+			"retry_after": 1.5,
+			"global":      false,
+		}))
+		// when
+		err := c.Send(ctx, url, m)
+		// then
+		var err2 discord.HTTPError
+		if assert.True(t, errors.As(err, &err2)) {
+			assert.Equal(t, 429, err2.StatusCode)
+			assert.Equal(t, 1500*time.Millisecond, err2.RetryAfter)
+			assert.True(t, err2.IsTemporary())
+		}
+		assert.ErrorIs(t, err, discord.ErrHTTP)
+	})
+	t.Run("should report permanent errors", func(t *testing.T) {
+		// given
+		httpmock.Reset()
+		httpmock.RegisterResponder("POST", url, httpmock.NewStringResponder(404, "Unknown Webhook"))
+		// when
+		err := c.Send(ctx, url, m)
+		// then
+		var err2 discord.HTTPError
+		if assert.True(t, errors.As(err, &err2)) {
+			assert.False(t, err2.IsTemporary())
+		}
+	})
+	t.Run("should report invalid URLs", func(t *testing.T) {
+		for _, u := range []string{"", "discord.com/api/webhooks/123/abc", "ftp://discord.com/x", "https://"} {
+			// given
+			httpmock.Reset()
+			// when
+			err := c.Send(ctx, u, m)
+			// then
+			assert.ErrorIs(t, err, discord.ErrInvalidURL, u)
+			assert.Equal(t, 0, httpmock.GetTotalCallCount())
+		}
+	})
+}
+
+func TestNewEmbed(t *testing.T) {
+	t.Run("should truncate long texts", func(t *testing.T) {
+		x := discord.NewEmbed(strings.Repeat("ä", 300), "description", "author", "", time.Time{})
+		assert.Equal(t, discord.MaxEmbedTitle, len([]rune(x.Title)))
+		assert.True(t, strings.HasSuffix(x.Title, "…"))
+		assert.Equal(t, "description", x.Description)
+		assert.Equal(t, "author", x.Author.Name)
+		assert.Nil(t, x.Footer)
+		assert.Empty(t, x.Timestamp)
+	})
+	t.Run("should set timestamp", func(t *testing.T) {
+		x := discord.NewEmbed("title", "", "", "footer", time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC))
+		assert.Equal(t, "2025-03-14T18:30:00Z", x.Timestamp)
+		assert.Equal(t, "footer", x.Footer.Text)
+	})
+}
@@ -0,0 +1,107 @@
+// Package eft implements parsing of ship fittings in the EFT format,
+// which is used by the Eve Online client for copying and pasting fittings.
+package eft
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is returned when a text is not a valid EFT fitting.
+var ErrInvalid = errors.New("invalid EFT fitting")
+
+var (
+	headerRx   = regexp.MustCompile(`^\[([^,\]]+),\s*(.*)\]$`)
+	emptySlot  = regexp.MustCompile(`^\[Empty .+\]$`)
+	quantityRx = regexp.MustCompile(`^(.+?)\s+x(\d+)$`)
+)
+
+// Item is a fitted module, charge, drone or cargo item.
+type Item struct {
+	Name     string
+	Quantity int
+}
+
+// Fitting is a ship fitting.
+type Fitting struct {
+	Name     string // name of the fitting
+	ShipType string // name of the ship type
+	Modules  []Item // fitted modules, rigs and subsystems
+	Charges  []Item // charges loaded into modules
+	Items    []Item // items with quantities, e.g. drones and cargo
+}
+
+// TypeNames returns the names of all types in the fitting including the ship type
+// without duplicates and in order of appearance.
+func (f Fitting) TypeNames() []string {
+	names := []string{f.ShipType}
+	for _, items := range [][]Item{f.Modules, f.Charges, f.Items} {
+		for _, it := range items {
+			if !slices.Contains(names, it.Name) {
+				names = append(names, it.Name)
+			}
+		}
+	}
+	return names
+}
+
+// Parse parses a fitting in the EFT format and returns it.
+//
+// The first line must be a header with the ship type and the fitting name, e.g. "[Tengu, My Tengu]".
+// Each following line is a module with an optional charge, e.g. "Heavy Missile Launcher II, Scourge Heavy Missile",
+// or an item with a quantity, e.g. "Hornet II x5". Empty slots and offline markers are ignored.
+func Parse(s string) (*Fitting, error) {
+	var f *Fitting
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if f == nil {
+			m := headerRx.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("header: %s: %w", line, ErrInvalid)
+			}
+			f = &Fitting{
+				Name:     strings.TrimSpace(m[2]),
+				ShipType: strings.TrimSpace(m[1]),
+				Modules:  make([]Item, 0),
+				Charges:  make([]Item, 0),
+				Items:    make([]Item, 0),
+			}
+			continue
+		}
+		if emptySlot.MatchString(line) {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimSuffix(line, "/OFFLINE"))
+		if m := quantityRx.FindStringSubmatch(line); m != nil {
+			n, err := strconv.Atoi(m[2])
+			if err != nil {
+				return nil, fmt.Errorf("quantity: %s: %w", line, ErrInvalid)
+			}
+			f.Items = append(f.Items, Item{Name: m[1], Quantity: n})
+			continue
+		}
+		module, charge, found := strings.Cut(line, ",")
+		f.Modules = append(f.Modules, Item{Name: strings.TrimSpace(module), Quantity: 1})
+		if found {
+			if charge = strings.TrimSpace(charge); charge != "" {
+				f.Charges = append(f.Charges, Item{Name: charge, Quantity: 1})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("empty: %w", ErrInvalid)
+	}
+	return f, nil
+}
@@ -0,0 +1,74 @@
+package eft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/eft"
+)
+
+const tenguFitting = `[Tengu, Doctrine Tengu]
+Ballistic Control System II
+Ballistic Control System II
+[Empty Low slot]
+
+Large Shield Extender II
+Multispectrum Shield Hardener II /OFFLINE
+
+Heavy Assault Missile Launcher II, Scourge Rage Heavy Assault Missile
+Heavy Assault Missile Launcher II, Scourge Rage Heavy Assault Missile
+
+Hornet II x5
+
+
+Scourge Rage Heavy Assault Missile x1000
+`
+
+func TestParse(t *testing.T) {
+	t.Run("can parse fitting", func(t *testing.T) {
+		got, err := eft.Parse(tenguFitting)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Tengu", got.ShipType)
+			assert.Equal(t, "Doctrine Tengu", got.Name)
+			assert.Equal(t, []eft.Item{
+				{Name: "Ballistic Control System II", Quantity: 1},
+				{Name: "Ballistic Control System II", Quantity: 1},
+				{Name: "Large Shield Extender II", Quantity: 1},
+				{Name: "Multispectrum Shield Hardener II", Quantity: 1},
+				{Name: "Heavy Assault Missile Launcher II", Quantity: 1},
+				{Name: "Heavy Assault Missile Launcher II", Quantity: 1},
+			}, got.Modules)
+			assert.Equal(t, []eft.Item{
+				{Name: "Scourge Rage Heavy Assault Missile", Quantity: 1},
+				{Name: "Scourge Rage Heavy Assault Missile", Quantity: 1},
+			}, got.Charges)
+			assert.Equal(t, []eft.Item{
+				{Name: "Hornet II", Quantity: 5},
+				{Name: "Scourge Rage Heavy Assault Missile", Quantity: 1000},
+			}, got.Items)
+		}
+	})
+	t.Run("can return type names without duplicates", func(t *testing.T) {
+		f, err := eft.Parse(tenguFitting)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{
+				"Tengu",
+				"Ballistic Control System II",
+				"Large Shield Extender II",
+				"Multispectrum Shield Hardener II",
+				"Heavy Assault Missile Launcher II",
+				"Scourge Rage Heavy Assault Missile",
+				"Hornet II",
+			}, f.TypeNames())
+		}
+	})
+	t.Run("should return error when header is missing", func(t *testing.T) {
+		_, err := eft.Parse("Hornet II x5")
+		assert.ErrorIs(t, err, eft.ErrInvalid)
+	})
+	t.Run("should return error when text is empty", func(t *testing.T) {
+		_, err := eft.Parse("\n\n")
+		assert.ErrorIs(t, err, eft.ErrInvalid)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 
 	md "github.com/JohannesKaufmann/html-to-markdown"
@@ -79,6 +80,125 @@ func patchLinks(s string) string {
 	return string(reLinks.ReplaceAll([]byte(s), []byte("$1 $2")))
 }
 
+// FromPlain converts plain text to Eve Online HTML text and returns it.
+func FromPlain(s string) string {
+	t := html.EscapeString(s)
+	return strings.ReplaceAll(t, "\n", "<br>")
+}
+
+// ShowInfoLink returns an Eve Online HTML link for showing information about an object.
+// An itemID of 0 links to the type only, e.g. for showing information about a ship type.
+func ShowInfoLink(typeID, itemID int32, text string) string {
+	if itemID == 0 {
+		return fmt.Sprintf(`<a href="showinfo:%d">%s</a>`, typeID, html.EscapeString(text))
+	}
+	return fmt.Sprintf(`<a href="showinfo:%d//%d">%s</a>`, typeID, itemID, html.EscapeString(text))
+}
+
+var (
+	reRichTextTag   = regexp.MustCompile(`(?i)<(/?)(a|b|br|font|i|u)((?:\s+[a-z]+\s*=\s*"[^"<>]*")*)\s*/?>`)
+	reRichTextAttr  = regexp.MustCompile(`(?i)([a-z]+)\s*=\s*"([^"<>]*)"`)
+	reFontSize      = regexp.MustCompile(`^[1-9][0-9]?$`)
+	reLinkReference = regexp.MustCompile(`(?i)^(showinfo:\d+(//\d+)?|killreport:\d+:[0-9a-f]+|https?://\S+)$`)
+)
+
+// FromRichText converts text with Eve Online HTML tags as written in the mail composer
+// to Eve Online HTML text and returns it.
+//
+// The tags rendered by the Eve client are kept, i.e. bold, italic, underline,
+// font with color and size and links to showinfo, killreport and web pages.
+// Everything else is escaped and line breaks are converted, same as with [FromPlain].
+// Font colors are normalized to the #AARRGGBB format of the Eve client.
+func FromRichText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	var b strings.Builder
+	var last int
+	var openTags []string // stack of tags which are not yet closed
+	for _, m := range reRichTextTag.FindAllStringSubmatchIndex(s, -1) {
+		isClosing := s[m[2]:m[3]] == "/"
+		name := strings.ToLower(s[m[4]:m[5]])
+		tag, ok := richTextTag(isClosing, name, s[m[6]:m[7]])
+		if !ok {
+			continue
+		}
+		if isClosing {
+			i := lastIndex(openTags, name)
+			if i == -1 {
+				continue // closing tag without opening tag
+			}
+			openTags = slices.Delete(openTags, i, i+1)
+		} else if name != "br" {
+			openTags = append(openTags, name)
+		}
+		b.WriteString(FromPlain(s[last:m[0]]))
+		b.WriteString(tag)
+		last = m[1]
+	}
+	b.WriteString(FromPlain(s[last:]))
+	for _, name := range slices.Backward(openTags) {
+		b.WriteString("</" + name + ">") // close open tags, innermost first
+	}
+	return b.String()
+}
+
+// lastIndex returns the index of the last occurrence of v in s or -1 if not found.
+func lastIndex(s []string, v string) int {
+	for i, x := range slices.Backward(s) {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// richTextTag returns a normalized tag and reports whether the tag is supported.
+func richTextTag(isClosing bool, name, attrs string) (string, bool) {
+	if isClosing {
+		if name == "br" {
+			return "", false
+		}
+		return "</" + name + ">", true
+	}
+	values := make(map[string]string)
+	for _, m := range reRichTextAttr.FindAllStringSubmatch(attrs, -1) {
+		values[strings.ToLower(m[1])] = html.UnescapeString(m[2])
+	}
+	switch name {
+	case "a":
+		href := values["href"]
+		if !reLinkReference.MatchString(href) {
+			return "", false
+		}
+		return fmt.Sprintf(`<a href="%s">`, html.EscapeString(href)), true
+	case "font":
+		var b strings.Builder
+		b.WriteString("<font")
+		if v := values["size"]; reFontSize.MatchString(v) {
+			fmt.Fprintf(&b, ` size="%s"`, v)
+		}
+		if v, ok := parseColor(values["color"]); ok {
+			fmt.Fprintf(&b, ` color="%s"`, v)
+		}
+		b.WriteString(">")
+		return b.String(), true
+	}
+	return "<" + name + ">", true
+}
+
+// Quote returns an Eve Online HTML text quoting another text in the same way as the Eve client does.
+// The lines of the header must already be Eve Online HTML.
+func Quote(header []string, body string) string {
+	var b strings.Builder
+	b.WriteString("<br><br>--------------------------------<br>")
+	for _, l := range header {
+		b.WriteString(l)
+		b.WriteString("<br>")
+	}
+	b.WriteString("<br>")
+	b.WriteString(body)
+	return b.String()
+}
+
 // Strip removes all XML/HTML from a given string and return the result.
 func Strip(xml string) string {
 	bodyPolicy := bluemonday.StrictPolicy()
@@ -71,3 +71,58 @@ func TestStrip(t *testing.T) {
 	want := "Helgatild - 9-12"
 	assert.Equal(t, want, got)
 }
+
+func TestFromPlain(t *testing.T) {
+	got := evehtml.FromPlain("first <line> & more\nsecond line")
+	want := "first &lt;line&gt; &amp; more<br>second line"
+	assert.Equal(t, want, got)
+}
+
+func TestShowInfoLink(t *testing.T) {
+	got := evehtml.ShowInfoLink(1376, 93330670, "Erik")
+	want := `<a href="showinfo:1376//93330670">Erik</a>`
+	assert.Equal(t, want, got)
+}
+
+func TestQuote(t *testing.T) {
+	got := evehtml.Quote([]string{"From: Erik", "To: Peter"}, "Hello<br>World")
+	want := "<br><br>--------------------------------<br>From: Erik<br>To: Peter<br><br>Hello<br>World"
+	assert.Equal(t, want, got)
+	assert.Equal(t, "\n\n--------------------------------\nFrom: Erik\nTo: Peter\n\nHello\nWorld", evehtml.ToPlain(got))
+}
+
+func TestShowInfoLinkForType(t *testing.T) {
+	got := evehtml.ShowInfoLink(587, 0, "Rifter")
+	want := `<a href="showinfo:587">Rifter</a>`
+	assert.Equal(t, want, got)
+}
+
+func TestFromRichText(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "first <line> & more\nsecond line", "first &lt;line&gt; &amp; more<br>second line"},
+		{"bold", "<b>bold</b> text", "<b>bold</b> text"},
+		{"italic and underline", "<I>a</I><u>b</u>", "<i>a</i><u>b</u>"},
+		{"line break tag", "a<br/>b", "a<br>b"},
+		{"font color", `<font color="#00ff00">x</font>`, `<font color="#ff00ff00">x</font>`},
+		{"font size and color", `<font size="14" color="#80FF0000">x</font>`, `<font size="14" color="#80ff0000">x</font>`},
+		{"font with invalid attributes", `<font size="big" color="red" face="x">x</font>`, `<font>x</font>`},
+		{"showinfo link", `<a href="showinfo:1376//93330670">Erik</a>`, `<a href="showinfo:1376//93330670">Erik</a>`},
+		{"type link", `<a href="showinfo:587">Rifter</a>`, `<a href="showinfo:587">Rifter</a>`},
+		{"web link", `<a href="https://www.example.com/?a=1&amp;b=2">x</a>`, `<a href="https://www.example.com/?a=1&amp;b=2">x</a>`},
+		{"killreport link", `<a href="killReport:123:abc1">x</a>`, `<a href="killReport:123:abc1">x</a>`},
+		{"script link", `<a href="javascript:alert(1)">x</a>`, `&lt;a href=&#34;javascript:alert(1)&#34;&gt;x&lt;/a&gt;`},
+		{"closing tag without opening tag", "x</b>", "x&lt;/b&gt;"},
+		{"unsupported tag", "<script>x</script>", "&lt;script&gt;x&lt;/script&gt;"},
+		{"unclosed tag", "<b>hi", "<b>hi</b>"},
+		{"unclosed nested tags", `<b>a<font color="#ff0000">b<i>c</i>`, `<b>a<font color="#ffff0000">b<i>c</i></font></b>`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, evehtml.FromRichText(tc.in))
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package evehtml
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// A Span is a part of a text with the same format.
+type Span struct {
+	Text      string
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Color     string // font color in #AARRGGBB format or empty for the default color
+	Size      int    // font size or 0 for the default size
+	Link      string // target of a link or empty when the span is not a link
+}
+
+func (s Span) hasSameFormat(other Span) bool {
+	other.Text = s.Text
+	return s == other
+}
+
+type openTag struct {
+	name   string
+	format Span // format before the tag was opened
+}
+
+// Parse parses an Eve Online HTML text and returns it as formatted spans.
+//
+// Besides the tags supported by the Eve client it also supports
+// paragraphs, headers and lists as generated from Markdown.
+// Line breaks are returned as new lines in the text of spans.
+func Parse(s string) []Span {
+	var spans []Span
+	var current Span
+	var stack []openTag
+	var newLines int // number of line breaks at the end of the text
+	add := func(text string) {
+		if text == "" {
+			return
+		}
+		if x := strings.TrimRight(text, "\n"); x == "" {
+			newLines += len(text)
+		} else {
+			newLines = len(text) - len(x)
+		}
+		if n := len(spans); n > 0 && spans[n-1].hasSameFormat(current) {
+			spans[n-1].Text += text
+			return
+		}
+		x := current
+		x.Text = text
+		spans = append(spans, x)
+	}
+	isLineStart := func() bool {
+		return len(spans) == 0 || newLines > 0
+	}
+	newLine := func() {
+		x := current
+		current = Span{}
+		add("\n")
+		current = x
+	}
+	push := func(name string) {
+		stack = append(stack, openTag{name: name, format: current})
+	}
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		t := z.Token()
+		switch tt {
+		case html.TextToken:
+			text := strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(t.Data)
+			if isLineStart() {
+				text = strings.TrimLeft(text, " ")
+			}
+			add(text)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch t.Data {
+			case "br":
+				newLine()
+			case "hr":
+				if !isLineStart() {
+					newLine()
+				}
+				add("----------")
+				newLine()
+			case "p", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol":
+				if len(spans) > 0 {
+					for range 2 - min(newLines, 2) {
+						newLine()
+					}
+				}
+				if strings.HasPrefix(t.Data, "h") {
+					push(t.Data)
+					current.Bold = true
+				}
+			case "li":
+				if !isLineStart() {
+					newLine()
+				}
+				add("• ")
+			case "b", "strong":
+				push(t.Data)
+				current.Bold = true
+			case "i", "em":
+				push(t.Data)
+				current.Italic = true
+			case "u":
+				push(t.Data)
+				current.Underline = true
+			case "font":
+				push(t.Data)
+				for _, a := range t.Attr {
+					switch a.Key {
+					case "color":
+						if c, ok := parseColor(a.Val); ok {
+							current.Color = c
+						}
+					case "size":
+						if v, err := strconv.Atoi(a.Val); err == nil && v > 0 {
+							current.Size = v
+						}
+					}
+				}
+			case "a":
+				push(t.Data)
+				for _, a := range t.Attr {
+					if a.Key == "href" {
+						current.Link = a.Val
+					}
+				}
+			}
+		case html.EndTagToken:
+			switch t.Data {
+			case "p", "h1", "h2", "h3", "h4", "h5", "h6", "li":
+				newLine()
+			}
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].name == t.Data {
+					current = stack[i].format
+					stack = stack[:i]
+					break
+				}
+			}
+		}
+	}
+	// remove trailing line breaks
+	for n := len(spans); n > 0; n = len(spans) {
+		x := strings.TrimRight(spans[n-1].Text, "\n")
+		if x != "" {
+			spans[n-1].Text = x
+			break
+		}
+		spans = spans[:n-1]
+	}
+	return spans
+}
+
+var reColor = regexp.MustCompile(`^#?([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// parseColor returns a color in #AARRGGBB format and reports whether it was valid.
+func parseColor(s string) (string, bool) {
+	if !reColor.MatchString(s) {
+		return "", false
+	}
+	s = strings.ToLower(strings.TrimPrefix(s, "#"))
+	if len(s) == 6 {
+		s = "ff" + s
+	}
+	return "#" + s, true
+}
+
+// LinkKind is the kind of a link in Eve Online HTML.
+type LinkKind uint
+
+const (
+	LinkUndefined  LinkKind = iota
+	LinkKillReport          // killmail
+	LinkShowInfo            // information about an object, e.g. a character
+	LinkWeb                 // web page
+)
+
+// A Link is a parsed link from an Eve Online HTML text.
+type Link struct {
+	Kind   LinkKind
+	Hash   string // hash of a killmail
+	ItemID int64  // ID of the object of a showinfo link or the ID of a killmail. 0 when not set.
+	TypeID int32  // type ID of a showinfo link
+	URL    string // address of a web page
+}
+
+var (
+	reShowInfoLink   = regexp.MustCompile(`^showinfo:(\d+)(?://(\d+))?$`)
+	reKillReportLink = regexp.MustCompile(`(?i)^killreport:(\d+):([0-9a-f]+)$`)
+)
+
+// ParseLink parses the target of a link and returns it.
+// Returns a link of kind [LinkUndefined] when the target is not supported.
+func ParseLink(href string) Link {
+	if m := reShowInfoLink.FindStringSubmatch(href); m != nil {
+		typeID, err := strconv.ParseInt(m[1], 10, 32)
+		if err != nil {
+			return Link{}
+		}
+		var itemID int64
+		if m[2] != "" {
+			itemID, err = strconv.ParseInt(m[2], 10, 64)
+			if err != nil {
+				return Link{}
+			}
+		}
+		return Link{Kind: LinkShowInfo, TypeID: int32(typeID), ItemID: itemID}
+	}
+	if m := reKillReportLink.FindStringSubmatch(href); m != nil {
+		id, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return Link{}
+		}
+		return Link{Kind: LinkKillReport, ItemID: id, Hash: m[2]}
+	}
+	if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+		return Link{Kind: LinkWeb, URL: href}
+	}
+	return Link{}
+}
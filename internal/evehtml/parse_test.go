@@ -0,0 +1,115 @@
+package evehtml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/evehtml"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want []evehtml.Span
+	}{
+		{
+			"plain text",
+			"alpha &amp; beta",
+			[]evehtml.Span{{Text: "alpha & beta"}},
+		},
+		{
+			"line breaks",
+			"alpha<br>beta<br><br>",
+			[]evehtml.Span{{Text: "alpha\nbeta"}},
+		},
+		{
+			"bold and italic",
+			"<b>alpha <i>beta</i></b> gamma",
+			[]evehtml.Span{
+				{Text: "alpha ", Bold: true},
+				{Text: "beta", Bold: true, Italic: true},
+				{Text: " gamma"},
+			},
+		},
+		{
+			"font color and size",
+			`<font size="14" color="#bf00ff00">alpha</font><font color="ff0000">beta</font>`,
+			[]evehtml.Span{
+				{Text: "alpha", Size: 14, Color: "#bf00ff00"},
+				{Text: "beta", Color: "#ffff0000"},
+			},
+		},
+		{
+			"links",
+			`Hi <a href="showinfo:1376//93330670">Erik</a>!`,
+			[]evehtml.Span{
+				{Text: "Hi "},
+				{Text: "Erik", Link: "showinfo:1376//93330670"},
+				{Text: "!"},
+			},
+		},
+		{
+			"unknown tags",
+			"<loc>alpha</loc>",
+			[]evehtml.Span{{Text: "alpha"}},
+		},
+		{
+			"closing tag without opening tag",
+			"alpha</b> beta",
+			[]evehtml.Span{{Text: "alpha beta"}},
+		},
+		{
+			"paragraphs from markdown",
+			"<p>alpha\nbeta</p>\n<p><strong>gamma</strong></p>\n",
+			[]evehtml.Span{
+				{Text: "alpha beta\n\n"},
+				{Text: "gamma", Bold: true},
+			},
+		},
+		{
+			"headers from markdown",
+			"<h1>alpha</h1>\n<p>beta</p>",
+			[]evehtml.Span{
+				{Text: "alpha", Bold: true},
+				{Text: "\n\nbeta"},
+			},
+		},
+		{
+			"lists from markdown",
+			"<ul>\n<li>alpha</li>\n<li>beta</li>\n</ul>\n",
+			[]evehtml.Span{{Text: "• alpha\n• beta"}},
+		},
+		{
+			"empty",
+			"",
+			nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, evehtml.Parse(tc.in))
+		})
+	}
+}
+
+func TestParseLink(t *testing.T) {
+	cases := []struct {
+		in   string
+		want evehtml.Link
+	}{
+		{"showinfo:1376//93330670", evehtml.Link{Kind: evehtml.LinkShowInfo, TypeID: 1376, ItemID: 93330670}},
+		{"showinfo:587", evehtml.Link{Kind: evehtml.LinkShowInfo, TypeID: 587}},
+		{"showinfo:35835//1039523841193", evehtml.Link{Kind: evehtml.LinkShowInfo, TypeID: 35835, ItemID: 1039523841193}},
+		{"killReport:126052937:2a6e8f1e", evehtml.Link{Kind: evehtml.LinkKillReport, ItemID: 126052937, Hash: "2a6e8f1e"}},
+		{"https://www.example.com", evehtml.Link{Kind: evehtml.LinkWeb, URL: "https://www.example.com"}},
+		{"fitting:587:2048;1::", evehtml.Link{}},
+		{"showinfo:abc", evehtml.Link{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			assert.Equal(t, tc.want, evehtml.ParseLink(tc.in))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package icalendar implements writing calendars in the iCalendar format (RFC 5545).
+package icalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateTimeLayout = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+// Event is a calendar event.
+type Event struct {
+	UID         string // globally unique ID, must be stable for the same event
+	Start       time.Time
+	End         time.Time // same as start when zero
+	Summary     string
+	Description string
+	Location    string
+}
+
+// Calendar is a collection of events.
+type Calendar struct {
+	Name   string
+	ProdID string
+	Events []Event
+}
+
+// Write writes the calendar in iCalendar format to w.
+func (c Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	now := time.Now()
+	writeLine(bw, "BEGIN", "VCALENDAR")
+	writeLine(bw, "VERSION", "2.0")
+	writeLine(bw, "PRODID", c.ProdID)
+	writeLine(bw, "CALSCALE", "GREGORIAN")
+	writeLine(bw, "METHOD", "PUBLISH")
+	if c.Name != "" {
+		writeLine(bw, "X-WR-CALNAME", escapeText(c.Name))
+	}
+	for _, e := range c.Events {
+		end := e.End
+		if end.IsZero() {
+			end = e.Start
+		}
+		writeLine(bw, "BEGIN", "VEVENT")
+		writeLine(bw, "UID", e.UID)
+		writeLine(bw, "DTSTAMP", formatTime(now))
+		writeLine(bw, "DTSTART", formatTime(e.Start))
+		writeLine(bw, "DTEND", formatTime(end))
+		writeLine(bw, "SUMMARY", escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(bw, "DESCRIPTION", escapeText(e.Description))
+		}
+		if e.Location != "" {
+			writeLine(bw, "LOCATION", escapeText(e.Location))
+		}
+		writeLine(bw, "END", "VEVENT")
+	}
+	writeLine(bw, "END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// String returns the calendar in iCalendar format.
+func (c Calendar) String() string {
+	var b strings.Builder
+	c.Write(&b)
+	return b.String()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// escapeText escapes a value of type TEXT.
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// writeLine writes a content line and folds it when it exceeds the max line length.
+// Lines are only folded at rune boundaries.
+func writeLine(w *bufio.Writer, name, value string) {
+	line := fmt.Sprintf("%s:%s", name, value)
+	limit := maxLineOctets
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		w.WriteString(line[:i])
+		w.WriteString("\r\n ")
+		line = line[i:]
+		limit = maxLineOctets - 1 // continuation lines start with a space
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
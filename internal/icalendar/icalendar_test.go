@@ -0,0 +1,51 @@
+package icalendar_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ErikKalkoken/evebuddy/internal/icalendar"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendar(t *testing.T) {
+	start := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	t.Run("can write calendar with event", func(t *testing.T) {
+		c := icalendar.Calendar{
+			Name:   "Timers",
+			ProdID: "-//Test//EN",
+			Events: []icalendar.Event{{
+				UID:         "alpha@example.com",
+				Start:       start,
+				Summary:     "Armor timer, Jita",
+				Description: "line 1\nline 2; done",
+			}},
+		}
+		got := c.String()
+		assert.True(t, strings.HasPrefix(got, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n"))
+		assert.Contains(t, got, "X-WR-CALNAME:Timers\r\n")
+		assert.Contains(t, got, "UID:alpha@example.com\r\n")
+		assert.Contains(t, got, "DTSTART:20250314T183000Z\r\n")
+		assert.Contains(t, got, "DTEND:20250314T183000Z\r\n")
+		assert.Contains(t, got, "SUMMARY:Armor timer\\, Jita\r\n")
+		assert.Contains(t, got, "DESCRIPTION:line 1\\nline 2\\; done\r\n")
+		assert.NotContains(t, got, "LOCATION")
+		assert.True(t, strings.HasSuffix(got, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	})
+	t.Run("should fold long lines", func(t *testing.T) {
+		c := icalendar.Calendar{
+			Events: []icalendar.Event{{
+				UID:     "bravo",
+				Start:   start,
+				Summary: strings.Repeat("äbc", 40),
+			}},
+		}
+		got := c.String()
+		for _, l := range strings.Split(got, "\r\n") {
+			assert.LessOrEqual(t, len(l), 75)
+		}
+		unfolded := strings.ReplaceAll(got, "\r\n ", "")
+		assert.Contains(t, unfolded, "SUMMARY:"+strings.Repeat("äbc", 40)+"\r\n")
+	})
+}
@@ -0,0 +1,107 @@
+// Package mailarchive implements writing mails as EML files (RFC 5322) and as mbox archives.
+package mailarchive
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout     = "Mon, 02 Jan 2006 15:04:05 -0700"
+	mboxDateLayout = "Mon Jan _2 15:04:05 2006"
+)
+
+// Message is a mail.
+type Message struct {
+	ID      string // globally unique ID, e.g. "123@example.com"
+	From    mail.Address
+	To      []mail.Address
+	Date    time.Time
+	Subject string
+	Body    string
+	IsHTML  bool // whether the body is HTML or plain text
+}
+
+// WriteEML writes the message in Internet Message Format to w.
+func (m Message) WriteEML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if m.ID != "" {
+		writeHeader(bw, "Message-ID", "<"+m.ID+">")
+	}
+	writeHeader(bw, "Date", m.Date.Format(dateLayout))
+	writeHeader(bw, "From", m.From.String())
+	if len(m.To) > 0 {
+		to := make([]string, len(m.To))
+		for i, a := range m.To {
+			to[i] = a.String()
+		}
+		writeHeader(bw, "To", strings.Join(to, ", "))
+	}
+	writeHeader(bw, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(bw, "MIME-Version", "1.0")
+	contentType := "text/plain"
+	if m.IsHTML {
+		contentType = "text/html"
+	}
+	writeHeader(bw, "Content-Type", contentType+"; charset=utf-8")
+	writeHeader(bw, "Content-Transfer-Encoding", "quoted-printable")
+	bw.WriteString("\r\n")
+	qw := quotedprintable.NewWriter(bw)
+	body := strings.ReplaceAll(m.Body, "\r\n", "\n")
+	if _, err := qw.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return err
+	}
+	if err := qw.Close(); err != nil {
+		return err
+	}
+	bw.WriteString("\r\n")
+	return bw.Flush()
+}
+
+// String returns the message in Internet Message Format.
+func (m Message) String() string {
+	var b strings.Builder
+	m.WriteEML(&b)
+	return b.String()
+}
+
+var mboxFromLine = regexp.MustCompile(`(?m)^(>*From )`)
+
+// WriteMbox writes messages as mbox archive to w.
+// It uses the mboxrd variant, which quotes lines starting with "From " in a reversible way.
+func WriteMbox(w io.Writer, messages []Message) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range messages {
+		var b bytes.Buffer
+		if err := m.WriteEML(&b); err != nil {
+			return err
+		}
+		s := strings.ReplaceAll(b.String(), "\r\n", "\n")
+		s = mboxFromLine.ReplaceAllString(s, ">$1")
+		sender := m.From.Address
+		if sender == "" {
+			sender = "MAILER-DAEMON"
+		}
+		fmt.Fprintf(bw, "From %s %s\n", sender, m.Date.UTC().Format(mboxDateLayout))
+		bw.WriteString(s)
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// writeHeader writes a header field. Line breaks in the value are removed.
+func writeHeader(w *bufio.Writer, name, value string) {
+	value = strings.NewReplacer("\r", "", "\n", " ").Replace(value)
+	w.WriteString(name)
+	w.WriteString(": ")
+	w.WriteString(value)
+	w.WriteString("\r\n")
+}
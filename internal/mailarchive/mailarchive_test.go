@@ -0,0 +1,89 @@
+package mailarchive_test
+
+import (
+	"bytes"
+	"mime"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ErikKalkoken/evebuddy/internal/mailarchive"
+)
+
+func TestMessage(t *testing.T) {
+	date := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	t.Run("can write message as EML", func(t *testing.T) {
+		m := mailarchive.Message{
+			ID:      "7@example.com",
+			From:    mail.Address{Name: "Bruce Wayne", Address: "1@example.com"},
+			To:      []mail.Address{{Name: "Alpha", Address: "2@example.com"}, {Name: "Bravo", Address: "3@example.com"}},
+			Date:    date,
+			Subject: "Stratop",
+			Body:    "line 1\nline 2",
+		}
+		got := m.String()
+		assert.True(t, strings.HasPrefix(got, "Message-ID: <7@example.com>\r\n"))
+		assert.Contains(t, got, "Date: Fri, 14 Mar 2025 18:30:00 +0000\r\n")
+		assert.Contains(t, got, "From: \"Bruce Wayne\" <1@example.com>\r\n")
+		assert.Contains(t, got, "To: \"Alpha\" <2@example.com>, \"Bravo\" <3@example.com>\r\n")
+		assert.Contains(t, got, "Subject: Stratop\r\n")
+		assert.Contains(t, got, "Content-Type: text/plain; charset=utf-8\r\n")
+		assert.True(t, strings.HasSuffix(got, "\r\n\r\nline 1\r\nline 2\r\n"))
+	})
+	t.Run("can be parsed", func(t *testing.T) {
+		m := mailarchive.Message{
+			From:    mail.Address{Name: "Jürgen", Address: "1@example.com"},
+			Date:    date,
+			Subject: "Grüße",
+			Body:    "<b>Grüße</b>",
+			IsHTML:  true,
+		}
+		x, err := mail.ReadMessage(strings.NewReader(m.String()))
+		if assert.NoError(t, err) {
+			from, err := x.Header.AddressList("From")
+			if assert.NoError(t, err) {
+				assert.Equal(t, "Jürgen", from[0].Name)
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(x.Header.Get("Subject"))
+			if assert.NoError(t, err) {
+				assert.Equal(t, "Grüße", subject)
+			}
+			assert.Equal(t, "text/html; charset=utf-8", x.Header.Get("Content-Type"))
+			d, err := x.Header.Date()
+			if assert.NoError(t, err) {
+				assert.True(t, date.Equal(d))
+			}
+		}
+	})
+}
+
+func TestWriteMbox(t *testing.T) {
+	date := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	mm := []mailarchive.Message{
+		{
+			From:    mail.Address{Name: "Alpha", Address: "1@example.com"},
+			Date:    date,
+			Subject: "first",
+			Body:    "From the start\n>From quoted",
+		},
+		{
+			From:    mail.Address{Name: "Bravo", Address: "2@example.com"},
+			Date:    date.Add(time.Hour),
+			Subject: "second",
+			Body:    "body",
+		},
+	}
+	var b bytes.Buffer
+	err := mailarchive.WriteMbox(&b, mm)
+	if assert.NoError(t, err) {
+		got := b.String()
+		assert.True(t, strings.HasPrefix(got, "From 1@example.com Fri Mar 14 18:30:00 2025\n"))
+		assert.Contains(t, got, "\nFrom 2@example.com Fri Mar 14 19:30:00 2025\n")
+		assert.Contains(t, got, "\n>From the start\n>>From quoted\n")
+		assert.NotContains(t, got, "\r\n")
+		assert.Equal(t, 2, strings.Count("\n"+got, "\nFrom "))
+	}
+}
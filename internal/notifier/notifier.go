@@ -0,0 +1,256 @@
+// Package notifier contains backends for delivering notifications to external services,
+// e.g. to receive alerts on a phone.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrHTTP    = errors.New("HTTP error")
+	ErrInvalid = errors.New("invalid configuration")
+)
+
+// Notifier is a backend which delivers notifications.
+type Notifier interface {
+	// Name returns the display name of a backend.
+	Name() string
+	// Send sends a notification.
+	Send(ctx context.Context, title, content string) error
+}
+
+// Ntfy sends notifications to a topic on a ntfy server.
+type Ntfy struct {
+	IsEnabled bool   `json:"is_enabled"`
+	Server    string `json:"server"` // defaults to the public server when empty
+	Token     string `json:"token"`  // optional access token
+	Topic     string `json:"topic"`
+}
+
+var _ Notifier = (*Ntfy)(nil)
+
+const ntfyServerDefault = "https://ntfy.sh"
+
+func (n Ntfy) Name() string {
+	return "ntfy"
+}
+
+func (n Ntfy) Send(ctx context.Context, title, content string) error {
+	if n.Topic == "" {
+		return fmt.Errorf("ntfy: topic missing: %w", ErrInvalid)
+	}
+	server := n.Server
+	if server == "" {
+		server = ntfyServerDefault
+	}
+	url := strings.TrimSuffix(server, "/") + "/" + n.Topic
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", title)
+	req.Header.Set("Markdown", "yes")
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+	return doRequest(req)
+}
+
+// Gotify sends notifications to a Gotify server.
+type Gotify struct {
+	IsEnabled bool   `json:"is_enabled"`
+	Priority  int    `json:"priority"`
+	Token     string `json:"token"` // application token
+	URL       string `json:"url"`   // URL of the server
+}
+
+var _ Notifier = (*Gotify)(nil)
+
+func (n Gotify) Name() string {
+	return "Gotify"
+}
+
+func (n Gotify) Send(ctx context.Context, title, content string) error {
+	if n.URL == "" || n.Token == "" {
+		return fmt.Errorf("gotify: URL or token missing: %w", ErrInvalid)
+	}
+	data, err := json.Marshal(map[string]any{
+		"title":    title,
+		"message":  content,
+		"priority": n.Priority,
+		"extras": map[string]any{
+			"client::display": map[string]string{"contentType": "text/markdown"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(n.URL, "/") + "/message"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", n.Token)
+	return doRequest(req)
+}
+
+// SMTP sends notifications as email.
+// STARTTLS is used when the server supports it.
+type SMTP struct {
+	From      string `json:"from"`
+	Host      string `json:"host"`
+	IsEnabled bool   `json:"is_enabled"`
+	Password  string `json:"password"`
+	Port      int    `json:"port"`
+	To        string `json:"to"`       // comma separated list of recipients
+	Username  string `json:"username"` // no authentication when empty
+}
+
+var _ Notifier = (*SMTP)(nil)
+
+func (n SMTP) Name() string {
+	return "Email (SMTP)"
+}
+
+func (n SMTP) Send(ctx context.Context, title, content string) error {
+	recipients := n.recipients()
+	if n.Host == "" || n.Port == 0 || n.From == "" || len(recipients) == 0 {
+		return fmt.Errorf("smtp: host, port, from or to missing: %w", ErrInvalid)
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(n.Host, strconv.Itoa(n.Port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, n.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.Host}); err != nil {
+			return err
+		}
+	}
+	if n.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.Username, n.Password, n.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(n.From); err != nil {
+		return err
+	}
+	for _, r := range recipients {
+		if err := c.Rcpt(r); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(n.makeMessage(title, content, recipients)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (n SMTP) recipients() []string {
+	var s []string
+	for _, r := range strings.Split(n.To, ",") {
+		r = strings.TrimSpace(r)
+		if r != "" {
+			s = append(s, r)
+		}
+	}
+	return s
+}
+
+func (n SMTP) makeMessage(title, content string, recipients []string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", removeLineBreaks(title)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(content, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+func removeLineBreaks(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// Webhook sends notifications as JSON to a generic webhook.
+type Webhook struct {
+	IsEnabled bool   `json:"is_enabled"`
+	URL       string `json:"url"`
+}
+
+var _ Notifier = (*Webhook)(nil)
+
+func (n Webhook) Name() string {
+	return "Webhook"
+}
+
+func (n Webhook) Send(ctx context.Context, title, content string) error {
+	if n.URL == "" {
+		return fmt.Errorf("webhook: URL missing: %w", ErrInvalid)
+	}
+	data, err := json.Marshal(map[string]string{
+		"title":     title,
+		"content":   content,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doRequest(req)
+}
+
+// doRequest executes a request and returns an error wrapping [ErrHTTP] for error responses.
+func doRequest(req *http.Request) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%s: %s: %w", resp.Status, strings.TrimSpace(string(body)), ErrHTTP)
+	}
+	return nil
+}
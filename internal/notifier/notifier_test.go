@@ -0,0 +1,275 @@
+package notifier_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ErikKalkoken/evebuddy/internal/notifier"
+	"github.com/stretchr/testify/assert"
+)
+
+type request struct {
+	body   string
+	header http.Header
+	path   string
+}
+
+// newServer returns a stand-in server which records requests and responds with status.
+func newServer(status int) (*httptest.Server, *[]request) {
+	requests := make([]request, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{body: string(b), header: r.Header, path: r.URL.Path})
+		w.WriteHeader(status)
+	}))
+	return srv, &requests
+}
+
+func TestNtfy(t *testing.T) {
+	ctx := context.Background()
+	t.Run("can send notification", func(t *testing.T) {
+		// given
+		srv, requests := newServer(200)
+		defer srv.Close()
+		n := notifier.Ntfy{Server: srv.URL, Topic: "alpha", Token: "token"}
+		// when
+		err := n.Send(ctx, "title", "content")
+		// then
+		if assert.NoError(t, err) {
+			assert.Len(t, *requests, 1)
+			r := (*requests)[0]
+			assert.Equal(t, "/alpha", r.path)
+			assert.Equal(t, "content", r.body)
+			assert.Equal(t, "title", r.header.Get("Title"))
+			assert.Equal(t, "Bearer token", r.header.Get("Authorization"))
+		}
+	})
+	t.Run("should report HTTP errors", func(t *testing.T) {
+		// given
+		srv, _ := newServer(403)
+		defer srv.Close()
+		n := notifier.Ntfy{Server: srv.URL, Topic: "alpha"}
+		// when
+		err := n.Send(ctx, "title", "content")
+		// then
+		assert.ErrorIs(t, err, notifier.ErrHTTP)
+	})
+	t.Run("should report invalid configuration", func(t *testing.T) {
+		n := notifier.Ntfy{}
+		err := n.Send(ctx, "title", "content")
+		assert.ErrorIs(t, err, notifier.ErrInvalid)
+	})
+}
+
+func TestGotify(t *testing.T) {
+	ctx := context.Background()
+	t.Run("can send notification", func(t *testing.T) {
+		// given
+		srv, requests := newServer(200)
+		defer srv.Close()
+		n := notifier.Gotify{URL: srv.URL, Token: "token", Priority: 5}
+		// when
+		err := n.Send(ctx, "title", "content")
+		// then
+		if assert.NoError(t, err) {
+			assert.Len(t, *requests, 1)
+			r := (*requests)[0]
+			assert.Equal(t, "/message", r.path)
+			assert.Equal(t, "token", r.header.Get("X-Gotify-Key"))
+			var data map[string]any
+			if err := json.Unmarshal([]byte(r.body), &data); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, "title", data["title"])
+			assert.Equal(t, "content", data["message"])
+			assert.EqualValues(t, 5, data["priority"])
+		}
+	})
+	t.Run("should report invalid configuration", func(t *testing.T) {
+		n := notifier.Gotify{URL: "https://www.example.com"}
+		err := n.Send(ctx, "title", "content")
+		assert.ErrorIs(t, err, notifier.ErrInvalid)
+	})
+}
+
+func TestWebhook(t *testing.T) {
+	ctx := context.Background()
+	t.Run("can send notification", func(t *testing.T) {
+		// given
+		srv, requests := newServer(204)
+		defer srv.Close()
+		n := notifier.Webhook{URL: srv.URL + "/hook"}
+		// when
+		err := n.Send(ctx, "title", "content")
+		// then
+		if assert.NoError(t, err) {
+			assert.Len(t, *requests, 1)
+			r := (*requests)[0]
+			assert.Equal(t, "/hook", r.path)
+			assert.Equal(t, "application/json", r.header.Get("Content-Type"))
+			var data map[string]string
+			if err := json.Unmarshal([]byte(r.body), &data); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, "title", data["title"])
+			assert.Equal(t, "content", data["content"])
+			assert.NotEmpty(t, data["timestamp"])
+		}
+	})
+	t.Run("should report HTTP errors", func(t *testing.T) {
+		// given
+		srv, _ := newServer(500)
+		defer srv.Close()
+		n := notifier.Webhook{URL: srv.URL}
+		// when
+		err := n.Send(ctx, "title", "content")
+		// then
+		assert.ErrorIs(t, err, notifier.ErrHTTP)
+	})
+}
+
+// smtpServer is a minimal SMTP server for tests, which records received mails.
+type smtpServer struct {
+	l          net.Listener
+	recipients []string
+	from       string
+	data       string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{l: l}
+	go s.serve()
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.l.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve() {
+	conn, err := s.l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) {
+		fmt.Fprintf(conn, "%s\r\n", s)
+	}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(line[10:], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(line[8:], "<>"))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 Go ahead")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.data = b.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Not implemented")
+		}
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	ctx := context.Background()
+	t.Run("can send notification", func(t *testing.T) {
+		// given
+		srv := newSMTPServer(t)
+		defer srv.l.Close()
+		n := notifier.SMTP{
+			From: "alpha@example.com",
+			Host: "127.0.0.1",
+			Port: srv.port(),
+			To:   "bravo@example.com, charlie@example.com",
+		}
+		// when
+		err := n.Send(ctx, "title", "content")
+		// then
+		if assert.NoError(t, err) {
+			assert.Equal(t, "alpha@example.com", srv.from)
+			assert.Equal(t, []string{"bravo@example.com", "charlie@example.com"}, srv.recipients)
+			assert.Contains(t, srv.data, "Subject: title\r\n")
+			assert.Contains(t, srv.data, "\r\n\r\ncontent\r\n")
+		}
+	})
+	t.Run("should encode subject", func(t *testing.T) {
+		// given
+		srv := newSMTPServer(t)
+		defer srv.l.Close()
+		n := notifier.SMTP{
+			From: "alpha@example.com",
+			Host: "127.0.0.1",
+			Port: srv.port(),
+			To:   "bravo@example.com",
+		}
+		// when
+		err := n.Send(ctx, "Größe\r\nBcc: charlie@example.com", "content")
+		// then
+		if assert.NoError(t, err) {
+			assert.Contains(t, srv.data, "Subject: =?utf-8?q?Gr=C3=B6=C3=9Fe__Bcc:_charlie@example.com?=\r\n")
+			assert.NotContains(t, srv.data, "\r\nBcc:")
+		}
+	})
+	t.Run("should normalize line breaks in content", func(t *testing.T) {
+		// given
+		srv := newSMTPServer(t)
+		defer srv.l.Close()
+		n := notifier.SMTP{
+			From: "alpha@example.com",
+			Host: "127.0.0.1",
+			Port: srv.port(),
+			To:   "bravo@example.com",
+		}
+		// when
+		err := n.Send(ctx, "title", "line 1\r\nline 2\nline 3")
+		// then
+		if assert.NoError(t, err) {
+			assert.Contains(t, srv.data, "\r\n\r\nline 1\r\nline 2\r\nline 3\r\n")
+			assert.NotContains(t, srv.data, "\r\r\n")
+		}
+	})
+	t.Run("should report invalid configuration", func(t *testing.T) {
+		n := notifier.SMTP{Host: "127.0.0.1", Port: 25}
+		err := n.Send(ctx, "title", "content")
+		assert.ErrorIs(t, err, notifier.ErrInvalid)
+	})
+}
@@ -34,6 +34,18 @@ func TestNullTypeConversion(t *testing.T) {
 		x2 := optional.ToNullTime(o)
 		assert.Equal(t, x1, x2)
 	})
+	t.Run("can convert NullBool 1", func(t *testing.T) {
+		x1 := sql.NullBool{Bool: true, Valid: true}
+		o := optional.FromNullBool(x1)
+		x2 := optional.ToNullBool(o)
+		assert.Equal(t, x1, x2)
+	})
+	t.Run("can convert NullBool 2", func(t *testing.T) {
+		x1 := sql.NullBool{}
+		o := optional.FromNullBool(x1)
+		x2 := optional.ToNullBool(o)
+		assert.Equal(t, x1, x2)
+	})
 	t.Run("can convert NullFloat64 1", func(t *testing.T) {
 		x1 := sql.NullFloat64{Float64: 1.23, Valid: true}
 		o := optional.FromNullFloat64(x1)
@@ -169,6 +169,45 @@ func NewSettingItemOptions(
 	}
 }
 
+// NewSettingItemEntry creates a setting for entering text in a setting list.
+func NewSettingItemEntry(
+	label, hint, placeholder string,
+	defaultV string,
+	getter func() string,
+	setter func(v string),
+	window func() fyne.Window,
+) SettingItem {
+	return SettingItem{
+		Label: label,
+		Hint:  hint,
+		Getter: func() any {
+			return getter()
+		},
+		Setter: func(v any) {
+			setter(v.(string))
+		},
+		onSelected: func(it SettingItem, refresh func()) {
+			entry := widget.NewEntry()
+			entry.PlaceHolder = placeholder
+			entry.SetText(it.Getter().(string))
+			entry.OnChanged = setter
+			w := window()
+			d := makeSettingDialog(
+				entry,
+				it.Label,
+				it.Hint,
+				func() {
+					entry.SetText(defaultV)
+				},
+				refresh,
+				w,
+			)
+			d.Show()
+		},
+		variant: settingCustom,
+	}
+}
+
 func makeSettingDialog(
 	setting fyne.CanvasObject,
 	label, hint string,
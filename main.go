@@ -227,6 +227,7 @@ func main() {
 	// Init EveUniverse service
 	eus := eveuniverseservice.New(st, esiClient)
 	eus.StatusCacheService = scs
+	eus.HTTPClient = rhc.StandardClient()
 
 	// Init EveNotification service
 	en := evenotification.New(eus)